@@ -17,6 +17,11 @@
 
 package base
 
+import (
+	"context"
+	"strconv"
+)
+
 import (
 	perrors "github.com/pkg/errors"
 
@@ -24,6 +29,7 @@ import (
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/diagnostic"
 	"dubbo.apache.org/dubbo-go/v3/cluster/directory"
 	"dubbo.apache.org/dubbo-go/v3/cluster/loadbalance"
 	"dubbo.apache.org/dubbo-go/v3/common"
@@ -66,6 +72,33 @@ func (invoker *ClusterInvoker) IsAvailable() bool {
 	return invoker.Directory.IsAvailable()
 }
 
+// Addresses returns the provider addresses resolved by the directory
+func (invoker *ClusterInvoker) Addresses() []directory.Address {
+	if lister, ok := invoker.Directory.(directory.AddressLister); ok {
+		return lister.Addresses()
+	}
+	return nil
+}
+
+// WarmUp connects to the providers resolved by the directory in advance
+func (invoker *ClusterInvoker) WarmUp(ctx context.Context, top int) error {
+	if warmer, ok := invoker.Directory.(directory.Warmer); ok {
+		return warmer.WarmUp(ctx, top)
+	}
+	return nil
+}
+
+// WaitReady waits until at least @min providers resolved by the directory are available
+func (invoker *ClusterInvoker) WaitReady(ctx context.Context, min int) error {
+	if waiter, ok := invoker.Directory.(directory.ReadyWaiter); ok {
+		return waiter.WaitReady(ctx, min)
+	}
+	if min <= 1 && invoker.IsAvailable() {
+		return nil
+	}
+	return perrors.Errorf("the directory of %s can't wait for %d providers", invoker.Directory.GetURL().Service(), min)
+}
+
 // CheckInvokers checks invokers' status if is available or not
 func (invoker *ClusterInvoker) CheckInvokers(invokers []protocol.Invoker, invocation protocol.Invocation) error {
 	if len(invokers) == 0 {
@@ -105,6 +138,7 @@ func (invoker *ClusterInvoker) DoSelect(lb loadbalance.LoadBalance, invocation p
 	if sticky && invoker.AvailableCheck &&
 		invoker.StickyInvoker != nil && invoker.StickyInvoker.IsAvailable() &&
 		(invoked == nil || !isInvoked(invoker.StickyInvoker, invoked)) {
+		invoker.recordSelection(invocation, invoker.StickyInvoker)
 		return invoker.StickyInvoker
 	}
 
@@ -112,9 +146,17 @@ func (invoker *ClusterInvoker) DoSelect(lb loadbalance.LoadBalance, invocation p
 	if sticky {
 		invoker.StickyInvoker = selectedInvoker
 	}
+	invoker.recordSelection(invocation, selectedInvoker)
 	return selectedInvoker
 }
 
+// recordSelection records the selected invoker for the diagnostic dump of the service
+func (invoker *ClusterInvoker) recordSelection(invocation protocol.Invocation, selected protocol.Invoker) {
+	if invoker.Directory != nil && selected != nil {
+		diagnostic.RecordSelection(diagnostic.ServiceKey(invoker.Directory.GetURL()), invocation.MethodName(), selected)
+	}
+}
+
 func (invoker *ClusterInvoker) doSelectInvoker(lb loadbalance.LoadBalance, invocation protocol.Invocation, invokers []protocol.Invoker, invoked []protocol.Invoker) protocol.Invoker {
 	if len(invokers) == 0 {
 		return nil
@@ -182,7 +224,17 @@ func GetLoadBalance(invoker protocol.Invoker, invocation protocol.Invocation) lo
 	if v := url.GetMethodParam(methodName, constant.LOADBALANCE_KEY, ""); len(v) > 0 {
 		lb = v
 	}
-	return extension.GetLoadbalance(lb)
+	loadBalance := extension.GetLoadbalance(lb)
+
+	// prefer the providers in the same locality with the consumer if it's configured
+	if locality := url.GetParam(constant.LOCALITY_AFFINITY_KEY, ""); len(locality) > 0 {
+		bias, err := strconv.ParseFloat(url.GetParam(constant.LOCALITY_AFFINITY_BIAS_KEY, ""), 64)
+		if err != nil || bias < 0 || bias > 1 {
+			bias = constant.DEFAULT_AFFINITY_BIAS
+		}
+		return loadbalance.NewLocalityLoadBalance(loadBalance, locality, bias)
+	}
+	return loadBalance
 }
 
 func getOtherInvokers(invokers []protocol.Invoker, invoker protocol.Invoker) []protocol.Invoker {
@@ -21,6 +21,10 @@ import (
 	"context"
 )
 
+import (
+	perrors "github.com/pkg/errors"
+)
+
 import (
 	"dubbo.apache.org/dubbo-go/v3/cluster/cluster/base"
 	"dubbo.apache.org/dubbo-go/v3/cluster/directory"
@@ -53,5 +57,10 @@ func (invoker *clusterInvoker) Invoke(ctx context.Context, invocation protocol.I
 	}
 
 	ivk := invoker.DoSelect(loadbalance, invocation, invokers, nil)
+	if ivk == nil {
+		return &protocol.RPCResult{Err: perrors.Errorf("Failed to invoke the method %s of the service %s .No provider is available.",
+			invocation.MethodName(), invoker.GetURL().Service())}
+	}
+	// the result of the only attempt is returned as it is, so that the error of the provider is surfaced verbatim
 	return ivk.Invoke(ctx, invocation)
 }
@@ -102,3 +102,31 @@ func TestFailfastInvokeFail(t *testing.T) {
 	assert.Equal(t, "error", result.Error().Error())
 	assert.Nil(t, result.Result())
 }
+
+type providerError struct {
+	code int
+}
+
+func (e *providerError) Error() string {
+	return fmt.Sprintf("provider error %d", e.code)
+}
+
+func TestFailfastInvokeRawError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	invoker := mock.NewMockInvoker(ctrl)
+	clusterInvoker := registerFailfast(invoker)
+
+	invoker.EXPECT().IsAvailable().Return(true).AnyTimes()
+	invoker.EXPECT().GetUrl().Return(failfastUrl).AnyTimes()
+
+	err := &providerError{code: 500}
+	invoker.EXPECT().Invoke(gomock.Any()).Return(&protocol.RPCResult{Err: err}).Times(1)
+	result := clusterInvoker.Invoke(context.Background(), &invocation.RPCInvocation{})
+
+	// the error of the provider isn't wrapped by the cluster
+	providerErr, ok := result.Error().(*providerError)
+	assert.True(t, ok)
+	assert.Same(t, err, providerErr)
+}
@@ -20,7 +20,10 @@ package failover
 import (
 	"context"
 	"fmt"
+	"math/rand"
 	"strconv"
+	"strings"
+	"unicode"
 )
 
 import (
@@ -30,8 +33,10 @@ import (
 import (
 	"dubbo.apache.org/dubbo-go/v3/cluster/cluster/base"
 	"dubbo.apache.org/dubbo-go/v3/cluster/directory"
+	"dubbo.apache.org/dubbo-go/v3/cluster/loadbalance"
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
 	"dubbo.apache.org/dubbo-go/v3/common/logger"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 )
@@ -48,35 +53,68 @@ func newClusterInvoker(directory directory.Directory) protocol.Invoker {
 
 func (invoker *clusterInvoker) Invoke(ctx context.Context, invocation protocol.Invocation) protocol.Result {
 	var (
-		result    protocol.Result
-		invoked   []protocol.Invoker
-		providers []string
-		ivk       protocol.Invoker
+		result  protocol.Result
+		invoked []protocol.Invoker
 	)
-
 	invokers := invoker.Directory.List(invocation)
 	if err := invoker.CheckInvokers(invokers, invocation); err != nil {
-		return &protocol.RPCResult{Err: err}
+		result = &protocol.RPCResult{Err: err}
+	} else {
+		result, invoked = invoker.failover(ctx, invocation, invokers, invoker.Directory.List, nil)
+		if result.Error() == nil || protocol.IsBizError(result.Error()) {
+			return result
+		}
+	}
+
+	// the invokers of the fallback protocol are the last resort once the listed ones are exhausted
+	lister, ok := invoker.Directory.(directory.FallbackLister)
+	if !ok {
+		return result
 	}
+	fallbackInvokers := lister.ListFallback(invocation)
+	if len(fallbackInvokers) == 0 {
+		return result
+	}
+	logger.Warnf("Failed to invoke the method %s of the service %s by the preferred protocol, fail over to the %s providers",
+		invocation.MethodName(), invoker.GetURL().Service(), fallbackInvokers[0].GetURL().Protocol)
+	result, _ = invoker.failover(ctx, invocation, fallbackInvokers, lister.ListFallback, invoked)
+	return result
+}
+
+// failover invokes @invokers and retries the other ones listed by @list again. The invokers tried before
+// are passed by @invoked.
+func (invoker *clusterInvoker) failover(ctx context.Context, invocation protocol.Invocation, invokers []protocol.Invoker,
+	list func(protocol.Invocation) []protocol.Invoker, invoked []protocol.Invoker) (protocol.Result, []protocol.Invoker) {
+	var (
+		result    protocol.Result
+		providers []string
+		ivk       protocol.Invoker
+	)
 
 	methodName := invocation.MethodName()
-	retries := getRetries(invokers, methodName)
+	retries := getRetries(invokers, invokedMethod(invocation))
 	loadBalance := base.GetLoadBalance(invokers[0], invocation)
+	tried := len(invoked)
 
-	for i := 0; i <= retries; i++ {
+	attempts := retries
+	for i := 0; i <= attempts; i++ {
 		// Reselect before retry to avoid a change of candidate `invokers`.
 		// NOTE: if `invokers` changed, then `invoked` also lose accuracy.
 		if i > 0 {
 			if err := invoker.CheckWhetherDestroyed(); err != nil {
-				return &protocol.RPCResult{Err: err}
+				return &protocol.RPCResult{Err: err}, invoked
 			}
 
-			invokers = invoker.Directory.List(invocation)
+			invokers = list(invocation)
 			if err := invoker.CheckInvokers(invokers, invocation); err != nil {
-				return &protocol.RPCResult{Err: err}
+				return &protocol.RPCResult{Err: err}, invoked
 			}
 		}
-		ivk = invoker.DoSelect(loadBalance, invocation, invokers, invoked)
+		if i > 0 {
+			ivk = invoker.selectRetryInvoker(loadBalance, invocation, invokers, invoked)
+		} else {
+			ivk = invoker.DoSelect(loadBalance, invocation, invokers, invoked)
+		}
 		if ivk == nil {
 			continue
 		}
@@ -84,29 +122,138 @@ func (invoker *clusterInvoker) Invoke(ctx context.Context, invocation protocol.I
 		// DO INVOKE
 		result = ivk.Invoke(ctx, invocation)
 		if result.Error() != nil {
+			if protocol.IsBizError(result.Error()) {
+				// the business errors fail on the other providers as well
+				setRetryAttachments(result, invoked)
+				return result, invoked
+			}
 			providers = append(providers, ivk.GetURL().Key())
+			// the request isn't sent by the provider going away, e.g. its session is being closed on shutdown,
+			// so it's retried once more on the providers not tried yet even if the retries are used up
+			if i == attempts && protocol.IsTransportError(result.Error()) && len(invoked)-tried < len(invokers) {
+				attempts++
+			}
 			continue
 		}
-		return result
+		setRetryAttachments(result, invoked)
+		if callback := extension.GetRetrySuccessCallback(); callback != nil && len(invoked) > 1 {
+			callback(ivk, invocation, len(invoked))
+		}
+		return result, invoked
 	}
 	ip := common.GetLocalIp()
 	invokerSvc := invoker.GetURL().Service()
 	invokerUrl := invoker.Directory.GetURL()
-	if ivk == nil {
+	if len(invoked) == tried {
 		logger.Errorf("Failed to invoke the method %s of the service %s .No provider is available.", methodName, invokerSvc)
 		return &protocol.RPCResult{
 			Err: perrors.Errorf("Failed to invoke the method %s of the service %s .No provider is available because can't connect server.",
 				methodName, invokerSvc),
-		}
+		}, invoked
+	}
+	if retries == 0 {
+		// the error of the only attempt is surfaced verbatim as failfast does
+		setRetryAttachments(result, invoked)
+		return result, invoked
 	}
 
-	return &protocol.RPCResult{
+	failure := &protocol.RPCResult{
 		Err: perrors.Wrap(result.Error(), fmt.Sprintf("Failed to invoke the method %v in the service %v. "+
 			"Tried %v times of the providers %v (%v/%v)from the registry %v on the consumer %v using the dubbo version %v. "+
 			"Last error is %+v.", methodName, invokerSvc, retries, providers, len(providers), len(invokers),
 			invokerUrl, ip, constant.Version, result.Error().Error()),
 		),
 	}
+	setRetryAttachments(failure, invoked)
+	return failure, invoked
+}
+
+// setRetryAttachments records the number of attempts and the addresses of the providers tried in order
+// into the attachments of @result, so that the callers are able to observe the retries.
+func setRetryAttachments(result protocol.Result, invoked []protocol.Invoker) {
+	addresses := make([]string, 0, len(invoked))
+	for _, ivk := range invoked {
+		addresses = append(addresses, ivk.GetURL().Location)
+	}
+	if result.Attachments() == nil {
+		result.SetAttachments(make(map[string]interface{}, 2))
+	}
+	result.AddAttachment(constant.RETRY_ATTEMPTS_KEY, strconv.Itoa(len(invoked)))
+	result.AddAttachment(constant.RETRY_ADDRESSES_KEY, strings.Join(addresses, ","))
+}
+
+// selectRetryInvoker selects the invoker to retry among the ones not tried yet. The invokers with lower
+// error rate and fewer active requests recorded in their RPCStatus are preferred, and it falls back
+// to the load balance if there is no difference among them.
+func (invoker *clusterInvoker) selectRetryInvoker(loadBalance loadbalance.LoadBalance, invocation protocol.Invocation,
+	invokers []protocol.Invoker, invoked []protocol.Invoker) protocol.Invoker {
+	candidates := make([]protocol.Invoker, 0, len(invokers))
+	for _, ivk := range invokers {
+		if isInvoked(ivk, invoked) || (invoker.AvailableCheck && !ivk.IsAvailable()) {
+			continue
+		}
+		candidates = append(candidates, ivk)
+	}
+	if len(candidates) == 0 {
+		return invoker.DoSelect(loadBalance, invocation, invokers, invoked)
+	}
+
+	var (
+		totalScore float64
+		sameScore  = true
+		scores     = make([]float64, len(candidates))
+	)
+	for i, ivk := range candidates {
+		scores[i] = getHealthScore(ivk, invocation.MethodName())
+		totalScore += scores[i]
+		if i > 0 && scores[i] != scores[0] {
+			sameScore = false
+		}
+	}
+	if sameScore || totalScore <= 0 {
+		return invoker.DoSelect(loadBalance, invocation, candidates, invoked)
+	}
+
+	offset := rand.Float64() * totalScore
+	for i, score := range scores {
+		offset -= score
+		if offset < 0 {
+			return candidates[i]
+		}
+	}
+	return candidates[len(candidates)-1]
+}
+
+// getHealthScore is the success rate of the method divided by its active requests, so the score of the invoker
+// without any statistics is 1.
+func getHealthScore(invoker protocol.Invoker, methodName string) float64 {
+	status := protocol.GetMethodStatus(invoker.GetURL(), methodName)
+	successRate := float64(1)
+	if total := status.GetTotal(); total > 0 {
+		successRate = float64(total-status.GetFailed()) / float64(total)
+	}
+	return successRate / float64(status.GetActive()+1)
+}
+
+func isInvoked(invoker protocol.Invoker, invoked []protocol.Invoker) bool {
+	for _, i := range invoked {
+		if i == invoker {
+			return true
+		}
+	}
+	return false
+}
+
+// invokedMethod returns the method invoked by @invocation, which is the first argument of the generic invocations
+func invokedMethod(invocation protocol.Invocation) string {
+	if invocation.MethodName() == constant.GENERIC {
+		if args := invocation.Arguments(); len(args) > 0 {
+			if method, ok := args[0].(string); ok {
+				return method
+			}
+		}
+	}
+	return invocation.MethodName()
 }
 
 func getRetries(invokers []protocol.Invoker, methodName string) int {
@@ -131,5 +278,33 @@ func getRetries(invokers []protocol.Invoker, methodName string) int {
 	if retries > len(invokers) {
 		retries = len(invokers)
 	}
+	if retries > 0 && !isIdempotent(url, methodName) {
+		retries = 0
+	}
 	return retries
 }
+
+// readMethodPrefixes are the prefixes of the get-style methods, which are idempotent unless it's configured
+var readMethodPrefixes = []string{"get", "query", "list", "find", "select", "search", "count", "exists", "is", "has"}
+
+// isIdempotent tells whether the method is safe to be retried by the idempotent of the method or the reference,
+// and the get-style methods are idempotent if neither is configured. The requests not sent, i.e. the transport
+// errors, are still retried for the other methods.
+func isIdempotent(url *common.URL, methodName string) bool {
+	if v := url.GetMethodParam(methodName, constant.IDEMPOTENT_KEY, url.GetParam(constant.IDEMPOTENT_KEY, "")); v != "" {
+		idempotent, err := strconv.ParseBool(v)
+		if err == nil {
+			return idempotent
+		}
+		logger.Warnf("The idempotent %s of the method %s is invalid, and it's decided by the method name", v, methodName)
+	}
+	lower := strings.ToLower(methodName)
+	for _, prefix := range readMethodPrefixes {
+		// the prefix is a word of the method name, e.g. GetUser or get_user rather than Issue
+		if strings.HasPrefix(lower, prefix) && (len(methodName) == len(prefix) ||
+			!unicode.IsLower(rune(methodName[len(prefix)]))) {
+			return true
+		}
+	}
+	return false
+}
@@ -25,12 +25,16 @@ import (
 )
 
 import (
+	perrors "github.com/pkg/errors"
+
 	"github.com/stretchr/testify/assert"
 )
 
 import (
 	clusterpkg "dubbo.apache.org/dubbo-go/v3/cluster/cluster"
+	"dubbo.apache.org/dubbo-go/v3/cluster/directory"
 	"dubbo.apache.org/dubbo-go/v3/cluster/directory/static"
+	"dubbo.apache.org/dubbo-go/v3/cluster/loadbalance"
 	"dubbo.apache.org/dubbo-go/v3/cluster/loadbalance/random"
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
@@ -44,6 +48,8 @@ func normalInvoke(successCount int, urlParam url.Values, invocations ...*invocat
 	extension.SetLoadbalance("random", random.NewLoadBalance)
 	failoverCluster := newCluster()
 
+	// the invocations without the method names are retried only if they are idempotent
+	urlParam.Set(constant.IDEMPOTENT_KEY, "true")
 	var invokers []protocol.Invoker
 	for i := 0; i < 10; i++ {
 		newUrl, _ := common.NewURL(fmt.Sprintf("dubbo://192.168.1.%v:20000/com.ikurento.user.UserProvider", i), common.WithParams(urlParam))
@@ -115,3 +121,165 @@ func TestFailoverDestroy(t *testing.T) {
 	clusterInvoker.Destroy()
 	assert.Equal(t, false, clusterInvoker.IsAvailable())
 }
+
+type firstLoadBalance struct{}
+
+func (firstLoadBalance) Select(invokers []protocol.Invoker, _ protocol.Invocation) protocol.Invoker {
+	return invokers[0]
+}
+
+type countInvoker struct {
+	protocol.BaseInvoker
+	err   error
+	count int
+}
+
+func (c *countInvoker) Invoke(context.Context, protocol.Invocation) protocol.Result {
+	c.count++
+	return &protocol.RPCResult{Err: c.err}
+}
+
+// nolint
+func TestFailoverRetryPreferHealthyInvoker(t *testing.T) {
+	extension.SetLoadbalance("first", func() loadbalance.LoadBalance {
+		return firstLoadBalance{}
+	})
+	defer protocol.CleanAllStatus()
+
+	urlParams := url.Values{}
+	urlParams.Set(constant.LOADBALANCE_KEY, "first")
+	urlParams.Set(constant.RETRIES_KEY, "1")
+	urlParams.Set(constant.IDEMPOTENT_KEY, "true")
+	var invokers []*countInvoker
+	for i := 0; i < 3; i++ {
+		u, _ := common.NewURL(fmt.Sprintf("dubbo://192.168.2.%v:20000/com.ikurento.user.UserProvider", i), common.WithParams(urlParams))
+		invokers = append(invokers, &countInvoker{BaseInvoker: *protocol.NewBaseInvoker(u), err: perrors.New("error")})
+	}
+	// the first one fails, the second one has been failing recently and the third one is healthy
+	unhealthy, healthy := invokers[1], invokers[2]
+	healthy.err = nil
+	for i := 0; i < 10; i++ {
+		protocol.BeginCount(unhealthy.GetURL(), "test")
+		protocol.EndCount(unhealthy.GetURL(), "test", 1, false)
+		protocol.BeginCount(healthy.GetURL(), "test")
+		protocol.EndCount(healthy.GetURL(), "test", 1, true)
+	}
+
+	clusterInvoker := newCluster().Join(static.NewDirectory([]protocol.Invoker{invokers[0], unhealthy, healthy}))
+	ivc := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("test"))
+	for i := 0; i < 10; i++ {
+		result := clusterInvoker.Invoke(context.Background(), ivc)
+		assert.NoError(t, result.Error())
+	}
+	assert.Equal(t, 10, invokers[0].count)
+	assert.Equal(t, 0, unhealthy.count)
+	assert.Equal(t, 10, healthy.count)
+}
+
+// nolint
+func TestFailoverRetryAttachments(t *testing.T) {
+	extension.SetLoadbalance("first", func() loadbalance.LoadBalance {
+		return firstLoadBalance{}
+	})
+	var retried int
+	extension.SetRetrySuccessCallback(func(_ protocol.Invoker, _ protocol.Invocation, attempts int) {
+		retried = attempts
+	})
+	defer extension.SetRetrySuccessCallback(nil)
+
+	urlParams := url.Values{}
+	urlParams.Set(constant.LOADBALANCE_KEY, "first")
+	urlParams.Set(constant.RETRIES_KEY, "2")
+	urlParams.Set(constant.IDEMPOTENT_KEY, "true")
+	failing, _ := common.NewURL("dubbo://192.168.3.1:20000/com.ikurento.user.UserProvider", common.WithParams(urlParams))
+	healthy, _ := common.NewURL("dubbo://192.168.3.2:20000/com.ikurento.user.UserProvider", common.WithParams(urlParams))
+	invokers := []protocol.Invoker{
+		&countInvoker{BaseInvoker: *protocol.NewBaseInvoker(failing), err: perrors.New("error")},
+		&countInvoker{BaseInvoker: *protocol.NewBaseInvoker(healthy)},
+	}
+
+	clusterInvoker := newCluster().Join(static.NewDirectory(invokers))
+	result := clusterInvoker.Invoke(context.Background(), invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("test")))
+	assert.NoError(t, result.Error())
+	assert.Equal(t, "2", result.Attachment(constant.RETRY_ATTEMPTS_KEY, ""))
+	assert.Equal(t, "192.168.3.1:20000,192.168.3.2:20000", result.Attachment(constant.RETRY_ADDRESSES_KEY, ""))
+	assert.Equal(t, 2, retried)
+}
+
+// fallbackDirectory lists the invokers of the fallback protocol apart
+type fallbackDirectory struct {
+	directory.Directory
+	fallback []protocol.Invoker
+}
+
+func (d *fallbackDirectory) ListFallback(protocol.Invocation) []protocol.Invoker {
+	return d.fallback
+}
+
+// nolint
+func TestFailoverFallbackProtocol(t *testing.T) {
+	extension.SetLoadbalance("first", func() loadbalance.LoadBalance {
+		return firstLoadBalance{}
+	})
+
+	urlParams := url.Values{}
+	urlParams.Set(constant.LOADBALANCE_KEY, "first")
+	urlParams.Set(constant.RETRIES_KEY, "1")
+	urlParams.Set(constant.IDEMPOTENT_KEY, "true")
+	urlParams.Set(constant.FALLBACK_PROTOCOL_KEY, "dubbo")
+	var invokers []protocol.Invoker
+	var triInvokers []*countInvoker
+	for i := 0; i < 2; i++ {
+		u, _ := common.NewURL(fmt.Sprintf("tri://192.168.4.%v:20000/com.ikurento.user.UserProvider", i), common.WithParams(urlParams))
+		triInvoker := &countInvoker{BaseInvoker: *protocol.NewBaseInvoker(u), err: perrors.New("error")}
+		triInvokers = append(triInvokers, triInvoker)
+		invokers = append(invokers, triInvoker)
+	}
+	u, _ := common.NewURL("dubbo://192.168.4.9:20000/com.ikurento.user.UserProvider", common.WithParams(urlParams))
+	dubboInvoker := &countInvoker{BaseInvoker: *protocol.NewBaseInvoker(u)}
+
+	dir := &fallbackDirectory{Directory: static.NewDirectory(invokers), fallback: []protocol.Invoker{dubboInvoker}}
+	clusterInvoker := newCluster().Join(dir)
+	result := clusterInvoker.Invoke(context.Background(), invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("test")))
+	assert.NoError(t, result.Error())
+	assert.Equal(t, 1, triInvokers[0].count)
+	assert.Equal(t, 1, triInvokers[1].count)
+	assert.Equal(t, 1, dubboInvoker.count)
+	assert.Equal(t, "3", result.Attachment(constant.RETRY_ATTEMPTS_KEY, ""))
+
+	// the fallback invokers are not tried on the business errors
+	triInvokers[0].err = protocol.NewBizError(perrors.New("biz"))
+	result = clusterInvoker.Invoke(context.Background(), invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("test")))
+	assert.Error(t, result.Error())
+	assert.Equal(t, 1, dubboInvoker.count)
+
+	// nor while the listed invokers succeed
+	triInvokers[0].err = nil
+	result = clusterInvoker.Invoke(context.Background(), invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("test")))
+	assert.NoError(t, result.Error())
+	assert.Equal(t, 1, dubboInvoker.count)
+}
+
+// nolint
+func TestFailoverRetryGenericInvocation(t *testing.T) {
+	for method, tries := range map[string]int{"GetUser": 3, "CreateUser": 1} {
+		var invokers []protocol.Invoker
+		var counters []*countInvoker
+		for i := 0; i < 3; i++ {
+			u, _ := common.NewURL(fmt.Sprintf("dubbo://192.168.3.%v:20000/com.ikurento.user.UserProvider", i))
+			counter := &countInvoker{BaseInvoker: *protocol.NewBaseInvoker(u), err: perrors.New("error")}
+			invokers = append(invokers, counter)
+			counters = append(counters, counter)
+		}
+		clusterInvoker := newCluster().Join(static.NewDirectory(invokers))
+		// the generic invocations are retried by the idempotence of the methods they invoke
+		ivc := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName(constant.GENERIC),
+			invocation.WithArguments([]interface{}{method, []string{}, []interface{}{}}))
+		assert.Error(t, clusterInvoker.Invoke(context.Background(), ivc).Error())
+		count := 0
+		for _, counter := range counters {
+			count += counter.count
+		}
+		assert.Equal(t, tries, count, method)
+	}
+}
@@ -69,6 +69,10 @@ func (invoker *clusterInvoker) Invoke(ctx context.Context, invocation protocol.I
 		}
 	}
 
+	if quorum := invoker.GetURL().GetParamByIntValue(constant.FORKING_QUORUM_KEY, 0); quorum > 0 {
+		return invokeWithQuorum(ctx, invocation, selected, quorum, time.Millisecond*time.Duration(timeouts))
+	}
+
 	resultQ := queue.New(1)
 	for _, ivk := range selected {
 		go func(k protocol.Invoker) {
@@ -96,3 +100,63 @@ func (invoker *clusterInvoker) Invoke(ctx context.Context, invocation protocol.I
 	}
 	return result
 }
+
+// invokeWithQuorum returns the aggregate of the results once @quorum invokers succeed, or the last error once
+// the quorum can't be reached any more. The rest of the invocations are canceled and their results are discarded.
+// The Result() of the aggregate is the []interface{} of the quorum results in the order they arrive, and the
+// attachments of them are merged.
+func invokeWithQuorum(ctx context.Context, invocation protocol.Invocation, selected []protocol.Invoker,
+	quorum int, timeout time.Duration) protocol.Result {
+	if len(selected) == 0 {
+		return &protocol.RPCResult{Err: fmt.Errorf("failed to forking invoke provider, no invoker is selected")}
+	}
+	if quorum > len(selected) {
+		quorum = len(selected)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// buffered so that the late invocations exit without being received
+	resultCh := make(chan protocol.Result, len(selected))
+	for _, ivk := range selected {
+		go func(k protocol.Invoker) {
+			resultCh <- k.Invoke(ctx, invocation)
+		}(ivk)
+	}
+
+	var (
+		successes []protocol.Result
+		failed    int
+		lastErr   error
+	)
+	for len(successes) < quorum {
+		select {
+		case result := <-resultCh:
+			if result.Error() != nil {
+				failed++
+				lastErr = result.Error()
+				if len(selected)-failed < quorum {
+					return &protocol.RPCResult{Err: fmt.Errorf("failed to forking invoke provider %v, "+
+						"only %d of %d succeed but the quorum is %d. Last error is: %v",
+						selected, len(selected)-failed, len(selected), quorum, lastErr)}
+				}
+				continue
+			}
+			successes = append(successes, result)
+		case <-ctx.Done():
+			return &protocol.RPCResult{Err: fmt.Errorf("failed to forking invoke provider %v, "+
+				"%d of the quorum %d succeed before timeout. Last error is: %v", selected, len(successes), quorum, lastErr)}
+		}
+	}
+
+	aggregate := &protocol.RPCResult{Attrs: make(map[string]interface{})}
+	rests := make([]interface{}, 0, len(successes))
+	for _, result := range successes {
+		rests = append(rests, result.Result())
+		for k, v := range result.Attachments() {
+			aggregate.Attrs[k] = v
+		}
+	}
+	aggregate.Rest = rests
+	return aggregate
+}
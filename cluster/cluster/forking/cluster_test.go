@@ -162,3 +162,48 @@ func TestForkingInvokeHalfTimeout(t *testing.T) {
 	assert.Equal(t, mockResult, result)
 	wg.Wait()
 }
+
+type delayInvoker struct {
+	protocol.BaseInvoker
+	delay    time.Duration
+	result   string
+	canceled chan struct{}
+}
+
+func (d *delayInvoker) Invoke(ctx context.Context, _ protocol.Invocation) protocol.Result {
+	select {
+	case <-time.After(d.delay):
+		return &protocol.RPCResult{Rest: d.result}
+	case <-ctx.Done():
+		close(d.canceled)
+		return &protocol.RPCResult{Err: ctx.Err()}
+	}
+}
+
+func TestForkingInvokeQuorum(t *testing.T) {
+	extension.SetLoadbalance(constant.LoadBalanceKeyRoundRobin, roundrobin.NewLoadBalance)
+	var invokers []protocol.Invoker
+	var delayInvokers []*delayInvoker
+	for i, delay := range []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 3 * time.Second} {
+		u, _ := common.NewURL(fmt.Sprintf("dubbo://192.168.1.%d:20000/com.ikurento.user.UserProvider?%s=3&%s=2&%s=5000&%s=%s",
+			i, constant.FORKS_KEY, constant.FORKING_QUORUM_KEY, constant.TIMEOUT_KEY,
+			constant.LOADBALANCE_KEY, constant.LoadBalanceKeyRoundRobin))
+		ivk := &delayInvoker{BaseInvoker: *protocol.NewBaseInvoker(u), delay: delay, result: strconv.Itoa(i), canceled: make(chan struct{})}
+		delayInvokers = append(delayInvokers, ivk)
+		invokers = append(invokers, ivk)
+	}
+	clusterInvoker := newCluster().Join(static.NewDirectory(invokers))
+
+	start := time.Now()
+	result := clusterInvoker.Invoke(context.Background(), &invocation.RPCInvocation{})
+	assert.NoError(t, result.Error())
+	assert.True(t, time.Since(start) < time.Second)
+	assert.ElementsMatch(t, []interface{}{"0", "1"}, result.Result())
+
+	// the late invocation is canceled once the quorum is reached
+	select {
+	case <-delayInvokers[2].canceled:
+	case <-time.After(time.Second):
+		assert.Fail(t, "the late invocation isn't canceled")
+	}
+}
@@ -23,6 +23,7 @@ import (
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/directory"
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 )
@@ -82,6 +83,30 @@ func (i *InterceptorInvoker) Invoke(ctx context.Context, invocation protocol.Inv
 	return i.interceptor.Invoke(ctx, i.next, invocation)
 }
 
+// Addresses returns the provider addresses resolved by the next invoker
+func (i *InterceptorInvoker) Addresses() []directory.Address {
+	if lister, ok := i.next.(directory.AddressLister); ok {
+		return lister.Addresses()
+	}
+	return nil
+}
+
+// WarmUp connects to the providers resolved by the next invoker in advance
+func (i *InterceptorInvoker) WarmUp(ctx context.Context, top int) error {
+	if warmer, ok := i.next.(directory.Warmer); ok {
+		return warmer.WarmUp(ctx, top)
+	}
+	return nil
+}
+
+// WaitReady waits until at least @min providers resolved by the next invoker are available
+func (i *InterceptorInvoker) WaitReady(ctx context.Context, min int) error {
+	if waiter, ok := i.next.(directory.ReadyWaiter); ok {
+		return waiter.WaitReady(ctx, min)
+	}
+	return directory.WaitInvokersReady(ctx, []protocol.Invoker{i.next}, min)
+}
+
 // Destroy will destroy invoker
 func (i *InterceptorInvoker) Destroy() {
 	i.next.Destroy()
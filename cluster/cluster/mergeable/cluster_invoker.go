@@ -0,0 +1,164 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mergeable
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/cluster/base"
+	"dubbo.apache.org/dubbo-go/v3/cluster/directory"
+	"dubbo.apache.org/dubbo-go/v3/cluster/merger"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	invocation_impl "dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+type clusterInvoker struct {
+	base.ClusterInvoker
+}
+
+func newClusterInvoker(directory directory.Directory) protocol.Invoker {
+	return &clusterInvoker{
+		ClusterInvoker: base.NewClusterInvoker(directory),
+	}
+}
+
+// Invoke invokes all of the groups and merges their results into the reply of @invocation
+func (invoker *clusterInvoker) Invoke(ctx context.Context, invocation protocol.Invocation) protocol.Result {
+	invokers := invoker.Directory.List(invocation)
+	err := invoker.CheckInvokers(invokers, invocation)
+	if err != nil {
+		return &protocol.RPCResult{Err: err}
+	}
+	err = invoker.CheckWhetherDestroyed()
+	if err != nil {
+		return &protocol.RPCResult{Err: err}
+	}
+
+	mergerName := invoker.getMergerName(invocation.MethodName())
+	if mergerName == "" {
+		for _, ivk := range invokers {
+			if ivk.IsAvailable() {
+				return ivk.Invoke(ctx, invocation)
+			}
+		}
+		return invokers[0].Invoke(ctx, invocation)
+	}
+
+	reply := reflect.ValueOf(invocation.Reply())
+	if reply.Kind() != reflect.Ptr || reply.IsNil() {
+		return &protocol.RPCResult{Err: perrors.Errorf("the reply of the method %s to merge should be a pointer, but got %T",
+			invocation.MethodName(), invocation.Reply())}
+	}
+	if reply.Elem().Kind() == reflect.Array {
+		// the merged results of the groups don't fit in the fixed length of an array
+		return &protocol.RPCResult{Err: perrors.Errorf("the reply of the method %s to merge should be a slice instead of the array %s",
+			invocation.MethodName(), reply.Elem().Type())}
+	}
+
+	// every group fills the reply of its own invocation
+	groupReplies := make([]reflect.Value, len(invokers))
+	groupResults := make([]protocol.Result, len(invokers))
+	var wg sync.WaitGroup
+	for i, ivk := range invokers {
+		groupReplies[i] = reflect.New(reply.Elem().Type())
+		wg.Add(1)
+		go func(i int, ivk protocol.Invoker) {
+			defer wg.Done()
+			groupResults[i] = ivk.Invoke(ctx, newGroupInvocation(invocation, groupReplies[i].Interface()))
+		}(i, ivk)
+	}
+	wg.Wait()
+
+	results := make([]interface{}, 0, len(invokers))
+	for i, result := range groupResults {
+		if result.Error() != nil {
+			logger.Errorf("Invoke the group of %v to merge failed, error: %v", invokers[i].GetURL(), result.Error())
+			err = result.Error()
+			continue
+		}
+		results = append(results, groupReplies[i].Elem().Interface())
+	}
+	if len(results) == 0 {
+		return &protocol.RPCResult{Err: err}
+	}
+
+	if len(results) > 1 {
+		if mergerName == "true" || mergerName == constant.DEFAULT_MERGER {
+			mergerName = merger.DefaultName(reply.Elem().Kind())
+			if mergerName == "" {
+				return &protocol.RPCResult{Err: perrors.Errorf("there isn't any merger for the reply of type %s of the method %s",
+					reply.Elem().Type(), invocation.MethodName())}
+			}
+		}
+		if !extension.HasMerger(mergerName) {
+			return &protocol.RPCResult{Err: perrors.Errorf("the merger %s of the method %s is not registered, make sure you have import the package",
+				mergerName, invocation.MethodName())}
+		}
+		merged, err := extension.GetMerger(mergerName).Merge(results)
+		if err != nil {
+			return &protocol.RPCResult{Err: perrors.WithMessagef(err, "merge the results of the method %s", invocation.MethodName())}
+		}
+		results = []interface{}{merged}
+	}
+	if results[0] != nil {
+		value := reflect.ValueOf(results[0])
+		if !value.Type().AssignableTo(reply.Elem().Type()) {
+			return &protocol.RPCResult{Err: perrors.Errorf("the merged result of type %s can not be set to the reply of type %s of the method %s",
+				value.Type(), reply.Elem().Type(), invocation.MethodName())}
+		}
+		reply.Elem().Set(value)
+	}
+	return &protocol.RPCResult{Rest: invocation.Reply()}
+}
+
+// getMergerName returns the merger of @methodName, which falls back to the one of the reference
+func (invoker *clusterInvoker) getMergerName(methodName string) string {
+	url := invoker.GetURL()
+	if url.SubURL != nil {
+		// the url of the registry directory carries the one of the reference
+		url = url.SubURL
+	}
+	return url.GetMethodParam(methodName, constant.MERGER_KEY, url.GetParam(constant.MERGER_KEY, ""))
+}
+
+// newGroupInvocation copies @origin with @reply, and the attachments are copied since the filters may change them
+func newGroupInvocation(origin protocol.Invocation, reply interface{}) protocol.Invocation {
+	attachments := make(map[string]interface{}, len(origin.Attachments()))
+	for k, v := range origin.Attachments() {
+		attachments[k] = v
+	}
+	ivc := invocation_impl.NewRPCInvocationWithOptions(invocation_impl.WithMethodName(origin.MethodName()),
+		invocation_impl.WithArguments(origin.Arguments()), invocation_impl.WithParameterTypes(origin.ParameterTypes()),
+		invocation_impl.WithParameterValues(origin.ParameterValues()), invocation_impl.WithReply(reply),
+		invocation_impl.WithAttachments(attachments), invocation_impl.WithInvoker(origin.Invoker()))
+	for k, v := range origin.Attributes() {
+		ivc.SetAttribute(k, v)
+	}
+	return ivc
+}
@@ -0,0 +1,124 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mergeable
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/directory/static"
+	_ "dubbo.apache.org/dubbo-go/v3/cluster/merger/collection"
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+// groupInvoker replies the users of its group
+type groupInvoker struct {
+	protocol.BaseInvoker
+	failed bool
+}
+
+func newGroupInvoker(group string, params ...string) *groupInvoker {
+	url, _ := common.NewURL(fmt.Sprintf("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?group=%s", group),
+		common.WithParamsValue(constant.MERGER_KEY, "true"))
+	for i := 0; i+1 < len(params); i += 2 {
+		url.SetParam(params[i], params[i+1])
+	}
+	return &groupInvoker{BaseInvoker: *protocol.NewBaseInvoker(url)}
+}
+
+func (gi *groupInvoker) Invoke(_ context.Context, inv protocol.Invocation) protocol.Result {
+	if gi.failed {
+		return &protocol.RPCResult{Err: perrors.New("group failed")}
+	}
+	group := gi.GetURL().GetParam(constant.GROUP_KEY, "")
+	switch reply := inv.Reply().(type) {
+	case *[]string:
+		*reply = []string{group + "-1", group + "-2"}
+	case *map[string]string:
+		*reply = map[string]string{group: inv.Arguments()[0].(string)}
+	}
+	return &protocol.RPCResult{Rest: inv.Reply()}
+}
+
+func TestMergeableInvokeSlices(t *testing.T) {
+	invoker := NewCluster().Join(static.NewDirectory([]protocol.Invoker{newGroupInvoker("a"), newGroupInvoker("b")}))
+	var reply []string
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUsers"), invocation.WithReply(&reply))
+	result := invoker.Invoke(context.Background(), inv)
+	assert.NoError(t, result.Error())
+	assert.Equal(t, &reply, result.Result())
+	sort.Strings(reply)
+	assert.Equal(t, []string{"a-1", "a-2", "b-1", "b-2"}, reply)
+}
+
+func TestMergeableInvokeMaps(t *testing.T) {
+	failed := newGroupInvoker("c", constant.MERGER_KEY, "map")
+	failed.failed = true
+	invokers := []protocol.Invoker{newGroupInvoker("a", constant.MERGER_KEY, "map"), newGroupInvoker("b"), failed}
+	invoker := NewCluster().Join(static.NewDirectory(invokers))
+	reply := map[string]string{}
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUserMap"),
+		invocation.WithArguments([]interface{}{"user"}), invocation.WithReply(&reply))
+	result := invoker.Invoke(context.Background(), inv)
+	// the failed groups are skipped
+	assert.NoError(t, result.Error())
+	assert.Equal(t, map[string]string{"a": "user", "b": "user"}, reply)
+}
+
+func TestMergeableInvokeWithoutMerger(t *testing.T) {
+	invokers := []protocol.Invoker{newGroupInvoker("a", constant.MERGER_KEY, ""), newGroupInvoker("b")}
+	invoker := NewCluster().Join(static.NewDirectory(invokers))
+	var reply []string
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUsers"), invocation.WithReply(&reply))
+	result := invoker.Invoke(context.Background(), inv)
+	assert.NoError(t, result.Error())
+	assert.Equal(t, []string{"a-1", "a-2"}, reply)
+}
+
+func TestMergeableInvokeWithUnknownMerger(t *testing.T) {
+	invokers := []protocol.Invoker{newGroupInvoker("a", constant.MERGER_KEY, "unknown"), newGroupInvoker("b")}
+	invoker := NewCluster().Join(static.NewDirectory(invokers))
+	var reply []string
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUsers"), invocation.WithReply(&reply))
+	result := invoker.Invoke(context.Background(), inv)
+	assert.Error(t, result.Error())
+	assert.Contains(t, result.Error().Error(), "the merger unknown of the method GetUsers is not registered")
+}
+
+func TestMergeableInvokeArrays(t *testing.T) {
+	invoker := NewCluster().Join(static.NewDirectory([]protocol.Invoker{newGroupInvoker("a"), newGroupInvoker("b")}))
+	var reply [2]string
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUsers"), invocation.WithReply(&reply))
+	result := invoker.Invoke(context.Background(), inv)
+	// the array reply is rejected instead of panicking on the merged slice
+	assert.Error(t, result.Error())
+	assert.Contains(t, result.Error().Error(), "should be a slice instead of the array [2]string")
+}
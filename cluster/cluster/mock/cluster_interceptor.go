@@ -0,0 +1,102 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mock
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	clusterpkg "dubbo.apache.org/dubbo-go/v3/cluster/cluster"
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+func init() {
+	clusterpkg.SetClusterInterceptor(constant.MOCK_KEY, newInterceptor)
+}
+
+// interceptor returns the recorded responses of the methods loaded from the fixtures instead of invoking the
+// cluster, so that the reference works without any provider.
+/**
+ * example:
+ * "UserProvider":
+ *   check: false
+ *   params:
+ *     mock: "file:testdata/user_provider.yml"
+ * The fixture file maps the methods to their recorded responses or errors in yaml or json, e.g.
+ *   GetUser:
+ *     response: {id: "A001", name: "Alex Stocks", age: 24}
+ *   DeleteUser:
+ *     error: "permission denied"
+ * The mock may reference a directory as well, in which the fixture of each method is in its own file named
+ * <method>.yml, <method>.yaml or <method>.json. The invocations of the methods without the fixtures fail.
+ */
+type interceptor struct{}
+
+func newInterceptor() clusterpkg.Interceptor {
+	return &interceptor{}
+}
+
+// Invoke returns the recorded response of the method if the mock of the reference references the fixtures
+func (i *interceptor) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	mock := referenceURL(invoker).GetParam(constant.MOCK_KEY, "")
+	if !strings.HasPrefix(mock, constant.MOCK_FILE_PREFIX) {
+		return invoker.Invoke(ctx, invocation)
+	}
+	path := strings.TrimPrefix(mock, constant.MOCK_FILE_PREFIX)
+	fixture, err := LoadFixture(path, invocation.MethodName())
+	if err != nil {
+		logger.Warnf("[Mock Cluster Interceptor] load the mock fixture of the method %s error: %v", invocation.MethodName(), err)
+		return &protocol.RPCResult{Err: err}
+	}
+	if fixture.Error != "" {
+		return &protocol.RPCResult{Err: perrors.New(fixture.Error)}
+	}
+
+	reply := invocation.Reply()
+	if reply == nil {
+		var response interface{}
+		reply = &response
+	}
+	if len(fixture.Response) > 0 {
+		if err = json.Unmarshal(fixture.Response, reply); err != nil {
+			return &protocol.RPCResult{Err: perrors.Wrapf(err, "the mock response of the method %s in %s can't be "+
+				"decoded into %T", invocation.MethodName(), path, reply)}
+		}
+	}
+	return &protocol.RPCResult{Rest: reply}
+}
+
+// referenceURL returns the url of the reference of the cluster invoker, which is the sub url of the registry url
+// if the invoker joins the directory of a registry
+func referenceURL(invoker protocol.Invoker) *common.URL {
+	url := invoker.GetURL()
+	if url.SubURL != nil {
+		return url.SubURL
+	}
+	return url
+}
@@ -0,0 +1,156 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mock
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	clusterpkg "dubbo.apache.org/dubbo-go/v3/cluster/cluster"
+	_ "dubbo.apache.org/dubbo-go/v3/cluster/cluster/failover"
+	"dubbo.apache.org/dubbo-go/v3/cluster/directory/base"
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+type user struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+type countInvoker struct {
+	protocol.BaseInvoker
+	count int
+}
+
+func (c *countInvoker) Invoke(context.Context, protocol.Invocation) protocol.Result {
+	c.count++
+	return &protocol.RPCResult{}
+}
+
+// newCountInvoker returns the invoker of the cluster joining the directory of a registry, whose reference mocks @mock
+func newCountInvoker(t *testing.T, mock string) *countInvoker {
+	url, err := common.NewURL("registry://127.0.0.1:2181")
+	assert.NoError(t, err)
+	url.SubURL, err = common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?mock=" + mock)
+	assert.NoError(t, err)
+	return &countInvoker{BaseInvoker: *protocol.NewBaseInvoker(url)}
+}
+
+func invoke(invoker protocol.Invoker, method string, reply interface{}) protocol.Result {
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName(method), invocation.WithReply(reply))
+	return clusterpkg.BuildInterceptorChain(invoker).Invoke(context.Background(), inv)
+}
+
+func TestInterceptorInvokeFixtureFile(t *testing.T) {
+	invoker := newCountInvoker(t, "file:testdata/user_provider.yml")
+
+	reply := &user{}
+	result := invoke(invoker, "GetUser", reply)
+	assert.NoError(t, result.Error())
+	assert.Equal(t, &user{ID: "A001", Name: "Alex Stocks", Age: 24}, reply)
+	assert.Same(t, reply, result.Result())
+
+	result = invoke(invoker, "DeleteUser", &user{})
+	assert.EqualError(t, result.Error(), "permission denied")
+
+	result = invoke(invoker, "UpdateUser", &user{})
+	assert.Error(t, result.Error())
+	assert.Contains(t, result.Error().Error(), "UpdateUser")
+	assert.Equal(t, 0, invoker.count)
+}
+
+func TestInterceptorInvokeFixtureDirectory(t *testing.T) {
+	invoker := newCountInvoker(t, "file:testdata/fixtures")
+
+	reply := &user{}
+	assert.NoError(t, invoke(invoker, "GetUser", reply).Error())
+	assert.Equal(t, &user{ID: "A002", Name: "Moorse", Age: 30}, reply)
+
+	result := invoke(invoker, "DeleteUser", &user{})
+	assert.Error(t, result.Error())
+	assert.Contains(t, result.Error().Error(), "DeleteUser")
+	assert.Equal(t, 0, invoker.count)
+}
+
+func TestInterceptorInvokeWithoutFixtures(t *testing.T) {
+	invoker := newCountInvoker(t, "")
+	assert.NoError(t, invoke(invoker, "GetUser", &user{}).Error())
+	assert.Equal(t, 1, invoker.count)
+
+	invoker = newCountInvoker(t, "file:testdata/absent.yml")
+	assert.Error(t, invoke(invoker, "GetUser", &user{}).Error())
+	assert.Equal(t, 0, invoker.count)
+}
+
+// emptyDirectory is the directory of a registry without any provider
+type emptyDirectory struct {
+	base.Directory
+}
+
+func (d *emptyDirectory) List(protocol.Invocation) []protocol.Invoker {
+	return nil
+}
+
+func (d *emptyDirectory) Destroy() {}
+
+func TestInterceptorWithoutProviders(t *testing.T) {
+	url, err := common.NewURL("registry://127.0.0.1:2181")
+	assert.NoError(t, err)
+	url.SubURL, err = common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?mock=file:testdata/user_provider.yml")
+	assert.NoError(t, err)
+	invoker := extension.GetCluster(constant.ClusterKeyFailover).Join(&emptyDirectory{Directory: base.NewDirectory(url)})
+
+	// the fixtures are served by the cluster without any provider
+	reply := &user{}
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUser"), invocation.WithReply(reply))
+	assert.NoError(t, invoker.Invoke(context.Background(), inv).Error())
+	assert.Equal(t, &user{ID: "A001", Name: "Alex Stocks", Age: 24}, reply)
+}
+
+func TestLoadFixtureCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fixtures")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "fixtures.yml")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("GetUser:\n  error: recorded\n"), 0o644))
+	fixture, err := LoadFixture(path, "GetUser")
+	assert.NoError(t, err)
+	assert.Equal(t, "recorded", fixture.Error)
+
+	// the fixture is loaded once
+	assert.NoError(t, os.Remove(path))
+	fixture, err = LoadFixture(path, "GetUser")
+	assert.NoError(t, err)
+	assert.Equal(t, "recorded", fixture.Error)
+	_, err = LoadFixture(path, "DeleteUser")
+	assert.Error(t, err)
+}
@@ -0,0 +1,105 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mock
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+import (
+	"github.com/ghodss/yaml"
+
+	perrors "github.com/pkg/errors"
+)
+
+// fixtureExtensions are the extensions of the fixture files of the methods in a fixture directory
+var fixtureExtensions = []string{".yml", ".yaml", ".json"}
+
+// fixtures caches the fixtures loaded by LoadFixture by their paths and methods
+var fixtures sync.Map
+
+// Fixture is the recorded response of a method
+type Fixture struct {
+	// Response is the response decoded into the reply of the invocation
+	Response json.RawMessage `json:"response"`
+	// Error is the message of the error returned instead of the response
+	Error string `json:"error"`
+}
+
+// fixtureKey is the key of the fixture of a method cached in fixtures
+type fixtureKey struct {
+	path   string
+	method string
+}
+
+// LoadFixture loads the fixture of @method from the fixture file or directory of @path. The fixtures are cached
+// once they are loaded, so the changes of the fixture files take effect after restarting.
+func LoadFixture(path string, method string) (*Fixture, error) {
+	key := fixtureKey{path: path, method: method}
+	if fixture, ok := fixtures.Load(key); ok {
+		return fixture.(*Fixture), nil
+	}
+	fixture, err := loadFixture(path, method)
+	if err != nil {
+		return nil, err
+	}
+	fixtures.Store(key, fixture)
+	return fixture, nil
+}
+
+func loadFixture(path string, method string) (*Fixture, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, perrors.Wrapf(err, "the mock fixtures %s can't be loaded", path)
+	}
+	if info.IsDir() {
+		for _, ext := range fixtureExtensions {
+			content, err := ioutil.ReadFile(filepath.Join(path, method+ext))
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return nil, perrors.WithStack(err)
+			}
+			fixture := &Fixture{}
+			if err = yaml.Unmarshal(content, fixture); err != nil {
+				return nil, perrors.Wrapf(err, "the mock fixture %s is invalid", filepath.Join(path, method+ext))
+			}
+			return fixture, nil
+		}
+		return nil, perrors.Errorf("there isn't the mock fixture of the method %s in the directory %s", method, path)
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, perrors.WithStack(err)
+	}
+	methodFixtures := make(map[string]*Fixture)
+	if err = yaml.Unmarshal(content, &methodFixtures); err != nil {
+		return nil, perrors.Wrapf(err, "the mock fixtures %s are invalid", path)
+	}
+	fixture, ok := methodFixtures[method]
+	if !ok || fixture == nil {
+		return nil, perrors.Errorf("there isn't the mock fixture of the method %s in the file %s", method, path)
+	}
+	return fixture, nil
+}
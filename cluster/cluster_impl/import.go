@@ -28,6 +28,8 @@ import (
 	_ "dubbo.apache.org/dubbo-go/v3/cluster/cluster/failover"
 	_ "dubbo.apache.org/dubbo-go/v3/cluster/cluster/failsafe"
 	_ "dubbo.apache.org/dubbo-go/v3/cluster/cluster/forking"
+	_ "dubbo.apache.org/dubbo-go/v3/cluster/cluster/mergeable"
+	_ "dubbo.apache.org/dubbo-go/v3/cluster/cluster/mock"
 	_ "dubbo.apache.org/dubbo-go/v3/cluster/cluster/zoneaware"
 )
 
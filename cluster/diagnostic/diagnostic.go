@@ -0,0 +1,219 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package diagnostic dumps the live routing state of the services referred by the consumer,
+// which includes the routers and their rules, the invokers, and the recent selections.
+package diagnostic
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/router"
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+// maxSelections is the number of the recent selections kept for each service
+const maxSelections = 32
+
+// ServiceQueryKey is the query parameter of the service key for the http handler
+const ServiceQueryKey = "service"
+
+// Source owns the router chain of a service, e.g. the directory of a reference
+type Source interface {
+	RouterChain() router.Chain
+}
+
+// snapshotter is implemented by the router chains copying their routers and invokers
+type snapshotter interface {
+	Snapshot() ([]router.PriorityRouter, []protocol.Invoker)
+}
+
+// ServiceState is the dump of the routing state of a service
+type ServiceState struct {
+	ServiceKey string         `json:"serviceKey"`
+	Routers    []RouterState  `json:"routers"`
+	Invokers   []InvokerState `json:"invokers"`
+	Selections []Selection    `json:"recentSelections"`
+}
+
+// RouterState is the dump of a router in the chain
+type RouterState struct {
+	Name     string      `json:"name"`
+	Priority int64       `json:"priority"`
+	Rule     interface{} `json:"rule,omitempty"`
+}
+
+// InvokerState is the dump of an invoker to be routed
+type InvokerState struct {
+	URL       string `json:"url"`
+	Available bool   `json:"available"`
+}
+
+// Selection is an invoker selected by the cluster
+type Selection struct {
+	Time    time.Time `json:"time"`
+	Method  string    `json:"method"`
+	Invoker string    `json:"invoker"`
+}
+
+type service struct {
+	source Source
+
+	lock sync.Mutex
+	// selections is the ring of the recent selections, and next is the position of the next one
+	selections []Selection
+	next       int
+}
+
+// services holds the map[string]*service of the registered services
+var services sync.Map
+
+// ServiceKey returns the service key of the directory url @url, which is the consumer url of the sub url
+// if the directory is created by the registry.
+func ServiceKey(url *common.URL) string {
+	if url == nil {
+		return ""
+	}
+	if key := url.ServiceKey(); key != "" || url.SubURL == nil {
+		return key
+	}
+	return url.SubURL.ServiceKey()
+}
+
+// Register registers @source as the state of the service @serviceKey, and the first one wins if the service
+// is registered by more than one source, e.g. the directories of the reference with multiple registries.
+func Register(serviceKey string, source Source) {
+	if serviceKey == "" || source == nil {
+		return
+	}
+	services.LoadOrStore(serviceKey, &service{source: source, selections: make([]Selection, 0, maxSelections)})
+}
+
+// Unregister removes the service @serviceKey if it's registered by @source
+func Unregister(serviceKey string, source Source) {
+	if s, ok := services.Load(serviceKey); ok && s.(*service).source == source {
+		services.Delete(serviceKey)
+	}
+}
+
+// RecordSelection records that @invoker is selected for the invocation of @method of the service @serviceKey
+func RecordSelection(serviceKey string, method string, invoker protocol.Invoker) {
+	s, ok := services.Load(serviceKey)
+	if !ok || invoker == nil {
+		return
+	}
+	s.(*service).record(Selection{Time: time.Now(), Method: method, Invoker: invoker.GetURL().Location})
+}
+
+func (s *service) record(selection Selection) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if len(s.selections) < maxSelections {
+		s.selections = append(s.selections, selection)
+	} else {
+		s.selections[s.next] = selection
+	}
+	s.next = (s.next + 1) % maxSelections
+}
+
+// recentSelections copies the recent selections from the oldest one
+func (s *service) recentSelections() []Selection {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	selections := make([]Selection, 0, len(s.selections))
+	if len(s.selections) == maxSelections {
+		selections = append(selections, s.selections[s.next:]...)
+		return append(selections, s.selections[:s.next]...)
+	}
+	return append(selections, s.selections...)
+}
+
+// Dump takes the snapshot of the routing state of the service @serviceKey
+func Dump(serviceKey string) (*ServiceState, error) {
+	s, ok := services.Load(serviceKey)
+	if !ok {
+		return nil, perrors.Errorf("the service %s isn't referred", serviceKey)
+	}
+	state := &ServiceState{
+		ServiceKey: serviceKey,
+		Routers:    []RouterState{},
+		Invokers:   []InvokerState{},
+		Selections: s.(*service).recentSelections(),
+	}
+	chain, ok := s.(*service).source.RouterChain().(snapshotter)
+	if !ok {
+		return state, nil
+	}
+	routers, invokers := chain.Snapshot()
+	for _, r := range routers {
+		routerState := RouterState{Name: routerName(r), Priority: r.Priority()}
+		if dumper, ok := r.(router.RuleDumper); ok {
+			routerState.Rule = dumper.DumpRule()
+		}
+		state.Routers = append(state.Routers, routerState)
+	}
+	for _, invoker := range invokers {
+		state.Invokers = append(state.Invokers, InvokerState{URL: invoker.GetURL().String(), Available: invoker.IsAvailable()})
+	}
+	return state, nil
+}
+
+// DumpJSON dumps the routing state of the service @serviceKey as json
+func DumpJSON(serviceKey string) ([]byte, error) {
+	state, err := Dump(serviceKey)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(state)
+}
+
+// Handler returns the http handler dumping the routing state of the service specified by the query parameter
+// service, e.g. /debug/routing?service=group/com.ikurento.user.UserProvider:1.0.0, which isn't served by default.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serviceKey := r.URL.Query().Get(ServiceQueryKey)
+		if serviceKey == "" {
+			http.Error(w, "the service key is missing", http.StatusBadRequest)
+			return
+		}
+		data, err := DumpJSON(serviceKey)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(data)
+	})
+}
+
+func routerName(r router.PriorityRouter) string {
+	if named, ok := r.(interface{ Name() string }); ok {
+		return named.Name()
+	}
+	return fmt.Sprintf("%T", r)
+}
@@ -0,0 +1,175 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package diagnostic
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/router"
+	"dubbo.apache.org/dubbo-go/v3/cluster/router/chain"
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+type ruleRouter struct{}
+
+func (r *ruleRouter) Route(invokers []protocol.Invoker, _ *common.URL, _ protocol.Invocation) []protocol.Invoker {
+	return invokers
+}
+
+func (r *ruleRouter) URL() *common.URL {
+	return nil
+}
+
+func (r *ruleRouter) Priority() int64 {
+	return 1
+}
+
+func (r *ruleRouter) Name() string {
+	return "rule"
+}
+
+func (r *ruleRouter) DumpRule() interface{} {
+	return map[string]string{"key": "canary"}
+}
+
+type source struct {
+	chain *chain.RouterChain
+}
+
+func (s *source) RouterChain() router.Chain {
+	return s.chain
+}
+
+func newInvokers(t *testing.T, n int) []protocol.Invoker {
+	invokers := make([]protocol.Invoker, 0, n)
+	for i := 0; i < n; i++ {
+		url, err := common.NewURL(fmt.Sprintf("dubbo://192.168.0.%d:20000/com.ikurento.user.UserProvider", i))
+		assert.NoError(t, err)
+		invokers = append(invokers, protocol.NewBaseInvoker(url))
+	}
+	return invokers
+}
+
+func TestDump(t *testing.T) {
+	invokers := newInvokers(t, 3)
+	serviceKey := ServiceKey(invokers[0].GetURL())
+	assert.Equal(t, "com.ikurento.user.UserProvider", serviceKey)
+	s := &source{chain: &chain.RouterChain{}}
+	s.chain.AddRouters([]router.PriorityRouter{&ruleRouter{}})
+	s.chain.SetInvokers(invokers)
+	Register(serviceKey, s)
+	defer Unregister(serviceKey, s)
+	// the first source wins
+	Register(serviceKey, &source{chain: &chain.RouterChain{}})
+
+	for i := 0; i < maxSelections+2; i++ {
+		RecordSelection(serviceKey, fmt.Sprintf("Get%d", i), invokers[i%3])
+	}
+	state, err := Dump(serviceKey)
+	assert.NoError(t, err)
+	assert.Equal(t, serviceKey, state.ServiceKey)
+	assert.Equal(t, []RouterState{{Name: "rule", Priority: 1, Rule: map[string]string{"key": "canary"}}}, state.Routers)
+	assert.Len(t, state.Invokers, 3)
+	for i, invoker := range state.Invokers {
+		assert.Equal(t, invokers[i].GetURL().String(), invoker.URL)
+		assert.True(t, invoker.Available)
+	}
+	// the oldest selections are dropped
+	assert.Len(t, state.Selections, maxSelections)
+	assert.Equal(t, "Get2", state.Selections[0].Method)
+	assert.Equal(t, invokers[2].GetURL().Location, state.Selections[0].Invoker)
+	assert.Equal(t, fmt.Sprintf("Get%d", maxSelections+1), state.Selections[maxSelections-1].Method)
+
+	_, err = Dump("com.ikurento.user.OrderProvider")
+	assert.Error(t, err)
+}
+
+func TestDumpConcurrently(t *testing.T) {
+	invokers := newInvokers(t, 4)
+	serviceKey := ServiceKey(invokers[0].GetURL())
+	s := &source{chain: &chain.RouterChain{}}
+	Register(serviceKey, s)
+	defer Unregister(serviceKey, s)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				s.chain.SetInvokers(invokers[:j%4+1])
+				s.chain.AddRouters([]router.PriorityRouter{&ruleRouter{}})
+				RecordSelection(serviceKey, "GetUser", invokers[i])
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_, err := DumpJSON(serviceKey)
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestHandler(t *testing.T) {
+	invokers := newInvokers(t, 2)
+	serviceKey := ServiceKey(invokers[0].GetURL())
+	s := &source{chain: &chain.RouterChain{}}
+	s.chain.AddRouters([]router.PriorityRouter{&ruleRouter{}})
+	s.chain.SetInvokers(invokers)
+	Register(serviceKey, s)
+	defer Unregister(serviceKey, s)
+
+	recorder := httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/?service="+serviceKey, nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	dump := &struct {
+		Routers []struct {
+			Name string `json:"name"`
+		} `json:"routers"`
+		Invokers []struct {
+			URL string `json:"url"`
+		} `json:"invokers"`
+	}{}
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), dump))
+	assert.Len(t, dump.Routers, 1)
+	assert.Equal(t, "rule", dump.Routers[0].Name)
+	assert.Len(t, dump.Invokers, 2)
+	assert.Equal(t, invokers[1].GetURL().String(), dump.Invokers[1].URL)
+
+	recorder = httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/?service=unknown", nil))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	recorder = httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
@@ -17,8 +17,20 @@
 
 package directory
 
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 )
 
@@ -33,3 +45,175 @@ type Directory interface {
 	// the return result directly.
 	List(invocation protocol.Invocation) []protocol.Invoker
 }
+
+// FallbackLister is implemented by the directories which keep the invokers of the fallback protocol of the reference
+// apart from the ones listed, so that they are only tried by the failover once the listed ones are exhausted.
+type FallbackLister interface {
+	// ListFallback lists the invokers of the fallback protocol, which aren't routed by the router chain
+	ListFallback(invocation protocol.Invocation) []protocol.Invoker
+}
+
+// Address is a provider address resolved by the directory
+type Address struct {
+	// Location is the host and port of the provider
+	Location string
+	// URL is the url of the invoker referring the provider
+	URL *common.URL
+	// Weight is the weight configured for the provider, which isn't warmed up
+	Weight int64
+	// Available is whether the invoker of the provider is available
+	Available bool
+}
+
+// AddressLister is implemented by the directories and the cluster invokers which expose the provider addresses
+// resolved currently, and the addresses change as the registry notifies.
+type AddressLister interface {
+	Addresses() []Address
+}
+
+// ResolveAddresses returns the addresses of @invokers, and the invokers exposing the addresses themselves,
+// e.g. the cluster invokers of the registries of a reference, are expanded into theirs.
+func ResolveAddresses(invokers []protocol.Invoker) []Address {
+	addresses := make([]Address, 0, len(invokers))
+	for _, invoker := range invokers {
+		if lister, ok := invoker.(AddressLister); ok {
+			addresses = append(addresses, lister.Addresses()...)
+			continue
+		}
+		url := invoker.GetURL()
+		addresses = append(addresses, Address{
+			Location:  url.Location,
+			URL:       url,
+			Weight:    url.GetParamInt(constant.WEIGHT_KEY, constant.DEFAULT_WEIGHT),
+			Available: invoker.IsAvailable(),
+		})
+	}
+	return addresses
+}
+
+// Warmer is implemented by the directories and the cluster invokers which connect to the providers resolved
+// currently in advance, so that the first invocations to them don't pay for the connecting.
+type Warmer interface {
+	// WarmUp connects to the @top providers of the highest weights, or all of them if it isn't positive,
+	// and returns once all of them are connected or @ctx is done.
+	WarmUp(ctx context.Context, top int) error
+}
+
+// WarmUpInvokers connects the @top invokers of the highest weights in @invokers concurrently, or all of them if
+// it isn't positive. The invokers warming up themselves, e.g. the cluster invokers of the registries of a reference,
+// are delegated to, and the invokers which aren't protocol.Connector are connected on demand only.
+func WarmUpInvokers(ctx context.Context, invokers []protocol.Invoker, top int) error {
+	if top > 0 && top < len(invokers) {
+		sorted := make([]protocol.Invoker, len(invokers))
+		copy(sorted, invokers)
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return sorted[i].GetURL().GetParamInt(constant.WEIGHT_KEY, constant.DEFAULT_WEIGHT) >
+				sorted[j].GetURL().GetParamInt(constant.WEIGHT_KEY, constant.DEFAULT_WEIGHT)
+		})
+		invokers = sorted[:top]
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(invokers))
+	for i, invoker := range invokers {
+		var connect func(context.Context) error
+		switch ivk := invoker.(type) {
+		case Warmer:
+			connect = func(ctx context.Context) error {
+				return ivk.WarmUp(ctx, top)
+			}
+		case protocol.Connector:
+			connect = ivk.Connect
+		default:
+			continue
+		}
+		wg.Add(1)
+		go func(i int, connect func(context.Context) error) {
+			defer wg.Done()
+			errs[i] = connect(ctx)
+		}(i, connect)
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return perrors.Wrap(ctx.Err(), "the connection warm up is not done")
+	}
+
+	var failures []string
+	for i, err := range errs {
+		if err != nil {
+			failures = append(failures, invokers[i].GetURL().Location+": "+err.Error())
+		}
+	}
+	if len(failures) > 0 {
+		return perrors.Errorf("failed to warm up the connections to %s", strings.Join(failures, ", "))
+	}
+	return nil
+}
+
+// ReadyWaiter is implemented by the directories and the cluster invokers whose providers change as the registry
+// notifies, so that the callers can wait for the providers instead of failing the first invocations.
+type ReadyWaiter interface {
+	// WaitReady returns once at least @min providers are available, or an error once @ctx is done.
+	// It wakes up on the notifications of the registry rather than polling.
+	WaitReady(ctx context.Context, min int) error
+}
+
+// CountAvailable returns the number of the available addresses in @addresses
+func CountAvailable(addresses []Address) int {
+	available := 0
+	for _, address := range addresses {
+		if address.Available {
+			available++
+		}
+	}
+	return available
+}
+
+// WaitInvokersReady returns once at least @min providers of @invokers are available, or an error once @ctx is done.
+// The invokers waiting for the providers themselves, e.g. the cluster invokers of the registries of a reference,
+// are waited for one more available provider each round, and the others are only checked once.
+func WaitInvokersReady(ctx context.Context, invokers []protocol.Invoker, min int) error {
+	for {
+		available := CountAvailable(ResolveAddresses(invokers))
+		if available >= min {
+			return nil
+		}
+
+		waitCtx, cancel := context.WithCancel(ctx)
+		woken := make(chan struct{}, len(invokers))
+		waiting := 0
+		for _, invoker := range invokers {
+			waiter, ok := invoker.(ReadyWaiter)
+			if !ok {
+				continue
+			}
+			own := 0
+			if lister, ok := invoker.(AddressLister); ok {
+				own = CountAvailable(lister.Addresses())
+			}
+			waiting++
+			go func(waiter ReadyWaiter, min int) {
+				if waiter.WaitReady(waitCtx, min) == nil {
+					woken <- struct{}{}
+				}
+			}(waiter, own+1)
+		}
+		if waiting == 0 {
+			cancel()
+			return perrors.Errorf("%d providers are available, fewer than %d", available, min)
+		}
+		select {
+		case <-woken:
+			cancel()
+		case <-ctx.Done():
+			cancel()
+			return perrors.Wrapf(ctx.Err(), "%d providers are available, fewer than %d", available, min)
+		}
+	}
+}
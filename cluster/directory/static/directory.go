@@ -17,11 +17,17 @@
 
 package static
 
+import (
+	"context"
+)
+
 import (
 	perrors "github.com/pkg/errors"
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/diagnostic"
+	dirpkg "dubbo.apache.org/dubbo-go/v3/cluster/directory"
 	"dubbo.apache.org/dubbo-go/v3/cluster/directory/base"
 	"dubbo.apache.org/dubbo-go/v3/cluster/router/chain"
 	"dubbo.apache.org/dubbo-go/v3/common"
@@ -49,6 +55,15 @@ func NewDirectory(invokers []protocol.Invoker) *directory {
 	return dir
 }
 
+// NewRegisteredDirectory creates the directory of @invokers registered to the diagnostics until it's destroyed,
+// which is only for the long-lived ones, e.g. the directory of a reference, rather than the ones recreated
+// on every refresh of the providers.
+func NewRegisteredDirectory(invokers []protocol.Invoker) *directory {
+	dir := NewDirectory(invokers)
+	diagnostic.Register(diagnostic.ServiceKey(dir.GetURL()), dir)
+	return dir
+}
+
 // for-loop invokers ,if all invokers is available ,then it means directory is available
 func (dir *directory) IsAvailable() bool {
 	if len(dir.invokers) == 0 {
@@ -76,9 +91,25 @@ func (dir *directory) List(invocation protocol.Invocation) []protocol.Invoker {
 	return routerChain.Route(dirUrl, invocation)
 }
 
+// Addresses returns the addresses of the invokers
+func (dir *directory) Addresses() []dirpkg.Address {
+	return dirpkg.ResolveAddresses(dir.invokers)
+}
+
+// WarmUp connects to the providers of the invokers in advance
+func (dir *directory) WarmUp(ctx context.Context, top int) error {
+	return dirpkg.WarmUpInvokers(ctx, dir.invokers, top)
+}
+
+// WaitReady waits until at least @min providers of the invokers are available
+func (dir *directory) WaitReady(ctx context.Context, min int) error {
+	return dirpkg.WaitInvokersReady(ctx, dir.invokers, min)
+}
+
 // Destroy Destroy
 func (dir *directory) Destroy() {
 	dir.Directory.Destroy(func() {
+		diagnostic.Unregister(diagnostic.ServiceKey(dir.GetURL()), dir)
 		for _, ivk := range dir.invokers {
 			ivk.Destroy()
 		}
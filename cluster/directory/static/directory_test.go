@@ -18,8 +18,10 @@
 package static
 
 import (
+	"context"
 	"fmt"
 	"testing"
+	"time"
 )
 
 import (
@@ -27,7 +29,9 @@ import (
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/diagnostic"
 	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
 )
@@ -57,3 +61,70 @@ func TestStaticDirDestroy(t *testing.T) {
 	staticDir.Destroy()
 	assert.Equal(t, false, staticDir.IsAvailable())
 }
+
+type connectInvoker struct {
+	protocol.BaseInvoker
+	connected bool
+	// connectedFirst is whether the invoker is connected before the first invocation
+	connectedFirst *bool
+}
+
+func (ci *connectInvoker) Connect(context.Context) error {
+	ci.connected = true
+	return nil
+}
+
+func (ci *connectInvoker) Invoke(context.Context, protocol.Invocation) protocol.Result {
+	if ci.connectedFirst == nil {
+		connected := ci.connected
+		ci.connectedFirst = &connected
+	}
+	return &protocol.RPCResult{}
+}
+
+func TestStaticDirWarmUp(t *testing.T) {
+	invokers := []protocol.Invoker{}
+	connectInvokers := []*connectInvoker{}
+	for i := 0; i < 3; i++ {
+		url, _ := common.NewURL(fmt.Sprintf("dubbo://192.168.1.%v:20000/com.ikurento.user.UserProvider", i),
+			common.WithParamsValue(constant.WEIGHT_KEY, fmt.Sprint(100*(i+1))))
+		invoker := &connectInvoker{BaseInvoker: *protocol.NewBaseInvoker(url)}
+		invokers = append(invokers, invoker)
+		connectInvokers = append(connectInvokers, invoker)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	staticDir := NewDirectory(invokers)
+	// the invokers of the highest weights are connected only
+	assert.NoError(t, staticDir.WarmUp(ctx, 2))
+	assert.False(t, connectInvokers[0].connected)
+	assert.True(t, connectInvokers[1].connected)
+	assert.True(t, connectInvokers[2].connected)
+
+	assert.NoError(t, staticDir.WarmUp(ctx, 0))
+	for _, invoker := range staticDir.List(&invocation.RPCInvocation{}) {
+		assert.NoError(t, invoker.Invoke(ctx, &invocation.RPCInvocation{}).Error())
+	}
+	for _, invoker := range connectInvokers {
+		assert.True(t, *invoker.connectedFirst)
+	}
+}
+
+func TestStaticDirDiagnostic(t *testing.T) {
+	url, _ := common.NewURL("dubbo://192.168.1.1:20000/com.ikurento.user.OrderProvider")
+	serviceKey := diagnostic.ServiceKey(url)
+
+	// the directories recreated on the refreshes aren't registered
+	dir := NewDirectory([]protocol.Invoker{protocol.NewBaseInvoker(url)})
+	_, err := diagnostic.Dump(serviceKey)
+	assert.Error(t, err)
+	dir.Destroy()
+
+	registered := NewRegisteredDirectory([]protocol.Invoker{protocol.NewBaseInvoker(url)})
+	_, err = diagnostic.Dump(serviceKey)
+	assert.NoError(t, err)
+	registered.Destroy()
+	_, err = diagnostic.Dump(serviceKey)
+	assert.Error(t, err)
+}
@@ -0,0 +1,129 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consistenthashing
+
+import (
+	"crypto/md5"
+	"encoding/binary"
+	"math/bits"
+	"sync"
+)
+
+import (
+	"github.com/cespare/xxhash/v2"
+)
+
+const (
+	// HashFunctionMD5 is the default hash function, which is compatible with dubbo java
+	HashFunctionMD5 = "md5"
+	// HashFunctionMurmur3 is the 32-bit murmur3 hash function
+	HashFunctionMurmur3 = "murmur3"
+	// HashFunctionXXHash is the 64-bit xxhash hash function
+	HashFunctionXXHash = "xxhash"
+)
+
+// HashFunc maps the key into one or more positions on the hash ring.
+// The first position is used to select the invoker for the key, and all of them are
+// taken up by the virtual nodes of the invoker when the key is the one of a virtual node.
+// The positions of md5 are used instead for the keys it returns no position for.
+type HashFunc func(key []byte) []uint32
+
+var (
+	hashFunctionsLock sync.RWMutex
+	hashFunctions     = map[string]HashFunc{
+		HashFunctionMD5:     md5Hash,
+		HashFunctionMurmur3: murmur3Hash,
+		HashFunctionXXHash:  xxHash,
+	}
+)
+
+// SetHashFunction registers the hash function which is selected by the hash.function param with @name
+func SetHashFunction(name string, function HashFunc) {
+	hashFunctionsLock.Lock()
+	defer hashFunctionsLock.Unlock()
+	hashFunctions[name] = function
+}
+
+// GetHashFunction returns the hash function registered with @name
+func GetHashFunction(name string) (HashFunc, bool) {
+	hashFunctionsLock.RLock()
+	defer hashFunctionsLock.RUnlock()
+	function, ok := hashFunctions[name]
+	return function, ok
+}
+
+// md5Hash splits the md5 digest into four positions
+func md5Hash(key []byte) []uint32 {
+	digest := md5.Sum(key)
+	positions := make([]uint32, 4)
+	for i := range positions {
+		positions[i] = (uint32(digest[3+i*4]&0xFF) << 24) | (uint32(digest[2+i*4]&0xFF) << 16) |
+			(uint32(digest[1+i*4]&0xFF) << 8) | uint32(digest[i*4]&0xFF)&0xFFFFFFF
+	}
+	return positions
+}
+
+// xxHash splits the 64-bit xxhash digest into two positions
+func xxHash(key []byte) []uint32 {
+	digest := xxhash.Sum64(key)
+	return []uint32{uint32(digest), uint32(digest >> 32)}
+}
+
+// murmur3Hash is the x86 32-bit murmur3 hash with seed 0
+func murmur3Hash(key []byte) []uint32 {
+	const (
+		c1 = 0xcc9e2d51
+		c2 = 0x1b873593
+	)
+	var h uint32
+	blocks := len(key) / 4
+	for i := 0; i < blocks; i++ {
+		k := binary.LittleEndian.Uint32(key[i*4:])
+		k *= c1
+		k = bits.RotateLeft32(k, 15)
+		k *= c2
+		h ^= k
+		h = bits.RotateLeft32(h, 13)
+		h = h*5 + 0xe6546b64
+	}
+
+	var k uint32
+	tail := key[blocks*4:]
+	switch len(tail) {
+	case 3:
+		k ^= uint32(tail[2]) << 16
+		fallthrough
+	case 2:
+		k ^= uint32(tail[1]) << 8
+		fallthrough
+	case 1:
+		k ^= uint32(tail[0])
+		k *= c1
+		k = bits.RotateLeft32(k, 15)
+		k *= c2
+		h ^= k
+	}
+
+	h ^= uint32(len(key))
+	h ^= h >> 16
+	h *= 0x85ebca6b
+	h ^= h >> 13
+	h *= 0xc2b2ae35
+	h ^= h >> 16
+	return []uint32{h}
+}
@@ -35,6 +35,12 @@ const (
 	HashNodes = "hash.nodes"
 	// HashArguments key of hash arguments in url
 	HashArguments = "hash.arguments"
+	// HashAttachment key of the invocation attachment whose value is used as hash key,
+	// the hash arguments are used if the attachment is absent
+	HashAttachment = "hash.attachment"
+	// HashFunction key of the name of the hash function building the hash ring, md5 by default,
+	// the consumers sharing the ring must select the same one
+	HashFunction = "hash.function"
 )
 
 var (
@@ -72,7 +78,7 @@ func (lb *loadBalance) Select(invokers []protocol.Invoker, invocation protocol.I
 	}
 	hashCode := crc32.ChecksumIEEE(bs)
 	selector, ok := selectors[key]
-	if !ok || selector.hashCode != hashCode {
+	if !ok || selector.hashCode != hashCode || selector.hashFunctionName != getHashFunctionName(invokers[0].GetURL(), methodName) {
 		selectors[key] = newSelector(invokers, methodName, hashCode)
 		selector = selectors[key]
 	}
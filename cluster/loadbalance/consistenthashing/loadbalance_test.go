@@ -23,6 +23,7 @@ import (
 )
 
 import (
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -120,3 +121,96 @@ func (s *consistentHashLoadBalanceSuite) TestSelect() {
 	invoker = s.lb.Select(s.invokers, invocation.NewRPCInvocation("echo", args, nil))
 	s.Equal(fmt.Sprintf("%s:%d", ip, port8080), invoker.GetURL().Location)
 }
+
+func TestConsistentHashLoadBalanceSelectByAttachment(t *testing.T) {
+	var invokers []protocol.Invoker
+	for _, port := range []int{8080, 8081, 8082, 8083} {
+		url, err := common.NewURL(fmt.Sprintf("dubbo://%s:%d/org.apache.demo.TenantService?hash.attachment=tenantId", ip, port))
+		assert.NoError(t, err)
+		invokers = append(invokers, protocol.NewBaseInvoker(url))
+	}
+	lb := newLoadBalance()
+
+	// the requests of the same tenant always hit the same invoker whatever the arguments are
+	for _, tenant := range []string{"tenant-a", "tenant-b", "tenant-c"} {
+		expected := lb.Select(invokers, invocation.NewRPCInvocation("getUser",
+			[]interface{}{"arg"}, map[string]interface{}{"tenantId": tenant}))
+		for i := 0; i < 20; i++ {
+			invoker := lb.Select(invokers, invocation.NewRPCInvocation("getUser",
+				[]interface{}{fmt.Sprintf("arg%d", i)}, map[string]interface{}{"tenantId": tenant}))
+			assert.Equal(t, expected.GetURL().Location, invoker.GetURL().Location)
+		}
+	}
+
+	// fall back to argument 0 if the attachment is absent
+	for i := 0; i < 20; i++ {
+		arg := fmt.Sprintf("arg%d", i)
+		byArgument := lb.Select(invokers, invocation.NewRPCInvocation("getUser", []interface{}{arg}, nil))
+		byAttachment := lb.Select(invokers, invocation.NewRPCInvocation("getUser",
+			[]interface{}{"other"}, map[string]interface{}{"tenantId": arg}))
+		assert.Equal(t, byArgument.GetURL().Location, byAttachment.GetURL().Location)
+	}
+}
+
+func TestConsistentHashSelectorHashFunction(t *testing.T) {
+	newInvokers := func(function string) []protocol.Invoker {
+		var invokers []protocol.Invoker
+		for _, port := range []int{8080, 8081, 8082, 8083} {
+			url, err := common.NewURL(fmt.Sprintf("dubbo://%s:%d/org.apache.demo.HelloService?hash.function=%s", ip, port, function))
+			assert.NoError(t, err)
+			invokers = append(invokers, protocol.NewBaseInvoker(url))
+		}
+		return invokers
+	}
+
+	rings := make(map[string]*selector)
+	for _, function := range []string{HashFunctionMD5, HashFunctionMurmur3, HashFunctionXXHash} {
+		// the rings built with the same function on both ends map the keys identically
+		ring, peerRing := newSelector(newInvokers(function), "echo", 0), newSelector(newInvokers(function), "echo", 0)
+		assert.Equal(t, function, ring.hashFunctionName)
+		assert.Len(t, ring.keys, 160*4)
+		assert.Equal(t, ring.keys, peerRing.keys)
+		for i := 0; i < 100; i++ {
+			inv := invocation.NewRPCInvocation("echo", []interface{}{fmt.Sprintf("key%d", i)}, nil)
+			assert.Equal(t, ring.Select(inv).GetURL().Location, peerRing.Select(inv).GetURL().Location)
+		}
+		rings[function] = ring
+	}
+	assert.NotEqual(t, rings[HashFunctionMD5].keys, rings[HashFunctionMurmur3].keys)
+	assert.NotEqual(t, rings[HashFunctionMD5].keys, rings[HashFunctionXXHash].keys)
+	assert.NotEqual(t, rings[HashFunctionMurmur3].keys, rings[HashFunctionXXHash].keys)
+
+	// the unknown function falls back to md5
+	ring := newSelector(newInvokers("unknown"), "echo", 0)
+	assert.Equal(t, rings[HashFunctionMD5].keys, ring.keys)
+
+	// and so does the one returning no position, instead of never filling the ring
+	SetHashFunction("empty", func([]byte) []uint32 { return nil })
+	ring = newSelector(newInvokers("empty"), "echo", 0)
+	assert.Equal(t, rings[HashFunctionMD5].keys, ring.keys)
+	assert.NotPanics(t, func() { ring.Select(invocation.NewRPCInvocation("echo", []interface{}{"key"}, nil)) })
+
+	// the keys the function returns no position for are placed by md5
+	SetHashFunction("partial", func(key []byte) []uint32 {
+		if string(key) == "key" {
+			return []uint32{}
+		}
+		return murmur3Hash(key)
+	})
+	ring = newSelector(newInvokers("partial"), "echo", 0)
+	assert.Equal(t, rings[HashFunctionMurmur3].keys, ring.keys)
+	assert.NotPanics(t, func() { ring.Select(invocation.NewRPCInvocation("echo", []interface{}{"key"}, nil)) })
+
+	// the ring is rebuilt once the function changes
+	lb := newLoadBalance()
+	inv := invocation.NewRPCInvocation("echo", []interface{}{"key"}, nil)
+	lb.Select(newInvokers(HashFunctionMD5), inv)
+	lb.Select(newInvokers(HashFunctionXXHash), inv)
+	assert.Equal(t, HashFunctionXXHash, selectors["org.apache.demo.HelloService.echo"].hashFunctionName)
+}
+
+func TestMurmur3Hash(t *testing.T) {
+	assert.Equal(t, []uint32{0}, murmur3Hash([]byte("")))
+	assert.Equal(t, []uint32{0x248bfa47}, murmur3Hash([]byte("hello")))
+	assert.Equal(t, []uint32{0x2e4ff723}, murmur3Hash([]byte("The quick brown fox jumps over the lazy dog")))
+}
@@ -18,7 +18,6 @@
 package consistenthashing
 
 import (
-	"crypto/md5"
 	"fmt"
 	"sort"
 	"strconv"
@@ -30,6 +29,8 @@ import (
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 )
 
@@ -40,6 +41,10 @@ type selector struct {
 	virtualInvokers map[uint32]protocol.Invoker
 	keys            gxsort.Uint32Slice
 	argumentIndex   []int
+	attachmentKey   string
+	// the name of the hash function configured, which the selector is rebuilt once it changes
+	hashFunctionName string
+	hashFunction     HashFunc
 }
 
 func newSelector(invokers []protocol.Invoker, methodName string,
@@ -50,6 +55,7 @@ func newSelector(invokers []protocol.Invoker, methodName string,
 	selector.hashCode = hashCode
 	url := invokers[0].GetURL()
 	selector.replicaNum = url.GetMethodParamIntValue(methodName, HashNodes, 160)
+	selector.attachmentKey = url.GetMethodParam(methodName, HashAttachment, url.GetParam(HashAttachment, ""))
 	indices := re.Split(url.GetMethodParam(methodName, HashArguments, "0"), -1)
 	for _, index := range indices {
 		i, err := strconv.Atoi(index)
@@ -58,15 +64,24 @@ func newSelector(invokers []protocol.Invoker, methodName string,
 		}
 		selector.argumentIndex = append(selector.argumentIndex, i)
 	}
+	selector.hashFunctionName = getHashFunctionName(url, methodName)
+	hashFunction, ok := GetHashFunction(selector.hashFunctionName)
+	if !ok {
+		logger.Warnf("The hash function %s isn't registered, %s is used instead", selector.hashFunctionName, HashFunctionMD5)
+		hashFunction = md5Hash
+	} else if len(hashFunction([]byte(selector.hashFunctionName))) == 0 {
+		logger.Warnf("The hash function %s returns no position, %s is used instead", selector.hashFunctionName, HashFunctionMD5)
+		hashFunction = md5Hash
+	}
+	selector.hashFunction = hashFunction
 	for _, invoker := range invokers {
 		u := invoker.GetURL()
 		address := u.Ip + ":" + u.Port
-		for i := 0; i < selector.replicaNum/4; i++ {
-			digest := md5.Sum([]byte(address + strconv.Itoa(i)))
-			for j := 0; j < 4; j++ {
-				key := selector.hash(digest, j)
+		for i, nodes := 0, 0; nodes < selector.replicaNum; i++ {
+			for _, key := range selector.positions([]byte(address + strconv.Itoa(i))) {
 				selector.keys = append(selector.keys, key)
 				selector.virtualInvokers[key] = invoker
+				nodes++
 			}
 		}
 	}
@@ -76,9 +91,20 @@ func newSelector(invokers []protocol.Invoker, methodName string,
 
 // Select gets invoker based on load balancing strategy
 func (c *selector) Select(invocation protocol.Invocation) protocol.Invoker {
-	key := c.toKey(invocation.Arguments())
-	digest := md5.Sum([]byte(key))
-	return c.selectForKey(c.hash(digest, 0))
+	key := c.toKeyByAttachment(invocation)
+	if len(key) == 0 {
+		key = c.toKey(invocation.Arguments())
+	}
+	return c.selectForKey(c.positions([]byte(key))[0])
+}
+
+// positions returns the positions of @key on the ring, and the ones of md5 if the hash function returns none
+// for it, which would leave the virtual nodes unplaced and the key unselectable otherwise
+func (c *selector) positions(key []byte) []uint32 {
+	if positions := c.hashFunction(key); len(positions) > 0 {
+		return positions
+	}
+	return md5Hash(key)
 }
 
 func (c *selector) toKey(args []interface{}) string {
@@ -91,6 +117,20 @@ func (c *selector) toKey(args []interface{}) string {
 	return sb.String()
 }
 
+func (c *selector) toKeyByAttachment(invocation protocol.Invocation) string {
+	if len(c.attachmentKey) == 0 {
+		return ""
+	}
+	value := invocation.Attachment(c.attachmentKey)
+	if value == nil {
+		return ""
+	}
+	if str, ok := value.(string); ok {
+		return str
+	}
+	return fmt.Sprint(value)
+}
+
 func (c *selector) selectForKey(hash uint32) protocol.Invoker {
 	idx := sort.Search(len(c.keys), func(i int) bool {
 		return c.keys[i] >= hash
@@ -101,7 +141,7 @@ func (c *selector) selectForKey(hash uint32) protocol.Invoker {
 	return c.virtualInvokers[c.keys[idx]]
 }
 
-func (c *selector) hash(digest [16]byte, i int) uint32 {
-	return (uint32(digest[3+i*4]&0xFF) << 24) | (uint32(digest[2+i*4]&0xFF) << 16) |
-		(uint32(digest[1+i*4]&0xFF) << 8) | uint32(digest[i*4]&0xFF)&0xFFFFFFF
+// getHashFunctionName returns the name of the hash function configured for @methodName
+func getHashFunctionName(url *common.URL, methodName string) string {
+	return url.GetMethodParam(methodName, HashFunction, url.GetParam(HashFunction, HashFunctionMD5))
 }
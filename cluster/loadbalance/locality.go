@@ -0,0 +1,64 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package loadbalance
+
+import (
+	"math/rand"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+type localityLoadBalance struct {
+	delegate LoadBalance
+	locality string
+	bias     float64
+}
+
+// NewLocalityLoadBalance wraps @delegate, picking the available invokers of @locality with
+// the probability of @bias and the invokers of other localities otherwise. The invokers of
+// other localities are always selected when there is no available one in @locality.
+func NewLocalityLoadBalance(delegate LoadBalance, locality string, bias float64) LoadBalance {
+	return &localityLoadBalance{
+		delegate: delegate,
+		locality: locality,
+		bias:     bias,
+	}
+}
+
+// Select gets invoker based on the locality of the invokers
+func (lb *localityLoadBalance) Select(invokers []protocol.Invoker, invocation protocol.Invocation) protocol.Invoker {
+	local := make([]protocol.Invoker, 0, len(invokers))
+	others := make([]protocol.Invoker, 0, len(invokers))
+	for _, invoker := range invokers {
+		if invoker.IsAvailable() && invoker.GetURL().GetParam(constant.LOCALITY_KEY, "") == lb.locality {
+			local = append(local, invoker)
+		} else {
+			others = append(others, invoker)
+		}
+	}
+	if len(local) == 0 || len(others) == 0 {
+		return lb.delegate.Select(invokers, invocation)
+	}
+	if rand.Float64() < lb.bias {
+		return lb.delegate.Select(local, invocation)
+	}
+	return lb.delegate.Select(others, invocation)
+}
@@ -0,0 +1,91 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package loadbalance
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+type randomLoadBalance struct{}
+
+func (randomLoadBalance) Select(invokers []protocol.Invoker, _ protocol.Invocation) protocol.Invoker {
+	return invokers[rand.Intn(len(invokers))]
+}
+
+func newLocalityInvokers(t *testing.T, locality string, count int, offset int) []protocol.Invoker {
+	invokers := make([]protocol.Invoker, 0, count)
+	for i := 0; i < count; i++ {
+		url, err := common.NewURL(fmt.Sprintf("dubbo://192.168.1.%d:20000/com.ikurento.user.UserProvider?%s=%s",
+			offset+i, constant.LOCALITY_KEY, locality))
+		assert.NoError(t, err)
+		invokers = append(invokers, protocol.NewBaseInvoker(url))
+	}
+	return invokers
+}
+
+func TestLocalityLoadBalanceSelect(t *testing.T) {
+	local := newLocalityInvokers(t, "az-1", 2, 0)
+	invokers := append(local, newLocalityInvokers(t, "az-2", 8, 2)...)
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUser"))
+
+	for _, bias := range []float64{0, 0.3, 0.8, 1} {
+		lb := NewLocalityLoadBalance(randomLoadBalance{}, "az-1", bias)
+		selectedLocal := 0
+		total := 10000
+		for i := 0; i < total; i++ {
+			if lb.Select(invokers, inv).GetURL().GetParam(constant.LOCALITY_KEY, "") == "az-1" {
+				selectedLocal++
+			}
+		}
+		assert.True(t, math.Abs(float64(selectedLocal)/float64(total)-bias) < 0.03,
+			"bias %v, local ratio %v", bias, float64(selectedLocal)/float64(total))
+	}
+}
+
+func TestLocalityLoadBalanceSelectWithoutAvailableLocal(t *testing.T) {
+	local := newLocalityInvokers(t, "az-1", 2, 0)
+	for _, invoker := range local {
+		invoker.Destroy()
+	}
+	invokers := append(local, newLocalityInvokers(t, "az-2", 3, 2)...)
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUser"))
+
+	// the delegate is in charge of all the invokers, cross-locality selection is allowed
+	lb := NewLocalityLoadBalance(randomLoadBalance{}, "az-1", 1)
+	selectedRemote := 0
+	for i := 0; i < 1000; i++ {
+		if lb.Select(invokers, inv).GetURL().GetParam(constant.LOCALITY_KEY, "") == "az-2" {
+			selectedRemote++
+		}
+	}
+	assert.True(t, selectedRemote > 0)
+}
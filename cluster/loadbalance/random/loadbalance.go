@@ -37,6 +37,7 @@ type loadBalance struct{}
 // NewLoadBalance returns a random load balance instance.
 //
 // Set random probabilities by weight, and the request will be sent to provider randomly.
+// The weights are the effective ones returned by extension.GetLoadbalanceWeightProvider.
 func NewLoadBalance() loadbalance.LoadBalance {
 	return &loadBalance{}
 }
@@ -48,13 +49,14 @@ func (lb *loadBalance) Select(invokers []protocol.Invoker, invocation protocol.I
 	}
 	sameWeight := true
 	weights := make([]int64, length)
+	getWeight := extension.GetLoadbalanceWeightProvider()
 
-	firstWeight := loadbalance.GetWeight(invokers[0], invocation)
+	firstWeight := getWeight(invokers[0], invocation)
 	totalWeight := firstWeight
 	weights[0] = firstWeight
 
 	for i := 1; i < length; i++ {
-		weight := loadbalance.GetWeight(invokers[i], invocation)
+		weight := getWeight(invokers[i], invocation)
 		weights[i] = weight
 
 		totalWeight += weight
@@ -30,8 +30,10 @@ import (
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/loadbalance"
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
 )
@@ -121,3 +123,65 @@ func TestRandomlbSelectWarmup(t *testing.T) {
 		return selected/10000 < 0.1
 	})
 }
+
+func TestRandomlbSelectionCallback(t *testing.T) {
+	var (
+		selected   protocol.Invoker
+		candidates []protocol.Invoker
+	)
+	extension.SetLoadbalanceSelectionCallback(func(s protocol.Invoker, c []protocol.Invoker, _ protocol.Invocation) {
+		selected = s
+		candidates = c
+	})
+	defer extension.SetLoadbalanceSelectionCallback(nil)
+
+	var invokers []protocol.Invoker
+	for i := 0; i < 10; i++ {
+		u, _ := common.NewURL(fmt.Sprintf(tmpUrlFormat, i))
+		invokers = append(invokers, protocol.NewBaseInvoker(u))
+	}
+	randomlb := extension.GetLoadbalance(constant.LoadBalanceKeyRandom)
+	ivk := randomlb.Select(invokers, &invocation.RPCInvocation{})
+	assert.NotNil(t, ivk)
+	assert.Equal(t, ivk, selected)
+	assert.Equal(t, invokers, candidates)
+}
+
+func TestRandomlbSelectWeightProvider(t *testing.T) {
+	var invokers []protocol.Invoker
+	for i := 0; i < 4; i++ {
+		u, _ := common.NewURL(fmt.Sprintf(tmpUrlFormat, i))
+		invokers = append(invokers, protocol.NewBaseInvoker(u))
+	}
+	ivc := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("test"))
+
+	// the invoker failing all of its requests is down-weighted by the health score
+	unhealthy := invokers[0].GetURL()
+	for i := 0; i < 10; i++ {
+		protocol.BeginCount(unhealthy, "test")
+		protocol.EndCount(unhealthy, "test", 1, false)
+	}
+	defer protocol.CleanAllStatus()
+	extension.SetLoadbalanceWeightProvider(loadbalance.NewHealthWeightProvider(time.Second, 5))
+	defer extension.SetLoadbalanceWeightProvider(nil)
+
+	randomlb := NewLoadBalance()
+	selected := 0
+	for i := 0; i < 10000; i++ {
+		if randomlb.Select(invokers, ivc) == invokers[0] {
+			selected++
+		}
+	}
+	// the weight of the unhealthy invoker drops from 100 to 1
+	assert.Less(t, selected, 100)
+	assert.Greater(t, selected, 0)
+
+	extension.SetLoadbalanceWeightProvider(nil)
+	selected = 0
+	for i := 0; i < 10000; i++ {
+		if randomlb.Select(invokers, ivc) == invokers[0] {
+			selected++
+		}
+	}
+	assert.Greater(t, selected, 2000)
+}
@@ -0,0 +1,46 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package loadbalance
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+// SelectionCallback is notified with the invoker selected by the load balance among the candidates,
+// e.g. to record the selection skew in the telemetry. It's called on every selection so it should be fast.
+type SelectionCallback func(selected protocol.Invoker, candidates []protocol.Invoker, invocation protocol.Invocation)
+
+type callbackLoadBalance struct {
+	delegate LoadBalance
+	callback SelectionCallback
+}
+
+// NewCallbackLoadBalance wraps @delegate to notify @callback of every selection
+func NewCallbackLoadBalance(delegate LoadBalance, callback SelectionCallback) LoadBalance {
+	return &callbackLoadBalance{
+		delegate: delegate,
+		callback: callback,
+	}
+}
+
+// Select gets invoker by the delegate and notifies the callback
+func (lb *callbackLoadBalance) Select(invokers []protocol.Invoker, invocation protocol.Invocation) protocol.Invoker {
+	selected := lb.delegate.Select(invokers, invocation)
+	lb.callback(selected, invokers, invocation)
+	return selected
+}
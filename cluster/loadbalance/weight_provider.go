@@ -0,0 +1,54 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package loadbalance
+
+import (
+	"math"
+	"time"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+// WeightProvider returns the effective weight of @invoker for @invocation, which the random load balance
+// selects the invokers by. It's called for every candidate on every selection so it should be fast.
+type WeightProvider func(invoker protocol.Invoker, invocation protocol.Invocation) int64
+
+// NewHealthWeightProvider returns the WeightProvider scaling the static weight by the health score of the invoker,
+// which multiplies the success rate of the method by the ratio of @latency to its average elapsed time up to 1.
+// The score is 1 until the method completes @minRequests requests, and the invokers with the positive static
+// weight keep the weight 1 at least, so that they still get a few requests to recover.
+func NewHealthWeightProvider(latency time.Duration, minRequests int32) WeightProvider {
+	return func(invoker protocol.Invoker, invocation protocol.Invocation) int64 {
+		weight := GetWeight(invoker, invocation)
+		if weight <= 0 {
+			return weight
+		}
+		status := protocol.GetMethodStatus(invoker.GetURL(), invocation.MethodName())
+		total := status.GetTotal()
+		if total <= 0 || total < minRequests {
+			return weight
+		}
+		score := 1 - float64(status.GetFailed())/float64(total)
+		if elapsed := float64(status.GetTotalElapsed()) / float64(total); elapsed > 0 {
+			score *= math.Min(1, float64(latency.Milliseconds())/elapsed)
+		}
+		return int64(math.Max(1, math.Round(float64(weight)*score)))
+	}
+}
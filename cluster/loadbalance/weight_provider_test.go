@@ -0,0 +1,62 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package loadbalance
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+func TestHealthWeightProvider(t *testing.T) {
+	url, err := common.NewURL("dubbo://192.168.1.1:20000/com.ikurento.user.UserProvider?weight=200")
+	assert.NoError(t, err)
+	invoker := protocol.NewBaseInvoker(url)
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUser"))
+	defer protocol.CleanAllStatus()
+	provider := NewHealthWeightProvider(50*time.Millisecond, 4)
+
+	record := func(elapsed int64, succeeded bool) {
+		protocol.BeginCount(url, "GetUser")
+		protocol.EndCount(url, "GetUser", elapsed, succeeded)
+	}
+	// the static weight is kept until there are enough requests
+	record(200, false)
+	record(200, true)
+	assert.Equal(t, int64(200), provider(invoker, inv))
+
+	// half of the requests fail, and they take twice the latency on average
+	record(0, false)
+	record(0, true)
+	assert.Equal(t, int64(50), provider(invoker, inv))
+
+	// the invoker keeps the weight 1 at least
+	for i := 0; i < 100; i++ {
+		record(1000, false)
+	}
+	assert.Equal(t, int64(1), provider(invoker, inv))
+}
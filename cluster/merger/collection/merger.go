@@ -0,0 +1,101 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package collection
+
+import (
+	"reflect"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/merger"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+)
+
+func init() {
+	extension.SetMerger(merger.SliceMerger, &sliceMerger{})
+	extension.SetMerger(merger.MapMerger, &mapMerger{})
+}
+
+// sliceMerger concatenates the slices, or the arrays into a slice, in the order of the results
+type sliceMerger struct{}
+
+// Merge returns the slice of the elements of @results
+func (m *sliceMerger) Merge(results []interface{}) (interface{}, error) {
+	values, err := valuesOf(results, reflect.Slice, reflect.Array)
+	if err != nil || len(values) == 0 {
+		return nil, err
+	}
+	merged := reflect.MakeSlice(reflect.SliceOf(values[0].Type().Elem()), 0, 0)
+	for _, value := range values {
+		for i := 0; i < value.Len(); i++ {
+			merged = reflect.Append(merged, value.Index(i))
+		}
+	}
+	return merged.Interface(), nil
+}
+
+// mapMerger puts the entries of the maps together, and the latter results win for the same keys
+type mapMerger struct{}
+
+// Merge returns the map of the entries of @results
+func (m *mapMerger) Merge(results []interface{}) (interface{}, error) {
+	values, err := valuesOf(results, reflect.Map)
+	if err != nil || len(values) == 0 {
+		return nil, err
+	}
+	merged := reflect.MakeMapWithSize(values[0].Type(), values[0].Len())
+	for _, value := range values {
+		iter := value.MapRange()
+		for iter.Next() {
+			merged.SetMapIndex(iter.Key(), iter.Value())
+		}
+	}
+	return merged.Interface(), nil
+}
+
+// valuesOf returns the values of the non-nil results, which must be of the same type of @kinds
+func valuesOf(results []interface{}, kinds ...reflect.Kind) ([]reflect.Value, error) {
+	values := make([]reflect.Value, 0, len(results))
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		value := reflect.ValueOf(result)
+		if !isKindOf(value.Kind(), kinds) {
+			return nil, perrors.Errorf("the result of type %s can not be merged as %v", value.Type(), kinds)
+		}
+		if len(values) > 0 && value.Type() != values[0].Type() {
+			return nil, perrors.Errorf("the results of the types %s and %s can not be merged", values[0].Type(), value.Type())
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+func isKindOf(kind reflect.Kind, kinds []reflect.Kind) bool {
+	for _, k := range kinds {
+		if kind == k {
+			return true
+		}
+	}
+	return false
+}
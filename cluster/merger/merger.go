@@ -0,0 +1,48 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package merger
+
+import (
+	"reflect"
+)
+
+const (
+	// SliceMerger is the name of the merger concatenating the slices
+	SliceMerger = "slice"
+	// MapMerger is the name of the merger putting the entries of the maps together
+	MapMerger = "map"
+)
+
+// Merger
+// Extension - Merger, it combines the results of the groups invoked by the mergeable cluster
+type Merger interface {
+	// Merge merges the values of @results, which are of the same type
+	Merge(results []interface{}) (interface{}, error)
+}
+
+// DefaultName returns the name of the merger for the results of @kind, and empty if none of them supports it
+func DefaultName(kind reflect.Kind) string {
+	switch kind {
+	case reflect.Slice:
+		return SliceMerger
+	case reflect.Map:
+		return MapMerger
+	default:
+		return ""
+	}
+}
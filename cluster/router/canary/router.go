@@ -0,0 +1,179 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canary
+
+import (
+	"sync/atomic"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+
+	"gopkg.in/yaml.v2"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/router"
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/config"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/registry"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+const name = "canary"
+
+// Rule is the canary rule published to the config center with the key <application>.canary-router in yaml,
+// e.g. the rule {key: canary, value: "true", percentage: 10} routes 10 percent of the requests to the providers
+// labeled with canary=true, and the others to the rest ones.
+type Rule struct {
+	// Key is the key of the metadata label of the canary providers
+	Key string `yaml:"key"`
+	// Value is the value of the metadata label of the canary providers, it's "true" by default
+	Value string `yaml:"value"`
+	// Percentage is the percentage of the requests routed to the canary providers, from 0 to 100
+	Percentage int `yaml:"percentage"`
+}
+
+// Router routes the configured percentage of the requests to the canary providers labeled by the rule,
+// and the other requests avoid them. All of the providers are used if there isn't any of the group chosen.
+type Router struct {
+	// requests counts the routed requests to spread the canary ones evenly
+	requests uint64
+	rule     atomic.Value
+}
+
+// NewCanaryRouter creates the canary router listening to the rule in the config center
+func NewCanaryRouter() (router.PriorityRouter, error) {
+	rootConfig := config.GetRootConfig()
+	if rootConfig.ConfigCenter == nil || rootConfig.ConfigCenter.DynamicConfiguration == nil {
+		logger.Infof("Config center does not start, the canary router is disabled")
+		return nil, nil
+	}
+	r := newRouter()
+	dynamicConfiguration := rootConfig.ConfigCenter.DynamicConfiguration
+	key := rootConfig.Application.Name + constant.CanaryRouterRuleSuffix
+	dynamicConfiguration.AddListener(key, r, config_center.WithGroup(rootConfig.ConfigCenter.Group))
+
+	value, err := dynamicConfiguration.GetProperties(key, config_center.WithGroup(rootConfig.ConfigCenter.Group))
+	if err != nil {
+		// the rule may not be published now
+		logger.Warnf("Can not get canary rule for key=%s, error=%v", key, err)
+		return r, nil
+	}
+	if err = r.setRule(value); err != nil {
+		logger.Warnf("Parse canary rule failed, error=%v", err)
+	}
+	return r, nil
+}
+
+func newRouter() *Router {
+	r := &Router{}
+	r.rule.Store((*Rule)(nil))
+	return r
+}
+
+// Process updates the rule once it changes in the config center, and the canary routing stops once it's removed
+func (r *Router) Process(event *config_center.ConfigChangeEvent) {
+	logger.Debugf("Canary router process event:\n%+v", event)
+	value, _ := event.Value.(string)
+	if event.ConfigType == remoting.EventTypeDel {
+		value = ""
+	}
+	if err := r.setRule(value); err != nil {
+		logger.Warnf("Parse canary rule failed, error=%v", err)
+	}
+}
+
+func (r *Router) setRule(value string) error {
+	if value == "" {
+		r.rule.Store((*Rule)(nil))
+		return nil
+	}
+	rule := &Rule{}
+	if err := yaml.Unmarshal([]byte(value), rule); err != nil {
+		return perrors.WithStack(err)
+	}
+	if rule.Key == "" {
+		return perrors.Errorf("the label key of the canary rule %s is empty", value)
+	}
+	if rule.Percentage < 0 || rule.Percentage > 100 {
+		return perrors.Errorf("the percentage %d of the canary rule is out of [0, 100]", rule.Percentage)
+	}
+	if rule.Value == "" {
+		rule.Value = "true"
+	}
+	registry.RegisterInstanceLabelKey(rule.Key)
+	r.rule.Store(rule)
+	return nil
+}
+
+// Route routes the request to either the canary providers or the other ones
+func (r *Router) Route(invokers []protocol.Invoker, _ *common.URL, _ protocol.Invocation) []protocol.Invoker {
+	rule := r.rule.Load().(*Rule)
+	if rule == nil || len(invokers) == 0 {
+		return invokers
+	}
+	canaries := make([]protocol.Invoker, 0, len(invokers))
+	others := make([]protocol.Invoker, 0, len(invokers))
+	for _, invoker := range invokers {
+		if invoker.GetURL().GetParam(rule.Key, "") == rule.Value {
+			canaries = append(canaries, invoker)
+		} else {
+			others = append(others, invoker)
+		}
+	}
+
+	// the request is a canary one if it reaches the next percent of the requests
+	n := atomic.AddUint64(&r.requests, 1)
+	percentage := uint64(rule.Percentage)
+	result := others
+	if n*percentage/100 != (n-1)*percentage/100 {
+		result = canaries
+	}
+	if len(result) == 0 {
+		return invokers
+	}
+	return result
+}
+
+// DumpRule returns the rule in effect, which is nil if the canary routing stops
+func (r *Router) DumpRule() interface{} {
+	if rule := r.rule.Load().(*Rule); rule != nil {
+		return rule
+	}
+	return nil
+}
+
+// Name get name of the canary router
+func (r *Router) Name() string {
+	return name
+}
+
+// URL Return URL in router
+func (r *Router) URL() *common.URL {
+	return nil
+}
+
+// Priority get Router priority level
+func (r *Router) Priority() int64 {
+	return router.CanaryRouterPriority
+}
@@ -0,0 +1,133 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canary
+
+import (
+	"fmt"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/registry"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+func newInvokers(t *testing.T) []protocol.Invoker {
+	var invokers []protocol.Invoker
+	for i := 0; i < 6; i++ {
+		rawURL := fmt.Sprintf("dubbo://192.168.0.%d:20000/com.ikurento.user.UserProvider", i)
+		// the first two providers are the canary ones
+		if i < 2 {
+			rawURL += "?canary=true"
+		}
+		url, err := common.NewURL(rawURL)
+		assert.NoError(t, err)
+		invokers = append(invokers, protocol.NewBaseInvoker(url))
+	}
+	return invokers
+}
+
+func routeCanaries(r *Router, invokers []protocol.Invoker, requests int) (int, bool) {
+	canaries, mixed := 0, false
+	for i := 0; i < requests; i++ {
+		routed := r.Route(invokers, nil, nil)
+		labeled := 0
+		for _, invoker := range routed {
+			if invoker.GetURL().GetParam("canary", "") == "true" {
+				labeled++
+			}
+		}
+		if labeled == len(routed) {
+			canaries++
+		} else if labeled > 0 {
+			mixed = true
+		}
+	}
+	return canaries, mixed
+}
+
+func TestCanaryRouterRoute(t *testing.T) {
+	invokers := newInvokers(t)
+	r := newRouter()
+	// all of the providers are used without the rule
+	assert.Equal(t, invokers, r.Route(invokers, nil, nil))
+
+	r.Process(&config_center.ConfigChangeEvent{
+		Value:      "key: canary\npercentage: 20",
+		ConfigType: remoting.EventTypeUpdate,
+	})
+	canaries, mixed := routeCanaries(r, invokers, 1000)
+	assert.Equal(t, 200, canaries)
+	assert.False(t, mixed)
+
+	// the percentage is dialed up
+	r.Process(&config_center.ConfigChangeEvent{
+		Value:      "key: canary\nvalue: \"true\"\npercentage: 50",
+		ConfigType: remoting.EventTypeUpdate,
+	})
+	canaries, mixed = routeCanaries(r, invokers, 1000)
+	assert.Equal(t, 500, canaries)
+	assert.False(t, mixed)
+
+	// the invalid rule is ignored
+	r.Process(&config_center.ConfigChangeEvent{Value: "key: canary\npercentage: 101", ConfigType: remoting.EventTypeUpdate})
+	canaries, _ = routeCanaries(r, invokers, 100)
+	assert.Equal(t, 50, canaries)
+
+	// the canary routing stops once the rule is removed
+	r.Process(&config_center.ConfigChangeEvent{ConfigType: remoting.EventTypeDel})
+	assert.Equal(t, invokers, r.Route(invokers, nil, nil))
+}
+
+func TestCanaryRouterRouteWithoutCanaries(t *testing.T) {
+	invokers := newInvokers(t)[2:]
+	r := newRouter()
+	r.Process(&config_center.ConfigChangeEvent{Value: "key: canary\npercentage: 100", ConfigType: remoting.EventTypeUpdate})
+	assert.Equal(t, invokers, r.Route(invokers, nil, nil))
+}
+
+func TestCanaryRouterInstanceLabel(t *testing.T) {
+	r := newRouter()
+	r.Process(&config_center.ConfigChangeEvent{Value: "key: gray\npercentage: 100", ConfigType: remoting.EventTypeUpdate})
+
+	metadataInfo := common.NewMetadataInfWithApp("user-center")
+	metadataInfo.AddService(common.NewServiceInfo("com.ikurento.user.UserProvider", "", "", "dubbo",
+		"com.ikurento.user.UserProvider", nil))
+	instance := &registry.DefaultServiceInstance{
+		ServiceName:     "user-center",
+		Host:            "192.168.0.1",
+		Port:            20000,
+		Metadata:        map[string]string{"gray": "true", "owner": "user-team"},
+		ServiceMetadata: metadataInfo,
+	}
+	urls := instance.ToURLs()
+	assert.Len(t, urls, 1)
+	// only the label of the rule is copied from the metadata of the instance
+	assert.Equal(t, "true", urls[0].GetParam("gray", ""))
+	assert.Equal(t, "", urls[0].GetParam("owner", ""))
+
+	invokers := []protocol.Invoker{protocol.NewBaseInvoker(urls[0])}
+	assert.Equal(t, invokers, r.Route(invokers, nil, nil))
+}
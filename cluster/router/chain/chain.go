@@ -51,9 +51,12 @@ type RouterChain struct {
 
 // Route Loop routers in RouterChain and call Route method to determine the target invokers list.
 func (c *RouterChain) Route(url *common.URL, invocation protocol.Invocation) []protocol.Invoker {
+	c.mutex.RLock()
 	finalInvokers := c.invokers
+	c.mutex.RUnlock()
+	// the routers narrow the snapshot one by one, the invokers set meanwhile are routed by the next call
 	for _, r := range c.copyRouters() {
-		finalInvokers = r.Route(c.invokers, url, invocation)
+		finalInvokers = r.Route(finalInvokers, url, invocation)
 	}
 	return finalInvokers
 }
@@ -80,6 +83,11 @@ func (c *RouterChain) SetInvokers(invokers []protocol.Invoker) {
 	c.mutex.Unlock()
 }
 
+// Snapshot returns the copies of the routers and the received invokers of the chain
+func (c *RouterChain) Snapshot() ([]router.PriorityRouter, []protocol.Invoker) {
+	return c.copyRouters(), c.copyInvokers()
+}
+
 // copyRouters make a snapshot copy from RouterChain's router list.
 func (c *RouterChain) copyRouters() []router.PriorityRouter {
 	c.mutex.RLock()
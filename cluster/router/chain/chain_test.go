@@ -0,0 +1,79 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"fmt"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/router"
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+// portRouter keeps the invokers whose ports pass @keep and records the ones it routes
+type portRouter struct {
+	priority int64
+	keep     func(port int64) bool
+	routed   []protocol.Invoker
+}
+
+func (r *portRouter) Route(invokers []protocol.Invoker, _ *common.URL, _ protocol.Invocation) []protocol.Invoker {
+	r.routed = invokers
+	result := make([]protocol.Invoker, 0, len(invokers))
+	for _, invoker := range invokers {
+		if r.keep(invoker.GetURL().GetParamInt("port", 0)) {
+			result = append(result, invoker)
+		}
+	}
+	return result
+}
+
+func (r *portRouter) URL() *common.URL {
+	return nil
+}
+
+func (r *portRouter) Priority() int64 {
+	return r.priority
+}
+
+func TestRouterChainRoute(t *testing.T) {
+	invokers := make([]protocol.Invoker, 0, 6)
+	for port := 0; port < 6; port++ {
+		url, err := common.NewURL(fmt.Sprintf("dubbo://192.168.0.1:%d/com.ikurento.user.UserProvider?port=%d", 20000+port, port))
+		assert.NoError(t, err)
+		invokers = append(invokers, protocol.NewBaseInvoker(url))
+	}
+	even := &portRouter{priority: router.CanaryRouterPriority, keep: func(port int64) bool { return port%2 == 0 }}
+	first := &portRouter{priority: router.ColorRouterPriority, keep: func(port int64) bool { return port < 3 }}
+	chain := &RouterChain{}
+	chain.AddRouters([]router.PriorityRouter{even, first})
+	chain.SetInvokers(invokers)
+
+	// the routers route the invokers left by the ones of the lower priorities
+	routed := chain.Route(nil, nil)
+	assert.Equal(t, []protocol.Invoker{invokers[0], invokers[2]}, routed)
+	assert.Equal(t, invokers, first.routed)
+	assert.Equal(t, invokers[:3], even.routed)
+}
@@ -0,0 +1,96 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package color
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/router"
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+const name = "color"
+
+// Router pins the requests carrying the color attachment, e.g. color=green, to the providers of the color,
+// which is published by the providers with the color of their application config.
+// The policy color.fallback of the reference decides the providers once none of them is of the color:
+// any falls back to all of the providers, uncolored falls back to the ones without any color,
+// and none leaves no provider so that the request fails.
+// The requests without the color attachment aren't routed.
+type Router struct{}
+
+// NewColorRouter creates the color router
+func NewColorRouter() (router.PriorityRouter, error) {
+	return &Router{}, nil
+}
+
+// Route picks the providers of the color of the request, or the fallback ones if there isn't any
+func (r *Router) Route(invokers []protocol.Invoker, url *common.URL, invocation protocol.Invocation) []protocol.Invoker {
+	if invocation == nil || len(invokers) == 0 {
+		return invokers
+	}
+	color := invocation.AttachmentsByKey(constant.COLOR_KEY, "")
+	if color == "" {
+		return invokers
+	}
+	colored := filterByColor(invokers, color)
+	if len(colored) > 0 {
+		return colored
+	}
+
+	fallback := constant.COLOR_FALLBACK_ANY
+	if url != nil {
+		fallback = url.GetParam(constant.COLOR_FALLBACK_KEY, constant.COLOR_FALLBACK_ANY)
+	}
+	switch fallback {
+	case constant.COLOR_FALLBACK_UNCOLORED:
+		return filterByColor(invokers, "")
+	case constant.COLOR_FALLBACK_NONE:
+		return []protocol.Invoker{}
+	case constant.COLOR_FALLBACK_ANY:
+	default:
+		logger.Warnf("Unknown color fallback policy %s, all of the providers are used", fallback)
+	}
+	return invokers
+}
+
+func filterByColor(invokers []protocol.Invoker, color string) []protocol.Invoker {
+	result := make([]protocol.Invoker, 0, len(invokers))
+	for _, invoker := range invokers {
+		if invoker.GetURL().GetParam(constant.COLOR_KEY, "") == color {
+			result = append(result, invoker)
+		}
+	}
+	return result
+}
+
+// Name get name of the color router
+func (r *Router) Name() string {
+	return name
+}
+
+// URL Return URL in router
+func (r *Router) URL() *common.URL {
+	return nil
+}
+
+// Priority get Router priority level
+func (r *Router) Priority() int64 {
+	return router.ColorRouterPriority
+}
@@ -0,0 +1,98 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package color
+
+import (
+	"fmt"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/registry"
+)
+
+// newInvokers creates the invokers from the instances registered with @colors
+func newInvokers(colors ...string) []protocol.Invoker {
+	metadataInfo := common.NewMetadataInfWithApp("user-center")
+	metadataInfo.AddService(common.NewServiceInfo("com.ikurento.user.UserProvider", "", "", "dubbo",
+		"com.ikurento.user.UserProvider", nil))
+	invokers := make([]protocol.Invoker, 0, len(colors))
+	for i, color := range colors {
+		metadata := map[string]string{}
+		if color != "" {
+			metadata[constant.COLOR_KEY] = color
+		}
+		instance := &registry.DefaultServiceInstance{
+			ServiceName:     "user-center",
+			Host:            fmt.Sprintf("192.168.0.%d", i),
+			Port:            20000,
+			Metadata:        metadata,
+			ServiceMetadata: metadataInfo,
+		}
+		for _, url := range instance.ToURLs() {
+			invokers = append(invokers, protocol.NewBaseInvoker(url))
+		}
+	}
+	return invokers
+}
+
+func colorsOf(invokers []protocol.Invoker) []string {
+	colors := make([]string, 0, len(invokers))
+	for _, invoker := range invokers {
+		colors = append(colors, invoker.GetURL().GetParam(constant.COLOR_KEY, ""))
+	}
+	return colors
+}
+
+func newInvocation(color string) protocol.Invocation {
+	attachments := map[string]interface{}{}
+	if color != "" {
+		attachments[constant.COLOR_KEY] = color
+	}
+	return invocation.NewRPCInvocation("GetUser", nil, attachments)
+}
+
+func TestColorRouterRoute(t *testing.T) {
+	invokers := newInvokers("blue", "green", "blue", "green", "")
+	r, err := NewColorRouter()
+	assert.NoError(t, err)
+	consumerURL, err := common.NewURL("consumer://127.0.0.1/com.ikurento.user.UserProvider")
+	assert.NoError(t, err)
+
+	// the color attachment pins the requests to the providers of the color
+	assert.Equal(t, []string{"green", "green"}, colorsOf(r.Route(invokers, consumerURL, newInvocation("green"))))
+	assert.Equal(t, []string{"blue", "blue"}, colorsOf(r.Route(invokers, consumerURL, newInvocation("blue"))))
+	// the requests without the color aren't routed
+	assert.Equal(t, invokers, r.Route(invokers, consumerURL, newInvocation("")))
+
+	// the fallbacks once none of the providers is of the color
+	assert.Equal(t, invokers, r.Route(invokers, consumerURL, newInvocation("red")))
+	consumerURL.SetParam(constant.COLOR_FALLBACK_KEY, constant.COLOR_FALLBACK_UNCOLORED)
+	assert.Equal(t, []string{""}, colorsOf(r.Route(invokers, consumerURL, newInvocation("red"))))
+	assert.Equal(t, []string{"green", "green"}, colorsOf(r.Route(invokers, consumerURL, newInvocation("green"))))
+	consumerURL.SetParam(constant.COLOR_FALLBACK_KEY, constant.COLOR_FALLBACK_NONE)
+	assert.Empty(t, r.Route(invokers, consumerURL, newInvocation("red")))
+}
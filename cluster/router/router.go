@@ -26,6 +26,16 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 )
 
+// The priorities of the builtin routers, which are chained from the lowest priority and each one routes the
+// invokers left by the former ones. The mesh routes keep the default priority 0 and run first, the color router
+// pins the requests to their colors before the version router prefers the upgraded providers among them, and
+// the canary router splits the requests between the providers left at last.
+const (
+	ColorRouterPriority   int64 = 100
+	VersionRouterPriority int64 = 200
+	CanaryRouterPriority  int64 = 300
+)
+
 // PriorityRouterFactory creates creates priority router with url
 type PriorityRouterFactory interface {
 	// NewPriorityRouter creates router instance with URL
@@ -45,6 +55,12 @@ type PriorityRouter interface {
 	Priority() int64
 }
 
+// RuleDumper is implemented by the routers reporting the rules in effect, e.g. for the diagnostics
+type RuleDumper interface {
+	// DumpRule returns the rule in effect which is marshalled into json, or nil if there isn't any
+	DumpRule() interface{}
+}
+
 // Poolable caches address pool and address metadata for a router instance which will be used later in Router's Route.
 type Poolable interface {
 	// Pool created address pool and address metadata from the invokers.
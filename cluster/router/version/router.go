@@ -0,0 +1,130 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package version
+
+import (
+	"strconv"
+	"strings"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/router"
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+const name = "version"
+
+// Router prefers the providers at or above the minimum app version configured by prefer.min.app.version of the
+// reference during the rolling upgrades, which is published by the providers with the version of their
+// application config as provider.app.version, rather than app.version filled by the one of the consumer
+// once the urls of the providers without it are merged. All of the providers are used once none of the preferred ones is available,
+// and the references without the minimum version aren't routed.
+type Router struct{}
+
+// NewVersionRouter creates the version router
+func NewVersionRouter() (router.PriorityRouter, error) {
+	return &Router{}, nil
+}
+
+// Route picks the available providers of the preferred versions, or all of them if there isn't any
+func (r *Router) Route(invokers []protocol.Invoker, url *common.URL, _ protocol.Invocation) []protocol.Invoker {
+	if url == nil || len(invokers) == 0 {
+		return invokers
+	}
+	minVersion := url.GetParam(constant.PREFER_MIN_APP_VERSION_KEY, "")
+	if minVersion == "" {
+		return invokers
+	}
+	preferred := make([]protocol.Invoker, 0, len(invokers))
+	for _, invoker := range invokers {
+		version := invoker.GetURL().GetParam(constant.PROVIDER_APP_VERSION_KEY, "")
+		if version != "" && compareVersions(version, minVersion) >= 0 && invoker.IsAvailable() {
+			preferred = append(preferred, invoker)
+		}
+	}
+	if len(preferred) == 0 {
+		return invokers
+	}
+	return preferred
+}
+
+// compareVersions compares the dot separated versions segment by segment, e.g. 2.10.0 is newer than 2.9.1,
+// and the missing segments are zeros, e.g. 2.1 is the same as 2.1.0. The numbers of the segments are compared
+// numerically, and the ones with the suffixes, e.g. 0-SNAPSHOT, are older than the ones without them.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		if c := compareSegments(segmentAt(as, i), segmentAt(bs, i)); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+func segmentAt(segments []string, i int) string {
+	if i < len(segments) {
+		return segments[i]
+	}
+	return "0"
+}
+
+func compareSegments(a, b string) int {
+	an, asuffix := splitSegment(a)
+	bn, bsuffix := splitSegment(b)
+	switch {
+	case an != bn:
+		if an < bn {
+			return -1
+		}
+		return 1
+	case asuffix == bsuffix:
+		return 0
+	case asuffix == "":
+		return 1
+	case bsuffix == "":
+		return -1
+	}
+	return strings.Compare(asuffix, bsuffix)
+}
+
+// splitSegment splits the segment into the leading number and the rest
+func splitSegment(segment string) (int, string) {
+	end := 0
+	for end < len(segment) && segment[end] >= '0' && segment[end] <= '9' {
+		end++
+	}
+	number, _ := strconv.Atoi(segment[:end])
+	return number, segment[end:]
+}
+
+// Name get name of the version router
+func (r *Router) Name() string {
+	return name
+}
+
+// URL Return URL in router
+func (r *Router) URL() *common.URL {
+	return nil
+}
+
+// Priority get Router priority level
+func (r *Router) Priority() int64 {
+	return router.VersionRouterPriority
+}
@@ -0,0 +1,107 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package version
+
+import (
+	"fmt"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/registry"
+)
+
+// newInvokers creates the invokers from the instances registered with the app @versions, whose urls are merged
+// with the one of the reference of the app version 9.0.0
+func newInvokers(versions ...string) []protocol.Invoker {
+	referenceURL, _ := common.NewURL("consumer://127.0.0.1/com.ikurento.user.UserProvider?" +
+		constant.APP_VERSION_KEY + "=9.0.0")
+	metadataInfo := common.NewMetadataInfWithApp("user-center")
+	metadataInfo.AddService(common.NewServiceInfo("com.ikurento.user.UserProvider", "", "", "dubbo",
+		"com.ikurento.user.UserProvider", nil))
+	invokers := make([]protocol.Invoker, 0, len(versions))
+	for i, version := range versions {
+		metadata := map[string]string{}
+		if version != "" {
+			metadata[constant.PROVIDER_APP_VERSION_KEY] = version
+		}
+		instance := &registry.DefaultServiceInstance{
+			ServiceName:     "user-center",
+			Host:            fmt.Sprintf("192.168.0.%d", i),
+			Port:            20000,
+			Metadata:        metadata,
+			ServiceMetadata: metadataInfo,
+		}
+		for _, url := range instance.ToURLs() {
+			invokers = append(invokers, protocol.NewBaseInvoker(common.MergeURL(url, referenceURL)))
+		}
+	}
+	return invokers
+}
+
+func versionsOf(invokers []protocol.Invoker) []string {
+	versions := make([]string, 0, len(invokers))
+	for _, invoker := range invokers {
+		versions = append(versions, invoker.GetURL().GetParam(constant.PROVIDER_APP_VERSION_KEY, ""))
+	}
+	return versions
+}
+
+func TestVersionRouterRoute(t *testing.T) {
+	invokers := newInvokers("2.0.0", "2.10.1", "1.9.9", "", "2.1.0-SNAPSHOT", "2.1")
+	r, err := NewVersionRouter()
+	assert.NoError(t, err)
+	consumerURL, err := common.NewURL("consumer://127.0.0.1/com.ikurento.user.UserProvider")
+	assert.NoError(t, err)
+
+	// the references without the minimum version aren't routed
+	assert.Equal(t, invokers, r.Route(invokers, consumerURL, nil))
+
+	// the newer providers are preferred
+	consumerURL.SetParam(constant.PREFER_MIN_APP_VERSION_KEY, "2.1.0")
+	assert.Equal(t, []string{"2.10.1", "2.1"}, versionsOf(r.Route(invokers, consumerURL, nil)))
+	consumerURL.SetParam(constant.PREFER_MIN_APP_VERSION_KEY, "2.0")
+	assert.Equal(t, []string{"2.0.0", "2.10.1", "2.1.0-SNAPSHOT", "2.1"}, versionsOf(r.Route(invokers, consumerURL, nil)))
+
+	// the provider without the version isn't preferred by the version of the consumer merged into its url
+	assert.Equal(t, "9.0.0", invokers[3].GetURL().GetParam(constant.APP_VERSION_KEY, ""))
+	consumerURL.SetParam(constant.PREFER_MIN_APP_VERSION_KEY, "2.10.0")
+	assert.Equal(t, []string{"2.10.1"}, versionsOf(r.Route(invokers, consumerURL, nil)))
+
+	// the older ones are used only once none of the newer ones is available
+	consumerURL.SetParam(constant.PREFER_MIN_APP_VERSION_KEY, "3.0.0")
+	assert.Equal(t, invokers, r.Route(invokers, consumerURL, nil))
+	consumerURL.SetParam(constant.PREFER_MIN_APP_VERSION_KEY, "2.10.0")
+	invokers[1].Destroy()
+	assert.Equal(t, invokers, r.Route(invokers, consumerURL, nil))
+}
+
+func TestCompareVersions(t *testing.T) {
+	assert.Equal(t, 0, compareVersions("2.1", "2.1.0"))
+	assert.Equal(t, 1, compareVersions("2.10.0", "2.9.1"))
+	assert.Equal(t, -1, compareVersions("2.1.0-SNAPSHOT", "2.1.0"))
+	assert.Equal(t, -1, compareVersions("2.1.0-alpha", "2.1.0-beta"))
+	assert.Equal(t, 1, compareVersions("3", "2.99.99"))
+}
@@ -25,5 +25,6 @@ const (
 	ClusterKeyFailover  = "failover"
 	ClusterKeyFailsafe  = "failsafe"
 	ClusterKeyForking   = "forking"
+	ClusterKeyMergeable = "mergeable"
 	ClusterKeyZoneAware = "zoneAware"
 )
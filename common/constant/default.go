@@ -44,15 +44,26 @@ const (
 	DEFAULT_FAILBACK_TASKS     = 100
 	DEFAULT_REST_CLIENT        = "resty"
 	DEFAULT_REST_SERVER        = "go-restful"
+	DEFAULT_REST_RETRY_STATUS  = "502,503,504"
+	DEFAULT_WILDCARD_REFRESH   = "30s"
 	DEFAULT_PORT               = 20000
 	DEFAULT_METADATAPORT       = 20005
 	DEFAULT_SERIALIZATION      = HESSIAN2_SERIALIZATION
+	DEFAULT_THREADS            = 200
+	DEFAULT_SLOW_THRESHOLD     = 1000
+	DEFAULT_AFFINITY_BIAS      = 1.0
+	DEFAULT_DEDUP_TTL          = "60s"
+)
+
+const (
+	// FIXED_THREADPOOL dispatches the requests of a service on its own bounded goroutine pool
+	FIXED_THREADPOOL = "fixed"
 )
 
 const (
 	DEFAULT_KEY               = "default"
 	PREFIX_DEFAULT_KEY        = "default."
-	DEFAULT_SERVICE_FILTERS   = EchoFilterKey + "," + MetricsFilterKey + "," + TokenFilterKey + "," + AccessLogFilterKey + "," + TpsLimitFilterKey + "," + GenericServiceFilterKey + "," + ExecuteLimitFilterKey + "," + GracefulShutdownProviderFilterKey
+	DEFAULT_SERVICE_FILTERS   = TraceLogFilterKey + "," + EchoFilterKey + "," + MetricsFilterKey + "," + TokenFilterKey + "," + AccessLogFilterKey + "," + TpsLimitFilterKey + "," + GenericServiceFilterKey + "," + ExecuteLimitFilterKey + "," + GracefulShutdownProviderFilterKey
 	DEFAULT_REFERENCE_FILTERS = GracefulShutdownConsumerFilterKey
 	GENERIC_REFERENCE_FILTERS = GenericFilterKey
 	GENERIC                   = "$invoke"
@@ -56,6 +56,8 @@ const (
 	PARAMS_TYPE_Key  = "parameter-type-names"
 	DEFAULT_Key      = "default"
 	METADATATYPE_KEY = "metadata-type"
+	// OBSERVER_KEY marks a consumer which discovers and invokes the providers without appearing as a live consumer
+	OBSERVER_KEY = "observer"
 )
 
 const (
@@ -65,12 +67,22 @@ const (
 
 // Filter Keys
 const (
+	ACLFilterKey                         = "acl"
 	AccessLogFilterKey                   = "accesslog"
 	ActiveFilterKey                      = "active"
+	AdmissionFilterKey                   = "admission"
+	AttachmentDecryptFilterKey           = "attachment-decrypt"
+	AttachmentEncryptFilterKey           = "attachment-encrypt"
+	AttachmentLimitFilterKey             = "attachment-limit"
 	AuthConsumerFilterKey                = "sign"
 	AuthProviderFilterKey                = "auth"
+	ConsumerRateLimitFilterKey           = "consumer-rate-limit"
+	ContextConsumerFilterKey             = "context-consumer"
+	ContextProviderFilterKey             = "context-provider"
+	DedupFilterKey                       = "dedup"
 	EchoFilterKey                        = "echo"
 	ExecuteLimitFilterKey                = "execute"
+	FaultInjectFilterKey                 = "fault-inject"
 	GenericFilterKey                     = "generic"
 	GenericServiceFilterKey              = "generic_service"
 	GracefulShutdownProviderFilterKey    = "pshutdown"
@@ -79,11 +91,16 @@ const (
 	HystrixConsumerFilterKey             = "hystrix_consumer"
 	HystrixProviderFilterKey             = "hystrix_provider"
 	MetricsFilterKey                     = "metrics"
+	PriorityFilterKey                    = "priority"
+	RequiredAttachmentFilterKey          = "required-attachment"
 	SeataFilterKey                       = "seata"
 	SentinelProviderFilterKey            = "sentinel-provider"
 	SentinelConsumerFilterKey            = "sentinel-consumer"
+	SingleFlightFilterKey                = "single-flight"
+	SlowRequestFilterKey                 = "slow-request"
 	TokenFilterKey                       = "token"
 	TpsLimitFilterKey                    = "tps"
+	TraceLogFilterKey                    = "trace-log"
 	TracingFilterKey                     = "tracing"
 )
 
@@ -95,10 +112,14 @@ const (
 	WEIGHT_KEY                             = "weight"
 	WARMUP_KEY                             = "warmup"
 	RETRIES_KEY                            = "retries"
+	RETRY_ATTEMPTS_KEY                     = "retry.attempts"
+	RETRY_ADDRESSES_KEY                    = "retry.addresses"
+	FALLBACK_PROTOCOL_KEY                  = "fallback.protocol"
 	STICKY_KEY                             = "sticky"
 	BEAN_NAME                              = "bean.name"
 	FAIL_BACK_TASKS_KEY                    = "failbacktasks"
 	FORKS_KEY                              = "forks"
+	FORKING_QUORUM_KEY                     = "forking.quorum"
 	DEFAULT_FORKS                          = 2
 	DEFAULT_TIMEOUT                        = 1000
 	TPS_LIMITER_KEY                        = "tps.limiter"
@@ -117,7 +138,259 @@ const (
 	RETRY_PERIOD_KEY                       = "retry.period"
 	RETRY_TIMES_KEY                        = "retry.times"
 	CYCLE_REPORT_KEY                       = "cycle.report"
+	METADATA_CACHE_FILE_KEY                = "metadata.cache.file"
 	DEFAULT_BLACK_LIST_RECOVER_BLOCK       = 16
+	THREADPOOL_KEY                         = "threadpool"
+	THREADS_KEY                            = "threads"
+)
+
+// Fault inject filter
+const (
+	// key whether the fault injection is enabled
+	FAULT_INJECT_ENABLED_KEY = "fault.enabled"
+	// key of the percentage of requests which will be delayed
+	FAULT_INJECT_DELAY_PERCENT_KEY = "fault.delay.percent"
+	// key of the latency added to the delayed requests, e.g. 200ms
+	FAULT_INJECT_DELAY_KEY = "fault.delay"
+	// key of the percentage of requests which will be aborted
+	FAULT_INJECT_ABORT_PERCENT_KEY = "fault.abort.percent"
+	// key of the error message returned by the aborted requests
+	FAULT_INJECT_ABORT_ERROR_KEY = "fault.abort.error"
+)
+
+// TLS, the keys start with "." so that they aren't registered to the registry
+const (
+	TLS_CERT_FILE_KEY            = ".tls.cert-file"
+	TLS_KEY_FILE_KEY             = ".tls.key-file"
+	TLS_CA_FILE_KEY              = ".tls.ca-file"
+	TLS_SERVER_NAME_KEY          = ".tls.server-name"
+	TLS_INSECURE_SKIP_VERIFY_KEY = ".tls.insecure-skip-verify"
+)
+
+// Locality affinity
+const (
+	// key of the locality where the provider instance is deployed, e.g. the availability zone
+	LOCALITY_KEY = "locality"
+	// key of the locality the consumer prefers to select the providers in
+	LOCALITY_AFFINITY_KEY = "affinity.locality"
+	// key of the probability of selecting a same-locality provider, from 0 to 1
+	LOCALITY_AFFINITY_BIAS_KEY = "affinity.bias"
+	// key of the instance metadata of the weight of the instance in the registry, e.g. the one adjusted in the nacos
+	// console, which scales the weights of the providers of the instance
+	INSTANCE_WEIGHT_KEY = "instance.weight"
+)
+
+// Active health check
+const (
+	// key of the health check url advertised by the provider instance, e.g. http://10.0.0.1:8080/health,
+	// or :8080/health on the host of the provider, which is healthy once it responds with a 2xx status
+	HEALTH_CHECK_URL_KEY = "health.check.url"
+	// key of the interval the consumer probes the health check urls of the providers at, e.g. 5s, and the
+	// providers aren't probed without it
+	HEALTH_CHECK_INTERVAL_KEY = "health.check.interval"
+	// key of the timeout of a probe of the health check url
+	HEALTH_CHECK_TIMEOUT_KEY     = "health.check.timeout"
+	DEFAULT_HEALTH_CHECK_TIMEOUT = "1s"
+)
+
+// Blue-green routing
+const (
+	// key of the color of the provider instance, e.g. blue or green, and the one of the attachment
+	// pinning the request to the providers of the color
+	COLOR_KEY = "color"
+	// key of the policy once none of the providers is of the pinned color, which is one of
+	// COLOR_FALLBACK_ANY, COLOR_FALLBACK_UNCOLORED and COLOR_FALLBACK_NONE
+	COLOR_FALLBACK_KEY = "color.fallback"
+	// the request falls back to all of the providers, it's the default policy
+	COLOR_FALLBACK_ANY = "any"
+	// the request falls back to the providers without any color
+	COLOR_FALLBACK_UNCOLORED = "uncolored"
+	// the request fails without any provider
+	COLOR_FALLBACK_NONE = "none"
+)
+
+// Version preference
+const (
+	// key of the minimum app version of the providers preferred by the reference, e.g. 2.1.0, the older providers
+	// and the ones without the version are used only once none of the preferred ones is available
+	PREFER_MIN_APP_VERSION_KEY = "prefer.min.app.version"
+	// key of the app version published by the provider for the version router, which is apart from app.version
+	// since the merged urls of the providers without the version carry the one of the consumer
+	PROVIDER_APP_VERSION_KEY = "provider.app.version"
+)
+
+// Dedup filter
+const (
+	// key of the attachment supplied by the consumer, the requests with the same key are executed at most once
+	IDEMPOTENCY_KEY = "idempotency-key"
+	// key of the duration that the result of the first request is cached for the duplicate ones
+	DEDUP_TTL_KEY = "dedup.ttl"
+)
+
+// Context propagation filter
+const (
+	// key of the comma separated names of the context keys propagated from the consumer to the provider
+	CONTEXT_KEYS_KEY = "context.keys"
+	// prefix of the attachments carrying the propagated context values
+	CONTEXT_ATTACHMENT_PREFIX = "context."
+)
+
+// Attachment template
+const (
+	// prefix of the url params carrying the attachments set on every invocation of the reference,
+	// e.g. attachment.template.tenant=t1, which are overridden by the per-call ones
+	ATTACHMENT_TEMPLATE_PREFIX = "attachment.template."
+)
+
+// Graceful startup
+const (
+	// key of the duration the registration of the exported provider is deferred for, e.g. 5s,
+	// the provider serves the requests meanwhile but isn't discovered by the consumers until it elapses
+	STARTUP_DELAY_KEY = "startup.delay"
+	// key of the result attachment telling the health probes whether the provider is ready for traffic
+	READY_KEY = "ready"
+)
+
+// Group merger
+const (
+	// key of the merger combining the results of the groups invoked by the reference with group * or a group list,
+	// e.g. slice or map, and true or default selects it by the kind of the results
+	MERGER_KEY = "merger"
+	// value of the merger key selecting the merger by the kind of the results
+	DEFAULT_MERGER = "default"
+)
+
+// Advertised address
+const (
+	// key of the host registered for the exported provider instead of the bound one, e.g. the public ip behind NAT
+	ADVERTISED_HOST_KEY = "advertised.host"
+	// key of the port registered for the exported provider instead of the bound one, e.g. the mapped port
+	ADVERTISED_PORT_KEY = "advertised.port"
+)
+
+// Slow request filter
+const (
+	// key of the latency threshold in milliseconds, the invocations exceeding it are flagged as slow
+	SLOW_THRESHOLD_KEY = "slow.threshold"
+)
+
+// ACL filter, the lists are comma separated consumer application names, and the lists of a method
+// override the ones of the service, e.g. methods.GetUser.acl.allow
+const (
+	// key of the consumer applications allowed to invoke the provider, all of them are allowed if it's empty
+	ACL_ALLOW_KEY = "acl.allow"
+	// key of the consumer applications denied to invoke the provider, it takes precedence over the allow list
+	ACL_DENY_KEY = "acl.deny"
+)
+
+// Attachment encryption filter
+const (
+	// key of the comma separated attachment keys encrypted by the reference, which is sent with the request
+	// as well to tell the provider the ones to decrypt
+	ENCRYPTED_ATTACHMENTS_KEY = "attachment.encrypted"
+	// key of the name of the attachment cipher
+	ATTACHMENT_CIPHER_KEY = "attachment.cipher"
+	// name of the default attachment cipher
+	DEFAULT_ATTACHMENT_CIPHER = "aes-gcm"
+	// key of the id of the key shared by the consumers and the providers, which is sent with the request as well
+	ATTACHMENT_KEY_ID_KEY = "attachment.key.id"
+)
+
+// Required attachment filter
+const (
+	// key of the comma separated attachment keys every request must carry, and methods.<method>.attachment.required
+	// overrides it
+	REQUIRED_ATTACHMENTS_KEY = "attachment.required"
+)
+
+// Attachment limit filter
+const (
+	// key of the max number of the attachments of a request
+	ATTACHMENT_MAX_COUNT_KEY = "attachment.max.count"
+	// key of the max total bytes of the keys and the values of the attachments of a request
+	ATTACHMENT_MAX_SIZE_KEY = "attachment.max.size"
+	// key of the policy of the requests over the limits, either reject by default or truncate, which drops the
+	// attachments over the limits with a warning
+	ATTACHMENT_LIMIT_POLICY_KEY = "attachment.limit.policy"
+	// DEFAULT_ATTACHMENT_MAX_COUNT is the default max number of the attachments of a request
+	DEFAULT_ATTACHMENT_MAX_COUNT = 1024
+	// DEFAULT_ATTACHMENT_MAX_SIZE is the default max total bytes of the attachments of a request
+	DEFAULT_ATTACHMENT_MAX_SIZE = 1024 * 1024
+)
+
+// Request logging
+const (
+	// TRACE_ID_KEY is the attachment of the trace id supplied by the consumer, which is written along with
+	// the provider logs of the request as the field of the same name
+	TRACE_ID_KEY = "traceId"
+)
+
+// Mock cluster interceptor
+const (
+	// MOCK_KEY is the mock of the reference, e.g. file:testdata/fixtures.yml referencing the recorded responses
+	// of the methods in a fixture file or directory
+	MOCK_KEY = "mock"
+	// MOCK_FILE_PREFIX is the prefix of the mock referencing the fixtures
+	MOCK_FILE_PREFIX = "file:"
+)
+
+// Priority filter
+const (
+	// PRIORITY_KEY is the attachment key of the priority lane of the request chosen by the consumer, e.g. high
+	PRIORITY_KEY = "priority"
+	// PRIORITY_LANES_KEY is the comma separated lane:threshold pairs of the provider, and the requests of a lane
+	// are shed once the number of the requests of the service in flight exceeds its threshold
+	PRIORITY_LANES_KEY = "priority.lanes"
+	// PRIORITY_DEFAULT_LANE_KEY is the lane of the requests without the priority or with an unknown one
+	PRIORITY_DEFAULT_LANE_KEY = "priority.default"
+	// DEFAULT_PRIORITY_LANE is the default lane of the unprioritized requests
+	DEFAULT_PRIORITY_LANE = "default"
+)
+
+// Idempotent retries
+const (
+	// IDEMPOTENT_KEY tells whether the methods of the reference are safe to be retried by the failover cluster,
+	// and methods.<method>.idempotent overrides it. The get-style methods, e.g. GetUser or queryOrders,
+	// are idempotent if it isn't configured, while the others fail fast on the first error.
+	IDEMPOTENT_KEY = "idempotent"
+)
+
+// Oneway invocation
+const (
+	// RETURN_KEY tells whether the consumer awaits the responses of the methods of the reference, and
+	// methods.<method>.return overrides it. The invocations of the methods with false are oneway ones, which
+	// return once the requests are written without any reply, and the providers don't respond to them.
+	RETURN_KEY = "return"
+)
+
+// Admission queue
+const (
+	// ADMISSION_CAPACITY_KEY is the number of the requests of the service dispatched at the same time,
+	// and the admission queue is disabled if it isn't positive
+	ADMISSION_CAPACITY_KEY = "admission.capacity"
+	// ADMISSION_MAX_WAIT_KEY is the longest duration a request waits in the queue for a dispatch slot, e.g. 100ms,
+	// before it's rejected as busy
+	ADMISSION_MAX_WAIT_KEY = "admission.maxWait"
+	// ADMISSION_QUEUE_KEY is the number of the requests waiting in the queue at most, and the ones beyond it are
+	// rejected at once. It's the capacity if it isn't positive.
+	ADMISSION_QUEUE_KEY = "admission.queue"
+	// DEFAULT_ADMISSION_MAX_WAIT is the default longest duration a request waits in the queue
+	DEFAULT_ADMISSION_MAX_WAIT = "100ms"
+)
+
+// Server timeout
+const (
+	// key of the duration the provider completes a request within, e.g. 3s, and methods.<method>.server.timeout
+	// overrides it. The server replies a timeout error once it elapses, or once the timeout of the consumer
+	// elapses if it's shorter, and the result of the invocation in flight is discarded.
+	SERVER_TIMEOUT_KEY = "server.timeout"
+)
+
+// Access log sampling
+const (
+	// key of the fraction of the invocations whose full request and response are logged by the access log,
+	// from 0 to 1, e.g. 0.01, and methods.<method>.accesslog.sample overrides it
+	ACCESS_LOG_SAMPLE_KEY = "accesslog.sample"
 )
 
 const (
@@ -139,10 +412,14 @@ const (
 	SIMPLIFIED_KEY            = "simplified"
 	NAMESPACE_KEY             = "namespace"
 	REGISTRY_GROUP_KEY        = "registry.group"
+	// REGISTRY_DEDUPLICATE_KEY Specify whether the providers of the same address reported by multiple registries
+	// are deduplicated, which is true by default
+	REGISTRY_DEDUPLICATE_KEY = "registry.deduplicate"
 )
 
 const (
 	APPLICATION_KEY          = "application"
+	REMOTE_APPLICATION_KEY   = "remote.application"
 	ORGANIZATION_KEY         = "organization"
 	NAME_KEY                 = "name"
 	MODULE_KEY               = "module"
@@ -177,6 +454,13 @@ const (
 	CONFIG_SECRET_KEY             = "secret"
 	CONFIG_BACKUP_CONFIG_KEY      = "isBackupConfig"
 	CONFIG_BACKUP_CONFIG_PATH_KEY = "backupConfigPath"
+	CONFIG_PORTAL_ADDRESS_KEY     = "portalAddress"
+	CONFIG_PORTAL_TOKEN_KEY       = "portalToken"
+	CONFIG_ENV_KEY                = "env"
+	CONFIG_OPERATOR_KEY           = "operator"
+	CONFIG_TOKEN_KEY              = "token"
+	CONFIG_WATCH_WAIT_KEY         = "watchWait"
+	CONFIG_POLL_INTERVAL_KEY      = "pollInterval"
 )
 
 const (
@@ -196,6 +480,7 @@ const (
 	MetadataReportPrefix       = "dubbo.metadata-report"
 	RouterConfigPrefix         = "dubbo.router"
 	LoggerConfigPrefix         = "dubbo.logger"
+	TLSConfigPrefix            = "dubbo.tls-configs"
 )
 
 const (
@@ -247,6 +532,165 @@ const (
 	TRACING_REMOTE_SPAN_CTX = DubboCtxKey("tracing.remote.span.ctx")
 )
 
+// Request cancellation
+const (
+	// CANCEL_METHOD is the method of the oneway request which notifies the provider that a request has been cancelled
+	CANCEL_METHOD = "$cancel"
+	// CANCEL_REQUEST_ID_KEY is the attachment key of the id of the cancelled request
+	CANCEL_REQUEST_ID_KEY = "cancel.request.id"
+	// REQUEST_CTX_KEY is the invocation attribute key of the provider side context which is done once the request is cancelled
+	REQUEST_CTX_KEY = "request.ctx"
+	// CODEC_OPTIONS_KEY is the invocation attribute key of the options of the codec encoding the request of the invocation
+	// and decoding its response, which are set by the invoker of the protocol
+	CODEC_OPTIONS_KEY = "codec.options"
+	// CALL_DEADLINE_KEY is the invocation attribute key of the deadline of the context of the consumer invoking,
+	// which bounds the waiting for the providers of the empty providers policy
+	CALL_DEADLINE_KEY = "call.deadline"
+)
+
+// Rest protocol
+const (
+	// REST_CODEC_KEY selects the codec used for the rest request and response bodies
+	REST_CODEC_KEY = "rest.codec"
+	// REST_CODEC_PB_JSON marshals proto messages by the proto3 JSON mapping
+	REST_CODEC_PB_JSON = "pb-json"
+	// REST_RETRY_STATUS_KEY is the comma separated status codes which the cluster fails over,
+	// the responses with the other error status codes are returned as the business errors
+	REST_RETRY_STATUS_KEY = "rest.retry.status"
+	// REST_OPENAPI_PATH_KEY is the path the OpenAPI document of the rest services is served on, e.g. /openapi.json,
+	// and the document isn't served without it
+	REST_OPENAPI_PATH_KEY = "rest.openapi.path"
+)
+
+// Dubbo protocol
+const (
+	// CONNECT_TIMEOUT_KEY is the duration the connection to the provider is established within, e.g. 500ms,
+	// which is 3s by default, so that an unreachable provider fails fast regardless of the request timeout.
+	// The references to the same provider address share its connections, which take the timeout of the reference
+	// connecting it first, so the timeout of the others doesn't apply until the connections are closed
+	CONNECT_TIMEOUT_KEY = "connect.timeout"
+	// READ_TIMEOUT_KEY is the duration the response is awaited within once the request is sent, e.g. 2s, which can
+	// be configured by methods as well, e.g. methods.GetUser.read.timeout. It's bounded by the request timeout,
+	// which is the only bound without it
+	READ_TIMEOUT_KEY = "read.timeout"
+	// PAYLOAD_KEY is the max body length in bytes of the dubbo frames, the larger ones are rejected by the codec
+	PAYLOAD_KEY = "payload"
+	// SERIALIZATION_ALLOWLIST_KEY is the comma separated classes and packages allowed by the hessian2 decoding
+	SERIALIZATION_ALLOWLIST_KEY = "serialization.allowlist"
+	// SERIALIZATION_DENYLIST_KEY is the comma separated classes and packages denied by the hessian2 decoding
+	SERIALIZATION_DENYLIST_KEY = "serialization.denylist"
+	// SERIALIZATION_MAX_STRING_LENGTH_KEY is the max number of the chars of a string decoded by hessian2,
+	// which is 4194304 by default, and the bodies with the longer ones are rejected before they are decoded
+	SERIALIZATION_MAX_STRING_LENGTH_KEY = "serialization.max.string.length"
+	// SERIALIZATION_MAX_COLLECTION_SIZE_KEY is the max number of the elements of a list or map decoded by hessian2,
+	// which is 1048576 by default, and the bodies with the larger ones are rejected before they are decoded
+	SERIALIZATION_MAX_COLLECTION_SIZE_KEY = "serialization.max.collection.size"
+	// SERIALIZATION_UNKNOWN_FIELDS_KEY is how the hessian2 decoding handles the fields unknown to the Go types, which is
+	// ignore by default, error to reject the bodies with them, or capture to pass them to the objects keeping them
+	SERIALIZATION_UNKNOWN_FIELDS_KEY = "serialization.unknown.fields"
+	// SERIALIZATION_LENIENT_COLLECTION_KEY enables the lenient decoding of the lists and maps replied by the methods,
+	// which skips the elements their Go types can't hold instead of failing the whole response. It's false by default
+	// and configured by methods, e.g. methods.ListUsers.serialization.lenient.collection
+	SERIALIZATION_LENIENT_COLLECTION_KEY = "serialization.lenient.collection"
+	// SERIALIZATION_SKIPPED_ELEMENTS_KEY is the attachment of the results telling the number of the elements
+	// skipped by the lenient decoding of the collections, which is absent if none is skipped
+	SERIALIZATION_SKIPPED_ELEMENTS_KEY = "serialization.skipped.elements"
+	// COMPRESS_KEY enables the gzip compression of the hessian2 bodies of the dubbo frames of a service, the requests
+	// are compressed only to the providers advertising it and the responses only to the consumers accepting it
+	COMPRESS_KEY = "compress"
+	// COMPRESS_MIN_SIZE_KEY is the min body size in bytes of the frames compressed, which is 1024 by default,
+	// and the smaller ones stay uncompressed
+	COMPRESS_MIN_SIZE_KEY = "compress.minSize"
+	// COMPRESS_SUPPORTED_KEY is the compression of the requests decoded by the provider, which is advertised
+	// by its url once the service enables the compression
+	COMPRESS_SUPPORTED_KEY = "compress.supported"
+	// COMPRESS_ACCEPT_KEY is the attachment of the requests whose consumers accept the compressed responses
+	COMPRESS_ACCEPT_KEY = "compress.accept"
+	// PREFER_SERIALIZATION_KEY is the comma separated serializations preferred by the reference in order,
+	// the first one supported by the provider is used by the requests to it
+	PREFER_SERIALIZATION_KEY = "prefer.serialization"
+	// SERIALIZATION_SUPPORTED_KEY is the comma separated serializations decoded by the provider, which are advertised
+	// by the service configured with them, e.g. hessian2,protobuf, and the first one is its serialization
+	SERIALIZATION_SUPPORTED_KEY = "serialization.supported"
+	// TYPED_ATTACHMENTS_KEY set to false keeps the service or the reference from the structured attachments serialized
+	// with their structure, they are flattened into json strings instead. The consumer sends it set to true to the
+	// provider once it accepts them, and the provider flattens the result attachments unless it's sent
+	TYPED_ATTACHMENTS_KEY = "attachment.typed"
+	// TYPED_ATTACHMENTS_SUPPORTED_KEY is advertised by the provider accepting the structured attachments, and the
+	// consumers flatten the attachments to the providers without it, e.g. the older ones
+	TYPED_ATTACHMENTS_SUPPORTED_KEY = "attachment.typed.supported"
+	// HESSIAN_DECIMAL_TYPE_KEY is the Go type of java.math.BigDecimal, which is decimal by default or string
+	HESSIAN_DECIMAL_TYPE_KEY = "hessian.decimal.type"
+	// HESSIAN_TIME_TYPE_KEY is the java type of time.Time, which is date by default for java.util.Date,
+	// or zoned for java.time.ZonedDateTime preserving the zone
+	HESSIAN_TIME_TYPE_KEY = "hessian.time.type"
+	// HESSIAN_TIME_LOCATION_KEY is the location which the decoded java.util.Date is converted into, e.g. Asia/Shanghai
+	HESSIAN_TIME_LOCATION_KEY = "hessian.time.location"
+)
+
+// Empty providers policy
+const (
+	// EMPTY_PROVIDERS_POLICY_KEY is how the reference handles the requests once there isn't any provider,
+	// which is fail-fast by default
+	EMPTY_PROVIDERS_POLICY_KEY = "empty.providers.policy"
+	// EMPTY_PROVIDERS_TIMEOUT_KEY is the duration the wait policy blocks the requests for the providers to appear,
+	// and the duration the use-cache policy serves the last known providers after they are gone
+	EMPTY_PROVIDERS_TIMEOUT_KEY = "empty.providers.timeout"
+	// EMPTY_PROVIDERS_POLICY_FAIL_FAST fails the requests at once if there isn't any provider
+	EMPTY_PROVIDERS_POLICY_FAIL_FAST = "fail-fast"
+	// EMPTY_PROVIDERS_POLICY_WAIT blocks the requests until a provider appears or the timeout elapses
+	EMPTY_PROVIDERS_POLICY_WAIT = "wait"
+	// EMPTY_PROVIDERS_POLICY_USE_CACHE serves the requests by the last known providers until the timeout elapses
+	EMPTY_PROVIDERS_POLICY_USE_CACHE = "use-cache"
+	// DEFAULT_EMPTY_PROVIDERS_TIMEOUT is the default timeout of the empty providers policies
+	DEFAULT_EMPTY_PROVIDERS_TIMEOUT = "3s"
+)
+
+// Connection warm up
+const (
+	// CONNECTION_WARMUP_KEY enables the reference to connect to the providers resolved before it's used,
+	// and to the providers notified later in the background
+	CONNECTION_WARMUP_KEY = "connection.warmup"
+	// CONNECTION_WARMUP_TOP_KEY is the number of the providers of the highest weights connected before the reference
+	// is used, and all of them are connected if it isn't positive
+	CONNECTION_WARMUP_TOP_KEY = "connection.warmup.top"
+	// CONNECTION_WARMUP_TIMEOUT_KEY is the duration the reference waits for the providers to be connected, e.g. 5s
+	CONNECTION_WARMUP_TIMEOUT_KEY = "connection.warmup.timeout"
+	// DEFAULT_CONNECTION_WARMUP_TIMEOUT is the default duration of the connection warm up
+	DEFAULT_CONNECTION_WARMUP_TIMEOUT = "3s"
+)
+
+// Registry fallback
+const (
+	// FALLBACK_RETRY_INTERVAL_KEY is the interval the reference with the fallback urls retries to refer the registries
+	// after they fail, e.g. 3s
+	FALLBACK_RETRY_INTERVAL_KEY = "fallback.retry.interval"
+	// DEFAULT_FALLBACK_RETRY_INTERVAL is the default interval of the retries to refer the registries
+	DEFAULT_FALLBACK_RETRY_INTERVAL = "3s"
+)
+
+// Traffic mirroring
+const (
+	// MIRROR_PERCENTAGE_KEY is the percentage of the invocations of the reference copied to the mirror urls,
+	// from 0 to 100
+	MIRROR_PERCENTAGE_KEY = "mirror.percentage"
+	// DEFAULT_MIRROR_PERCENTAGE mirrors all of the invocations by default
+	DEFAULT_MIRROR_PERCENTAGE = 100
+	// MIRROR_MAX_CONCURRENCY_KEY is the max number of the copies of the invocations in flight to the mirror urls,
+	// and the invocations mirrored beyond it are dropped
+	MIRROR_MAX_CONCURRENCY_KEY = "mirror.max.concurrency"
+	// DEFAULT_MIRROR_MAX_CONCURRENCY is the default max number of the copies in flight
+	DEFAULT_MIRROR_MAX_CONCURRENCY = 64
+)
+
+// Use for logger module
+const (
+	// LoggerLevelSuffix Specify the suffix of the config center key of the logger level overrides
+	LoggerLevelSuffix = ".logger-level"
+	// LoggerRootLevel is the name of the level of the whole logger in the overrides
+	LoggerRootLevel = "root"
+)
+
 // Use for router module
 const (
 	// TagRouterRuleSuffix Specify tag router suffix
@@ -255,6 +699,12 @@ const (
 	ConditionRouterRuleSuffix = ".condition-router"
 	// MeshRouteSuffix Specify mesh router suffix
 	MeshRouteSuffix = ".MESHAPPRULE"
+	// CanaryRouterRuleSuffix Specify canary router suffix
+	CanaryRouterRuleSuffix = ".canary-router"
+	// RateLimitRuleSuffix Specify the suffix of the config center key of the consumer rate limit
+	RateLimitRuleSuffix = ".rate-limit"
+	// ClusterRuleSuffix Specify the suffix of the config center key of the cluster strategy overriding the one of the reference
+	ClusterRuleSuffix = ".cluster"
 	// ForceUseTag is the tag in attachment
 	ForceUseTag = "dubbo.force.tag"
 	Tagkey      = "dubbo.tag"
@@ -322,6 +772,9 @@ const (
 
 	// SERVICE_DISCOVERY_KEY indicate which service discovery instance will be used
 	SERVICE_DISCOVERY_KEY = "service_discovery"
+
+	// WILDCARD_REFRESH_INTERVAL_KEY is the interval the wildcard subscription looks for the new services in
+	WILDCARD_REFRESH_INTERVAL_KEY = "wildcard.refresh.interval"
 )
 
 // Generic Filter
@@ -27,3 +27,8 @@ const (
 	PROTOBUF_SERIALIZATION = "protobuf"
 	MSGPACK_SERIALIZATION  = "msgpack"
 )
+
+const (
+	// GZIP_COMPRESSION is the compression of the hessian2 bodies of the dubbo frames
+	GZIP_COMPRESSION = "gzip"
+)
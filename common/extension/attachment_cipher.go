@@ -0,0 +1,38 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package extension
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/filter"
+)
+
+var attachmentCiphers = make(map[string]func() filter.AttachmentCipher)
+
+// SetAttachmentCipher puts the @fcn into map with name
+func SetAttachmentCipher(name string, fcn func() filter.AttachmentCipher) {
+	attachmentCiphers[name] = fcn
+}
+
+// GetAttachmentCipher finds the AttachmentCipher with @name
+// Panic if not found
+func GetAttachmentCipher(name string) filter.AttachmentCipher {
+	if attachmentCiphers[name] == nil {
+		panic("attachment cipher for " + name + " is not existing, make sure you have import the package.")
+	}
+	return attachmentCiphers[name]()
+}
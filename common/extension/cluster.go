@@ -36,3 +36,8 @@ func GetCluster(name string) cluster.Cluster {
 	}
 	return clusters[name]()
 }
+
+// HasCluster tells whether the cluster fault-tolerant mode with @name is registered
+func HasCluster(name string) bool {
+	return clusters[name] != nil
+}
@@ -17,6 +17,10 @@
 
 package extension
 
+import (
+	"sort"
+)
+
 import (
 	"dubbo.apache.org/dubbo-go/v3/filter"
 )
@@ -24,8 +28,14 @@ import (
 var (
 	filters                  = make(map[string]func() filter.Filter)
 	rejectedExecutionHandler = make(map[string]func() filter.RejectedExecutionHandler)
+	globalInterceptors       = make([]globalInterceptor, 0, 4)
 )
 
+type globalInterceptor struct {
+	priority int
+	creator  func() filter.Filter
+}
+
 // SetFilter sets the filter extension with @name
 // For example: hystrix/metrics/token/tracing/limit/...
 func SetFilter(name string, v func() filter.Filter) {
@@ -54,3 +64,22 @@ func GetRejectedExecutionHandler(name string) filter.RejectedExecutionHandler {
 	}
 	return creator()
 }
+
+// AddGlobalInterceptor adds the interceptor wrapping the filter chain of every consumer and provider
+// invocation, no matter which filters the service configures. The interceptors with lower @priority
+// are outer, and the ones with the same priority are ordered by the registration.
+func AddGlobalInterceptor(priority int, creator func() filter.Filter) {
+	globalInterceptors = append(globalInterceptors, globalInterceptor{priority: priority, creator: creator})
+	sort.SliceStable(globalInterceptors, func(i, j int) bool {
+		return globalInterceptors[i].priority < globalInterceptors[j].priority
+	})
+}
+
+// GetGlobalInterceptors creates the global interceptors from the outermost to the innermost
+func GetGlobalInterceptors() []filter.Filter {
+	interceptors := make([]filter.Filter, 0, len(globalInterceptors))
+	for _, interceptor := range globalInterceptors {
+		interceptors = append(interceptors, interceptor.creator())
+	}
+	return interceptors
+}
@@ -21,7 +21,11 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/cluster/loadbalance"
 )
 
-var loadbalances = make(map[string]func() loadbalance.LoadBalance)
+var (
+	loadbalances      = make(map[string]func() loadbalance.LoadBalance)
+	selectionCallback loadbalance.SelectionCallback
+	weightProvider    loadbalance.WeightProvider
+)
 
 // SetLoadbalance sets the loadbalance extension with @name
 // For example: random/round_robin/consistent_hash/least_active/...
@@ -35,5 +39,30 @@ func GetLoadbalance(name string) loadbalance.LoadBalance {
 		panic("loadbalance for " + name + " is not existing, make sure you have import the package.")
 	}
 
-	return loadbalances[name]()
+	lb := loadbalances[name]()
+	if selectionCallback != nil {
+		return loadbalance.NewCallbackLoadBalance(lb, selectionCallback)
+	}
+	return lb
+}
+
+// SetLoadbalanceSelectionCallback sets the callback notified with the invoker selected by every loadbalance,
+// and nil removes it. It's supposed to be set before the invocations start.
+func SetLoadbalanceSelectionCallback(callback loadbalance.SelectionCallback) {
+	selectionCallback = callback
+}
+
+// SetLoadbalanceWeightProvider sets the provider of the effective weights consulted by the random loadbalance,
+// e.g. loadbalance.NewHealthWeightProvider, and nil restores the static weights. It's supposed to be set before
+// the invocations start.
+func SetLoadbalanceWeightProvider(provider loadbalance.WeightProvider) {
+	weightProvider = provider
+}
+
+// GetLoadbalanceWeightProvider returns the provider of the effective weights, which is loadbalance.GetWeight by default
+func GetLoadbalanceWeightProvider() loadbalance.WeightProvider {
+	if weightProvider == nil {
+		return loadbalance.GetWeight
+	}
+	return weightProvider
 }
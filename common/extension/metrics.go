@@ -17,26 +17,98 @@
 
 package extension
 
+import (
+	"sync"
+)
+
 import (
 	"dubbo.apache.org/dubbo-go/v3/metrics"
 )
 
 // we couldn't store the instance because the some instance may initialize before loading configuration
 // so lazy initialization will be better.
-var metricReporterMap = make(map[string]func(config *metrics.ReporterConfig) metrics.Reporter, 4)
+var (
+	metricReporterMap = make(map[string]func(config *metrics.ReporterConfig) metrics.MetricsReporter, 4)
+	// metricCallbacksLock guards the callbacks below, which are set by the reporters while the invocations run
+	metricCallbacksLock  sync.RWMutex
+	retrySuccessCallback metrics.RetrySuccessCallback
+	mirrorCallback       metrics.MirrorCallback
+	admissionCallback    metrics.AdmissionQueueCallback
+	slowRequestCallback  metrics.SlowRequestCallback
+)
 
 // SetMetricReporter sets a reporter with the @name
-func SetMetricReporter(name string, reporterFunc func(config *metrics.ReporterConfig) metrics.Reporter) {
+func SetMetricReporter(name string, reporterFunc func(config *metrics.ReporterConfig) metrics.MetricsReporter) {
 	metricReporterMap[name] = reporterFunc
 }
 
 // GetMetricReporter finds the reporter with @name.
 // if not found, it will panic.
 // we should know that this method usually is called when system starts, so we should panic
-func GetMetricReporter(name string, config *metrics.ReporterConfig) metrics.Reporter {
+func GetMetricReporter(name string, config *metrics.ReporterConfig) metrics.MetricsReporter {
 	reporterFunc, found := metricReporterMap[name]
 	if !found {
 		panic("Cannot find the reporter with name: " + name)
 	}
 	return reporterFunc(config)
 }
+
+// SetRetrySuccessCallback sets the callback the failover cluster notifies once an invocation succeeds after retries,
+// and nil removes it
+func SetRetrySuccessCallback(callback metrics.RetrySuccessCallback) {
+	metricCallbacksLock.Lock()
+	defer metricCallbacksLock.Unlock()
+	retrySuccessCallback = callback
+}
+
+// GetRetrySuccessCallback returns the callback notified with the invocations which succeed after retries
+func GetRetrySuccessCallback() metrics.RetrySuccessCallback {
+	metricCallbacksLock.RLock()
+	defer metricCallbacksLock.RUnlock()
+	return retrySuccessCallback
+}
+
+// SetMirrorCallback sets the callback notified with the result of every copy sent to the shadow providers,
+// whether it succeeds or not, and nil removes it
+func SetMirrorCallback(callback metrics.MirrorCallback) {
+	metricCallbacksLock.Lock()
+	defer metricCallbacksLock.Unlock()
+	mirrorCallback = callback
+}
+
+// GetMirrorCallback returns the callback notified with the invocations copied to the shadow providers
+func GetMirrorCallback() metrics.MirrorCallback {
+	metricCallbacksLock.RLock()
+	defer metricCallbacksLock.RUnlock()
+	return mirrorCallback
+}
+
+// SetAdmissionQueueCallback sets the callback reporting the depth of the admission queue of a provider
+// whenever a request enters or leaves it, and nil stops reporting it
+func SetAdmissionQueueCallback(callback metrics.AdmissionQueueCallback) {
+	metricCallbacksLock.Lock()
+	defer metricCallbacksLock.Unlock()
+	admissionCallback = callback
+}
+
+// GetAdmissionQueueCallback returns the callback notified with the depth of the admission queues of the providers
+func GetAdmissionQueueCallback() metrics.AdmissionQueueCallback {
+	metricCallbacksLock.RLock()
+	defer metricCallbacksLock.RUnlock()
+	return admissionCallback
+}
+
+// SetSlowRequestCallback sets the callback the slow request filter calls, on either side, with the invocations
+// exceeding the threshold, and nil turns the slow requests into logs only
+func SetSlowRequestCallback(callback metrics.SlowRequestCallback) {
+	metricCallbacksLock.Lock()
+	defer metricCallbacksLock.Unlock()
+	slowRequestCallback = callback
+}
+
+// GetSlowRequestCallback returns the callback notified with the invocations exceeding the slow threshold
+func GetSlowRequestCallback() metrics.SlowRequestCallback {
+	metricCallbacksLock.RLock()
+	defer metricCallbacksLock.RUnlock()
+	return slowRequestCallback
+}
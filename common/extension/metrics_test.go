@@ -18,7 +18,7 @@
 package extension
 
 import (
-	"context"
+	"sync"
 	"testing"
 	"time"
 )
@@ -35,15 +35,43 @@ import (
 func TestGetMetricReporter(t *testing.T) {
 	reporter := &mockReporter{}
 	name := "mock"
-	SetMetricReporter(name, func(config *metrics.ReporterConfig) metrics.Reporter {
+	SetMetricReporter(name, func(config *metrics.ReporterConfig) metrics.MetricsReporter {
 		return reporter
 	})
 	res := GetMetricReporter(name, metrics.NewReporterConfig())
 	assert.Equal(t, reporter, res)
 }
 
-type mockReporter struct{}
+func TestSetSlowRequestCallbackConcurrently(t *testing.T) {
+	defer SetSlowRequestCallback(nil)
+	var wg sync.WaitGroup
+	wg.Add(2)
+	// the reporter sets the callback while the invocations read it
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			SetSlowRequestCallback(func(protocol.Invoker, protocol.Invocation, time.Duration) {})
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			if callback := GetSlowRequestCallback(); callback != nil {
+				callback(nil, nil, time.Second)
+			}
+		}
+	}()
+	wg.Wait()
+	assert.NotNil(t, GetSlowRequestCallback())
 
-// Report method for feature expansion
-func (m mockReporter) Report(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation, cost time.Duration, res protocol.Result) {
+	SetSlowRequestCallback(nil)
+	assert.Nil(t, GetSlowRequestCallback())
 }
+
+type mockReporter struct{}
+
+func (m *mockReporter) IncCounter(string, float64, map[string]string) {}
+
+func (m *mockReporter) SetGauge(string, float64, map[string]string) {}
+
+func (m *mockReporter) ObserveHistogram(string, float64, map[string]string) {}
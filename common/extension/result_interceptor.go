@@ -0,0 +1,53 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package extension
+
+import (
+	"sort"
+	"sync"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+var (
+	resultInterceptorsLock sync.RWMutex
+	resultInterceptors     = make([]protocol.ResultInterceptor, 0, 8)
+)
+
+// AddResultInterceptor adds the interceptor of the provider results, and the interceptors are sorted by
+// the priorities, while the ones of the same priority are applied in the order they're added.
+func AddResultInterceptor(interceptor protocol.ResultInterceptor) {
+	resultInterceptorsLock.Lock()
+	defer resultInterceptorsLock.Unlock()
+	interceptors := make([]protocol.ResultInterceptor, 0, len(resultInterceptors)+1)
+	interceptors = append(interceptors, resultInterceptors...)
+	interceptors = append(interceptors, interceptor)
+	sort.SliceStable(interceptors, func(i, j int) bool {
+		return interceptors[i].GetPriority() < interceptors[j].GetPriority()
+	})
+	resultInterceptors = interceptors
+}
+
+// GetResultInterceptors returns the sorted interceptors of the provider results, the result won't be nil
+func GetResultInterceptors() []protocol.ResultInterceptor {
+	resultInterceptorsLock.RLock()
+	defer resultInterceptorsLock.RUnlock()
+	return resultInterceptors
+}
@@ -0,0 +1,86 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logger
+
+import (
+	"context"
+)
+
+import (
+	"go.uber.org/zap"
+)
+
+// contextKey is the key of the logger of the request in the context
+type contextKey struct{}
+
+// requestLogger is the global logger called directly, which is the base of the loggers of the requests
+var requestLogger Logger
+
+// FieldLogger is implemented by the loggers writing the key-value pairs along with every log,
+// which are the fields of the logs in the structured logging mode, i.e. the json encoding
+type FieldLogger interface {
+	Logger
+	With(keysAndValues ...interface{}) Logger
+}
+
+// With returns the logger writing the @keysAndValues pairs along with every log
+func (dl *DubboLogger) With(keysAndValues ...interface{}) Logger {
+	sugar, ok := dl.Logger.(*zap.SugaredLogger)
+	if !ok {
+		return dl
+	}
+	return &DubboLogger{Logger: sugar.With(keysAndValues...), dynamicLevel: dl.dynamicLevel}
+}
+
+// NewContext returns the context carrying the logger of the request, which writes the @keysAndValues pairs
+// along with every log of the request, e.g. the trace id of it. The pairs are skipped if the logger
+// isn't a FieldLogger.
+func NewContext(ctx context.Context, keysAndValues ...interface{}) context.Context {
+	l, ok := ctx.Value(contextKey{}).(Logger)
+	if !ok {
+		l = requestLogger
+	}
+	if fl, ok := l.(FieldLogger); ok {
+		l = fl.With(keysAndValues...)
+	}
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// directLogger returns the logger recording the callers calling it directly rather than by the functions
+// of the package, which is the case of the logger of the request
+func directLogger(l Logger) Logger {
+	dl, ok := l.(*DubboLogger)
+	if !ok {
+		return l
+	}
+	sugar, ok := dl.Logger.(*zap.SugaredLogger)
+	if !ok {
+		return l
+	}
+	return &DubboLogger{Logger: sugar.Desugar().WithOptions(zap.AddCallerSkip(-1)).Sugar(), dynamicLevel: dl.dynamicLevel}
+}
+
+// FromContext returns the logger of the request carried by @ctx, or the global logger if there isn't any
+func FromContext(ctx context.Context) Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(contextKey{}).(Logger); ok {
+			return l
+		}
+	}
+	return requestLogger
+}
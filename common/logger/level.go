@@ -0,0 +1,150 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logger
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+var (
+	// packageLevelsLock serializes the updates of packageLevels
+	packageLevelsLock sync.Mutex
+	// packageLevels holds the map[string]zapcore.Level of the levels overriding the one of the logger by packages
+	packageLevels atomic.Value
+)
+
+func init() {
+	packageLevels.Store(map[string]zapcore.Level{})
+}
+
+// SetPackageLoggerLevel overrides the level of the logs written by the package @pkg and its sub packages,
+// e.g. dubbo.apache.org/dubbo-go/v3/registry, and the most specific package wins.
+// It's only applied to the loggers recording the callers, which is the default one.
+func SetPackageLoggerLevel(pkg string, level string) bool {
+	l := new(zapcore.Level)
+	if err := l.Set(level); err != nil {
+		return false
+	}
+	updatePackageLevels(func(levels map[string]zapcore.Level) {
+		levels[pkg] = *l
+	})
+	return true
+}
+
+// ResetPackageLoggerLevel removes the level override of the package @pkg
+func ResetPackageLoggerLevel(pkg string) {
+	updatePackageLevels(func(levels map[string]zapcore.Level) {
+		delete(levels, pkg)
+	})
+}
+
+// ResetPackageLoggerLevels removes the level overrides of all of the packages
+func ResetPackageLoggerLevels() {
+	updatePackageLevels(func(levels map[string]zapcore.Level) {
+		for pkg := range levels {
+			delete(levels, pkg)
+		}
+	})
+}
+
+func updatePackageLevels(update func(map[string]zapcore.Level)) {
+	packageLevelsLock.Lock()
+	defer packageLevelsLock.Unlock()
+	old := packageLevels.Load().(map[string]zapcore.Level)
+	levels := make(map[string]zapcore.Level, len(old)+1)
+	for pkg, level := range old {
+		levels[pkg] = level
+	}
+	update(levels)
+	packageLevels.Store(levels)
+}
+
+// packageLevelCore applies the package level overrides on top of the dynamic level of the logger
+type packageLevelCore struct {
+	zapcore.Core
+	level zap.AtomicLevel
+}
+
+func newPackageLevelCore(core zapcore.Core, level zap.AtomicLevel) zapcore.Core {
+	return &packageLevelCore{Core: core, level: level}
+}
+
+func (c *packageLevelCore) Enabled(level zapcore.Level) bool {
+	if c.level.Enabled(level) {
+		return true
+	}
+	for _, l := range packageLevels.Load().(map[string]zapcore.Level) {
+		if l.Enabled(level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *packageLevelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &packageLevelCore{Core: c.Core.With(fields), level: c.level}
+}
+
+func (c *packageLevelCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if len(packageLevels.Load().(map[string]zapcore.Level)) == 0 {
+		if !c.level.Enabled(entry.Level) {
+			return checked
+		}
+		return c.Core.Check(entry, checked)
+	}
+	// the caller is unknown until the entry is written
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *packageLevelCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	if !c.levelOf(entry.Caller).Enabled(entry.Level) {
+		return nil
+	}
+	return c.Core.Write(entry, fields)
+}
+
+// levelOf returns the level overriding the one of the package of @caller, or the level of the logger
+func (c *packageLevelCore) levelOf(caller zapcore.EntryCaller) zapcore.Level {
+	pkg := callerPackage(caller.Function)
+	matched, level := "", c.level.Level()
+	for p, l := range packageLevels.Load().(map[string]zapcore.Level) {
+		if len(p) > len(matched) && (pkg == p || strings.HasPrefix(pkg, p+"/")) {
+			matched, level = p, l
+		}
+	}
+	return level
+}
+
+// callerPackage extracts the package path from the full function name, e.g. a/b/c.(*T).M
+func callerPackage(function string) string {
+	slash := strings.LastIndex(function, "/")
+	if dot := strings.Index(function[slash+1:], "."); dot >= 0 {
+		return function[:slash+1+dot]
+	}
+	return function
+}
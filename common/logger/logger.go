@@ -97,7 +97,11 @@ func InitLogger(conf *Config) {
 		zapLogger = initZapLoggerWithSyncer(config)
 	}
 
+	zapLogger = zapLogger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return newPackageLevelCore(core, config.ZapConfig.Level)
+	}))
 	logger = &DubboLogger{Logger: zapLogger.Sugar(), dynamicLevel: config.ZapConfig.Level}
+	requestLogger = directLogger(logger)
 
 	// set getty log
 	getty.SetLogger(logger)
@@ -106,6 +110,7 @@ func InitLogger(conf *Config) {
 // SetLogger sets logger for dubbo and getty
 func SetLogger(log Logger) {
 	logger = log
+	requestLogger = directLogger(logger)
 	getty.SetLogger(logger)
 }
 
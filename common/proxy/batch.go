@@ -0,0 +1,99 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package proxy
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	invocation_impl "dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+// BatchInvoke invokes @methodName once for each of the arguments in @argsList, and the response of the i-th
+// invocation is filled into @replies[i]. At most @concurrency invocations are in flight at the same time,
+// and all of them are in flight if it isn't positive.
+// The attachments of the proxy and @ctx are resolved once for the whole batch, while every invocation
+// still goes through the invoker chain on its own since the filters and the load balance, e.g. the tps
+// limiter and the least active one, keep their states per invocation.
+// The returned results are in the order of @argsList, and the failure of an invocation, including a panic,
+// is reported by its result only without affecting the other ones.
+func (p *Proxy) BatchInvoke(ctx context.Context, methodName string, argsList [][]interface{}, replies []interface{}, concurrency int) []protocol.Result {
+	results := make([]protocol.Result, len(argsList))
+	if len(replies) != len(argsList) {
+		err := perrors.Errorf("the number of replies %d doesn't match the number of invocations %d", len(replies), len(argsList))
+		for i := range results {
+			results[i] = &protocol.RPCResult{Err: err}
+		}
+		return results
+	}
+	if concurrency <= 0 || concurrency > len(argsList) {
+		concurrency = len(argsList)
+	}
+
+	prototype := invocation_impl.NewRPCInvocationWithOptions(invocation_impl.WithMethodName(methodName))
+	p.setAttachments(ctx, prototype)
+	attachments := prototype.Attachments()
+
+	var wg sync.WaitGroup
+	tokens := make(chan struct{}, concurrency)
+	for i := range argsList {
+		select {
+		case tokens <- struct{}{}:
+		case <-ctx.Done():
+			results[i] = &protocol.RPCResult{Err: ctx.Err()}
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				if e := recover(); e != nil {
+					results[i] = &protocol.RPCResult{Err: perrors.Errorf("batch invocation %d panics: %v", i, e)}
+				}
+				<-tokens
+				wg.Done()
+			}()
+			results[i] = p.invokeBatchItem(ctx, methodName, argsList[i], replies[i], attachments)
+		}(i)
+	}
+	wg.Wait()
+	return results
+}
+
+func (p *Proxy) invokeBatchItem(ctx context.Context, methodName string, args []interface{}, reply interface{},
+	attachments map[string]interface{}) protocol.Result {
+	argValues := make([]reflect.Value, len(args))
+	for i, arg := range args {
+		argValues[i] = reflect.ValueOf(arg)
+	}
+	inv := invocation_impl.NewRPCInvocationWithOptions(invocation_impl.WithMethodName(methodName),
+		invocation_impl.WithArguments(args), invocation_impl.WithParameterValues(argValues),
+		invocation_impl.WithReply(reply), invocation_impl.WithCallBack(p.callback))
+	// every invocation owns a copy since the filters may change the attachments
+	for k, v := range attachments {
+		inv.SetAttachments(k, v)
+	}
+	return p.invoke.Invoke(ctx, inv)
+}
@@ -0,0 +1,105 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+type batchInvoker struct {
+	protocol.BaseInvoker
+	inFlight    int32
+	maxInFlight int32
+}
+
+func (bi *batchInvoker) Invoke(_ context.Context, inv protocol.Invocation) protocol.Result {
+	inFlight := atomic.AddInt32(&bi.inFlight, 1)
+	defer atomic.AddInt32(&bi.inFlight, -1)
+	for {
+		max := atomic.LoadInt32(&bi.maxInFlight)
+		if inFlight <= max || atomic.CompareAndSwapInt32(&bi.maxInFlight, max, inFlight) {
+			break
+		}
+	}
+	time.Sleep(time.Millisecond)
+
+	key := inv.Arguments()[0].(int)
+	switch {
+	case key == 7:
+		panic("broken key")
+	case key%5 == 0:
+		return &protocol.RPCResult{Err: perrors.Errorf("key %d not found", key)}
+	}
+	*inv.Reply().(*string) = fmt.Sprintf("%v-%v-%d", inv.Attachment("token"), inv.Attachment("user"), key)
+	return &protocol.RPCResult{Rest: inv.Reply()}
+}
+
+func TestProxyBatchInvoke(t *testing.T) {
+	invoker := &batchInvoker{BaseInvoker: *protocol.NewBaseInvoker(&common.URL{})}
+	p := NewProxy(invoker, nil, map[string]string{"token": "t"})
+
+	const count = 50
+	argsList := make([][]interface{}, count)
+	replies := make([]interface{}, count)
+	for i := 0; i < count; i++ {
+		argsList[i] = []interface{}{i}
+		replies[i] = new(string)
+	}
+	ctx := context.WithValue(context.Background(), constant.AttachmentKey, map[string]string{"user": "u"})
+	results := p.BatchInvoke(ctx, "Get", argsList, replies, 8)
+
+	assert.Len(t, results, count)
+	assert.LessOrEqual(t, atomic.LoadInt32(&invoker.maxInFlight), int32(8))
+	for i, result := range results {
+		switch {
+		case i == 7:
+			assert.Contains(t, result.Error().Error(), "broken key")
+		case i%5 == 0:
+			assert.EqualError(t, result.Error(), fmt.Sprintf("key %d not found", i))
+			assert.Equal(t, "", *replies[i].(*string))
+		default:
+			assert.NoError(t, result.Error())
+			assert.Equal(t, fmt.Sprintf("t-u-%d", i), *replies[i].(*string))
+		}
+	}
+}
+
+func TestProxyBatchInvokeMismatchedReplies(t *testing.T) {
+	p := NewProxy(&batchInvoker{BaseInvoker: *protocol.NewBaseInvoker(&common.URL{})}, nil, nil)
+	results := p.BatchInvoke(context.Background(), "Get", [][]interface{}{{1}, {2}}, []interface{}{new(string)}, 0)
+	assert.Len(t, results, 2)
+	for _, result := range results {
+		assert.Error(t, result.Error())
+	}
+}
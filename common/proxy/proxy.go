@@ -35,6 +35,7 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/common/logger"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 	invocation_impl "dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
 )
 
 // nolint
@@ -110,6 +111,94 @@ func (p *Proxy) GetInvoker() protocol.Invoker {
 	return p.invoke
 }
 
+// AsyncInvoke invokes @methodName with @args by the non-blocking transport without waiting for the response,
+// the invocation goes through the same invoker chain, including the consumer filters, as the synchronous one.
+// The returned channel receives the result once the response arrives, the invocation fails or times out,
+// and @reply is filled with the response of the successful invocation.
+func (p *Proxy) AsyncInvoke(ctx context.Context, methodName string, args []interface{}, reply interface{}) <-chan protocol.Result {
+	resultCh := make(chan protocol.Result, 1)
+	if reply == nil {
+		resultCh <- &protocol.RPCResult{Err: protocol.ErrNoReply}
+		return resultCh
+	}
+	var once sync.Once
+	done := func(result protocol.Result) {
+		once.Do(func() {
+			resultCh <- result
+		})
+	}
+
+	argValues := make([]reflect.Value, len(args))
+	for i, arg := range args {
+		argValues[i] = reflect.ValueOf(arg)
+	}
+	inv := invocation_impl.NewRPCInvocationWithOptions(invocation_impl.WithMethodName(methodName),
+		invocation_impl.WithArguments(args), invocation_impl.WithParameterValues(argValues),
+		invocation_impl.WithReply(reply),
+		invocation_impl.WithCallBack(func(response common.CallbackResponse) {
+			done(toAsyncResult(response))
+		}))
+	p.setAttachments(ctx, inv)
+	setDeadline(ctx, inv)
+	inv.SetAttachments(constant.ASYNC_KEY, "true")
+
+	// the response arrives by the callback unless the request isn't sent
+	if result := p.invoke.Invoke(ctx, inv); result.Error() != nil {
+		done(result)
+	}
+	return resultCh
+}
+
+// setAttachments puts the attachments of the proxy and the ones carried by @ctx into @inv
+func (p *Proxy) setAttachments(ctx context.Context, inv *invocation_impl.RPCInvocation) {
+	for k, value := range p.attachments {
+		inv.SetAttachments(k, value)
+	}
+
+	// add user setAttachment. It is compatibility with previous versions.
+	atm := ctx.Value(constant.AttachmentKey)
+	if m, ok := atm.(map[string]string); ok {
+		for k, value := range m {
+			inv.SetAttachments(k, value)
+		}
+	} else if m2, ok2 := atm.(map[string]interface{}); ok2 {
+		// it is support to transfer map[string]interface{}. It refers to dubbo-java 2.7.
+		for k, value := range m2 {
+			inv.SetAttachments(k, value)
+		}
+	}
+}
+
+// setDeadline records the deadline of @ctx into the attributes of @inv, so that the invokers without the context,
+// e.g. the directories, are bounded by it as well
+func setDeadline(ctx context.Context, inv *invocation_impl.RPCInvocation) {
+	if deadline, ok := ctx.Deadline(); ok {
+		inv.SetAttribute(constant.CALL_DEADLINE_KEY, deadline)
+	}
+}
+
+// toAsyncResult converts the response notified to the callback of the async invocation into the result
+func toAsyncResult(response common.CallbackResponse) protocol.Result {
+	result := &protocol.RPCResult{}
+	callbackResponse, ok := response.(remoting.AsyncCallbackResponse)
+	if !ok {
+		result.Err = perrors.Errorf("unexpected async response %T", response)
+		return result
+	}
+	if callbackResponse.Cause != nil {
+		result.Err = callbackResponse.Cause
+		return result
+	}
+	if rsp, ok := callbackResponse.Reply.(*remoting.Response); ok {
+		if rpcResult, ok := rsp.Result.(*protocol.RPCResult); ok {
+			result.Rest = rpcResult.Rest
+			result.Attrs = rpcResult.Attrs
+			result.Err = rpcResult.Err
+		}
+	}
+	return result
+}
+
 // DefaultProxyImplementFunc the default function for proxy impl
 func DefaultProxyImplementFunc(p *Proxy, v common.RPCService) {
 	// check parameters, incoming interface must be a elem's pointer.
@@ -186,22 +275,8 @@ func DefaultProxyImplementFunc(p *Proxy, v common.RPCService) {
 				inv.SetReply(reply.Interface())
 			}
 
-			for k, value := range p.attachments {
-				inv.SetAttachments(k, value)
-			}
-
-			// add user setAttachment. It is compatibility with previous versions.
-			atm := invCtx.Value(constant.AttachmentKey)
-			if m, ok := atm.(map[string]string); ok {
-				for k, value := range m {
-					inv.SetAttachments(k, value)
-				}
-			} else if m2, ok2 := atm.(map[string]interface{}); ok2 {
-				// it is support to transfer map[string]interface{}. It refers to dubbo-java 2.7.
-				for k, value := range m2 {
-					inv.SetAttachments(k, value)
-				}
-			}
+			p.setAttachments(invCtx, inv)
+			setDeadline(invCtx, inv)
 
 			result := p.invoke.Invoke(invCtx, inv)
 			err = result.Error()
@@ -65,11 +65,28 @@ func (factory *DefaultProxyFactory) GetProxy(invoker protocol.Invoker, url *comm
 // GetAsyncProxy gets a async proxy
 func (factory *DefaultProxyFactory) GetAsyncProxy(invoker protocol.Invoker, callBack interface{}, url *common.URL) *proxy.Proxy {
 	// create proxy
-	attachments := map[string]string{}
+	attachments := templateAttachments(url)
 	attachments[constant.ASYNC_KEY] = url.GetParam(constant.ASYNC_KEY, "false")
+	// the provider identifies the consumer by it, e.g. the acl filter
+	if application := url.GetParam(constant.APPLICATION_KEY, ""); application != "" {
+		attachments[constant.REMOTE_APPLICATION_KEY] = application
+	}
 	return proxy.NewProxy(invoker, callBack, attachments)
 }
 
+// templateAttachments collects the attachment template of the reference from the url into a new map,
+// which is owned by the proxy and copied into every invocation, so the template is never mutated by the calls
+func templateAttachments(url *common.URL) map[string]string {
+	attachments := map[string]string{}
+	url.RangeParams(func(key, value string) bool {
+		if strings.HasPrefix(key, constant.ATTACHMENT_TEMPLATE_PREFIX) {
+			attachments[strings.TrimPrefix(key, constant.ATTACHMENT_TEMPLATE_PREFIX)] = value
+		}
+		return true
+	})
+	return attachments
+}
+
 // GetInvoker gets a invoker
 func (factory *DefaultProxyFactory) GetInvoker(url *common.URL) protocol.Invoker {
 	return &ProxyInvoker{
@@ -98,7 +115,7 @@ func (pi *ProxyInvoker) Invoke(ctx context.Context, invocation protocol.Invocati
 	// get service
 	svc := common.ServiceMap.GetServiceByServiceKey(proto, url.ServiceKey())
 	if svc == nil {
-		logger.Errorf("cannot find service [%s] in %s", path, proto)
+		logger.FromContext(ctx).Errorf("cannot find service [%s] in %s", path, proto)
 		result.SetError(perrors.Errorf("cannot find service [%s] in %s", path, proto))
 		return result
 	}
@@ -106,7 +123,7 @@ func (pi *ProxyInvoker) Invoke(ctx context.Context, invocation protocol.Invocati
 	// get method
 	method := svc.Method()[methodName]
 	if method == nil {
-		logger.Errorf("cannot find method [%s] of service [%s] in %s", methodName, path, proto)
+		logger.FromContext(ctx).Errorf("cannot find method [%s] of service [%s] in %s", methodName, path, proto)
 		result.SetError(perrors.Errorf("cannot find method [%s] of service [%s] in %s", methodName, path, proto))
 		return result
 	}
@@ -158,9 +175,37 @@ func (pi *ProxyInvoker) Invoke(ctx context.Context, invocation protocol.Invocati
 			result.SetResult(replyv.Interface())
 		}
 	}
+	return interceptResult(ctx, pi, invocation, result)
+}
+
+// interceptResult applies the result interceptors in order to @result returned by the business method,
+// and the panic of an interceptor is recovered into the error of the result
+func interceptResult(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation,
+	result protocol.Result) protocol.Result {
+	for _, interceptor := range extension.GetResultInterceptors() {
+		var ok bool
+		if result, ok = applyResultInterceptor(ctx, interceptor, invoker, invocation, result); !ok {
+			break
+		}
+	}
 	return result
 }
 
+func applyResultInterceptor(ctx context.Context, interceptor protocol.ResultInterceptor, invoker protocol.Invoker,
+	invocation protocol.Invocation, result protocol.Result) (intercepted protocol.Result, ok bool) {
+	defer func() {
+		if e := recover(); e != nil {
+			logger.FromContext(ctx).Errorf("result interceptor %T panics on %s: %v", interceptor, invocation.MethodName(), e)
+			result.SetError(perrors.Errorf("result interceptor %T panics: %v", interceptor, e))
+			intercepted, ok = result, false
+		}
+	}()
+	if intercepted = interceptor.Intercept(ctx, invoker, invocation, result); intercepted == nil {
+		intercepted = result
+	}
+	return intercepted, true
+}
+
 func getProviderURL(url *common.URL) *common.URL {
 	if url.SubURL == nil {
 		return url
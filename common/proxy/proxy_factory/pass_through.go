@@ -55,8 +55,12 @@ func (factory *PassThroughProxyFactory) GetProxy(invoker protocol.Invoker, url *
 // GetAsyncProxy gets a async proxy
 func (factory *PassThroughProxyFactory) GetAsyncProxy(invoker protocol.Invoker, callBack interface{}, url *common.URL) *proxy.Proxy {
 	//create proxy
-	attachments := map[string]string{}
+	attachments := templateAttachments(url)
 	attachments[constant.ASYNC_KEY] = url.GetParam(constant.ASYNC_KEY, "false")
+	// the provider identifies the consumer by it, e.g. the acl filter
+	if application := url.GetParam(constant.APPLICATION_KEY, ""); application != "" {
+		attachments[constant.REMOTE_APPLICATION_KEY] = application
+	}
 	return proxy.NewProxy(invoker, callBack, attachments)
 }
 
@@ -114,10 +118,10 @@ func (pi *PassThroughProxyInvoker) Invoke(ctx context.Context, invocation protoc
 
 	if retErr != nil {
 		result.SetError(retErr.(error))
-		return result
+		return interceptResult(ctx, pi, invocation, result)
 	}
 	if replyv.IsValid() && (replyv.Kind() != reflect.Ptr || replyv.Kind() == reflect.Ptr && replyv.Elem().IsValid()) {
 		result.SetResult(replyv.Interface())
 	}
-	return result
+	return interceptResult(ctx, pi, invocation, result)
 }
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 )
 
 import (
@@ -157,3 +158,32 @@ func (bi *TestProxyInvoker) Invoke(_ context.Context, inv protocol.Invocation) p
 		Rest: inv.Arguments(),
 	}
 }
+
+type deadlineInvoker struct {
+	protocol.BaseInvoker
+	deadline interface{}
+}
+
+func (di *deadlineInvoker) Invoke(_ context.Context, inv protocol.Invocation) protocol.Result {
+	di.deadline = inv.AttributeByKey(constant.CALL_DEADLINE_KEY, nil)
+	return &protocol.RPCResult{}
+}
+
+func TestProxyImplementForDeadline(t *testing.T) {
+	invoker := &deadlineInvoker{BaseInvoker: *protocol.NewBaseInvoker(&common.URL{})}
+	p := NewProxy(invoker, nil, nil)
+	s := &TestService{}
+	p.Implement(s)
+
+	// the deadline of the context is recorded into the invocation
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	deadline, _ := ctx.Deadline()
+	_, err := s.MethodSix(ctx, "xxx")
+	assert.NoError(t, err)
+	assert.Equal(t, deadline, invoker.deadline)
+
+	_, err = s.MethodSix(context.Background(), "xxx")
+	assert.NoError(t, err)
+	assert.Nil(t, invoker.deadline)
+}
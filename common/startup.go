@@ -0,0 +1,61 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	readyOnce sync.Once
+	readyCh   = make(chan struct{})
+	// the number of the registrations deferred by the startup.delay
+	deferredStartups int32
+)
+
+// MarkReady is the readiness callback which signals that the provider has warmed up and is ready to
+// accept traffic, the registrations deferred by the startup.delay are performed at once.
+func MarkReady() {
+	readyOnce.Do(func() {
+		close(readyCh)
+	})
+}
+
+// ReadyNotify returns the channel which is closed once MarkReady is called
+func ReadyNotify() <-chan struct{} {
+	return readyCh
+}
+
+// DeferStartup records a registration deferred until the provider is ready,
+// the returned function must be called once the registration is done.
+func DeferStartup() func() {
+	atomic.AddInt32(&deferredStartups, 1)
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			atomic.AddInt32(&deferredStartups, -1)
+		})
+	}
+}
+
+// IsReady checks whether there isn't any registration deferred,
+// the provider is serving but not ready for the real traffic if it returns false.
+func IsReady() bool {
+	return atomic.LoadInt32(&deferredStartups) == 0
+}
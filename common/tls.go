@@ -0,0 +1,173 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+)
+
+// IsTLSEnabled checks whether the TLS params are configured in the @url
+func IsTLSEnabled(url *URL) bool {
+	return len(url.GetParam(constant.TLS_CERT_FILE_KEY, "")) > 0 ||
+		len(url.GetParam(constant.TLS_CA_FILE_KEY, "")) > 0 ||
+		url.GetParamBool(constant.TLS_INSECURE_SKIP_VERIFY_KEY, false)
+}
+
+// NewClientTLSConfig builds the tls config used to dial the server by the TLS params of the @url.
+// The server certificate is verified by the CA file if it's configured, or the system CA pool otherwise.
+func NewClientTLSConfig(url *URL) (*tls.Config, error) {
+	config := &tls.Config{
+		ServerName:         url.GetParam(constant.TLS_SERVER_NAME_KEY, ""),
+		InsecureSkipVerify: url.GetParamBool(constant.TLS_INSECURE_SKIP_VERIFY_KEY, false),
+	}
+	reloader, err := newCertificateReloader(url)
+	if err != nil {
+		return nil, err
+	}
+	if reloader != nil {
+		config.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return reloader.certificate(), nil
+		}
+	}
+	if config.RootCAs, err = loadCertPool(url); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// NewServerTLSConfig builds the tls config of the server by the TLS params of the @url,
+// the certificates of the clients are required and verified if the CA file is configured.
+// The certificate is reloaded once its files change, so the rotated one is presented by the new handshakes
+// while the established connections are unaffected.
+func NewServerTLSConfig(url *URL) (*tls.Config, error) {
+	reloader, err := newCertificateReloader(url)
+	if err != nil {
+		return nil, err
+	}
+	if reloader == nil {
+		return nil, perrors.Errorf("the server certificate isn't configured by %s", constant.TLS_CERT_FILE_KEY)
+	}
+	config := &tls.Config{
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return reloader.certificate(), nil
+		},
+		ClientAuth: tls.NoClientCert,
+	}
+	if config.ClientCAs, err = loadCertPool(url); err != nil {
+		return nil, err
+	}
+	if config.ClientCAs != nil {
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config, nil
+}
+
+// certificateReloader holds the certificate loaded from the cert and key files, and reloads it
+// on the handshake after either of the files is modified, e.g. by the certificate rotation
+type certificateReloader struct {
+	certFile string
+	keyFile  string
+
+	lock    sync.Mutex
+	modTime [2]time.Time
+	cert    *tls.Certificate
+}
+
+// newCertificateReloader loads the certificate configured by @url, and it's nil if there isn't one
+func newCertificateReloader(url *URL) (*certificateReloader, error) {
+	certFile := url.GetParam(constant.TLS_CERT_FILE_KEY, "")
+	keyFile := url.GetParam(constant.TLS_KEY_FILE_KEY, "")
+	if len(certFile) == 0 && len(keyFile) == 0 {
+		return nil, nil
+	}
+	r := &certificateReloader{certFile: certFile, keyFile: keyFile}
+	modTime, err := r.modTimes()
+	if err != nil {
+		return nil, err
+	}
+	if err = r.load(modTime); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// certificate returns the latest certificate, and keeps the previous one if the files can't be reloaded,
+// e.g. they are being written, which is retried on the next handshake
+func (r *certificateReloader) certificate() *tls.Certificate {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	modTime, err := r.modTimes()
+	if err == nil && modTime != r.modTime {
+		err = r.load(modTime)
+	}
+	if err != nil {
+		logger.Warnf("Could not reload the certificate, the previous one is used, error: %v", err)
+	}
+	return r.cert
+}
+
+func (r *certificateReloader) load(modTime [2]time.Time) error {
+	certificate, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return perrors.WithMessagef(err, "load the certificate {%s} and the key {%s}", r.certFile, r.keyFile)
+	}
+	r.cert, r.modTime = &certificate, modTime
+	return nil
+}
+
+func (r *certificateReloader) modTimes() ([2]time.Time, error) {
+	var modTime [2]time.Time
+	for i, file := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(file)
+		if err != nil {
+			return modTime, perrors.WithMessagef(err, "load the certificate {%s} and the key {%s}", r.certFile, r.keyFile)
+		}
+		modTime[i] = info.ModTime()
+	}
+	return modTime, nil
+}
+
+func loadCertPool(url *URL) (*x509.CertPool, error) {
+	caFile := url.GetParam(constant.TLS_CA_FILE_KEY, "")
+	if len(caFile) == 0 {
+		return nil, nil
+	}
+	caPem, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, perrors.WithMessagef(err, "read the CA file {%s}", caFile)
+	}
+	certPool := x509.NewCertPool()
+	if !certPool.AppendCertsFromPEM(caPem) {
+		return nil, perrors.Errorf("no valid certificate in the CA file {%s}", caFile)
+	}
+	return certPool, nil
+}
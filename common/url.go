@@ -703,6 +703,17 @@ func MergeURL(serviceURL *URL, referenceURL *URL) *URL {
 	// loadBalance,cluster,retries strategy config
 	methodConfigMergeFcn := mergeNormalParam(params, referenceURL, []string{constant.LOADBALANCE_KEY, constant.CLUSTER_KEY, constant.RETRIES_KEY, constant.TIMEOUT_KEY})
 
+	// the merged url is on the side of the reference in its application, e.g. the consumer side overrides apply to it
+	if v := referenceURL.GetParam(constant.SIDE_KEY, ""); len(v) > 0 {
+		params[constant.SIDE_KEY] = []string{v}
+	}
+	if v := referenceURL.GetParam(constant.APPLICATION_KEY, ""); len(v) > 0 {
+		if remote := serviceURL.GetParam(constant.APPLICATION_KEY, ""); len(remote) > 0 {
+			params[constant.REMOTE_APPLICATION_KEY] = []string{remote}
+		}
+		params[constant.APPLICATION_KEY] = []string{v}
+	}
+
 	// remote timestamp
 	if v := serviceURL.GetParam(constant.TIMESTAMP_KEY, ""); len(v) > 0 {
 		params[constant.REMOTE_TIMESTAMP_KEY] = []string{v}
@@ -309,9 +309,13 @@ func TestMergeUrl(t *testing.T) {
 	referenceUrlParams.Set(constant.CLUSTER_KEY, "random")
 	referenceUrlParams.Set(constant.RETRIES_KEY, "1")
 	referenceUrlParams.Set("test3", "1")
+	referenceUrlParams.Set(constant.SIDE_KEY, "consumer")
+	referenceUrlParams.Set(constant.APPLICATION_KEY, "consumer-app")
 	referenceUrlParams.Set("methods.testMethod."+constant.RETRIES_KEY, "1")
 	serviceUrlParams := url.Values{}
 	serviceUrlParams.Set("test2", "1")
+	serviceUrlParams.Set(constant.SIDE_KEY, "provider")
+	serviceUrlParams.Set(constant.APPLICATION_KEY, "provider-app")
 	serviceUrlParams.Set(constant.CLUSTER_KEY, "roundrobin")
 	serviceUrlParams.Set(constant.RETRIES_KEY, "2")
 	serviceUrlParams.Set(constant.METHOD_KEYS+".testMethod."+constant.RETRIES_KEY, "2")
@@ -322,6 +326,9 @@ func TestMergeUrl(t *testing.T) {
 	assert.Equal(t, "random", mergedUrl.GetParam(constant.CLUSTER_KEY, ""))
 	assert.Equal(t, "1", mergedUrl.GetParam("test2", ""))
 	assert.Equal(t, "1", mergedUrl.GetParam("test3", ""))
+	assert.Equal(t, "consumer", mergedUrl.GetParam(constant.SIDE_KEY, ""))
+	assert.Equal(t, "consumer-app", mergedUrl.GetParam(constant.APPLICATION_KEY, ""))
+	assert.Equal(t, "provider-app", mergedUrl.GetParam(constant.REMOTE_APPLICATION_KEY, ""))
 	assert.Equal(t, "1", mergedUrl.GetParam(constant.RETRIES_KEY, ""))
 	assert.Equal(t, "2", mergedUrl.GetParam(constant.METHOD_KEYS+".testMethod."+constant.RETRIES_KEY, ""))
 }
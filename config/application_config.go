@@ -37,6 +37,13 @@ type ApplicationConfig struct {
 	Environment  string `default:"dev" yaml:"environment" json:"environment,omitempty" property:"environment"`
 	// the metadata type. remote or local
 	MetadataType string `default:"local" yaml:"metadata-type" json:"metadataType,omitempty" property:"metadataType"`
+	// the locality where the instance is deployed, e.g. the availability zone
+	Locality string `yaml:"locality" json:"locality,omitempty" property:"locality"`
+	// the color of the stack the instance belongs to, e.g. blue or green, which the consumers pin to
+	Color string `yaml:"color" json:"color,omitempty" property:"color"`
+	// the health check url of the instance probed by the consumers checking the providers actively,
+	// e.g. http://10.0.0.1:8080/health, or :8080/health on the host of the instance
+	HealthCheckURL string `yaml:"health-check-url" json:"health-check-url,omitempty" property:"health-check-url"`
 }
 
 // Prefix dubbo.application
@@ -105,6 +112,21 @@ func (acb *ApplicationConfigBuilder) SetMetadataType(metadataType string) *Appli
 	return acb
 }
 
+func (acb *ApplicationConfigBuilder) SetLocality(locality string) *ApplicationConfigBuilder {
+	acb.application.Locality = locality
+	return acb
+}
+
+func (acb *ApplicationConfigBuilder) SetColor(color string) *ApplicationConfigBuilder {
+	acb.application.Color = color
+	return acb
+}
+
+func (acb *ApplicationConfigBuilder) SetHealthCheckURL(healthCheckURL string) *ApplicationConfigBuilder {
+	acb.application.HealthCheckURL = healthCheckURL
+	return acb
+}
+
 func (acb *ApplicationConfigBuilder) Build() *ApplicationConfig {
 	return acb.application
 }
@@ -162,7 +162,12 @@ func (c *CenterConfig) CreateDynamicConfiguration() (config_center.DynamicConfig
 }
 
 func (c *CenterConfig) GetDynamicConfiguration() (config_center.DynamicConfiguration, error) {
+	if _, ok := c.DynamicConfiguration.(*config_center.SwitchableDynamicConfiguration); ok {
+		return c.DynamicConfiguration, nil
+	}
 	if c.DynamicConfiguration != nil {
+		// the one set directly is wrapped as well, so that the listeners added afterwards are moved on reloading
+		c.DynamicConfiguration = config_center.NewSwitchableDynamicConfiguration(c.DynamicConfiguration)
 		return c.DynamicConfiguration, nil
 	}
 	dynamicConfig, err := c.CreateDynamicConfiguration()
@@ -170,8 +175,61 @@ func (c *CenterConfig) GetDynamicConfiguration() (config_center.DynamicConfigura
 		logger.Warnf("Create dynamic configuration error , error message is %v", err)
 		return nil, errors.WithStack(err)
 	}
-	c.DynamicConfiguration = dynamicConfig
-	return dynamicConfig, nil
+	// the listeners are kept by the switchable one to be moved to the new source once the config center is reloaded
+	c.DynamicConfiguration = config_center.NewSwitchableDynamicConfiguration(dynamicConfig)
+	return c.DynamicConfiguration, nil
+}
+
+// Reload switches the config center to the one of @to at runtime, e.g. a new address, and @to is applied to c.
+// The listeners are moved to the new config center and notified of the values differing between the two ones,
+// while the config center in use keeps serving until the new one is ready, and it's kept if the new one fails.
+// The properties of the data id from the new config center are not applied to the root config loaded already.
+// The config centers sharing the process-global client, e.g. apollo, can't be reloaded from or to.
+func (c *CenterConfig) Reload(to *CenterConfig) error {
+	if err := to.check(); err != nil {
+		return err
+	}
+	// the new one is never created, since it would take over the client of the one in use
+	for _, protocol := range []string{c.Protocol, to.Protocol} {
+		if isExclusiveConfigCenter(protocol) {
+			return errors.Errorf("reload config center %s://%s error: config center %s can't be reloaded at runtime",
+				to.Protocol, to.Address, protocol)
+		}
+	}
+	dynamicConfig, err := to.CreateDynamicConfiguration()
+	if err != nil {
+		return errors.WithMessagef(err, "reload config center %s://%s error", to.Protocol, to.Address)
+	}
+	if _, err = dynamicConfig.GetProperties(to.DataId, config_center.WithGroup(to.Group)); err != nil {
+		config_center.DestroyDynamicConfiguration(dynamicConfig)
+		return errors.WithMessagef(err, "reload config center %s://%s error", to.Protocol, to.Address)
+	}
+
+	switchable, ok := c.DynamicConfiguration.(*config_center.SwitchableDynamicConfiguration)
+	switch {
+	case ok:
+		switchable.Switch(dynamicConfig)
+	case c.DynamicConfiguration != nil:
+		// the one set directly isn't got by anyone yet, so it's destroyed without any listener to move
+		switchable = config_center.NewSwitchableDynamicConfiguration(c.DynamicConfiguration)
+		switchable.Switch(dynamicConfig)
+	default:
+		switchable = config_center.NewSwitchableDynamicConfiguration(dynamicConfig)
+	}
+	*c = *to
+	c.DynamicConfiguration = switchable
+	conf.GetEnvInstance().SetDynamicConfiguration(switchable)
+	logger.Infof("Config center is reloaded to %s://%s", c.Protocol, c.Address)
+	return nil
+}
+
+// isExclusiveConfigCenter tells whether the config centers of @protocol share the process-global client
+func isExclusiveConfigCenter(protocol string) bool {
+	if protocol == "" {
+		return false
+	}
+	_, ok := extension.GetConfigCenterFactory(protocol).(config_center.ExclusiveDynamicConfigurationFactory)
+	return ok
 }
 
 func (c *CenterConfig) prepareEnvironment() (string, error) {
@@ -18,15 +18,28 @@
 package config
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 )
 
 import (
+	perrors "github.com/pkg/errors"
+
 	"github.com/stretchr/testify/assert"
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
 	_ "dubbo.apache.org/dubbo-go/v3/config_center/apollo"
+	"dubbo.apache.org/dubbo-go/v3/config_center/file"
+	"dubbo.apache.org/dubbo-go/v3/config_center/parser"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
 )
 
 func TestApolloConfigCenterConfig(t *testing.T) {
@@ -37,3 +50,233 @@ func TestApolloConfigCenterConfig(t *testing.T) {
 	registries := rootConfig.Registries
 	assert.NotNil(t, registries)
 }
+
+// reloadDynamicConfiguration serves the values by the keys, ignoring the groups
+type reloadDynamicConfiguration struct {
+	config_center.DynamicConfiguration
+	mutex     sync.Mutex
+	values    map[string]string
+	listeners map[string]config_center.ConfigurationListener
+	destroyed bool
+}
+
+func newReloadDynamicConfiguration(values map[string]string) *reloadDynamicConfiguration {
+	return &reloadDynamicConfiguration{values: values, listeners: map[string]config_center.ConfigurationListener{}}
+}
+
+func (r *reloadDynamicConfiguration) Parser() parser.ConfigurationParser {
+	return &parser.DefaultConfigurationParser{}
+}
+
+func (r *reloadDynamicConfiguration) AddListener(key string, listener config_center.ConfigurationListener, _ ...config_center.Option) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.listeners[key] = listener
+}
+
+func (r *reloadDynamicConfiguration) RemoveListener(key string, _ config_center.ConfigurationListener, _ ...config_center.Option) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.listeners, key)
+}
+
+func (r *reloadDynamicConfiguration) GetProperties(key string, _ ...config_center.Option) (string, error) {
+	if value, ok := r.values[key]; ok {
+		return value, nil
+	}
+	return "", perrors.Errorf("key %s not found", key)
+}
+
+func (r *reloadDynamicConfiguration) Destroy() {
+	r.destroyed = true
+}
+
+func (r *reloadDynamicConfiguration) listening(key string) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	_, ok := r.listeners[key]
+	return ok
+}
+
+type reloadConfigCenterFactory struct {
+	dynamicConfig config_center.DynamicConfiguration
+	err           error
+}
+
+func (f *reloadConfigCenterFactory) GetDynamicConfiguration(_ *common.URL) (config_center.DynamicConfiguration, error) {
+	return f.dynamicConfig, f.err
+}
+
+type reloadListener struct {
+	events map[string]*config_center.ConfigChangeEvent
+}
+
+func (l *reloadListener) Process(event *config_center.ConfigChangeEvent) {
+	l.events[event.Key] = event
+}
+
+func TestCenterConfigReload(t *testing.T) {
+	oldConfig := newReloadDynamicConfiguration(map[string]string{"data": "", "same": "1", "changed": "a", "removed": "x"})
+	newConfig := newReloadDynamicConfiguration(map[string]string{"data": "", "same": "1", "changed": "b", "added": "y"})
+	extension.SetConfigCenterFactory("reload-old", func() config_center.DynamicConfigurationFactory {
+		return &reloadConfigCenterFactory{dynamicConfig: oldConfig}
+	})
+	extension.SetConfigCenterFactory("reload-new", func() config_center.DynamicConfigurationFactory {
+		return &reloadConfigCenterFactory{dynamicConfig: newConfig}
+	})
+	extension.SetConfigCenterFactory("reload-broken", func() config_center.DynamicConfigurationFactory {
+		return &reloadConfigCenterFactory{err: perrors.New("connection refused")}
+	})
+
+	cc := &CenterConfig{Protocol: "reload-old", Address: "127.0.0.1:1", DataId: "data"}
+	assert.Nil(t, cc.check())
+	dynamicConfig, err := cc.GetDynamicConfiguration()
+	assert.Nil(t, err)
+	listener := &reloadListener{events: map[string]*config_center.ConfigChangeEvent{}}
+	for _, key := range []string{"same", "changed", "removed", "added"} {
+		dynamicConfig.AddListener(key, listener, config_center.WithGroup("dubbo"))
+	}
+
+	// the config center in use keeps serving if the new one fails
+	err = cc.Reload(&CenterConfig{Protocol: "reload-broken", Address: "127.0.0.1:2", DataId: "data"})
+	assert.Error(t, err)
+	assert.Equal(t, "reload-old", cc.Protocol)
+	value, err := cc.DynamicConfiguration.GetProperties("changed")
+	assert.Nil(t, err)
+	assert.Equal(t, "a", value)
+	assert.Empty(t, listener.events)
+
+	// and the new one without the data id is destroyed
+	noData := newReloadDynamicConfiguration(map[string]string{})
+	extension.SetConfigCenterFactory("reload-no-data", func() config_center.DynamicConfigurationFactory {
+		return &reloadConfigCenterFactory{dynamicConfig: noData}
+	})
+	err = cc.Reload(&CenterConfig{Protocol: "reload-no-data", Address: "127.0.0.1:2", DataId: "data"})
+	assert.Error(t, err)
+	assert.True(t, noData.destroyed)
+	assert.Equal(t, "reload-old", cc.Protocol)
+	assert.Empty(t, listener.events)
+
+	err = cc.Reload(&CenterConfig{Protocol: "reload-new", Address: "127.0.0.1:3", DataId: "data"})
+	assert.Nil(t, err)
+	assert.Equal(t, "reload-new", cc.Protocol)
+	assert.Equal(t, "127.0.0.1:3", cc.Address)
+	assert.Equal(t, dynamicConfig, cc.DynamicConfiguration)
+	assert.True(t, oldConfig.destroyed)
+
+	value, err = dynamicConfig.GetProperties("changed")
+	assert.Nil(t, err)
+	assert.Equal(t, "b", value)
+	for _, key := range []string{"same", "changed", "removed", "added"} {
+		assert.True(t, newConfig.listening(key))
+		assert.False(t, oldConfig.listening(key))
+	}
+
+	assert.Len(t, listener.events, 3)
+	assert.NotContains(t, listener.events, "same")
+	assert.Equal(t, &config_center.ConfigChangeEvent{Key: "changed", Value: "b", ConfigType: remoting.EventTypeUpdate},
+		listener.events["changed"])
+	assert.Equal(t, &config_center.ConfigChangeEvent{Key: "removed", Value: "", ConfigType: remoting.EventTypeDel},
+		listener.events["removed"])
+	assert.Equal(t, &config_center.ConfigChangeEvent{Key: "added", Value: "y", ConfigType: remoting.EventTypeAdd},
+		listener.events["added"])
+}
+
+func TestCenterConfigReloadDirectlySet(t *testing.T) {
+	oldConfig := newReloadDynamicConfiguration(map[string]string{"data": "", "changed": "a"})
+	newConfig := newReloadDynamicConfiguration(map[string]string{"data": "", "changed": "b"})
+	extension.SetConfigCenterFactory("reload-direct", func() config_center.DynamicConfigurationFactory {
+		return &reloadConfigCenterFactory{dynamicConfig: newConfig}
+	})
+
+	// the one set directly is wrapped once it's got, so that its listeners are moved on reloading
+	cc := &CenterConfig{Protocol: "reload-old", Address: "127.0.0.1:1", DataId: "data", DynamicConfiguration: oldConfig}
+	dynamicConfig, err := cc.GetDynamicConfiguration()
+	assert.Nil(t, err)
+	assert.IsType(t, &config_center.SwitchableDynamicConfiguration{}, dynamicConfig)
+	listener := &reloadListener{events: map[string]*config_center.ConfigChangeEvent{}}
+	dynamicConfig.AddListener("changed", listener)
+	assert.True(t, oldConfig.listening("changed"))
+
+	err = cc.Reload(&CenterConfig{Protocol: "reload-direct", Address: "127.0.0.1:2", DataId: "data"})
+	assert.Nil(t, err)
+	assert.True(t, oldConfig.destroyed)
+	assert.False(t, oldConfig.listening("changed"))
+	assert.True(t, newConfig.listening("changed"))
+	assert.Equal(t, &config_center.ConfigChangeEvent{Key: "changed", Value: "b", ConfigType: remoting.EventTypeUpdate},
+		listener.events["changed"])
+
+	// the one set directly and never got is destroyed on reloading
+	unused := newReloadDynamicConfiguration(map[string]string{"data": ""})
+	cc = &CenterConfig{Protocol: "reload-old", Address: "127.0.0.1:1", DataId: "data", DynamicConfiguration: unused}
+	assert.Nil(t, cc.Reload(&CenterConfig{Protocol: "reload-direct", Address: "127.0.0.1:2", DataId: "data"}))
+	assert.True(t, unused.destroyed)
+}
+
+func TestCenterConfigReloadApollo(t *testing.T) {
+	inUse := newReloadDynamicConfiguration(map[string]string{"data": ""})
+	extension.SetConfigCenterFactory("reload-apollo", func() config_center.DynamicConfigurationFactory {
+		return &reloadConfigCenterFactory{dynamicConfig: newReloadDynamicConfiguration(map[string]string{"data": ""})}
+	})
+
+	// apollo is neither reloaded to nor from, since its client is shared by the process
+	cc := &CenterConfig{Protocol: "reload-apollo", Address: "127.0.0.1:1", DataId: "data", DynamicConfiguration: inUse}
+	err := cc.Reload(&CenterConfig{Protocol: "apollo", Address: "127.0.0.1:8080", DataId: "data"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "config center apollo can't be reloaded at runtime")
+	assert.Equal(t, "reload-apollo", cc.Protocol)
+
+	cc = &CenterConfig{Protocol: "apollo", Address: "127.0.0.1:8080", DataId: "data", DynamicConfiguration: inUse}
+	err = cc.Reload(&CenterConfig{Protocol: "reload-apollo", Address: "127.0.0.1:1", DataId: "data"})
+	assert.Error(t, err)
+	assert.Equal(t, "apollo", cc.Protocol)
+	assert.Equal(t, inUse, cc.DynamicConfiguration)
+	assert.False(t, inUse.destroyed)
+}
+
+// fileReloadListener sends the events notified by the file config centers
+type fileReloadListener struct {
+	events chan *config_center.ConfigChangeEvent
+}
+
+func (l *fileReloadListener) Process(event *config_center.ConfigChangeEvent) {
+	l.events <- event
+}
+
+func TestCenterConfigReloadFile(t *testing.T) {
+	oldDir, newDir := t.TempDir(), t.TempDir()
+	for dir, value := range map[string]string{oldDir: "a", newDir: "b"} {
+		assert.NoError(t, os.MkdirAll(filepath.Join(dir, "dubbo"), os.ModePerm))
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "dubbo", "data"), []byte(""), os.ModePerm))
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "dubbo", "changed"), []byte(value), os.ModePerm))
+	}
+	newCenterConfig := func(dir string) *CenterConfig {
+		return &CenterConfig{Protocol: "file", Address: "127.0.0.1:0", DataId: "data",
+			Params: map[string]string{file.ConfigCenterDirParamName: dir}}
+	}
+
+	cc := newCenterConfig(oldDir)
+	assert.Nil(t, cc.check())
+	dynamicConfig, err := cc.GetDynamicConfiguration()
+	assert.Nil(t, err)
+	listener := &fileReloadListener{events: make(chan *config_center.ConfigChangeEvent, 16)}
+	dynamicConfig.AddListener("changed", listener, config_center.WithGroup("dubbo"))
+
+	// the listener is notified of the value changed by the new directory, and it watches the new one afterwards
+	assert.Nil(t, cc.Reload(newCenterConfig(newDir)))
+	assert.Equal(t, &config_center.ConfigChangeEvent{Key: "changed", Value: "b", ConfigType: remoting.EventTypeUpdate},
+		<-listener.events)
+	value, err := dynamicConfig.GetProperties("changed", config_center.WithGroup("dubbo"))
+	assert.Nil(t, err)
+	assert.Equal(t, "b", value)
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(oldDir, "dubbo", "changed"), []byte("x"), os.ModePerm))
+	newPath := filepath.Join(newDir, "dubbo", "changed")
+	assert.NoError(t, ioutil.WriteFile(newPath, []byte("c"), os.ModePerm))
+	select {
+	case event := <-listener.events:
+		assert.Equal(t, newPath, event.Key)
+	case <-time.After(3 * time.Second):
+		assert.Fail(t, "the change of the new directory isn't notified")
+	}
+}
@@ -101,25 +101,44 @@ func registerServiceInstance() {
 //// nolint
 func createInstance(url *common.URL) (registry.ServiceInstance, error) {
 	appConfig := GetApplicationConfig()
-	port, err := strconv.ParseInt(url.Port, 10, 32)
-	if err != nil {
-		return nil, perrors.WithMessage(err, "invalid port: "+url.Port)
-	}
-
 	host := url.Ip
 	if len(host) == 0 {
 		host = common.GetLocalIp()
 	}
+	rawPort := url.Port
+	// the instance is registered with the advertised address instead of the bound one if it's configured
+	if advertisedHost := url.GetParam(constant.ADVERTISED_HOST_KEY, ""); len(advertisedHost) > 0 {
+		host = advertisedHost
+	}
+	if advertisedPort := url.GetParam(constant.ADVERTISED_PORT_KEY, ""); len(advertisedPort) > 0 {
+		rawPort = advertisedPort
+	}
+	port, err := strconv.ParseInt(rawPort, 10, 32)
+	if err != nil {
+		return nil, perrors.WithMessage(err, "invalid port: "+rawPort)
+	}
 
 	// usually we will add more metadata
 	metadata := make(map[string]string, 8)
 	metadata[constant.METADATA_STORAGE_TYPE_PROPERTY_NAME] = appConfig.MetadataType
+	if len(appConfig.Locality) > 0 {
+		metadata[constant.LOCALITY_KEY] = appConfig.Locality
+	}
+	if len(appConfig.Color) > 0 {
+		metadata[constant.COLOR_KEY] = appConfig.Color
+	}
+	if len(appConfig.HealthCheckURL) > 0 {
+		metadata[constant.HEALTH_CHECK_URL_KEY] = appConfig.HealthCheckURL
+	}
+	if len(appConfig.Version) > 0 {
+		metadata[constant.PROVIDER_APP_VERSION_KEY] = appConfig.Version
+	}
 
 	instance := &registry.DefaultServiceInstance{
 		ServiceName: appConfig.Name,
 		Host:        host,
 		Port:        int(port),
-		ID:          host + constant.KEY_SEPARATOR + url.Port,
+		ID:          host + constant.KEY_SEPARATOR + rawPort,
 		Enable:      true,
 		Healthy:     true,
 		Metadata:    metadata,
@@ -0,0 +1,53 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+func TestCreateInstanceWithAdvertisedAddress(t *testing.T) {
+	defer func(old *RootConfig) { rootConfig = old }(rootConfig)
+	rootConfig = NewRootConfigBuilder().SetApplication(NewApplicationConfigBuilder().SetName("user-app").Build()).Build()
+
+	url, err := common.NewURL("dubbo://0.0.0.0:20880/com.ikurento.user.UserProvider")
+	assert.NoError(t, err)
+	instance, err := createInstance(url)
+	assert.NoError(t, err)
+	assert.Equal(t, "0.0.0.0", instance.GetHost())
+	assert.Equal(t, 20880, instance.GetPort())
+
+	// the instance is registered with the advertised address while the server binds the local one
+	url.SetParam(constant.ADVERTISED_HOST_KEY, "203.0.113.10")
+	url.SetParam(constant.ADVERTISED_PORT_KEY, "30880")
+	instance, err = createInstance(url)
+	assert.NoError(t, err)
+	assert.Equal(t, "203.0.113.10", instance.GetHost())
+	assert.Equal(t, 30880, instance.GetPort())
+	assert.Equal(t, "203.0.113.10:30880", instance.GetID())
+	assert.Equal(t, "0.0.0.0:20880", url.Location)
+}
@@ -0,0 +1,180 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/directory"
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+// fallbackInvoker invokes the providers from the registries once they're available, and the fallback ones
+// of the direct urls otherwise, e.g. the registries can't be reached at startup.
+type fallbackInvoker struct {
+	protocol.BaseInvoker
+	fallback protocol.Invoker
+	// registry holds the protocol.Invoker of the registries once they're referred
+	registry atomic.Value
+	// referred is closed once the registries are referred
+	referred      chan struct{}
+	usingFallback int32
+	done          chan struct{}
+	destroyOnce   sync.Once
+}
+
+// newFallbackInvoker creates the invoker referring the registries by @refer, which is retried in the background
+// until it succeeds, and the invocations go to @fallback meanwhile
+func newFallbackInvoker(url *common.URL, fallback protocol.Invoker, refer func() protocol.Invoker) *fallbackInvoker {
+	fi := &fallbackInvoker{
+		BaseInvoker: *protocol.NewBaseInvoker(url),
+		fallback:    fallback,
+		referred:    make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	if err := fi.refer(refer); err != nil {
+		logger.Warnf("Refer the registries of %s error: %v, using the fallback urls until they recover", url.Path, err)
+		go fi.retry(url.GetParamDuration(constant.FALLBACK_RETRY_INTERVAL_KEY, constant.DEFAULT_FALLBACK_RETRY_INTERVAL), refer)
+	}
+	return fi
+}
+
+func (fi *fallbackInvoker) refer(refer func() protocol.Invoker) (err error) {
+	defer func() {
+		// the registry protocol panics if it can't connect to the registry
+		if e := recover(); e != nil {
+			err = perrors.Errorf("%v", e)
+		}
+	}()
+	invoker := refer()
+	if invoker == nil {
+		return perrors.New("no invoker is referred")
+	}
+	fi.registry.Store(invoker)
+	close(fi.referred)
+	return nil
+}
+
+func (fi *fallbackInvoker) retry(interval time.Duration, refer func() protocol.Invoker) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-fi.done:
+			return
+		case <-ticker.C:
+			err := fi.refer(refer)
+			if err == nil {
+				logger.Infof("Refer the registries of %s successfully", fi.GetURL().Path)
+				return
+			}
+			logger.Debugf("Retry to refer the registries of %s error: %v", fi.GetURL().Path, err)
+		}
+	}
+}
+
+func (fi *fallbackInvoker) registryInvoker() protocol.Invoker {
+	registry, _ := fi.registry.Load().(protocol.Invoker)
+	return registry
+}
+
+// listed returns the invoker of the registries once they're referred, or the fallback one
+func (fi *fallbackInvoker) listed() protocol.Invoker {
+	if registry := fi.registryInvoker(); registry != nil {
+		return registry
+	}
+	return fi.fallback
+}
+
+// current returns the invoker of the registries if any of their providers is available, or the fallback one
+func (fi *fallbackInvoker) current() protocol.Invoker {
+	if registry := fi.registryInvoker(); registry != nil && registry.IsAvailable() {
+		if atomic.CompareAndSwapInt32(&fi.usingFallback, 1, 0) {
+			logger.Infof("The providers of %s from the registries are available, switch back to them", fi.GetURL().Path)
+		}
+		return registry
+	}
+	if atomic.CompareAndSwapInt32(&fi.usingFallback, 0, 1) {
+		logger.Warnf("The providers of %s from the registries are unavailable, switch to the fallback urls", fi.GetURL().Path)
+	}
+	return fi.fallback
+}
+
+// Invoke invokes the providers from the registries or the fallback ones
+func (fi *fallbackInvoker) Invoke(ctx context.Context, invocation protocol.Invocation) protocol.Result {
+	return fi.current().Invoke(ctx, invocation)
+}
+
+// IsAvailable tells whether either the providers from the registries or the fallback ones are available
+func (fi *fallbackInvoker) IsAvailable() bool {
+	if registry := fi.registryInvoker(); registry != nil && registry.IsAvailable() {
+		return true
+	}
+	return fi.fallback.IsAvailable()
+}
+
+// Addresses returns the addresses of the providers from the registries once they're referred, or the fallback ones
+func (fi *fallbackInvoker) Addresses() []directory.Address {
+	return directory.ResolveAddresses([]protocol.Invoker{fi.listed()})
+}
+
+// WarmUp connects to the providers from the registries once they're referred, or the fallback ones in advance
+func (fi *fallbackInvoker) WarmUp(ctx context.Context, top int) error {
+	return directory.WarmUpInvokers(ctx, []protocol.Invoker{fi.listed()}, top)
+}
+
+// WaitReady waits until the registries are referred and at least @min providers from them are available,
+// since the fallback providers are used only while the registries are unavailable
+func (fi *fallbackInvoker) WaitReady(ctx context.Context, min int) error {
+	select {
+	case <-fi.referred:
+	case <-fi.done:
+		return perrors.Errorf("the reference %s is destroyed", fi.GetURL().Path)
+	case <-ctx.Done():
+		return perrors.Wrapf(ctx.Err(), "the registries of %s are not referred", fi.GetURL().Path)
+	}
+	registry := fi.registryInvoker()
+	if waiter, ok := registry.(directory.ReadyWaiter); ok {
+		return waiter.WaitReady(ctx, min)
+	}
+	return directory.WaitInvokersReady(ctx, []protocol.Invoker{registry}, min)
+}
+
+// Destroy stops the retries and destroys both of the invokers
+func (fi *fallbackInvoker) Destroy() {
+	fi.destroyOnce.Do(func() {
+		close(fi.done)
+		if registry := fi.registryInvoker(); registry != nil {
+			registry.Destroy()
+		}
+		fi.fallback.Destroy()
+		fi.BaseInvoker.Destroy()
+	})
+}
@@ -19,6 +19,7 @@ package config
 
 import (
 	"net/url"
+	"strings"
 )
 
 import (
@@ -34,6 +35,8 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/common/logger"
 	"dubbo.apache.org/dubbo-go/v3/common/yaml"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
 )
 
 type ZapConfig struct {
@@ -51,6 +54,8 @@ type ZapConfig struct {
 type LoggerConfig struct {
 	LumberjackConfig *lumberjack.Logger `yaml:"lumberjack-config" json:"lumberjack-config,omitempty" property:"lumberjack-config"`
 	ZapConfig        ZapConfig          `yaml:"zap-config" json:"zap-config,omitempty" property:"zap-config"`
+	// LevelKey is the config center key of the level overrides, it's <application>.logger-level by default
+	LevelKey string `yaml:"level-key" json:"level-key,omitempty" property:"level-key"`
 }
 
 type EncoderConfig struct {
@@ -96,6 +101,30 @@ func (lc *LoggerConfig) Init() error {
 	return nil
 }
 
+// subscribeLevel listens to the level overrides of the logger in the config center of @rc. The overrides are
+// the properties like dubbo.apache.org/dubbo-go/v3/registry=debug, which are applied to the packages and
+// their sub packages, and the level of the whole logger is overridden by the root one. The configured
+// level is restored once the overrides are cleared.
+func (lc *LoggerConfig) subscribeLevel(rc *RootConfig) {
+	if rc.ConfigCenter == nil || rc.ConfigCenter.DynamicConfiguration == nil {
+		return
+	}
+	dynamicConfig := rc.ConfigCenter.DynamicConfiguration
+	key := lc.LevelKey
+	if key == "" {
+		key = rc.Application.Name + constant.LoggerLevelSuffix
+	}
+	listener := &loggerLevelListener{defaultLevel: lc.ZapConfig.Level}
+	dynamicConfig.AddListener(key, listener, config_center.WithGroup(rc.ConfigCenter.Group))
+	value, err := dynamicConfig.GetProperties(key, config_center.WithGroup(rc.ConfigCenter.Group))
+	if err != nil {
+		// the overrides may not be published yet
+		logger.Debugf("Can not get the logger level overrides for key=%s, error=%v", key, err)
+		return
+	}
+	listener.apply(value)
+}
+
 func (lc *LoggerConfig) check() error {
 	if err := defaults.Set(lc); err != nil {
 		return err
@@ -145,6 +174,52 @@ func (lc *LoggerConfig) getUrlMap() url.Values {
 	return urlMap
 }
 
+// loggerLevelListener applies the level overrides of the logger pushed by the config center
+type loggerLevelListener struct {
+	defaultLevel string
+}
+
+// Process applies the overrides of @event, and restores the configured level once they are removed
+func (l *loggerLevelListener) Process(event *config_center.ConfigChangeEvent) {
+	value, _ := event.Value.(string)
+	if event.ConfigType == remoting.EventTypeDel {
+		value = ""
+	}
+	l.apply(value)
+}
+
+func (l *loggerLevelListener) apply(value string) {
+	logger.ResetPackageLoggerLevels()
+	rootLevel := l.defaultLevel
+	for _, line := range strings.Split(value, "\n") {
+		pkg, level, ok := parseLevelOverride(line)
+		if !ok {
+			continue
+		}
+		if pkg == constant.LoggerRootLevel {
+			rootLevel = level
+			continue
+		}
+		if !logger.SetPackageLoggerLevel(pkg, level) {
+			logger.Warnf("Invalid logger level %s of the package %s", level, pkg)
+		}
+	}
+	logger.SetLoggerLevel(rootLevel)
+}
+
+// parseLevelOverride parses the line like package=level, the empty lines and the comments are skipped
+func parseLevelOverride(line string) (string, string, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", "", false
+	}
+	pair := strings.SplitN(line, "=", 2)
+	if len(pair) != 2 {
+		return "", "", false
+	}
+	return strings.TrimSpace(pair[0]), strings.TrimSpace(pair[1]), true
+}
+
 type LoggerConfigBuilder struct {
 	loggerConfig *LoggerConfig
 }
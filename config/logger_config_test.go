@@ -18,6 +18,8 @@
 package config
 
 import (
+	"io/ioutil"
+	"path/filepath"
 	"testing"
 )
 
@@ -27,6 +29,8 @@ import (
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
 )
 
 func TestLoggerInit(t *testing.T) {
@@ -70,3 +74,52 @@ func TestLoggerInit(t *testing.T) {
 		logger.Errorf("%s", "error")
 	})
 }
+
+func TestLoggerLevelOverrides(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "dubbo.log")
+	loggerConfig := NewLoggerConfigBuilder().Build()
+	assert.NoError(t, loggerConfig.check())
+	loggerConfig.ZapConfig.OutputPaths = []string{logPath}
+	assert.NoError(t, loggerConfig.Init())
+	defer logger.InitLogger(nil)
+
+	readLog := func() string {
+		content, err := ioutil.ReadFile(logPath)
+		assert.NoError(t, err)
+		return string(content)
+	}
+	listener := &loggerLevelListener{defaultLevel: loggerConfig.ZapConfig.Level}
+
+	logger.Debug("debug before the override")
+	// the override of this package enables the debug logs
+	listener.Process(&config_center.ConfigChangeEvent{
+		Value:      "dubbo.apache.org/dubbo-go/v3/config=debug\ndubbo.apache.org/dubbo-go/v3/registry=error",
+		ConfigType: remoting.EventTypeUpdate,
+	})
+	logger.Debug("debug with the override")
+	logger.Info("info with the override")
+
+	// the configured level is restored once the override is cleared
+	listener.Process(&config_center.ConfigChangeEvent{ConfigType: remoting.EventTypeDel})
+	logger.Debug("debug after the override")
+	logger.Info("info after the override")
+
+	content := readLog()
+	assert.NotContains(t, content, "debug before the override")
+	assert.Contains(t, content, "debug with the override")
+	assert.Contains(t, content, "info with the override")
+	assert.NotContains(t, content, "debug after the override")
+	assert.Contains(t, content, "info after the override")
+
+	// the root level overrides the whole logger except for the packages overridden
+	listener.Process(&config_center.ConfigChangeEvent{
+		Value:      "root=error\ndubbo.apache.org/dubbo-go/v3/config/generic=debug",
+		ConfigType: remoting.EventTypeUpdate,
+	})
+	logger.Warn("warn with the root override")
+	listener.Process(&config_center.ConfigChangeEvent{Value: "", ConfigType: remoting.EventTypeUpdate})
+	logger.Warn("warn after the root override")
+	content = readLog()
+	assert.NotContains(t, content, "warn with the root override")
+	assert.Contains(t, content, "warn after the root override")
+}
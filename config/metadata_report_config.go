@@ -37,6 +37,12 @@ type MetadataReportConfig struct {
 	Password string `yaml:"password" json:"password,omitempty"`
 	Timeout  string `yaml:"timeout" json:"timeout,omitempty"`
 	Group    string `yaml:"group" json:"group,omitempty"`
+	// RegistryFallback registers the instance with its metadata in the metadata report instead of inline
+	// once the registry rejects the metadata for its size, only nacos supports it at present
+	RegistryFallback bool `yaml:"registry-fallback" json:"registry-fallback,omitempty"`
+	// CacheFile is the file caching the fetched metadata, which is read once the metadata report is down,
+	// it's ~/.dubbo/dubbo-metadata-<address>.cache by default
+	CacheFile string `yaml:"cache-file" json:"cache-file,omitempty"`
 	// metadataType of this application is defined by application config, local or remote
 	metadataType string
 }
@@ -66,6 +72,9 @@ func (mc *MetadataReportConfig) ToUrl() (*common.URL, error) {
 		return nil, perrors.New("Invalid MetadataReport Config.")
 	}
 	res.SetParam("metadata", res.Protocol)
+	if mc.CacheFile != "" {
+		res.SetParam(constant.METADATA_CACHE_FILE_KEY, mc.CacheFile)
+	}
 	return res, nil
 }
 
@@ -172,6 +181,12 @@ func (mrcb *MetadataReportConfigBuilder) SetGroup(group string) *MetadataReportC
 	return mrcb
 }
 
+// nolint
+func (mrcb *MetadataReportConfigBuilder) SetCacheFile(cacheFile string) *MetadataReportConfigBuilder {
+	mrcb.metadataReportConfig.CacheFile = cacheFile
+	return mrcb
+}
+
 // nolint
 func (mrcb *MetadataReportConfigBuilder) Build() *MetadataReportConfig {
 	// TODO Init
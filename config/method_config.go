@@ -40,6 +40,15 @@ type MethodConfig struct {
 	ExecuteLimitRejectedHandler string `yaml:"execute.limit.rejected.handler" json:"execute.limit.rejected.handler,omitempty" property:"execute.limit.rejected.handler"`
 	Sticky                      bool   `yaml:"sticky"   json:"sticky,omitempty" property:"sticky"`
 	RequestTimeout              string `yaml:"timeout"  json:"timeout,omitempty" property:"timeout"`
+	// Idempotent tells whether the method is safe to be retried, "true" or "false", and the get-style methods
+	// are idempotent if it's empty
+	Idempotent string `yaml:"idempotent"  json:"idempotent,omitempty" property:"idempotent"`
+	// Return tells whether the consumer awaits the response of the method, "true" or "false", and the
+	// invocations are oneway ones with "false", e.g. for the fire-and-forget notifications
+	Return string `yaml:"return"  json:"return,omitempty" property:"return"`
+	// LenientCollection tells whether the malformed elements of the list or map replied by the method are skipped,
+	// "true" or "false", and the whole response fails with any of them by default
+	LenientCollection string `yaml:"lenient-collection"  json:"lenient-collection,omitempty" property:"lenient-collection"`
 }
 
 // nolint
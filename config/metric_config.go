@@ -36,6 +36,10 @@ type MetricConfig struct {
 	Port               string `default:"9090" yaml:"port" json:"port,omitempty" property:"port"`
 	Path               string `default:"/metrics" yaml:"path" json:"path,omitempty" property:"path"`
 	PushGatewayAddress string `default:"" yaml:"push-gateway-address" json:"push-gateway-address,omitempty" property:"push-gateway-address"`
+	// Protocol is the name of the reporter, e.g. prometheus or datadog
+	Protocol string `default:"prometheus" yaml:"protocol" json:"protocol,omitempty" property:"protocol"`
+	// Address is the address of the agent the metrics are pushed to, e.g. the DogStatsD one of datadog
+	Address string `yaml:"address" json:"address,omitempty" property:"address"`
 }
 
 func (m *MetricConfig) ToReporterConfig() *metrics.ReporterConfig {
@@ -51,6 +55,10 @@ func (m *MetricConfig) ToReporterConfig() *metrics.ReporterConfig {
 	defaultMetricsReportConfig.Port = m.Port
 	defaultMetricsReportConfig.Path = m.Path
 	defaultMetricsReportConfig.PushGatewayAddress = m.PushGatewayAddress
+	if m.Protocol != "" {
+		defaultMetricsReportConfig.Protocol = m.Protocol
+	}
+	defaultMetricsReportConfig.Address = m.Address
 	return defaultMetricsReportConfig
 }
 
@@ -65,7 +73,7 @@ func (mc *MetricConfig) Init() error {
 	if err := verify(mc); err != nil {
 		return err
 	}
-	extension.GetMetricReporter("prometheus", mc.ToReporterConfig())
+	extension.GetMetricReporter(mc.Protocol, mc.ToReporterConfig())
 	return nil
 }
 
@@ -78,6 +86,18 @@ func NewMetricConfigBuilder() *MetricConfigBuilder {
 	return &MetricConfigBuilder{metricConfig: &MetricConfig{}}
 }
 
+// nolint
+func (mcb *MetricConfigBuilder) SetProtocol(protocol string) *MetricConfigBuilder {
+	mcb.metricConfig.Protocol = protocol
+	return mcb
+}
+
+// nolint
+func (mcb *MetricConfigBuilder) SetAddress(address string) *MetricConfigBuilder {
+	mcb.metricConfig.Address = address
+	return mcb
+}
+
 // nolint
 func (mcb *MetricConfigBuilder) Build() *MetricConfig {
 	return mcb.metricConfig
@@ -0,0 +1,261 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+	"reflect"
+	"sync/atomic"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/directory"
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	invocation_impl "dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+// mirrorInvoker copies the configured percentage of the invocations to the shadow providers in the background,
+// e.g. a new backend before cutting over to it. The results and the errors of the copies are discarded,
+// so that the results of the invocations are the ones of the primary providers only.
+type mirrorInvoker struct {
+	protocol.BaseInvoker
+	primary    protocol.Invoker
+	shadow     protocol.Invoker
+	percentage uint64
+	// requests counts the invocations to spread the mirrored ones evenly
+	requests uint64
+	// copies bounds the copies in flight, so that a slow shadow doesn't pile up the goroutines
+	copies chan struct{}
+}
+
+func newMirrorInvoker(url *common.URL, primary protocol.Invoker, shadow protocol.Invoker) *mirrorInvoker {
+	percentage := url.GetParamInt(constant.MIRROR_PERCENTAGE_KEY, constant.DEFAULT_MIRROR_PERCENTAGE)
+	if percentage < 0 {
+		percentage = 0
+	} else if percentage > 100 {
+		percentage = 100
+	}
+	concurrency := url.GetParamInt(constant.MIRROR_MAX_CONCURRENCY_KEY, constant.DEFAULT_MIRROR_MAX_CONCURRENCY)
+	if concurrency <= 0 {
+		concurrency = constant.DEFAULT_MIRROR_MAX_CONCURRENCY
+	}
+	return &mirrorInvoker{
+		BaseInvoker: *protocol.NewBaseInvoker(url),
+		primary:     primary,
+		shadow:      shadow,
+		percentage:  uint64(percentage),
+		copies:      make(chan struct{}, concurrency),
+	}
+}
+
+// Invoke invokes the primary providers, and copies the invocation to the shadow ones if it's mirrored
+func (mi *mirrorInvoker) Invoke(ctx context.Context, invocation protocol.Invocation) protocol.Result {
+	// the invocation reaching the next percent of the invocations is mirrored
+	n := atomic.AddUint64(&mi.requests, 1)
+	if n*mi.percentage/100 != (n-1)*mi.percentage/100 {
+		select {
+		case mi.copies <- struct{}{}:
+			// the copy is made before the primary invocation since the filters may change the attachments
+			go mi.mirror(copyInvocation(invocation))
+		default:
+			logger.Debugf("Drop the mirrored invocation of %s.%s since %d copies are in flight",
+				mi.GetURL().Path, invocation.MethodName(), cap(mi.copies))
+		}
+	}
+	return mi.primary.Invoke(ctx, invocation)
+}
+
+func (mi *mirrorInvoker) mirror(invocation protocol.Invocation) {
+	var err error
+	defer func() {
+		if e := recover(); e != nil {
+			err = perrors.Errorf("%v", e)
+		}
+		if err != nil {
+			logger.Debugf("Mirror the invocation of %s.%s error: %v", mi.GetURL().Path, invocation.MethodName(), err)
+		}
+		if callback := extension.GetMirrorCallback(); callback != nil {
+			callback(mi, invocation, err)
+		}
+		<-mi.copies
+	}()
+	// the copy isn't bound to the context of the caller, which may be canceled once the primary invocation returns
+	err = mi.shadow.Invoke(context.Background(), invocation).Error()
+}
+
+// copyInvocation copies @invocation with a new reply of the same type to be discarded, and the arguments are
+// copied deeply so that neither the shadow providers nor the primary ones see the changes made by the others
+func copyInvocation(invocation protocol.Invocation) protocol.Invocation {
+	var reply interface{}
+	if r := invocation.Reply(); r != nil {
+		if t := reflect.TypeOf(r); t.Kind() == reflect.Ptr {
+			reply = reflect.New(t.Elem()).Interface()
+		}
+	}
+	attachments := make(map[string]interface{}, len(invocation.Attachments()))
+	for k, v := range invocation.Attachments() {
+		attachments[k] = v
+	}
+	// the values shared by the arguments and the parameter values are copied once
+	copied := make(map[copiedPointer]reflect.Value)
+	var arguments []interface{}
+	if invocation.Arguments() != nil {
+		arguments = deepCopy(reflect.ValueOf(invocation.Arguments()), copied).Interface().([]interface{})
+	}
+	var parameterValues []reflect.Value
+	if invocation.ParameterValues() != nil {
+		parameterValues = make([]reflect.Value, len(invocation.ParameterValues()))
+		for i, v := range invocation.ParameterValues() {
+			parameterValues[i] = v
+			if v.IsValid() && v.CanInterface() {
+				parameterValues[i] = deepCopy(v, copied)
+			}
+		}
+	}
+	inv := invocation_impl.NewRPCInvocationWithOptions(
+		invocation_impl.WithMethodName(invocation.MethodName()),
+		invocation_impl.WithArguments(arguments),
+		invocation_impl.WithParameterTypes(invocation.ParameterTypes()),
+		invocation_impl.WithParameterValues(parameterValues),
+		invocation_impl.WithReply(reply),
+		invocation_impl.WithAttachments(attachments))
+	for k, v := range invocation.Attributes() {
+		inv.SetAttribute(k, v)
+	}
+	return inv
+}
+
+// copiedPointer is a pointer copied, whose type tells the struct from its first field
+type copiedPointer struct {
+	typ reflect.Type
+	ptr uintptr
+}
+
+// deepCopy copies @v recursively, and the pointers copied are kept in @copied to copy the cycles and
+// the shared values once. The unexported fields of the structs are copied shallowly.
+func deepCopy(v reflect.Value, copied map[copiedPointer]reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		key := copiedPointer{typ: v.Type(), ptr: v.Pointer()}
+		if c, ok := copied[key]; ok {
+			return c
+		}
+		c := reflect.New(v.Type().Elem())
+		copied[key] = c
+		c.Elem().Set(deepCopy(v.Elem(), copied))
+		return c
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type()).Elem()
+		c.Set(deepCopy(v.Elem(), copied))
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		copyElements(c, v, copied)
+		return c
+	case reflect.Array:
+		c := reflect.New(v.Type()).Elem()
+		copyElements(c, v, copied)
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			c.SetMapIndex(deepCopy(iter.Key(), copied), deepCopy(iter.Value(), copied))
+		}
+		return c
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		for i := 0; i < c.NumField(); i++ {
+			if field := c.Field(i); field.CanSet() {
+				field.Set(deepCopy(v.Field(i), copied))
+			}
+		}
+		return c
+	}
+	return v
+}
+
+// copyElements copies the elements of the slice or array @src to @dst, and the ones without any reference
+// are copied as a whole
+func copyElements(dst, src reflect.Value, copied map[copiedPointer]reflect.Value) {
+	switch src.Type().Elem().Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Array, reflect.Map, reflect.Struct:
+		for i := 0; i < src.Len(); i++ {
+			dst.Index(i).Set(deepCopy(src.Index(i), copied))
+		}
+	default:
+		reflect.Copy(dst, src)
+	}
+}
+
+// IsAvailable tells whether the primary providers are available
+func (mi *mirrorInvoker) IsAvailable() bool {
+	return mi.primary.IsAvailable()
+}
+
+// Addresses returns the addresses of the primary providers
+func (mi *mirrorInvoker) Addresses() []directory.Address {
+	if lister, ok := mi.primary.(directory.AddressLister); ok {
+		return lister.Addresses()
+	}
+	return nil
+}
+
+// WarmUp connects to the primary providers in advance
+func (mi *mirrorInvoker) WarmUp(ctx context.Context, top int) error {
+	if warmer, ok := mi.primary.(directory.Warmer); ok {
+		return warmer.WarmUp(ctx, top)
+	}
+	return nil
+}
+
+// WaitReady waits until at least @min primary providers are available
+func (mi *mirrorInvoker) WaitReady(ctx context.Context, min int) error {
+	if waiter, ok := mi.primary.(directory.ReadyWaiter); ok {
+		return waiter.WaitReady(ctx, min)
+	}
+	return directory.WaitInvokersReady(ctx, []protocol.Invoker{mi.primary}, min)
+}
+
+// Destroy destroys both of the primary and the shadow invokers
+func (mi *mirrorInvoker) Destroy() {
+	mi.primary.Destroy()
+	mi.shadow.Destroy()
+	mi.BaseInvoker.Destroy()
+}
@@ -0,0 +1,117 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+type mirrorUser struct {
+	Name  string
+	Tags  []string
+	Attrs map[string]interface{}
+	Self  *mirrorUser
+}
+
+func TestCopyInvocation(t *testing.T) {
+	user := &mirrorUser{Name: "u", Tags: []string{"a"}, Attrs: map[string]interface{}{"k": []int{1}}}
+	user.Self = user
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUser"),
+		invocation.WithArguments([]interface{}{user, []byte("raw")}),
+		invocation.WithParameterValues([]reflect.Value{reflect.ValueOf(user), reflect.ValueOf([]byte("raw"))}))
+
+	copied := copyInvocation(inv)
+	copiedUser := copied.Arguments()[0].(*mirrorUser)
+	assert.Equal(t, user, copiedUser)
+	// the arguments and the parameter values share the copies of the same values
+	assert.Same(t, copiedUser, copied.ParameterValues()[0].Interface())
+	assert.Same(t, copiedUser, copiedUser.Self)
+
+	// the changes of the primary providers aren't seen by the copy
+	user.Name = "changed"
+	user.Tags[0] = "changed"
+	user.Attrs["k"].([]int)[0] = 2
+	inv.Arguments()[1].([]byte)[0] = 'R'
+	assert.Equal(t, "u", copiedUser.Name)
+	assert.Equal(t, []string{"a"}, copiedUser.Tags)
+	assert.Equal(t, []int{1}, copiedUser.Attrs["k"])
+	assert.Equal(t, []byte("raw"), copied.Arguments()[1])
+}
+
+type blockingMirrorInvoker struct {
+	protocol.BaseInvoker
+	invoked chan struct{}
+	release chan struct{}
+}
+
+func (bi *blockingMirrorInvoker) Invoke(_ context.Context, _ protocol.Invocation) protocol.Result {
+	bi.invoked <- struct{}{}
+	<-bi.release
+	return &protocol.RPCResult{}
+}
+
+func TestMirrorInvokerDropsCopies(t *testing.T) {
+	url, err := common.NewURL("primary://127.0.0.1:20000/com.ikurento.user.UserProvider",
+		common.WithParamsValue(constant.MIRROR_MAX_CONCURRENCY_KEY, "1"))
+	assert.NoError(t, err)
+	primary := &fallbackTestInvoker{BaseInvoker: *protocol.NewBaseInvoker(url), name: "primary"}
+	shadow := &blockingMirrorInvoker{BaseInvoker: *protocol.NewBaseInvoker(url),
+		invoked: make(chan struct{}, 2), release: make(chan struct{})}
+	mi := newMirrorInvoker(url, primary, shadow)
+	mirrored := make(chan error, 2)
+	extension.SetMirrorCallback(func(_ protocol.Invoker, _ protocol.Invocation, err error) {
+		mirrored <- err
+	})
+	defer extension.SetMirrorCallback(nil)
+
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUser"))
+	assert.NoError(t, mi.Invoke(context.Background(), inv).Error())
+	<-shadow.invoked
+	// the copy beyond the max concurrency is dropped while the primary invocation goes on
+	assert.NoError(t, mi.Invoke(context.Background(), inv).Error())
+	select {
+	case <-shadow.invoked:
+		assert.Fail(t, "the copy beyond the max concurrency is not dropped")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(shadow.release)
+	assert.NoError(t, <-mirrored)
+	// the copies go on once the ones in flight are done
+	assert.Eventually(t, func() bool {
+		return len(mi.copies) == 0
+	}, time.Second, 10*time.Millisecond)
+	assert.NoError(t, mi.Invoke(context.Background(), inv).Error())
+	<-shadow.invoked
+	assert.NoError(t, <-mirrored)
+}
@@ -31,6 +31,15 @@ type ProtocolConfig struct {
 	Ip     string      `yaml:"ip"  json:"ip,omitempty" property:"ip"`
 	Port   string      `default:"20000" yaml:"port" json:"port,omitempty" property:"port"`
 	Params interface{} `yaml:"params" json:"params,omitempty" property:"params"`
+	// the id of the tls config used by the server and the clients of the protocol
+	TLSConfigID string `yaml:"tls-config" json:"tls-config,omitempty" property:"tls-config"`
+	// whether the codec of the protocol pools its buffers, which is true by default. The pool is shared by all
+	// of the servers and clients of the protocol in the process, e.g. the dubbo one.
+	BufferPool *bool `yaml:"buffer-pool" json:"buffer-pool,omitempty" property:"buffer-pool"`
+	// the max size in bytes of the buffers kept by the pool, which is 1048576 by default
+	BufferPoolMaxSize int `yaml:"buffer-pool-max-size" json:"buffer-pool-max-size,omitempty" property:"buffer-pool-max-size"`
+
+	tlsConfig *TLSConfig
 }
 
 // Prefix dubbo.config-center
@@ -77,6 +86,17 @@ func (pcb *ProtocolConfigBuilder) SetParams(params interface{}) *ProtocolConfigB
 	return pcb
 }
 
+func (pcb *ProtocolConfigBuilder) SetTLSConfigID(tlsConfigID string) *ProtocolConfigBuilder {
+	pcb.protocolConfig.TLSConfigID = tlsConfigID
+	return pcb
+}
+
+func (pcb *ProtocolConfigBuilder) SetBufferPool(enabled bool, maxSize int) *ProtocolConfigBuilder {
+	pcb.protocolConfig.BufferPool = &enabled
+	pcb.protocolConfig.BufferPoolMaxSize = maxSize
+	return pcb
+}
+
 func (pcb *ProtocolConfigBuilder) Build() *ProtocolConfig {
 	return pcb.protocolConfig
 }
@@ -44,6 +44,10 @@ type ProviderConfig struct {
 
 	FilterConf interface{}       `yaml:"filter_conf" json:"filter_conf,omitempty" property:"filter_conf"`
 	ConfigType map[string]string `yaml:"config_type" json:"config_type,omitempty" property:"config_type"`
+	// AdvertisedHost is the host registered for the providers instead of the bound one, e.g. the public ip behind NAT
+	AdvertisedHost string `yaml:"advertised-host" json:"advertised-host,omitempty" property:"advertised-host"`
+	// AdvertisedPort is the port registered for the providers instead of the bound one, e.g. the mapped port
+	AdvertisedPort string `yaml:"advertised-port" json:"advertised-port,omitempty" property:"advertised-port"`
 
 	rootConfig *RootConfig
 }
@@ -172,6 +176,18 @@ func (pcb *ProviderConfigBuilder) AddConfigType(key, value string) *ProviderConf
 	return pcb
 }
 
+// nolint
+func (pcb *ProviderConfigBuilder) SetAdvertisedHost(advertisedHost string) *ProviderConfigBuilder {
+	pcb.providerConfig.AdvertisedHost = advertisedHost
+	return pcb
+}
+
+// nolint
+func (pcb *ProviderConfigBuilder) SetAdvertisedPort(advertisedPort string) *ProviderConfigBuilder {
+	pcb.providerConfig.AdvertisedPort = advertisedPort
+	return pcb
+}
+
 // nolint
 func (pcb *ProviderConfigBuilder) SetRootConfig(rootConfig *RootConfig) *ProviderConfigBuilder {
 	pcb.providerConfig.rootConfig = rootConfig
@@ -18,9 +18,11 @@
 package config
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -28,13 +30,17 @@ import (
 	"github.com/creasty/defaults"
 
 	gxstrings "github.com/dubbogo/gost/strings"
+
+	perrors "github.com/pkg/errors"
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/directory"
 	"dubbo.apache.org/dubbo-go/v3/cluster/directory/static"
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
 	"dubbo.apache.org/dubbo-go/v3/common/proxy"
 	"dubbo.apache.org/dubbo-go/v3/config/generic"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
@@ -66,7 +72,27 @@ type ReferenceConfig struct {
 	Generic        string `yaml:"generic"  json:"generic,omitempty" property:"generic"`
 	Sticky         bool   `yaml:"sticky"   json:"sticky,omitempty" property:"sticky"`
 	RequestTimeout string `yaml:"timeout"  json:"timeout,omitempty" property:"timeout"`
-	ForceTag       bool   `yaml:"force.tag"  json:"force.tag,omitempty" property:"force.tag"`
+	// ConnectTimeout bounds the connection establishment to the providers apart from the request timeout,
+	// the connections to a provider address are shared by the references with the timeout of the first one
+	ConnectTimeout string `yaml:"connect-timeout"  json:"connect-timeout,omitempty" property:"connect-timeout"`
+	// ReadTimeout bounds the wait for the responses once the requests are sent, which is bounded by the request timeout
+	ReadTimeout string `yaml:"read-timeout"  json:"read-timeout,omitempty" property:"read-timeout"`
+	ForceTag    bool   `yaml:"force.tag"  json:"force.tag,omitempty" property:"force.tag"`
+	// Observer reference subscribes the providers, but doesn't register or report itself as a consumer
+	Observer bool `yaml:"observer"  json:"observer,omitempty" property:"observer"`
+	// Attachments is the template of the attachments set on every invocation, and the per-call ones override it
+	Attachments map[string]string `yaml:"attachments"  json:"attachments,omitempty" property:"attachments"`
+	// FallbackURLs are the direct urls of the providers used while the registries are unavailable,
+	// and the reference switches back to the providers from the registries once they recover
+	FallbackURLs []string `yaml:"fallback-urls"  json:"fallback-urls,omitempty" property:"fallback-urls"`
+	// MirrorURLs are the direct urls of the shadow providers, to which the mirror.percentage percent of the
+	// invocations are copied without affecting the results of them
+	MirrorURLs []string `yaml:"mirror-urls"  json:"mirror-urls,omitempty" property:"mirror-urls"`
+	// ReadyProviders is the minimum number of the available providers WaitReady waits for, it's 1 by default
+	ReadyProviders int `yaml:"ready-providers"  json:"ready-providers,omitempty" property:"ready-providers"`
+	// HealthCheckInterval is the interval the health check urls advertised by the providers are probed at,
+	// and the failing providers are skipped until they recover. The providers aren't probed without it
+	HealthCheckInterval string `yaml:"health-check-interval"  json:"health-check-interval,omitempty" property:"health-check-interval"`
 
 	rootConfig   *RootConfig
 	metaDataType string
@@ -133,36 +159,101 @@ func (rc *ReferenceConfig) Refer(srv interface{}) {
 				serviceURL.SubURL = cfgURL
 				rc.urls = append(rc.urls, serviceURL)
 			} else { // URL stands for a direct address
-				if serviceURL.Path == "" {
-					serviceURL.Path = "/" + rc.InterfaceName
-				}
-				// merge URL param with cfgURL, others are same as serviceURL
-				newURL := common.MergeURL(serviceURL, cfgURL)
-				rc.urls = append(rc.urls, newURL)
+				rc.urls = append(rc.urls, rc.directURL(serviceURL, cfgURL))
 			}
 		}
-	} else { // use registry configs
-		rc.urls = loadRegistries(rc.RegistryIDs, rc.rootConfig.Registries, common.CONSUMER)
-		// set url to regURLs
-		for _, regURL := range rc.urls {
-			regURL.SubURL = cfgURL
+		rc.invoker = rc.referURLs(rc.urls, true)
+	} else if len(rc.FallbackURLs) == 0 { // use registry configs
+		rc.urls = rc.loadRegistryURLs(cfgURL)
+		rc.invoker = rc.referURLs(rc.urls, false)
+	} else { // use registry configs, and the fallback urls while the registries are unavailable
+		rc.urls = rc.loadRegistryURLs(cfgURL)
+		rc.invoker = newFallbackInvoker(cfgURL, rc.referDirectURLs("fallback", rc.FallbackURLs, cfgURL), func() protocol.Invoker {
+			return rc.referURLs(rc.urls, false)
+		})
+	}
+	if len(rc.MirrorURLs) != 0 {
+		rc.invoker = newMirrorInvoker(cfgURL, rc.invoker, rc.referDirectURLs("mirror", rc.MirrorURLs, cfgURL))
+	}
+
+	if cfgURL.GetParamBool(constant.CONNECTION_WARMUP_KEY, false) {
+		rc.warmUp(cfgURL)
+	}
+
+	// publish consumer's metadata, observer reference has no side effect that makes it appear as a live consumer
+	if !rc.Observer {
+		publishServiceDefinition(cfgURL)
+	}
+	// create proxy
+	if rc.Async {
+		callback := GetCallback(rc.id)
+		rc.pxy = extension.GetProxyFactory(rc.rootConfig.Consumer.ProxyFactory).GetAsyncProxy(rc.invoker, callback, cfgURL)
+	} else {
+		rc.pxy = extension.GetProxyFactory(rc.rootConfig.Consumer.ProxyFactory).GetProxy(rc.invoker, cfgURL)
+	}
+}
+
+// directURL merges the params of @cfgURL into the direct address @serviceURL of the providers
+func (rc *ReferenceConfig) directURL(serviceURL *common.URL, cfgURL *common.URL) *common.URL {
+	if serviceURL.Path == "" {
+		serviceURL.Path = "/" + rc.InterfaceName
+	}
+	// merge URL param with cfgURL, others are same as serviceURL
+	return common.MergeURL(serviceURL, cfgURL)
+}
+
+// loadRegistryURLs loads the urls of the registries subscribed by the reference of @cfgURL
+func (rc *ReferenceConfig) loadRegistryURLs(cfgURL *common.URL) []*common.URL {
+	urls := loadRegistries(rc.RegistryIDs, rc.rootConfig.Registries, common.CONSUMER)
+	// set url to regURLs
+	for _, regURL := range urls {
+		regURL.SubURL = cfgURL
+	}
+	return urls
+}
+
+// referDirectURLs refers @urlStrs, e.g. the fallback or the mirror urls, as the direct addresses of the providers
+func (rc *ReferenceConfig) referDirectURLs(kind string, urlStrs []string, cfgURL *common.URL) protocol.Invoker {
+	urls := make([]*common.URL, 0, len(urlStrs))
+	for _, urlStr := range urlStrs {
+		serviceURL, err := common.NewURL(urlStr)
+		if err != nil {
+			panic(fmt.Sprintf("url configuration error,  please check your configuration, %s URL %v refer error, error message is %v ", kind, urlStr, err.Error()))
 		}
+		urls = append(urls, rc.directURL(serviceURL, cfgURL))
 	}
+	return rc.referURLs(urls, true)
+}
 
-	// Get invokers according to rc.urls
+// referURLs refers @urls and joins the invokers of them, @direct tells whether they are the direct addresses of
+// the providers rather than the registries
+func (rc *ReferenceConfig) referURLs(urls []*common.URL, direct bool) protocol.Invoker {
+	// Get invokers according to urls
 	var (
 		invoker protocol.Invoker
 		regURL  *common.URL
 	)
-	invokers := make([]protocol.Invoker, len(rc.urls))
-	for i, u := range rc.urls {
+	invokers := make([]protocol.Invoker, len(urls))
+	defer func() {
+		// the invokers referred before the panic, e.g. the one of the registry connected, are destroyed
+		// since the reference is retried or abandoned as a whole
+		if e := recover(); e != nil {
+			for _, referred := range invokers {
+				if referred != nil {
+					referred.Destroy()
+				}
+			}
+			panic(e)
+		}
+	}()
+	for i, u := range urls {
 		if u.Protocol == constant.SERVICE_REGISTRY_PROTOCOL {
 			invoker = extension.GetProtocol("registry").Refer(u)
 		} else {
 			invoker = extension.GetProtocol(u.Protocol).Refer(u)
 		}
 
-		if rc.URL != "" {
+		if direct {
 			invoker = protocolwrapper.BuildInvokerChain(invoker, constant.REFERENCE_FILTER_KEY)
 		}
 
@@ -174,13 +265,13 @@ func (rc *ReferenceConfig) Refer(srv interface{}) {
 
 	// TODO(hxmhlt): decouple from directory, config should not depend on directory module
 	if len(invokers) == 1 {
-		rc.invoker = invokers[0]
-		if rc.URL != "" {
+		invoker = invokers[0]
+		if direct {
 			hitClu := constant.ClusterKeyFailover
-			if u := rc.invoker.GetURL(); u != nil {
+			if u := invoker.GetURL(); u != nil {
 				hitClu = u.GetParam(constant.CLUSTER_KEY, constant.ClusterKeyZoneAware)
 			}
-			rc.invoker = extension.GetCluster(hitClu).Join(static.NewDirectory(invokers))
+			invoker = extension.GetCluster(hitClu).Join(static.NewRegisteredDirectory(invokers))
 		}
 	} else {
 		var hitClu string
@@ -194,18 +285,10 @@ func (rc *ReferenceConfig) Refer(srv interface{}) {
 				hitClu = u.GetParam(constant.CLUSTER_KEY, constant.ClusterKeyZoneAware)
 			}
 		}
-		rc.invoker = extension.GetCluster(hitClu).Join(static.NewDirectory(invokers))
+		invoker = extension.GetCluster(hitClu).Join(static.NewRegisteredDirectory(invokers))
 	}
 
-	// publish consumer's metadata
-	publishServiceDefinition(cfgURL)
-	// create proxy
-	if rc.Async {
-		callback := GetCallback(rc.id)
-		rc.pxy = extension.GetProxyFactory(rc.rootConfig.Consumer.ProxyFactory).GetAsyncProxy(rc.invoker, callback, cfgURL)
-	} else {
-		rc.pxy = extension.GetProxyFactory(rc.rootConfig.Consumer.ProxyFactory).GetProxy(rc.invoker, cfgURL)
-	}
+	return invoker
 }
 
 // Implement
@@ -224,12 +307,30 @@ func (rc *ReferenceConfig) GetProxy() *proxy.Proxy {
 	return rc.pxy
 }
 
+// getProtocolTLSConfig gets the tls config of the protocol that the reference uses
+func (rc *ReferenceConfig) getProtocolTLSConfig() *TLSConfig {
+	if rc.rootConfig == nil {
+		return nil
+	}
+	for id, protocolConfig := range rc.rootConfig.Protocols {
+		if (id == rc.Protocol || protocolConfig.Name == rc.Protocol) && protocolConfig.tlsConfig != nil {
+			return protocolConfig.tlsConfig
+		}
+	}
+	return nil
+}
+
 func (rc *ReferenceConfig) getURLMap() url.Values {
 	urlMap := url.Values{}
-	// first set user params
+	// the tls config of the protocol can be overridden by user params
+	rc.getProtocolTLSConfig().appendTo(urlMap)
+	// then set user params
 	for k, v := range rc.Params {
 		urlMap.Set(k, v)
 	}
+	for k, v := range rc.Attachments {
+		urlMap.Set(constant.ATTACHMENT_TEMPLATE_PREFIX+k, v)
+	}
 	urlMap.Set(constant.INTERFACE_KEY, rc.InterfaceName)
 	urlMap.Set(constant.TIMESTAMP_KEY, strconv.FormatInt(time.Now().Unix(), 10))
 	urlMap.Set(constant.CLUSTER_KEY, rc.Cluster)
@@ -241,6 +342,11 @@ func (rc *ReferenceConfig) getURLMap() url.Values {
 	urlMap.Set(constant.ROLE_KEY, strconv.Itoa(common.CONSUMER))
 	urlMap.Set(constant.PROVIDED_BY, rc.ProvidedBy)
 	urlMap.Set(constant.SERIALIZATION_KEY, rc.Serialization)
+	if serializations := strings.Split(rc.Serialization, ","); len(serializations) > 1 {
+		// the serialization used is negotiated with every provider by the preference
+		urlMap.Set(constant.SERIALIZATION_KEY, strings.TrimSpace(serializations[0]))
+		urlMap.Set(constant.PREFER_SERIALIZATION_KEY, rc.Serialization)
+	}
 
 	urlMap.Set(constant.RELEASE_KEY, "dubbo-golang-"+constant.Version)
 	urlMap.Set(constant.SIDE_KEY, (common.RoleType(common.CONSUMER)).Role())
@@ -248,9 +354,21 @@ func (rc *ReferenceConfig) getURLMap() url.Values {
 	if len(rc.RequestTimeout) != 0 {
 		urlMap.Set(constant.TIMEOUT_KEY, rc.RequestTimeout)
 	}
+	if len(rc.ConnectTimeout) != 0 {
+		urlMap.Set(constant.CONNECT_TIMEOUT_KEY, rc.ConnectTimeout)
+	}
+	if len(rc.ReadTimeout) != 0 {
+		urlMap.Set(constant.READ_TIMEOUT_KEY, rc.ReadTimeout)
+	}
+	if len(rc.HealthCheckInterval) != 0 {
+		urlMap.Set(constant.HEALTH_CHECK_INTERVAL_KEY, rc.HealthCheckInterval)
+	}
 	// getty invoke async or sync
 	urlMap.Set(constant.ASYNC_KEY, strconv.FormatBool(rc.Async))
 	urlMap.Set(constant.STICKY_KEY, strconv.FormatBool(rc.Sticky))
+	if rc.Observer {
+		urlMap.Set(constant.OBSERVER_KEY, "true")
+	}
 
 	// applicationConfig info
 	urlMap.Set(constant.APPLICATION_KEY, rc.rootConfig.Application.Name)
@@ -275,6 +393,15 @@ func (rc *ReferenceConfig) getURLMap() url.Values {
 		if len(v.RequestTimeout) != 0 {
 			urlMap.Set("methods."+v.Name+"."+constant.TIMEOUT_KEY, v.RequestTimeout)
 		}
+		if len(v.Idempotent) != 0 {
+			urlMap.Set("methods."+v.Name+"."+constant.IDEMPOTENT_KEY, v.Idempotent)
+		}
+		if len(v.Return) != 0 {
+			urlMap.Set("methods."+v.Name+"."+constant.RETURN_KEY, v.Return)
+		}
+		if len(v.LenientCollection) != 0 {
+			urlMap.Set("methods."+v.Name+"."+constant.SERIALIZATION_LENIENT_COLLECTION_KEY, v.LenientCollection)
+		}
 	}
 
 	return urlMap
@@ -294,6 +421,46 @@ func (rc *ReferenceConfig) GetInvoker() protocol.Invoker {
 	return rc.invoker
 }
 
+// Addresses returns the provider addresses resolved currently for the reference, including the unavailable ones,
+// and they change as the registry notifies. It's empty before the reference is referred.
+func (rc *ReferenceConfig) Addresses() []directory.Address {
+	if lister, ok := rc.invoker.(directory.AddressLister); ok {
+		return lister.Addresses()
+	}
+	return nil
+}
+
+// WaitReady blocks until at least ReadyProviders providers of the reference are available, 1 by default,
+// and returns an error once @ctx is done before that. It's woken up by the notifications of the registries.
+func (rc *ReferenceConfig) WaitReady(ctx context.Context) error {
+	if rc.invoker == nil {
+		return perrors.Errorf("the reference %s is not referred", rc.InterfaceName)
+	}
+	min := rc.ReadyProviders
+	if min <= 0 {
+		min = 1
+	}
+	if waiter, ok := rc.invoker.(directory.ReadyWaiter); ok {
+		return waiter.WaitReady(ctx, min)
+	}
+	return directory.WaitInvokersReady(ctx, []protocol.Invoker{rc.invoker}, min)
+}
+
+// warmUp connects to the providers resolved for the reference before it's used, and the failure is only logged
+// since the providers not connected are connected on demand.
+func (rc *ReferenceConfig) warmUp(url *common.URL) {
+	warmer, ok := rc.invoker.(directory.Warmer)
+	if !ok {
+		return
+	}
+	timeout := url.GetParamDuration(constant.CONNECTION_WARMUP_TIMEOUT_KEY, constant.DEFAULT_CONNECTION_WARMUP_TIMEOUT)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := warmer.WarmUp(ctx, int(url.GetParamInt(constant.CONNECTION_WARMUP_TOP_KEY, 0))); err != nil {
+		logger.Warnf("Warm up the connections of the reference %s error: %v", rc.InterfaceName, err)
+	}
+}
+
 // postProcessConfig asks registered ConfigPostProcessor to post-process the current ReferenceConfig.
 func (rc *ReferenceConfig) postProcessConfig(url *common.URL) {
 	for _, p := range extension.GetConfigPostProcessors() {
@@ -353,6 +520,46 @@ func (pcb *ReferenceConfigBuilder) SetProtocol(protocol string) *ReferenceConfig
 	return pcb
 }
 
+func (pcb *ReferenceConfigBuilder) SetConnectTimeout(connectTimeout string) *ReferenceConfigBuilder {
+	pcb.referenceConfig.ConnectTimeout = connectTimeout
+	return pcb
+}
+
+func (pcb *ReferenceConfigBuilder) SetReadTimeout(readTimeout string) *ReferenceConfigBuilder {
+	pcb.referenceConfig.ReadTimeout = readTimeout
+	return pcb
+}
+
+func (pcb *ReferenceConfigBuilder) SetObserver(observer bool) *ReferenceConfigBuilder {
+	pcb.referenceConfig.Observer = observer
+	return pcb
+}
+
+func (pcb *ReferenceConfigBuilder) SetAttachments(attachments map[string]string) *ReferenceConfigBuilder {
+	pcb.referenceConfig.Attachments = attachments
+	return pcb
+}
+
+func (pcb *ReferenceConfigBuilder) SetFallbackURLs(fallbackURLs ...string) *ReferenceConfigBuilder {
+	pcb.referenceConfig.FallbackURLs = fallbackURLs
+	return pcb
+}
+
+func (pcb *ReferenceConfigBuilder) SetMirrorURLs(mirrorURLs ...string) *ReferenceConfigBuilder {
+	pcb.referenceConfig.MirrorURLs = mirrorURLs
+	return pcb
+}
+
+func (pcb *ReferenceConfigBuilder) SetReadyProviders(readyProviders int) *ReferenceConfigBuilder {
+	pcb.referenceConfig.ReadyProviders = readyProviders
+	return pcb
+}
+
+func (pcb *ReferenceConfigBuilder) SetHealthCheckInterval(healthCheckInterval string) *ReferenceConfigBuilder {
+	pcb.referenceConfig.HealthCheckInterval = healthCheckInterval
+	return pcb
+}
+
 func (pcb *ReferenceConfigBuilder) Build() *ReferenceConfig {
 	return pcb.referenceConfig
 }
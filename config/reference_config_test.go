@@ -17,6 +17,313 @@
 
 package config
 
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.uber.org/atomic"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/cluster"
+	"dubbo.apache.org/dubbo-go/v3/cluster/directory"
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	_ "dubbo.apache.org/dubbo-go/v3/common/proxy/proxy_factory"
+	"dubbo.apache.org/dubbo-go/v3/metadata/service"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+type mockObserverMetadataService struct {
+	service.MetadataService
+	published []*common.URL
+}
+
+func (m *mockObserverMetadataService) PublishServiceDefinition(url *common.URL) error {
+	m.published = append(m.published, url)
+	return nil
+}
+
+type mockObserverProtocol struct {
+	protocol.BaseProtocol
+}
+
+func TestReferenceConfigObserver(t *testing.T) {
+	metadataService := &mockObserverMetadataService{}
+	extension.SetLocalMetadataService(constant.DEFAULT_KEY, func() (service.MetadataService, error) {
+		return metadataService, nil
+	})
+	extension.SetProtocol("observer", func() protocol.Protocol {
+		return &mockObserverProtocol{BaseProtocol: protocol.NewBaseProtocol()}
+	})
+	extension.SetCluster("mock", cluster.NewMockCluster)
+
+	rootConfig := &RootConfig{
+		Application: &ApplicationConfig{Name: "observer-app"},
+		Consumer:    &ConsumerConfig{Filter: "-" + constant.GracefulShutdownConsumerFilterKey},
+	}
+	newReference := func(observer bool) *ReferenceConfig {
+		rc := NewReferenceConfigBuilder().
+			SetInterface("com.ikurento.user.UserProvider").
+			SetCluster("mock").
+			SetObserver(observer).
+			Build()
+		rc.URL = "observer://127.0.0.1:20000"
+		rc.rootConfig = rootConfig
+		return rc
+	}
+
+	observer := newReference(true)
+	observer.Refer(nil)
+	assert.NotNil(t, observer.GetInvoker())
+	assert.True(t, observer.GetInvoker().GetURL().GetParamBool(constant.OBSERVER_KEY, false))
+	assert.Len(t, metadataService.published, 0)
+
+	consumer := newReference(false)
+	consumer.Refer(nil)
+	assert.NotNil(t, consumer.GetInvoker())
+	assert.Len(t, metadataService.published, 1)
+}
+
+type fallbackTestInvoker struct {
+	protocol.BaseInvoker
+	name string
+}
+
+func (fi *fallbackTestInvoker) Invoke(_ context.Context, _ protocol.Invocation) protocol.Result {
+	return &protocol.RPCResult{Rest: fi.name}
+}
+
+type fallbackTestProtocol struct {
+	protocol.BaseProtocol
+	name string
+	up   *atomic.Bool
+}
+
+func (fp *fallbackTestProtocol) Refer(url *common.URL) protocol.Invoker {
+	if fp.up != nil && !fp.up.Load() {
+		panic("registry can not connect success")
+	}
+	return &fallbackTestInvoker{BaseInvoker: *protocol.NewBaseInvoker(url), name: fp.name}
+}
+
+// fallbackTestCluster invokes the first provider in the directory
+type fallbackTestCluster struct{}
+
+func (fallbackTestCluster) Join(dir directory.Directory) protocol.Invoker {
+	return &fallbackTestClusterInvoker{BaseInvoker: *protocol.NewBaseInvoker(dir.GetURL()), dir: dir}
+}
+
+type fallbackTestClusterInvoker struct {
+	protocol.BaseInvoker
+	dir directory.Directory
+}
+
+func (ci *fallbackTestClusterInvoker) IsAvailable() bool {
+	return ci.dir.IsAvailable()
+}
+
+func (ci *fallbackTestClusterInvoker) Invoke(ctx context.Context, inv protocol.Invocation) protocol.Result {
+	return ci.dir.List(inv)[0].Invoke(ctx, inv)
+}
+
+func TestReferenceConfigFallbackURLs(t *testing.T) {
+	extension.SetLocalMetadataService(constant.DEFAULT_KEY, func() (service.MetadataService, error) {
+		return &mockObserverMetadataService{}, nil
+	})
+	registryUp := atomic.NewBool(false)
+	extension.SetProtocol("registry", func() protocol.Protocol {
+		return &fallbackTestProtocol{BaseProtocol: protocol.NewBaseProtocol(), name: "registry", up: registryUp}
+	})
+	extension.SetProtocol("fallback", func() protocol.Protocol {
+		return &fallbackTestProtocol{BaseProtocol: protocol.NewBaseProtocol(), name: "fallback"}
+	})
+	extension.SetCluster("fallback-test", func() cluster.Cluster {
+		return fallbackTestCluster{}
+	})
+
+	rc := NewReferenceConfigBuilder().
+		SetInterface("com.ikurento.user.UserProvider").
+		SetCluster("fallback-test").
+		SetFallbackURLs("fallback://127.0.0.1:20000", "fallback://127.0.0.1:20001").
+		Build()
+	rc.Params[constant.FALLBACK_RETRY_INTERVAL_KEY] = "10ms"
+	rc.rootConfig = &RootConfig{
+		Application: &ApplicationConfig{Name: "fallback-app"},
+		Consumer:    &ConsumerConfig{Filter: "-" + constant.GracefulShutdownConsumerFilterKey},
+		Registries: map[string]*RegistryConfig{
+			"unavailable": {Protocol: "zookeeper", Address: "127.0.0.1:2181"},
+		},
+	}
+	rc.Refer(nil)
+	defer rc.GetInvoker().Destroy()
+
+	invoke := func() interface{} {
+		return rc.GetInvoker().Invoke(context.Background(), invocation.NewRPCInvocation("GetUser", nil, nil)).Result()
+	}
+	// the registry is unavailable at startup
+	assert.True(t, rc.GetInvoker().IsAvailable())
+	assert.Equal(t, "fallback", invoke())
+	assert.Len(t, rc.urls, 1)
+	assert.Equal(t, "127.0.0.1:20000", rc.Addresses()[0].Location)
+
+	// the reference isn't ready until the registry is referred, though the fallback providers are available
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	assert.ErrorIs(t, rc.WaitReady(ctx), context.DeadlineExceeded)
+	cancel()
+	ready := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		ready <- rc.WaitReady(ctx)
+	}()
+	select {
+	case err := <-ready:
+		assert.Fail(t, "the reference is ready before the registry is referred", "%v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// and the reference adopts the providers from the registry once it comes up
+	registryUp.Store(true)
+	select {
+	case err := <-ready:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		assert.Fail(t, "the reference is not ready once the registry is referred")
+	}
+	assert.Eventually(t, func() bool {
+		return invoke() == "registry"
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, "127.0.0.1:2181", rc.Addresses()[0].Location)
+
+	// it returns promptly once the registry is referred
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	assert.NoError(t, rc.WaitReady(ctx))
+	assert.Less(t, int64(time.Since(start)), int64(50*time.Millisecond))
+}
+
+// partialTestProtocol refers the urls of the port 20000 only, and panics for the others
+type partialTestProtocol struct {
+	protocol.BaseProtocol
+	referred []protocol.Invoker
+}
+
+func (pp *partialTestProtocol) Refer(url *common.URL) protocol.Invoker {
+	if url.Port != "20000" {
+		panic("registry can not connect success")
+	}
+	invoker := protocol.NewBaseInvoker(url)
+	pp.referred = append(pp.referred, invoker)
+	return invoker
+}
+
+func TestReferenceConfigReferURLsPanic(t *testing.T) {
+	partial := &partialTestProtocol{BaseProtocol: protocol.NewBaseProtocol()}
+	extension.SetProtocol("partial", func() protocol.Protocol {
+		return partial
+	})
+	up, err := common.NewURL("partial://127.0.0.1:20000")
+	assert.NoError(t, err)
+	down, err := common.NewURL("partial://127.0.0.1:20001")
+	assert.NoError(t, err)
+
+	// the invokers referred before the panic are destroyed
+	rc := &ReferenceConfig{}
+	assert.Panics(t, func() {
+		rc.referURLs([]*common.URL{up, down}, false)
+	})
+	assert.Len(t, partial.referred, 1)
+	assert.True(t, partial.referred[0].(*protocol.BaseInvoker).IsDestroyed())
+}
+
+type mirrorTestInvoker struct {
+	protocol.BaseInvoker
+	invocations chan protocol.Invocation
+}
+
+func (mi *mirrorTestInvoker) Invoke(_ context.Context, inv protocol.Invocation) protocol.Result {
+	*inv.Reply().(*string) = "shadow"
+	mi.invocations <- inv
+	return &protocol.RPCResult{Err: perrors.New("shadow is broken")}
+}
+
+type mirrorTestProtocol struct {
+	protocol.BaseProtocol
+	invocations chan protocol.Invocation
+}
+
+func (mp *mirrorTestProtocol) Refer(url *common.URL) protocol.Invoker {
+	return &mirrorTestInvoker{BaseInvoker: *protocol.NewBaseInvoker(url), invocations: mp.invocations}
+}
+
+func TestReferenceConfigMirrorURLs(t *testing.T) {
+	extension.SetLocalMetadataService(constant.DEFAULT_KEY, func() (service.MetadataService, error) {
+		return &mockObserverMetadataService{}, nil
+	})
+	extension.SetProtocol("primary", func() protocol.Protocol {
+		return &fallbackTestProtocol{BaseProtocol: protocol.NewBaseProtocol(), name: "primary"}
+	})
+	invocations := make(chan protocol.Invocation, 10)
+	extension.SetProtocol("shadow", func() protocol.Protocol {
+		return &mirrorTestProtocol{BaseProtocol: protocol.NewBaseProtocol(), invocations: invocations}
+	})
+	extension.SetCluster("fallback-test", func() cluster.Cluster {
+		return fallbackTestCluster{}
+	})
+	mirrored := make(chan error, 10)
+	extension.SetMirrorCallback(func(_ protocol.Invoker, _ protocol.Invocation, err error) {
+		mirrored <- err
+	})
+	defer extension.SetMirrorCallback(nil)
+
+	rc := NewReferenceConfigBuilder().
+		SetInterface("com.ikurento.user.UserProvider").
+		SetCluster("fallback-test").
+		SetMirrorURLs("shadow://127.0.0.1:20010").
+		Build()
+	rc.URL = "primary://127.0.0.1:20000"
+	rc.Params[constant.MIRROR_PERCENTAGE_KEY] = "100"
+	rc.rootConfig = &RootConfig{
+		Application: &ApplicationConfig{Name: "mirror-app"},
+		Consumer:    &ConsumerConfig{Filter: "-" + constant.GracefulShutdownConsumerFilterKey},
+	}
+	rc.Refer(nil)
+	defer rc.GetInvoker().Destroy()
+
+	for i := 0; i < 3; i++ {
+		reply := new(string)
+		inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUser"),
+			invocation.WithArguments([]interface{}{i}), invocation.WithReply(reply),
+			invocation.WithAttachments(map[string]interface{}{"user": "u"}))
+		result := rc.GetInvoker().Invoke(context.Background(), inv)
+		assert.NoError(t, result.Error())
+		assert.Equal(t, "primary", result.Result())
+
+		select {
+		case shadowInv := <-invocations:
+			assert.Equal(t, "GetUser", shadowInv.MethodName())
+			assert.Equal(t, []interface{}{i}, shadowInv.Arguments())
+			assert.Equal(t, "u", shadowInv.Attachment("user"))
+		case <-time.After(time.Second):
+			assert.Fail(t, "the invocation is not mirrored")
+		}
+		assert.EqualError(t, <-mirrored, "shadow is broken")
+		// the reply of the caller isn't touched by the shadow provider
+		assert.Equal(t, "", *reply)
+	}
+}
+
 //import (
 //	"context"
 //	"dubbo.apache.org/dubbo-go/v3/config"
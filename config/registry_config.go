@@ -25,6 +25,8 @@ import (
 
 import (
 	"github.com/creasty/defaults"
+
+	perrors "github.com/pkg/errors"
 )
 
 import (
@@ -56,6 +58,9 @@ type RegistryConfig struct {
 	Weight       int64             `yaml:"weight" json:"weight,omitempty" property:"weight"`
 	Params       map[string]string `yaml:"params" json:"params,omitempty" property:"params"`
 	RegistryType string            `yaml:"registry-type"`
+	// TLSConfigID isn't supported by the registries, since their clients can't dial with TLS. It's kept to fail the
+	// validation rather than ignoring the tls config silently.
+	TLSConfigID string `yaml:"tls-config" json:"tls-config,omitempty" property:"tls-config"`
 }
 
 // Prefix dubbo.registries
@@ -68,9 +73,28 @@ func (c *RegistryConfig) Init() error {
 		return err
 	}
 	c.translateRegistryAddress()
+	if err := c.checkTLS(); err != nil {
+		return err
+	}
 	return verify(c)
 }
 
+// checkTLS rejects the tls config referenced by the registry or set by its params
+func (c *RegistryConfig) checkTLS() error {
+	if len(c.TLSConfigID) > 0 {
+		return perrors.Errorf("the registry %s://%s can't use the tls config %s, the registries don't support TLS",
+			c.Protocol, c.Address, c.TLSConfigID)
+	}
+	for _, key := range []string{constant.TLS_CERT_FILE_KEY, constant.TLS_KEY_FILE_KEY, constant.TLS_CA_FILE_KEY,
+		constant.TLS_SERVER_NAME_KEY, constant.TLS_INSECURE_SKIP_VERIFY_KEY} {
+		if _, ok := c.Params[key]; ok {
+			return perrors.Errorf("the registry %s://%s can't use the tls param %s, the registries don't support TLS",
+				c.Protocol, c.Address, key)
+		}
+	}
+	return nil
+}
+
 func (c *RegistryConfig) getUrlMap(roleType common.RoleType) url.Values {
 	urlMap := url.Values{}
 	urlMap.Set(constant.GROUP_KEY, c.Group)
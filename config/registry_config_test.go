@@ -26,6 +26,7 @@ import (
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
 )
 
 func TestLoadRegistries(t *testing.T) {
@@ -73,3 +74,18 @@ func TestTranslateRegistryAddress(t *testing.T) {
 	assert.Equal(t, "nacos", reg.Protocol)
 	assert.Equal(t, "127.0.0.1:8848", reg.Address)
 }
+
+func TestRegistryConfigRejectTLS(t *testing.T) {
+	reg := &RegistryConfig{Protocol: "zookeeper", Address: "127.0.0.1:2181", TLSConfigID: "internal"}
+	err := reg.Init()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "tls config internal")
+
+	reg = &RegistryConfig{Protocol: "zookeeper", Address: "127.0.0.1:2181",
+		Params: map[string]string{constant.TLS_CA_FILE_KEY: "./testdata/ca.crt"}}
+	err = reg.Init()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), constant.TLS_CA_FILE_KEY)
+
+	assert.NoError(t, (&RegistryConfig{Protocol: "zookeeper", Address: "127.0.0.1:2181"}).Init())
+}
@@ -78,6 +78,9 @@ type RootConfig struct {
 
 	// cache file used to store the current used configurations.
 	CacheFile string `yaml:"cache_file" json:"cache_file,omitempty" property:"cache_file"`
+
+	// TLSConfigs tls configs referenced by the protocols
+	TLSConfigs map[string]*TLSConfig `yaml:"tls-configs" json:"tls-configs,omitempty" property:"tls-configs"`
 }
 
 func SetRootConfig(r RootConfig) {
@@ -147,6 +150,14 @@ func (rc *RootConfig) Init() error {
 	if err := rc.Application.Init(); err != nil {
 		return err
 	}
+	rc.Logger.subscribeLevel(rc)
+
+	// init tls config
+	for id, tlsConfig := range rc.TLSConfigs {
+		if err := tlsConfig.Init(); err != nil {
+			return perrors.WithMessagef(err, "init tls config %s", id)
+		}
+	}
 
 	// init protocol
 	protocols := rc.Protocols
@@ -160,6 +171,11 @@ func (rc *RootConfig) Init() error {
 		if err := protocol.Init(); err != nil {
 			return err
 		}
+		tlsConfig, err := rc.getTLSConfig(protocol.TLSConfigID)
+		if err != nil {
+			return err
+		}
+		protocol.tlsConfig = tlsConfig
 	}
 
 	// init registry
@@ -303,6 +319,14 @@ func (rb *RootConfigBuilder) SetConfigCenter(configCenterConfig *CenterConfig) *
 	return rb
 }
 
+func (rb *RootConfigBuilder) AddTLSConfig(tlsConfigID string, tlsConfig *TLSConfig) *RootConfigBuilder {
+	if rb.rootConfig.TLSConfigs == nil {
+		rb.rootConfig.TLSConfigs = make(map[string]*TLSConfig)
+	}
+	rb.rootConfig.TLSConfigs[tlsConfigID] = tlsConfig
+	return rb
+}
+
 func (rb *RootConfigBuilder) Build() *RootConfig {
 	return rb.rootConfig
 }
@@ -42,6 +42,7 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
 	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/common/proxy"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 	"dubbo.apache.org/dubbo-go/v3/protocol/protocolwrapper"
 )
@@ -52,6 +53,7 @@ type ServiceConfig struct {
 	Filter                      string            `yaml:"filter" json:"filter,omitempty" property:"filter"`
 	ProtocolIDs                 []string          `default:"[\"dubbo\"]"  validate:"required"  yaml:"protocol-ids"  json:"protocol-ids,omitempty" property:"protocol-ids"` // multi protocolIDs support, split by ','
 	Interface                   string            `validate:"required"  yaml:"interface"  json:"interface,omitempty" property:"interface"`
+	InterfaceAliases            []string          `yaml:"interface-aliases"  json:"interface-aliases,omitempty" property:"interface-aliases"` // the other interface names the service is exported under as well
 	RegistryIDs                 []string          `yaml:"registry-ids"  json:"registry-ids,omitempty"  property:"registry-ids"`
 	Cluster                     string            `default:"failover" yaml:"cluster"  json:"cluster,omitempty" property:"cluster"`
 	Loadbalance                 string            `default:"random" yaml:"loadbalance"  json:"loadbalance,omitempty"  property:"loadbalance"`
@@ -59,6 +61,7 @@ type ServiceConfig struct {
 	Version                     string            `yaml:"version"  json:"version,omitempty" property:"version" `
 	Methods                     []*MethodConfig   `yaml:"methods"  json:"methods,omitempty" property:"methods"`
 	Warmup                      string            `yaml:"warmup"  json:"warmup,omitempty"  property:"warmup"`
+	StartupDelay                string            `yaml:"startup-delay"  json:"startup-delay,omitempty"  property:"startup-delay"` // the duration the registration is deferred for after the service is bound
 	Retries                     string            `yaml:"retries"  json:"retries,omitempty" property:"retries"`
 	Serialization               string            `yaml:"serialization" json:"serialization" property:"serialization"`
 	Params                      map[string]string `yaml:"params"  json:"params,omitempty" property:"params"`
@@ -75,6 +78,8 @@ type ServiceConfig struct {
 	ParamSign                   string            `yaml:"param.sign" json:"param.sign,omitempty" property:"param.sign"`
 	Tag                         string            `yaml:"tag" json:"tag,omitempty" property:"tag"`
 	GrpcMaxMessageSize          int               `default:"4" yaml:"max_message_size" json:"max_message_size,omitempty"`
+	ThreadPool                  string            `yaml:"threadpool" json:"threadpool,omitempty" property:"threadpool"`
+	Threads                     string            `yaml:"threads" json:"threads,omitempty" property:"threads"`
 
 	RCProtocolsMap  map[string]*ProtocolConfig
 	RCRegistriesMap map[string]*RegistryConfig
@@ -89,6 +94,9 @@ type ServiceConfig struct {
 	exporters       []protocol.Exporter
 
 	metadataType string
+	// advertisedHost and advertisedPort are registered instead of the bound address if they are set
+	advertisedHost string
+	advertisedPort string
 }
 
 // Prefix returns dubbo.service.${InterfaceName}.
@@ -110,6 +118,8 @@ func (svc *ServiceConfig) Init(rc *RootConfig) error {
 	svc.RCProtocolsMap = rc.Protocols
 	if rc.Provider != nil {
 		svc.ProxyFactoryKey = rc.Provider.ProxyFactory
+		svc.advertisedHost = rc.Provider.AdvertisedHost
+		svc.advertisedPort = rc.Provider.AdvertisedPort
 	}
 	svc.RegistryIDs = translateRegistryIds(svc.RegistryIDs)
 	if len(svc.RegistryIDs) <= 0 {
@@ -161,7 +171,6 @@ func (svc *ServiceConfig) Export() error {
 	}
 
 	regUrls := loadRegistries(svc.RegistryIDs, svc.RCRegistriesMap, common.PROVIDER)
-	urlMap := svc.getUrlMap()
 	protocolConfigs := loadProtocol(svc.ProtocolIDs, svc.RCProtocolsMap)
 	if len(protocolConfigs) == 0 {
 		logger.Warnf("The service %v's '%v' protocols don't has right protocolConfigs, Please check your configuration center and transfer protocol ", svc.Interface, svc.ProtocolIDs)
@@ -172,80 +181,121 @@ func (svc *ServiceConfig) Export() error {
 	nextPort := ports.Front()
 	proxyFactory := extension.GetProxyFactory(svc.ProxyFactoryKey)
 	for _, proto := range protocolConfigs {
-		// registry the service reflect
-		methods, err := common.ServiceMap.Register(svc.Interface, proto.Name, svc.Group, svc.Version, svc.rpcService)
-		if err != nil {
-			formatErr := perrors.Errorf("The service %v export the protocol %v error! Error message is %v.",
-				svc.Interface, proto.Name, err.Error())
-			logger.Errorf(formatErr.Error())
-			return formatErr
-		}
-
 		port := proto.Port
 		if len(proto.Port) == 0 {
 			port = nextPort.Value.(string)
 			nextPort = nextPort.Next()
 		}
-		ivkURL := common.NewURLWithOptions(
-			common.WithPath(svc.Interface),
-			common.WithProtocol(proto.Name),
-			common.WithIp(proto.Ip),
-			common.WithPort(port),
-			common.WithParams(urlMap),
-			common.WithParamsValue(constant.BEAN_NAME_KEY, svc.id),
-			//common.WithParamsValue(constant.SSL_ENABLED_KEY, strconv.FormatBool(config.GetSslEnabled())),
-			common.WithMethods(strings.Split(methods, ",")),
-			common.WithToken(svc.Token),
-			common.WithParamsValue(constant.METADATATYPE_KEY, svc.metadataType),
-		)
-		if len(svc.Tag) > 0 {
-			ivkURL.AddParam(constant.Tagkey, svc.Tag)
+		// the implementation is exported under each of the interface names on the same port
+		for _, interfaceName := range svc.getInterfaceNames() {
+			exported, err := svc.exportInterface(interfaceName, proto, port, regUrls, proxyFactory)
+			if err != nil {
+				return err
+			}
+			if !exported {
+				return nil
+			}
 		}
+	}
+	svc.exported.Store(true)
+	return nil
+}
 
-		// post process the URL to be exported
-		svc.postProcessConfig(ivkURL)
-		// config post processor may set "export" to false
-		if !ivkURL.GetParamBool(constant.EXPORT_KEY, true) {
-			return nil
+// getInterfaceNames returns the interface name and the aliases of the service
+func (svc *ServiceConfig) getInterfaceNames() []string {
+	interfaceNames := []string{svc.Interface}
+	for _, alias := range svc.InterfaceAliases {
+		if alias = strings.TrimSpace(alias); len(alias) > 0 && alias != svc.Interface {
+			interfaceNames = append(interfaceNames, alias)
 		}
+	}
+	return interfaceNames
+}
+
+// exportInterface exports the service under @interfaceName by @proto on @port, it returns false if the rest of
+// the export is stopped, e.g. the config post processors disable it
+func (svc *ServiceConfig) exportInterface(interfaceName string, proto *ProtocolConfig, port string,
+	regUrls []*common.URL, proxyFactory proxy.ProxyFactory) (bool, error) {
+	// registry the service reflect
+	methods, err := common.ServiceMap.Register(interfaceName, proto.Name, svc.Group, svc.Version, svc.rpcService)
+	if err != nil {
+		formatErr := perrors.Errorf("The service %v export the protocol %v error! Error message is %v.",
+			interfaceName, proto.Name, err.Error())
+		logger.Errorf(formatErr.Error())
+		return false, formatErr
+	}
 
-		if len(regUrls) > 0 {
-			svc.cacheMutex.Lock()
-			if svc.cacheProtocol == nil {
-				logger.Infof(fmt.Sprintf("First load the registry protocol, url is {%v}!", ivkURL))
-				svc.cacheProtocol = extension.GetProtocol("registry")
-			}
-			svc.cacheMutex.Unlock()
-
-			for _, regUrl := range regUrls {
-				regUrl.SubURL = ivkURL
-				invoker := proxyFactory.GetInvoker(regUrl)
-				exporter := svc.cacheProtocol.Export(invoker)
-				if exporter == nil {
-					return perrors.New(fmt.Sprintf("Registry protocol new exporter error, registry is {%v}, url is {%v}", regUrl, ivkURL))
-				}
-				svc.exporters = append(svc.exporters, exporter)
-			}
-		} else {
-			if ivkURL.GetParam(constant.INTERFACE_KEY, "") == constant.METADATA_SERVICE_NAME {
-				ms, err := extension.GetLocalMetadataService("")
-				if err != nil {
-					logger.Warnf("export org.apache.dubbo.metadata.MetadataService failed beacause of %s ! pls check if you import _ \"dubbo.apache.org/dubbo-go/v3/metadata/service/local\"", err)
-					return nil
-				}
-				ms.SetMetadataServiceURL(ivkURL)
-			}
-			invoker := proxyFactory.GetInvoker(ivkURL)
-			exporter := extension.GetProtocol(protocolwrapper.FILTER).Export(invoker)
+	urlMap := svc.getUrlMap()
+	urlMap.Set(constant.INTERFACE_KEY, interfaceName)
+	ivkURL := common.NewURLWithOptions(
+		common.WithPath(interfaceName),
+		common.WithProtocol(proto.Name),
+		common.WithIp(proto.Ip),
+		common.WithPort(port),
+		common.WithParams(urlMap),
+		common.WithParamsValue(constant.BEAN_NAME_KEY, svc.id),
+		//common.WithParamsValue(constant.SSL_ENABLED_KEY, strconv.FormatBool(config.GetSslEnabled())),
+		common.WithMethods(strings.Split(methods, ",")),
+		common.WithToken(svc.Token),
+		common.WithParamsValue(constant.METADATATYPE_KEY, svc.metadataType),
+	)
+	if len(svc.Tag) > 0 {
+		ivkURL.AddParam(constant.Tagkey, svc.Tag)
+	}
+	if len(svc.advertisedHost) > 0 {
+		ivkURL.SetParam(constant.ADVERTISED_HOST_KEY, svc.advertisedHost)
+	}
+	if len(svc.advertisedPort) > 0 {
+		ivkURL.SetParam(constant.ADVERTISED_PORT_KEY, svc.advertisedPort)
+	}
+	for k, v := range proto.tlsConfig.getUrlMap() {
+		ivkURL.SetParam(k, v[0])
+	}
+
+	// post process the URL to be exported
+	svc.postProcessConfig(ivkURL)
+	// config post processor may set "export" to false
+	if !ivkURL.GetParamBool(constant.EXPORT_KEY, true) {
+		return false, nil
+	}
+
+	if len(regUrls) > 0 {
+		svc.cacheMutex.Lock()
+		if svc.cacheProtocol == nil {
+			logger.Infof(fmt.Sprintf("First load the registry protocol, url is {%v}!", ivkURL))
+			svc.cacheProtocol = extension.GetProtocol("registry")
+		}
+		svc.cacheMutex.Unlock()
+
+		for _, regUrl := range regUrls {
+			// the registry url is cloned since each of the exported urls is carried by its own one
+			regUrl = regUrl.Clone()
+			regUrl.SubURL = ivkURL
+			invoker := proxyFactory.GetInvoker(regUrl)
+			exporter := svc.cacheProtocol.Export(invoker)
 			if exporter == nil {
-				return perrors.New(fmt.Sprintf("Filter protocol without registry new exporter error, url is {%v}", ivkURL))
+				return false, perrors.New(fmt.Sprintf("Registry protocol new exporter error, registry is {%v}, url is {%v}", regUrl, ivkURL))
 			}
 			svc.exporters = append(svc.exporters, exporter)
 		}
-		publishServiceDefinition(ivkURL)
+	} else {
+		if ivkURL.GetParam(constant.INTERFACE_KEY, "") == constant.METADATA_SERVICE_NAME {
+			ms, err := extension.GetLocalMetadataService("")
+			if err != nil {
+				logger.Warnf("export org.apache.dubbo.metadata.MetadataService failed beacause of %s ! pls check if you import _ \"dubbo.apache.org/dubbo-go/v3/metadata/service/local\"", err)
+				return false, nil
+			}
+			ms.SetMetadataServiceURL(ivkURL)
+		}
+		invoker := proxyFactory.GetInvoker(ivkURL)
+		exporter := extension.GetProtocol(protocolwrapper.FILTER).Export(invoker)
+		if exporter == nil {
+			return false, perrors.New(fmt.Sprintf("Filter protocol without registry new exporter error, url is {%v}", ivkURL))
+		}
+		svc.exporters = append(svc.exporters, exporter)
 	}
-	svc.exported.Store(true)
-	return nil
+	publishServiceDefinition(ivkURL)
+	return true, nil
 }
 
 //loadProtocol filter protocols by ids
@@ -336,6 +386,7 @@ func (svc *ServiceConfig) getUrlMap() url.Values {
 	urlMap.Set(constant.CLUSTER_KEY, svc.Cluster)
 	urlMap.Set(constant.LOADBALANCE_KEY, svc.Loadbalance)
 	urlMap.Set(constant.WARMUP_KEY, svc.Warmup)
+	urlMap.Set(constant.STARTUP_DELAY_KEY, svc.StartupDelay)
 	urlMap.Set(constant.RETRIES_KEY, svc.Retries)
 	urlMap.Set(constant.GROUP_KEY, svc.Group)
 	urlMap.Set(constant.VERSION_KEY, svc.Version)
@@ -345,6 +396,21 @@ func (svc *ServiceConfig) getUrlMap() url.Values {
 	urlMap.Set(constant.MESSAGE_SIZE_KEY, strconv.Itoa(svc.GrpcMaxMessageSize))
 	// todo: move
 	urlMap.Set(constant.SERIALIZATION_KEY, svc.Serialization)
+	if svc.Serialization != "" {
+		// the consumers negotiate the serialization by the advertised ones, since the serialization param
+		// is filled by the one of the reference once the urls are merged
+		urlMap.Set(constant.SERIALIZATION_KEY, strings.TrimSpace(strings.Split(svc.Serialization, ",")[0]))
+		urlMap.Set(constant.SERIALIZATION_SUPPORTED_KEY, svc.Serialization)
+	}
+	if compress, _ := strconv.ParseBool(urlMap.Get(constant.COMPRESS_KEY)); compress {
+		// the consumers compress the requests only to the providers advertising it, since the compress param
+		// may be filled by the one of the reference once the urls are merged
+		urlMap.Set(constant.COMPRESS_SUPPORTED_KEY, constant.GZIP_COMPRESSION)
+	}
+	if typed, err := strconv.ParseBool(urlMap.Get(constant.TYPED_ATTACHMENTS_KEY)); err != nil || typed {
+		// the consumers send the typed attachments only to the providers advertising it
+		urlMap.Set(constant.TYPED_ATTACHMENTS_SUPPORTED_KEY, strconv.FormatBool(true))
+	}
 	// application config info
 	ac := GetApplicationConfig()
 	urlMap.Set(constant.APPLICATION_KEY, ac.Name)
@@ -354,6 +420,15 @@ func (svc *ServiceConfig) getUrlMap() url.Values {
 	urlMap.Set(constant.APP_VERSION_KEY, ac.Version)
 	urlMap.Set(constant.OWNER_KEY, ac.Owner)
 	urlMap.Set(constant.ENVIRONMENT_KEY, ac.Environment)
+	if len(ac.Version) > 0 {
+		urlMap.Set(constant.PROVIDER_APP_VERSION_KEY, ac.Version)
+	}
+	if len(ac.Color) > 0 {
+		urlMap.Set(constant.COLOR_KEY, ac.Color)
+	}
+	if len(ac.HealthCheckURL) > 0 {
+		urlMap.Set(constant.HEALTH_CHECK_URL_KEY, ac.HealthCheckURL)
+	}
 
 	// filter
 	if svc.Filter == "" {
@@ -379,6 +454,14 @@ func (svc *ServiceConfig) getUrlMap() url.Values {
 	urlMap.Set(constant.SERVICE_AUTH_KEY, svc.Auth)
 	urlMap.Set(constant.PARAMETER_SIGNATURE_ENABLE_KEY, svc.ParamSign)
 
+	// dedicated goroutine pool of the service, the shared pool is used if it's not set
+	if svc.ThreadPool != "" {
+		urlMap.Set(constant.THREADPOOL_KEY, svc.ThreadPool)
+	}
+	if svc.Threads != "" {
+		urlMap.Set(constant.THREADS_KEY, svc.Threads)
+	}
+
 	// whether to export or not
 	urlMap.Set(constant.EXPORT_KEY, strconv.FormatBool(svc.export))
 
@@ -455,6 +538,11 @@ func (pcb *ServiceConfigBuilder) SetInterface(interfaceName string) *ServiceConf
 	return pcb
 }
 
+func (pcb *ServiceConfigBuilder) SetInterfaceAliases(aliases ...string) *ServiceConfigBuilder {
+	pcb.serviceConfig.InterfaceAliases = aliases
+	return pcb
+}
+
 func (pcb *ServiceConfigBuilder) SetMetadataType(setMetadataType string) *ServiceConfigBuilder {
 	pcb.serviceConfig.metadataType = setMetadataType
 	return pcb
@@ -470,6 +558,11 @@ func (pcb *ServiceConfigBuilder) SetWarmUpTie(warmUp string) *ServiceConfigBuild
 	return pcb
 }
 
+func (pcb *ServiceConfigBuilder) SetStartupDelay(startupDelay string) *ServiceConfigBuilder {
+	pcb.serviceConfig.StartupDelay = startupDelay
+	return pcb
+}
+
 func (pcb *ServiceConfigBuilder) SetCluster(cluster string) *ServiceConfigBuilder {
 	pcb.serviceConfig.Cluster = cluster
 	return pcb
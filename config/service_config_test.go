@@ -0,0 +1,140 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	_ "dubbo.apache.org/dubbo-go/v3/common/proxy/proxy_factory"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+type AliasProvider struct{}
+
+func (p *AliasProvider) GetUser(_ context.Context, id string) (string, error) {
+	return "user-" + id, nil
+}
+
+func (p *AliasProvider) Reference() string {
+	return "AliasProvider"
+}
+
+type mockAliasRegistryProtocol struct {
+	protocol.BaseProtocol
+	invokers []protocol.Invoker
+}
+
+func (m *mockAliasRegistryProtocol) Export(invoker protocol.Invoker) protocol.Exporter {
+	m.invokers = append(m.invokers, invoker)
+	return protocol.NewBaseExporter(invoker.GetURL().SubURL.ServiceKey(), invoker, m.ExporterMap())
+}
+
+// overrideProtocol sets the protocol extension of @name to @p, and returns the function restoring the previous one
+func overrideProtocol(name string, p protocol.Protocol) func() {
+	var previous protocol.Protocol
+	func() {
+		// the protocol extension may not exist
+		defer func() { _ = recover() }()
+		previous = extension.GetProtocol(name)
+	}()
+	extension.SetProtocol(name, func() protocol.Protocol {
+		return p
+	})
+	return func() {
+		if previous == nil {
+			extension.SetProtocol(name, nil)
+			return
+		}
+		extension.SetProtocol(name, func() protocol.Protocol {
+			return previous
+		})
+	}
+}
+
+func TestServiceConfigExportInterfaceAliases(t *testing.T) {
+	registryProtocol := &mockAliasRegistryProtocol{BaseProtocol: protocol.NewBaseProtocol()}
+	defer overrideProtocol(constant.REGISTRY_PROTOCOL, registryProtocol)()
+
+	svc := NewServiceConfigBuilder().
+		SetInterface("com.old.UserProvider").
+		SetInterfaceAliases("com.new.UserProvider").
+		SetProtocolIDs("dubbo").
+		SetRegistryIDs("mock").
+		AddRCProtocol("dubbo", &ProtocolConfig{Name: "dubbo", Ip: "127.0.0.1", Port: "20010"}).
+		AddRCRegistry("mock", &RegistryConfig{Protocol: "mock", Address: "127.0.0.1:2181"}).
+		SetRPCService(&AliasProvider{}).
+		Build()
+	svc.InitExported()
+	assert.NoError(t, svc.Export())
+	assert.True(t, svc.IsExport())
+
+	// each of the interface names is registered
+	assert.Len(t, registryProtocol.invokers, 2)
+	var interfaceNames []string
+	for _, invoker := range registryProtocol.invokers {
+		providerURL := invoker.GetURL().SubURL
+		interfaceNames = append(interfaceNames, providerURL.GetParam(constant.INTERFACE_KEY, ""))
+		assert.Equal(t, "20010", providerURL.Port)
+
+		// the consumers referencing either name reach the same implementation
+		inv := invocation.NewRPCInvocation("GetUser", []interface{}{"1"}, nil)
+		result := invoker.Invoke(context.Background(), inv)
+		assert.NoError(t, result.Error())
+		assert.Equal(t, "user-1", result.Result())
+	}
+	assert.Equal(t, []string{"com.old.UserProvider", "com.new.UserProvider"}, interfaceNames)
+}
+
+func TestServiceConfigAdvertiseSerializations(t *testing.T) {
+	svc := NewServiceConfigBuilder().SetInterface("com.ikurento.user.UserProvider").
+		SetSerialization("hessian2,protobuf").Build()
+	urlMap := svc.getUrlMap()
+	assert.Equal(t, "hessian2", urlMap.Get(constant.SERIALIZATION_KEY))
+	assert.Equal(t, "hessian2,protobuf", urlMap.Get(constant.SERIALIZATION_SUPPORTED_KEY))
+
+	urlMap = NewServiceConfigBuilder().SetInterface("com.ikurento.user.UserProvider").Build().getUrlMap()
+	assert.Equal(t, "", urlMap.Get(constant.SERIALIZATION_SUPPORTED_KEY))
+}
+
+func TestServiceConfigAdvertiseCompression(t *testing.T) {
+	svc := NewServiceConfigBuilder().SetInterface("com.ikurento.user.UserProvider").Build()
+	svc.Params = map[string]string{constant.COMPRESS_KEY: "true"}
+	assert.Equal(t, constant.GZIP_COMPRESSION, svc.getUrlMap().Get(constant.COMPRESS_SUPPORTED_KEY))
+
+	svc.Params = map[string]string{constant.COMPRESS_KEY: "false"}
+	assert.Equal(t, "", svc.getUrlMap().Get(constant.COMPRESS_SUPPORTED_KEY))
+}
+
+func TestServiceConfigAdvertiseTypedAttachments(t *testing.T) {
+	svc := NewServiceConfigBuilder().SetInterface("com.ikurento.user.UserProvider").Build()
+	assert.Equal(t, "true", svc.getUrlMap().Get(constant.TYPED_ATTACHMENTS_SUPPORTED_KEY))
+
+	// the service turning them off doesn't advertise them
+	svc.Params = map[string]string{constant.TYPED_ATTACHMENTS_KEY: "false"}
+	assert.Equal(t, "", svc.getUrlMap().Get(constant.TYPED_ATTACHMENTS_SUPPORTED_KEY))
+}
@@ -0,0 +1,107 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"net/url"
+	"strconv"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+// TLSConfig is the TLS configuration referenced by the protocols with its id.
+// The registries don't support it since their clients can't dial with TLS, and the registry referencing
+// one fails the validation.
+/**
+ * example:
+ * tls-configs:
+ *   internal:
+ *     cert-file: "/etc/dubbo/client.crt"
+ *     key-file: "/etc/dubbo/client.key"
+ *     ca-file: "/etc/dubbo/ca.crt"
+ * protocols:
+ *   dubbo:
+ *     name: dubbo
+ *     tls-config: internal
+ */
+type TLSConfig struct {
+	CertFile           string `yaml:"cert-file" json:"cert-file,omitempty" property:"cert-file"`
+	KeyFile            string `yaml:"key-file" json:"key-file,omitempty" property:"key-file"`
+	CAFile             string `yaml:"ca-file" json:"ca-file,omitempty" property:"ca-file"`
+	ServerName         string `yaml:"server-name" json:"server-name,omitempty" property:"server-name"`
+	InsecureSkipVerify bool   `yaml:"insecure-skip-verify" json:"insecure-skip-verify,omitempty" property:"insecure-skip-verify"`
+}
+
+// Prefix dubbo.tls-configs
+func (TLSConfig) Prefix() string {
+	return constant.TLSConfigPrefix
+}
+
+// Init checks that the certificate, the key and the CA files can be loaded
+func (c *TLSConfig) Init() error {
+	if (len(c.CertFile) == 0) != (len(c.KeyFile) == 0) {
+		return perrors.New("cert-file and key-file of the tls config should be configured together")
+	}
+	_, err := common.NewClientTLSConfig(common.NewURLWithOptions(common.WithParams(c.getUrlMap())))
+	return err
+}
+
+func (c *TLSConfig) getUrlMap() url.Values {
+	urlMap := url.Values{}
+	c.appendTo(urlMap)
+	return urlMap
+}
+
+// appendTo sets the TLS params into @urlMap, and does nothing if @c is nil
+func (c *TLSConfig) appendTo(urlMap url.Values) {
+	if c == nil {
+		return
+	}
+	params := map[string]string{
+		constant.TLS_CERT_FILE_KEY:   c.CertFile,
+		constant.TLS_KEY_FILE_KEY:    c.KeyFile,
+		constant.TLS_CA_FILE_KEY:     c.CAFile,
+		constant.TLS_SERVER_NAME_KEY: c.ServerName,
+	}
+	for k, v := range params {
+		if len(v) > 0 {
+			urlMap.Set(k, v)
+		}
+	}
+	if c.InsecureSkipVerify {
+		urlMap.Set(constant.TLS_INSECURE_SKIP_VERIFY_KEY, strconv.FormatBool(c.InsecureSkipVerify))
+	}
+}
+
+// getTLSConfig gets the tls config referenced by @id
+func (rc *RootConfig) getTLSConfig(id string) (*TLSConfig, error) {
+	if len(id) == 0 {
+		return nil, nil
+	}
+	if tlsConfig, ok := rc.TLSConfigs[id]; ok {
+		return tlsConfig, nil
+	}
+	return nil, perrors.Errorf("the tls config %s isn't found", id)
+}
@@ -0,0 +1,53 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTLSConfigInit(t *testing.T) {
+	err := (&TLSConfig{CertFile: "./testdata/absent.crt"}).Init()
+	assert.Error(t, err)
+
+	err = (&TLSConfig{CertFile: "./testdata/absent.crt", KeyFile: "./testdata/absent.key"}).Init()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "./testdata/absent.crt")
+
+	assert.NoError(t, (&TLSConfig{InsecureSkipVerify: true}).Init())
+}
+
+func TestGetTLSConfig(t *testing.T) {
+	tlsConfig := &TLSConfig{InsecureSkipVerify: true}
+	rc := NewRootConfigBuilder().AddTLSConfig("internal", tlsConfig).Build()
+
+	c, err := rc.getTLSConfig("internal")
+	assert.NoError(t, err)
+	assert.Equal(t, tlsConfig, c)
+
+	_, err = rc.getTLSConfig("external")
+	assert.Error(t, err)
+
+	c, err = rc.getTLSConfig("")
+	assert.NoError(t, err)
+	assert.Nil(t, c)
+}
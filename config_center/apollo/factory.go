@@ -34,6 +34,9 @@ func createDynamicConfigurationFactory() config_center.DynamicConfigurationFacto
 
 type apolloConfigurationFactory struct{}
 
+// Exclusive marks the apollo configurations sharing the global agollo client
+func (f *apolloConfigurationFactory) Exclusive() {}
+
 // GetDynamicConfiguration gets the dynamic configuration
 func (f *apolloConfigurationFactory) GetDynamicConfiguration(url *common.URL) (config_center.DynamicConfiguration, error) {
 	dynamicConfiguration, err := newApolloConfiguration(url)
@@ -27,9 +27,14 @@ import (
 import (
 	gxset "github.com/dubbogo/gost/container/set"
 
+	"github.com/knadh/koanf"
+	"github.com/knadh/koanf/parsers/yaml"
+	"github.com/knadh/koanf/providers/rawbytes"
+
 	perrors "github.com/pkg/errors"
 
 	"github.com/zouyx/agollo/v3"
+	"github.com/zouyx/agollo/v3/component/notify"
 	"github.com/zouyx/agollo/v3/env/config"
 )
 
@@ -38,6 +43,7 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	cc "dubbo.apache.org/dubbo-go/v3/config_center"
 	"dubbo.apache.org/dubbo-go/v3/config_center/parser"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
 )
 
 const (
@@ -50,26 +56,73 @@ type apolloConfiguration struct {
 
 	listeners sync.Map
 	appConf   *config.AppConfig
-	parser    parser.ConfigurationParser
+	// openAPI writes the configs back to apollo, which is nil if the portal isn't configured
+	openAPI *openAPIClient
+	// namespaces are the watched ones in the order of priority, and the former ones override the latter ones
+	namespaces []string
+	parser     parser.ConfigurationParser
+	// health is reported by the refreshes and the changes notified by the long polling, since agollo doesn't
+	// expose the failures of its polls
+	health *metrics.ConnectionHealth
 }
 
+// newApolloConfiguration creates the config center watching the comma separated namespaces of the url,
+// all of which are long polled by the same client, e.g. the one overriding the application configs
+// followed by the one shared by the dubbo applications
 func newApolloConfiguration(url *common.URL) (*apolloConfiguration, error) {
 	c := &apolloConfiguration{
-		url: url,
+		url:        url,
+		namespaces: splitNamespaces(url.GetParam(constant.CONFIG_NAMESPACE_KEY, cc.DEFAULT_GROUP)),
+		health:     metrics.NewConnectionHealth(metrics.ConfigCenterComponent, url),
 	}
 	c.appConf = &config.AppConfig{
 		AppID:            url.GetParam(constant.CONFIG_APP_ID_KEY, ""),
 		Cluster:          url.GetParam(constant.CONFIG_CLUSTER_KEY, ""),
-		NamespaceName:    url.GetParam(constant.CONFIG_NAMESPACE_KEY, cc.DEFAULT_GROUP),
+		NamespaceName:    strings.Join(c.namespaces, ","),
 		IP:               c.getAddressWithProtocolPrefix(url),
 		Secret:           url.GetParam(constant.CONFIG_SECRET_KEY, ""),
 		IsBackupConfig:   url.GetParamBool(constant.CONFIG_BACKUP_CONFIG_KEY, true),
 		BackupConfigPath: url.GetParam(constant.CONFIG_BACKUP_CONFIG_PATH_KEY, ""),
 	}
+	c.openAPI = newOpenAPIClient(url)
 	agollo.InitCustomConfig(func() (*config.AppConfig, error) {
 		return c.appConf, nil
 	})
-	return c, agollo.Start()
+	err := agollo.Start()
+	c.health.SetConnected(err == nil)
+	if err == nil {
+		agollo.AddChangeListener(&healthListener{health: c.health})
+	}
+	return c, err
+}
+
+func splitNamespaces(value string) []string {
+	namespaces := make([]string, 0, 1)
+	for _, namespace := range strings.Split(value, ",") {
+		if namespace = strings.TrimSpace(namespace); namespace != "" {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	if len(namespaces) == 0 {
+		namespaces = append(namespaces, cc.DEFAULT_GROUP)
+	}
+	return namespaces
+}
+
+// watchedNamespace returns the namespace which the listener of @key and @group is notified of the changes of,
+// which is either @key or @group if it's watched, or empty for all of the namespaces
+func (c *apolloConfiguration) watchedNamespace(key, group string) string {
+	for _, namespace := range c.namespaces {
+		if namespace == key {
+			return key
+		}
+	}
+	for _, namespace := range c.namespaces {
+		if namespace == group {
+			return group
+		}
+	}
+	return ""
 }
 
 func (c *apolloConfiguration) AddListener(key string, listener cc.ConfigurationListener, opts ...cc.Option) {
@@ -78,8 +131,14 @@ func (c *apolloConfiguration) AddListener(key string, listener cc.ConfigurationL
 		opt(k)
 	}
 
+	namespace := c.watchedNamespace(key, k.Group)
+	newListener := newApolloListener(namespace)
+	if key == "" && namespace == "" && len(c.namespaces) > 1 {
+		// the listeners of the merged view get the contents as GetProperties merges them
+		newListener.merge = c.mergeProperties
+	}
 	key = k.Group + key
-	l, _ := c.listeners.LoadOrStore(key, newApolloListener())
+	l, _ := c.listeners.LoadOrStore(key, newListener)
 	l.(*apolloListener).AddListener(listener)
 }
 
@@ -96,36 +155,138 @@ func (c *apolloConfiguration) RemoveListener(key string, listener cc.Configurati
 	}
 }
 
+// GetInternalProperty returns the value of @key in the first namespace having it
 func (c *apolloConfiguration) GetInternalProperty(key string, opts ...cc.Option) (string, error) {
-	newConfig := agollo.GetConfig(c.appConf.NamespaceName)
-	if newConfig == nil {
+	found := false
+	for _, namespace := range c.namespaces {
+		newConfig := agollo.GetConfig(namespace)
+		if newConfig == nil {
+			continue
+		}
+		found = true
+		if value := newConfig.GetStringValue(key, ""); value != "" {
+			return value, nil
+		}
+	}
+	if !found {
 		return "", perrors.New(fmt.Sprintf("nothing in namespace:%s ", key))
 	}
-	return newConfig.GetStringValue(key, ""), nil
+	return "", nil
 }
 
 func (c *apolloConfiguration) GetRule(key string, opts ...cc.Option) (string, error) {
 	return c.GetInternalProperty(key, opts...)
 }
 
-// PublishConfig will publish the config with the (key, group, value) pair
-func (c *apolloConfiguration) PublishConfig(string, string, string) error {
-	return perrors.New("unsupport operation")
+// PublishConfig will publish the config with the (key, group, value) pair by the open api of the portal,
+// the item @key of the namespace @group is created or updated and the namespace is released at once,
+// and the first namespace is the one if @group is empty
+func (c *apolloConfiguration) PublishConfig(key string, group string, value string) error {
+	if c.openAPI == nil {
+		return perrors.New("the portal of apollo is not configured for publishing the configs")
+	}
+	return c.openAPI.publish(c.resolvedNamespace(group), key, value)
+}
+
+// RemoveConfig will remove the config with the (key, group) pair by the open api of the portal,
+// and it does nothing if the item doesn't exist
+func (c *apolloConfiguration) RemoveConfig(key string, group string) error {
+	if c.openAPI == nil {
+		return perrors.New("the portal of apollo is not configured for removing the configs")
+	}
+	return c.openAPI.remove(c.resolvedNamespace(group), key)
+}
+
+func (c *apolloConfiguration) resolvedNamespace(group string) string {
+	if group == "" {
+		return c.namespaces[0]
+	}
+	return group
 }
 
-// GetConfigKeysByGroup will return all keys with the group
+// GetConfigKeysByGroup will return all keys in the namespace of the group, which are the ones
+// in all of the namespaces of the config center if the group is empty
 func (c *apolloConfiguration) GetConfigKeysByGroup(group string) (*gxset.HashSet, error) {
-	return nil, perrors.New("unsupport operation")
+	namespaces := c.namespaces
+	if group != "" {
+		namespaces = []string{group}
+	}
+	keys := gxset.NewSet()
+	found := false
+	for _, namespace := range namespaces {
+		namespaceConfig := agollo.GetConfig(namespace)
+		if namespaceConfig == nil {
+			continue
+		}
+		found = true
+		namespaceConfig.GetCache().Range(func(key, _ interface{}) bool {
+			keys.Add(key.(string))
+			return true
+		})
+	}
+	if !found {
+		return nil, perrors.New(fmt.Sprintf("nothing in namespace:%s ", strings.Join(namespaces, ",")))
+	}
+	return keys, nil
+}
+
+// GetConfigKeysByGroupPaged will return the page of the keys in the namespace of the group
+func (c *apolloConfiguration) GetConfigKeysByGroupPaged(group string, offset, limit int) ([]string, int, error) {
+	keys, err := c.GetConfigKeysByGroup(group)
+	if err != nil {
+		return nil, 0, err
+	}
+	page, total := cc.PageKeys(keys, offset, limit)
+	return page, total, nil
+}
+
+// WatchConfig streams the changes of the config @key on the channel until cancel is called
+func (c *apolloConfiguration) WatchConfig(key string, opts ...cc.Option) (<-chan cc.ConfigChangeEvent, func()) {
+	return cc.WatchConfig(c, key, opts...)
 }
 
+// GetProperties returns the content of the namespace @key, and the yaml contents of all of the namespaces
+// are merged by their priorities if @key is empty
 func (c *apolloConfiguration) GetProperties(key string, opts ...cc.Option) (string, error) {
 	/**
 	 * when group is not null, we are getting startup configs(config file) from ShutdownConfig Center, for example:
 	 * key=dubbo.propertie
 	 */
 	if key == "" {
-		key = c.appConf.NamespaceName
+		if len(c.namespaces) > 1 {
+			return c.mergeProperties()
+		}
+		key = c.namespaces[0]
 	}
+	return c.getNamespaceContent(key)
+}
+
+// mergeProperties merges the yaml contents of the namespaces, and the former ones override the latter ones.
+// The namespaces without anything are skipped, which fails only if all of them are empty.
+func (c *apolloConfiguration) mergeProperties() (string, error) {
+	koan := koanf.New(".")
+	found := false
+	for i := len(c.namespaces) - 1; i >= 0; i-- {
+		content, err := c.getNamespaceContent(c.namespaces[i])
+		if err != nil {
+			continue
+		}
+		if err = koan.Load(rawbytes.Provider([]byte(content)), yaml.Parser()); err != nil {
+			return "", perrors.WithMessagef(err, "parse the content of namespace:%s", c.namespaces[i])
+		}
+		found = true
+	}
+	if !found {
+		return "", perrors.New(fmt.Sprintf("nothing in namespace:%s ", strings.Join(c.namespaces, ",")))
+	}
+	b, err := yaml.Parser().Marshal(koan.Raw())
+	if err != nil {
+		return "", perrors.WithStack(err)
+	}
+	return string(b), nil
+}
+
+func (c *apolloConfiguration) getNamespaceContent(key string) (string, error) {
 	tmpConfig := agollo.GetConfig(key)
 	if tmpConfig == nil {
 		return "", perrors.New(fmt.Sprintf("nothing in namespace:%s ", key))
@@ -141,6 +302,17 @@ func (c *apolloConfiguration) GetProperties(key string, opts ...cc.Option) (stri
 	return content, nil
 }
 
+// Refresh re-fetches all the namespaces from apollo synchronously instead of waiting for the long polling,
+// the listeners are notified if anything has been changed
+func (c *apolloConfiguration) Refresh() error {
+	if err := notify.AutoSyncConfigServices(c.appConf); err != nil {
+		c.health.SetConnected(false)
+		return perrors.WithMessage(err, "refresh apollo config")
+	}
+	c.health.Active()
+	return nil
+}
+
 func (c *apolloConfiguration) getAddressWithProtocolPrefix(url *common.URL) string {
 	address := url.Location
 	converted := address
@@ -24,6 +24,7 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 )
 
 import (
@@ -32,10 +33,13 @@ import (
 	"github.com/knadh/koanf/providers/rawbytes"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/zouyx/agollo/v3/storage"
 )
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/config"
 	"dubbo.apache.org/dubbo-go/v3/config_center"
 	"dubbo.apache.org/dubbo-go/v3/config_center/parser"
@@ -199,6 +203,265 @@ func TestListener(t *testing.T) {
 	assert.Equal(t, listenerCount, 0)
 }
 
+func TestRefresh(t *testing.T) {
+	apollo := initMockApollo(t)
+	originConfigRes := mockConfigRes
+	defer func() {
+		mockConfigRes = originConfigRes
+	}()
+	listener := &apolloRefreshListener{events: make(chan *config_center.ConfigChangeEvent, 16)}
+	apollo.AddListener(mockNamespace, listener)
+	defer apollo.RemoveListener(mockNamespace, listener)
+
+	mockConfigRes = `{
+	"appId": "testApplication_yang",
+	"cluster": "default",
+	"namespaceName": "mockDubbogo.yaml",
+	"configurations": {
+		"registries.hangzhouzk.username": "refreshed"
+	},
+	"releaseKey": "20191104105242-0f13805d89f834a5"
+}`
+	assert.NoError(t, apollo.Refresh())
+
+	// the update arrives much earlier than the next long polling
+	timeout := time.After(500 * time.Millisecond)
+	for {
+		select {
+		case event := <-listener.events:
+			if strings.Contains(event.Value.(string), "refreshed") {
+				assert.Equal(t, mockNamespace, event.Key)
+				return
+			}
+		case <-timeout:
+			assert.Fail(t, "the listener isn't notified after refresh")
+			return
+		}
+	}
+}
+
+func TestWatchConfig(t *testing.T) {
+	apollo := initMockApollo(t)
+	originConfigRes := mockConfigRes
+	defer func() {
+		mockConfigRes = originConfigRes
+	}()
+	events, cancel := apollo.WatchConfig(mockNamespace)
+
+	mockConfigRes = `{
+	"appId": "testApplication_yang",
+	"cluster": "default",
+	"namespaceName": "mockDubbogo.yaml",
+	"configurations": {
+		"registries.hangzhouzk.username": "watched"
+	},
+	"releaseKey": "20191104105242-0f13805d89f834a6"
+}`
+	assert.NoError(t, apollo.Refresh())
+	timeout := time.After(500 * time.Millisecond)
+	for changed := false; !changed; {
+		select {
+		case event := <-events:
+			changed = strings.Contains(event.Value.(string), "watched")
+			assert.Equal(t, mockNamespace, event.Key)
+		case <-timeout:
+			assert.FailNow(t, "the change isn't delivered on the channel")
+		}
+	}
+
+	// the listener is removed and the channel is closed once it's canceled
+	cancel()
+	mockConfigRes = originConfigRes
+	assert.NoError(t, apollo.Refresh())
+	// the buffered events are drained until it's closed
+	for range events {
+	}
+	apollo.listeners.Range(func(_, value interface{}) bool {
+		assert.Empty(t, value.(*apolloListener).listeners)
+		return true
+	})
+}
+
+func TestGetConfigKeysByGroupPaged(t *testing.T) {
+	apollo := initMockApollo(t)
+	originConfigRes := mockConfigRes
+	defer func() {
+		mockConfigRes = originConfigRes
+	}()
+	configurations := make([]string, 0, 25)
+	for i := 0; i < 25; i++ {
+		configurations = append(configurations, fmt.Sprintf(`"key.%02d": "value"`, i))
+	}
+	mockConfigRes = `{
+	"appId": "testApplication_yang",
+	"cluster": "default",
+	"namespaceName": "mockDubbogo.yaml",
+	"configurations": {` + strings.Join(configurations, ",") + `},
+	"releaseKey": "20191104105242-0f13805d89f834a6"
+}`
+	assert.NoError(t, apollo.Refresh())
+
+	keys := make(map[string]struct{})
+	for offset := 0; ; offset += 7 {
+		page, total, err := apollo.GetConfigKeysByGroupPaged(mockNamespace, offset, 7)
+		assert.NoError(t, err)
+		assert.Equal(t, 25, total)
+		if len(page) == 0 {
+			break
+		}
+		assert.LessOrEqual(t, len(page), 7)
+		for _, key := range page {
+			// the pages don't overlap
+			assert.NotContains(t, keys, key)
+			keys[key] = struct{}{}
+		}
+		// the pages are consistent
+		again, _, err := apollo.GetConfigKeysByGroupPaged(mockNamespace, offset, 7)
+		assert.NoError(t, err)
+		assert.Equal(t, page, again)
+	}
+	assert.Len(t, keys, 25)
+	for i := 0; i < 25; i++ {
+		assert.Contains(t, keys, fmt.Sprintf("key.%02d", i))
+	}
+}
+
+const mockOverridesNamespace = "mockOverrides.yaml"
+
+var originMockConfigRes = mockConfigRes
+
+var mockOverridesConfigRes = `{
+	"appId": "testApplication_yang",
+	"cluster": "default",
+	"namespaceName": "mockOverrides.yaml",
+	"configurations":{
+		"content":"dubbo:\n  application:\n     name: \"override-server\"\n"
+    },
+	"releaseKey": "20191104105242-0f13805d89f834b4"
+}`
+
+func overridesConfigResponse(rw http.ResponseWriter, _ *http.Request) {
+	fmt.Fprintf(rw, "%s", mockOverridesConfigRes)
+}
+
+func initMockApolloNamespaces(t *testing.T, namespaces string) *apolloConfiguration {
+	handlerMap := map[string]func(http.ResponseWriter, *http.Request){
+		mockNamespace:          configResponse,
+		mockOverridesNamespace: overridesConfigResponse,
+	}
+	apollo := runMockConfigServer(handlerMap, notifyResponse)
+	c := &config.CenterConfig{
+		Protocol:  "apollo",
+		AppID:     mockAppId,
+		Cluster:   mockCluster,
+		Namespace: namespaces,
+	}
+	url, err := common.NewURL(strings.ReplaceAll(apollo.URL, "http", "apollo"), common.WithParams(c.GetUrlMap()),
+		// the backups of the namespaces aren't written into the source tree
+		common.WithParamsValue(constant.CONFIG_BACKUP_CONFIG_PATH_KEY, t.TempDir()))
+	assert.NoError(t, err)
+	configuration, err := newApolloConfiguration(url)
+	assert.NoError(t, err)
+	return configuration
+}
+
+func TestGetPropertiesOfNamespaces(t *testing.T) {
+	// the contents may be changed by the former tests
+	changedConfigRes := mockConfigRes
+	mockConfigRes = originMockConfigRes
+	defer func() {
+		mockConfigRes = changedConfigRes
+	}()
+	apollo := initMockApolloNamespaces(t, mockOverridesNamespace+", "+mockNamespace)
+	assert.Equal(t, []string{mockOverridesNamespace, mockNamespace}, apollo.namespaces)
+
+	// the former namespace overrides the latter one
+	configs, err := apollo.GetProperties("")
+	assert.NoError(t, err)
+	koan := koanf.New(".")
+	assert.NoError(t, koan.Load(rawbytes.Provider([]byte(configs)), yaml.Parser()))
+	rc := &config.RootConfig{}
+	assert.NoError(t, koan.UnmarshalWithConf(rc.Prefix(), rc, koanf.UnmarshalConf{Tag: "yaml"}))
+	assert.Equal(t, "override-server", rc.Application.Name)
+	assert.Equal(t, "2.0", rc.Application.Version)
+
+	// each namespace is still available on its own
+	configs, err = apollo.GetProperties(mockNamespace)
+	assert.NoError(t, err)
+	assert.Contains(t, configs, "demo-server")
+	assert.NotContains(t, configs, "override-server")
+
+	keys, err := apollo.GetConfigKeysByGroup("")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, keys.Size())
+	assert.True(t, keys.Contains("content"))
+}
+
+func TestNamespaceListener(t *testing.T) {
+	apollo := initMockApolloNamespaces(t, mockOverridesNamespace+","+mockNamespace)
+	listener := &apolloRefreshListener{events: make(chan *config_center.ConfigChangeEvent, 16)}
+	apollo.AddListener(mockOverridesNamespace, listener)
+	defer apollo.RemoveListener(mockOverridesNamespace, listener)
+	l, ok := apollo.listeners.Load(mockOverridesNamespace)
+	assert.True(t, ok)
+	namespaceListener := l.(*apolloListener)
+	assert.Equal(t, mockOverridesNamespace, namespaceListener.namespace)
+
+	// only the changes of the namespace are notified
+	for _, namespace := range []string{"", mockNamespace, mockOverridesNamespace} {
+		event := &storage.FullChangeEvent{Changes: map[string]interface{}{"k": "v"}}
+		event.Namespace = namespace
+		namespaceListener.OnNewestChange(event)
+	}
+	for event := range listener.events {
+		if event.Key == mockOverridesNamespace {
+			break
+		}
+		assert.Fail(t, "the change of the other namespace is notified", event.Key)
+	}
+
+	// the listeners of the other keys are notified of the changes of all of the namespaces
+	other := &apolloRefreshListener{events: make(chan *config_center.ConfigChangeEvent, 16)}
+	apollo.AddListener("dubbo.application.name", other)
+	defer apollo.RemoveListener("dubbo.application.name", other)
+	l, _ = apollo.listeners.Load("dubbo.application.name")
+	assert.Equal(t, "", l.(*apolloListener).namespace)
+}
+
+func TestMergedViewListener(t *testing.T) {
+	// the contents may be changed by the former tests
+	changedConfigRes := mockConfigRes
+	mockConfigRes = originMockConfigRes
+	defer func() {
+		mockConfigRes = changedConfigRes
+	}()
+	apollo := initMockApolloNamespaces(t, mockOverridesNamespace+","+mockNamespace)
+	listener := &apolloRefreshListener{events: make(chan *config_center.ConfigChangeEvent, 16)}
+	apollo.AddListener("", listener)
+	defer apollo.RemoveListener("", listener)
+	l, ok := apollo.listeners.Load("")
+	assert.True(t, ok)
+
+	// the change of either namespace notifies the merged contents as GetProperties returns them
+	merged, err := apollo.GetProperties("")
+	assert.NoError(t, err)
+	event := &storage.FullChangeEvent{Changes: map[string]interface{}{"k": "v"}}
+	event.Namespace = mockNamespace
+	l.(*apolloListener).OnNewestChange(event)
+	notified := <-listener.events
+	assert.Equal(t, "", notified.Key)
+	assert.Equal(t, merged, notified.Value)
+	assert.Contains(t, notified.Value, "override-server")
+}
+
+type apolloRefreshListener struct {
+	events chan *config_center.ConfigChangeEvent
+}
+
+func (l *apolloRefreshListener) Process(event *config_center.ConfigChangeEvent) {
+	l.events <- event
+}
+
 type apolloDataListener struct {
 	wg    sync.WaitGroup
 	count int
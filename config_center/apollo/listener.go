@@ -27,16 +27,23 @@ import (
 import (
 	"dubbo.apache.org/dubbo-go/v3/common/logger"
 	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
 	"dubbo.apache.org/dubbo-go/v3/remoting"
 )
 
 type apolloListener struct {
+	// namespace is the one whose changes are notified, and the changes of all namespaces are if it's empty
+	namespace string
+	// merge returns the merged contents of all of the namespaces, which the listeners of the merged view
+	// are notified of instead of the changes
+	merge     func() (string, error)
 	listeners map[config_center.ConfigurationListener]struct{}
 }
 
 // nolint
-func newApolloListener() *apolloListener {
+func newApolloListener(namespace string) *apolloListener {
 	return &apolloListener{
+		namespace: namespace,
 		listeners: make(map[config_center.ConfigurationListener]struct{}),
 	}
 }
@@ -47,17 +54,30 @@ func (a *apolloListener) OnChange(changeEvent *storage.ChangeEvent) {
 
 // OnNewestChange process each listener by all changes
 func (a *apolloListener) OnNewestChange(changeEvent *storage.FullChangeEvent) {
-	b, err := yaml.Marshal(changeEvent.Changes)
-	if err != nil {
-		logger.Errorf("apollo onNewestChange err %+v",
-			err)
+	if a.namespace != "" && changeEvent.Namespace != a.namespace {
 		return
 	}
-	content := string(b)
+	key, content := changeEvent.Namespace, ""
+	if a.merge != nil {
+		merged, err := a.merge()
+		if err != nil {
+			logger.Errorf("apollo onNewestChange merge the namespaces err %+v", err)
+			return
+		}
+		key, content = "", merged
+	} else {
+		b, err := yaml.Marshal(changeEvent.Changes)
+		if err != nil {
+			logger.Errorf("apollo onNewestChange err %+v",
+				err)
+			return
+		}
+		content = string(b)
+	}
 	for listener := range a.listeners {
 		listener.Process(&config_center.ConfigChangeEvent{
 			ConfigType: remoting.EventTypeUpdate,
-			Key:        changeEvent.Namespace,
+			Key:        key,
 			Value:      content,
 		})
 	}
@@ -75,3 +95,16 @@ func (a *apolloListener) AddListener(l config_center.ConfigurationListener) {
 func (a *apolloListener) RemoveListener(l config_center.ConfigurationListener) {
 	delete(a.listeners, l)
 }
+
+// healthListener reports the changes notified by the long polling as the successful polls of apollo
+type healthListener struct {
+	health *metrics.ConnectionHealth
+}
+
+// OnChange does nothing since the changes are reported by OnNewestChange
+func (h *healthListener) OnChange(*storage.ChangeEvent) {}
+
+// OnNewestChange reports the successful poll
+func (h *healthListener) OnNewestChange(*storage.FullChangeEvent) {
+	h.health.Active()
+}
@@ -0,0 +1,154 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package apollo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+const (
+	defaultEnv      = "DEV"
+	defaultCluster  = "default"
+	defaultOperator = "apollo"
+)
+
+// openAPIClient writes the items of the namespaces by the open api of the apollo portal, which is authorized by
+// the token of the third party application with the permissions of the namespaces granted in the portal
+type openAPIClient struct {
+	portal   string
+	token    string
+	env      string
+	appID    string
+	cluster  string
+	operator string
+	client   *http.Client
+}
+
+// newOpenAPIClient creates the client of the portal configured by @url, and it's nil without the portal address
+func newOpenAPIClient(url *common.URL) *openAPIClient {
+	portal := strings.TrimSuffix(url.GetParam(constant.CONFIG_PORTAL_ADDRESS_KEY, ""), "/")
+	if portal == "" {
+		return nil
+	}
+	if !strings.HasPrefix(portal, apolloProtocolPrefix) && !strings.HasPrefix(portal, "https://") {
+		portal = apolloProtocolPrefix + portal
+	}
+	cluster := url.GetParam(constant.CONFIG_CLUSTER_KEY, "")
+	if cluster == "" {
+		cluster = defaultCluster
+	}
+	return &openAPIClient{
+		portal:   portal,
+		token:    url.GetParam(constant.CONFIG_PORTAL_TOKEN_KEY, ""),
+		env:      url.GetParam(constant.CONFIG_ENV_KEY, defaultEnv),
+		appID:    url.GetParam(constant.CONFIG_APP_ID_KEY, ""),
+		cluster:  cluster,
+		operator: url.GetParam(constant.CONFIG_OPERATOR_KEY, defaultOperator),
+		client:   &http.Client{Timeout: url.GetParamDuration(constant.CONFIG_TIMEOUT_KEY, "10s")},
+	}
+}
+
+// publish creates or updates the item @key of @namespace, and releases the namespace
+func (o *openAPIClient) publish(namespace, key, value string) error {
+	item := map[string]string{
+		"key":                      key,
+		"value":                    value,
+		"dataChangeLastModifiedBy": o.operator,
+		"dataChangeCreatedBy":      o.operator,
+	}
+	path := o.namespacePath(namespace) + "/items/" + url.PathEscape(key) + "?createIfNotExists=true"
+	if _, err := o.do(http.MethodPut, path, item); err != nil {
+		return perrors.WithMessagef(err, "publish the item %s of namespace %s", key, namespace)
+	}
+	return o.release(namespace)
+}
+
+// remove deletes the item @key of @namespace, and releases the namespace if the item exists
+func (o *openAPIClient) remove(namespace, key string) error {
+	path := o.namespacePath(namespace) + "/items/" + url.PathEscape(key) + "?operator=" + url.QueryEscape(o.operator)
+	status, err := o.do(http.MethodDelete, path, nil)
+	if status == http.StatusNotFound {
+		return nil
+	}
+	if err != nil {
+		return perrors.WithMessagef(err, "remove the item %s of namespace %s", key, namespace)
+	}
+	return o.release(namespace)
+}
+
+// release makes the modified items of @namespace visible to the clients
+func (o *openAPIClient) release(namespace string) error {
+	release := map[string]string{
+		"releaseTitle": time.Now().Format("20060102150405") + "-release",
+		"releasedBy":   o.operator,
+	}
+	if _, err := o.do(http.MethodPost, o.namespacePath(namespace)+"/releases", release); err != nil {
+		return perrors.WithMessagef(err, "release namespace %s", namespace)
+	}
+	return nil
+}
+
+func (o *openAPIClient) namespacePath(namespace string) string {
+	return fmt.Sprintf("/openapi/v1/envs/%s/apps/%s/clusters/%s/namespaces/%s", url.PathEscape(o.env),
+		url.PathEscape(o.appID), url.PathEscape(o.cluster), url.PathEscape(namespace))
+}
+
+// do sends the request with the json @body to the portal, and returns the status code of the response
+func (o *openAPIClient) do(method, path string, body interface{}) (int, error) {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return 0, perrors.WithStack(err)
+		}
+		reader = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(method, o.portal+path, reader)
+	if err != nil {
+		return 0, perrors.WithStack(err)
+	}
+	req.Header.Set("Authorization", o.token)
+	req.Header.Set("Content-Type", "application/json;charset=UTF-8")
+	rsp, err := o.client.Do(req)
+	if err != nil {
+		return 0, perrors.WithStack(err)
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode < http.StatusOK || rsp.StatusCode >= http.StatusMultipleChoices {
+		message, _ := ioutil.ReadAll(rsp.Body)
+		return rsp.StatusCode, perrors.Errorf("%s %s responds %d: %s", method, path, rsp.StatusCode, message)
+	}
+	return rsp.StatusCode, nil
+}
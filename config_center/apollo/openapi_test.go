@@ -0,0 +1,111 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package apollo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+type portalRequest struct {
+	method string
+	uri    string
+	token  string
+	body   map[string]string
+}
+
+// runMockPortal records the requests to the open api, and responds 404 to the deletion of the missing items
+func runMockPortal() (*httptest.Server, func() []portalRequest) {
+	var (
+		lock     sync.Mutex
+		requests []portalRequest
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := portalRequest{method: r.Method, uri: r.RequestURI, token: r.Header.Get("Authorization")}
+		_ = json.NewDecoder(r.Body).Decode(&req.body)
+		lock.Lock()
+		requests = append(requests, req)
+		lock.Unlock()
+		if r.Method == http.MethodDelete && strings.Contains(r.RequestURI, "/items/missing") {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return ts, func() []portalRequest {
+		lock.Lock()
+		defer lock.Unlock()
+		result := requests
+		requests = nil
+		return result
+	}
+}
+
+func TestPublishAndRemoveConfig(t *testing.T) {
+	portal, requests := runMockPortal()
+	defer portal.Close()
+	url, err := common.NewURL("apollo://127.0.0.1:8080",
+		common.WithParamsValue(constant.CONFIG_APP_ID_KEY, mockAppId),
+		common.WithParamsValue(constant.CONFIG_PORTAL_ADDRESS_KEY, strings.TrimPrefix(portal.URL, "http://")),
+		common.WithParamsValue(constant.CONFIG_PORTAL_TOKEN_KEY, "token"),
+		common.WithParamsValue(constant.CONFIG_OPERATOR_KEY, "admin"))
+	assert.NoError(t, err)
+	apollo := &apolloConfiguration{namespaces: []string{"dubbo", mockNamespace}, openAPI: newOpenAPIClient(url)}
+
+	// the item is written and the namespace is released
+	assert.NoError(t, apollo.PublishConfig("demo.condition-router", "", "scope: application"))
+	recorded := requests()
+	assert.Len(t, recorded, 2)
+	namespacePath := "/openapi/v1/envs/DEV/apps/" + mockAppId + "/clusters/default/namespaces/dubbo"
+	assert.Equal(t, http.MethodPut, recorded[0].method)
+	assert.Equal(t, namespacePath+"/items/demo.condition-router?createIfNotExists=true", recorded[0].uri)
+	assert.Equal(t, "token", recorded[0].token)
+	assert.Equal(t, "scope: application", recorded[0].body["value"])
+	assert.Equal(t, "admin", recorded[0].body["dataChangeLastModifiedBy"])
+	assert.Equal(t, http.MethodPost, recorded[1].method)
+	assert.Equal(t, namespacePath+"/releases", recorded[1].uri)
+	assert.Equal(t, "admin", recorded[1].body["releasedBy"])
+
+	assert.NoError(t, apollo.RemoveConfig("demo.condition-router", mockNamespace))
+	recorded = requests()
+	assert.Len(t, recorded, 2)
+	namespacePath = "/openapi/v1/envs/DEV/apps/" + mockAppId + "/clusters/default/namespaces/" + mockNamespace
+	assert.Equal(t, http.MethodDelete, recorded[0].method)
+	assert.Equal(t, namespacePath+"/items/demo.condition-router?operator=admin", recorded[0].uri)
+	assert.Equal(t, namespacePath+"/releases", recorded[1].uri)
+
+	// nothing is released if the item doesn't exist
+	assert.NoError(t, apollo.RemoveConfig("missing", ""))
+	assert.Len(t, requests(), 1)
+
+	// the configs can't be written without the portal
+	apollo.openAPI = nil
+	assert.Error(t, apollo.PublishConfig("demo.condition-router", "", "scope: application"))
+	assert.Error(t, apollo.RemoveConfig("demo.condition-router", ""))
+}
@@ -0,0 +1,322 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appconfig
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+import (
+	gxset "github.com/dubbogo/gost/container/set"
+
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/config_center/parser"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+const (
+	defaultAgentAddress = "localhost:2772"
+	defaultPollInterval = "15s"
+)
+
+// appConfigDynamicConfiguration reads the configs deployed by AWS AppConfig from the AppConfig agent, which runs
+// as the sidecar of the tasks of ECS or the pods of EKS and caches the configs fetched from AppConfig with
+// the credentials of the task or the pod.
+// The config of the key in the group is the configuration profile named $(group).$(key) of the application and
+// the environment of the url, or the one named $(key) if the group is empty, and the configs listened are polled
+// from the agent periodically to notify the listeners of the changes.
+type appConfigDynamicConfiguration struct {
+	config_center.BaseDynamicConfiguration
+	url         *common.URL
+	address     string
+	application string
+	environment string
+	token       string
+	interval    time.Duration
+	client      *http.Client
+	parser      parser.ConfigurationParser
+
+	lock     sync.Mutex
+	watchers map[string]*profileWatcher
+	done     chan struct{}
+	wg       sync.WaitGroup
+	once     sync.Once
+}
+
+// profileWatcher keeps the content of a configuration profile last polled
+type profileWatcher struct {
+	key       string
+	listeners map[config_center.ConfigurationListener]struct{}
+	found     bool
+	content   []byte
+}
+
+func newAppConfigDynamicConfiguration(url *common.URL) (*appConfigDynamicConfiguration, error) {
+	address := strings.Split(url.Location, ",")[0]
+	if address == "" {
+		address = defaultAgentAddress
+	}
+	if !strings.HasPrefix(address, "http://") && !strings.HasPrefix(address, "https://") {
+		address = "http://" + address
+	}
+	application := url.GetParam(constant.CONFIG_APP_ID_KEY, "")
+	environment := url.GetParam(constant.CONFIG_ENV_KEY, "")
+	if application == "" || environment == "" {
+		return nil, perrors.New("the application and the environment of AWS AppConfig are required")
+	}
+	c := &appConfigDynamicConfiguration{
+		url:         url,
+		address:     strings.TrimSuffix(address, "/"),
+		application: application,
+		environment: environment,
+		token:       url.GetParam(constant.CONFIG_TOKEN_KEY, ""),
+		interval:    url.GetParamDuration(constant.CONFIG_POLL_INTERVAL_KEY, defaultPollInterval),
+		client:      &http.Client{Timeout: url.GetParamDuration(constant.CONFIG_TIMEOUT_KEY, config_center.DEFAULT_CONFIG_TIMEOUT)},
+		watchers:    make(map[string]*profileWatcher),
+		done:        make(chan struct{}),
+	}
+	c.wg.Add(1)
+	go c.poll()
+	return c, nil
+}
+
+// AddListener adds the listener of @key in the group, and the content of the profile is polled since then
+func (c *appConfigDynamicConfiguration) AddListener(key string, listener config_center.ConfigurationListener, opts ...config_center.Option) {
+	profile := profileName(key, resolveGroup(opts))
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	w, ok := c.watchers[profile]
+	if !ok {
+		w = &profileWatcher{key: key, listeners: make(map[config_center.ConfigurationListener]struct{})}
+		// the current content is the base of the changes notified
+		content, found, err := c.getProfile(profile)
+		if err != nil {
+			logger.Warnf("[AppConfig] get the configuration profile %s error: %v", profile, err)
+		}
+		w.content, w.found = content, found
+		c.watchers[profile] = w
+	}
+	w.listeners[listener] = struct{}{}
+}
+
+// RemoveListener removes the listener of @key in the group, and the profile isn't polled without any listener
+func (c *appConfigDynamicConfiguration) RemoveListener(key string, listener config_center.ConfigurationListener, opts ...config_center.Option) {
+	profile := profileName(key, resolveGroup(opts))
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if w, ok := c.watchers[profile]; ok {
+		delete(w.listeners, listener)
+		if len(w.listeners) == 0 {
+			delete(c.watchers, profile)
+		}
+	}
+}
+
+func (c *appConfigDynamicConfiguration) poll() {
+	defer c.wg.Done()
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			if err := c.Refresh(); err != nil {
+				logger.Warnf("[AppConfig] poll the configuration profiles error: %v", err)
+			}
+		}
+	}
+}
+
+// Refresh polls the contents of the profiles listened from the agent immediately, and the listeners are notified
+// if anything has been changed
+func (c *appConfigDynamicConfiguration) Refresh() error {
+	c.lock.Lock()
+	profiles := make([]string, 0, len(c.watchers))
+	for profile := range c.watchers {
+		profiles = append(profiles, profile)
+	}
+	c.lock.Unlock()
+
+	var failed []string
+	for _, profile := range profiles {
+		content, found, err := c.getProfile(profile)
+		if err != nil {
+			logger.Debugf("[AppConfig] get the configuration profile %s error: %v", profile, err)
+			failed = append(failed, profile)
+			continue
+		}
+		c.update(profile, content, found)
+	}
+	if len(failed) > 0 {
+		return perrors.Errorf("get the configuration profiles %s failed", strings.Join(failed, ","))
+	}
+	return nil
+}
+
+// update records the content of @profile polled and notifies the listeners if it has been changed
+func (c *appConfigDynamicConfiguration) update(profile string, content []byte, found bool) {
+	c.lock.Lock()
+	w, ok := c.watchers[profile]
+	if !ok {
+		c.lock.Unlock()
+		return
+	}
+	var event *config_center.ConfigChangeEvent
+	switch {
+	case found && !w.found:
+		event = &config_center.ConfigChangeEvent{Key: w.key, Value: string(content), ConfigType: remoting.EventTypeAdd}
+	case !found && w.found:
+		event = &config_center.ConfigChangeEvent{Key: w.key, Value: "", ConfigType: remoting.EventTypeDel}
+	case found && !bytes.Equal(content, w.content):
+		event = &config_center.ConfigChangeEvent{Key: w.key, Value: string(content), ConfigType: remoting.EventTypeUpdate}
+	}
+	w.content, w.found = content, found
+	listeners := make([]config_center.ConfigurationListener, 0, len(w.listeners))
+	for listener := range w.listeners {
+		listeners = append(listeners, listener)
+	}
+	c.lock.Unlock()
+	if event == nil {
+		return
+	}
+	for _, listener := range listeners {
+		listener.Process(event)
+	}
+}
+
+// getProfile gets the content of @profile from the agent, and whether it's deployed
+func (c *appConfigDynamicConfiguration) getProfile(profile string) ([]byte, bool, error) {
+	target := fmt.Sprintf("%s/applications/%s/environments/%s/configurations/%s", c.address,
+		url.PathEscape(c.application), url.PathEscape(c.environment), url.PathEscape(profile))
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, target, nil)
+	if err != nil {
+		return nil, false, perrors.WithStack(err)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	rsp, err := c.client.Do(req)
+	if err != nil {
+		return nil, false, perrors.WithStack(err)
+	}
+	defer rsp.Body.Close()
+	content, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, false, perrors.WithStack(err)
+	}
+	if rsp.StatusCode == http.StatusNotFound {
+		return nil, false, nil
+	}
+	if rsp.StatusCode < http.StatusOK || rsp.StatusCode >= http.StatusMultipleChoices {
+		return nil, false, perrors.Errorf("the agent responds %d: %s", rsp.StatusCode, content)
+	}
+	return content, true, nil
+}
+
+// GetProperties returns the content of the configuration profile of @key in the group
+func (c *appConfigDynamicConfiguration) GetProperties(key string, opts ...config_center.Option) (string, error) {
+	profile := profileName(key, resolveGroup(opts))
+	content, found, err := c.getProfile(profile)
+	if err != nil {
+		return "", perrors.WithMessagef(err, "get the configuration profile %s", profile)
+	}
+	if !found {
+		return "", perrors.Errorf("the configuration profile %s isn't deployed", profile)
+	}
+	return string(content), nil
+}
+
+// GetInternalProperty For AppConfig, getConfig and getConfigs have the same meaning.
+func (c *appConfigDynamicConfiguration) GetInternalProperty(key string, opts ...config_center.Option) (string, error) {
+	return c.GetProperties(key, opts...)
+}
+
+// GetRule returns the governance rule of @key in the group
+func (c *appConfigDynamicConfiguration) GetRule(key string, opts ...config_center.Option) (string, error) {
+	return c.GetProperties(key, opts...)
+}
+
+// PublishConfig isn't supported since the configs are deployed by AppConfig rather than the agent
+func (c *appConfigDynamicConfiguration) PublishConfig(string, string, string) error {
+	return perrors.New("unsupport operation")
+}
+
+// GetConfigKeysByGroup isn't supported since the agent doesn't list the configuration profiles
+func (c *appConfigDynamicConfiguration) GetConfigKeysByGroup(string) (*gxset.HashSet, error) {
+	return nil, perrors.New("unsupport operation")
+}
+
+// GetConfigKeysByGroupPaged isn't supported since the agent doesn't list the configuration profiles
+func (c *appConfigDynamicConfiguration) GetConfigKeysByGroupPaged(string, int, int) ([]string, int, error) {
+	return nil, 0, perrors.New("unsupport operation")
+}
+
+// WatchConfig streams the changes of the config @key on the channel until cancel is called
+func (c *appConfigDynamicConfiguration) WatchConfig(key string, opts ...config_center.Option) (<-chan config_center.ConfigChangeEvent, func()) {
+	return config_center.WatchConfig(c, key, opts...)
+}
+
+func (c *appConfigDynamicConfiguration) Parser() parser.ConfigurationParser {
+	return c.parser
+}
+
+func (c *appConfigDynamicConfiguration) SetParser(p parser.ConfigurationParser) {
+	c.parser = p
+}
+
+// Destroy stops polling the configuration profiles
+func (c *appConfigDynamicConfiguration) Destroy() {
+	c.once.Do(func() {
+		close(c.done)
+	})
+	c.wg.Wait()
+}
+
+func profileName(key, group string) string {
+	if group == "" {
+		return key
+	}
+	if key == "" {
+		return group
+	}
+	return group + "." + key
+}
+
+func resolveGroup(opts []config_center.Option) string {
+	options := &config_center.Options{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options.Group
+}
@@ -0,0 +1,166 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appconfig
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+const profilesPath = "/applications/demo/environments/prod/configurations/"
+
+// mockAgent serves the configuration profiles deployed like the AppConfig agent
+type mockAgent struct {
+	lock     sync.Mutex
+	profiles map[string]string
+	auths    map[string]struct{}
+}
+
+func (a *mockAgent) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.auths[r.Header.Get("Authorization")] = struct{}{}
+	content, ok := a.profiles[strings.TrimPrefix(r.URL.Path, profilesPath)]
+	if !strings.HasPrefix(r.URL.Path, profilesPath) || !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	_, _ = w.Write([]byte(content))
+}
+
+func (a *mockAgent) deploy(profile, content string) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if content == "" {
+		delete(a.profiles, profile)
+		return
+	}
+	a.profiles[profile] = content
+}
+
+type appConfigListener struct {
+	events chan *config_center.ConfigChangeEvent
+}
+
+func (l *appConfigListener) Process(event *config_center.ConfigChangeEvent) {
+	l.events <- event
+}
+
+func (l *appConfigListener) next(t *testing.T) *config_center.ConfigChangeEvent {
+	select {
+	case event := <-l.events:
+		return event
+	case <-time.After(3 * time.Second):
+		assert.FailNow(t, "the listener isn't notified of the change")
+		return nil
+	}
+}
+
+func initAppConfig(t *testing.T) (*appConfigDynamicConfiguration, *mockAgent) {
+	agent := &mockAgent{profiles: make(map[string]string), auths: make(map[string]struct{})}
+	server := httptest.NewServer(agent)
+	t.Cleanup(server.Close)
+	url, err := common.NewURL("appconfig://"+strings.TrimPrefix(server.URL, "http://"),
+		common.WithParamsValue(constant.CONFIG_APP_ID_KEY, "demo"),
+		common.WithParamsValue(constant.CONFIG_ENV_KEY, "prod"),
+		common.WithParamsValue(constant.CONFIG_TOKEN_KEY, "agent-token"),
+		common.WithParamsValue(constant.CONFIG_POLL_INTERVAL_KEY, "50ms"))
+	assert.NoError(t, err)
+	configuration, err := (&appConfigDynamicConfigurationFactory{}).GetDynamicConfiguration(url)
+	assert.NoError(t, err)
+	c := configuration.(*appConfigDynamicConfiguration)
+	t.Cleanup(c.Destroy)
+	return c, agent
+}
+
+func TestAppConfigGetProperties(t *testing.T) {
+	c, agent := initAppConfig(t)
+	agent.deploy("dubbo.dubbo.yaml", "dubbo:\n  application:\n    name: demo")
+	agent.deploy("demo.condition-router", "scope: application")
+
+	value, err := c.GetProperties("dubbo.yaml", config_center.WithGroup("dubbo"))
+	assert.NoError(t, err)
+	assert.Equal(t, "dubbo:\n  application:\n    name: demo", value)
+	value, err = c.GetRule("demo.condition-router")
+	assert.NoError(t, err)
+	assert.Equal(t, "scope: application", value)
+	assert.Contains(t, agent.auths, "Bearer agent-token")
+
+	_, err = c.GetProperties("missing")
+	assert.Error(t, err)
+	assert.Error(t, c.PublishConfig("demo.condition-router", "", "scope: application"))
+
+	_, err = newAppConfigDynamicConfiguration(common.NewURLWithOptions(common.WithLocation("localhost:2772")))
+	assert.Error(t, err)
+}
+
+func TestAppConfigListener(t *testing.T) {
+	c, agent := initAppConfig(t)
+	agent.deploy("demo.tag-router", "tags: []")
+	listener := &appConfigListener{events: make(chan *config_center.ConfigChangeEvent, 16)}
+	c.AddListener("demo.tag-router", listener)
+	c.AddListener("demo.configurators", listener, config_center.WithGroup("governance"))
+
+	agent.deploy("demo.tag-router", "tags: [gray]")
+	assert.Equal(t, &config_center.ConfigChangeEvent{
+		Key: "demo.tag-router", Value: "tags: [gray]", ConfigType: remoting.EventTypeUpdate,
+	}, listener.next(t))
+
+	agent.deploy("governance.demo.configurators", "configs: []")
+	assert.Equal(t, &config_center.ConfigChangeEvent{
+		Key: "demo.configurators", Value: "configs: []", ConfigType: remoting.EventTypeAdd,
+	}, listener.next(t))
+
+	agent.deploy("demo.tag-router", "")
+	assert.Equal(t, &config_center.ConfigChangeEvent{
+		Key: "demo.tag-router", Value: "", ConfigType: remoting.EventTypeDel,
+	}, listener.next(t))
+
+	// the profiles aren't polled without the listeners
+	c.RemoveListener("demo.tag-router", listener)
+	c.RemoveListener("demo.configurators", listener, config_center.WithGroup("governance"))
+	assert.Empty(t, c.watchers)
+	agent.deploy("demo.tag-router", "tags: []")
+	assert.NoError(t, c.Refresh())
+	select {
+	case event := <-listener.events:
+		assert.Fail(t, "the listener removed is notified", event.Key)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestProfileName(t *testing.T) {
+	assert.Equal(t, "dubbo.properties", profileName("dubbo.properties", ""))
+	assert.Equal(t, "dubbo.properties", profileName("properties", "dubbo"))
+	assert.Equal(t, "dubbo", profileName("", "dubbo"))
+}
@@ -24,3 +24,8 @@ type BaseDynamicConfiguration struct{}
 func (bdc *BaseDynamicConfiguration) RemoveConfig(string, string) error {
 	return nil
 }
+
+// Refresh does nothing by default since the changes are pushed to the listeners by the config center
+func (bdc *BaseDynamicConfiguration) Refresh() error {
+	return nil
+}
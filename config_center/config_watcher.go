@@ -0,0 +1,83 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_center
+
+import (
+	"sync"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+)
+
+// ConfigWatchBuffer is the number of the change events buffered for a consumer of WatchConfig. Once the buffer
+// of a slow consumer is full, the oldest event in it is dropped for the new one, so that the latest change of the
+// config is always delivered.
+const ConfigWatchBuffer = 64
+
+// WatchConfig streams the changes of the config @key of @dc on the returned channel by a listener added to it,
+// and cancel removes the listener then closes the channel. The events beyond ConfigWatchBuffer not consumed
+// are dropped from the oldest one.
+func WatchConfig(dc DynamicConfiguration, key string, opts ...Option) (<-chan ConfigChangeEvent, func()) {
+	w := &configWatcher{key: key, events: make(chan ConfigChangeEvent, ConfigWatchBuffer)}
+	dc.AddListener(key, w, opts...)
+	var once sync.Once
+	return w.events, func() {
+		once.Do(func() {
+			dc.RemoveListener(key, w, opts...)
+			w.close()
+		})
+	}
+}
+
+// configWatcher is the listener delivering the change events to the channel of WatchConfig
+type configWatcher struct {
+	key string
+	// mu guards events from being closed while the events are sent
+	mu     sync.Mutex
+	events chan ConfigChangeEvent
+	closed bool
+}
+
+// Process delivers the event, and it drops the oldest buffered one if the buffer is full
+func (w *configWatcher) Process(event *ConfigChangeEvent) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return
+	}
+	for {
+		select {
+		case w.events <- *event:
+			return
+		default:
+		}
+		select {
+		case dropped := <-w.events:
+			logger.Warnf("The change event %v of the config %s isn't consumed in time and dropped", dropped, w.key)
+		default:
+		}
+	}
+}
+
+func (w *configWatcher) close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+	close(w.events)
+}
@@ -0,0 +1,59 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_center
+
+import (
+	"strconv"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+func TestWatchConfig(t *testing.T) {
+	dc, err := (&MockDynamicConfigurationFactory{}).GetDynamicConfiguration(nil)
+	assert.NoError(t, err)
+	events, cancel := WatchConfig(dc, "watched")
+	listener := dc.(*MockDynamicConfiguration).listener["watched"]
+
+	listener.Process(&ConfigChangeEvent{Key: "watched", Value: "v1", ConfigType: remoting.EventTypeUpdate})
+	event := <-events
+	assert.Equal(t, "v1", event.Value)
+
+	// the oldest events are dropped once the buffer is full
+	for i := 0; i < ConfigWatchBuffer+2; i++ {
+		listener.Process(&ConfigChangeEvent{Key: "watched", Value: strconv.Itoa(i), ConfigType: remoting.EventTypeUpdate})
+	}
+	assert.Len(t, events, ConfigWatchBuffer)
+	assert.Equal(t, "2", (<-events).Value)
+
+	// nothing is delivered once it's canceled
+	cancel()
+	cancel()
+	listener.Process(&ConfigChangeEvent{Key: "watched", Value: "v2", ConfigType: remoting.EventTypeUpdate})
+	var last ConfigChangeEvent
+	for event := range events {
+		last = event
+	}
+	assert.Equal(t, strconv.Itoa(ConfigWatchBuffer+1), last.Value)
+}
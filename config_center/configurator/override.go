@@ -37,6 +37,11 @@ func init() {
 }
 
 func newConfigurator(url *common.URL) config_center.Configurator {
+	// the address of the rule without the port, e.g. 0.0.0.0 for all of the instances, matches any port
+	if len(url.Ip) == 0 && len(url.Location) != 0 && !strings.Contains(url.Location, ":") {
+		url.Ip = url.Location
+		url.Port = "0"
+	}
 	return &overrideConfigurator{configuratorUrl: url}
 }
 
@@ -0,0 +1,194 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consul
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+)
+
+const (
+	consulIndexHeader = "X-Consul-Index"
+	consulTokenHeader = "X-Consul-Token"
+)
+
+// kvClient accesses the kv store of consul by its http api
+type kvClient struct {
+	address string
+	token   string
+	timeout time.Duration
+	client  *http.Client
+	// health is reported by the results of the requests to consul
+	health *metrics.ConnectionHealth
+}
+
+func newKVClient(address, token string, timeout time.Duration, health *metrics.ConnectionHealth) *kvClient {
+	if !strings.HasPrefix(address, "http://") && !strings.HasPrefix(address, "https://") {
+		address = "http://" + address
+	}
+	return &kvClient{
+		address: strings.TrimSuffix(address, "/"),
+		token:   token,
+		timeout: timeout,
+		client:  &http.Client{},
+		health:  health,
+	}
+}
+
+// get returns the value of @key, whether it exists and the index of the kv store. It's a blocking query if
+// @index is positive, which returns once the index goes beyond @index or @wait elapses.
+func (c *kvClient) get(ctx context.Context, key string, index uint64, wait time.Duration) ([]byte, bool, uint64, error) {
+	query := "raw"
+	timeout := c.timeout
+	if index > 0 {
+		query += fmt.Sprintf("&index=%d&wait=%dms", index, wait.Milliseconds())
+		// consul adds a jitter of wait/16 at most to the blocking queries
+		timeout += wait + wait/16
+	}
+	rsp, err := c.do(ctx, http.MethodGet, key, query, nil, timeout)
+	if err != nil {
+		return nil, false, 0, err
+	}
+	defer rsp.Body.Close()
+	newIndex, _ := strconv.ParseUint(rsp.Header.Get(consulIndexHeader), 10, 64)
+	if rsp.StatusCode == http.StatusNotFound {
+		return nil, false, newIndex, nil
+	}
+	if err = checkStatus(rsp); err != nil {
+		return nil, false, 0, err
+	}
+	value, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, false, 0, perrors.WithStack(err)
+	}
+	return value, true, newIndex, nil
+}
+
+// put sets the value of @key
+func (c *kvClient) put(key string, value []byte) error {
+	rsp, err := c.do(context.Background(), http.MethodPut, key, "", bytes.NewReader(value), c.timeout)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+	if err = checkStatus(rsp); err != nil {
+		return err
+	}
+	var ok bool
+	if err = json.NewDecoder(rsp.Body).Decode(&ok); err != nil {
+		return perrors.WithStack(err)
+	}
+	if !ok {
+		return perrors.Errorf("put the value of %s to consul failed", key)
+	}
+	return nil
+}
+
+// delete removes @key, and it succeeds if @key doesn't exist
+func (c *kvClient) delete(key string) error {
+	rsp, err := c.do(context.Background(), http.MethodDelete, key, "", nil, c.timeout)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+	return checkStatus(rsp)
+}
+
+// keys returns the keys of the prefix @prefix, which is empty if there isn't any of them
+func (c *kvClient) keys(prefix string) ([]string, error) {
+	rsp, err := c.do(context.Background(), http.MethodGet, prefix, "keys", nil, c.timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if err = checkStatus(rsp); err != nil {
+		return nil, err
+	}
+	var keys []string
+	if err = json.NewDecoder(rsp.Body).Decode(&keys); err != nil {
+		return nil, perrors.WithStack(err)
+	}
+	return keys, nil
+}
+
+func (c *kvClient) do(ctx context.Context, method, key, query string, body io.Reader,
+	timeout time.Duration) (*http.Response, error) {
+	target := c.address + "/v1/kv/" + key
+	if query != "" {
+		target += "?" + query
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		cancel()
+		return nil, perrors.WithStack(err)
+	}
+	if c.token != "" {
+		req.Header.Set(consulTokenHeader, c.token)
+	}
+	rsp, err := c.client.Do(req)
+	if err != nil {
+		// the requests cancelled by the callers don't tell the connection is lost
+		if ctx.Err() != context.Canceled {
+			c.health.SetConnected(false)
+		}
+		cancel()
+		return nil, perrors.WithStack(err)
+	}
+	c.health.Active()
+	rsp.Body = &cancelBody{ReadCloser: rsp.Body, cancel: cancel}
+	return rsp, nil
+}
+
+// cancelBody releases the context of the request once the body of the response is closed
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+func checkStatus(rsp *http.Response) error {
+	if rsp.StatusCode >= http.StatusOK && rsp.StatusCode < http.StatusMultipleChoices {
+		return nil
+	}
+	message, _ := ioutil.ReadAll(rsp.Body)
+	return perrors.Errorf("%s %s responds %d: %s", rsp.Request.Method, rsp.Request.URL.Path, rsp.StatusCode, message)
+}
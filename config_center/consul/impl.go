@@ -0,0 +1,335 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consul
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+import (
+	gxset "github.com/dubbogo/gost/container/set"
+
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/config_center/parser"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+const (
+	pathSeparator    = "/"
+	defaultWatchWait = "30s"
+	// watchRetryInterval is the interval retrying the blocking queries failed, e.g. consul is restarting
+	watchRetryInterval = time.Second
+)
+
+// consulDynamicConfiguration keeps the configs in the kv store of consul with the keys of
+// $(namespace)/config/$(group)/$(key), and the changes are watched by the blocking queries of the keys listened
+type consulDynamicConfiguration struct {
+	config_center.BaseDynamicConfiguration
+	url      *common.URL
+	rootPath string
+	client   *kvClient
+	// wait is the max duration of a blocking query
+	wait   time.Duration
+	parser parser.ConfigurationParser
+
+	lock     sync.Mutex
+	watchers map[string]*keyWatcher
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// keyWatcher notifies the listeners of a key of its changes
+type keyWatcher struct {
+	key       string
+	path      string
+	listeners map[config_center.ConfigurationListener]struct{}
+	cancel    context.CancelFunc
+	// initialized tells whether the value has been fetched once, and only the changes after it are notified
+	initialized bool
+	found       bool
+	value       []byte
+	index       uint64
+}
+
+func newConsulDynamicConfiguration(url *common.URL) (*consulDynamicConfiguration, error) {
+	address := strings.Split(url.Location, ",")[0]
+	if address == "" {
+		return nil, perrors.New("the address of consul is empty")
+	}
+	c := &consulDynamicConfiguration{
+		url:      url,
+		rootPath: url.GetParam(constant.CONFIG_NAMESPACE_KEY, config_center.DEFAULT_GROUP) + pathSeparator + "config",
+		client: newKVClient(address, url.GetParam(constant.CONFIG_TOKEN_KEY, ""),
+			url.GetParamDuration(constant.CONFIG_TIMEOUT_KEY, config_center.DEFAULT_CONFIG_TIMEOUT),
+			metrics.NewConnectionHealth(metrics.ConfigCenterComponent, url)),
+		wait:     url.GetParamDuration(constant.CONFIG_WATCH_WAIT_KEY, defaultWatchWait),
+		watchers: make(map[string]*keyWatcher),
+	}
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+	return c, nil
+}
+
+// AddListener adds the listener of @key in the group, and the key is watched once it's listened at first
+func (c *consulDynamicConfiguration) AddListener(key string, listener config_center.ConfigurationListener, opts ...config_center.Option) {
+	path := c.getPath(key, resolveGroup(opts))
+	if c.addWatchedListener(path, listener) {
+		return
+	}
+	ctx, cancel := context.WithCancel(c.ctx)
+	w := &keyWatcher{
+		key:       key,
+		path:      path,
+		listeners: make(map[config_center.ConfigurationListener]struct{}),
+		cancel:    cancel,
+	}
+	// the value is fetched before it returns, so that the changes right after it are notified,
+	// and it's fetched without the lock since it's a round trip to consul
+	c.fetch(ctx, w, false)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.ctx.Err() != nil {
+		cancel()
+		return
+	}
+	if watched, ok := c.watchers[path]; ok {
+		// the key is watched by another listener added meanwhile
+		cancel()
+		watched.listeners[listener] = struct{}{}
+		return
+	}
+	w.listeners[listener] = struct{}{}
+	c.watchers[path] = w
+	c.wg.Add(1)
+	go c.watch(ctx, w)
+}
+
+// addWatchedListener adds @listener if the key of @path is watched already, it returns false if the key needs
+// watching, and true if the listener is added or the configuration is destroyed
+func (c *consulDynamicConfiguration) addWatchedListener(path string, listener config_center.ConfigurationListener) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.ctx.Err() != nil {
+		return true
+	}
+	w, ok := c.watchers[path]
+	if ok {
+		w.listeners[listener] = struct{}{}
+	}
+	return ok
+}
+
+// RemoveListener removes the listener of @key in the group, and the key isn't watched without any listener
+func (c *consulDynamicConfiguration) RemoveListener(key string, listener config_center.ConfigurationListener, opts ...config_center.Option) {
+	path := c.getPath(key, resolveGroup(opts))
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	w, ok := c.watchers[path]
+	if !ok {
+		return
+	}
+	delete(w.listeners, listener)
+	if len(w.listeners) == 0 {
+		w.cancel()
+		delete(c.watchers, path)
+	}
+}
+
+func (c *consulDynamicConfiguration) watch(ctx context.Context, w *keyWatcher) {
+	defer c.wg.Done()
+	for ctx.Err() == nil {
+		if !c.fetch(ctx, w, true) {
+			select {
+			case <-ctx.Done():
+			case <-time.After(watchRetryInterval):
+			}
+		}
+	}
+}
+
+// fetch gets the value of the key of @w by a blocking query if it has been fetched once, and the listeners are
+// notified if it changes and @notify is true. It returns false if the query fails.
+func (c *consulDynamicConfiguration) fetch(ctx context.Context, w *keyWatcher, notify bool) bool {
+	index := w.index
+	if !w.initialized {
+		index = 0
+	}
+	value, found, newIndex, err := c.client.get(ctx, w.path, index, c.wait)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Warnf("[Consul Config Center] watch %s error: %v", w.path, err)
+		}
+		return false
+	}
+	switch {
+	case newIndex < w.index:
+		// the index is reset, e.g. the kv store is restored, and it's fetched without blocking next time
+		w.index = 0
+	case newIndex == 0:
+		w.index = 1
+	default:
+		w.index = newIndex
+	}
+
+	var event *config_center.ConfigChangeEvent
+	switch {
+	case !w.initialized:
+	case found && !w.found:
+		event = &config_center.ConfigChangeEvent{Key: w.key, Value: string(value), ConfigType: remoting.EventTypeAdd}
+	case !found && w.found:
+		event = &config_center.ConfigChangeEvent{Key: w.key, Value: "", ConfigType: remoting.EventTypeDel}
+	case found && !bytes.Equal(value, w.value):
+		event = &config_center.ConfigChangeEvent{Key: w.key, Value: string(value), ConfigType: remoting.EventTypeUpdate}
+	}
+	w.initialized, w.found, w.value = true, found, value
+	if event != nil && notify {
+		c.notify(w, event)
+	}
+	return true
+}
+
+func (c *consulDynamicConfiguration) notify(w *keyWatcher, event *config_center.ConfigChangeEvent) {
+	c.lock.Lock()
+	listeners := make([]config_center.ConfigurationListener, 0, len(w.listeners))
+	for listener := range w.listeners {
+		listeners = append(listeners, listener)
+	}
+	c.lock.Unlock()
+	for _, listener := range listeners {
+		listener.Process(event)
+	}
+}
+
+// GetProperties returns the value of @key in the group
+func (c *consulDynamicConfiguration) GetProperties(key string, opts ...config_center.Option) (string, error) {
+	path := c.getPath(key, resolveGroup(opts))
+	value, found, _, err := c.client.get(context.Background(), path, 0, 0)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "", perrors.Errorf("nothing in consul with key %s", path)
+	}
+	return string(value), nil
+}
+
+// GetInternalProperty For consul, getConfig and getConfigs have the same meaning.
+func (c *consulDynamicConfiguration) GetInternalProperty(key string, opts ...config_center.Option) (string, error) {
+	return c.GetProperties(key, opts...)
+}
+
+// GetRule returns the governance rule of @key in the group
+func (c *consulDynamicConfiguration) GetRule(key string, opts ...config_center.Option) (string, error) {
+	return c.GetProperties(key, opts...)
+}
+
+// PublishConfig will put the value into consul with the key $(namespace)/config/$(group)/$(key)
+func (c *consulDynamicConfiguration) PublishConfig(key string, group string, value string) error {
+	return c.client.put(c.getPath(key, group), []byte(value))
+}
+
+// RemoveConfig will remove the config with the (key, group) pair from consul
+func (c *consulDynamicConfiguration) RemoveConfig(key string, group string) error {
+	return c.client.delete(c.getPath(key, group))
+}
+
+// GetConfigKeysByGroup will return all keys with the group
+func (c *consulDynamicConfiguration) GetConfigKeysByGroup(group string) (*gxset.HashSet, error) {
+	prefix := c.buildPath(group) + pathSeparator
+	keys, err := c.client.keys(prefix)
+	if err != nil {
+		return nil, err
+	}
+	set := gxset.NewSet()
+	for _, key := range keys {
+		if key = strings.TrimPrefix(key, prefix); key != "" {
+			set.Add(key)
+		}
+	}
+	if set.Empty() {
+		return nil, perrors.New("could not find keys with group: " + group)
+	}
+	return set, nil
+}
+
+// GetConfigKeysByGroupPaged will return the page of the keys with the group
+func (c *consulDynamicConfiguration) GetConfigKeysByGroupPaged(group string, offset, limit int) ([]string, int, error) {
+	keys, err := c.GetConfigKeysByGroup(group)
+	if err != nil {
+		return nil, 0, err
+	}
+	page, total := config_center.PageKeys(keys, offset, limit)
+	return page, total, nil
+}
+
+// WatchConfig streams the changes of the config @key on the channel until cancel is called
+func (c *consulDynamicConfiguration) WatchConfig(key string, opts ...config_center.Option) (<-chan config_center.ConfigChangeEvent, func()) {
+	return config_center.WatchConfig(c, key, opts...)
+}
+
+func (c *consulDynamicConfiguration) Parser() parser.ConfigurationParser {
+	return c.parser
+}
+
+func (c *consulDynamicConfiguration) SetParser(p parser.ConfigurationParser) {
+	c.parser = p
+}
+
+// Destroy stops watching all of the keys
+func (c *consulDynamicConfiguration) Destroy() {
+	c.lock.Lock()
+	c.cancel()
+	c.watchers = make(map[string]*keyWatcher)
+	c.lock.Unlock()
+	c.wg.Wait()
+}
+
+func (c *consulDynamicConfiguration) getPath(key string, group string) string {
+	if len(key) == 0 {
+		return c.buildPath(group)
+	}
+	return c.buildPath(group) + pathSeparator + key
+}
+
+func (c *consulDynamicConfiguration) buildPath(group string) string {
+	if len(group) == 0 {
+		group = config_center.DEFAULT_GROUP
+	}
+	return c.rootPath + pathSeparator + group
+}
+
+func resolveGroup(opts []config_center.Option) string {
+	options := &config_center.Options{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options.Group
+}
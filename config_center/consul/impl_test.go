@@ -0,0 +1,343 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consul
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+// mockConsul serves the kv api of consul with the blocking queries
+type mockConsul struct {
+	lock    sync.Mutex
+	index   uint64
+	kv      map[string][]byte
+	changed chan struct{}
+	tokens  map[string]struct{}
+}
+
+func newMockConsul() *mockConsul {
+	return &mockConsul{index: 1, kv: make(map[string][]byte), changed: make(chan struct{}), tokens: make(map[string]struct{})}
+}
+
+func (m *mockConsul) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.URL.Path, "/v1/kv/")
+	query := r.URL.Query()
+	m.lock.Lock()
+	m.tokens[r.Header.Get(consulTokenHeader)] = struct{}{}
+	m.lock.Unlock()
+	switch r.Method {
+	case http.MethodGet:
+		if index, _ := strconv.ParseUint(query.Get("index"), 10, 64); index > 0 {
+			wait, _ := time.ParseDuration(query.Get("wait"))
+			m.block(r, index, wait)
+		}
+		m.lock.Lock()
+		defer m.lock.Unlock()
+		w.Header().Set(consulIndexHeader, strconv.FormatUint(m.index, 10))
+		if _, ok := query["keys"]; ok {
+			keys := make([]string, 0)
+			for k := range m.kv {
+				if strings.HasPrefix(k, key) {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+			_ = json.NewEncoder(w).Encode(keys)
+			return
+		}
+		value, ok := m.kv[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(value)
+	case http.MethodPut:
+		value, _ := ioutil.ReadAll(r.Body)
+		m.update(func() { m.kv[key] = value })
+		_, _ = w.Write([]byte("true"))
+	case http.MethodDelete:
+		m.update(func() { delete(m.kv, key) })
+		_, _ = w.Write([]byte("true"))
+	}
+}
+
+// block waits until the index goes beyond @index or @wait elapses
+func (m *mockConsul) block(r *http.Request, index uint64, wait time.Duration) {
+	timeout := time.After(wait)
+	for {
+		m.lock.Lock()
+		current, changed := m.index, m.changed
+		m.lock.Unlock()
+		if current > index {
+			return
+		}
+		select {
+		case <-changed:
+		case <-timeout:
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func (m *mockConsul) update(f func()) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	f()
+	m.index++
+	close(m.changed)
+	m.changed = make(chan struct{})
+}
+
+type consulListener struct {
+	events chan *config_center.ConfigChangeEvent
+}
+
+func (l *consulListener) Process(event *config_center.ConfigChangeEvent) {
+	l.events <- event
+}
+
+func (l *consulListener) next(t *testing.T) *config_center.ConfigChangeEvent {
+	select {
+	case event := <-l.events:
+		return event
+	case <-time.After(3 * time.Second):
+		assert.FailNow(t, "the listener isn't notified of the change")
+		return nil
+	}
+}
+
+func initConsulConfiguration(t *testing.T) (*consulDynamicConfiguration, *mockConsul) {
+	consul := newMockConsul()
+	server := httptest.NewServer(consul)
+	t.Cleanup(server.Close)
+	url, err := common.NewURL("consul://"+strings.TrimPrefix(server.URL, "http://"),
+		common.WithParamsValue(constant.CONFIG_TOKEN_KEY, "acl-token"),
+		common.WithParamsValue(constant.CONFIG_WATCH_WAIT_KEY, "200ms"))
+	assert.NoError(t, err)
+	configuration, err := (&consulDynamicConfigurationFactory{}).GetDynamicConfiguration(url)
+	assert.NoError(t, err)
+	c := configuration.(*consulDynamicConfiguration)
+	t.Cleanup(c.Destroy)
+	return c, consul
+}
+
+func TestConsulPublishAndGetConfig(t *testing.T) {
+	c, consul := initConsulConfiguration(t)
+
+	assert.NoError(t, c.PublishConfig("dubbo.properties", "", "dubbo.application.name=demo"))
+	assert.NoError(t, c.PublishConfig("demo.condition-router", "governance", "scope: application"))
+	assert.Contains(t, consul.kv, "dubbo/config/dubbo/dubbo.properties")
+	assert.Contains(t, consul.tokens, "acl-token")
+
+	value, err := c.GetProperties("dubbo.properties", config_center.WithGroup("dubbo"))
+	assert.NoError(t, err)
+	assert.Equal(t, "dubbo.application.name=demo", value)
+	value, err = c.GetRule("demo.condition-router", config_center.WithGroup("governance"))
+	assert.NoError(t, err)
+	assert.Equal(t, "scope: application", value)
+	_, err = c.GetProperties("missing")
+	assert.Error(t, err)
+
+	keys, err := c.GetConfigKeysByGroup("governance")
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"demo.condition-router"}, keys.Values())
+	_, err = c.GetConfigKeysByGroup("missing")
+	assert.Error(t, err)
+
+	assert.NoError(t, c.RemoveConfig("demo.condition-router", "governance"))
+	_, err = c.GetRule("demo.condition-router", config_center.WithGroup("governance"))
+	assert.Error(t, err)
+	// the missing key is removed as well
+	assert.NoError(t, c.RemoveConfig("demo.condition-router", "governance"))
+}
+
+func TestConsulListener(t *testing.T) {
+	c, _ := initConsulConfiguration(t)
+	assert.NoError(t, c.PublishConfig("demo.tag-router", "", "tags: []"))
+
+	listener := &consulListener{events: make(chan *config_center.ConfigChangeEvent, 16)}
+	c.AddListener("demo.tag-router", listener)
+	c.AddListener("demo.configurators", listener, config_center.WithGroup("dubbo"))
+
+	assert.NoError(t, c.PublishConfig("demo.tag-router", "", "tags: [gray]"))
+	assert.Equal(t, &config_center.ConfigChangeEvent{
+		Key: "demo.tag-router", Value: "tags: [gray]", ConfigType: remoting.EventTypeUpdate,
+	}, listener.next(t))
+
+	assert.NoError(t, c.PublishConfig("demo.configurators", "dubbo", "configs: []"))
+	assert.Equal(t, &config_center.ConfigChangeEvent{
+		Key: "demo.configurators", Value: "configs: []", ConfigType: remoting.EventTypeAdd,
+	}, listener.next(t))
+
+	assert.NoError(t, c.RemoveConfig("demo.tag-router", ""))
+	assert.Equal(t, &config_center.ConfigChangeEvent{
+		Key: "demo.tag-router", Value: "", ConfigType: remoting.EventTypeDel,
+	}, listener.next(t))
+
+	// the keys aren't watched without the listeners
+	c.RemoveListener("demo.tag-router", listener)
+	c.RemoveListener("demo.configurators", listener)
+	assert.Empty(t, c.watchers)
+	assert.NoError(t, c.PublishConfig("demo.tag-router", "", "tags: []"))
+	select {
+	case event := <-listener.events:
+		assert.Fail(t, "the listener removed is notified", event.Key)
+	case <-time.After(300 * time.Millisecond):
+	}
+}
+
+func TestConsulAddListenerFetchWithoutLock(t *testing.T) {
+	consul := newMockConsul()
+	stalled, stall := make(chan struct{}), make(chan struct{})
+	var once sync.Once
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "demo.stalled") {
+			once.Do(func() { close(stalled) })
+			<-stall
+		}
+		consul.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	url, err := common.NewURL("consul://"+strings.TrimPrefix(server.URL, "http://"),
+		common.WithParamsValue(constant.CONFIG_WATCH_WAIT_KEY, "200ms"))
+	assert.NoError(t, err)
+	c, err := newConsulDynamicConfiguration(url)
+	assert.NoError(t, err)
+	t.Cleanup(c.Destroy)
+	var release sync.Once
+	// the stalled fetch is released before it's destroyed even if it fails
+	t.Cleanup(func() { release.Do(func() { close(stall) }) })
+
+	listener := &consulListener{events: make(chan *config_center.ConfigChangeEvent, 16)}
+	added := make(chan struct{})
+	go func() {
+		defer close(added)
+		c.AddListener("demo.stalled", listener)
+	}()
+	<-stalled
+
+	// the other keys are listened while the value of the stalled one is being fetched
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.AddListener("demo.tag-router", listener)
+		c.RemoveListener("demo.tag-router", listener)
+	}()
+	select {
+	case <-done:
+	case <-time.After(3 * time.Second):
+		assert.FailNow(t, "the listeners are blocked by the fetch of another key")
+	}
+
+	release.Do(func() { close(stall) })
+	<-added
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	assert.Contains(t, c.watchers, c.getPath("demo.stalled", config_center.DEFAULT_GROUP))
+}
+
+func TestConsulWatchConfig(t *testing.T) {
+	c, _ := initConsulConfiguration(t)
+	events, cancel := c.WatchConfig("dubbo.properties")
+	assert.NoError(t, c.PublishConfig("dubbo.properties", "", "dubbo.application.name=watched"))
+	select {
+	case event := <-events:
+		assert.Equal(t, "dubbo.application.name=watched", event.Value)
+	case <-time.After(3 * time.Second):
+		assert.FailNow(t, "the change isn't delivered on the channel")
+	}
+	cancel()
+	for range events {
+	}
+	assert.Empty(t, c.watchers)
+}
+
+// gaugeReporter records the gauges by their names and tags
+type gaugeReporter struct {
+	sync.Mutex
+	gauges map[string]float64
+}
+
+func (r *gaugeReporter) IncCounter(_ string, _ float64, _ map[string]string) {}
+
+func (r *gaugeReporter) SetGauge(name string, value float64, tags map[string]string) {
+	r.Lock()
+	defer r.Unlock()
+	r.gauges[name+"@"+tags[metrics.AddressTag]] = value
+}
+
+func (r *gaugeReporter) ObserveHistogram(_ string, _ float64, _ map[string]string) {}
+
+func (r *gaugeReporter) gauge(name string) (float64, bool) {
+	r.Lock()
+	defer r.Unlock()
+	value, ok := r.gauges[name]
+	return value, ok
+}
+
+func TestConsulHealth(t *testing.T) {
+	reporter := &gaugeReporter{gauges: map[string]float64{}}
+	metrics.SetHealthReporter(metrics.NewHealthReporter(reporter))
+	defer metrics.SetHealthReporter(nil)
+
+	server := httptest.NewServer(newMockConsul())
+	address := strings.TrimPrefix(server.URL, "http://")
+	url, err := common.NewURL("consul://" + address)
+	assert.NoError(t, err)
+	c, err := newConsulDynamicConfiguration(url)
+	assert.NoError(t, err)
+	defer c.Destroy()
+
+	// the successful requests report the connected state and the latest poll
+	assert.NoError(t, c.PublishConfig("dubbo.properties", "", "dubbo.application.name=demo"))
+	value, ok := reporter.gauge(metrics.ConfigCenterConnected + "@" + address)
+	assert.True(t, ok)
+	assert.Equal(t, 1.0, value)
+	_, ok = reporter.gauge(metrics.ConfigCenterLastPollTimestamp + "@" + address)
+	assert.True(t, ok)
+
+	// and the failed ones report the disconnected state
+	server.Close()
+	_, err = c.GetProperties("dubbo.properties")
+	assert.Error(t, err)
+	value, _ = reporter.gauge(metrics.ConfigCenterConnected + "@" + address)
+	assert.Equal(t, 0.0, value)
+}
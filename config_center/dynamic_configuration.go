@@ -18,6 +18,7 @@
 package config_center
 
 import (
+	"sort"
 	"time"
 )
 
@@ -65,6 +66,17 @@ type DynamicConfiguration interface {
 
 	// GetConfigKeysByGroup will return all keys with the group
 	GetConfigKeysByGroup(group string) (*gxset.HashSet, error)
+
+	// GetConfigKeysByGroupPaged returns at most @limit keys of the group from @offset in the order of the keys,
+	// and the total number of the keys, so that the groups of lots of keys are iterated page by page
+	GetConfigKeysByGroupPaged(group string, offset, limit int) ([]string, int, error)
+
+	// Refresh re-fetches the watched configs immediately and notifies the listeners of the changes
+	Refresh() error
+
+	// WatchConfig streams the changes of the config key on the channel instead of a listener,
+	// and the cancel func stops the stream
+	WatchConfig(string, ...Option) (<-chan ConfigChangeEvent, func())
 }
 
 // Options ...
@@ -90,6 +102,28 @@ func WithTimeout(time time.Duration) Option {
 	}
 }
 
+// PageKeys returns the sorted @keys from @offset of at most @limit ones, and all of them from @offset
+// if @limit isn't positive. The total number of the keys is returned as well.
+func PageKeys(keys *gxset.HashSet, offset, limit int) ([]string, int) {
+	sorted := make([]string, 0, keys.Size())
+	for _, key := range keys.Values() {
+		sorted = append(sorted, key.(string))
+	}
+	sort.Strings(sorted)
+	total := len(sorted)
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > total {
+		offset = total
+	}
+	end := total
+	if limit > 0 && offset+limit < total {
+		end = offset + limit
+	}
+	return sorted[offset:end], total
+}
+
 // GetRuleKey The format is '{interfaceName}:[version]:[group]'
 func GetRuleKey(url *common.URL) string {
 	return url.ColonSeparatedKey()
@@ -25,3 +25,12 @@ import (
 type DynamicConfigurationFactory interface {
 	GetDynamicConfiguration(*common.URL) (DynamicConfiguration, error)
 }
+
+// ExclusiveDynamicConfigurationFactory is implemented by the factories whose DynamicConfigurations share the
+// process-global client, e.g. apollo, so that a new one takes over the state of the one in use. The config center
+// of theirs can't be switched from or to at runtime.
+type ExclusiveDynamicConfigurationFactory interface {
+	DynamicConfigurationFactory
+	// Exclusive marks the factory, it does nothing
+	Exclusive()
+}
@@ -176,6 +176,21 @@ func (fsdc *FileSystemDynamicConfiguration) GetConfigKeysByGroup(group string) (
 	return r, nil
 }
 
+// GetConfigKeysByGroupPaged will return the page of the keys with the group
+func (fsdc *FileSystemDynamicConfiguration) GetConfigKeysByGroupPaged(group string, offset, limit int) ([]string, int, error) {
+	keys, err := fsdc.GetConfigKeysByGroup(group)
+	if err != nil {
+		return nil, 0, err
+	}
+	page, total := config_center.PageKeys(keys, offset, limit)
+	return page, total, nil
+}
+
+// WatchConfig streams the changes of the config @key on the channel until cancel is called
+func (fsdc *FileSystemDynamicConfiguration) WatchConfig(key string, opts ...config_center.Option) (<-chan config_center.ConfigChangeEvent, func()) {
+	return config_center.WatchConfig(fsdc, key, opts...)
+}
+
 // RemoveConfig will remove tconfig_center/nacos/impl_testhe config whit hte (key, group)
 func (fsdc *FileSystemDynamicConfiguration) RemoveConfig(key string, group string) error {
 	tmpPath := fsdc.GetPath(key, group)
@@ -51,7 +51,11 @@ func NewCacheListener(rootPath string) *CacheListener {
 	go func() {
 		for {
 			select {
-			case event := <-watch.Events:
+			case event, ok := <-watch.Events:
+				if !ok {
+					// the watcher is closed
+					return
+				}
 				key := event.Name
 				logger.Debugf("watcher %s, event %v", cl.rootPath, event)
 				if event.Op&fsnotify.Write == fsnotify.Write {
@@ -71,7 +75,10 @@ func NewCacheListener(rootPath string) *CacheListener {
 						removeCallback(l.(map[config_center.ConfigurationListener]struct{}), key, remoting.EventTypeDel)
 					}
 				}
-			case err := <-watch.Errors:
+			case err, ok := <-watch.Errors:
+				if !ok {
+					return
+				}
 				// err may be nil, ignore
 				if err != nil {
 					logger.Warnf("file : listen watch fail:%+v", err)
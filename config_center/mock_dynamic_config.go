@@ -96,6 +96,21 @@ func (c *MockDynamicConfiguration) GetConfigKeysByGroup(group string) (*gxset.Ha
 	return gxset.NewSet(c.content), nil
 }
 
+// GetConfigKeysByGroupPaged will return the page of the keys with the group
+func (c *MockDynamicConfiguration) GetConfigKeysByGroupPaged(group string, offset, limit int) ([]string, int, error) {
+	keys, err := c.GetConfigKeysByGroup(group)
+	if err != nil {
+		return nil, 0, err
+	}
+	page, total := PageKeys(keys, offset, limit)
+	return page, total, nil
+}
+
+// WatchConfig streams the changes of the config @key on the channel until cancel is called
+func (c *MockDynamicConfiguration) WatchConfig(key string, opts ...Option) (<-chan ConfigChangeEvent, func()) {
+	return WatchConfig(c, key, opts...)
+}
+
 // MockDynamicConfiguration uses to parse content and defines listener
 type MockDynamicConfiguration struct {
 	BaseDynamicConfiguration
@@ -37,6 +37,7 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/common/logger"
 	"dubbo.apache.org/dubbo-go/v3/config_center"
 	"dubbo.apache.org/dubbo-go/v3/config_center/parser"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
 )
 
 const (
@@ -59,6 +60,7 @@ type nacosDynamicConfiguration struct {
 	client       *nacosClient.NacosConfigClient
 	keyListeners sync.Map
 	parser       parser.ConfigurationParser
+	health       *metrics.ConnectionHealth
 }
 
 func newNacosDynamicConfiguration(url *common.URL) (*nacosDynamicConfiguration, error) {
@@ -66,12 +68,14 @@ func newNacosDynamicConfiguration(url *common.URL) (*nacosDynamicConfiguration,
 		rootPath: "/" + url.GetParam(constant.CONFIG_NAMESPACE_KEY, config_center.DEFAULT_GROUP) + "/config",
 		url:      url,
 		done:     make(chan struct{}),
+		health:   metrics.NewConnectionHealth(metrics.ConfigCenterComponent, url),
 	}
 	err := ValidateNacosClient(c)
 	if err != nil {
 		logger.Errorf("nacos configClient start error ,error message is %v", err)
 		return nil, err
 	}
+	c.health.SetConnected(true)
 	c.wg.Add(1)
 	go HandleClientRestart(c)
 	return c, err
@@ -106,6 +110,7 @@ func (n *nacosDynamicConfiguration) PublishConfig(key string, group string, valu
 		Group:   group,
 		Content: value,
 	})
+	n.reportHealth(err)
 	if err != nil {
 		return perrors.WithStack(err)
 	}
@@ -125,6 +130,7 @@ func (n *nacosDynamicConfiguration) GetConfigKeysByGroup(group string) (*gxset.H
 		// actually it's impossible for user to create 9999 application under one group
 		PageSize: maxKeysNum,
 	})
+	n.reportHealth(err)
 
 	result := gxset.NewSet()
 	if err != nil {
@@ -136,6 +142,21 @@ func (n *nacosDynamicConfiguration) GetConfigKeysByGroup(group string) (*gxset.H
 	return result, nil
 }
 
+// GetConfigKeysByGroupPaged will return the page of the keys with the group in the order of the keys
+func (n *nacosDynamicConfiguration) GetConfigKeysByGroupPaged(group string, offset, limit int) ([]string, int, error) {
+	keys, err := n.GetConfigKeysByGroup(group)
+	if err != nil {
+		return nil, 0, err
+	}
+	page, total := config_center.PageKeys(keys, offset, limit)
+	return page, total, nil
+}
+
+// WatchConfig streams the changes of the config @key on the channel until cancel is called
+func (n *nacosDynamicConfiguration) WatchConfig(key string, opts ...config_center.Option) (<-chan config_center.ConfigChangeEvent, func()) {
+	return config_center.WatchConfig(n, key, opts...)
+}
+
 // GetRule Get router rule
 func (n *nacosDynamicConfiguration) GetRule(key string, opts ...config_center.Option) (string, error) {
 	tmpOpts := &config_center.Options{}
@@ -146,6 +167,7 @@ func (n *nacosDynamicConfiguration) GetRule(key string, opts ...config_center.Op
 		DataId: key,
 		Group:  n.resolvedGroup(tmpOpts.Group),
 	})
+	n.reportHealth(err)
 	if err != nil {
 		return "", perrors.WithStack(err)
 	} else {
@@ -153,6 +175,16 @@ func (n *nacosDynamicConfiguration) GetRule(key string, opts ...config_center.Op
 	}
 }
 
+// reportHealth reports the health of the connection to nacos by the result @err of a request, the connection
+// is regarded as lost once a request fails until another one succeeds
+func (n *nacosDynamicConfiguration) reportHealth(err error) {
+	if err != nil {
+		n.health.SetConnected(false)
+		return
+	}
+	n.health.Active()
+}
+
 // Parser Get Parser
 func (n *nacosDynamicConfiguration) Parser() parser.ConfigurationParser {
 	return n.parser
@@ -42,9 +42,11 @@ func (n *nacosDynamicConfiguration) addListener(key string, listener config_cent
 			DataId: key,
 			Group:  "dubbo",
 			OnChange: func(namespace, group, dataId, data string) {
+				n.health.Active()
 				go callback(listener, namespace, group, dataId, data)
 			},
 		})
+		n.reportHealth(err)
 		if err != nil {
 			logger.Errorf("nacos : listen config fail, error:%v ", err)
 			return
@@ -140,7 +140,7 @@ func serviceItemToUrls(item ConfigItem, config ConfiguratorConfig) ([]*common.UR
 		if len(apps) > 0 {
 			for _, v := range apps {
 				newUrlStr := urlStr
-				newUrlStr = newUrlStr + "&application"
+				newUrlStr = newUrlStr + "&application="
 				newUrlStr = newUrlStr + v
 				url, err := common.NewURL(newUrlStr)
 				if err != nil {
@@ -167,7 +167,7 @@ func appItemToUrls(item ConfigItem, config ConfiguratorConfig) ([]*common.URL, e
 	}
 	var urls []*common.URL
 	for _, v := range addresses {
-		urlStr := constant.OVERRIDE_PROTOCOL + "://" + v + "/"
+		addressStr := constant.OVERRIDE_PROTOCOL + "://" + v + "/"
 		services := item.Services
 		if len(services) == 0 {
 			services = append(services, constant.ANY_VALUE)
@@ -177,7 +177,7 @@ func appItemToUrls(item ConfigItem, config ConfiguratorConfig) ([]*common.URL, e
 			if err != nil {
 				return nil, perrors.WithStack(err)
 			}
-			urlStr = urlStr + serviceStr
+			urlStr := addressStr + serviceStr
 			paramStr, err := getParamString(item)
 			if err != nil {
 				return nil, perrors.WithStack(err)
@@ -0,0 +1,276 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_center
+
+import (
+	"io"
+	"sync"
+)
+
+import (
+	gxset "github.com/dubbogo/gost/container/set"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/config_center/parser"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+type listenedKey struct {
+	key   string
+	group string
+}
+
+type listenerEntry struct {
+	listener ConfigurationListener
+	opts     []Option
+}
+
+// SwitchableDynamicConfiguration delegates to a DynamicConfiguration which can be switched to another one at runtime,
+// e.g. a config center at a new address. It keeps the listeners added through it, so that they are moved to the new
+// source on switching and notified of the values differing between the two sources.
+type SwitchableDynamicConfiguration struct {
+	switching sync.Mutex
+	mutex     sync.RWMutex
+	delegate  DynamicConfiguration
+	listeners map[listenedKey][]listenerEntry
+}
+
+// NewSwitchableDynamicConfiguration creates the SwitchableDynamicConfiguration delegating to @delegate
+func NewSwitchableDynamicConfiguration(delegate DynamicConfiguration) *SwitchableDynamicConfiguration {
+	return &SwitchableDynamicConfiguration{
+		delegate:  delegate,
+		listeners: make(map[listenedKey][]listenerEntry),
+	}
+}
+
+// Delegate returns the DynamicConfiguration in use
+func (s *SwitchableDynamicConfiguration) Delegate() DynamicConfiguration {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.delegate
+}
+
+// Switch moves the listeners to @to, which serves all of the calls afterwards, and destroys the one in use.
+// The listeners are notified of the keys whose values differ between the two sources once the switch is done.
+func (s *SwitchableDynamicConfiguration) Switch(to DynamicConfiguration) {
+	if to == nil {
+		return
+	}
+	// the switches are serialized, so that the diffs are made against the source switched from
+	s.switching.Lock()
+	defer s.switching.Unlock()
+	from := s.Delegate()
+	if from == to {
+		return
+	}
+	if to.Parser() == nil {
+		to.SetParser(from.Parser())
+	}
+	// the values are got from both of the sources out of the lock, which doesn't block the calls meanwhile
+	events := s.diffEvents(s.listenedKeys(), from, to)
+
+	s.mutex.Lock()
+	pending := make(map[ConfigurationListener][]*ConfigChangeEvent)
+	var added []listenedKey
+	for lk, entries := range s.listeners {
+		event, diffed := events[lk]
+		if !diffed {
+			// the key is listened to during the diffs
+			added = append(added, lk)
+		}
+		for _, entry := range entries {
+			to.AddListener(lk.key, entry.listener, entry.opts...)
+			from.RemoveListener(lk.key, entry.listener, entry.opts...)
+			if event != nil {
+				pending[entry.listener] = append(pending[entry.listener], event)
+			}
+		}
+	}
+	s.delegate = to
+	s.mutex.Unlock()
+
+	for lk, event := range s.diffEvents(added, from, to) {
+		if event == nil {
+			continue
+		}
+		for _, entry := range s.entriesOf(lk) {
+			pending[entry.listener] = append(pending[entry.listener], event)
+		}
+	}
+	// the listeners may call back, so they are notified out of the lock
+	for listener, events := range pending {
+		for _, event := range events {
+			listener.Process(event)
+		}
+	}
+	DestroyDynamicConfiguration(from)
+}
+
+// listenedKeys returns the keys listened to with the options of their first listeners
+func (s *SwitchableDynamicConfiguration) listenedKeys() []listenedKey {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	keys := make([]listenedKey, 0, len(s.listeners))
+	for lk := range s.listeners {
+		keys = append(keys, lk)
+	}
+	return keys
+}
+
+func (s *SwitchableDynamicConfiguration) entriesOf(lk listenedKey) []listenerEntry {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.listeners[lk]
+}
+
+// diffEvents returns the events of @keys changed from @from to @to, which are nil for the ones unchanged
+func (s *SwitchableDynamicConfiguration) diffEvents(keys []listenedKey, from, to DynamicConfiguration) map[listenedKey]*ConfigChangeEvent {
+	events := make(map[listenedKey]*ConfigChangeEvent, len(keys))
+	for _, lk := range keys {
+		entries := s.entriesOf(lk)
+		if len(entries) == 0 {
+			continue
+		}
+		events[lk] = diffEvent(lk.key, entries[0].opts, from, to)
+	}
+	return events
+}
+
+// diffEvent returns the event changing the value of @key in @from to the one in @to, or nil if they're the same
+func diffEvent(key string, opts []Option, from, to DynamicConfiguration) *ConfigChangeEvent {
+	// the value not published yet is the same as an empty one
+	oldValue, _ := from.GetProperties(key, opts...)
+	newValue, _ := to.GetProperties(key, opts...)
+	if oldValue == newValue {
+		return nil
+	}
+	event := &ConfigChangeEvent{Key: key, Value: newValue, ConfigType: remoting.EventTypeUpdate}
+	switch {
+	case oldValue == "":
+		event.ConfigType = remoting.EventTypeAdd
+	case newValue == "":
+		event.ConfigType = remoting.EventTypeDel
+	}
+	return event
+}
+
+// DestroyDynamicConfiguration destroys or closes @dc if it supports either, e.g. the one switched from
+func DestroyDynamicConfiguration(dc DynamicConfiguration) {
+	switch d := dc.(type) {
+	case interface{ Destroy() }:
+		d.Destroy()
+	case io.Closer:
+		if err := d.Close(); err != nil {
+			logger.Warnf("Close the dynamic configuration error: %v", err)
+		}
+	}
+}
+
+// Parser returns the parser of the DynamicConfiguration in use
+func (s *SwitchableDynamicConfiguration) Parser() parser.ConfigurationParser {
+	return s.Delegate().Parser()
+}
+
+// SetParser sets the parser of the DynamicConfiguration in use
+func (s *SwitchableDynamicConfiguration) SetParser(p parser.ConfigurationParser) {
+	s.Delegate().SetParser(p)
+}
+
+// AddListener adds the listener to the DynamicConfiguration in use, and keeps it for switching
+func (s *SwitchableDynamicConfiguration) AddListener(key string, listener ConfigurationListener, opts ...Option) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	lk := listenedKey{key: key, group: groupOf(opts)}
+	s.listeners[lk] = append(s.listeners[lk], listenerEntry{listener: listener, opts: opts})
+	s.delegate.AddListener(key, listener, opts...)
+}
+
+// RemoveListener removes the listener from the DynamicConfiguration in use
+func (s *SwitchableDynamicConfiguration) RemoveListener(key string, listener ConfigurationListener, opts ...Option) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	lk := listenedKey{key: key, group: groupOf(opts)}
+	entries := s.listeners[lk]
+	for i, entry := range entries {
+		if entry.listener == listener {
+			entries = append(entries[:i:i], entries[i+1:]...)
+			break
+		}
+	}
+	if len(entries) == 0 {
+		delete(s.listeners, lk)
+	} else {
+		s.listeners[lk] = entries
+	}
+	s.delegate.RemoveListener(key, listener, opts...)
+}
+
+func groupOf(opts []Option) string {
+	options := &Options{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options.Group
+}
+
+// GetProperties gets the properties file from the DynamicConfiguration in use
+func (s *SwitchableDynamicConfiguration) GetProperties(key string, opts ...Option) (string, error) {
+	return s.Delegate().GetProperties(key, opts...)
+}
+
+// GetRule gets the rule from the DynamicConfiguration in use
+func (s *SwitchableDynamicConfiguration) GetRule(key string, opts ...Option) (string, error) {
+	return s.Delegate().GetRule(key, opts...)
+}
+
+// GetInternalProperty gets the internal property from the DynamicConfiguration in use
+func (s *SwitchableDynamicConfiguration) GetInternalProperty(key string, opts ...Option) (string, error) {
+	return s.Delegate().GetInternalProperty(key, opts...)
+}
+
+// PublishConfig publishes the config to the DynamicConfiguration in use
+func (s *SwitchableDynamicConfiguration) PublishConfig(key string, group string, value string) error {
+	return s.Delegate().PublishConfig(key, group, value)
+}
+
+// RemoveConfig removes the config from the DynamicConfiguration in use
+func (s *SwitchableDynamicConfiguration) RemoveConfig(key string, group string) error {
+	return s.Delegate().RemoveConfig(key, group)
+}
+
+// GetConfigKeysByGroup returns all keys of the group in the DynamicConfiguration in use
+func (s *SwitchableDynamicConfiguration) GetConfigKeysByGroup(group string) (*gxset.HashSet, error) {
+	return s.Delegate().GetConfigKeysByGroup(group)
+}
+
+// GetConfigKeysByGroupPaged returns the page of the keys of the group in the DynamicConfiguration in use
+func (s *SwitchableDynamicConfiguration) GetConfigKeysByGroupPaged(group string, offset, limit int) ([]string, int, error) {
+	return s.Delegate().GetConfigKeysByGroupPaged(group, offset, limit)
+}
+
+// WatchConfig streams the changes of the config @key on the channel, and it keeps streaming across the switches
+func (s *SwitchableDynamicConfiguration) WatchConfig(key string, opts ...Option) (<-chan ConfigChangeEvent, func()) {
+	return WatchConfig(s, key, opts...)
+}
+
+// Refresh refreshes the DynamicConfiguration in use
+func (s *SwitchableDynamicConfiguration) Refresh() error {
+	return s.Delegate().Refresh()
+}
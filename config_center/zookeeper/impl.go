@@ -38,6 +38,7 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/config"
 	"dubbo.apache.org/dubbo-go/v3/config_center"
 	"dubbo.apache.org/dubbo-go/v3/config_center/parser"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
 	"dubbo.apache.org/dubbo-go/v3/remoting/zookeeper"
 )
 
@@ -60,12 +61,14 @@ type zookeeperDynamicConfiguration struct {
 	parser        parser.ConfigurationParser
 
 	base64Enabled bool
+	health        *metrics.ConnectionHealth
 }
 
 func newZookeeperDynamicConfiguration(url *common.URL) (*zookeeperDynamicConfiguration, error) {
 	c := &zookeeperDynamicConfiguration{
 		url:      url,
 		rootPath: "/" + url.GetParam(constant.CONFIG_NAMESPACE_KEY, config_center.DEFAULT_GROUP) + "/config",
+		health:   metrics.NewConnectionHealth(metrics.ConfigCenterComponent, url),
 	}
 	if v, ok := config.GetRootConfig().ConfigCenter.Params["base64"]; ok {
 		base64Enabled, err := strconv.ParseBool(v)
@@ -80,6 +83,7 @@ func newZookeeperDynamicConfiguration(url *common.URL) (*zookeeperDynamicConfigu
 		logger.Errorf("zookeeper client start error ,error message is %v", err)
 		return nil, err
 	}
+	c.health.SetConnected(true)
 	c.wg.Add(1)
 	go zookeeper.HandleClientRestart(c)
 
@@ -123,6 +127,7 @@ func (c *zookeeperDynamicConfiguration) GetProperties(key string, opts ...config
 	if err != nil {
 		return "", perrors.WithStack(err)
 	}
+	c.health.Active()
 	if !c.base64Enabled {
 		return string(content), nil
 	}
@@ -171,6 +176,21 @@ func (c *zookeeperDynamicConfiguration) GetConfigKeysByGroup(group string) (*gxs
 	return set, nil
 }
 
+// GetConfigKeysByGroupPaged will return the page of the keys with the group
+func (c *zookeeperDynamicConfiguration) GetConfigKeysByGroupPaged(group string, offset, limit int) ([]string, int, error) {
+	keys, err := c.GetConfigKeysByGroup(group)
+	if err != nil {
+		return nil, 0, err
+	}
+	page, total := config_center.PageKeys(keys, offset, limit)
+	return page, total, nil
+}
+
+// WatchConfig streams the changes of the config @key on the channel until cancel is called
+func (c *zookeeperDynamicConfiguration) WatchConfig(key string, opts ...config_center.Option) (<-chan config_center.ConfigChangeEvent, func()) {
+	return config_center.WatchConfig(c, key, opts...)
+}
+
 func (c *zookeeperDynamicConfiguration) GetRule(key string, opts ...config_center.Option) (string, error) {
 	return c.GetProperties(key, opts...)
 }
@@ -237,6 +257,11 @@ func (c *zookeeperDynamicConfiguration) RestartCallBack() bool {
 	return true
 }
 
+// Health returns the health of the connection to the config center reported to the health metrics
+func (c *zookeeperDynamicConfiguration) Health() *metrics.ConnectionHealth {
+	return c.health
+}
+
 func (c *zookeeperDynamicConfiguration) getPath(key string, group string) string {
 	if len(key) == 0 {
 		return c.buildPath(group)
@@ -0,0 +1,90 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package zookeeper
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	gxzookeeper "github.com/dubbogo/gost/database/kv/zk"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+	"dubbo.apache.org/dubbo-go/v3/remoting/zookeeper"
+)
+
+// gaugeReporter records the gauges by their names and tags
+type gaugeReporter struct {
+	sync.Mutex
+	gauges map[string]float64
+}
+
+func (r *gaugeReporter) IncCounter(_ string, _ float64, _ map[string]string) {}
+
+func (r *gaugeReporter) SetGauge(name string, value float64, tags map[string]string) {
+	r.Lock()
+	defer r.Unlock()
+	r.gauges[name+"@"+tags[metrics.AddressTag]] = value
+}
+
+func (r *gaugeReporter) ObserveHistogram(_ string, _ float64, _ map[string]string) {}
+
+func (r *gaugeReporter) gauge(name string) (float64, bool) {
+	r.Lock()
+	defer r.Unlock()
+	value, ok := r.gauges[name]
+	return value, ok
+}
+
+func TestZookeeperDynamicConfigurationHealth(t *testing.T) {
+	reporter := &gaugeReporter{gauges: map[string]float64{}}
+	metrics.SetHealthReporter(metrics.NewHealthReporter(reporter))
+	defer metrics.SetHealthReporter(nil)
+
+	url, err := common.NewURL("registry://127.0.0.1:2181")
+	assert.NoError(t, err)
+	// the client never connected is regarded as the disconnected one
+	c := &zookeeperDynamicConfiguration{
+		url:    url,
+		client: &gxzookeeper.ZookeeperClient{},
+		done:   make(chan struct{}),
+		health: metrics.NewConnectionHealth(metrics.ConfigCenterComponent, url),
+	}
+	const gauge = metrics.ConfigCenterConnected + "@127.0.0.1:2181"
+	c.health.SetConnected(true)
+	value, ok := reporter.gauge(gauge)
+	assert.True(t, ok)
+	assert.Equal(t, 1.0, value)
+
+	c.wg.Add(1)
+	go zookeeper.HandleClientRestart(c)
+	assert.Eventually(t, func() bool {
+		value, _ := reporter.gauge(gauge)
+		return value == 0
+	}, 3*time.Second, 10*time.Millisecond)
+
+	close(c.done)
+	c.wg.Wait()
+}
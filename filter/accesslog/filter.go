@@ -19,13 +19,18 @@ package accesslog
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"reflect"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
 	"dubbo.apache.org/dubbo-go/v3/common/logger"
@@ -50,6 +55,10 @@ const (
 	Types = "types"
 	// nolint
 	Arguments = "arguments"
+	// Request is the full arguments of the sampled invocations
+	Request = "request"
+	// Response is the full result of the sampled invocations
+	Response = "response"
 )
 
 func init() {
@@ -67,6 +76,8 @@ func init() {
  *   interface : "com.ikurento.user.UserProvider"
  *   ... # other configuration
  *   accesslog: "/your/path/to/store/the/log/", # it should be the path of file.
+ *   params:
+ *     accesslog.sample: "0.01" # the full request and response of 1 percent of the invocations are logged
  *
  * the value of "accesslog" can be "true" or "default" too.
  * If the value is one of them, the access log will be record in log file which defined in log.yml
@@ -74,6 +85,8 @@ func init() {
  */
 type Filter struct {
 	logChan chan Data
+	// samples holds the map[string]*uint64 counting the invocations of the methods to spread the sampled ones evenly
+	samples sync.Map
 }
 
 // Invoke will check whether user wants to use this filter.
@@ -82,11 +95,48 @@ func (f *Filter) Invoke(ctx context.Context, invoker protocol.Invoker, invocatio
 	accessLog := invoker.GetURL().GetParam(constant.AccessLogFilterKey, "")
 
 	// the user do not
-	if len(accessLog) > 0 {
-		accessLogData := Data{data: f.buildAccessLogData(invoker, invocation), accessLog: accessLog}
+	if len(accessLog) == 0 {
+		return invoker.Invoke(ctx, invocation)
+	}
+	accessLogData := Data{data: f.buildAccessLogData(invoker, invocation), accessLog: accessLog}
+	if !f.sampled(invoker.GetURL(), invocation.MethodName()) {
 		f.logIntoChannel(accessLogData)
+		return invoker.Invoke(ctx, invocation)
+	}
+	// the sampled invocation is logged once it completes with the response
+	result := invoker.Invoke(ctx, invocation)
+	accessLogData.data[Request] = fmt.Sprintf("%+v", invocation.Arguments())
+	if result.Error() != nil {
+		accessLogData.data[Response] = fmt.Sprintf("error: %v", result.Error())
+	} else {
+		accessLogData.data[Response] = fmt.Sprintf("%+v", result.Result())
+	}
+	f.logIntoChannel(accessLogData)
+	return result
+}
+
+// sampled decides whether the full request and response of the invocation of @method are logged,
+// which happens to the fraction of the invocations configured by the sample key of @url
+func (f *Filter) sampled(url *common.URL, method string) bool {
+	sample := url.GetMethodParam(method, constant.ACCESS_LOG_SAMPLE_KEY, url.GetParam(constant.ACCESS_LOG_SAMPLE_KEY, ""))
+	if len(sample) == 0 {
+		return false
+	}
+	fraction, err := strconv.ParseFloat(sample, 64)
+	if err != nil || fraction <= 0 {
+		return false
+	}
+	if fraction >= 1 {
+		return true
 	}
-	return invoker.Invoke(ctx, invocation)
+	key := url.ServiceKey() + "#" + method
+	counter, ok := f.samples.Load(key)
+	if !ok {
+		counter, _ = f.samples.LoadOrStore(key, new(uint64))
+	}
+	// the invocation is sampled if it reaches the next sample of the invocations
+	n := atomic.AddUint64(counter.(*uint64), 1)
+	return uint64(float64(n)*fraction) != uint64(float64(n-1)*fraction)
 }
 
 // logIntoChannel won't block the invocation
@@ -269,5 +319,11 @@ func (d *Data) toLogMessage() string {
 	if len(d.data[Arguments]) > 0 {
 		builder.WriteString(d.data[Arguments])
 	}
+	if len(d.data[Response]) > 0 {
+		builder.WriteString(" request: ")
+		builder.WriteString(d.data[Request])
+		builder.WriteString(" response: ")
+		builder.WriteString(d.data[Response])
+	}
 	return builder.String()
 }
@@ -19,7 +19,11 @@ package accesslog
 
 import (
 	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 import (
@@ -81,3 +85,58 @@ func TestFilterOnResponse(t *testing.T) {
 	response := accessLogFilter.OnResponse(context.TODO(), result, nil, nil)
 	assert.Equal(t, result, response)
 }
+
+type echoInvoker struct {
+	protocol.BaseInvoker
+}
+
+func (i *echoInvoker) Invoke(_ context.Context, inv protocol.Invocation) protocol.Result {
+	return &protocol.RPCResult{Rest: map[string]interface{}{"echo": inv.Arguments()[0]}}
+}
+
+func TestFilterInvokeSample(t *testing.T) {
+	accessLog := filepath.Join(t.TempDir(), "access.log")
+	accessLogFilter := newFilter()
+	// invoke returns the line logged for the invocation of the method with @sample
+	logged := 0
+	invoke := func(method string, sample string) string {
+		url, err := common.NewURL("dubbo://:20000/UserProvider?interface=com.ikurento.user.UserProvider" +
+			"&accesslog=" + accessLog + "&methods." + method + ".accesslog.sample=" + sample)
+		assert.NoError(t, err)
+		inv := invocation.NewRPCInvocation(method, []interface{}{"ping"}, map[string]interface{}{})
+		result := accessLogFilter.Invoke(context.Background(), &echoInvoker{BaseInvoker: *protocol.NewBaseInvoker(url)}, inv)
+		assert.NoError(t, result.Error())
+
+		var lines []string
+		assert.Eventually(t, func() bool {
+			content, _ := ioutil.ReadFile(accessLog)
+			lines = strings.Split(strings.TrimSpace(string(content)), "\n")
+			return len(content) > 0 && len(lines) > logged
+		}, time.Second, 10*time.Millisecond)
+		logged++
+		return lines[len(lines)-1]
+	}
+
+	// all of the invocations are sampled with the bodies
+	assert.Contains(t, invoke("Sampled", "1"), "request: [ping] response: map[echo:ping]")
+	// none of them is sampled
+	assert.NotContains(t, invoke("Unsampled", "0"), "response:")
+}
+
+func TestFilterSampled(t *testing.T) {
+	url, err := common.NewURL("dubbo://:20000/UserProvider?interface=com.ikurento.user.UserProvider" +
+		"&accesslog.sample=0.1&methods.GetUser.accesslog.sample=0.5")
+	assert.NoError(t, err)
+	accessLogFilter := &Filter{}
+	count := func(method string) int {
+		sampled := 0
+		for i := 0; i < 100; i++ {
+			if accessLogFilter.sampled(url, method) {
+				sampled++
+			}
+		}
+		return sampled
+	}
+	assert.Equal(t, 10, count("GetUsers"))
+	assert.Equal(t, 50, count("GetUser"))
+}
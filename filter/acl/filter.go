@@ -0,0 +1,124 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package acl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+var (
+	aclOnce   sync.Once
+	aclFilter *Filter
+)
+
+func init() {
+	extension.SetFilter(constant.ACLFilterKey, newFilter)
+}
+
+// AccessDeniedError is returned if the consumer application isn't allowed to invoke the method
+type AccessDeniedError struct {
+	// Application is the name of the consumer application, and it's empty if the consumer doesn't tell it
+	Application string
+	// Service is the service key of the provider
+	Service string
+	// Method is the name of the invoked method
+	Method string
+}
+
+func (e *AccessDeniedError) Error() string {
+	return fmt.Sprintf("the consumer application %q is denied to invoke the method %s of the service %s",
+		e.Application, e.Method, e.Service)
+}
+
+// Filter checks the consumer application against the allow and deny lists on the provider side.
+/**
+ * example:
+ * "UserProvider":
+ *   filter: "acl"
+ *   params:
+ *     acl.allow: "order-center,user-center"
+ *     methods.DeleteUser.acl.allow: "user-center"
+ *     methods.GetUser.acl.deny: "legacy-app"
+ * The consumer application is carried by the attachment "remote.application", which is populated by the
+ * proxy of the consumer with the name of its application. The lists of the method override the ones of the
+ * service, the denied applications are rejected in the first place, and then the ones not in the allow
+ * list are rejected if it isn't empty. The rejected requests fail with AccessDeniedError.
+ */
+type Filter struct{}
+
+// newFilter returns the singleton Filter instance
+func newFilter() filter.Filter {
+	aclOnce.Do(func() {
+		aclFilter = &Filter{}
+	})
+	return aclFilter
+}
+
+// Invoke rejects the request if the consumer application isn't allowed to invoke the method
+func (f *Filter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	url := invoker.GetURL()
+	application, _ := invocation.Attachment(constant.REMOTE_APPLICATION_KEY).(string)
+	if !allowed(url, invocation.MethodName(), application) {
+		logger.Warnf("[ACL Filter] the consumer application %q is denied to invoke %s#%s",
+			application, url.ServiceKey(), invocation.MethodName())
+		return &protocol.RPCResult{Err: &AccessDeniedError{
+			Application: application,
+			Service:     url.ServiceKey(),
+			Method:      invocation.MethodName(),
+		}}
+	}
+	return invoker.Invoke(ctx, invocation)
+}
+
+// OnResponse dummy process, returns the result directly
+func (f *Filter) OnResponse(_ context.Context, result protocol.Result, _ protocol.Invoker, _ protocol.Invocation) protocol.Result {
+	return result
+}
+
+func allowed(url *common.URL, method string, application string) bool {
+	if contains(url.GetMethodParam(method, constant.ACL_DENY_KEY, url.GetParam(constant.ACL_DENY_KEY, "")), application) {
+		return false
+	}
+	allowList := url.GetMethodParam(method, constant.ACL_ALLOW_KEY, url.GetParam(constant.ACL_ALLOW_KEY, ""))
+	return allowList == "" || contains(allowList, application)
+}
+
+// contains returns whether the comma separated @list contains @application
+func contains(list string, application string) bool {
+	if application == "" {
+		return false
+	}
+	for _, item := range strings.Split(list, ",") {
+		if strings.TrimSpace(item) == application {
+			return true
+		}
+	}
+	return false
+}
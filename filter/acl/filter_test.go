@@ -0,0 +1,77 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package acl
+
+import (
+	"context"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+func TestFilterInvoke(t *testing.T) {
+	url, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?side=provider" +
+		"&acl.allow=order-center,user-center&methods.DeleteUser.acl.allow=user-center&methods.GetUser.acl.deny=order-center")
+	assert.NoError(t, err)
+	invoker := protocol.NewBaseInvoker(url)
+	filter := newFilter()
+
+	invoke := func(method string, application string) protocol.Result {
+		attachments := map[string]interface{}{}
+		if application != "" {
+			attachments[constant.REMOTE_APPLICATION_KEY] = application
+		}
+		return filter.Invoke(context.Background(), invoker, invocation.NewRPCInvocation(method, nil, attachments))
+	}
+	assertDenied := func(result protocol.Result, method string, application string) {
+		err, ok := result.Error().(*AccessDeniedError)
+		if assert.True(t, ok, "the error %v isn't an AccessDeniedError", result.Error()) {
+			assert.Equal(t, application, err.Application)
+			assert.Equal(t, method, err.Method)
+			assert.Equal(t, url.ServiceKey(), err.Service)
+		}
+	}
+
+	// the service lists
+	assert.NoError(t, invoke("UpdateUser", "order-center").Error())
+	assert.NoError(t, invoke("UpdateUser", "user-center").Error())
+	assertDenied(invoke("UpdateUser", "other-app"), "UpdateUser", "other-app")
+	assertDenied(invoke("UpdateUser", ""), "UpdateUser", "")
+
+	// the lists of the methods override the ones of the service
+	assert.NoError(t, invoke("DeleteUser", "user-center").Error())
+	assertDenied(invoke("DeleteUser", "order-center"), "DeleteUser", "order-center")
+	assert.NoError(t, invoke("GetUser", "user-center").Error())
+	assertDenied(invoke("GetUser", "order-center"), "GetUser", "order-center")
+
+	// all of the consumers are allowed without any list
+	url, err = common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?side=provider")
+	assert.NoError(t, err)
+	invoker = protocol.NewBaseInvoker(url)
+	assert.NoError(t, invoke("GetUser", "other-app").Error())
+	assert.NoError(t, invoke("GetUser", "").Error())
+}
@@ -0,0 +1,151 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package admission
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+var (
+	admissionOnce   sync.Once
+	admissionFilter *Filter
+)
+
+// ErrBusy is the cause of the rejections of the requests which don't get a dispatch slot in time
+var ErrBusy = perrors.New("the provider is busy")
+
+func init() {
+	extension.SetFilter(constant.AdmissionFilterKey, newFilter)
+}
+
+// Filter queues the requests of the service for a bounded time once the dispatch slots are used up,
+// rather than rejecting them at once, so that the short spikes are smoothed.
+/**
+ * example:
+ * "UserProvider":
+ *   filter: "admission,echo,token,accesslog"
+ *   params:
+ *     admission.capacity: "200"
+ *     admission.maxWait: "100ms"
+ *     admission.queue: "400"
+ * At most 200 requests of the service are dispatched at the same time, and the others wait for a slot in the queue
+ * up to 100ms before they are rejected as busy. The requests beyond the 400 ones waiting are rejected at once.
+ * The capacity and the queue of a service are settled by its first request.
+ */
+type Filter struct {
+	// queues holds the *queue by the service keys
+	queues sync.Map
+}
+
+// queue is the dispatch slots of a service and the requests waiting for them
+type queue struct {
+	slots   chan struct{}
+	limit   int64
+	waiting int64
+}
+
+// newFilter returns the singleton Filter instance
+func newFilter() filter.Filter {
+	admissionOnce.Do(func() {
+		admissionFilter = &Filter{}
+	})
+	return admissionFilter
+}
+
+// Invoke dispatches the request once it gets a slot, or rejects it once it waits longer than the admission.maxWait
+func (f *Filter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	url := invoker.GetURL()
+	capacity := url.GetParamInt(constant.ADMISSION_CAPACITY_KEY, 0)
+	if capacity <= 0 {
+		return invoker.Invoke(ctx, invocation)
+	}
+	q := f.queue(url.ServiceKey(), capacity, url.GetParamInt(constant.ADMISSION_QUEUE_KEY, 0))
+
+	select {
+	case q.slots <- struct{}{}:
+	default:
+		if err := q.wait(ctx, invoker, url.GetParamDuration(constant.ADMISSION_MAX_WAIT_KEY, constant.DEFAULT_ADMISSION_MAX_WAIT)); err != nil {
+			logger.Warnf("[Admission Filter] the request to %s#%s is rejected: %v", url.ServiceKey(), invocation.MethodName(), err)
+			return &protocol.RPCResult{Err: err}
+		}
+	}
+	defer func() {
+		<-q.slots
+	}()
+	return invoker.Invoke(ctx, invocation)
+}
+
+// OnResponse dummy process, returns the result directly
+func (f *Filter) OnResponse(_ context.Context, result protocol.Result, _ protocol.Invoker, _ protocol.Invocation) protocol.Result {
+	return result
+}
+
+func (f *Filter) queue(serviceKey string, capacity, limit int64) *queue {
+	if q, ok := f.queues.Load(serviceKey); ok {
+		return q.(*queue)
+	}
+	if limit <= 0 {
+		limit = capacity
+	}
+	q, _ := f.queues.LoadOrStore(serviceKey, &queue{slots: make(chan struct{}, capacity), limit: limit})
+	return q.(*queue)
+}
+
+// wait waits for a dispatch slot up to @maxWait, and the request isn't queued if the queue is full
+func (q *queue) wait(ctx context.Context, invoker protocol.Invoker, maxWait time.Duration) error {
+	depth := atomic.AddInt64(&q.waiting, 1)
+	defer func() {
+		q.report(invoker, atomic.AddInt64(&q.waiting, -1))
+	}()
+	if depth > q.limit {
+		return perrors.WithMessagef(ErrBusy, "%d requests are waiting already", depth-1)
+	}
+	q.report(invoker, depth)
+
+	timer := time.NewTimer(maxWait)
+	defer timer.Stop()
+	select {
+	case q.slots <- struct{}{}:
+		return nil
+	case <-timer.C:
+		return perrors.WithMessagef(ErrBusy, "no dispatch slot is released in %v", maxWait)
+	case <-ctx.Done():
+		return perrors.WithStack(ctx.Err())
+	}
+}
+
+func (q *queue) report(invoker protocol.Invoker, depth int64) {
+	if callback := extension.GetAdmissionQueueCallback(); callback != nil {
+		callback(invoker, int(depth))
+	}
+}
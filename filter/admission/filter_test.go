@@ -0,0 +1,123 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package admission
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+// blockInvoker blocks the requests of the Block method until release is closed
+type blockInvoker struct {
+	protocol.BaseInvoker
+	started sync.WaitGroup
+	release chan struct{}
+}
+
+func (bi *blockInvoker) Invoke(_ context.Context, inv protocol.Invocation) protocol.Result {
+	if inv.MethodName() == "Block" {
+		bi.started.Done()
+		<-bi.release
+	}
+	return &protocol.RPCResult{}
+}
+
+func TestFilterInvoke(t *testing.T) {
+	var depth int64
+	extension.SetAdmissionQueueCallback(func(_ protocol.Invoker, d int) {
+		atomic.StoreInt64(&depth, int64(d))
+	})
+	defer extension.SetAdmissionQueueCallback(nil)
+
+	url, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?side=provider" +
+		"&admission.capacity=2&admission.maxWait=300ms&admission.queue=2")
+	assert.NoError(t, err)
+	f := newFilter()
+	// saturate the capacity with 2 requests blocked
+	saturate := func() (*blockInvoker, *sync.WaitGroup) {
+		invoker := &blockInvoker{BaseInvoker: *protocol.NewBaseInvoker(url), release: make(chan struct{})}
+		var done sync.WaitGroup
+		for i := 0; i < 2; i++ {
+			invoker.started.Add(1)
+			done.Add(1)
+			go func() {
+				defer done.Done()
+				assert.NoError(t, f.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("Block", nil, nil)).Error())
+			}()
+		}
+		invoker.started.Wait()
+		return invoker, &done
+	}
+
+	// the requests released within the wait window are dispatched
+	invoker, blocked := saturate()
+	var queued sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		queued.Add(1)
+		go func() {
+			defer queued.Done()
+			assert.NoError(t, f.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("GetUser", nil, nil)).Error())
+		}()
+	}
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt64(&depth) == 2
+	}, time.Second, time.Millisecond)
+	// and the ones beyond the queue are rejected at once
+	result := f.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("GetUser", nil, nil))
+	assert.True(t, perrors.Is(result.Error(), ErrBusy))
+	time.Sleep(100 * time.Millisecond)
+	close(invoker.release)
+	queued.Wait()
+	blocked.Wait()
+	assert.Equal(t, int64(0), atomic.LoadInt64(&depth))
+
+	// the requests waiting longer than the window are rejected as busy
+	invoker, blocked = saturate()
+	start := time.Now()
+	result = f.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("GetUser", nil, nil))
+	assert.True(t, perrors.Is(result.Error(), ErrBusy))
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(300*time.Millisecond))
+	close(invoker.release)
+	blocked.Wait()
+
+	// the slots are released
+	assert.NoError(t, f.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("GetUser", nil, nil)).Error())
+}
+
+func TestFilterInvokeDisabled(t *testing.T) {
+	url, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?side=provider")
+	assert.NoError(t, err)
+	invoker := &blockInvoker{BaseInvoker: *protocol.NewBaseInvoker(url)}
+	assert.NoError(t, newFilter().Invoke(context.Background(), invoker, invocation.NewRPCInvocation("GetUser", nil, nil)).Error())
+}
@@ -0,0 +1,106 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package attachment
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+var (
+	requiredOnce   sync.Once
+	requiredFilter *Filter
+)
+
+func init() {
+	extension.SetFilter(constant.RequiredAttachmentFilterKey, newFilter)
+}
+
+// MissingAttachmentError is returned if the request doesn't carry the attachments required by the provider
+type MissingAttachmentError struct {
+	// Keys are the keys of the missing attachments
+	Keys []string
+	// Service is the service key of the provider
+	Service string
+	// Method is the name of the invoked method
+	Method string
+}
+
+func (e *MissingAttachmentError) Error() string {
+	return fmt.Sprintf("the attachments %s required by the method %s of the service %s are missing",
+		strings.Join(e.Keys, ","), e.Method, e.Service)
+}
+
+// Filter rejects the requests missing any of the attachments required by the provider.
+/**
+ * example:
+ * "UserProvider":
+ *   filter: "required-attachment,echo,token,accesslog"
+ *   params:
+ *     attachment.required: "traceId,callerApp"
+ *     methods.Echo.attachment.required: "traceId"
+ * The keys of the method override the ones of the service, and an attachment with the empty value is missing
+ * as well. The filter should be listed ahead of the business filters to reject the requests early, which fail
+ * with MissingAttachmentError naming all of the missing keys.
+ */
+type Filter struct{}
+
+// newFilter returns the singleton Filter instance
+func newFilter() filter.Filter {
+	requiredOnce.Do(func() {
+		requiredFilter = &Filter{}
+	})
+	return requiredFilter
+}
+
+// Invoke rejects the request if any of the required attachments is missing
+func (f *Filter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	url := invoker.GetURL()
+	method := invocation.MethodName()
+	var missing []string
+	for _, key := range strings.Split(url.GetMethodParam(method, constant.REQUIRED_ATTACHMENTS_KEY,
+		url.GetParam(constant.REQUIRED_ATTACHMENTS_KEY, "")), ",") {
+		if key = strings.TrimSpace(key); key == "" {
+			continue
+		}
+		if v := invocation.Attachment(key); v == nil || v == "" {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		logger.Warnf("[Required Attachment Filter] the attachments %v are missing in the request to %s#%s",
+			missing, url.ServiceKey(), method)
+		return &protocol.RPCResult{Err: &MissingAttachmentError{Keys: missing, Service: url.ServiceKey(), Method: method}}
+	}
+	return invoker.Invoke(ctx, invocation)
+}
+
+// OnResponse dummy process, returns the result directly
+func (f *Filter) OnResponse(_ context.Context, result protocol.Result, _ protocol.Invoker, _ protocol.Invocation) protocol.Result {
+	return result
+}
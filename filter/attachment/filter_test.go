@@ -0,0 +1,64 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package attachment
+
+import (
+	"context"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+func TestFilterInvoke(t *testing.T) {
+	url, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?side=provider" +
+		"&attachment.required=traceId,callerApp&methods.Echo.attachment.required=traceId")
+	assert.NoError(t, err)
+	invoker := protocol.NewBaseInvoker(url)
+	filter := newFilter()
+
+	invoke := func(method string, attachments map[string]interface{}) protocol.Result {
+		return filter.Invoke(context.Background(), invoker, invocation.NewRPCInvocation(method, nil, attachments))
+	}
+	assertMissing := func(result protocol.Result, method string, keys ...string) {
+		err, ok := result.Error().(*MissingAttachmentError)
+		if assert.True(t, ok, "the error %v isn't a MissingAttachmentError", result.Error()) {
+			assert.Equal(t, keys, err.Keys)
+			assert.Equal(t, method, err.Method)
+			assert.Equal(t, url.ServiceKey(), err.Service)
+			for _, key := range keys {
+				assert.Contains(t, err.Error(), key)
+			}
+		}
+	}
+
+	assert.NoError(t, invoke("GetUser", map[string]interface{}{"traceId": "t", "callerApp": "order-center"}).Error())
+	assertMissing(invoke("GetUser", map[string]interface{}{"traceId": "t"}), "GetUser", "callerApp")
+	assertMissing(invoke("GetUser", map[string]interface{}{"traceId": "", "other": "o"}), "GetUser", "traceId", "callerApp")
+
+	// the list of the method overrides the one of the service
+	assert.NoError(t, invoke("Echo", map[string]interface{}{"traceId": "t"}).Error())
+	assertMissing(invoke("Echo", map[string]interface{}{"callerApp": "order-center"}), "Echo", "traceId")
+}
@@ -0,0 +1,169 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package attachment
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+const (
+	// LimitPolicyReject rejects the requests over the limits
+	LimitPolicyReject = "reject"
+	// LimitPolicyTruncate drops the attachments over the limits and goes on with the requests
+	LimitPolicyTruncate = "truncate"
+)
+
+var (
+	limitOnce   sync.Once
+	limitFilter *LimitFilter
+
+	// reservedKeys are the attachments set by dubbo itself, which are kept by the truncation
+	reservedKeys = map[string]struct{}{
+		constant.PATH_KEY:      {},
+		constant.INTERFACE_KEY: {},
+		constant.GROUP_KEY:     {},
+		constant.VERSION_KEY:   {},
+		constant.TIMEOUT_KEY:   {},
+		constant.TOKEN_KEY:     {},
+		constant.ASYNC_KEY:     {},
+		constant.GENERIC_KEY:   {},
+	}
+)
+
+func init() {
+	extension.SetFilter(constant.AttachmentLimitFilterKey, newLimitFilter)
+}
+
+// ExceededAttachmentsError is returned if the attachments of the request are over the limits of the provider
+type ExceededAttachmentsError struct {
+	// Count is the number of the attachments of the request
+	Count int
+	// Size is the total bytes of the keys and the values of the attachments of the request
+	Size int
+	// Service is the service key of the provider
+	Service string
+	// Method is the name of the invoked method
+	Method string
+}
+
+func (e *ExceededAttachmentsError) Error() string {
+	return fmt.Sprintf("the %d attachments of %d bytes of the request to the method %s of the service %s are over the limits",
+		e.Count, e.Size, e.Method, e.Service)
+}
+
+// LimitFilter bounds the number and the total size of the attachments of the requests, and the requests over
+// the limits are either rejected or truncated.
+type LimitFilter struct{}
+
+// newLimitFilter returns the singleton LimitFilter instance
+func newLimitFilter() filter.Filter {
+	limitOnce.Do(func() {
+		limitFilter = &LimitFilter{}
+	})
+	return limitFilter
+}
+
+// Invoke rejects or truncates the request if its attachments are over the limits
+func (f *LimitFilter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	url := invoker.GetURL()
+	maxCount := int(url.GetParamInt(constant.ATTACHMENT_MAX_COUNT_KEY, constant.DEFAULT_ATTACHMENT_MAX_COUNT))
+	maxSize := int(url.GetParamInt(constant.ATTACHMENT_MAX_SIZE_KEY, constant.DEFAULT_ATTACHMENT_MAX_SIZE))
+	attachments := invocation.Attachments()
+	size := 0
+	for k, v := range attachments {
+		size += attachmentSize(k, v)
+	}
+	if len(attachments) <= maxCount && size <= maxSize {
+		return invoker.Invoke(ctx, invocation)
+	}
+
+	method := invocation.MethodName()
+	if url.GetParam(constant.ATTACHMENT_LIMIT_POLICY_KEY, LimitPolicyReject) != LimitPolicyTruncate {
+		logger.Warnf("[Attachment Limit Filter] reject the request to %s#%s with %d attachments of %d bytes",
+			url.ServiceKey(), method, len(attachments), size)
+		return &protocol.RPCResult{Err: &ExceededAttachmentsError{Count: len(attachments), Size: size,
+			Service: url.ServiceKey(), Method: method}}
+	}
+	dropped := truncate(attachments, maxCount, maxSize)
+	logger.Warnf("[Attachment Limit Filter] drop the attachments %v of the request to %s#%s over the limits",
+		dropped, url.ServiceKey(), method)
+	return invoker.Invoke(ctx, invocation)
+}
+
+// OnResponse dummy process, returns the result directly
+func (f *LimitFilter) OnResponse(_ context.Context, result protocol.Result, _ protocol.Invoker, _ protocol.Invocation) protocol.Result {
+	return result
+}
+
+// truncate keeps the reserved attachments and the other ones in the order of the keys within the limits,
+// and returns the keys of the dropped ones
+func truncate(attachments map[string]interface{}, maxCount, maxSize int) []string {
+	keys := make([]string, 0, len(attachments))
+	count, size := 0, 0
+	for k, v := range attachments {
+		if _, ok := reservedKeys[k]; ok {
+			count++
+			size += attachmentSize(k, v)
+		} else {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	var dropped []string
+	for _, k := range keys {
+		s := attachmentSize(k, attachments[k])
+		if count < maxCount && size+s <= maxSize {
+			count++
+			size += s
+			continue
+		}
+		delete(attachments, k)
+		dropped = append(dropped, k)
+	}
+	return dropped
+}
+
+// attachmentSize estimates the bytes of the attachment of @key and @value in the request
+func attachmentSize(key string, value interface{}) int {
+	size := len(key)
+	switch v := value.(type) {
+	case string:
+		size += len(v)
+	case []byte:
+		size += len(v)
+	case []string:
+		for _, s := range v {
+			size += len(s)
+		}
+	case nil:
+	default:
+		size += len(fmt.Sprint(v))
+	}
+	return size
+}
@@ -0,0 +1,78 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package attachment
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+func TestLimitFilterInvoke(t *testing.T) {
+	excessive := func(count int) map[string]interface{} {
+		attachments := map[string]interface{}{constant.INTERFACE_KEY: "com.ikurento.user.UserProvider"}
+		for i := 0; i < count; i++ {
+			attachments[fmt.Sprintf("key-%04d", i)] = "value"
+		}
+		return attachments
+	}
+	invoke := func(params string, attachments map[string]interface{}) (protocol.Result, protocol.Invocation) {
+		url, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?side=provider" + params)
+		assert.NoError(t, err)
+		inv := invocation.NewRPCInvocation("GetUser", nil, attachments)
+		return newLimitFilter().Invoke(context.Background(), protocol.NewBaseInvoker(url), inv), inv
+	}
+
+	// the defaults are generous
+	result, _ := invoke("", excessive(100))
+	assert.NoError(t, result.Error())
+	result, _ = invoke("", excessive(2000))
+	assert.IsType(t, &ExceededAttachmentsError{}, result.Error())
+
+	// the number of the attachments is over the limit
+	result, _ = invoke("&attachment.max.count=10", excessive(10))
+	err, ok := result.Error().(*ExceededAttachmentsError)
+	if assert.True(t, ok, "the error %v isn't an ExceededAttachmentsError", result.Error()) {
+		assert.Equal(t, 11, err.Count)
+		assert.Equal(t, "GetUser", err.Method)
+	}
+
+	// the total size of the attachments is over the limit
+	result, _ = invoke("&attachment.max.size=1024", map[string]interface{}{"large": strings.Repeat("x", 1024)})
+	assert.IsType(t, &ExceededAttachmentsError{}, result.Error())
+
+	// the attachments over the limits are dropped by the truncation, while the reserved ones are kept
+	result, inv := invoke("&attachment.max.count=10&attachment.limit.policy=truncate", excessive(20))
+	assert.NoError(t, result.Error())
+	assert.Len(t, inv.Attachments(), 10)
+	assert.Equal(t, "com.ikurento.user.UserProvider", inv.Attachment(constant.INTERFACE_KEY))
+	assert.Equal(t, "value", inv.Attachment("key-0008"))
+	assert.Nil(t, inv.Attachment("key-0009"))
+}
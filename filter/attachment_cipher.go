@@ -0,0 +1,30 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filter
+
+// AttachmentCipher encrypts and decrypts the sensitive attachments with the key of @keyID, which is shared
+// by the consumers and the providers.
+// Custom AttachmentCipher must be set by calling extension.SetAttachmentCipher before use.
+type AttachmentCipher interface {
+
+	// Encrypt encrypts the attachment value on consumer side
+	Encrypt(keyID string, plaintext []byte) ([]byte, error)
+
+	// Decrypt decrypts the attachment value on provider side
+	Decrypt(keyID string, ciphertext []byte) ([]byte, error)
+}
@@ -0,0 +1,84 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ctxpropagation
+
+import (
+	"fmt"
+	"sync"
+)
+
+// KeyCodec converts the value of a propagated context key from and to the attachment
+type KeyCodec interface {
+	// Encode converts the context value into the attachment
+	Encode(value interface{}) (string, error)
+	// Decode restores the context value from the attachment
+	Decode(attachment string) (interface{}, error)
+}
+
+// StringCodec propagates the context values of string
+type StringCodec struct{}
+
+// Encode returns the string value directly
+func (c StringCodec) Encode(value interface{}) (string, error) {
+	str, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("the context value %v is not a string", value)
+	}
+	return str, nil
+}
+
+// Decode returns the attachment directly
+func (c StringCodec) Decode(attachment string) (interface{}, error) {
+	return attachment, nil
+}
+
+type contextKey struct {
+	key   interface{}
+	codec KeyCodec
+}
+
+var (
+	contextKeysLock sync.RWMutex
+	contextKeys     = make(map[string]contextKey)
+)
+
+// RegisterKey registers the context @key with @name, which is referred by the context.keys param.
+// The value of @key is encoded by @codec on the consumer side and decoded by it on the provider side,
+// so both sides should register the key with the same name and codec.
+func RegisterKey(name string, key interface{}, codec KeyCodec) {
+	if codec == nil {
+		codec = StringCodec{}
+	}
+	contextKeysLock.Lock()
+	defer contextKeysLock.Unlock()
+	contextKeys[name] = contextKey{key: key, codec: codec}
+}
+
+// UnregisterKey removes the context key registered with @name
+func UnregisterKey(name string) {
+	contextKeysLock.Lock()
+	defer contextKeysLock.Unlock()
+	delete(contextKeys, name)
+}
+
+func getKey(name string) (contextKey, bool) {
+	contextKeysLock.RLock()
+	defer contextKeysLock.RUnlock()
+	key, ok := contextKeys[name]
+	return key, ok
+}
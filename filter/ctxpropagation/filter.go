@@ -0,0 +1,129 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ctxpropagation
+
+import (
+	"context"
+	"strings"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+func init() {
+	extension.SetFilter(constant.ContextConsumerFilterKey, func() filter.Filter {
+		return &ConsumerFilter{}
+	})
+	extension.SetFilter(constant.ContextProviderFilterKey, func() filter.Filter {
+		return &ProviderFilter{}
+	})
+}
+
+// ConsumerFilter puts the values of the listed context keys into the attachments.
+/**
+ * example:
+ * ctxpropagation.RegisterKey("tenantID", tenantIDKey, nil)
+ *
+ * references:
+ *   "UserProvider":
+ *     filter: "context-consumer"
+ *     params:
+ *       context.keys: "tenantID"
+ * services:
+ *   "UserProvider":
+ *     filter: "context-provider"
+ *     params:
+ *       context.keys: "tenantID"
+ * The value of tenantIDKey in the context of the consumer is carried by the attachment "context.tenantID"
+ * and restored into the context passed to the provider's handler. The keys which aren't listed by either
+ * side aren't propagated.
+ */
+type ConsumerFilter struct{}
+
+// Invoke encodes the listed context values into the attachments of @invocation
+func (f *ConsumerFilter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	for _, name := range getKeyNames(invoker.GetURL()) {
+		key, ok := getKey(name)
+		if !ok {
+			logger.Warnf("[Context Filter] the context key %s is not registered", name)
+			continue
+		}
+		value := ctx.Value(key.key)
+		if value == nil {
+			continue
+		}
+		attachment, err := key.codec.Encode(value)
+		if err != nil {
+			logger.Warnf("[Context Filter] could not encode the context key %s: %v", name, err)
+			continue
+		}
+		invocation.SetAttachments(constant.CONTEXT_ATTACHMENT_PREFIX+name, attachment)
+	}
+	return invoker.Invoke(ctx, invocation)
+}
+
+// OnResponse dummy process, returns the result directly
+func (f *ConsumerFilter) OnResponse(_ context.Context, result protocol.Result, _ protocol.Invoker, _ protocol.Invocation) protocol.Result {
+	return result
+}
+
+// ProviderFilter restores the values of the listed context keys from the attachments.
+type ProviderFilter struct{}
+
+// Invoke decodes the listed context values from the attachments of @invocation into the context of the handler
+func (f *ProviderFilter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	for _, name := range getKeyNames(invoker.GetURL()) {
+		attachment, ok := invocation.Attachment(constant.CONTEXT_ATTACHMENT_PREFIX + name).(string)
+		if !ok {
+			continue
+		}
+		key, ok := getKey(name)
+		if !ok {
+			logger.Warnf("[Context Filter] the context key %s is not registered", name)
+			continue
+		}
+		value, err := key.codec.Decode(attachment)
+		if err != nil {
+			logger.Warnf("[Context Filter] could not decode the context key %s: %v", name, err)
+			continue
+		}
+		ctx = context.WithValue(ctx, key.key, value)
+	}
+	return invoker.Invoke(ctx, invocation)
+}
+
+// OnResponse dummy process, returns the result directly
+func (f *ProviderFilter) OnResponse(_ context.Context, result protocol.Result, _ protocol.Invoker, _ protocol.Invocation) protocol.Result {
+	return result
+}
+
+func getKeyNames(url *common.URL) []string {
+	var names []string
+	for _, name := range strings.Split(url.GetParam(constant.CONTEXT_KEYS_KEY, ""), ",") {
+		if name = strings.TrimSpace(name); len(name) > 0 {
+			names = append(names, name)
+		}
+	}
+	return names
+}
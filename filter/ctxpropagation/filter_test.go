@@ -0,0 +1,115 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ctxpropagation
+
+import (
+	"context"
+	"strconv"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+type tenantIDKey struct{}
+
+type regionKey struct{}
+
+type intCodec struct{}
+
+func (c intCodec) Encode(value interface{}) (string, error) {
+	return strconv.Itoa(value.(int)), nil
+}
+
+func (c intCodec) Decode(attachment string) (interface{}, error) {
+	return strconv.Atoi(attachment)
+}
+
+// testTransportInvoker passes the string attachments to the provider as the dubbo protocol does
+type testTransportInvoker struct {
+	protocol.BaseInvoker
+	provider protocol.Invoker
+}
+
+func (iv *testTransportInvoker) Invoke(_ context.Context, inv protocol.Invocation) protocol.Result {
+	attachments := make(map[string]interface{})
+	for k, v := range inv.Attachments() {
+		if str, ok := v.(string); ok {
+			attachments[k] = str
+		}
+	}
+	providerInv := invocation.NewRPCInvocation(inv.MethodName(), inv.Arguments(), attachments)
+	return (&ProviderFilter{}).Invoke(context.Background(), iv.provider, providerInv)
+}
+
+// testHandlerInvoker returns the context of the handler
+type testHandlerInvoker struct {
+	protocol.BaseInvoker
+}
+
+func (iv *testHandlerInvoker) Invoke(ctx context.Context, _ protocol.Invocation) protocol.Result {
+	return &protocol.RPCResult{Rest: ctx}
+}
+
+func TestContextPropagation(t *testing.T) {
+	RegisterKey("tenantID", tenantIDKey{}, intCodec{})
+	RegisterKey("region", regionKey{}, nil)
+	defer UnregisterKey("tenantID")
+	defer UnregisterKey("region")
+
+	consumerURL, _ := common.NewURL("dubbo://127.0.0.1:20000/UserProvider?" + constant.CONTEXT_KEYS_KEY + "=tenantID")
+	providerURL, _ := common.NewURL("dubbo://127.0.0.1:20000/UserProvider?" + constant.CONTEXT_KEYS_KEY + "=tenantID,region")
+	invoker := &testTransportInvoker{
+		BaseInvoker: *protocol.NewBaseInvoker(consumerURL),
+		provider:    &testHandlerInvoker{BaseInvoker: *protocol.NewBaseInvoker(providerURL)},
+	}
+
+	ctx := context.WithValue(context.Background(), tenantIDKey{}, 42)
+	ctx = context.WithValue(ctx, regionKey{}, "hangzhou")
+	inv := invocation.NewRPCInvocation("GetUser", []interface{}{"1"}, nil)
+	result := (&ConsumerFilter{}).Invoke(ctx, invoker, inv)
+	assert.NoError(t, result.Error())
+
+	providerCtx := result.Result().(context.Context)
+	assert.Equal(t, 42, providerCtx.Value(tenantIDKey{}))
+	// the region isn't listed by the consumer
+	assert.Nil(t, providerCtx.Value(regionKey{}))
+	assert.Equal(t, "42", inv.AttachmentsByKey(constant.CONTEXT_ATTACHMENT_PREFIX+"tenantID", ""))
+	assert.Nil(t, inv.Attachment(constant.CONTEXT_ATTACHMENT_PREFIX+"region"))
+}
+
+func TestProviderFilterUnlistedKey(t *testing.T) {
+	RegisterKey("tenantID", tenantIDKey{}, nil)
+	defer UnregisterKey("tenantID")
+
+	providerURL, _ := common.NewURL("dubbo://127.0.0.1:20000/UserProvider")
+	inv := invocation.NewRPCInvocation("GetUser", []interface{}{"1"}, map[string]interface{}{
+		constant.CONTEXT_ATTACHMENT_PREFIX + "tenantID": "42",
+	})
+	result := (&ProviderFilter{}).Invoke(context.Background(),
+		&testHandlerInvoker{BaseInvoker: *protocol.NewBaseInvoker(providerURL)}, inv)
+	assert.Nil(t, result.Result().(context.Context).Value(tenantIDKey{}))
+}
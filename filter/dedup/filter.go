@@ -0,0 +1,195 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dedup
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+var (
+	dedupOnce   sync.Once
+	dedupFilter *Filter
+)
+
+func init() {
+	extension.SetFilter(constant.DedupFilterKey, newFilter)
+}
+
+// Filter executes the requests carrying the same idempotency key at most once on the provider side.
+/**
+ * example:
+ * "UserProvider":
+ *   filter: "dedup"
+ *   params:
+ *     dedup.ttl: "30s"                  # 60s by default
+ *     methods.AddUser.dedup.ttl: "5m"   # method-level configuration overrides the service-level one
+ * The consumer puts the idempotency key into the attachment "idempotency-key", the result of the first
+ * request is cached for the ttl and returned to the duplicate requests of the same method instead of
+ * executing the method again. The requests without the idempotency key aren't affected, and the results
+ * with error aren't cached so that they can be retried.
+ */
+type Filter struct {
+	lock      sync.Mutex
+	entries   map[string]*entry
+	lastSweep int64
+}
+
+type entry struct {
+	done     chan struct{}
+	result   protocol.Result
+	expireAt time.Time
+	// err is published to the waiters if the first request panics
+	err error
+}
+
+// expired checks whether the cached result of the finished request is out of date
+func (e *entry) expired(now time.Time) bool {
+	select {
+	case <-e.done:
+		return e.expireAt.Before(now)
+	default:
+		return false
+	}
+}
+
+// newFilter returns the singleton Filter instance
+func newFilter() filter.Filter {
+	dedupOnce.Do(func() {
+		dedupFilter = &Filter{
+			entries: make(map[string]*entry),
+		}
+	})
+	return dedupFilter
+}
+
+// Invoke returns the cached result if the request with the same idempotency key has been executed
+func (f *Filter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	idempotencyKey := invocation.AttachmentsByKey(constant.IDEMPOTENCY_KEY, "")
+	if len(idempotencyKey) == 0 {
+		return invoker.Invoke(ctx, invocation)
+	}
+
+	key := fmt.Sprintf("%s#%s#%s", invoker.GetURL().ServiceKey(), invocation.MethodName(), idempotencyKey)
+	for {
+		now := time.Now()
+		f.lock.Lock()
+		f.sweep(now)
+		e, ok := f.entries[key]
+		if !ok || e.expired(now) {
+			e = &entry{done: make(chan struct{})}
+			f.entries[key] = e
+			f.lock.Unlock()
+			return f.execute(ctx, invoker, invocation, key, e)
+		}
+		f.lock.Unlock()
+
+		// wait for the result if the first request is still in progress
+		select {
+		case <-e.done:
+		case <-ctx.Done():
+			return &protocol.RPCResult{Err: ctx.Err()}
+		}
+		if e.result != nil {
+			logger.Debugf("[Dedup Filter] return the cached result of the duplicate request %s", key)
+			return e.result
+		}
+		if e.err != nil {
+			return &protocol.RPCResult{Err: e.err}
+		}
+		// the first request failed, so this one executes the method again
+	}
+}
+
+// execute invokes the method for the first request and caches the result if it succeeds.
+// The panic of the method is recovered and returned as the error of both the first request and its waiters.
+func (f *Filter) execute(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation,
+	key string, e *entry) (result protocol.Result) {
+	defer func() {
+		if r := recover(); r != nil {
+			err := perrors.Errorf("the request %s panics: %v", key, r)
+			logger.Errorf("[Dedup Filter] %v", err)
+			f.lock.Lock()
+			if f.entries[key] == e {
+				delete(f.entries, key)
+			}
+			e.err = err
+			f.lock.Unlock()
+			close(e.done)
+			result = &protocol.RPCResult{Err: err}
+		}
+	}()
+	result = invoker.Invoke(ctx, invocation)
+
+	f.lock.Lock()
+	if result.Error() != nil {
+		if f.entries[key] == e {
+			delete(f.entries, key)
+		}
+	} else {
+		e.result = result
+		e.expireAt = time.Now().Add(getTTL(invoker.GetURL(), invocation.MethodName()))
+	}
+	f.lock.Unlock()
+	close(e.done)
+	return result
+}
+
+// OnResponse dummy process, returns the result directly
+func (f *Filter) OnResponse(_ context.Context, result protocol.Result, _ protocol.Invoker, _ protocol.Invocation) protocol.Result {
+	return result
+}
+
+// sweep removes the expired entries at most once per second, it must be called with the lock held
+func (f *Filter) sweep(now time.Time) {
+	if now.Unix() == f.lastSweep {
+		return
+	}
+	f.lastSweep = now.Unix()
+	for key, e := range f.entries {
+		if e.expired(now) {
+			delete(f.entries, key)
+		}
+	}
+}
+
+func getTTL(url *common.URL, methodName string) time.Duration {
+	ttlConfig := url.GetMethodParam(methodName, constant.DEDUP_TTL_KEY,
+		url.GetParam(constant.DEDUP_TTL_KEY, constant.DEFAULT_DEDUP_TTL))
+	ttl, err := time.ParseDuration(ttlConfig)
+	if err != nil {
+		logger.Errorf("The configuration of %s is invalid: %s", constant.DEDUP_TTL_KEY, ttlConfig)
+		ttl, _ = time.ParseDuration(constant.DEFAULT_DEDUP_TTL)
+	}
+	return ttl
+}
@@ -0,0 +1,149 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dedup
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+type countInvoker struct {
+	protocol.BaseInvoker
+	count int32
+}
+
+func (c *countInvoker) Invoke(context.Context, protocol.Invocation) protocol.Result {
+	count := atomic.AddInt32(&c.count, 1)
+	time.Sleep(10 * time.Millisecond)
+	return &protocol.RPCResult{Rest: count}
+}
+
+func TestFilterInvoke(t *testing.T) {
+	url, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?side=provider")
+	assert.NoError(t, err)
+	invoker := &countInvoker{BaseInvoker: *protocol.NewBaseInvoker(url)}
+	filter := newFilter()
+
+	newInvocation := func(method string, key string) protocol.Invocation {
+		return invocation.NewRPCInvocation(method, []interface{}{"OK"}, map[string]interface{}{constant.IDEMPOTENCY_KEY: key})
+	}
+
+	// the duplicate requests in flight share the result of the first one
+	var wg sync.WaitGroup
+	results := make([]protocol.Result, 2)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = filter.Invoke(context.Background(), invoker, newInvocation("AddUser", "request-1"))
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&invoker.count))
+	assert.Equal(t, results[0].Result(), results[1].Result())
+
+	// the duplicate request after the first one finished gets the cached result
+	result := filter.Invoke(context.Background(), invoker, newInvocation("AddUser", "request-1"))
+	assert.Equal(t, results[0].Result(), result.Result())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&invoker.count))
+
+	// the keys are namespaced per method
+	result = filter.Invoke(context.Background(), invoker, newInvocation("UpdateUser", "request-1"))
+	assert.Equal(t, int32(2), result.Result())
+
+	// the requests without idempotency key are always executed
+	filter.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("AddUser", []interface{}{"OK"}, nil))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&invoker.count))
+}
+
+func TestFilterInvokeExpired(t *testing.T) {
+	url, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?side=provider&methods.AddUser.dedup.ttl=20ms")
+	assert.NoError(t, err)
+	invoker := &countInvoker{BaseInvoker: *protocol.NewBaseInvoker(url)}
+	filter := newFilter()
+	inv := invocation.NewRPCInvocation("AddUser", []interface{}{"OK"}, map[string]interface{}{constant.IDEMPOTENCY_KEY: "request-2"})
+
+	filter.Invoke(context.Background(), invoker, inv)
+	filter.Invoke(context.Background(), invoker, inv)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&invoker.count))
+
+	time.Sleep(30 * time.Millisecond)
+	filter.Invoke(context.Background(), invoker, inv)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&invoker.count))
+}
+
+type panicInvoker struct {
+	protocol.BaseInvoker
+	started chan struct{}
+	proceed chan struct{}
+}
+
+func (p *panicInvoker) Invoke(context.Context, protocol.Invocation) protocol.Result {
+	close(p.started)
+	<-p.proceed
+	panic("broken")
+}
+
+func TestFilterInvokePanic(t *testing.T) {
+	url, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?side=provider")
+	assert.NoError(t, err)
+	invoker := &panicInvoker{
+		BaseInvoker: *protocol.NewBaseInvoker(url),
+		started:     make(chan struct{}),
+		proceed:     make(chan struct{}),
+	}
+	filter := newFilter()
+	inv := invocation.NewRPCInvocation("AddUser", []interface{}{"OK"}, map[string]interface{}{constant.IDEMPOTENCY_KEY: "request-3"})
+
+	leader := make(chan protocol.Result, 1)
+	go func() {
+		leader <- filter.Invoke(context.Background(), invoker, inv)
+	}()
+	<-invoker.started
+	waiter := make(chan protocol.Result, 1)
+	go func() {
+		waiter <- filter.Invoke(context.Background(), invoker, inv)
+	}()
+	// the waiter is waiting for the leader
+	time.Sleep(10 * time.Millisecond)
+	close(invoker.proceed)
+
+	for _, results := range []chan protocol.Result{leader, waiter} {
+		select {
+		case result := <-results:
+			assert.Error(t, result.Error())
+			assert.Contains(t, result.Error().Error(), "broken")
+		case <-time.After(time.Second):
+			assert.FailNow(t, "the request is blocked after the first one panics")
+		}
+	}
+}
@@ -19,9 +19,11 @@ package echo
 
 import (
 	"context"
+	"strconv"
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
 	"dubbo.apache.org/dubbo-go/v3/common/logger"
@@ -45,9 +47,15 @@ type Filter struct{}
 func (f *Filter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
 	logger.Debugf("%v,%v", invocation.MethodName(), len(invocation.Arguments()))
 	if invocation.MethodName() == constant.ECHO && len(invocation.Arguments()) == 1 {
+		attachments := make(map[string]interface{}, len(invocation.Attachments())+1)
+		for k, v := range invocation.Attachments() {
+			attachments[k] = v
+		}
+		// the provider is serving but not ready during the startup delay
+		attachments[constant.READY_KEY] = strconv.FormatBool(common.IsReady())
 		return &protocol.RPCResult{
 			Rest:  invocation.Arguments()[0],
-			Attrs: invocation.Attachments(),
+			Attrs: attachments,
 		}
 	}
 
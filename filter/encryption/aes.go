@@ -0,0 +1,105 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"io"
+	"sync"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+)
+
+// aesKeys holds the cipher.AEAD of the keys by their ids
+var aesKeys sync.Map
+
+func init() {
+	extension.SetAttachmentCipher(constant.DEFAULT_ATTACHMENT_CIPHER, newAESCipher)
+}
+
+// SetAESKey sets the AES key of @keyID used by the default attachment cipher, which is 16, 24 or 32 bytes.
+// The keys are set in the code rather than the configs, since the params of the urls are published to the registry.
+func SetAESKey(keyID string, key []byte) error {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return perrors.WithStack(err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return perrors.WithStack(err)
+	}
+	aesKeys.Store(keyID, aead)
+	return nil
+}
+
+// RemoveAESKey removes the AES key of @keyID
+func RemoveAESKey(keyID string) {
+	aesKeys.Delete(keyID)
+}
+
+// aesCipher encrypts the attachments by AES-GCM, and the ciphertext is prefixed with the random nonce
+type aesCipher struct{}
+
+func newAESCipher() filter.AttachmentCipher {
+	return &aesCipher{}
+}
+
+func (c *aesCipher) aead(keyID string) (cipher.AEAD, error) {
+	aead, ok := aesKeys.Load(keyID)
+	if !ok {
+		return nil, perrors.Errorf("the AES key %s is not set", keyID)
+	}
+	return aead.(cipher.AEAD), nil
+}
+
+// Encrypt seals @plaintext with the key of @keyID
+func (c *aesCipher) Encrypt(keyID string, plaintext []byte) ([]byte, error) {
+	aead, err := c.aead(keyID)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, perrors.WithStack(err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, []byte(keyID)), nil
+}
+
+// Decrypt opens @ciphertext with the key of @keyID
+func (c *aesCipher) Decrypt(keyID string, ciphertext []byte) ([]byte, error) {
+	aead, err := c.aead(keyID)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, perrors.New("the ciphertext is too short")
+	}
+	nonce, sealed := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, sealed, []byte(keyID))
+	return plaintext, perrors.WithStack(err)
+}
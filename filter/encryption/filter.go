@@ -0,0 +1,167 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encryption
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"sync"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+var (
+	encryptOnce   sync.Once
+	encryptFilter *EncryptFilter
+	decryptOnce   sync.Once
+	decryptFilter *DecryptFilter
+)
+
+func init() {
+	extension.SetFilter(constant.AttachmentEncryptFilterKey, newEncryptFilter)
+	extension.SetFilter(constant.AttachmentDecryptFilterKey, newDecryptFilter)
+}
+
+// EncryptFilter encrypts the sensitive attachments of the requests on consumer side, and the others are
+// sent in plaintext.
+/**
+ * example:
+ * "UserProvider":
+ *   filter: "attachment-encrypt"
+ *   params:
+ *     attachment.encrypted: "token,ak"
+ *     attachment.key.id: "key-2021"
+ *     attachment.cipher: "aes-gcm"
+ * The key of the id must be set by encryption.SetAESKey on both sides for the default cipher, and the providers
+ * configure the attachment-decrypt filter to decrypt them.
+ */
+type EncryptFilter struct{}
+
+func newEncryptFilter() filter.Filter {
+	encryptOnce.Do(func() {
+		encryptFilter = &EncryptFilter{}
+	})
+	return encryptFilter
+}
+
+// Invoke encrypts the configured attachments, and the request fails rather than sending them in plaintext
+func (f *EncryptFilter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	url := invoker.GetURL()
+	// the retries carry the attachments encrypted by the former tries
+	if invocation.AttachmentsByKey(constant.ENCRYPTED_ATTACHMENTS_KEY, "") != "" {
+		return invoker.Invoke(ctx, invocation)
+	}
+	keyID := url.GetParam(constant.ATTACHMENT_KEY_ID_KEY, "")
+	var (
+		attachmentCipher filter.AttachmentCipher
+		encrypted        []string
+	)
+	for _, key := range strings.Split(url.GetParam(constant.ENCRYPTED_ATTACHMENTS_KEY, ""), ",") {
+		if key = strings.TrimSpace(key); key == "" {
+			continue
+		}
+		value, ok := invocation.Attachment(key).(string)
+		if !ok {
+			if invocation.Attachment(key) != nil {
+				logger.Warnf("[Attachment Encrypt Filter] the attachment %s isn't a string, which is not encrypted", key)
+			}
+			continue
+		}
+		if attachmentCipher == nil {
+			attachmentCipher = extension.GetAttachmentCipher(url.GetParam(constant.ATTACHMENT_CIPHER_KEY, constant.DEFAULT_ATTACHMENT_CIPHER))
+		}
+		ciphertext, err := attachmentCipher.Encrypt(keyID, []byte(value))
+		if err != nil {
+			logger.Errorf("[Attachment Encrypt Filter] encrypt the attachment %s of %s#%s error: %v",
+				key, url.ServiceKey(), invocation.MethodName(), err)
+			return &protocol.RPCResult{Err: perrors.WithMessagef(err, "encrypt the attachment %s", key)}
+		}
+		invocation.SetAttachments(key, base64.StdEncoding.EncodeToString(ciphertext))
+		encrypted = append(encrypted, key)
+	}
+	if len(encrypted) > 0 {
+		invocation.SetAttachments(constant.ENCRYPTED_ATTACHMENTS_KEY, strings.Join(encrypted, ","))
+		invocation.SetAttachments(constant.ATTACHMENT_KEY_ID_KEY, keyID)
+	}
+	return invoker.Invoke(ctx, invocation)
+}
+
+// OnResponse dummy process, returns the result directly
+func (f *EncryptFilter) OnResponse(_ context.Context, result protocol.Result, _ protocol.Invoker, _ protocol.Invocation) protocol.Result {
+	return result
+}
+
+// DecryptFilter decrypts the attachments encrypted by the consumers on provider side
+/**
+ * example:
+ * "UserProvider":
+ *   filter: "attachment-decrypt"
+ *   params:
+ *     attachment.cipher: "aes-gcm"
+ * The keys of the ids sent by the consumers must be set by encryption.SetAESKey for the default cipher.
+ */
+type DecryptFilter struct{}
+
+func newDecryptFilter() filter.Filter {
+	decryptOnce.Do(func() {
+		decryptFilter = &DecryptFilter{}
+	})
+	return decryptFilter
+}
+
+// Invoke decrypts the attachments listed by the request, and rejects the request if any of them fails
+func (f *DecryptFilter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	encrypted := invocation.AttachmentsByKey(constant.ENCRYPTED_ATTACHMENTS_KEY, "")
+	if encrypted == "" {
+		return invoker.Invoke(ctx, invocation)
+	}
+	url := invoker.GetURL()
+	keyID := invocation.AttachmentsByKey(constant.ATTACHMENT_KEY_ID_KEY, "")
+	attachmentCipher := extension.GetAttachmentCipher(url.GetParam(constant.ATTACHMENT_CIPHER_KEY, constant.DEFAULT_ATTACHMENT_CIPHER))
+	for _, key := range strings.Split(encrypted, ",") {
+		value, _ := invocation.Attachment(key).(string)
+		ciphertext, err := base64.StdEncoding.DecodeString(value)
+		if err == nil {
+			var plaintext []byte
+			if plaintext, err = attachmentCipher.Decrypt(keyID, ciphertext); err == nil {
+				invocation.SetAttachments(key, string(plaintext))
+				continue
+			}
+		}
+		logger.Warnf("[Attachment Decrypt Filter] decrypt the attachment %s of %s#%s with the key %s error: %v",
+			key, url.ServiceKey(), invocation.MethodName(), keyID, err)
+		return &protocol.RPCResult{Err: perrors.Errorf("decrypt the attachment %s with the key %s failed", key, keyID)}
+	}
+	return invoker.Invoke(ctx, invocation)
+}
+
+// OnResponse dummy process, returns the result directly
+func (f *DecryptFilter) OnResponse(_ context.Context, result protocol.Result, _ protocol.Invoker, _ protocol.Invocation) protocol.Result {
+	return result
+}
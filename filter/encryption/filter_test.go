@@ -0,0 +1,103 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package encryption
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+// recordInvoker records the attachments of the last invocation
+type recordInvoker struct {
+	protocol.BaseInvoker
+	attachments map[string]interface{}
+}
+
+func (r *recordInvoker) Invoke(_ context.Context, inv protocol.Invocation) protocol.Result {
+	r.attachments = make(map[string]interface{}, len(inv.Attachments()))
+	for k, v := range inv.Attachments() {
+		r.attachments[k] = v
+	}
+	return &protocol.RPCResult{}
+}
+
+func TestFilterInvoke(t *testing.T) {
+	assert.NoError(t, SetAESKey("key-1", []byte("0123456789abcdef")))
+	defer RemoveAESKey("key-1")
+
+	consumerURL, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider" +
+		"?attachment.encrypted=token,ak&attachment.key.id=key-1")
+	assert.NoError(t, err)
+	wire := &recordInvoker{BaseInvoker: *protocol.NewBaseInvoker(consumerURL)}
+	inv := invocation.NewRPCInvocation("GetUser", nil, map[string]interface{}{
+		"token":   "secret-token",
+		"traceId": "trace-1",
+	})
+	assert.NoError(t, newEncryptFilter().Invoke(context.Background(), wire, inv).Error())
+
+	// the sensitive attachment is ciphertext on the wire, and the others are plaintext
+	token, ok := wire.attachments["token"].(string)
+	assert.True(t, ok)
+	assert.NotEqual(t, "secret-token", token)
+	ciphertext, err := base64.StdEncoding.DecodeString(token)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(ciphertext), "secret-token")
+	assert.Equal(t, "trace-1", wire.attachments["traceId"])
+	assert.Equal(t, "token", wire.attachments[constant.ENCRYPTED_ATTACHMENTS_KEY])
+	assert.Equal(t, "key-1", wire.attachments[constant.ATTACHMENT_KEY_ID_KEY])
+
+	// the retry doesn't encrypt them again
+	assert.NoError(t, newEncryptFilter().Invoke(context.Background(), wire, inv).Error())
+	assert.Equal(t, token, wire.attachments["token"])
+
+	// the provider recovers the plaintext
+	providerURL, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?side=provider")
+	assert.NoError(t, err)
+	provider := &recordInvoker{BaseInvoker: *protocol.NewBaseInvoker(providerURL)}
+	received := invocation.NewRPCInvocation("GetUser", nil, wire.attachments)
+	assert.NoError(t, newDecryptFilter().Invoke(context.Background(), provider, received).Error())
+	assert.Equal(t, "secret-token", provider.attachments["token"])
+	assert.Equal(t, "trace-1", provider.attachments["traceId"])
+
+	// the tampered ciphertext is rejected
+	tampered := append([]byte{}, ciphertext...)
+	tampered[len(tampered)-1] ^= 1
+	received = invocation.NewRPCInvocation("GetUser", nil, map[string]interface{}{
+		"token":                            base64.StdEncoding.EncodeToString(tampered),
+		constant.ENCRYPTED_ATTACHMENTS_KEY: "token",
+		constant.ATTACHMENT_KEY_ID_KEY:     "key-1",
+	})
+	assert.Error(t, newDecryptFilter().Invoke(context.Background(), provider, received).Error())
+
+	// the request fails rather than sending the plaintext without the key
+	RemoveAESKey("key-1")
+	inv = invocation.NewRPCInvocation("GetUser", nil, map[string]interface{}{"token": "secret-token"})
+	assert.Error(t, newEncryptFilter().Invoke(context.Background(), wire, inv).Error())
+}
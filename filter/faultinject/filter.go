@@ -0,0 +1,122 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package faultinject
+
+import (
+	"context"
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+const defaultAbortError = "fault injected by fault-inject filter"
+
+func init() {
+	extension.SetFilter(constant.FaultInjectFilterKey, func() filter.Filter {
+		return &Filter{}
+	})
+}
+
+// Filter injects latency and errors into the invocations for resilience testing.
+/**
+ * example:
+ * "UserProvider":
+ *   filter: "fault-inject"
+ *   params:
+ *     fault.enabled: "true"     # the filter is a no-op unless it's enabled
+ *     fault.delay.percent: "10" # 10% of the requests will be delayed
+ *     fault.delay: "500ms"      # the latency added to the delayed requests
+ *     fault.abort.percent: "5"  # 5% of the requests will be aborted
+ *     fault.abort.error: "injected error"
+ *     methods.GetUser.fault.abort.percent: "50" # method-level configuration overrides the service-level one
+ * All of the params can be overridden by the configurators of the config center,
+ * so the fault injection can be turned on or off without redeploying.
+ */
+type Filter struct{}
+
+// Invoke delays or aborts the invocation according to the fault configuration of the invoked method
+func (f *Filter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	url := invoker.GetURL()
+	methodName := invocation.MethodName()
+	if !url.GetMethodParamBool(methodName, constant.FAULT_INJECT_ENABLED_KEY,
+		url.GetParamBool(constant.FAULT_INJECT_ENABLED_KEY, false)) {
+		return invoker.Invoke(ctx, invocation)
+	}
+
+	if hit(url, methodName, constant.FAULT_INJECT_DELAY_PERCENT_KEY) {
+		delay := getDelay(url, methodName)
+		logger.Debugf("fault-inject filter delays the invocation %s of %s for %s", methodName, url.ServiceKey(), delay)
+		select {
+		case <-ctx.Done():
+			return &protocol.RPCResult{Err: ctx.Err()}
+		case <-time.After(delay):
+		}
+	}
+
+	if hit(url, methodName, constant.FAULT_INJECT_ABORT_PERCENT_KEY) {
+		errMsg := url.GetMethodParam(methodName, constant.FAULT_INJECT_ABORT_ERROR_KEY,
+			url.GetParam(constant.FAULT_INJECT_ABORT_ERROR_KEY, defaultAbortError))
+		logger.Debugf("fault-inject filter aborts the invocation %s of %s", methodName, url.ServiceKey())
+		return &protocol.RPCResult{Err: perrors.New(errMsg)}
+	}
+
+	return invoker.Invoke(ctx, invocation)
+}
+
+// OnResponse dummy process, returns the result directly
+func (f *Filter) OnResponse(_ context.Context, result protocol.Result, _ protocol.Invoker, _ protocol.Invocation) protocol.Result {
+	return result
+}
+
+// hit judges whether the current invocation falls into the percentage configured by @key
+func hit(url *common.URL, methodName string, key string) bool {
+	percentConfig := url.GetMethodParam(methodName, key, url.GetParam(key, "0"))
+	percent, err := strconv.ParseFloat(percentConfig, 64)
+	if err != nil {
+		logger.Errorf("The configuration of %s is invalid: %s", key, percentConfig)
+		return false
+	}
+	if percent <= 0 {
+		return false
+	}
+	return percent >= 100 || rand.Float64()*100 < percent
+}
+
+func getDelay(url *common.URL, methodName string) time.Duration {
+	delayConfig := url.GetMethodParam(methodName, constant.FAULT_INJECT_DELAY_KEY,
+		url.GetParam(constant.FAULT_INJECT_DELAY_KEY, "0s"))
+	delay, err := time.ParseDuration(delayConfig)
+	if err != nil {
+		logger.Errorf("The configuration of %s is invalid: %s", constant.FAULT_INJECT_DELAY_KEY, delayConfig)
+		return 0
+	}
+	return delay
+}
@@ -0,0 +1,81 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package faultinject
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+func TestFilterInvokeDisabled(t *testing.T) {
+	filter := &Filter{}
+	url := common.NewURLWithOptions(
+		common.WithParamsValue(constant.FAULT_INJECT_ABORT_PERCENT_KEY, "100"))
+	result := filter.Invoke(context.Background(), protocol.NewBaseInvoker(url),
+		invocation.NewRPCInvocation("GetUser", []interface{}{"OK"}, nil))
+	assert.Nil(t, result.Error())
+}
+
+func TestFilterInvokeAbort(t *testing.T) {
+	filter := &Filter{}
+	url := common.NewURLWithOptions(
+		common.WithParamsValue(constant.FAULT_INJECT_ENABLED_KEY, "true"),
+		common.WithParamsValue(constant.FAULT_INJECT_ABORT_PERCENT_KEY, "100"),
+		common.WithParamsValue(constant.FAULT_INJECT_ABORT_ERROR_KEY, "service unavailable"))
+	result := filter.Invoke(context.Background(), protocol.NewBaseInvoker(url),
+		invocation.NewRPCInvocation("GetUser", []interface{}{"OK"}, nil))
+	assert.EqualError(t, result.Error(), "service unavailable")
+
+	// the method-level configuration overrides the service-level one
+	url.SetParam("methods.GetUser."+constant.FAULT_INJECT_ABORT_PERCENT_KEY, "0")
+	result = filter.Invoke(context.Background(), protocol.NewBaseInvoker(url),
+		invocation.NewRPCInvocation("GetUser", []interface{}{"OK"}, nil))
+	assert.Nil(t, result.Error())
+}
+
+func TestFilterInvokeDelay(t *testing.T) {
+	filter := &Filter{}
+	url := common.NewURLWithOptions(
+		common.WithParamsValue(constant.FAULT_INJECT_ENABLED_KEY, "true"),
+		common.WithParamsValue(constant.FAULT_INJECT_DELAY_PERCENT_KEY, "100"),
+		common.WithParamsValue(constant.FAULT_INJECT_DELAY_KEY, "100ms"))
+	start := time.Now()
+	result := filter.Invoke(context.Background(), protocol.NewBaseInvoker(url),
+		invocation.NewRPCInvocation("GetUser", []interface{}{"OK"}, nil))
+	assert.Nil(t, result.Error())
+	assert.True(t, time.Since(start) >= 100*time.Millisecond)
+
+	// the delay is interrupted once the context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	result = filter.Invoke(ctx, protocol.NewBaseInvoker(url),
+		invocation.NewRPCInvocation("GetUser", []interface{}{"OK"}, nil))
+	assert.Equal(t, context.DeadlineExceeded, result.Error())
+}
@@ -22,18 +22,30 @@ package filter_impl
 
 import (
 	_ "dubbo.apache.org/dubbo-go/v3/filter/accesslog"
+	_ "dubbo.apache.org/dubbo-go/v3/filter/acl"
 	_ "dubbo.apache.org/dubbo-go/v3/filter/active"
+	_ "dubbo.apache.org/dubbo-go/v3/filter/admission"
+	_ "dubbo.apache.org/dubbo-go/v3/filter/attachment"
 	_ "dubbo.apache.org/dubbo-go/v3/filter/auth"
+	_ "dubbo.apache.org/dubbo-go/v3/filter/ctxpropagation"
+	_ "dubbo.apache.org/dubbo-go/v3/filter/dedup"
 	_ "dubbo.apache.org/dubbo-go/v3/filter/echo"
+	_ "dubbo.apache.org/dubbo-go/v3/filter/encryption"
 	_ "dubbo.apache.org/dubbo-go/v3/filter/execlmt"
+	_ "dubbo.apache.org/dubbo-go/v3/filter/faultinject"
 	_ "dubbo.apache.org/dubbo-go/v3/filter/generic"
 	_ "dubbo.apache.org/dubbo-go/v3/filter/gshutdown"
 	_ "dubbo.apache.org/dubbo-go/v3/filter/hystrix"
 	_ "dubbo.apache.org/dubbo-go/v3/filter/metrics"
+	_ "dubbo.apache.org/dubbo-go/v3/filter/priority"
+	_ "dubbo.apache.org/dubbo-go/v3/filter/ratelimit"
 	_ "dubbo.apache.org/dubbo-go/v3/filter/seata"
 	_ "dubbo.apache.org/dubbo-go/v3/filter/sentinel"
+	_ "dubbo.apache.org/dubbo-go/v3/filter/singleflight"
+	_ "dubbo.apache.org/dubbo-go/v3/filter/slowrequest"
 	_ "dubbo.apache.org/dubbo-go/v3/filter/token"
 	_ "dubbo.apache.org/dubbo-go/v3/filter/tps"
+	_ "dubbo.apache.org/dubbo-go/v3/filter/tracelog"
 	_ "dubbo.apache.org/dubbo-go/v3/filter/tracing"
 )
 
@@ -31,7 +31,6 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/common/logger"
 	"dubbo.apache.org/dubbo-go/v3/filter"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
-	invocation2 "dubbo.apache.org/dubbo-go/v3/protocol/invocation"
 )
 
 func init() {
@@ -79,13 +78,12 @@ func (f *Filter) Invoke(ctx context.Context, invoker protocol.Invoker, invocatio
 			types,
 			args,
 		}
-		newivc := invocation2.NewRPCInvocation(constant.GENERIC, newargs, invocation.Attachments())
-		newivc.SetReply(invocation.Reply())
-		newivc.Attachments()[constant.GENERIC_KEY] = invoker.GetURL().GetParam(constant.GENERIC_KEY, "")
+		newivc := newInvocation(constant.GENERIC, newargs, invocation)
+		newivc.SetAttachments(constant.GENERIC_KEY, invoker.GetURL().GetParam(constant.GENERIC_KEY, ""))
 
 		return invoker.Invoke(ctx, newivc)
 	} else if isMakingAGenericCall(invoker, invocation) {
-		invocation.Attachments()[constant.GENERIC_KEY] = invoker.GetURL().GetParam(constant.GENERIC_KEY, "")
+		invocation.SetAttachments(constant.GENERIC_KEY, invoker.GetURL().GetParam(constant.GENERIC_KEY, ""))
 	}
 	return invoker.Invoke(ctx, invocation)
 }
@@ -100,3 +100,72 @@ func TestFilter_InvokeWithGenericCall(t *testing.T) {
 	result := filter.Invoke(context.Background(), mockInvoker, genericInvocation)
 	assert.NotNil(t, result)
 }
+
+type AttachmentHelloService struct{}
+
+func (s *AttachmentHelloService) Hello(who string) (string, error) {
+	return "hello, " + who, nil
+}
+
+func (s *AttachmentHelloService) Reference() string {
+	return "org.apache.dubbo.attachment"
+}
+
+type passInvoker struct {
+	protocol.BaseInvoker
+	invoke func(ctx context.Context, invocation protocol.Invocation) protocol.Result
+}
+
+func (i *passInvoker) Invoke(ctx context.Context, invocation protocol.Invocation) protocol.Result {
+	return i.invoke(ctx, invocation)
+}
+
+// test the custom attachments of a generic call reach the provider
+func TestFilter_InvokeWithAttachments(t *testing.T) {
+	service := &AttachmentHelloService{}
+	ivkUrl := common.NewURLWithOptions(
+		common.WithProtocol("test"),
+		common.WithParams(url.Values{}),
+		common.WithParamsValue(constant.INTERFACE_KEY, service.Reference()),
+		common.WithParamsValue(constant.GENERIC_KEY, constant.GenericSerializationDefault))
+	_, err := common.ServiceMap.Register(service.Reference(), ivkUrl.Protocol, "", "", service)
+	assert.Nil(t, err)
+
+	var providerInvocation protocol.Invocation
+	provider := &passInvoker{
+		BaseInvoker: *protocol.NewBaseInvoker(ivkUrl),
+		invoke: func(_ context.Context, invocation protocol.Invocation) protocol.Result {
+			providerInvocation = invocation
+			return &protocol.RPCResult{Rest: "hello, world"}
+		},
+	}
+	// the generic invocation is transferred from the consumer filter to the service filter
+	consumer := &passInvoker{
+		BaseInvoker: *protocol.NewBaseInvoker(ivkUrl),
+		invoke: func(ctx context.Context, invocation protocol.Invocation) protocol.Result {
+			assert.Equal(t, constant.GENERIC, invocation.MethodName())
+			args := invocation.Arguments()
+			assert.Equal(t, "Hello", args[0])
+			assert.Equal(t, []string{"java.lang.String"}, args[1])
+			assert.Equal(t, []hessian.Object{"world"}, args[2])
+			return (&ServiceFilter{}).Invoke(ctx, provider, invocation)
+		},
+	}
+
+	// no attachments at all
+	result := (&Filter{}).Invoke(context.Background(), consumer,
+		invocation.NewRPCInvocation("Hello", []interface{}{"world"}, nil))
+	assert.NoError(t, result.Error())
+
+	attachments := map[string]interface{}{"auth": "token", "traceId": "trace-1"}
+	result = (&Filter{}).Invoke(context.Background(), consumer,
+		invocation.NewRPCInvocation("Hello", []interface{}{"world"}, attachments))
+	assert.NoError(t, result.Error())
+	assert.Equal(t, "hello, world", result.Result())
+	assert.Equal(t, "Hello", providerInvocation.MethodName())
+	assert.Equal(t, []interface{}{"world"}, providerInvocation.Arguments())
+	assert.Equal(t, "token", providerInvocation.AttachmentsByKey("auth", ""))
+	assert.Equal(t, "trace-1", providerInvocation.AttachmentsByKey("traceId", ""))
+	// the generic key isn't leaked into the attachments of the caller
+	assert.NotContains(t, attachments, constant.GENERIC_KEY)
+}
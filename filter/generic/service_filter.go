@@ -34,7 +34,6 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/common/logger"
 	"dubbo.apache.org/dubbo-go/v3/filter"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
-	invocation2 "dubbo.apache.org/dubbo-go/v3/protocol/invocation"
 )
 
 func init() {
@@ -105,8 +104,7 @@ func (f *ServiceFilter) Invoke(ctx context.Context, invoker protocol.Invoker, in
 	}
 
 	// build a normal invocation
-	newivc := invocation2.NewRPCInvocation(mtdname, newargs, invocation.Attachments())
-	newivc.SetReply(invocation.Reply())
+	newivc := newInvocation(mtdname, newargs, invocation)
 
 	return invoker.Invoke(ctx, newivc)
 }
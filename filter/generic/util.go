@@ -26,6 +26,7 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/common/logger"
 	"dubbo.apache.org/dubbo-go/v3/filter/generic/generalizer"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
+	invocation2 "dubbo.apache.org/dubbo-go/v3/protocol/invocation"
 )
 
 // isCallingToGenericService check if it calls to a generic service
@@ -55,6 +56,26 @@ func isGenericInvocation(invocation protocol.Invocation) bool {
 		len(invocation.Arguments()) == 3
 }
 
+// newInvocation builds the invocation of @methodName with @args transformed from @origin. The attachments of
+// @origin, e.g. the auth and the trace ones set by the gateways, and its attributes are carried over, and the
+// attachments are copied so that the ones added to the new invocation don't leak into @origin.
+func newInvocation(methodName string, args []interface{}, origin protocol.Invocation) *invocation2.RPCInvocation {
+	attachments := make(map[string]interface{}, len(origin.Attachments())+1)
+	for k, v := range origin.Attachments() {
+		attachments[k] = v
+	}
+	ivc := invocation2.NewRPCInvocation(methodName, args, attachments)
+	ivc.SetReply(origin.Reply())
+	ivc.SetInvoker(origin.Invoker())
+	for k, v := range origin.Attributes() {
+		ivc.SetAttribute(k, v)
+	}
+	if rpcInvocation, ok := origin.(*invocation2.RPCInvocation); ok {
+		ivc.SetCallBack(rpcInvocation.CallBack())
+	}
+	return ivc
+}
+
 // toUnexport is to lower the first letter
 func toUnexport(a string) string {
 	return strings.ToLower(a[:1]) + a[1:]
@@ -0,0 +1,181 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package hystrix
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+import (
+	"github.com/afex/hystrix-go/hystrix"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+)
+
+// CircuitState is the state of the circuit breaker of a method of an invoker
+type CircuitState int
+
+const (
+	// CircuitClosed means the requests flow through the circuit
+	CircuitClosed CircuitState = iota
+	// CircuitOpen means the requests are rejected by the circuit
+	CircuitOpen
+	// CircuitHalfOpen means the circuit is open, while the sleep window has passed so that a single request
+	// is allowed to test whether the invoker recovers
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("unknown(%d)", int(s))
+}
+
+// CircuitInfo describes the circuit breaker of a method of an invoker
+type CircuitInfo struct {
+	ServiceKey string
+	Method     string
+	// Invoker is the key of the url of the invoker
+	Invoker string
+	State   CircuitState
+}
+
+// circuits holds the *circuit by the names of the commands
+var circuits sync.Map
+
+// circuit tracks the hystrix command of a method of an invoker
+type circuit struct {
+	serviceKey string
+	method     string
+	invoker    string
+	// openedOrTested is the time in nanoseconds the circuit opened or the last single test passed through,
+	// and it's 0 while the circuit is closed
+	openedOrTested int64
+}
+
+func (c *circuit) commandName() string {
+	return fmt.Sprintf("%s&method=%s", c.invoker, c.method)
+}
+
+// before is called before a request goes through the circuit, and the request is a test if the circuit is open
+func (c *circuit) before(cb *hystrix.CircuitBreaker) {
+	if cb.IsOpen() {
+		atomic.StoreInt64(&c.openedOrTested, time.Now().UnixNano())
+	}
+}
+
+// observe records when the circuit opens, and it's called after a request is done or rejected
+func (c *circuit) observe(cb *hystrix.CircuitBreaker) {
+	if !cb.IsOpen() {
+		atomic.StoreInt64(&c.openedOrTested, 0)
+		return
+	}
+	atomic.CompareAndSwapInt64(&c.openedOrTested, 0, time.Now().UnixNano())
+}
+
+func (c *circuit) state() CircuitState {
+	name := c.commandName()
+	configLoadMutex.RLock()
+	cb, _, err := hystrix.GetCircuit(name)
+	configLoadMutex.RUnlock()
+	if err != nil {
+		return CircuitClosed
+	}
+	// the circuit may be opened by the metrics updated after the last request
+	c.observe(cb)
+	openedOrTested := atomic.LoadInt64(&c.openedOrTested)
+	if openedOrTested == 0 {
+		return CircuitClosed
+	}
+	sleepWindow := time.Duration(hystrix.DefaultSleepWindow) * time.Millisecond
+	if settings := hystrix.GetCircuitSettings()[name]; settings != nil {
+		sleepWindow = settings.SleepWindow
+	}
+	if time.Now().UnixNano() > openedOrTested+sleepWindow.Nanoseconds() {
+		return CircuitHalfOpen
+	}
+	return CircuitOpen
+}
+
+// reset closes the hystrix circuit in place, rather than moving to a new command whose breaker and metric
+// goroutine would never be released by hystrix. The circuit is closed by the success reported to it, which
+// resets its metrics, and the failures of a closed circuit not enough to open it are kept.
+func (c *circuit) reset() {
+	configLoadMutex.RLock()
+	cb, _, err := hystrix.GetCircuit(c.commandName())
+	configLoadMutex.RUnlock()
+	if err != nil {
+		return
+	}
+	// the unhealthy circuit is opened first, so that it's closed with its metrics reset
+	if cb.IsOpen() {
+		if err = cb.ReportEvent([]string{"success"}, time.Now(), 0); err != nil {
+			logger.Warnf("[Hystrix Filter]Report the reset of circuit %s error: %v", c.commandName(), err)
+		}
+	}
+	atomic.StoreInt64(&c.openedOrTested, 0)
+}
+
+// GetCircuits returns the circuits of all of the methods of the invokers going through the hystrix filters,
+// in the order of the service keys, the methods and the invokers
+func GetCircuits() []CircuitInfo {
+	infos := make([]CircuitInfo, 0)
+	circuits.Range(func(_, value interface{}) bool {
+		c := value.(*circuit)
+		infos = append(infos, CircuitInfo{ServiceKey: c.serviceKey, Method: c.method, Invoker: c.invoker, State: c.state()})
+		return true
+	})
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].ServiceKey != infos[j].ServiceKey {
+			return infos[i].ServiceKey < infos[j].ServiceKey
+		}
+		if infos[i].Method != infos[j].Method {
+			return infos[i].Method < infos[j].Method
+		}
+		return infos[i].Invoker < infos[j].Invoker
+	})
+	return infos
+}
+
+// ResetCircuit force-closes the circuits of the method @method of all invokers of the service @serviceKey,
+// e.g. once a known fix is deployed, so that the requests flow through them again without waiting for the tests.
+// It returns false if there isn't any circuit of the method.
+func ResetCircuit(serviceKey string, method string) bool {
+	found := false
+	circuits.Range(func(_, value interface{}) bool {
+		if c := value.(*circuit); c.serviceKey == serviceKey && c.method == method {
+			c.reset()
+			found = true
+			logger.Infof("[Hystrix Filter]Circuit of %s is reset manually", c.commandName())
+		}
+		return true
+	})
+	return found
+}
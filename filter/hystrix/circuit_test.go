@@ -0,0 +1,100 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package hystrix
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/afex/hystrix-go/hystrix"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+func circuitOf(serviceKey string, method string) *CircuitInfo {
+	for _, info := range GetCircuits() {
+		if info.ServiceKey == serviceKey && info.Method == method {
+			return &info
+		}
+	}
+	return nil
+}
+
+func TestResetCircuit(t *testing.T) {
+	mockInitHystrixConfig()
+	confConsumer.Configs["circuit"] = &CommandConfigWithError{
+		Timeout:                1000,
+		MaxConcurrentRequests:  64,
+		RequestVolumeThreshold: 5,
+		SleepWindow:            200,
+		ErrorPercentThreshold:  50,
+	}
+	confConsumer.Services["com.ikurento.user.CircuitProvider"] = ServiceHystrixConfig{ServiceConfig: "circuit"}
+
+	url, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.CircuitProvider?interface=com.ikurento.user.CircuitProvider")
+	assert.NoError(t, err)
+	serviceKey := url.ServiceKey()
+	failInvoker := &testMockFailInvoker{*protocol.NewBaseInvoker(url)}
+	successInvoker := &testMockSuccessInvoker{*protocol.NewBaseInvoker(url)}
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUser"))
+	hf := &Filter{COrP: true}
+
+	assert.False(t, ResetCircuit(serviceKey, "GetUnknown"))
+	// the circuit is left by the former runs of the test
+	ResetCircuit(serviceKey, "GetUser")
+	result := hf.Invoke(context.Background(), successInvoker, inv)
+	assert.NoError(t, result.Error())
+	info := circuitOf(serviceKey, "GetUser")
+	assert.NotNil(t, info)
+	assert.Equal(t, url.Key(), info.Invoker)
+	assert.Equal(t, CircuitClosed, info.State)
+
+	// the metrics of the failures are collected asynchronously
+	assert.Eventually(t, func() bool {
+		hf.Invoke(context.Background(), failInvoker, inv)
+		return circuitOf(serviceKey, "GetUser").State == CircuitOpen
+	}, 5*time.Second, 10*time.Millisecond)
+	result = hf.Invoke(context.Background(), successInvoker, inv)
+	assert.True(t, result.Error().(*FilterError).FailByHystrix())
+
+	assert.Eventually(t, func() bool {
+		return circuitOf(serviceKey, "GetUser").State == CircuitHalfOpen
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the circuit is closed in place without any new command
+	commands := len(hystrix.GetCircuitSettings())
+	assert.True(t, ResetCircuit(serviceKey, "GetUser"))
+	assert.Equal(t, CircuitClosed, circuitOf(serviceKey, "GetUser").State)
+	assert.Equal(t, commands, len(hystrix.GetCircuitSettings()))
+	for i := 0; i < 10; i++ {
+		result = hf.Invoke(context.Background(), successInvoker, inv)
+		assert.NoError(t, result.Error())
+		assert.Equal(t, "Success", result.Result())
+	}
+	assert.Equal(t, CircuitClosed, circuitOf(serviceKey, "GetUser").State)
+	assert.Equal(t, "half-open", CircuitHalfOpen.String())
+}
@@ -132,6 +132,15 @@ type Filter struct {
 // Invoke is an implementation of filter, provides Hystrix pattern latency and fault tolerance
 func (f *Filter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
 	cmdName := fmt.Sprintf("%s&method=%s", invoker.GetURL().Key(), invocation.MethodName())
+	value, ok := circuits.Load(cmdName)
+	if !ok {
+		value, _ = circuits.LoadOrStore(cmdName, &circuit{
+			serviceKey: invoker.GetURL().ServiceKey(),
+			method:     invocation.MethodName(),
+			invoker:    invoker.GetURL().Key(),
+		})
+	}
+	c := value.(*circuit)
 
 	// Do the configuration if the circuit breaker is created for the first time
 	if _, load := f.ifNewMap.LoadOrStore(cmdName, true); !load {
@@ -158,7 +167,7 @@ func (f *Filter) Invoke(ctx context.Context, invoker protocol.Invoker, invocatio
 		configLoadMutex.Unlock()
 	}
 	configLoadMutex.RLock()
-	_, _, err := hystrix.GetCircuit(cmdName)
+	cb, _, err := hystrix.GetCircuit(cmdName)
 	configLoadMutex.RUnlock()
 	if err != nil {
 		logger.Errorf("[Hystrix Filter]Errors occurred getting circuit for %s , will invoke without hystrix, error is: %+v", cmdName, err)
@@ -167,6 +176,7 @@ func (f *Filter) Invoke(ctx context.Context, invoker protocol.Invoker, invocatio
 	logger.Infof("[Hystrix Filter]Using hystrix filter: %s", cmdName)
 	var result protocol.Result
 	_ = hystrix.Do(cmdName, func() error {
+		c.before(cb)
 		result = invoker.Invoke(ctx, invocation)
 		err := result.Error()
 		if err != nil {
@@ -188,6 +198,7 @@ func (f *Filter) Invoke(ctx context.Context, invoker protocol.Invoker, invocatio
 		result.SetError(NewHystrixFilterError(err, ok))
 		return err
 	})
+	c.observe(cb)
 	return result
 }
 
@@ -19,12 +19,17 @@ package metrics
 
 import (
 	"context"
+	"strconv"
+	"strings"
 	"time"
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/config"
 	"dubbo.apache.org/dubbo-go/v3/filter"
 	"dubbo.apache.org/dubbo-go/v3/metrics"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
@@ -40,7 +45,7 @@ func init() {
 // Filter will calculate the invocation's duration and the report to the reporters
 // more info please take a look at dubbo-samples projects
 type Filter struct {
-	reporters []metrics.Reporter
+	reporters []metrics.MetricsReporter
 }
 
 // Invoke collect the duration of invocation and then report the duration by using goroutine
@@ -49,26 +54,67 @@ func (p *Filter) Invoke(ctx context.Context, invoker protocol.Invoker, invocatio
 	res := invoker.Invoke(ctx, invocation)
 	end := time.Now()
 	duration := end.Sub(start)
-	go func() {
-		for _, reporter := range p.reporters {
-			reporter.Report(ctx, invoker, invocation, duration, res)
-		}
-	}()
+	go p.report(invoker, invocation, duration, res)
 	return res
 }
 
+// report writes the metrics of the invocation to the reporters
+// the role in url must be consumer or provider
+// or it will be ignored
+func (p *Filter) report(invoker protocol.Invoker, invocation protocol.Invocation, cost time.Duration, res protocol.Result) {
+	url := invoker.GetURL()
+	var prefix string
+	if isProvider(url) {
+		prefix = metrics.ProviderPrefix
+	} else if isConsumer(url) {
+		prefix = metrics.ConsumerPrefix
+	} else {
+		logger.Warnf("The url belongs neither the consumer nor the provider, "+
+			"so the invocation will be ignored. url: %s", url.String())
+		return
+	}
+
+	tags := metrics.NewInvocationTags(invoker, invocation)
+	tags[constant.TIMEOUT_KEY] = url.GetParam(constant.TIMEOUT_KEY, "")
+	costMs := float64(cost.Nanoseconds()) / float64(time.Millisecond)
+	for _, reporter := range p.reporters {
+		reporter.IncCounter(prefix+metrics.RequestsTotal, 1, tags)
+		if res != nil && res.Error() != nil {
+			reporter.IncCounter(prefix+metrics.RequestsFailedTotal, 1, tags)
+		}
+		reporter.SetGauge(prefix+metrics.ServiceRT, float64(cost.Nanoseconds()), tags)
+		reporter.ObserveHistogram(prefix+metrics.ServiceRTHistogram, costMs, tags)
+	}
+}
+
 // OnResponse do nothing and return the result
 func (p *Filter) OnResponse(ctx context.Context, res protocol.Result, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
 	return res
 }
 
+// isProvider shows whether this url represents the application received the request as server
+func isProvider(url *common.URL) bool {
+	role := url.GetParam(constant.ROLE_KEY, "")
+	return strings.EqualFold(role, strconv.Itoa(common.PROVIDER))
+}
+
+// isConsumer shows whether this url represents the application sent then request as client
+func isConsumer(url *common.URL) bool {
+	role := url.GetParam(constant.ROLE_KEY, "")
+	return strings.EqualFold(role, strconv.Itoa(common.CONSUMER))
+}
+
 // newFilter the Filter is singleton.
 // it's lazy initialization
 // make sure that the configuration had been loaded before invoking this method.
 func newFilter() filter.Filter {
 	if metricFilterInstance == nil {
-		reporters := make([]metrics.Reporter, 0, 1)
-		reporters = append(reporters, extension.GetMetricReporter("prometheus", metrics.NewReporterConfig()))
+		reporterConfig := metrics.NewReporterConfig()
+		if metricConfig := config.GetMetricConfig(); metricConfig != nil {
+			reporterConfig = metricConfig.ToReporterConfig()
+		}
+		reporters := make([]metrics.MetricsReporter, 0, 1)
+		reporters = append(reporters, extension.GetMetricReporter(reporterConfig.Protocol, reporterConfig))
 		metricFilterInstance = &Filter{
 			reporters: reporters,
 		}
@@ -19,64 +19,106 @@ package metrics
 
 import (
 	"context"
-	"sync"
+	"errors"
 	"testing"
 	"time"
 )
 
 import (
 	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/mock"
 )
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
-	"dubbo.apache.org/dubbo-go/v3/common/extension"
 	"dubbo.apache.org/dubbo-go/v3/metrics"
-	_ "dubbo.apache.org/dubbo-go/v3/metrics/prometheus"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
 )
 
 func TestMetricsFilterInvoke(t *testing.T) {
-	// prepare the mock reporter
-	mk := &mockReporter{}
-	extension.SetMetricReporter("mock", func(config *metrics.ReporterConfig) metrics.Reporter {
-		return mk
-	})
-
-	instance := newFilter()
+	reporter := &fakeReporter{metrics: make(chan fakeMetric, 8)}
+	instance := &Filter{reporters: []metrics.MetricsReporter{reporter}}
 
 	url, _ := common.NewURL(
 		"dubbo://:20000/UserProvider?app.version=0.0.1&application=BDTService&bean.name=UserProvider" +
 			"&cluster=failover&environment=dev&group=&interface=com.ikurento.user.UserProvider&loadbalance=random&methods.GetUser." +
 			"loadbalance=random&methods.GetUser.retries=1&methods.GetUser.weight=0&module=dubbogo+user-info+server&name=" +
 			"BDTService&organization=ikurento.com&owner=ZX&registry.role=3&retries=&" +
-			"service.filter=echo%2Ctoken%2Caccesslog&timestamp=1569153406&token=934804bf-b007-4174-94eb-96e3e1d60cc7&version=&warmup=100")
-	invoker := protocol.NewBaseInvoker(url)
+			"service.filter=echo%2Ctoken%2Caccesslog&timestamp=1569153406&timeout=3s&token=934804bf-b007-4174-94eb-96e3e1d60cc7&version=&warmup=100")
+	invoker := &failingInvoker{BaseInvoker: *protocol.NewBaseInvoker(url)}
 
 	attach := make(map[string]interface{}, 10)
 	inv := invocation.NewRPCInvocation("MethodName", []interface{}{"OK", "Hello"}, attach)
 
 	ctx := context.Background()
-
-	mk.On("Report", ctx, invoker, inv).Return(true, nil)
-
-	mk.wg.Add(1)
 	result := instance.Invoke(ctx, invoker, inv)
 	assert.NotNil(t, result)
-	mk.AssertNotCalled(t, "Report", 1)
+
+	tags := map[string]string{
+		"service": "com.ikurento.user.UserProvider",
+		"group":   "",
+		"version": "0.0.1",
+		"method":  "MethodName",
+		"timeout": "3s",
+	}
+	names := make([]string, 0, 4)
+	values := make(map[string]float64, 4)
+	for i := 0; i < 4; i++ {
+		select {
+		case metric := <-reporter.metrics:
+			assert.Equal(t, tags, metric.tags)
+			names = append(names, metric.kind+":"+metric.name)
+			values[metric.name] = metric.value
+		case <-time.After(time.Second):
+			t.Fatal("the metrics of the invocation are not reported")
+		}
+	}
+	assert.Equal(t, []string{
+		"counter:provider_requests_total",
+		"counter:provider_requests_failed_total",
+		"gauge:provider_service_rt",
+		"histogram:provider_service_rt_histogram",
+	}, names)
+	// the gauge keeps the nanoseconds while the histogram is in milliseconds
+	assert.GreaterOrEqual(t, values["provider_service_rt"], float64(invokeLatency.Nanoseconds()))
+	assert.GreaterOrEqual(t, values["provider_service_rt_histogram"], float64(invokeLatency.Milliseconds()))
+	assert.Less(t, values["provider_service_rt_histogram"], float64(time.Second.Milliseconds()))
+
 	// it will do nothing
 	result = instance.OnResponse(ctx, nil, invoker, inv)
 	assert.Nil(t, result)
 }
 
-type mockReporter struct {
-	mock.Mock
-	wg sync.WaitGroup
+const invokeLatency = 5 * time.Millisecond
+
+type failingInvoker struct {
+	protocol.BaseInvoker
+}
+
+func (fi *failingInvoker) Invoke(context.Context, protocol.Invocation) protocol.Result {
+	time.Sleep(invokeLatency)
+	return &protocol.RPCResult{Err: errors.New("failed")}
+}
+
+type fakeMetric struct {
+	kind  string
+	name  string
+	value float64
+	tags  map[string]string
+}
+
+type fakeReporter struct {
+	metrics chan fakeMetric
+}
+
+func (f *fakeReporter) IncCounter(name string, value float64, tags map[string]string) {
+	f.metrics <- fakeMetric{kind: "counter", name: name, value: value, tags: tags}
+}
+
+func (f *fakeReporter) SetGauge(name string, value float64, tags map[string]string) {
+	f.metrics <- fakeMetric{kind: "gauge", name: name, value: value, tags: tags}
 }
 
-func (m *mockReporter) Report(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation, cost time.Duration, res protocol.Result) {
-	m.Called(ctx, invoker, invocation)
-	m.wg.Done()
+func (f *fakeReporter) ObserveHistogram(name string, value float64, tags map[string]string) {
+	f.metrics <- fakeMetric{kind: "histogram", name: name, value: value, tags: tags}
 }
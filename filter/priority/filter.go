@@ -0,0 +1,130 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package priority
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+var (
+	priorityOnce   sync.Once
+	priorityFilter *Filter
+)
+
+func init() {
+	extension.SetFilter(constant.PriorityFilterKey, newFilter)
+}
+
+// Filter sheds the requests of the lower priority lanes first once the provider is congested.
+/**
+ * example:
+ * "UserProvider":
+ *   filter: "priority,echo,token,accesslog"
+ *   params:
+ *     priority.lanes: "high:200,default:150,low:100"
+ *     priority.default: "default"
+ * The consumers choose the lane of the request by the priority attachment, e.g. "high", and the requests without it
+ * or with an unknown lane are in the default lane. The number of a lane is the number of the requests of the service
+ * in flight, across all of the lanes, above which the requests of the lane are rejected, so that the low ones are
+ * shed once 100 requests are in flight while the high ones are still admitted until 200.
+ * The requests of the default lane are always admitted if it isn't listed by the lanes.
+ */
+type Filter struct {
+	// inFlights holds the *int64 number of the requests in flight by the service keys
+	inFlights sync.Map
+	// lanes caches the parsed thresholds by the priority.lanes
+	lanes sync.Map
+}
+
+// newFilter returns the singleton Filter instance
+func newFilter() filter.Filter {
+	priorityOnce.Do(func() {
+		priorityFilter = &Filter{}
+	})
+	return priorityFilter
+}
+
+// Invoke admits the request if the requests in flight don't reach the threshold of its lane
+func (f *Filter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	url := invoker.GetURL()
+	thresholds := f.thresholds(url.GetParam(constant.PRIORITY_LANES_KEY, ""))
+	lane, _ := invocation.Attachment(constant.PRIORITY_KEY).(string)
+	threshold, ok := thresholds[lane]
+	if !ok {
+		lane = url.GetParam(constant.PRIORITY_DEFAULT_LANE_KEY, constant.DEFAULT_PRIORITY_LANE)
+		threshold, ok = thresholds[lane]
+	}
+
+	actual, _ := f.inFlights.LoadOrStore(url.ServiceKey(), new(int64))
+	inFlight := actual.(*int64)
+	defer atomic.AddInt64(inFlight, -1)
+	if n := atomic.AddInt64(inFlight, 1); ok && n > threshold {
+		logger.Warnf("[Priority Filter] the request of the lane %s to %s#%s is shed with %d requests in flight",
+			lane, url.ServiceKey(), invocation.MethodName(), n-1)
+		return &protocol.RPCResult{Err: perrors.Errorf("the request of the priority lane %s is shed since the "+
+			"provider of the service %s is congested", lane, url.ServiceKey())}
+	}
+	return invoker.Invoke(ctx, invocation)
+}
+
+// OnResponse dummy process, returns the result directly
+func (f *Filter) OnResponse(_ context.Context, result protocol.Result, _ protocol.Invoker, _ protocol.Invocation) protocol.Result {
+	return result
+}
+
+// thresholds parses the lanes configured as the comma separated lane:threshold pairs, the invalid ones are ignored
+func (f *Filter) thresholds(lanes string) map[string]int64 {
+	if cached, ok := f.lanes.Load(lanes); ok {
+		return cached.(map[string]int64)
+	}
+	thresholds := make(map[string]int64)
+	for _, pair := range strings.Split(lanes, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		i := strings.LastIndex(pair, ":")
+		if i < 0 {
+			logger.Warnf("[Priority Filter] the priority lane %s is invalid without the threshold", pair)
+			continue
+		}
+		threshold, err := strconv.ParseInt(strings.TrimSpace(pair[i+1:]), 10, 64)
+		if err != nil {
+			logger.Warnf("[Priority Filter] the threshold of the priority lane %s is invalid: %v", pair, err)
+			continue
+		}
+		thresholds[strings.TrimSpace(pair[:i])] = threshold
+	}
+	f.lanes.Store(lanes, thresholds)
+	return thresholds
+}
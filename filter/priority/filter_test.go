@@ -0,0 +1,116 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package priority
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+// blockInvoker blocks the requests of the Block method until release is closed
+type blockInvoker struct {
+	protocol.BaseInvoker
+	started sync.WaitGroup
+	release chan struct{}
+}
+
+func (bi *blockInvoker) Invoke(_ context.Context, inv protocol.Invocation) protocol.Result {
+	if inv.MethodName() == "Block" {
+		bi.started.Done()
+		<-bi.release
+	}
+	return &protocol.RPCResult{}
+}
+
+func TestFilterInvoke(t *testing.T) {
+	url, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?side=provider" +
+		"&priority.lanes=high:5,default:4,low:2")
+	assert.NoError(t, err)
+	invoker := &blockInvoker{BaseInvoker: *protocol.NewBaseInvoker(url), release: make(chan struct{})}
+	f := newFilter()
+	invoke := func(method, lane string) protocol.Result {
+		attachments := map[string]interface{}{}
+		if lane != "" {
+			attachments[constant.PRIORITY_KEY] = lane
+		}
+		return f.Invoke(context.Background(), invoker, invocation.NewRPCInvocation(method, nil, attachments))
+	}
+
+	// all of the lanes are admitted without the congestion
+	for _, lane := range []string{"high", "low", "", "unknown"} {
+		assert.NoError(t, invoke("GetUser", lane).Error())
+	}
+
+	// congest the provider with 3 requests in flight
+	var done sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		invoker.started.Add(1)
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			assert.NoError(t, invoke("Block", "high").Error())
+		}()
+	}
+	invoker.started.Wait()
+
+	for i := 0; i < 3; i++ {
+		assert.Error(t, invoke("GetUser", "low").Error())
+		assert.NoError(t, invoke("GetUser", "high").Error())
+		assert.NoError(t, invoke("GetUser", "").Error())
+	}
+
+	// congest the provider further with the 4th request until the unprioritized ones are shed as well
+	invoker.started.Add(1)
+	done.Add(1)
+	go func() {
+		defer done.Done()
+		assert.NoError(t, invoke("Block", "high").Error())
+	}()
+	invoker.started.Wait()
+	assert.Error(t, invoke("GetUser", "").Error())
+	assert.Error(t, invoke("GetUser", "unknown").Error())
+	assert.NoError(t, invoke("GetUser", "high").Error())
+
+	close(invoker.release)
+	done.Wait()
+	assert.NoError(t, invoke("GetUser", "low").Error())
+}
+
+func TestFilterInvokeWithoutDefaultLane(t *testing.T) {
+	url, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?side=provider" +
+		"&priority.lanes=low:0,bad,worse:x")
+	assert.NoError(t, err)
+	invoker := protocol.NewBaseInvoker(url)
+	f := newFilter()
+	inv := invocation.NewRPCInvocation("GetUser", nil, map[string]interface{}{constant.PRIORITY_KEY: "low"})
+	assert.Error(t, f.Invoke(context.Background(), invoker, inv).Error())
+	// the requests of the default lane are always admitted if it isn't listed
+	assert.NoError(t, f.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("GetUser", nil, nil)).Error())
+}
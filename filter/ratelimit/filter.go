@@ -0,0 +1,229 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+
+	"gopkg.in/yaml.v2"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/config"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+const (
+	// modeQueue makes the calls over the limit wait for the next window
+	modeQueue = "queue"
+	// defaultInterval is the window of the rate
+	defaultInterval = time.Second
+)
+
+var (
+	rateLimitOnce   sync.Once
+	rateLimitFilter *Filter
+)
+
+func init() {
+	extension.SetFilter(constant.ConsumerRateLimitFilterKey, newFilter)
+}
+
+// Rule is the rate limit of a consumer application calling a service, which is published to the config center
+// with the key <application>.<interface>.rate-limit in yaml
+type Rule struct {
+	// Rate is the number of the calls allowed within the interval, and the calls aren't limited if it isn't positive
+	Rate int `yaml:"rate"`
+	// Interval is the window of the rate, e.g. 1s, which is 1s by default
+	Interval string `yaml:"interval"`
+	// Mode is either reject by default to fail the calls over the limit, or queue to make them wait for the next window
+	Mode string `yaml:"mode"`
+}
+
+// Filter throttles the outbound calls of the consumer by the rate limits pushed by the config center.
+/**
+ * example:
+ * "UserProvider":
+ *   filter: "consumer-rate-limit"
+ * and the rule published to the config center with the key order-center.com.ikurento.user.UserProvider.rate-limit,
+ * in which order-center is the name of the consumer application:
+ *   rate: 100
+ *   interval: 1s
+ *   mode: queue
+ * The calls aren't throttled until the rule is published, and the throttling stops once the rule is removed.
+ * The calls over the limit fail at once in the reject mode, and wait until the next window or the context
+ * is done in the queue mode.
+ */
+type Filter struct {
+	// limiters holds the *limiter by the keys of the rules
+	limiters sync.Map
+}
+
+// newFilter returns the singleton Filter instance
+func newFilter() filter.Filter {
+	rateLimitOnce.Do(func() {
+		rateLimitFilter = &Filter{}
+	})
+	return rateLimitFilter
+}
+
+// Invoke throttles the call by the rate limit of the consumer application calling the service
+func (f *Filter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	url := invoker.GetURL()
+	key := url.GetParam(constant.APPLICATION_KEY, "") + "." + url.GetParam(constant.INTERFACE_KEY, url.Service()) +
+		constant.RateLimitRuleSuffix
+	if err := f.limiter(key).acquire(ctx); err != nil {
+		logger.Warnf("[Consumer Rate Limit Filter] the call of %s#%s is throttled: %v", url.ServiceKey(),
+			invocation.MethodName(), err)
+		return &protocol.RPCResult{Err: err}
+	}
+	return invoker.Invoke(ctx, invocation)
+}
+
+// OnResponse dummy process, returns the result directly
+func (f *Filter) OnResponse(_ context.Context, result protocol.Result, _ protocol.Invoker, _ protocol.Invocation) protocol.Result {
+	return result
+}
+
+// limiter returns the limiter of the rule of @key, which listens to the rule in the config center once it's created
+func (f *Filter) limiter(key string) *limiter {
+	if l, ok := f.limiters.Load(key); ok {
+		return l.(*limiter)
+	}
+	l, loaded := f.limiters.LoadOrStore(key, newLimiter(key))
+	if !loaded {
+		l.(*limiter).subscribe()
+	}
+	return l.(*limiter)
+}
+
+// limiter limits the calls by the fixed windows of the rule
+type limiter struct {
+	key  string
+	rule atomic.Value
+
+	mu          sync.Mutex
+	windowStart time.Time
+	count       int
+}
+
+func newLimiter(key string) *limiter {
+	l := &limiter{key: key}
+	l.rule.Store((*Rule)(nil))
+	return l
+}
+
+func (l *limiter) subscribe() {
+	rootConfig := config.GetRootConfig()
+	if rootConfig.ConfigCenter == nil || rootConfig.ConfigCenter.DynamicConfiguration == nil {
+		logger.Debugf("Config center does not start, the rate limit %s is disabled", l.key)
+		return
+	}
+	dynamicConfiguration := rootConfig.ConfigCenter.DynamicConfiguration
+	dynamicConfiguration.AddListener(l.key, l, config_center.WithGroup(rootConfig.ConfigCenter.Group))
+	value, err := dynamicConfiguration.GetProperties(l.key, config_center.WithGroup(rootConfig.ConfigCenter.Group))
+	if err != nil {
+		// the rule may not be published now
+		logger.Debugf("Can not get the rate limit for key=%s, error=%v", l.key, err)
+		return
+	}
+	if err = l.setRule(value); err != nil {
+		logger.Warnf("Parse the rate limit %s failed, error=%v", l.key, err)
+	}
+}
+
+// Process updates the rule once it changes in the config center, and the throttling stops once it's removed
+func (l *limiter) Process(event *config_center.ConfigChangeEvent) {
+	value, _ := event.Value.(string)
+	if event.ConfigType == remoting.EventTypeDel {
+		value = ""
+	}
+	if err := l.setRule(value); err != nil {
+		logger.Warnf("Parse the rate limit %s failed, error=%v", l.key, err)
+	}
+}
+
+func (l *limiter) setRule(value string) error {
+	if value == "" {
+		l.rule.Store((*Rule)(nil))
+		return nil
+	}
+	rule := &Rule{}
+	if err := yaml.Unmarshal([]byte(value), rule); err != nil {
+		return perrors.WithStack(err)
+	}
+	if rule.Interval != "" {
+		if interval, err := time.ParseDuration(rule.Interval); err != nil || interval <= 0 {
+			return perrors.Errorf("the interval %s of the rate limit is invalid", rule.Interval)
+		}
+	}
+	l.rule.Store(rule)
+	return nil
+}
+
+// acquire takes a permit of the current window, and it waits for the next window in the queue mode
+func (l *limiter) acquire(ctx context.Context) error {
+	for {
+		rule := l.rule.Load().(*Rule)
+		if rule == nil || rule.Rate <= 0 {
+			return nil
+		}
+		interval := defaultInterval
+		if rule.Interval != "" {
+			interval, _ = time.ParseDuration(rule.Interval)
+		}
+
+		l.mu.Lock()
+		now := time.Now()
+		if now.Sub(l.windowStart) >= interval {
+			l.windowStart = now
+			l.count = 0
+		}
+		if l.count < rule.Rate {
+			l.count++
+			l.mu.Unlock()
+			return nil
+		}
+		next := l.windowStart.Add(interval)
+		l.mu.Unlock()
+
+		if rule.Mode != modeQueue {
+			return perrors.Errorf("the calls exceed the rate limit %d per %s of %s", rule.Rate, interval, l.key)
+		}
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return perrors.WithStack(ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
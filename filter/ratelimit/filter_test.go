@@ -0,0 +1,108 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/config"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+// listenerDynamicConfiguration records the listeners to push the rules to them
+type listenerDynamicConfiguration struct {
+	config_center.MockDynamicConfiguration
+	listeners map[string]config_center.ConfigurationListener
+}
+
+func (c *listenerDynamicConfiguration) AddListener(key string, listener config_center.ConfigurationListener, _ ...config_center.Option) {
+	c.listeners[key] = listener
+}
+
+func (c *listenerDynamicConfiguration) GetProperties(_ string, _ ...config_center.Option) (string, error) {
+	return "", nil
+}
+
+func (c *listenerDynamicConfiguration) push(key, value string) {
+	var eventType remoting.EventType = remoting.EventTypeUpdate
+	if value == "" {
+		eventType = remoting.EventTypeDel
+	}
+	c.listeners[key].Process(&config_center.ConfigChangeEvent{Key: key, Value: value, ConfigType: eventType})
+}
+
+func TestFilterInvoke(t *testing.T) {
+	dynamicConfiguration := &listenerDynamicConfiguration{listeners: map[string]config_center.ConfigurationListener{}}
+	config.SetRootConfig(config.RootConfig{
+		ConfigCenter: &config.CenterConfig{DynamicConfiguration: dynamicConfiguration},
+	})
+	defer config.SetRootConfig(*config.NewRootConfigBuilder().Build())
+
+	url, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?application=order-center" +
+		"&interface=com.ikurento.user.UserProvider")
+	assert.NoError(t, err)
+	invoker := protocol.NewBaseInvoker(url)
+	f := newFilter()
+	invoke := func(ctx context.Context) protocol.Result {
+		return f.Invoke(ctx, invoker, invocation.NewRPCInvocation("GetUser", nil, nil))
+	}
+	const key = "order-center.com.ikurento.user.UserProvider.rate-limit"
+
+	// not throttled without the rule
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, invoke(context.Background()).Error())
+	}
+	assert.Contains(t, dynamicConfiguration.listeners, key)
+
+	// the calls over the limit are rejected
+	dynamicConfiguration.push(key, "rate: 3\ninterval: 1m")
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, invoke(context.Background()).Error())
+	}
+	assert.Error(t, invoke(context.Background()).Error())
+
+	// the calls over the limit wait for the next window
+	dynamicConfiguration.push(key, "rate: 2\ninterval: 200ms\nmode: queue")
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, invoke(context.Background()).Error())
+	}
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(400*time.Millisecond))
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	invoke(context.Background())
+	assert.Error(t, invoke(ctx).Error())
+
+	// the throttling stops once the rule is removed
+	dynamicConfiguration.push(key, "")
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, invoke(context.Background()).Error())
+	}
+}
@@ -0,0 +1,151 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package singleflight
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+var (
+	singleFlightOnce   sync.Once
+	singleFlightFilter *Filter
+)
+
+func init() {
+	extension.SetFilter(constant.SingleFlightFilterKey, newFilter)
+}
+
+// Filter collapses the identical concurrent requests into one invocation on the consumer side.
+/**
+ * example:
+ * "UserProvider":
+ *   filter: "single-flight"
+ * The requests of the same method with the same arguments wait for the one in flight instead of invoking
+ * the provider again, and all of them receive its result or error. Nothing is cached, so the next request
+ * after it finishes invokes the provider again. It's meant for the read requests only, and the replies of
+ * the duplicate requests are the shallow copies of the one of the invocation in flight.
+ */
+type Filter struct {
+	lock  sync.Mutex
+	calls map[string]*call
+}
+
+type call struct {
+	done   chan struct{}
+	result protocol.Result
+	reply  interface{}
+}
+
+// newFilter returns the singleton Filter instance
+func newFilter() filter.Filter {
+	singleFlightOnce.Do(func() {
+		singleFlightFilter = &Filter{
+			calls: make(map[string]*call),
+		}
+	})
+	return singleFlightFilter
+}
+
+// Invoke waits for the identical request in flight, or invokes the provider if there isn't any
+func (f *Filter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	args, err := json.Marshal(invocation.Arguments())
+	if err != nil {
+		logger.Debugf("[Single Flight Filter] the arguments of %s aren't serializable: %v", invocation.MethodName(), err)
+		return invoker.Invoke(ctx, invocation)
+	}
+	key := fmt.Sprintf("%s#%s#%s", invoker.GetURL().ServiceKey(), invocation.MethodName(), args)
+
+	f.lock.Lock()
+	if c, ok := f.calls[key]; ok {
+		f.lock.Unlock()
+		select {
+		case <-c.done:
+		case <-ctx.Done():
+			return &protocol.RPCResult{Err: ctx.Err()}
+		}
+		logger.Debugf("[Single Flight Filter] share the result of the request in flight %s", key)
+		return share(c, invocation)
+	}
+	c := &call{done: make(chan struct{}), reply: invocation.Reply()}
+	f.calls[key] = c
+	f.lock.Unlock()
+
+	return f.execute(ctx, invoker, invocation, key, c)
+}
+
+// execute invokes the provider for the request and wakes up the duplicate ones waiting for it
+func (f *Filter) execute(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation,
+	key string, c *call) protocol.Result {
+	defer func() {
+		e := recover()
+		if e != nil {
+			c.result = &protocol.RPCResult{Err: perrors.Errorf("the invocation of %s panics: %v", key, e)}
+		}
+		f.lock.Lock()
+		delete(f.calls, key)
+		f.lock.Unlock()
+		close(c.done)
+		if e != nil {
+			panic(e)
+		}
+	}()
+	c.result = invoker.Invoke(ctx, invocation)
+	return c.result
+}
+
+// share returns the result of the call to the duplicate request, with the reply copied into its own one
+func share(c *call, invocation protocol.Invocation) protocol.Result {
+	result := &protocol.RPCResult{Err: c.result.Error(), Rest: c.result.Result()}
+	if attachments := c.result.Attachments(); attachments != nil {
+		result.Attrs = make(map[string]interface{}, len(attachments))
+		for k, v := range attachments {
+			result.Attrs[k] = v
+		}
+	}
+	reply := invocation.Reply()
+	if reply == nil || c.reply == nil || result.Rest != c.reply {
+		return result
+	}
+	from, to := reflect.ValueOf(c.reply), reflect.ValueOf(reply)
+	if from.Kind() == reflect.Ptr && to.Kind() == reflect.Ptr && from.Type() == to.Type() && !from.IsNil() && !to.IsNil() {
+		to.Elem().Set(from.Elem())
+		result.Rest = reply
+	}
+	return result
+}
+
+// OnResponse dummy process, returns the result directly
+func (f *Filter) OnResponse(_ context.Context, result protocol.Result, _ protocol.Invoker, _ protocol.Invocation) protocol.Result {
+	return result
+}
@@ -0,0 +1,115 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package singleflight
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+type user struct {
+	ID   string
+	Name string
+}
+
+type slowInvoker struct {
+	protocol.BaseInvoker
+	count int32
+}
+
+func (s *slowInvoker) Invoke(_ context.Context, inv protocol.Invocation) protocol.Result {
+	atomic.AddInt32(&s.count, 1)
+	time.Sleep(50 * time.Millisecond)
+	id := inv.Arguments()[0].(string)
+	if id == "missing" {
+		return &protocol.RPCResult{Err: perrors.New("user not found")}
+	}
+	reply := inv.Reply().(*user)
+	reply.ID, reply.Name = id, "name-"+id
+	return &protocol.RPCResult{Rest: reply, Attrs: map[string]interface{}{"server": "provider-1"}}
+}
+
+func newInvocation(id string, reply *user) protocol.Invocation {
+	return invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUser"),
+		invocation.WithArguments([]interface{}{id}), invocation.WithReply(reply))
+}
+
+// invokeConcurrently fires @n identical requests at the same time
+func invokeConcurrently(n int, invoker protocol.Invoker, id string) ([]protocol.Result, []*user) {
+	var wg sync.WaitGroup
+	results := make([]protocol.Result, n)
+	replies := make([]*user, n)
+	for i := 0; i < n; i++ {
+		replies[i] = &user{}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = newFilter().Invoke(context.Background(), invoker, newInvocation(id, replies[i]))
+		}(i)
+	}
+	wg.Wait()
+	return results, replies
+}
+
+func TestFilterInvoke(t *testing.T) {
+	url, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider")
+	assert.NoError(t, err)
+	invoker := &slowInvoker{BaseInvoker: *protocol.NewBaseInvoker(url)}
+
+	// the identical concurrent requests invoke the provider once
+	results, replies := invokeConcurrently(10, invoker, "1")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&invoker.count))
+	for i, result := range results {
+		assert.NoError(t, result.Error())
+		assert.Equal(t, &user{ID: "1", Name: "name-1"}, replies[i])
+		assert.Same(t, replies[i], result.Result())
+		assert.Equal(t, "provider-1", result.Attachment("server", ""))
+	}
+
+	// nothing is cached after the request finishes
+	results, _ = invokeConcurrently(1, invoker, "1")
+	assert.NoError(t, results[0].Error())
+	assert.Equal(t, int32(2), atomic.LoadInt32(&invoker.count))
+
+	// the requests with different arguments aren't collapsed
+	results, _ = invokeConcurrently(1, invoker, "2")
+	assert.NoError(t, results[0].Error())
+	assert.Equal(t, int32(3), atomic.LoadInt32(&invoker.count))
+
+	// the error is shared as well
+	results, _ = invokeConcurrently(10, invoker, "missing")
+	assert.Equal(t, int32(4), atomic.LoadInt32(&invoker.count))
+	for _, result := range results {
+		assert.EqualError(t, result.Error(), "user not found")
+	}
+}
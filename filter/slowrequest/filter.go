@@ -0,0 +1,97 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package slowrequest
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+func init() {
+	extension.SetFilter(constant.SlowRequestFilterKey, func() filter.Filter {
+		return &Filter{}
+	})
+}
+
+// Filter flags the invocations whose duration exceeds the threshold by a warning log and a metric,
+// which is written by the metric reporter configured.
+/**
+ * example:
+ * "UserProvider":
+ *   filter: "slow-request"
+ *   params:
+ *     slow.threshold: "500"                  # in milliseconds, 1000 by default
+ *     methods.GetUser.slow.threshold: "100"  # method-level configuration overrides the service-level one
+ * It can be used on both of the provider side and the consumer side.
+ */
+type Filter struct{}
+
+// Invoke measures the duration of the invocation and reports it if it's slow
+func (f *Filter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	start := time.Now()
+	result := invoker.Invoke(ctx, invocation)
+	duration := time.Since(start)
+
+	url := invoker.GetURL()
+	methodName := invocation.MethodName()
+	threshold := getThreshold(url, methodName)
+	if threshold > 0 && duration > threshold {
+		logger.Warnf("[Slow Request] side: %s, service: %s, method: %s, duration: %s, threshold: %s, peer: %s",
+			url.GetParam(constant.SIDE_KEY, ""), url.ServiceKey(), methodName, duration, threshold, getPeer(url, invocation))
+		if callback := extension.GetSlowRequestCallback(); callback != nil {
+			callback(invoker, invocation, duration)
+		}
+	}
+	return result
+}
+
+// OnResponse dummy process, returns the result directly
+func (f *Filter) OnResponse(_ context.Context, result protocol.Result, _ protocol.Invoker, _ protocol.Invocation) protocol.Result {
+	return result
+}
+
+func getThreshold(url *common.URL, methodName string) time.Duration {
+	thresholdConfig := url.GetMethodParam(methodName, constant.SLOW_THRESHOLD_KEY,
+		url.GetParam(constant.SLOW_THRESHOLD_KEY, strconv.Itoa(constant.DEFAULT_SLOW_THRESHOLD)))
+	threshold, err := strconv.ParseInt(thresholdConfig, 10, 64)
+	if err != nil {
+		logger.Errorf("The configuration of %s is invalid: %s", constant.SLOW_THRESHOLD_KEY, thresholdConfig)
+		return time.Duration(constant.DEFAULT_SLOW_THRESHOLD) * time.Millisecond
+	}
+	return time.Duration(threshold) * time.Millisecond
+}
+
+// getPeer returns the remote address of the consumer on the provider side,
+// and the address of the provider on the consumer side
+func getPeer(url *common.URL, invocation protocol.Invocation) string {
+	if remote, ok := invocation.Attachments()[constant.REMOTE_ADDR]; ok && remote != nil {
+		return fmt.Sprint(remote)
+	}
+	return url.Location
+}
@@ -0,0 +1,98 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package slowrequest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+type slowInvoker struct {
+	protocol.BaseInvoker
+	latency time.Duration
+}
+
+func (s *slowInvoker) Invoke(context.Context, protocol.Invocation) protocol.Result {
+	time.Sleep(s.latency)
+	return &protocol.RPCResult{}
+}
+
+type recordLogger struct {
+	logger.Logger
+	warnings []string
+}
+
+func (r *recordLogger) Warnf(format string, args ...interface{}) {
+	r.warnings = append(r.warnings, fmt.Sprintf(format, args...))
+}
+
+// counterReporter records the counters increased
+type counterReporter struct {
+	metrics.MetricsReporter
+	counters map[string]float64
+}
+
+func (r *counterReporter) IncCounter(name string, value float64, tags map[string]string) {
+	r.counters[name+"/"+tags[constant.METHOD_KEY]] += value
+}
+
+func TestFilterInvoke(t *testing.T) {
+	originLogger := logger.GetLogger()
+	log := &recordLogger{Logger: originLogger}
+	logger.SetLogger(log)
+	defer logger.SetLogger(originLogger)
+
+	url, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?side=provider&slow.threshold=10&methods.GetUser.slow.threshold=1000")
+	assert.NoError(t, err)
+	invoker := &slowInvoker{BaseInvoker: *protocol.NewBaseInvoker(url), latency: 50 * time.Millisecond}
+	reporter := &counterReporter{counters: make(map[string]float64)}
+	extension.SetSlowRequestCallback(metrics.NewSlowRequestCallback(reporter))
+	defer extension.SetSlowRequestCallback(nil)
+
+	filter := &Filter{}
+	inv := invocation.NewRPCInvocation("GetUserList", []interface{}{"OK"},
+		map[string]interface{}{constant.REMOTE_ADDR: "127.0.0.1:56789"})
+	result := filter.Invoke(context.Background(), invoker, inv)
+	assert.Nil(t, result.Error())
+	assert.Equal(t, float64(1), reporter.counters[metrics.ProviderPrefix+metrics.SlowRequestsTotal+"/GetUserList"])
+	assert.Len(t, log.warnings, 1)
+	assert.Contains(t, log.warnings[0], "GetUserList")
+	assert.Contains(t, log.warnings[0], "127.0.0.1:56789")
+
+	// the method-level threshold isn't exceeded
+	result = filter.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("GetUser", []interface{}{"OK"}, nil))
+	assert.Nil(t, result.Error())
+	assert.Equal(t, float64(0), reporter.counters[metrics.ProviderPrefix+metrics.SlowRequestsTotal+"/GetUser"])
+	assert.Len(t, log.warnings, 1)
+}
@@ -0,0 +1,69 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tracelog
+
+import (
+	"context"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+func init() {
+	extension.SetFilter(constant.TraceLogFilterKey, func() filter.Filter {
+		return &Filter{}
+	})
+}
+
+// Filter carries the trace id supplied by the consumer in the logger of the request, so that the provider
+// logs written by logger.FromContext carry it. It is the head of the default service filters in order that
+// the logs of the other filters carry it as well.
+type Filter struct{}
+
+// Invoke invokes with the context carrying the logger writing the trace id of @invocation
+func (f *Filter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	if traceID := traceIDOf(invocation); traceID != "" {
+		ctx = logger.NewContext(ctx, constant.TRACE_ID_KEY, traceID)
+	}
+	return invoker.Invoke(ctx, invocation)
+}
+
+// OnResponse dummy process, returns the result directly
+func (f *Filter) OnResponse(_ context.Context, result protocol.Result, _ protocol.Invoker,
+	_ protocol.Invocation) protocol.Result {
+
+	return result
+}
+
+// traceIDOf returns the trace id attachment of @invocation, which is a list of strings in the triple protocol
+func traceIDOf(invocation protocol.Invocation) string {
+	switch traceID := invocation.Attachment(constant.TRACE_ID_KEY).(type) {
+	case string:
+		return traceID
+	case []string:
+		if len(traceID) > 0 {
+			return traceID[0]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,95 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tracelog
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/protocol/protocolwrapper"
+)
+
+// loggingInvoker logs the first argument of the invocations by the logger of the request
+type loggingInvoker struct {
+	protocol.BaseInvoker
+}
+
+func (i *loggingInvoker) Invoke(ctx context.Context, inv protocol.Invocation) protocol.Result {
+	logger.FromContext(ctx).Infof("echo %v", inv.Arguments()[0])
+	return &protocol.RPCResult{Rest: inv.Arguments()[0]}
+}
+
+func TestDefaultServiceFilters(t *testing.T) {
+	// the trace log filter heads the chain so that the logs of the other filters carry the trace id
+	assert.True(t, strings.HasPrefix(constant.DEFAULT_SERVICE_FILTERS, constant.TraceLogFilterKey+","))
+}
+
+func TestFilterTraceID(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	defaultLogger := logger.GetLogger()
+	logger.SetLogger(&logger.DubboLogger{Logger: zap.New(core).Sugar()})
+	defer logger.SetLogger(defaultLogger)
+
+	url, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.TraceProvider?interface=com.ikurento.user.TraceProvider")
+	assert.NoError(t, err)
+	url.SetParam(constant.SERVICE_FILTER_KEY, constant.TraceLogFilterKey)
+	invoker := protocolwrapper.BuildInvokerChain(&loggingInvoker{BaseInvoker: *protocol.NewBaseInvoker(url)},
+		constant.SERVICE_FILTER_KEY)
+
+	inv := invocation.NewRPCInvocation("Echo", []interface{}{"dubbo"},
+		map[string]interface{}{constant.TRACE_ID_KEY: "trace-0001"})
+	result := invoker.Invoke(context.Background(), inv)
+	assert.NoError(t, result.Error())
+	assert.Equal(t, "dubbo", result.Result())
+
+	entries := logs.FilterMessage("echo dubbo").All()
+	assert.Len(t, entries, 1)
+	assert.Equal(t, map[string]interface{}{constant.TRACE_ID_KEY: "trace-0001"}, entries[0].ContextMap())
+
+	// the trace id in the triple protocol is a list of strings
+	logs.TakeAll()
+	inv = invocation.NewRPCInvocation("Echo", []interface{}{"triple"},
+		map[string]interface{}{constant.TRACE_ID_KEY: []string{"trace-0002"}})
+	invoker.Invoke(context.Background(), inv)
+	entries = logs.FilterMessage("echo triple").All()
+	assert.Len(t, entries, 1)
+	assert.Equal(t, map[string]interface{}{constant.TRACE_ID_KEY: "trace-0002"}, entries[0].ContextMap())
+
+	// the logs without the trace id don't carry it
+	logs.TakeAll()
+	invoker.Invoke(context.Background(), invocation.NewRPCInvocation("Echo", []interface{}{"go"}, nil))
+	entries = logs.FilterMessage("echo go").All()
+	assert.Len(t, entries, 1)
+	assert.Empty(t, entries[0].ContextMap())
+}
@@ -25,29 +25,49 @@ import (
 	_ "dubbo.apache.org/dubbo-go/v3/cluster/cluster/failover"
 	_ "dubbo.apache.org/dubbo-go/v3/cluster/cluster/failsafe"
 	_ "dubbo.apache.org/dubbo-go/v3/cluster/cluster/forking"
+	_ "dubbo.apache.org/dubbo-go/v3/cluster/cluster/mergeable"
+	_ "dubbo.apache.org/dubbo-go/v3/cluster/cluster/mock"
 	_ "dubbo.apache.org/dubbo-go/v3/cluster/cluster/zoneaware"
 	_ "dubbo.apache.org/dubbo-go/v3/cluster/loadbalance/consistenthashing"
 	_ "dubbo.apache.org/dubbo-go/v3/cluster/loadbalance/leastactive"
 	_ "dubbo.apache.org/dubbo-go/v3/cluster/loadbalance/random"
 	_ "dubbo.apache.org/dubbo-go/v3/cluster/loadbalance/roundrobin"
+	_ "dubbo.apache.org/dubbo-go/v3/cluster/merger/collection"
+	_ "dubbo.apache.org/dubbo-go/v3/cluster/router/canary"
+	_ "dubbo.apache.org/dubbo-go/v3/cluster/router/color"
 	_ "dubbo.apache.org/dubbo-go/v3/cluster/router/v3router"
+	_ "dubbo.apache.org/dubbo-go/v3/cluster/router/version"
 	_ "dubbo.apache.org/dubbo-go/v3/common/proxy/proxy_factory"
 	_ "dubbo.apache.org/dubbo-go/v3/config_center/apollo"
+	_ "dubbo.apache.org/dubbo-go/v3/config_center/appconfig"
+	_ "dubbo.apache.org/dubbo-go/v3/config_center/consul"
 	_ "dubbo.apache.org/dubbo-go/v3/config_center/nacos"
 	_ "dubbo.apache.org/dubbo-go/v3/config_center/zookeeper"
 	_ "dubbo.apache.org/dubbo-go/v3/filter/accesslog"
+	_ "dubbo.apache.org/dubbo-go/v3/filter/acl"
 	_ "dubbo.apache.org/dubbo-go/v3/filter/active"
+	_ "dubbo.apache.org/dubbo-go/v3/filter/admission"
+	_ "dubbo.apache.org/dubbo-go/v3/filter/attachment"
 	_ "dubbo.apache.org/dubbo-go/v3/filter/auth"
+	_ "dubbo.apache.org/dubbo-go/v3/filter/ctxpropagation"
+	_ "dubbo.apache.org/dubbo-go/v3/filter/dedup"
 	_ "dubbo.apache.org/dubbo-go/v3/filter/echo"
+	_ "dubbo.apache.org/dubbo-go/v3/filter/encryption"
 	_ "dubbo.apache.org/dubbo-go/v3/filter/execlmt"
+	_ "dubbo.apache.org/dubbo-go/v3/filter/faultinject"
 	_ "dubbo.apache.org/dubbo-go/v3/filter/generic"
 	_ "dubbo.apache.org/dubbo-go/v3/filter/gshutdown"
 	_ "dubbo.apache.org/dubbo-go/v3/filter/hystrix"
 	_ "dubbo.apache.org/dubbo-go/v3/filter/metrics"
+	_ "dubbo.apache.org/dubbo-go/v3/filter/priority"
+	_ "dubbo.apache.org/dubbo-go/v3/filter/ratelimit"
 	_ "dubbo.apache.org/dubbo-go/v3/filter/seata"
 	_ "dubbo.apache.org/dubbo-go/v3/filter/sentinel"
+	_ "dubbo.apache.org/dubbo-go/v3/filter/singleflight"
+	_ "dubbo.apache.org/dubbo-go/v3/filter/slowrequest"
 	_ "dubbo.apache.org/dubbo-go/v3/filter/token"
 	_ "dubbo.apache.org/dubbo-go/v3/filter/tps"
+	_ "dubbo.apache.org/dubbo-go/v3/filter/tracelog"
 	_ "dubbo.apache.org/dubbo-go/v3/filter/tracing"
 	_ "dubbo.apache.org/dubbo-go/v3/metadata/mapping/metadata"
 	_ "dubbo.apache.org/dubbo-go/v3/metadata/report/etcd"
@@ -56,6 +76,7 @@ import (
 	_ "dubbo.apache.org/dubbo-go/v3/metadata/service/exporter/configurable"
 	_ "dubbo.apache.org/dubbo-go/v3/metadata/service/local"
 	_ "dubbo.apache.org/dubbo-go/v3/metadata/service/remote"
+	_ "dubbo.apache.org/dubbo-go/v3/metrics/datadog"
 	_ "dubbo.apache.org/dubbo-go/v3/metrics/prometheus"
 	_ "dubbo.apache.org/dubbo-go/v3/protocol/dubbo"
 	_ "dubbo.apache.org/dubbo-go/v3/protocol/dubbo3"
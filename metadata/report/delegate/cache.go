@@ -0,0 +1,127 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package delegate
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+)
+
+// metadataCache keeps the metadata fetched from the metadata report on the disk, so that the consumers
+// are still able to build the references with the last known metadata once the metadata report is down.
+type metadataCache struct {
+	file string
+
+	lock    sync.Mutex
+	loaded  bool
+	entries map[string]string
+	// degraded is set once the metadata is read from the cache, and it's reset once the metadata report recovers
+	degraded bool
+}
+
+func newMetadataCache(url *common.URL) *metadataCache {
+	file := url.GetParam(constant.METADATA_CACHE_FILE_KEY, "")
+	if file == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			logger.Warnf("can not find the home directory for the metadata cache, error: %v", err)
+			home = os.TempDir()
+		}
+		name := strings.NewReplacer(":", "-", "/", "-", ",", "-").Replace(url.Location)
+		file = filepath.Join(home, ".dubbo", "dubbo-metadata-"+name+".cache")
+	}
+	return &metadataCache{file: file}
+}
+
+// get returns the cached metadata of @key
+func (mc *metadataCache) get(key string) (string, bool) {
+	mc.lock.Lock()
+	defer mc.lock.Unlock()
+	mc.load()
+	mc.degraded = true
+	value, ok := mc.entries[key]
+	return value, ok
+}
+
+// put caches the metadata of @key fetched from the metadata report, and the cache file is rewritten
+// only if the metadata changes.
+func (mc *metadataCache) put(key string, value string) {
+	mc.lock.Lock()
+	defer mc.lock.Unlock()
+	mc.load()
+	if mc.degraded {
+		mc.degraded = false
+		logger.Infof("the metadata report recovers, refresh the metadata cache %s", mc.file)
+	}
+	if old, ok := mc.entries[key]; ok && old == value {
+		return
+	}
+	mc.entries[key] = value
+	if err := mc.store(); err != nil {
+		logger.Warnf("store the metadata cache %s failed, error: %v", mc.file, err)
+	}
+}
+
+func (mc *metadataCache) load() {
+	if mc.loaded {
+		return
+	}
+	mc.loaded = true
+	mc.entries = make(map[string]string)
+	content, err := ioutil.ReadFile(mc.file)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Warnf("read the metadata cache %s failed, error: %v", mc.file, err)
+		}
+		return
+	}
+	if err = json.Unmarshal(content, &mc.entries); err != nil {
+		logger.Warnf("the metadata cache %s is broken, error: %v", mc.file, err)
+		mc.entries = make(map[string]string)
+	}
+}
+
+// store writes the entries into a temporary file first and renames it, so the cache file is never half written
+func (mc *metadataCache) store() error {
+	content, err := json.Marshal(mc.entries)
+	if err != nil {
+		return perrors.WithStack(err)
+	}
+	if err = os.MkdirAll(filepath.Dir(mc.file), 0o755); err != nil {
+		return perrors.WithStack(err)
+	}
+	tmp := mc.file + ".tmp"
+	if err = ioutil.WriteFile(tmp, content, 0o644); err != nil {
+		return perrors.WithStack(err)
+	}
+	return perrors.WithStack(os.Rename(tmp, mc.file))
+}
@@ -0,0 +1,193 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package delegate
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+import (
+	gxset "github.com/dubbogo/gost/container/set"
+
+	perrors "github.com/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.uber.org/atomic"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/config/instance"
+	"dubbo.apache.org/dubbo-go/v3/metadata/identifier"
+	"dubbo.apache.org/dubbo-go/v3/metadata/report"
+	"dubbo.apache.org/dubbo-go/v3/metadata/report/factory"
+)
+
+// unstableMetadataReport serves the metadata only while it's available
+type unstableMetadataReport struct {
+	available  atomic.Bool
+	info       atomic.Value
+	definition atomic.String
+}
+
+var unstableReport = &unstableMetadataReport{}
+
+type unstableMetadataReportFactory struct{}
+
+func (unstableMetadataReportFactory) CreateMetadataReport(*common.URL) report.MetadataReport {
+	return unstableReport
+}
+
+func (mr *unstableMetadataReport) down() error {
+	if mr.available.Load() {
+		return nil
+	}
+	return perrors.New("the metadata report is unavailable")
+}
+
+func (mr *unstableMetadataReport) StoreProviderMetadata(*identifier.MetadataIdentifier, string) error {
+	return mr.down()
+}
+
+func (mr *unstableMetadataReport) StoreConsumerMetadata(*identifier.MetadataIdentifier, string) error {
+	return mr.down()
+}
+
+func (mr *unstableMetadataReport) SaveServiceMetadata(*identifier.ServiceMetadataIdentifier, *common.URL) error {
+	return mr.down()
+}
+
+func (mr *unstableMetadataReport) RemoveServiceMetadata(*identifier.ServiceMetadataIdentifier) error {
+	return mr.down()
+}
+
+func (mr *unstableMetadataReport) GetExportedURLs(*identifier.ServiceMetadataIdentifier) ([]string, error) {
+	return nil, mr.down()
+}
+
+func (mr *unstableMetadataReport) SaveSubscribedData(*identifier.SubscriberMetadataIdentifier, string) error {
+	return mr.down()
+}
+
+func (mr *unstableMetadataReport) GetSubscribedURLs(*identifier.SubscriberMetadataIdentifier) ([]string, error) {
+	return nil, mr.down()
+}
+
+func (mr *unstableMetadataReport) GetServiceDefinition(*identifier.MetadataIdentifier) (string, error) {
+	if err := mr.down(); err != nil {
+		return "", err
+	}
+	return mr.definition.Load(), nil
+}
+
+func (mr *unstableMetadataReport) GetAppMetadata(*identifier.SubscriberMetadataIdentifier) (*common.MetadataInfo, error) {
+	if err := mr.down(); err != nil {
+		return nil, err
+	}
+	return mr.info.Load().(*common.MetadataInfo), nil
+}
+
+func (mr *unstableMetadataReport) PublishAppMetadata(*identifier.SubscriberMetadataIdentifier, *common.MetadataInfo) error {
+	return mr.down()
+}
+
+func (mr *unstableMetadataReport) RegisterServiceAppMapping(string, string, string) error {
+	return mr.down()
+}
+
+func (mr *unstableMetadataReport) GetServiceAppMapping(string, string) (*gxset.HashSet, error) {
+	return gxset.NewSet(), mr.down()
+}
+
+func TestMetadataReportFallbackToCache(t *testing.T) {
+	extension.SetMetadataReportFactory("unstable", func() factory.MetadataReportFactory {
+		return unstableMetadataReportFactory{}
+	})
+	cacheFile := filepath.Join(t.TempDir(), "metadata.cache")
+	url, err := common.NewURL("unstable://127.0.0.1:2181",
+		common.WithParamsValue(constant.CYCLE_REPORT_KEY, "false"),
+		common.WithParamsValue(constant.METADATA_CACHE_FILE_KEY, cacheFile))
+	assert.NoError(t, err)
+	instance.GetMetadataReportInstance(url)
+	instance.SetMetadataReportUrl(url)
+
+	appId := &identifier.SubscriberMetadataIdentifier{
+		Revision:                          "1",
+		BaseApplicationMetadataIdentifier: identifier.BaseApplicationMetadataIdentifier{Application: "provider"},
+	}
+	serviceId := &identifier.MetadataIdentifier{
+		Application: "provider",
+		BaseMetadataIdentifier: identifier.BaseMetadataIdentifier{
+			ServiceInterface: "com.ikurento.user.UserProvider",
+			Side:             constant.PROVIDER_PROTOCOL,
+		},
+	}
+	info := common.NewMetadataInfWithApp("provider")
+	info.Revision = "1"
+	info.Services["com.ikurento.user.UserProvider:dubbo"] = common.NewServiceInfo("com.ikurento.user.UserProvider",
+		"", "", "dubbo", "", map[string]string{constant.TIMEOUT_KEY: "3000"})
+	unstableReport.info.Store(info)
+	unstableReport.definition.Store(`{"canonicalName":"com.ikurento.user.UserProvider"}`)
+	unstableReport.available.Store(true)
+
+	mr, err := NewMetadataReport()
+	assert.NoError(t, err)
+	_, err = mr.GetAppMetadata(appId)
+	assert.NoError(t, err)
+	_, err = mr.GetServiceDefinition(serviceId)
+	assert.NoError(t, err)
+
+	// the metadata report goes down, and the references are still built by the cached metadata
+	unstableReport.available.Store(false)
+	cached, err := mr.GetAppMetadata(appId)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", cached.Revision)
+	assert.Equal(t, "3000", cached.Services["com.ikurento.user.UserProvider:dubbo"].GetParams().Get(constant.TIMEOUT_KEY))
+	definition, err := mr.GetServiceDefinition(serviceId)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"canonicalName":"com.ikurento.user.UserProvider"}`, definition)
+	_, err = mr.GetAppMetadata(&identifier.SubscriberMetadataIdentifier{
+		Revision:                          "2",
+		BaseApplicationMetadataIdentifier: identifier.BaseApplicationMetadataIdentifier{Application: "provider"},
+	})
+	assert.Error(t, err)
+
+	// the cache survives the restart
+	restarted, err := NewMetadataReport()
+	assert.NoError(t, err)
+	cached, err = restarted.GetAppMetadata(appId)
+	assert.NoError(t, err)
+	assert.Equal(t, "provider", cached.App)
+
+	// the cache is refreshed once the metadata report recovers
+	unstableReport.definition.Store(`{"canonicalName":"com.ikurento.user.UserProvider","methods":[]}`)
+	unstableReport.available.Store(true)
+	definition, err = restarted.GetServiceDefinition(serviceId)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"canonicalName":"com.ikurento.user.UserProvider","methods":[]}`, definition)
+	unstableReport.available.Store(false)
+	restarted, err = NewMetadataReport()
+	assert.NoError(t, err)
+	definition, err = restarted.GetServiceDefinition(serviceId)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"canonicalName":"com.ikurento.user.UserProvider","methods":[]}`, definition)
+}
@@ -106,6 +106,9 @@ type MetadataReport struct {
 	// allMetadataReports store all the metdadata reports records in memory
 	allMetadataReports     map[*identifier.MetadataIdentifier]interface{}
 	allMetadataReportsLock sync.RWMutex
+
+	// cache keeps the fetched metadata to fall back to once the metadata report is down
+	cache *metadataCache
 }
 
 // NewMetadataReport will create a MetadataReport with initiation
@@ -120,6 +123,7 @@ func NewMetadataReport() (*MetadataReport, error) {
 		syncReport:         url.GetParamBool(constant.SYNC_REPORT_KEY, false),
 		failedReports:      make(map[*identifier.MetadataIdentifier]interface{}, 4),
 		allMetadataReports: make(map[*identifier.MetadataIdentifier]interface{}, 4),
+		cache:              newMetadataCache(url),
 	}
 
 	mrr, err := newMetadataReportRetry(
@@ -157,9 +161,27 @@ func (mr *MetadataReport) PublishAppMetadata(identifier *identifier.SubscriberMe
 }
 
 // GetAppMetadata delegate get metadata info
+// The last fetched metadata is returned with a warning once the metadata report is unavailable.
 func (mr *MetadataReport) GetAppMetadata(identifier *identifier.SubscriberMetadataIdentifier) (*common.MetadataInfo, error) {
 	report := instance.GetMetadataReportInstance()
-	return report.GetAppMetadata(identifier)
+	key := identifier.GetIdentifierKey()
+	info, err := report.GetAppMetadata(identifier)
+	if err == nil {
+		if content, e := json.Marshal(info); e == nil {
+			mr.cache.put(key, string(content))
+		}
+		return info, nil
+	}
+	content, ok := mr.cache.get(key)
+	if !ok {
+		return nil, err
+	}
+	cached := &common.MetadataInfo{}
+	if e := json.Unmarshal([]byte(content), cached); e != nil {
+		return nil, err
+	}
+	logger.Warnf("get the metadata of %s from the metadata report failed, use the cached one, error: %v", key, err)
+	return cached, nil
 }
 
 // retry will do metadata failed reports collection by call metadata report sdk
@@ -294,9 +316,21 @@ func (mr *MetadataReport) GetSubscribedURLs(identifier *identifier.SubscriberMet
 }
 
 // GetServiceDefinition will delegate to call remote metadata's sdk to get service definitions
+// The last fetched definition is returned with a warning once the metadata report is unavailable.
 func (mr *MetadataReport) GetServiceDefinition(identifier *identifier.MetadataIdentifier) (string, error) {
 	report := instance.GetMetadataReportInstance()
-	return report.GetServiceDefinition(identifier)
+	key := identifier.GetIdentifierKey()
+	serviceDefinition, err := report.GetServiceDefinition(identifier)
+	if err == nil {
+		mr.cache.put(key, serviceDefinition)
+		return serviceDefinition, nil
+	}
+	cached, ok := mr.cache.get(key)
+	if !ok {
+		return "", err
+	}
+	logger.Warnf("get the service definition of %s from the metadata report failed, use the cached one, error: %v", key, err)
+	return cached, nil
 }
 
 // doHandlerMetadataCollection will store metadata to metadata support with given metadataMap
@@ -0,0 +1,142 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package datadog
+
+import (
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+)
+
+const (
+	reporterName = "datadog"
+	// the address the DogStatsD agent listens on by default
+	defaultAddress = "127.0.0.1:8125"
+
+	counterType   = "c"
+	gaugeType     = "g"
+	histogramType = "h"
+)
+
+var (
+	reporterInstance *DatadogReporter
+	reporterInitOnce sync.Once
+	// tagReplacer replaces the characters reserved by the DogStatsD datagrams in the tags
+	tagReplacer = strings.NewReplacer(",", "_", "|", "_", "#", "_", "\n", "_")
+)
+
+func init() {
+	extension.SetMetricReporter(reporterName, newDatadogReporter)
+}
+
+// DatadogReporter sends the metrics to the DogStatsD agent of datadog by UDP, e.g. the counter
+// consumer_requests_total is sent as dubbo.consumer_requests_total:1|c|#method:GetUser,service:UserProvider.
+// The metrics are dropped silently if the agent is unreachable.
+type DatadogReporter struct {
+	namespace string
+	conn      net.Conn
+}
+
+// newDatadogReporter creates the reporter sending the metrics to the agent at the address of @reporterConfig
+func newDatadogReporter(reporterConfig *metrics.ReporterConfig) metrics.MetricsReporter {
+	reporterInitOnce.Do(func() {
+		address := reporterConfig.Address
+		if address == "" {
+			address = defaultAddress
+		}
+		reporter := &DatadogReporter{namespace: reporterConfig.Namespace}
+		conn, err := net.Dial("udp", address)
+		if err != nil {
+			logger.Errorf("new datadog reporter for the agent %s with error = %s", address, err)
+		}
+		reporter.conn = conn
+		reporterInstance = reporter
+		extension.SetRetrySuccessCallback(metrics.NewRetrySuccessCallback(reporterInstance))
+		extension.SetMirrorCallback(metrics.NewMirrorCallback(reporterInstance))
+		extension.SetAdmissionQueueCallback(metrics.NewAdmissionQueueCallback(reporterInstance))
+		extension.SetSlowRequestCallback(metrics.NewSlowRequestCallback(reporterInstance))
+		metrics.SetHealthReporter(metrics.NewHealthReporter(reporterInstance))
+	})
+	return reporterInstance
+}
+
+// IncCounter sends @value of the counter @name with @tags
+func (reporter *DatadogReporter) IncCounter(name string, value float64, tags map[string]string) {
+	reporter.send(name, value, counterType, tags)
+}
+
+// SetGauge sends @value of the gauge @name with @tags
+func (reporter *DatadogReporter) SetGauge(name string, value float64, tags map[string]string) {
+	reporter.send(name, value, gaugeType, tags)
+}
+
+// ObserveHistogram sends @value of the histogram @name with @tags
+func (reporter *DatadogReporter) ObserveHistogram(name string, value float64, tags map[string]string) {
+	reporter.send(name, value, histogramType, tags)
+}
+
+func (reporter *DatadogReporter) send(name string, value float64, metricType string, tags map[string]string) {
+	if reporter.conn == nil {
+		return
+	}
+	if _, err := reporter.conn.Write([]byte(reporter.format(name, value, metricType, tags))); err != nil {
+		logger.Debugf("send the metric %s to datadog with error = %s", name, err)
+	}
+}
+
+// format formats the metric as the DogStatsD datagram, the tags with empty values are omitted
+// and the others are sorted by their keys
+func (reporter *DatadogReporter) format(name string, value float64, metricType string, tags map[string]string) string {
+	var builder strings.Builder
+	if reporter.namespace != "" {
+		builder.WriteString(reporter.namespace)
+		builder.WriteString(".")
+	}
+	builder.WriteString(name)
+	builder.WriteString(":")
+	builder.WriteString(strconv.FormatFloat(value, 'f', -1, 64))
+	builder.WriteString("|")
+	builder.WriteString(metricType)
+
+	keys := make([]string, 0, len(tags))
+	for k, v := range tags {
+		if v != "" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for i, k := range keys {
+		if i == 0 {
+			builder.WriteString("|#")
+		} else {
+			builder.WriteString(",")
+		}
+		builder.WriteString(tagReplacer.Replace(k))
+		builder.WriteString(":")
+		builder.WriteString(tagReplacer.Replace(tags[k]))
+	}
+	return builder.String()
+}
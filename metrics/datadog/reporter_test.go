@@ -0,0 +1,61 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package datadog
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+)
+
+func TestDatadogReporter(t *testing.T) {
+	agent, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer agent.Close()
+
+	reporterConfig := metrics.NewReporterConfig()
+	reporterConfig.Address = agent.LocalAddr().String()
+	reporter := extension.GetMetricReporter(reporterName, reporterConfig)
+
+	tags := map[string]string{"service": "com.ikurento.user.UserProvider", "method": "GetUser", "group": ""}
+	reporter.IncCounter("consumer_requests_total", 1, tags)
+	reporter.SetGauge("consumer_service_rt", 12.5, tags)
+	reporter.ObserveHistogram("consumer_service_rt_histogram", 12.5, map[string]string{"method": "a,b|c"})
+
+	expected := []string{
+		"dubbo.consumer_requests_total:1|c|#method:GetUser,service:com.ikurento.user.UserProvider",
+		"dubbo.consumer_service_rt:12.5|g|#method:GetUser,service:com.ikurento.user.UserProvider",
+		"dubbo.consumer_service_rt_histogram:12.5|h|#method:a_b_c",
+	}
+	buf := make([]byte, 1024)
+	for _, datagram := range expected {
+		assert.NoError(t, agent.SetReadDeadline(time.Now().Add(time.Second)))
+		n, _, err := agent.ReadFrom(buf)
+		assert.NoError(t, err)
+		assert.Equal(t, datagram, string(buf[:n]))
+	}
+}
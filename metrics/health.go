@@ -0,0 +1,164 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metrics
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+)
+
+// the components whose connections are reported by the health metrics
+const (
+	// ConfigCenterComponent is the component of the config centers
+	ConfigCenterComponent = "config_center"
+	// RegistryComponent is the component of the registries
+	RegistryComponent = "registry"
+)
+
+// the names of the health metrics of the config centers and the registries
+const (
+	// ConfigCenterConnected is the gauge of the connected state of the config center, 1 if it's connected or 0
+	ConfigCenterConnected = "config_center_connected"
+	// ConfigCenterLastPollTimestamp is the gauge of the unix time in seconds of the latest successful poll
+	ConfigCenterLastPollTimestamp = "config_center_last_poll_timestamp_seconds"
+	// RegistryConnected is the gauge of the connected state of the registry, 1 if it's connected or 0
+	RegistryConnected = "registry_connected"
+	// RegistryLastNotifyTimestamp is the gauge of the unix time in seconds of the latest notify of the registry
+	RegistryLastNotifyTimestamp = "registry_last_notify_timestamp_seconds"
+)
+
+// AddressTag is the tag of the address of the config center or the registry of the health metrics
+const AddressTag = "address"
+
+// HealthReporter records the health of the connections to the config centers and the registries
+type HealthReporter interface {
+	// ReportConnected records the connected state of the @component at @address
+	ReportConnected(component, address string, connected bool)
+	// ReportActivity records the time of the latest successful poll of the config center
+	// or notify of the registry at @address
+	ReportActivity(component, address string, at time.Time)
+}
+
+// the health reporter is held here rather than by the extension package since the config centers
+// and the registries reporting the health can't depend on it
+var (
+	healthReporterLock sync.RWMutex
+	healthReporter     HealthReporter
+)
+
+// SetHealthReporter sets the reporter of the health of the connections, and nil removes it.
+// The metric reporters set it when they are created.
+func SetHealthReporter(reporter HealthReporter) {
+	healthReporterLock.Lock()
+	defer healthReporterLock.Unlock()
+	healthReporter = reporter
+}
+
+// GetHealthReporter returns the reporter of the health of the connections, which is nil if there isn't any
+func GetHealthReporter() HealthReporter {
+	healthReporterLock.RLock()
+	defer healthReporterLock.RUnlock()
+	return healthReporter
+}
+
+// NewHealthReporter returns the health reporter writing the gauges by @reporter
+func NewHealthReporter(reporter MetricsReporter) HealthReporter {
+	return &gaugeHealthReporter{reporter: reporter}
+}
+
+type gaugeHealthReporter struct {
+	reporter MetricsReporter
+}
+
+func (r *gaugeHealthReporter) ReportConnected(component, address string, connected bool) {
+	name := ConfigCenterConnected
+	if component == RegistryComponent {
+		name = RegistryConnected
+	}
+	value := 0.0
+	if connected {
+		value = 1
+	}
+	r.reporter.SetGauge(name, value, map[string]string{AddressTag: address})
+}
+
+func (r *gaugeHealthReporter) ReportActivity(component, address string, at time.Time) {
+	name := ConfigCenterLastPollTimestamp
+	if component == RegistryComponent {
+		name = RegistryLastNotifyTimestamp
+	}
+	r.reporter.SetGauge(name, float64(at.Unix()), map[string]string{AddressTag: address})
+}
+
+// the connected states of ConnectionHealth
+const (
+	stateUnknown int32 = iota
+	stateDisconnected
+	stateConnected
+)
+
+// ConnectionHealth tracks the connection to a config center or a registry, and reports its connected state
+// on the transitions only
+//
+// The zookeeper and etcd config centers and registries report by their facades, and the consul config center
+// and the nacos ones by the results of their requests. Apollo reports only by its refreshes and the notified changes since its
+// client hides the failures of the polls, and the file and appconfig config centers hold no connection to report.
+type ConnectionHealth struct {
+	component string
+	address   string
+	state     int32
+}
+
+// NewConnectionHealth returns the health of the connection of @component to @url
+func NewConnectionHealth(component string, url *common.URL) *ConnectionHealth {
+	return &ConnectionHealth{component: component, address: url.Location}
+}
+
+// SetConnected records the connected state, which is reported once it changes
+func (h *ConnectionHealth) SetConnected(connected bool) {
+	if h == nil {
+		return
+	}
+	state := stateDisconnected
+	if connected {
+		state = stateConnected
+	}
+	reporter := GetHealthReporter()
+	// the state stays unknown until it's reported
+	if reporter == nil || atomic.SwapInt32(&h.state, state) == state {
+		return
+	}
+	reporter.ReportConnected(h.component, h.address, connected)
+}
+
+// Active records the successful poll of the config center or the notify of the registry,
+// which proves the connection is connected as well
+func (h *ConnectionHealth) Active() {
+	if h == nil {
+		return
+	}
+	h.SetConnected(true)
+	if reporter := GetHealthReporter(); reporter != nil {
+		reporter.ReportActivity(h.component, h.address, time.Now())
+	}
+}
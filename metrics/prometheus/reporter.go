@@ -18,12 +18,8 @@
 package prometheus
 
 import (
-	"context"
 	"net/http"
-	"strconv"
-	"strings"
 	"sync"
-	"time"
 )
 
 import (
@@ -34,34 +30,16 @@ import (
 )
 
 import (
-	"dubbo.apache.org/dubbo-go/v3/common"
-	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
 	"dubbo.apache.org/dubbo-go/v3/common/logger"
 	"dubbo.apache.org/dubbo-go/v3/metrics"
-	"dubbo.apache.org/dubbo-go/v3/protocol"
 )
 
 const (
 	reporterName = "prometheus"
-	serviceKey   = constant.SERVICE_KEY
-	groupKey     = constant.GROUP_KEY
-	versionKey   = constant.VERSION_KEY
-	methodKey    = constant.METHOD_KEY
-	timeoutKey   = constant.TIMEOUT_KEY
-
-	// to identify side
-	providerPrefix = "provider_"
-	consumerPrefix = "consumer_"
-
-	// to identify the metric's type
-	rtSuffix = "_rt"
-	// to identify the metric's type
-	tpsSuffix = "_tps"
 )
 
 var (
-	labelNames             = []string{serviceKey, groupKey, versionKey, methodKey, timeoutKey}
 	reporterInstance       *PrometheusReporter
 	reporterInitOnce       sync.Once
 	defaultHistogramBucket = []float64{10, 50, 100, 200, 500, 1000, 10000}
@@ -77,51 +55,44 @@ func init() {
 // if you want to use this feature, you need to initialize your prometheus.
 // https://prometheus.io/docs/guides/go-application/
 type PrometheusReporter struct {
-	// report the consumer-side's rt gauge data
-	consumerRTGaugeVec *prometheus.GaugeVec
-	// report the provider-side's rt gauge data
-	providerRTGaugeVec *prometheus.GaugeVec
-	// todo tps support
-	// report the consumer-side's tps gauge data
-	consumerTPSGaugeVec *prometheus.GaugeVec
-	// report the provider-side's tps gauge data
-	providerTPSGaugeVec *prometheus.GaugeVec
-
-	userGauge      sync.Map
-	userSummary    sync.Map
-	userCounter    sync.Map
-	userCounterVec sync.Map
-	userGaugeVec   sync.Map
-	userSummaryVec sync.Map
+	userGauge        sync.Map
+	userSummary      sync.Map
+	userCounter      sync.Map
+	userCounterVec   sync.Map
+	userGaugeVec     sync.Map
+	userSummaryVec   sync.Map
+	userHistogramVec sync.Map
 
 	namespace string
 }
 
-// Report reports the duration to Prometheus
-// the role in url must be consumer or provider
-// or it will be ignored
-func (reporter *PrometheusReporter) Report(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation, cost time.Duration, res protocol.Result) {
-	url := invoker.GetURL()
-	var rtVec *prometheus.GaugeVec
-	if isProvider(url) {
-		rtVec = reporter.providerRTGaugeVec
-	} else if isConsumer(url) {
-		rtVec = reporter.consumerRTGaugeVec
-	} else {
-		logger.Warnf("The url belongs neither the consumer nor the provider, "+
-			"so the invocation will be ignored. url: %s", url.String())
-		return
+// IncCounter increases the counter vec @name with the labels @tags by @value
+func (reporter *PrometheusReporter) IncCounter(name string, value float64, tags map[string]string) {
+	reporter.addCounter(name, value, tags)
+}
+
+// SetGauge sets the gauge vec @name with the labels @tags to @value
+func (reporter *PrometheusReporter) SetGauge(name string, value float64, tags map[string]string) {
+	reporter.setGauge(name, value, tags)
+}
+
+// ObserveHistogram observes @value in the histogram vec @name with the labels @tags
+func (reporter *PrometheusReporter) ObserveHistogram(name string, value float64, tags map[string]string) {
+	val, exist := reporter.userHistogramVec.Load(name)
+	if !exist {
+		newHistogramVec := newHistogramVec(name, reporter.namespace, labelKeys(tags))
+		_ = prom.DefaultRegisterer.Register(newHistogramVec)
+		val, _ = reporter.userHistogramVec.LoadOrStore(name, newHistogramVec)
 	}
+	val.(*prometheus.HistogramVec).With(tags).Observe(value)
+}
 
-	labels := prometheus.Labels{
-		serviceKey: url.Service(),
-		groupKey:   url.GetParam(groupKey, ""),
-		versionKey: url.GetParam(constant.APP_VERSION_KEY, ""),
-		methodKey:  invocation.MethodName(),
-		timeoutKey: url.GetParam(timeoutKey, ""),
+func labelKeys(labelMap prometheus.Labels) []string {
+	keyList := make([]string, 0, len(labelMap))
+	for k := range labelMap {
+		keyList = append(keyList, k)
 	}
-	costMs := cost.Nanoseconds()
-	rtVec.With(labels).Set(float64(costMs))
+	return keyList
 }
 
 func newHistogramVec(name, namespace string, labels []string) *prometheus.HistogramVec {
@@ -194,30 +165,19 @@ func newSummaryVec(name, namespace string, labels []string) *prometheus.SummaryV
 	)
 }
 
-// isProvider shows whether this url represents the application received the request as server
-func isProvider(url *common.URL) bool {
-	role := url.GetParam(constant.ROLE_KEY, "")
-	return strings.EqualFold(role, strconv.Itoa(common.PROVIDER))
-}
-
-// isConsumer shows whether this url represents the application sent then request as client
-func isConsumer(url *common.URL) bool {
-	role := url.GetParam(constant.ROLE_KEY, "")
-	return strings.EqualFold(role, strconv.Itoa(common.CONSUMER))
-}
-
 // newPrometheusReporter create new prometheusReporter
 // it will register the metrics into prometheus
-func newPrometheusReporter(reporterConfig *metrics.ReporterConfig) metrics.Reporter {
+func newPrometheusReporter(reporterConfig *metrics.ReporterConfig) metrics.MetricsReporter {
 	if reporterInstance == nil {
 		reporterInitOnce.Do(func() {
 			reporterInstance = &PrometheusReporter{
-				namespace:          reporterConfig.Namespace,
-				consumerRTGaugeVec: newGaugeVec(consumerPrefix+serviceKey+rtSuffix, reporterConfig.Namespace, labelNames),
-				providerRTGaugeVec: newGaugeVec(providerPrefix+serviceKey+rtSuffix, reporterConfig.Namespace, labelNames),
+				namespace: reporterConfig.Namespace,
 			}
-
-			prom.DefaultRegisterer.MustRegister(reporterInstance.consumerRTGaugeVec, reporterInstance.providerRTGaugeVec)
+			extension.SetRetrySuccessCallback(metrics.NewRetrySuccessCallback(reporterInstance))
+			extension.SetMirrorCallback(metrics.NewMirrorCallback(reporterInstance))
+			extension.SetAdmissionQueueCallback(metrics.NewAdmissionQueueCallback(reporterInstance))
+			extension.SetSlowRequestCallback(metrics.NewSlowRequestCallback(reporterInstance))
+			metrics.SetHealthReporter(metrics.NewHealthReporter(reporterInstance))
 			metricsExporter, err := ocprom.NewExporter(ocprom.Options{
 				Registry: prom.DefaultRegisterer.(*prom.Registry),
 			})
@@ -278,31 +238,33 @@ func (reporter *PrometheusReporter) setGauge(gaugeName string, toSetValue float6
 // incCounter inc counter to inc if label is not empty, set counter vec
 // if target counter/counterVec not exist, just create new counter and inc the value
 func (reporter *PrometheusReporter) incCounter(counterName string, labelMap prometheus.Labels) {
+	reporter.addCounter(counterName, 1, labelMap)
+}
+
+// addCounter adds @value to the counter, and the counter vec if label is not empty
+// if target counter/counterVec not exist, just create new counter and add the value
+func (reporter *PrometheusReporter) addCounter(counterName string, value float64, labelMap prometheus.Labels) {
 	if len(labelMap) == 0 {
 		// counter
 		if val, exist := reporter.userCounter.Load(counterName); !exist {
 			newCounter := newCounter(counterName, reporter.namespace)
 			_ = prom.DefaultRegisterer.Register(newCounter)
 			reporter.userCounter.Store(counterName, newCounter)
-			newCounter.Inc()
+			newCounter.Add(value)
 		} else {
-			val.(prometheus.Counter).Inc()
+			val.(prometheus.Counter).Add(value)
 		}
 		return
 	}
 
-	// counter vec inc
+	// counter vec add
 	if val, exist := reporter.userCounterVec.Load(counterName); !exist {
-		keyList := make([]string, 0)
-		for k, _ := range labelMap {
-			keyList = append(keyList, k)
-		}
-		newCounterVec := newCounterVec(counterName, reporter.namespace, keyList)
+		newCounterVec := newCounterVec(counterName, reporter.namespace, labelKeys(labelMap))
 		_ = prom.DefaultRegisterer.Register(newCounterVec)
 		reporter.userCounterVec.Store(counterName, newCounterVec)
-		newCounterVec.With(labelMap).Inc()
+		newCounterVec.With(labelMap).Add(value)
 	} else {
-		val.(*prometheus.CounterVec).With(labelMap).Inc()
+		val.(*prometheus.CounterVec).With(labelMap).Add(value)
 	}
 }
 
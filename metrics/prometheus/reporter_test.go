@@ -18,57 +18,51 @@
 package prometheus
 
 import (
-	"context"
 	"testing"
-	"time"
 )
 
 import (
+	prom "github.com/prometheus/client_golang/prometheus"
+
 	"github.com/stretchr/testify/assert"
 )
 
 import (
-	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
 	"dubbo.apache.org/dubbo-go/v3/metrics"
-	"dubbo.apache.org/dubbo-go/v3/protocol"
-	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
 )
 
-func TestPrometheusReporter_Report(t *testing.T) {
+func TestPrometheusReporter(t *testing.T) {
 	reporter := extension.GetMetricReporter(reporterName, metrics.NewReporterConfig())
-	url, _ := common.NewURL(
-		"dubbo://:20000/UserProvider?app.version=0.0.1&application=BDTService&bean.name=UserProvider" +
-			"&cluster=failover&environment=dev&group=&interface=com.ikurento.user.UserProvider&loadbalance=random&methods.GetUser." +
-			"loadbalance=random&methods.GetUser.retries=1&methods.GetUser.weight=0&module=dubbogo+user-info+server&name=" +
-			"BDTService&organization=ikurento.com&owner=ZX&registry.role=3&retries=&" +
-			"service.filter=echo%2Ctoken%2Caccesslog&timestamp=1569153406&token=934804bf-b007-4174-94eb-96e3e1d60cc7&version=&warmup=100")
-	invoker := protocol.NewBaseInvoker(url)
-
-	attach := make(map[string]interface{}, 10)
-	inv := invocation.NewRPCInvocation("MethodName", []interface{}{"OK", "Hello"}, attach)
-
-	assert.False(t, isConsumer(url))
-	ctx := context.Background()
-	reporter.Report(ctx, invoker, inv, 100*time.Millisecond, nil)
-
-	// consumer side
-	url, _ = common.NewURL(
-		"dubbo://:20000/UserProvider?app.version=0.0.1&application=BDTService&bean.name=UserProvider" +
-			"&cluster=failover&environment=dev&group=&interface=com.ikurento.user.UserProvider&loadbalance=random&methods.GetUser." +
-			"loadbalance=random&methods.GetUser.retries=1&methods.GetUser.weight=0&module=dubbogo+user-info+server&name=" +
-			"BDTService&organization=ikurento.com&owner=ZX&registry.role=0&retries=&" +
-			"service.filter=echo%2Ctoken%2Caccesslog&timestamp=1569153406&token=934804bf-b007-4174-94eb-96e3e1d60cc7&version=&warmup=100")
-	invoker = protocol.NewBaseInvoker(url)
-	reporter.Report(ctx, invoker, inv, 100*time.Millisecond, nil)
+	tags := map[string]string{"service": "com.ikurento.user.UserProvider", "method": "GetUser"}
+	reporter.IncCounter("consumer_requests_total", 2, tags)
+	reporter.IncCounter("consumer_requests_total", 1, tags)
+	reporter.SetGauge("consumer_service_rt", 100, tags)
+	reporter.ObserveHistogram("consumer_service_rt_histogram", 100, tags)
+	reporter.ObserveHistogram("consumer_service_rt_histogram", 20, tags)
 
-	// invalid role
-	url, _ = common.NewURL(
-		"dubbo://:20000/UserProvider?app.version=0.0.1&application=BDTService&bean.name=UserProvider" +
-			"&cluster=failover&environment=dev&group=&interface=com.ikurento.user.UserProvider&loadbalance=random&methods.GetUser." +
-			"loadbalance=random&methods.GetUser.retries=1&methods.GetUser.weight=0&module=dubbogo+user-info+server&name=" +
-			"BDTService&organization=ikurento.com&owner=ZX&registry.role=9&retries=&" +
-			"service.filter=echo%2Ctoken%2Caccesslog&timestamp=1569153406&token=934804bf-b007-4174-94eb-96e3e1d60cc7&version=&warmup=100")
-	invoker = protocol.NewBaseInvoker(url)
-	reporter.Report(ctx, invoker, inv, 100*time.Millisecond, nil)
+	families, err := prom.DefaultGatherer.Gather()
+	assert.NoError(t, err)
+	values := make(map[string]float64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if assert.ObjectsAreEqual(tags, labels) {
+				switch {
+				case metric.GetCounter() != nil:
+					values[family.GetName()] = metric.GetCounter().GetValue()
+				case metric.GetGauge() != nil:
+					values[family.GetName()] = metric.GetGauge().GetValue()
+				case metric.GetHistogram() != nil:
+					values[family.GetName()] = float64(metric.GetHistogram().GetSampleCount())
+				}
+			}
+		}
+	}
+	assert.Equal(t, float64(3), values["dubbo_consumer_requests_total"])
+	assert.Equal(t, float64(100), values["dubbo_consumer_service_rt"])
+	assert.Equal(t, float64(2), values["dubbo_consumer_service_rt_histogram"])
 }
@@ -18,11 +18,11 @@
 package metrics
 
 import (
-	"context"
 	"time"
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 )
 
@@ -33,6 +33,10 @@ type ReporterConfig struct {
 	Port               string
 	Path               string
 	PushGatewayAddress string
+	// Protocol is the name of the reporter writing the metrics, e.g. prometheus or datadog
+	Protocol string
+	// Address is the address of the agent collecting the metrics pushed by the reporter, e.g. the DogStatsD one
+	Address string
 }
 
 type ReportMode string
@@ -50,12 +54,115 @@ func NewReporterConfig() *ReporterConfig {
 		Path:               "/metrics",
 		Mode:               ReportModePull,
 		PushGatewayAddress: "",
+		Protocol:           DefaultReporterProtocol,
 	}
 }
 
-// Reporter will be used to report the invocation's duration
-type Reporter interface {
-	// report the duration of an invocation
-	Report(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation,
-		cost time.Duration, res protocol.Result)
+// DefaultReporterProtocol is the name of the reporter used if none is configured
+const DefaultReporterProtocol = "prometheus"
+
+// the names of the metrics written by dubbo-go
+const (
+	// ConsumerPrefix prefixes the names of the metrics of the consumer side
+	ConsumerPrefix = "consumer_"
+	// ProviderPrefix prefixes the names of the metrics of the provider side
+	ProviderPrefix = "provider_"
+
+	// RequestsTotal counts the invocations
+	RequestsTotal = "requests_total"
+	// RequestsFailedTotal counts the invocations returning errors
+	RequestsFailedTotal = "requests_failed_total"
+	// ServiceRT is the gauge of the duration in nanoseconds of the latest invocation
+	ServiceRT = "service_rt"
+	// ServiceRTHistogram is the histogram of the durations in milliseconds of the invocations
+	ServiceRTHistogram = "service_rt_histogram"
+	// RetriedSuccess counts the invocations succeeding after retries
+	RetriedSuccess = "retried_success"
+	// MirroredTotal counts the invocations copied to the shadow providers
+	MirroredTotal = "mirrored_total"
+	// MirroredFailedTotal counts the invocations copied to the shadow providers returning errors
+	MirroredFailedTotal = "mirrored_failed_total"
+	// SlowRequestsTotal counts the invocations whose duration exceeds the slow threshold
+	SlowRequestsTotal = "slow_requests_total"
+	// AdmissionQueueDepth is the gauge of the number of the requests waiting in the admission queue of the service
+	AdmissionQueueDepth = "admission_queue_depth"
+)
+
+// MetricsReporter writes the metrics to the monitoring system, and the tags of a metric are attached
+// as its labels, e.g. the service and the method of the invocations.
+// The metrics with the same name are always written with the same tag keys.
+type MetricsReporter interface {
+	// IncCounter increases the counter @name with @tags by @value
+	IncCounter(name string, value float64, tags map[string]string)
+	// SetGauge sets the gauge @name with @tags to @value
+	SetGauge(name string, value float64, tags map[string]string)
+	// ObserveHistogram records @value into the histogram @name with @tags
+	ObserveHistogram(name string, value float64, tags map[string]string)
+}
+
+// NewInvocationTags returns the tags identifying the method of @invocation on @invoker
+func NewInvocationTags(invoker protocol.Invoker, invocation protocol.Invocation) map[string]string {
+	url := invoker.GetURL()
+	return map[string]string{
+		constant.SERVICE_KEY: url.Service(),
+		constant.GROUP_KEY:   url.GetParam(constant.GROUP_KEY, ""),
+		constant.VERSION_KEY: url.GetParam(constant.APP_VERSION_KEY, ""),
+		constant.METHOD_KEY:  invocation.MethodName(),
+	}
+}
+
+// RetrySuccessCallback is notified with the invocation which succeeds on the provider @invoker
+// after @attempts attempts of the failover cluster.
+type RetrySuccessCallback func(invoker protocol.Invoker, invocation protocol.Invocation, attempts int)
+
+// NewRetrySuccessCallback returns the callback counting the invocations succeeding after retries by @reporter
+func NewRetrySuccessCallback(reporter MetricsReporter) RetrySuccessCallback {
+	return func(invoker protocol.Invoker, invocation protocol.Invocation, _ int) {
+		reporter.IncCounter(ConsumerPrefix+RetriedSuccess, 1, NewInvocationTags(invoker, invocation))
+	}
+}
+
+// MirrorCallback is notified with the copy of the invocation done by the shadow providers of @invoker,
+// and @err is the error returned by them.
+type MirrorCallback func(invoker protocol.Invoker, invocation protocol.Invocation, err error)
+
+// NewMirrorCallback returns the callback counting the invocations copied to the shadow providers by @reporter
+func NewMirrorCallback(reporter MetricsReporter) MirrorCallback {
+	return func(invoker protocol.Invoker, invocation protocol.Invocation, err error) {
+		tags := NewInvocationTags(invoker, invocation)
+		reporter.IncCounter(ConsumerPrefix+MirroredTotal, 1, tags)
+		if err != nil {
+			reporter.IncCounter(ConsumerPrefix+MirroredFailedTotal, 1, tags)
+		}
+	}
+}
+
+// SlowRequestCallback is notified with the invocation on @invoker whose @duration exceeds the slow threshold
+type SlowRequestCallback func(invoker protocol.Invoker, invocation protocol.Invocation, duration time.Duration)
+
+// NewSlowRequestCallback returns the callback counting the slow invocations of both sides by @reporter
+func NewSlowRequestCallback(reporter MetricsReporter) SlowRequestCallback {
+	return func(invoker protocol.Invoker, invocation protocol.Invocation, _ time.Duration) {
+		prefix := ConsumerPrefix
+		if invoker.GetURL().GetParam(constant.SIDE_KEY, "") == constant.PROVIDER_PROTOCOL {
+			prefix = ProviderPrefix
+		}
+		reporter.IncCounter(prefix+SlowRequestsTotal, 1, NewInvocationTags(invoker, invocation))
+	}
+}
+
+// AdmissionQueueCallback is notified with the number of the requests waiting in the admission queue of @invoker
+// once it changes.
+type AdmissionQueueCallback func(invoker protocol.Invoker, depth int)
+
+// NewAdmissionQueueCallback returns the callback setting the depth of the admission queues by @reporter
+func NewAdmissionQueueCallback(reporter MetricsReporter) AdmissionQueueCallback {
+	return func(invoker protocol.Invoker, depth int) {
+		url := invoker.GetURL()
+		reporter.SetGauge(ProviderPrefix+AdmissionQueueDepth, float64(depth), map[string]string{
+			constant.SERVICE_KEY: url.Service(),
+			constant.GROUP_KEY:   url.GetParam(constant.GROUP_KEY, ""),
+			constant.VERSION_KEY: url.GetParam(constant.APP_VERSION_KEY, ""),
+		})
+	}
 }
@@ -0,0 +1,117 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dubbo
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	invocation_impl "dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+// prepareAttachments decides how the structured attachments, e.g. maps and lists, of @inv are transferred.
+// They are serialized with their structure by the serializer of the body if the peer is @typed, otherwise
+// they are flattened into json strings since the older peers only accept map<string,string>.
+func prepareAttachments(inv *invocation_impl.RPCInvocation, typed bool) {
+	// tell the provider how to transfer the result attachments as well
+	inv.SetAttachments(constant.TYPED_ATTACHMENTS_KEY, strconv.FormatBool(typed))
+	if typed {
+		return
+	}
+	for k, v := range inv.Attachments() {
+		if isStructuredAttachment(v) {
+			inv.SetAttachments(k, flattenAttachment(k, v))
+		}
+	}
+}
+
+// isTypedProvider tells whether the provider of @url accepts the typed attachments, which is advertised by
+// the provider and may be turned off by the reference
+func isTypedProvider(url *common.URL) bool {
+	return url.GetParamBool(constant.TYPED_ATTACHMENTS_SUPPORTED_KEY, false) &&
+		url.GetParamBool(constant.TYPED_ATTACHMENTS_KEY, true)
+}
+
+// prepareResultAttachments flattens the structured @attachments of the result unless the consumer sending @inv
+// accepts the typed attachments
+func prepareResultAttachments(inv *invocation_impl.RPCInvocation, attachments map[string]interface{}) {
+	if inv.AttachmentsByKey(constant.TYPED_ATTACHMENTS_KEY, "false") == "true" {
+		return
+	}
+	for k, v := range attachments {
+		if isStructuredAttachment(v) {
+			attachments[k] = flattenAttachment(k, v)
+		}
+	}
+}
+
+// flattenAttachment returns the json string of the structured attachment @v of @key
+func flattenAttachment(key string, v interface{}) string {
+	flattened, err := json.Marshal(toJSONCompatible(v))
+	if err != nil {
+		logger.Warnf("Could not flatten the attachment %s into json: %v", key, err)
+		return fmt.Sprint(v)
+	}
+	return string(flattened)
+}
+
+func isStructuredAttachment(value interface{}) bool {
+	if value == nil {
+		return false
+	}
+	if _, ok := value.([]byte); ok {
+		return false
+	}
+	switch reflect.Indirect(reflect.ValueOf(value)).Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array:
+		return true
+	}
+	return false
+}
+
+// toJSONCompatible converts the maps with interface keys decoded by hessian into the ones json supports
+func toJSONCompatible(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		dest := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			dest[fmt.Sprint(key)] = toJSONCompatible(item)
+		}
+		return dest
+	case map[string]interface{}:
+		dest := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			dest[key] = toJSONCompatible(item)
+		}
+		return dest
+	case []interface{}:
+		dest := make([]interface{}, len(v))
+		for i, item := range v {
+			dest[i] = toJSONCompatible(item)
+		}
+		return dest
+	}
+	return value
+}
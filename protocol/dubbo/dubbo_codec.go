@@ -29,6 +29,7 @@ import (
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/common/logger"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
@@ -47,7 +48,30 @@ func init() {
 }
 
 // DubboCodec. It is implements remoting.Codec
-type DubboCodec struct{}
+type DubboCodec struct {
+	// maxBodyLen is the max body length of the frames of the server or the client using the codec
+	maxBodyLen int
+}
+
+// ForURL returns the codec of the server or the client of @url, whose frames are limited by its payload param
+func (c *DubboCodec) ForURL(url *common.URL) remoting.Codec {
+	return &DubboCodec{maxBodyLen: url.GetParamByIntValue(constant.PAYLOAD_KEY, 0)}
+}
+
+// newProtocolCodec returns the codec of a package encoded by the codec
+func (c *DubboCodec) newProtocolCodec() *impl.ProtocolCodec {
+	codec := impl.NewDubboCodec(nil)
+	codec.SetMaxBodyLen(c.maxBodyLen)
+	return codec
+}
+
+// newPackage returns the package decoding @data by the codec
+func (c *DubboCodec) newPackage(data *bytes.Buffer) *impl.DubboPackage {
+	pkg := impl.NewDubboPackage(data)
+	pkg.Codec.SetMaxBodyLen(c.maxBodyLen)
+	pkg.Codec.SetOptionsResolver(lookupServiceOptions)
+	return pkg
+}
 
 // encode request for transport
 func (c *DubboCodec) EncodeRequest(request *remoting.Request) (*bytes.Buffer, error) {
@@ -78,10 +102,8 @@ func (c *DubboCodec) EncodeRequest(request *remoting.Request) (*bytes.Buffer, er
 
 	header := impl.DubboHeader{}
 	serialization := invocation.AttachmentsByKey(constant.SERIALIZATION_KEY, constant.HESSIAN2_SERIALIZATION)
-	if serialization == constant.PROTOBUF_SERIALIZATION {
-		header.SerialID = constant.S_Proto
-	} else {
-		header.SerialID = constant.S_Hessian2
+	if header.SerialID, err = impl.GetSerialIdByName(serialization); err != nil {
+		return nil, perrors.WithStack(err)
 	}
 	header.ID = request.ID
 	if request.TwoWay {
@@ -95,7 +117,11 @@ func (c *DubboCodec) EncodeRequest(request *remoting.Request) (*bytes.Buffer, er
 		Service: svc,
 		Body:    impl.NewRequestPayload(invocation.Arguments(), invocation.Attachments()),
 		Err:     nil,
-		Codec:   impl.NewDubboCodec(nil),
+		Codec:   c.newProtocolCodec(),
+	}
+
+	if opts, ok := invocation.AttributeByKey(constant.CODEC_OPTIONS_KEY, nil).(*impl.Options); ok {
+		pkg.Codec.SetOptions(opts)
 	}
 
 	if err := impl.LoadSerializer(pkg); err != nil {
@@ -118,7 +144,7 @@ func (c *DubboCodec) encodeHeartbeartReqeust(request *remoting.Request) (*bytes.
 		Service: impl.Service{},
 		Body:    impl.NewRequestPayload([]interface{}{}, nil),
 		Err:     nil,
-		Codec:   impl.NewDubboCodec(nil),
+		Codec:   c.newProtocolCodec(),
 	}
 
 	if err := impl.LoadSerializer(pkg); err != nil {
@@ -133,12 +159,21 @@ func (c *DubboCodec) EncodeResponse(response *remoting.Response) (*bytes.Buffer,
 	if response.IsHeartbeat() {
 		ptype = impl.PackageHeartbeat
 	}
+	status := response.Status
+	if _, ok := response.Error.(*remoting.ServerBusyError); ok {
+		status = impl.Response_SERVER_THREADPOOL_EXHAUSTED_ERROR
+	}
+	if result, ok := response.Result.(protocol.RPCResult); ok {
+		if _, ok := result.Err.(*remoting.ServerTimeoutError); ok {
+			status = impl.Response_SERVER_TIMEOUT
+		}
+	}
 	resp := &impl.DubboPackage{
 		Header: impl.DubboHeader{
 			SerialID:       response.SerialID,
 			Type:           ptype,
 			ID:             response.ID,
-			ResponseStatus: response.Status,
+			ResponseStatus: status,
 		},
 	}
 	if !response.IsHeartbeat() {
@@ -149,7 +184,18 @@ func (c *DubboCodec) EncodeResponse(response *remoting.Response) (*bytes.Buffer,
 		}
 	}
 
-	codec := impl.NewDubboCodec(nil)
+	codec := c.newProtocolCodec()
+	if response.SerialID != 0 {
+		// the response is serialized by the serialization of the request
+		serializer, err := impl.GetSerializerById(response.SerialID)
+		if err != nil {
+			return nil, perrors.WithStack(err)
+		}
+		codec.SetSerializer(serializer)
+	}
+	if opts, ok := response.CodecOptions.(*impl.Options); ok {
+		codec.SetOptions(opts)
+	}
 
 	pkg, err := codec.Encode(*resp)
 	if err != nil {
@@ -184,15 +230,37 @@ func (c *DubboCodec) isRequest(data []byte) bool {
 func (c *DubboCodec) decodeRequest(data []byte) (*remoting.Request, int, error) {
 	var request *remoting.Request = nil
 	buf := bytes.NewBuffer(data)
-	pkg := impl.NewDubboPackage(buf)
+	pkg := c.newPackage(buf)
 	pkg.SetBody(make([]interface{}, 7))
 	err := pkg.Unmarshal()
+	// the decoded values don't refer to the bytes of the package
+	defer pkg.Release()
 	if err != nil {
 		originErr := perrors.Cause(err)
 		if originErr == hessian.ErrHeaderNotEnough || originErr == hessian.ErrBodyNotEnough {
 			// FIXME
 			return nil, 0, originErr
 		}
+		if mismatch, ok := originErr.(*impl.SerializationMismatchError); ok {
+			// the error is replied to the client with the local serialization instead of closing the session
+			logger.Warnf("Could not decode the request %d: %v", pkg.Header.ID, mismatch)
+			return &remoting.Request{
+				ID:       pkg.Header.ID,
+				SerialID: mismatch.LocalID,
+				TwoWay:   pkg.Header.Type&impl.PackageRequest_TwoWay != 0x00,
+				Data:     mismatch,
+			}, hessian.HEADER_LENGTH + pkg.Header.BodyLen, nil
+		}
+		if isRejectedBody(originErr) {
+			// only the request is rejected, the others on the session are kept
+			logger.Warnf("Could not decode the request %d: %v", pkg.Header.ID, err)
+			return &remoting.Request{
+				ID:       pkg.Header.ID,
+				SerialID: pkg.Header.SerialID,
+				TwoWay:   pkg.Header.Type&impl.PackageRequest_TwoWay != 0x00,
+				Data:     err,
+			}, hessian.HEADER_LENGTH + pkg.Header.BodyLen, nil
+		}
 		logger.Errorf("pkg.Unmarshal(len(@data):%d) = error:%+v", buf.Len(), err)
 
 		return request, 0, perrors.WithStack(err)
@@ -202,6 +270,8 @@ func (c *DubboCodec) decodeRequest(data []byte) (*remoting.Request, int, error)
 		SerialID: pkg.Header.SerialID,
 		TwoWay:   pkg.Header.Type&impl.PackageRequest_TwoWay != 0x00,
 		Event:    pkg.Header.Type&impl.PackageHeartbeat != 0x00,
+		// the options resolved by the service of the request
+		CodecOptions: pkg.Codec.GetOptions(),
 	}
 	if (pkg.Header.Type & impl.PackageHeartbeat) == 0x00 {
 		// convert params of request
@@ -223,6 +293,10 @@ func (c *DubboCodec) decodeRequest(data []byte) (*remoting.Request, int, error)
 		methodName = pkg.Service.Method
 		args = req[impl.ArgsKey].([]interface{})
 		attachments = req[impl.AttachmentsKey].(map[string]interface{})
+		if attachments[constant.COMPRESS_ACCEPT_KEY] != constant.GZIP_COMPRESSION {
+			// the response is compressed only to the consumer accepting it
+			request.CodecOptions = pkg.Codec.GetOptions().WithoutCompression()
+		}
 		invoc := invocation.NewRPCInvocationWithOptions(invocation.WithAttachments(attachments),
 			invocation.WithArguments(args), invocation.WithMethodName(methodName))
 		request.Data = invoc
@@ -234,14 +308,37 @@ func (c *DubboCodec) decodeRequest(data []byte) (*remoting.Request, int, error)
 // decode response
 func (c *DubboCodec) decodeResponse(data []byte) (*remoting.Response, int, error) {
 	buf := bytes.NewBuffer(data)
-	pkg := impl.NewDubboPackage(buf)
+	pkg := c.newPackage(buf)
 	err := pkg.Unmarshal()
+	// the decoded values don't refer to the bytes of the package
+	defer pkg.Release()
 	if err != nil {
 		originErr := perrors.Cause(err)
 		// if the data is very big, so the receive need much times.
 		if originErr == hessian.ErrHeaderNotEnough || originErr == hessian.ErrBodyNotEnough {
 			return nil, 0, originErr
 		}
+		if mismatch, ok := originErr.(*impl.SerializationMismatchError); ok {
+			// the pending invocation fails with the error
+			logger.Warnf("Could not decode the response %d: %v", pkg.Header.ID, mismatch)
+			return &remoting.Response{
+				ID:       pkg.Header.ID,
+				SerialID: pkg.Header.SerialID,
+				Status:   pkg.Header.ResponseStatus,
+				Error:    mismatch,
+				Result:   &protocol.RPCResult{Err: mismatch},
+			}, hessian.HEADER_LENGTH + pkg.Header.BodyLen, nil
+		}
+		if isRejectedBody(originErr) {
+			logger.Warnf("Could not decode the response %d: %v", pkg.Header.ID, err)
+			return &remoting.Response{
+				ID:       pkg.Header.ID,
+				SerialID: pkg.Header.SerialID,
+				Status:   pkg.Header.ResponseStatus,
+				Error:    err,
+				Result:   &protocol.RPCResult{Err: err},
+			}, hessian.HEADER_LENGTH + pkg.Header.BodyLen, nil
+		}
 		logger.Errorf("pkg.Unmarshal(len(@data):%d) = error:%+v", buf.Len(), err)
 
 		return nil, 0, perrors.WithStack(err)
@@ -276,11 +373,33 @@ func (c *DubboCodec) decodeResponse(data []byte) (*remoting.Response, int, error
 			rpcResult.Err = pkg.Err
 		} else if pkg.Body.(*impl.ResponsePayload).Exception != nil {
 			rpcResult.Err = pkg.Body.(*impl.ResponsePayload).Exception
+			if pkg.Header.ResponseStatus == impl.Response_SERVER_THREADPOOL_EXHAUSTED_ERROR {
+				// the client fails over to the other servers for the typed error
+				rpcResult.Err = remoting.ParseServerBusyError(rpcResult.Err.Error())
+			} else if pkg.Header.ResponseStatus == impl.Response_SERVER_TIMEOUT {
+				rpcResult.Err = remoting.ParseServerTimeoutError(rpcResult.Err.Error())
+			}
 			response.Error = rpcResult.Err
 		}
 		rpcResult.Attrs = pkg.Body.(*impl.ResponsePayload).Attachments
 		rpcResult.Rest = pkg.Body.(*impl.ResponsePayload).RspObj
+		if skipped := pkg.Body.(*impl.ResponsePayload).SkippedElements; skipped > 0 {
+			if rpcResult.Attrs == nil {
+				rpcResult.Attrs = make(map[string]interface{}, 1)
+			}
+			rpcResult.AddAttachment(constant.SERIALIZATION_SKIPPED_ELEMENTS_KEY, skipped)
+		}
 	}
 
 	return response, hessian.HEADER_LENGTH + pkg.Header.BodyLen, nil
 }
+
+// isRejectedBody tells whether the cause @err of the decoding rejects the body of the package only, which fails
+// the request or the response of the package instead of the session it's received on.
+func isRejectedBody(err error) bool {
+	switch err {
+	case impl.ErrSizeExceeded, impl.ErrClassNotAllowed, impl.ErrUnknownField:
+		return true
+	}
+	return false
+}
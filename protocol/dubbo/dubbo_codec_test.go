@@ -0,0 +1,319 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dubbo
+
+import (
+	"encoding/binary"
+	"strings"
+	"testing"
+	"time"
+)
+
+import (
+	hessian "github.com/apache/dubbo-go-hessian2"
+
+	perrors "github.com/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/dubbo/impl"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+// protobufPackage builds the package with @header serialized by protobuf, which isn't the local serialization
+func protobufPackage(header [hessian.HEADER_LENGTH]byte, id int64) []byte {
+	body := []byte{0x0a, 0x05, 'h', 'e', 'l', 'l', 'o'}
+	header[2] |= constant.S_Proto
+	binary.BigEndian.PutUint64(header[4:], uint64(id))
+	binary.BigEndian.PutUint32(header[12:], uint32(len(body)))
+	return append(header[:], body...)
+}
+
+func TestDubboCodecDecodeSerializationMismatch(t *testing.T) {
+	codec := &DubboCodec{}
+
+	// the provider replies the error instead of failing to decode the request
+	data := protobufPackage(hessian.DubboRequestHeaderBytesTwoWay, 10)
+	result, length, err := codec.Decode(data)
+	assert.NoError(t, err)
+	assert.Equal(t, len(data), length)
+	assert.True(t, result.IsRequest)
+	request := result.Result.(*remoting.Request)
+	assert.Equal(t, int64(10), request.ID)
+	assert.True(t, request.TwoWay)
+	mismatch, ok := request.Data.(*impl.SerializationMismatchError)
+	assert.True(t, ok)
+	assert.Equal(t, constant.S_Hessian2, mismatch.LocalID)
+	assert.Equal(t, constant.S_Proto, mismatch.RemoteID)
+	assert.Equal(t, constant.S_Hessian2, request.SerialID)
+	assert.Contains(t, mismatch.Error(), "hessian2(id 2)")
+	assert.Contains(t, mismatch.Error(), "protobuf(id 21)")
+
+	// the consumer fails the pending invocation with the error
+	data = protobufPackage(hessian.DubboResponseHeaderBytes, 11)
+	result, length, err = codec.Decode(data)
+	assert.NoError(t, err)
+	assert.Equal(t, len(data), length)
+	assert.False(t, result.IsRequest)
+	response := result.Result.(*remoting.Response)
+	assert.Equal(t, int64(11), response.ID)
+	mismatch, ok = response.Error.(*impl.SerializationMismatchError)
+	assert.True(t, ok)
+	assert.Equal(t, constant.S_Hessian2, mismatch.LocalID)
+	assert.Equal(t, constant.S_Proto, mismatch.RemoteID)
+	assert.Equal(t, mismatch, response.Result.(*protocol.RPCResult).Err)
+}
+
+func TestDubboCodecServerBusyResponse(t *testing.T) {
+	codec := &DubboCodec{}
+	busy := remoting.NewServerBusyError(500 * time.Millisecond)
+	response := remoting.NewResponse(11, "2.0.2")
+	response.SerialID = constant.S_Hessian2
+	response.Status = hessian.Response_SERVER_ERROR
+	response.Error = busy
+	response.Result = protocol.RPCResult{Err: busy}
+	buf, err := codec.EncodeResponse(response)
+	assert.NoError(t, err)
+	assert.Equal(t, impl.Response_SERVER_THREADPOOL_EXHAUSTED_ERROR, buf.Bytes()[3])
+
+	// the consumer gets the typed error with the retry-after hint
+	result, _, err := codec.Decode(buf.Bytes())
+	assert.NoError(t, err)
+	decoded := result.Result.(*remoting.Response)
+	assert.Equal(t, int64(11), decoded.ID)
+	assert.Equal(t, busy, decoded.Error)
+	assert.Equal(t, busy, decoded.Result.(*protocol.RPCResult).Err)
+}
+
+func TestDubboCodecServerTimeoutResponse(t *testing.T) {
+	codec := &DubboCodec{}
+	timeout := remoting.NewServerTimeoutError(3 * time.Second)
+	response := remoting.NewResponse(12, "2.0.2")
+	response.SerialID = constant.S_Hessian2
+	response.Status = hessian.Response_OK
+	response.Result = protocol.RPCResult{Err: timeout}
+	buf, err := codec.EncodeResponse(response)
+	assert.NoError(t, err)
+	assert.Equal(t, impl.Response_SERVER_TIMEOUT, buf.Bytes()[3])
+
+	// the consumer gets the typed error with the server timeout
+	result, _, err := codec.Decode(buf.Bytes())
+	assert.NoError(t, err)
+	decoded := result.Result.(*remoting.Response)
+	assert.Equal(t, timeout, decoded.Error)
+	assert.Equal(t, timeout, decoded.Result.(*protocol.RPCResult).Err)
+}
+
+func TestDubboCodecSkippedElementsAttachment(t *testing.T) {
+	codec := &DubboCodec{}
+	response := remoting.NewResponse(13, "2.0.2")
+	response.SerialID = constant.S_Hessian2
+	response.Status = hessian.Response_OK
+	response.Result = protocol.RPCResult{Rest: []interface{}{"alex", int64(1), "bob"}}
+	buf, err := codec.EncodeResponse(response)
+	assert.NoError(t, err)
+
+	// the consumer tells the number of the elements skipped by the result attachment
+	var reply []string
+	pending := remoting.NewPendingResponse(13)
+	pending.Reply = &reply
+	pending.LenientCollection = true
+	remoting.AddPendingResponse(pending)
+	result, _, err := codec.Decode(buf.Bytes())
+	assert.NoError(t, err)
+	rpcResult := result.Result.(*remoting.Response).Result.(*protocol.RPCResult)
+	assert.NoError(t, rpcResult.Err)
+	assert.Equal(t, []string{"alex", "bob"}, reply)
+	assert.Equal(t, 1, rpcResult.Attachment(constant.SERIALIZATION_SKIPPED_ELEMENTS_KEY, 0))
+}
+
+func TestDubboCodecLateResponse(t *testing.T) {
+	codec := &DubboCodec{}
+	response := remoting.NewResponse(14, "2.0.2")
+	response.SerialID = constant.S_Hessian2
+	response.Status = hessian.Response_OK
+	response.Result = protocol.RPCResult{Rest: "hello"}
+	buf, err := codec.EncodeResponse(response)
+	assert.NoError(t, err)
+
+	// the response of the cancelled invocation has no waiter, it's decoded without its body
+	result, length, err := codec.Decode(buf.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, buf.Len(), length)
+	decoded := result.Result.(*remoting.Response)
+	assert.Equal(t, int64(14), decoded.ID)
+	assert.NoError(t, decoded.Error)
+	assert.Nil(t, decoded.Result.(*protocol.RPCResult).Rest)
+	assert.NotPanics(t, decoded.Handle)
+}
+
+// encodeValueRequest encodes the request of @value to the service of @url
+func encodeValueRequest(t *testing.T, url *common.URL, value interface{}) []byte {
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUser"),
+		invocation.WithArguments([]interface{}{value}),
+		invocation.WithAttachments(map[string]interface{}{constant.PATH_KEY: url.Path[1:]}))
+	var rpcInvocation protocol.Invocation = inv
+	buf, err := (&DubboCodec{}).EncodeRequest(&remoting.Request{ID: 15, TwoWay: true, Data: &rpcInvocation})
+	assert.NoError(t, err)
+	return buf.Bytes()
+}
+
+// encodeValueResponse encodes the response of @value
+func encodeValueResponse(t *testing.T, value interface{}) []byte {
+	response := remoting.NewResponse(16, "2.0.2")
+	response.SerialID = constant.S_Hessian2
+	response.Status = hessian.Response_OK
+	response.Result = protocol.RPCResult{Rest: value}
+	buf, err := (&DubboCodec{}).EncodeResponse(response)
+	assert.NoError(t, err)
+	return buf.Bytes()
+}
+
+// decodeRejectedRequest decodes the request @data to the service of @url, whose options reject its arguments,
+// and returns the error the request gets.
+func decodeRejectedRequest(t *testing.T, url *common.URL, data []byte) error {
+	opts := newServiceOptions(url)
+	storeServiceOptions(url, opts)
+	defer removeServiceOptions(url, opts)
+
+	// the provider replies the error instead of closing the session
+	result, length, err := (&DubboCodec{}).Decode(data)
+	assert.NoError(t, err)
+	assert.Equal(t, len(data), length)
+	request := result.Result.(*remoting.Request)
+	assert.Equal(t, int64(15), request.ID)
+	assert.True(t, request.TwoWay)
+	reqErr, _ := request.Data.(error)
+	return reqErr
+}
+
+// decodeRejectedResponse decodes the response @data to the reference of @url, whose options reject its value,
+// and returns the error the response gets.
+func decodeRejectedResponse(t *testing.T, url *common.URL, data []byte) error {
+	var reply interface{}
+	pending := remoting.NewPendingResponse(16)
+	pending.Reply = &reply
+	pending.CodecOptions = newServiceOptions(url)
+	remoting.AddPendingResponse(pending)
+	// the consumer fails the invocation instead of closing the session
+	result, length, err := (&DubboCodec{}).Decode(data)
+	assert.NoError(t, err)
+	assert.Equal(t, len(data), length)
+	decoded := result.Result.(*remoting.Response)
+	assert.Equal(t, int64(16), decoded.ID)
+	assert.Equal(t, decoded.Error, decoded.Result.(*protocol.RPCResult).Err)
+	decoded.Handle()
+	return decoded.Error
+}
+
+func TestDubboCodecSizeExceeded(t *testing.T) {
+	url, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.LimitedProvider",
+		common.WithParamsValue(constant.SERIALIZATION_MAX_COLLECTION_SIZE_KEY, "10"))
+	assert.NoError(t, err)
+	value := make([]interface{}, 11)
+	err = decodeRejectedRequest(t, url, encodeValueRequest(t, url, value))
+	assert.Equal(t, impl.ErrSizeExceeded, perrors.Cause(err))
+	err = decodeRejectedResponse(t, url, encodeValueResponse(t, value))
+	assert.Equal(t, impl.ErrSizeExceeded, perrors.Cause(err))
+}
+
+type deniedUser struct {
+	Name string
+}
+
+func (deniedUser) JavaClassName() string {
+	return "com.ikurento.user.DeniedUser"
+}
+
+func TestDubboCodecClassNotAllowed(t *testing.T) {
+	hessian.RegisterPOJO(&deniedUser{})
+	url, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.StrictProvider",
+		common.WithParamsValue(constant.SERIALIZATION_DENYLIST_KEY, "com.ikurento.user.DeniedUser"))
+	assert.NoError(t, err)
+	err = decodeRejectedRequest(t, url, encodeValueRequest(t, url, []interface{}{&deniedUser{Name: "Alex"}}))
+	assert.Equal(t, impl.ErrClassNotAllowed, perrors.Cause(err))
+	assert.Contains(t, err.Error(), "com.ikurento.user.DeniedUser")
+}
+
+type strictUser struct {
+	Name string
+}
+
+func (strictUser) JavaClassName() string {
+	return "com.ikurento.user.StrictUser"
+}
+
+type strictUserV2 struct {
+	Name  string
+	Email string
+}
+
+func (strictUserV2) JavaClassName() string {
+	return "com.ikurento.user.StrictUser"
+}
+
+func TestDubboCodecUnknownField(t *testing.T) {
+	url, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.StrictProvider",
+		common.WithParamsValue(constant.SERIALIZATION_UNKNOWN_FIELDS_KEY, impl.UnknownFieldsError))
+	assert.NoError(t, err)
+	// the newer user is encoded, and the older one is decoded
+	hessian.RegisterPOJO(&strictUserV2{})
+	user := &strictUserV2{Name: "Alex", Email: "alex@example.com"}
+	request, response := encodeValueRequest(t, url, user), encodeValueResponse(t, user)
+	hessian.UnRegisterPOJOs(&strictUserV2{})
+	hessian.RegisterPOJO(&strictUser{})
+	defer hessian.UnRegisterPOJOs(&strictUser{})
+
+	err = decodeRejectedRequest(t, url, request)
+	assert.Equal(t, impl.ErrUnknownField, perrors.Cause(err))
+	err = decodeRejectedResponse(t, url, response)
+	assert.Equal(t, impl.ErrUnknownField, perrors.Cause(err))
+	assert.Contains(t, err.Error(), "field email of class com.ikurento.user.StrictUser")
+}
+
+func TestDubboCodecForURLMaxPayload(t *testing.T) {
+	small, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?payload=1024")
+	assert.NoError(t, err)
+	large, err := common.NewURL("dubbo://127.0.0.1:20001/com.ikurento.user.UserProvider")
+	assert.NoError(t, err)
+	smallCodec := remoting.GetCodecByURL(small)
+	largeCodec := remoting.GetCodecByURL(large)
+
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUser"),
+		invocation.WithArguments([]interface{}{strings.Repeat("x", 2048)}),
+		invocation.WithAttachments(map[string]interface{}{constant.PATH_KEY: "com.ikurento.user.UserProvider"}))
+	var rpcInvocation protocol.Invocation = inv
+	request := &remoting.Request{ID: 1, TwoWay: true, Data: &rpcInvocation}
+
+	// the limit of one server or client doesn't affect the others
+	_, err = smallCodec.EncodeRequest(request)
+	assert.Error(t, err)
+	buf, err := largeCodec.EncodeRequest(request)
+	assert.NoError(t, err)
+	_, _, err = smallCodec.Decode(buf.Bytes())
+	assert.ErrorIs(t, err, impl.ErrBodyTooLarge)
+	result, _, err := largeCodec.Decode(buf.Bytes())
+	assert.NoError(t, err)
+	assert.True(t, result.IsRequest)
+}
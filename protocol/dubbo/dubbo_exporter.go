@@ -21,29 +21,66 @@ import (
 	"sync"
 )
 
+import (
+	gxsync "github.com/dubbogo/gost/sync"
+)
+
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/common/logger"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/dubbo/impl"
 )
 
 // DubboExporter is dubbo service exporter.
 type DubboExporter struct {
 	protocol.BaseExporter
+	// taskPool is the dedicated goroutine pool of the service, nil means the shared pool of the server is used
+	taskPool gxsync.GenericTaskPool
+	// options are the options of the hessian2 serialization of the service
+	options *impl.Options
 }
 
 // NewDubboExporter get a DubboExporter.
 func NewDubboExporter(key string, invoker protocol.Invoker, exporterMap *sync.Map) *DubboExporter {
 	return &DubboExporter{
 		BaseExporter: *protocol.NewBaseExporter(key, invoker, exporterMap),
+		taskPool:     newServiceTaskPool(invoker.GetURL()),
+		options:      newServiceOptions(invoker.GetURL()),
 	}
 }
 
+// newServiceTaskPool creates the goroutine pool configured by the threadpool and threads params of @url.
+func newServiceTaskPool(url *common.URL) gxsync.GenericTaskPool {
+	threadPool := url.GetParam(constant.THREADPOOL_KEY, "")
+	switch threadPool {
+	case "":
+		return nil
+	case constant.FIXED_THREADPOOL:
+		threads := url.GetParamByIntValue(constant.THREADS_KEY, constant.DEFAULT_THREADS)
+		logger.Infof("service %s uses a fixed goroutine pool with %d threads", url.ServiceKey(), threads)
+		return gxsync.NewTaskPoolSimple(threads)
+	default:
+		logger.Warnf("unsupported threadpool %s of service %s, the shared goroutine pool will be used",
+			threadPool, url.ServiceKey())
+		return nil
+	}
+}
+
+// TaskPool returns the dedicated goroutine pool of the service, it returns nil if the service uses the shared pool.
+func (de *DubboExporter) TaskPool() gxsync.GenericTaskPool {
+	return de.taskPool
+}
+
 // Unexport unexport dubbo service exporter.
 func (de *DubboExporter) Unexport() {
 	interfaceName := de.GetInvoker().GetURL().GetParam(constant.INTERFACE_KEY, "")
 	de.BaseExporter.Unexport()
+	removeServiceOptions(de.GetInvoker().GetURL(), de.options)
+	if de.taskPool != nil {
+		de.taskPool.Close()
+	}
 	err := common.ServiceMap.UnRegister(interfaceName, DUBBO, de.GetInvoker().GetURL().ServiceKey())
 	if err != nil {
 		logger.Errorf("[DubboExporter.Unexport] error: %v", err)
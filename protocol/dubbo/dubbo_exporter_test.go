@@ -0,0 +1,81 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dubbo
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+func TestDubboExporterTaskPool(t *testing.T) {
+	exporterMap := new(sync.Map)
+
+	latencyURL, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.LatencyProvider",
+		common.WithParamsValue(constant.THREADPOOL_KEY, constant.FIXED_THREADPOOL),
+		common.WithParamsValue(constant.THREADS_KEY, "2"))
+	assert.NoError(t, err)
+	batchURL, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.BatchProvider",
+		common.WithParamsValue(constant.THREADPOOL_KEY, constant.FIXED_THREADPOOL),
+		common.WithParamsValue(constant.THREADS_KEY, "1"))
+	assert.NoError(t, err)
+	sharedURL, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.SharedProvider")
+	assert.NoError(t, err)
+
+	latencyExporter := NewDubboExporter(latencyURL.ServiceKey(), protocol.NewBaseInvoker(latencyURL), exporterMap)
+	batchExporter := NewDubboExporter(batchURL.ServiceKey(), protocol.NewBaseInvoker(batchURL), exporterMap)
+	sharedExporter := NewDubboExporter(sharedURL.ServiceKey(), protocol.NewBaseInvoker(sharedURL), exporterMap)
+
+	assert.NotNil(t, latencyExporter.TaskPool())
+	assert.NotNil(t, batchExporter.TaskPool())
+	assert.Nil(t, sharedExporter.TaskPool())
+	assert.NotEqual(t, latencyExporter.TaskPool(), batchExporter.TaskPool())
+
+	// occupy the only goroutine of the batch service
+	release := make(chan struct{})
+	assert.True(t, batchExporter.TaskPool().AddTask(func() {
+		<-release
+	}))
+
+	// the latency service still dispatches on its own pool
+	done := make(chan struct{})
+	assert.True(t, latencyExporter.TaskPool().AddTask(func() {
+		close(done)
+	}))
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		assert.Fail(t, "the latency service is stalled by the batch service")
+	}
+	close(release)
+
+	batchExporter.Unexport()
+	assert.True(t, batchExporter.TaskPool().IsClosed())
+	assert.False(t, latencyExporter.TaskPool().IsClosed())
+	latencyExporter.Unexport()
+}
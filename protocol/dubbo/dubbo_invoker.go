@@ -35,6 +35,7 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/common/logger"
 	"dubbo.apache.org/dubbo-go/v3/config"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/dubbo/impl"
 	invocation_impl "dubbo.apache.org/dubbo-go/v3/protocol/invocation"
 	"dubbo.apache.org/dubbo-go/v3/remoting"
 )
@@ -53,6 +54,10 @@ type DubboInvoker struct {
 	quitOnce    sync.Once
 	// timeout for service(interface) level.
 	timeout time.Duration
+	// serialization is the one negotiated with the provider by the preference of the reference
+	serialization string
+	// options are the ones of the hessian2 serialization of the bodies configured by the reference
+	options *impl.Options
 }
 
 // NewDubboInvoker constructor
@@ -65,11 +70,39 @@ func NewDubboInvoker(url *common.URL, client *remoting.ExchangeClient) *DubboInv
 		clientGuard: &sync.RWMutex{},
 		client:      client,
 		timeout:     timeout,
+		options:     newServiceOptions(url),
 	}
+	di.serialization = negotiateSerialization(url)
 
 	return di
 }
 
+// negotiateSerialization picks the first serialization preferred by the reference which is available locally and
+// advertised by the provider of @url, and the providers without the advertisement are assumed to support hessian2
+// only, since the serialization param of the merged url is filled by the reference. It's empty if there isn't any
+// preference or none of them is supported, and the request is serialized as usual then.
+func negotiateSerialization(url *common.URL) string {
+	preferred := url.GetParam(constant.PREFER_SERIALIZATION_KEY, "")
+	if preferred == "" {
+		return ""
+	}
+	supported := url.GetParam(constant.SERIALIZATION_SUPPORTED_KEY, constant.HESSIAN2_SERIALIZATION)
+	for _, p := range strings.Split(preferred, ",") {
+		p = strings.TrimSpace(p)
+		if !impl.HasSerializer(p) {
+			continue
+		}
+		for _, s := range strings.Split(supported, ",") {
+			if p == strings.TrimSpace(s) {
+				return p
+			}
+		}
+	}
+	logger.Warnf("None of the preferred serializations %s is supported by the provider %s which supports %s",
+		preferred, url.Location, supported)
+	return ""
+}
+
 func (di *DubboInvoker) setClient(client *remoting.ExchangeClient) {
 	di.clientGuard.Lock()
 	defer di.clientGuard.Unlock()
@@ -84,6 +117,15 @@ func (di *DubboInvoker) getClient() *remoting.ExchangeClient {
 	return di.client
 }
 
+// Connect connects the client to the provider if it isn't connected yet
+func (di *DubboInvoker) Connect(ctx context.Context) error {
+	client := di.getClient()
+	if client == nil {
+		return protocol.ErrClientClosed
+	}
+	return client.Connect(ctx, di.GetURL())
+}
+
 // Invoke call remoting.
 func (di *DubboInvoker) Invoke(ctx context.Context, invocation protocol.Invocation) protocol.Result {
 	var (
@@ -128,10 +170,18 @@ func (di *DubboInvoker) Invoke(ctx context.Context, invocation protocol.Invocati
 	di.appendCtx(ctx, inv)
 
 	url := di.GetURL()
+	prepareAttachments(inv, isTypedProvider(url))
 	// default hessian2 serialization, compatible
 	if url.GetParam(constant.SERIALIZATION_KEY, "") == "" {
 		url.SetParam(constant.SERIALIZATION_KEY, constant.HESSIAN2_SERIALIZATION)
 	}
+	if di.serialization != "" {
+		inv.SetAttachments(constant.SERIALIZATION_KEY, di.serialization)
+	}
+	inv.SetAttribute(constant.CODEC_OPTIONS_KEY, di.options)
+	if di.options.CompressionEnabled() {
+		inv.SetAttachments(constant.COMPRESS_ACCEPT_KEY, constant.GZIP_COMPRESSION)
+	}
 	// async
 	async, err := strconv.ParseBool(inv.AttachmentsByKey(constant.ASYNC_KEY, "false"))
 	if err != nil {
@@ -141,7 +191,10 @@ func (di *DubboInvoker) Invoke(ctx context.Context, invocation protocol.Invocati
 	// response := NewResponse(inv.Reply(), nil)
 	rest := &protocol.RPCResult{}
 	timeout := di.getTimeout(inv)
-	if async {
+	if di.isOneway(inv) {
+		// the request is written without awaiting any response, and the provider doesn't reply to it
+		result.Err = di.client.Send(&invocation, url, timeout)
+	} else if async {
 		if callBack, ok := inv.CallBack().(func(response common.CallbackResponse)); ok {
 			result.Err = di.client.AsyncRequest(&invocation, url, timeout, callBack, rest)
 		} else {
@@ -151,7 +204,7 @@ func (di *DubboInvoker) Invoke(ctx context.Context, invocation protocol.Invocati
 		if inv.Reply() == nil {
 			result.Err = protocol.ErrNoReply
 		} else {
-			result.Err = di.client.Request(&invocation, url, timeout, rest)
+			result.Err = di.client.RequestWithContext(ctx, &invocation, url, timeout, rest)
 		}
 	}
 	if result.Err == nil {
@@ -163,12 +216,24 @@ func (di *DubboInvoker) Invoke(ctx context.Context, invocation protocol.Invocati
 	return &result
 }
 
-// get timeout including methodConfig
-func (di *DubboInvoker) getTimeout(invocation *invocation_impl.RPCInvocation) time.Duration {
-	methodName := invocation.MethodName()
+// invokedMethod returns the name of the method invoked by @invocation, which is the first argument of the generic one
+func (di *DubboInvoker) invokedMethod(invocation *invocation_impl.RPCInvocation) string {
 	if di.GetURL().GetParamBool(constant.GENERIC_KEY, false) {
-		methodName = invocation.Arguments()[0].(string)
+		return invocation.Arguments()[0].(string)
 	}
+	return invocation.MethodName()
+}
+
+// isOneway returns true if the response of the method invoked by @invocation isn't awaited
+func (di *DubboInvoker) isOneway(invocation *invocation_impl.RPCInvocation) bool {
+	url := di.GetURL()
+	return !url.GetMethodParamBool(di.invokedMethod(invocation), constant.RETURN_KEY,
+		url.GetParamBool(constant.RETURN_KEY, true))
+}
+
+// get timeout including methodConfig
+func (di *DubboInvoker) getTimeout(invocation *invocation_impl.RPCInvocation) time.Duration {
+	methodName := di.invokedMethod(invocation)
 	timeout := di.GetURL().GetParam(strings.Join([]string{constant.METHOD_KEYS, methodName, constant.TIMEOUT_KEY}, "."), "")
 	if len(timeout) != 0 {
 		if t, err := time.ParseDuration(timeout); err == nil {
@@ -17,6 +17,530 @@
 
 package dubbo
 
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.uber.org/atomic"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/proxy"
+	"dubbo.apache.org/dubbo-go/v3/common/proxy/proxy_factory"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/dubbo/impl"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+	"dubbo.apache.org/dubbo-go/v3/remoting/getty"
+)
+
+type CancelProvider struct {
+	cancelled chan struct{}
+}
+
+func (p *CancelProvider) Wait(ctx context.Context, id string) (string, error) {
+	select {
+	case <-ctx.Done():
+		close(p.cancelled)
+		return "", ctx.Err()
+	case <-time.After(3 * time.Second):
+		return id, nil
+	}
+}
+
+func (p *CancelProvider) Reference() string {
+	return "CancelProvider"
+}
+
+func TestDubboInvokerInvokeCancel(t *testing.T) {
+	provider := &CancelProvider{cancelled: make(chan struct{})}
+	_, err := common.ServiceMap.Register("com.ikurento.user.CancelProvider", "dubbo", "", "", provider)
+	assert.NoError(t, err)
+	url, err := common.NewURL("dubbo://127.0.0.1:20704/com.ikurento.user.CancelProvider?" +
+		"interface=com.ikurento.user.CancelProvider&side=provider&timeout=5000&methods=Wait")
+	assert.NoError(t, err)
+	proto := GetProtocol()
+	proto.Export(&proxy_factory.ProxyInvoker{BaseInvoker: *protocol.NewBaseInvoker(url)})
+	defer proto.Destroy()
+
+	invoker := NewDubboInvoker(url, getExchangeClient(url))
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("Wait"),
+		invocation.WithArguments([]interface{}{"1"}), invocation.WithReply(new(string)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(200*time.Millisecond, cancel)
+	start := time.Now()
+	res := invoker.Invoke(ctx, inv)
+	assert.Equal(t, context.Canceled, perrors.Cause(res.Error()))
+	assert.True(t, time.Since(start) < 3*time.Second)
+
+	// the provider observes the cancellation instead of finishing the wasted work
+	select {
+	case <-provider.cancelled:
+	case <-time.After(2 * time.Second):
+		assert.Fail(t, "the provider doesn't observe the cancellation")
+	}
+}
+
+type AsyncProvider struct{}
+
+func (p *AsyncProvider) GetName(_ context.Context, id string) (string, error) {
+	return "name-" + id, nil
+}
+
+func (p *AsyncProvider) Sleep(_ context.Context, id string) (string, error) {
+	time.Sleep(time.Second)
+	return id, nil
+}
+
+func (p *AsyncProvider) Reference() string {
+	return "AsyncProvider"
+}
+
+func TestDubboInvokerAsyncInvoke(t *testing.T) {
+	_, err := common.ServiceMap.Register("com.ikurento.user.AsyncProvider", "dubbo", "", "", &AsyncProvider{})
+	assert.NoError(t, err)
+	url, err := common.NewURL("dubbo://127.0.0.1:20705/com.ikurento.user.AsyncProvider?" +
+		"interface=com.ikurento.user.AsyncProvider&side=provider&methods=GetName,Sleep&methods.Sleep.timeout=200ms")
+	assert.NoError(t, err)
+	proto := GetProtocol()
+	proto.Export(&proxy_factory.ProxyInvoker{BaseInvoker: *protocol.NewBaseInvoker(url)})
+	defer proto.Destroy()
+
+	invoker := NewDubboInvoker(url, getExchangeClient(url))
+	syncReply := new(string)
+	res := invoker.Invoke(context.Background(), invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetName"),
+		invocation.WithArguments([]interface{}{"1"}), invocation.WithReply(syncReply)))
+	assert.NoError(t, res.Error())
+	assert.Equal(t, "name-1", *syncReply)
+
+	// issue the calls together and await them
+	pxy := proxy.NewProxy(invoker, nil, nil)
+	replies := make([]*string, 3)
+	futures := make([]<-chan protocol.Result, 3)
+	for i := range futures {
+		replies[i] = new(string)
+		futures[i] = pxy.AsyncInvoke(context.Background(), "GetName", []interface{}{"1"}, replies[i])
+	}
+	for i, future := range futures {
+		select {
+		case res = <-future:
+			assert.NoError(t, res.Error())
+			assert.Equal(t, *syncReply, *replies[i])
+		case <-time.After(3 * time.Second):
+			assert.Fail(t, "the async invocation isn't finished")
+		}
+	}
+
+	// the timeout is honored
+	start := time.Now()
+	res = <-pxy.AsyncInvoke(context.Background(), "Sleep", []interface{}{"1"}, new(string))
+	assert.Equal(t, remoting.ErrAsyncRequestTimeout, perrors.Cause(res.Error()))
+	assert.True(t, time.Since(start) < time.Second)
+}
+
+type AttachmentProvider struct {
+	received chan interface{}
+}
+
+func (p *AttachmentProvider) GetMeta(ctx context.Context, id string) (string, error) {
+	p.received <- ctx.Value(constant.AttachmentKey).(map[string]interface{})["meta"]
+	return id, nil
+}
+
+func (p *AttachmentProvider) Reference() string {
+	return "AttachmentProvider"
+}
+
+func TestDubboInvokerTypedAttachments(t *testing.T) {
+	provider := &AttachmentProvider{received: make(chan interface{}, 1)}
+	_, err := common.ServiceMap.Register("com.ikurento.user.AttachmentProvider", "dubbo", "", "", provider)
+	assert.NoError(t, err)
+	url, err := common.NewURL("dubbo://127.0.0.1:20706/com.ikurento.user.AttachmentProvider?" +
+		"interface=com.ikurento.user.AttachmentProvider&side=provider&methods=GetMeta")
+	assert.NoError(t, err)
+	proto := GetProtocol()
+	proto.Export(&proxy_factory.ProxyInvoker{BaseInvoker: *protocol.NewBaseInvoker(url)})
+	defer proto.Destroy()
+
+	meta := map[string]interface{}{
+		"region": "hangzhou",
+		"tags":   []interface{}{"gray", "canary"},
+		"owner":  map[string]interface{}{"name": "dubbo"},
+	}
+	invoke := func(invoker protocol.Invoker) interface{} {
+		inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetMeta"),
+			invocation.WithArguments([]interface{}{"1"}), invocation.WithReply(new(string)),
+			invocation.WithAttachments(map[string]interface{}{"meta": meta}))
+		assert.NoError(t, invoker.Invoke(context.Background(), inv).Error())
+		return <-provider.received
+	}
+
+	// the attachment is flattened for the provider which doesn't advertise the typed attachments
+	flattened := `{"region":"hangzhou","tags":["gray","canary"],"owner":{"name":"dubbo"}}`
+	received := invoke(NewDubboInvoker(url, getExchangeClient(url)))
+	assert.JSONEq(t, flattened, received.(string))
+
+	// and the structure is intact for the one advertising them
+	typedURL := url.Clone()
+	typedURL.SetParam(constant.TYPED_ATTACHMENTS_SUPPORTED_KEY, "true")
+	received = invoke(NewDubboInvoker(typedURL, getExchangeClient(typedURL)))
+	structured, ok := received.(map[interface{}]interface{})
+	assert.True(t, ok)
+	assert.Equal(t, "hangzhou", structured["region"])
+	assert.Equal(t, []interface{}{"gray", "canary"}, structured["tags"])
+	assert.Equal(t, "dubbo", structured["owner"].(map[interface{}]interface{})["name"])
+
+	// unless the reference turns them off
+	typedURL.SetParam(constant.TYPED_ATTACHMENTS_KEY, "false")
+	received = invoke(NewDubboInvoker(typedURL, getExchangeClient(typedURL)))
+	assert.JSONEq(t, flattened, received.(string))
+}
+
+func TestPrepareResultAttachments(t *testing.T) {
+	attachments := map[string]interface{}{"meta": map[string]interface{}{"region": "hangzhou"}, "trace": "1"}
+	// the result attachments are kept for the consumer accepting them
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetMeta"))
+	prepareAttachments(inv, true)
+	assert.Equal(t, "true", inv.AttachmentsByKey(constant.TYPED_ATTACHMENTS_KEY, ""))
+	prepareResultAttachments(inv, attachments)
+	assert.Equal(t, map[string]interface{}{"region": "hangzhou"}, attachments["meta"])
+
+	// and flattened for the ones which don't tell it, e.g. the older ones
+	inv = invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetMeta"))
+	prepareResultAttachments(inv, attachments)
+	assert.Equal(t, `{"region":"hangzhou"}`, attachments["meta"])
+	assert.Equal(t, "1", attachments["trace"])
+
+	inv = invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetMeta"))
+	prepareAttachments(inv, false)
+	assert.Equal(t, "false", inv.AttachmentsByKey(constant.TYPED_ATTACHMENTS_KEY, ""))
+	attachments["meta"] = map[string]interface{}{"region": "hangzhou"}
+	prepareResultAttachments(inv, attachments)
+	assert.Equal(t, `{"region":"hangzhou"}`, attachments["meta"])
+}
+
+type InterceptedProvider struct{}
+
+func (p *InterceptedProvider) GetName(_ context.Context, id string) (string, error) {
+	return "name-" + id, nil
+}
+
+func (p *InterceptedProvider) Reference() string {
+	return "InterceptedProvider"
+}
+
+// envelopeInterceptor stamps the server version on the results of the InterceptedProvider and wraps them
+type envelopeInterceptor struct {
+	priority int
+}
+
+func (i *envelopeInterceptor) Intercept(_ context.Context, invoker protocol.Invoker, inv protocol.Invocation,
+	result protocol.Result) protocol.Result {
+	if invoker.GetURL().GetParam(constant.INTERFACE_KEY, "") != "com.ikurento.user.InterceptedProvider" {
+		return result
+	}
+	if inv.Arguments()[0] == "panic" && i.priority > 0 {
+		panic("broken envelope")
+	}
+	result.AddAttachment("server.version", "1.2.0")
+	result.SetResult(fmt.Sprintf("{%v}", result.Result()))
+	return result
+}
+
+func (i *envelopeInterceptor) GetPriority() int {
+	return i.priority
+}
+
+func TestDubboInvokerResultInterceptor(t *testing.T) {
+	// the interceptors are applied in the order of the priorities
+	extension.AddResultInterceptor(&envelopeInterceptor{priority: 1})
+	extension.AddResultInterceptor(&envelopeInterceptor{priority: 0})
+	_, err := common.ServiceMap.Register("com.ikurento.user.InterceptedProvider", "dubbo", "", "", &InterceptedProvider{})
+	assert.NoError(t, err)
+	url, err := common.NewURL("dubbo://127.0.0.1:20707/com.ikurento.user.InterceptedProvider?" +
+		"interface=com.ikurento.user.InterceptedProvider&side=provider&methods=GetName")
+	assert.NoError(t, err)
+	proto := GetProtocol()
+	proto.Export(&proxy_factory.ProxyInvoker{BaseInvoker: *protocol.NewBaseInvoker(url)})
+	defer proto.Destroy()
+
+	invoker := NewDubboInvoker(url, getExchangeClient(url))
+	invoke := func(id string) (protocol.Result, string) {
+		reply := new(string)
+		inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetName"),
+			invocation.WithArguments([]interface{}{id}), invocation.WithReply(reply))
+		return invoker.Invoke(context.Background(), inv), *reply
+	}
+
+	result, reply := invoke("1")
+	assert.NoError(t, result.Error())
+	assert.Equal(t, "{{name-1}}", reply)
+	assert.Equal(t, "1.2.0", result.Attachment("server.version", ""))
+
+	// the panic of an interceptor is recovered into the error of the result
+	result, _ = invoke("panic")
+	if assert.Error(t, result.Error()) {
+		assert.Contains(t, result.Error().Error(), "broken envelope")
+	}
+}
+
+// countingSerializer is the hessian2 serializer counting the bodies it serializes
+type countingSerializer struct {
+	hessian      impl.HessianSerializer
+	marshalled   *atomic.Int32
+	unmarshalled *atomic.Int32
+}
+
+func (s countingSerializer) Marshal(p impl.DubboPackage) ([]byte, error) {
+	s.marshalled.Inc()
+	return s.hessian.Marshal(p)
+}
+
+func (s countingSerializer) Unmarshal(input []byte, p *impl.DubboPackage) error {
+	s.unmarshalled.Inc()
+	return s.hessian.Unmarshal(input, p)
+}
+
+// mockSerialID marks the packages of the mock serialization, which stands for the one available during the migration
+const mockSerialID = 30
+
+func TestDubboInvokerSerializationPreference(t *testing.T) {
+	impl.SetSerializerWithId(mockSerialID, "mock", impl.HessianSerializer{})
+	referenceURL, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?" +
+		"interface=com.ikurento.user.UserProvider&side=consumer&serialization=mock&prefer.serialization=mock,hessian2")
+	assert.NoError(t, err)
+	migrated, err := common.NewURL("dubbo://127.0.0.1:20001/com.ikurento.user.UserProvider?" +
+		"interface=com.ikurento.user.UserProvider&side=provider&serialization=hessian2&serialization.supported=hessian2,mock")
+	assert.NoError(t, err)
+	legacy, err := common.NewURL("dubbo://127.0.0.2:20001/com.ikurento.user.UserProvider?" +
+		"interface=com.ikurento.user.UserProvider&side=provider")
+	assert.NoError(t, err)
+	unknown, err := common.NewURL("dubbo://127.0.0.3:20001/com.ikurento.user.UserProvider?" +
+		"interface=com.ikurento.user.UserProvider&side=provider&serialization=kryo&serialization.supported=kryo")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "mock", NewDubboInvoker(common.MergeURL(migrated, referenceURL), nil).serialization)
+	// the serialization of the legacy provider is filled by the reference once merged, which isn't advertised
+	legacyMerged := common.MergeURL(legacy, referenceURL)
+	assert.Equal(t, "mock", legacyMerged.GetParam(constant.SERIALIZATION_KEY, ""))
+	assert.Equal(t, "hessian2", NewDubboInvoker(legacyMerged, nil).serialization)
+	// the request is serialized as usual if none of the preferred ones is supported
+	assert.Equal(t, "", NewDubboInvoker(common.MergeURL(unknown, referenceURL), nil).serialization)
+	// and the preference is absent for the single serialization
+	assert.Equal(t, "", NewDubboInvoker(legacy, nil).serialization)
+}
+
+type SerializationProvider struct{}
+
+func (p *SerializationProvider) GetName(_ context.Context, id string) (string, error) {
+	return "name-" + id, nil
+}
+
+func (p *SerializationProvider) Reference() string {
+	return "SerializationProvider"
+}
+
+func TestDubboInvokerNegotiatedSerialization(t *testing.T) {
+	serializer := countingSerializer{marshalled: atomic.NewInt32(0), unmarshalled: atomic.NewInt32(0)}
+	impl.SetSerializerWithId(mockSerialID, "mock", serializer)
+	defer impl.SetSerializerWithId(mockSerialID, "mock", impl.HessianSerializer{})
+	_, err := common.ServiceMap.Register("com.ikurento.user.SerializationProvider", "dubbo", "", "",
+		&SerializationProvider{})
+	assert.NoError(t, err)
+	serviceURL, err := common.NewURL("dubbo://127.0.0.1:20710/com.ikurento.user.SerializationProvider?" +
+		"interface=com.ikurento.user.SerializationProvider&side=provider&methods=GetName" +
+		"&serialization=hessian2&serialization.supported=hessian2,mock")
+	assert.NoError(t, err)
+	referenceURL, err := common.NewURL("dubbo://127.0.0.1:20710/com.ikurento.user.SerializationProvider?" +
+		"interface=com.ikurento.user.SerializationProvider&side=consumer&serialization=kryo&prefer.serialization=kryo,mock")
+	assert.NoError(t, err)
+	proto := GetProtocol()
+	proto.Export(&proxy_factory.ProxyInvoker{BaseInvoker: *protocol.NewBaseInvoker(serviceURL)})
+	defer proto.Destroy()
+
+	// both the request and the response are serialized by the negotiated serialization
+	url := common.MergeURL(serviceURL, referenceURL)
+	reply := new(string)
+	res := NewDubboInvoker(url, getExchangeClient(url)).Invoke(context.Background(),
+		invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetName"),
+			invocation.WithArguments([]interface{}{"1"}), invocation.WithReply(reply)))
+	assert.NoError(t, res.Error())
+	assert.Equal(t, "name-1", *reply)
+	assert.Equal(t, int32(2), serializer.marshalled.Load())
+	assert.Equal(t, int32(2), serializer.unmarshalled.Load())
+}
+
+type NotifyProvider struct {
+	received chan string
+}
+
+func (p *NotifyProvider) Notify(_ context.Context, msg string) error {
+	// the consumer doesn't wait for the slow handling
+	time.Sleep(time.Second)
+	p.received <- msg
+	return nil
+}
+
+func (p *NotifyProvider) Reference() string {
+	return "NotifyProvider"
+}
+
+func TestDubboInvokerOnewayInvoke(t *testing.T) {
+	provider := &NotifyProvider{received: make(chan string, 1)}
+	_, err := common.ServiceMap.Register("com.ikurento.user.NotifyProvider", "dubbo", "", "", provider)
+	assert.NoError(t, err)
+	url, err := common.NewURL("dubbo://127.0.0.1:20709/com.ikurento.user.NotifyProvider?" +
+		"interface=com.ikurento.user.NotifyProvider&side=provider&methods=Notify&methods.Notify.return=false")
+	assert.NoError(t, err)
+	proto := GetProtocol()
+	proto.Export(&proxy_factory.ProxyInvoker{BaseInvoker: *protocol.NewBaseInvoker(url)})
+	defer proto.Destroy()
+
+	invoker := NewDubboInvoker(url, getExchangeClient(url))
+	start := time.Now()
+	// the oneway invocation needs no reply
+	res := invoker.Invoke(context.Background(), invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("Notify"),
+		invocation.WithArguments([]interface{}{"hello"})))
+	assert.NoError(t, res.Error())
+	assert.Nil(t, res.Result())
+	assert.True(t, time.Since(start) < 500*time.Millisecond)
+
+	select {
+	case msg := <-provider.received:
+		assert.Equal(t, "hello", msg)
+	case <-time.After(3 * time.Second):
+		assert.Fail(t, "the provider doesn't receive the oneway invocation")
+	}
+}
+
+type SlowProvider struct{}
+
+func (p *SlowProvider) GetName(_ context.Context, id string) (string, error) {
+	return "name-" + id, nil
+}
+
+func (p *SlowProvider) Sleep(_ context.Context, id string) (string, error) {
+	time.Sleep(time.Second)
+	return id, nil
+}
+
+func (p *SlowProvider) Reference() string {
+	return "SlowProvider"
+}
+
+func TestDubboInvokerConnectAndReadTimeout(t *testing.T) {
+	// the slow connect fails on the connect timeout regardless of the read timeout
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	addr := listener.Addr().String()
+	assert.NoError(t, listener.Close())
+	unreachable, err := common.NewURL("dubbo://" + addr + "/com.ikurento.user.SlowProvider?" +
+		"interface=com.ikurento.user.SlowProvider&timeout=10s&read.timeout=10s&connect.timeout=200ms")
+	assert.NoError(t, err)
+	start := time.Now()
+	assert.Nil(t, getExchangeClient(unreachable))
+	assert.True(t, time.Since(start) < time.Second)
+
+	// the slow read fails on the read timeout regardless of the connect and request timeouts
+	_, err = common.ServiceMap.Register("com.ikurento.user.SlowProvider", "dubbo", "", "", &SlowProvider{})
+	assert.NoError(t, err)
+	url, err := common.NewURL("dubbo://127.0.0.1:20708/com.ikurento.user.SlowProvider?" +
+		"interface=com.ikurento.user.SlowProvider&side=provider&methods=GetName,Sleep&" +
+		"timeout=10s&read.timeout=200ms&connect.timeout=200ms")
+	assert.NoError(t, err)
+	proto := GetProtocol()
+	proto.Export(&proxy_factory.ProxyInvoker{BaseInvoker: *protocol.NewBaseInvoker(url)})
+	defer proto.Destroy()
+
+	invoker := NewDubboInvoker(url, getExchangeClient(url))
+	reply := new(string)
+	res := invoker.Invoke(context.Background(), invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetName"),
+		invocation.WithArguments([]interface{}{"1"}), invocation.WithReply(reply)))
+	assert.NoError(t, res.Error())
+	assert.Equal(t, "name-1", *reply)
+
+	start = time.Now()
+	res = invoker.Invoke(context.Background(), invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("Sleep"),
+		invocation.WithArguments([]interface{}{"1"}), invocation.WithReply(new(string))))
+	if assert.Error(t, res.Error()) {
+		assert.Contains(t, res.Error().Error(), "read timeout")
+	}
+	assert.True(t, time.Since(start) < time.Second)
+}
+
+// connectCountingClient counts the connections made by the getty client
+type connectCountingClient struct {
+	*getty.Client
+	connects atomic.Int32
+}
+
+func (c *connectCountingClient) Connect(url *common.URL) error {
+	c.connects.Inc()
+	return c.Client.Connect(url)
+}
+
+func TestDubboInvokerConnect(t *testing.T) {
+	_, err := common.ServiceMap.Register("com.ikurento.user.SlowProvider", "dubbo", "", "", &SlowProvider{})
+	assert.NoError(t, err)
+	url, err := common.NewURL("dubbo://127.0.0.1:20711/com.ikurento.user.SlowProvider?" +
+		"interface=com.ikurento.user.SlowProvider&side=provider&methods=GetName,Sleep")
+	assert.NoError(t, err)
+	proto := GetProtocol()
+	proto.Export(&proxy_factory.ProxyInvoker{BaseInvoker: *protocol.NewBaseInvoker(url)})
+	defer proto.Destroy()
+
+	newClient := func() *connectCountingClient {
+		return &connectCountingClient{Client: getty.NewClient(getty.Options{
+			ConnectTimeout: time.Second,
+			RequestTimeout: 3 * time.Second,
+		})}
+	}
+	client := newClient()
+	exchangeClient := remoting.NewExchangeClient(url, client, time.Second, true)
+	defer exchangeClient.Close()
+	invoker := NewDubboInvoker(url, exchangeClient)
+
+	// the concurrent callers share the only connection
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, invoker.Connect(context.Background()))
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), client.connects.Load())
+	reply := new(string)
+	res := invoker.Invoke(context.Background(), invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetName"),
+		invocation.WithArguments([]interface{}{"1"}), invocation.WithReply(reply)))
+	assert.NoError(t, res.Error())
+	assert.Equal(t, "name-1", *reply)
+	assert.Equal(t, int32(1), client.connects.Load())
+
+	// the connecting is given up once the context is done
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	lazyClient := remoting.NewExchangeClient(url, newClient(), time.Second, true)
+	defer lazyClient.Close()
+	err = NewDubboInvoker(url, lazyClient).Connect(ctx)
+	assert.True(t, perrors.Is(err, context.Canceled))
+}
+
 //
 //import (
 //	"bytes"
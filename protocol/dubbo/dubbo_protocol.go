@@ -20,11 +20,15 @@ package dubbo
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"sort"
 	"sync"
 	"time"
 )
 
 import (
+	gxsync "github.com/dubbogo/gost/sync"
+
 	"github.com/opentracing/opentracing-go"
 )
 
@@ -33,7 +37,9 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
 	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/config"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/dubbo/impl"
 	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
 	"dubbo.apache.org/dubbo-go/v3/remoting"
 	"dubbo.apache.org/dubbo-go/v3/remoting/getty"
@@ -42,6 +48,8 @@ import (
 const (
 	// DUBBO is dubbo protocol name
 	DUBBO = "dubbo"
+	// defaultConnectTimeout is the connect timeout if the reference doesn't configure it
+	defaultConnectTimeout = "3s"
 )
 
 var (
@@ -49,6 +57,8 @@ var (
 	// It will create one connection for one address (ip+port)
 	exchangeClientMap = new(sync.Map)
 	exchangeLock      = new(sync.Map)
+	// bufferPoolOnce applies the pool of the buffers of the process config once the dubbo protocol is used
+	bufferPoolOnce sync.Once
 )
 
 func init() {
@@ -81,6 +91,8 @@ func (dp *DubboProtocol) Export(invoker protocol.Invoker) protocol.Exporter {
 	exporter := NewDubboExporter(serviceKey, invoker, dp.ExporterMap())
 	dp.SetExporterMap(serviceKey, exporter)
 	logger.Infof("Export service: %s", url.String())
+	bufferPoolOnce.Do(setBufferPool)
+	storeServiceOptions(url, exporter.options)
 	// start server
 	dp.openServer(url)
 	return exporter
@@ -88,6 +100,7 @@ func (dp *DubboProtocol) Export(invoker protocol.Invoker) protocol.Exporter {
 
 // Refer create dubbo service reference.
 func (dp *DubboProtocol) Refer(url *common.URL) protocol.Invoker {
+	bufferPoolOnce.Do(setBufferPool)
 	exchangeClient := getExchangeClient(url)
 	if exchangeClient == nil {
 		logger.Warnf("can't dial the server: %+v", url.Location)
@@ -126,7 +139,9 @@ func (dp *DubboProtocol) openServer(url *common.URL) {
 			handler := func(invocation *invocation.RPCInvocation) protocol.RPCResult {
 				return doHandleRequest(invocation)
 			}
-			srv := remoting.NewExchangeServer(url, getty.NewServer(url, handler))
+			gettyServer := getty.NewServer(url, handler)
+			gettyServer.SetTaskPoolSelector(getServiceTaskPool)
+			srv := remoting.NewExchangeServer(url, gettyServer)
 			dp.serverMap[url.Location] = srv
 			srv.Start()
 		}
@@ -157,7 +172,13 @@ func doHandleRequest(rpcInvocation *invocation.RPCInvocation) protocol.RPCResult
 		// FIXME
 		ctx := rebuildCtx(rpcInvocation)
 
-		invokeResult := invoker.Invoke(ctx, rpcInvocation)
+		request := make(map[string]interface{}, len(rpcInvocation.Attachments()))
+		for k, v := range rpcInvocation.Attachments() {
+			request[k] = v
+		}
+		invokeResult := invokeWithServerTimeout(ctx, invoker, rpcInvocation)
+		result.Attrs = resultAttachments(rpcInvocation, request, invokeResult.Attachments())
+		prepareResultAttachments(rpcInvocation, result.Attrs)
 		if err := invokeResult.Error(); err != nil {
 			result.Err = invokeResult.Error()
 			// p.Header.ResponseStatus = hessian.Response_OK
@@ -173,6 +194,78 @@ func doHandleRequest(rpcInvocation *invocation.RPCInvocation) protocol.RPCResult
 	return result
 }
 
+// resultAttachments returns the attachments set to the result by the provider, e.g. by the result interceptors,
+// which are the ones added or changed from the @request ones since the result shares the attachments of the request.
+// It's nil if there isn't any, and then the response carries no attachments as before.
+func resultAttachments(rpcInvocation *invocation.RPCInvocation, request map[string]interface{},
+	attachments map[string]interface{}) map[string]interface{} {
+	var added map[string]interface{}
+	for k, v := range attachments {
+		if origin, ok := request[k]; ok && reflect.DeepEqual(origin, v) {
+			continue
+		}
+		if added == nil {
+			added = make(map[string]interface{})
+		}
+		added[k] = v
+	}
+	if added != nil {
+		// the response carries the attachments only if the dubbo version of the request supports them
+		if version := rpcInvocation.Attachment(impl.DUBBO_VERSION_KEY); version != nil {
+			added[impl.DUBBO_VERSION_KEY] = version
+		}
+	}
+	return added
+}
+
+// getServiceTaskPool returns the dedicated goroutine pool of the service invoked by @rpcInvocation.
+// It returns nil if the service doesn't configure its own pool, and then the shared pool will be used.
+func getServiceTaskPool(rpcInvocation *invocation.RPCInvocation) gxsync.GenericTaskPool {
+	exporter, ok := dubboProtocol.ExporterMap().Load(rpcInvocation.ServiceKey())
+	if !ok {
+		return nil
+	}
+	if dubboExporter, ok := exporter.(*DubboExporter); ok && dubboExporter.TaskPool() != nil {
+		return dubboExporter.TaskPool()
+	}
+	return nil
+}
+
+// setBufferPool applies the pool of the buffers configured by the dubbo protocol config of the process to the dubbo
+// codec, which is shared by all of the dubbo servers and clients rather than configured by the services.
+func setBufferPool() {
+	rootConfig := config.GetRootConfig()
+	if rootConfig == nil || rootConfig.Protocols == nil {
+		return
+	}
+	protocolConf := getBufferPoolProtocolConfig(rootConfig.Protocols)
+	if protocolConf == nil {
+		return
+	}
+	enabled := protocolConf.BufferPool == nil || *protocolConf.BufferPool
+	impl.SetBufferPool(enabled, protocolConf.BufferPoolMaxSize)
+}
+
+// getBufferPoolProtocolConfig returns the dubbo protocol config configuring the pool of the buffers, the protocol
+// configs are keyed by their ids rather than names, and the one with the smallest id wins if several configure it.
+func getBufferPoolProtocolConfig(protocols map[string]*config.ProtocolConfig) *config.ProtocolConfig {
+	ids := make([]string, 0, len(protocols))
+	for id := range protocols {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		protocolConf := protocols[id]
+		if protocolConf == nil || protocolConf.Name != DUBBO {
+			continue
+		}
+		if protocolConf.BufferPool != nil || protocolConf.BufferPoolMaxSize > 0 {
+			return protocolConf
+		}
+	}
+	return nil
+}
+
 func getExchangeClient(url *common.URL) *remoting.ExchangeClient {
 	clientTmp, ok := exchangeClientMap.Load(url.Location)
 	if !ok {
@@ -201,11 +294,13 @@ func getExchangeClient(url *common.URL) *remoting.ExchangeClient {
 			//	RequestTimeout: config.GetConsumerConfig().RequestTimeout,
 			//}), config.GetConsumerConfig().ConnectTimeout, false)
 
-			// todo set by config
+			// the client is shared by the references to the location, so the connect timeout of the first one
+			// applies to all of them
+			connectTimeout := url.GetParamDuration(constant.CONNECT_TIMEOUT_KEY, defaultConnectTimeout)
 			exchangeClientTmp = remoting.NewExchangeClient(url, getty.NewClient(getty.Options{
-				ConnectTimeout: 3 * time.Second,
+				ConnectTimeout: connectTimeout,
 				RequestTimeout: 3 * time.Second,
-			}), 3*time.Second, false)
+			}), connectTimeout, false)
 			// input store
 			if exchangeClientTmp != nil {
 				exchangeClientMap.Store(url.Location, exchangeClientTmp)
@@ -228,7 +323,12 @@ func getExchangeClient(url *common.URL) *remoting.ExchangeClient {
 // Once we decided to transfer more context's key-value, we should change this.
 // now we only support rebuild the tracing context
 func rebuildCtx(inv *invocation.RPCInvocation) context.Context {
-	ctx := context.WithValue(context.Background(), constant.DubboCtxKey("attachment"), inv.Attachments())
+	// the request context is done once the consumer cancels the request or goes away
+	parent, ok := inv.AttributeByKey(constant.REQUEST_CTX_KEY, nil).(context.Context)
+	if !ok {
+		parent = context.Background()
+	}
+	ctx := context.WithValue(parent, constant.DubboCtxKey("attachment"), inv.Attachments())
 
 	// actually, if user do not use any opentracing framework, the err will not be nil.
 	spanCtx, err := opentracing.GlobalTracer().Extract(opentracing.TextMap,
@@ -17,6 +17,39 @@
 
 package dubbo
 
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/config"
+	"dubbo.apache.org/dubbo-go/v3/protocol/dubbo/impl"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+func TestDubboProtocolReferConnectTimeout(t *testing.T) {
+	// the provider is unreachable since nothing listens on the port any longer
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	addr := listener.Addr().String()
+	assert.NoError(t, listener.Close())
+
+	url, err := common.NewURL("dubbo://" + addr + "/com.ikurento.user.UserProvider?" +
+		"interface=com.ikurento.user.UserProvider&timeout=10s&connect.timeout=200ms")
+	assert.NoError(t, err)
+	start := time.Now()
+	assert.Nil(t, GetProtocol().Refer(url))
+	// the connection is tried twice by the exchange client, each within the connect timeout
+	assert.Less(t, int64(time.Since(start)), int64(time.Second))
+}
+
 //
 //import (
 //	"testing"
@@ -150,3 +183,37 @@ package dubbo
 //	invokersLen = len(proto.(*DubboProtocol).Invokers())
 //	assert.Equal(t, 0, invokersLen)
 //}
+
+func TestResultAttachments(t *testing.T) {
+	request := map[string]interface{}{"trace": "t-1", "region": "hangzhou", impl.DUBBO_VERSION_KEY: "2.0.2"}
+	inv := invocation.NewRPCInvocation("GetUser", nil, request)
+
+	// the result shares the attachments of the request, which aren't sent back
+	assert.Nil(t, resultAttachments(inv, request, request))
+
+	// the ones the provider added or changed are sent back
+	attachments := map[string]interface{}{
+		"trace": "t-1", "region": "shanghai", "server.version": "1.2.0", impl.DUBBO_VERSION_KEY: "2.0.2",
+	}
+	assert.Equal(t, map[string]interface{}{
+		"region": "shanghai", "server.version": "1.2.0", impl.DUBBO_VERSION_KEY: "2.0.2",
+	}, resultAttachments(inv, request, attachments))
+}
+
+func TestGetBufferPoolProtocolConfig(t *testing.T) {
+	disabled := false
+	// the protocol configs are keyed by their ids, which aren't necessarily the protocol names
+	protocols := map[string]*config.ProtocolConfig{
+		"triple-protocol": {Name: "tri", BufferPoolMaxSize: 1024},
+		"dubbo-default":   {Name: DUBBO},
+		"dubbo-pooled":    {Name: DUBBO, BufferPool: &disabled},
+		"dubbo-sized":     {Name: DUBBO, BufferPoolMaxSize: 4096},
+	}
+	assert.Equal(t, protocols["dubbo-pooled"], getBufferPoolProtocolConfig(protocols))
+
+	delete(protocols, "dubbo-pooled")
+	assert.Equal(t, protocols["dubbo-sized"], getBufferPoolProtocolConfig(protocols))
+
+	delete(protocols, "dubbo-sized")
+	assert.Nil(t, getBufferPoolProtocolConfig(protocols))
+}
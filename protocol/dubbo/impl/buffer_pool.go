@@ -0,0 +1,137 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package impl
+
+import (
+	"bufio"
+	"bytes"
+	"sync"
+	"sync/atomic"
+)
+
+import (
+	hessian "github.com/apache/dubbo-go-hessian2"
+)
+
+const (
+	// DefaultMaxPooledBufferSize is the max size in bytes of the buffers kept by the pool by default,
+	// the packages larger than it are read by the buffers of their own as before
+	DefaultMaxPooledBufferSize = 1024 * 1024
+	// minPooledBufferSize is the size of the smallest buffers, and the sizes of the others are the powers of 2
+	minPooledBufferSize = 4 * 1024
+)
+
+// bufferPool reuses the buffers reading the dubbo packages and the hessian2 decoders of their bodies across the
+// requests, so that the high qps ones don't allocate them for every package. The encoded packages are handed over
+// to the transport, so they aren't pooled, and their bodies are encoded right after the headers instead of copied.
+type bufferPool struct {
+	enabled bool
+	maxSize int
+	// readers are the pools of the readers by the sizes of their buffers from the smallest one
+	readers []sync.Pool
+}
+
+var (
+	currentBufferPool atomic.Value
+	// decoderPool is shared by the buffer pools since the buffers of the decoders are of the fixed size
+	decoderPool = sync.Pool{
+		New: func() interface{} {
+			return hessian.NewDecoder(nil)
+		},
+	}
+)
+
+func init() {
+	currentBufferPool.Store(newBufferPool(true, DefaultMaxPooledBufferSize))
+}
+
+// SetBufferPool enables or disables the pool of the buffers encoding and decoding the dubbo packages, and the
+// buffers larger than @maxSize aren't kept by it. The default max size is restored if it isn't positive.
+func SetBufferPool(enabled bool, maxSize int) {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxPooledBufferSize
+	}
+	currentBufferPool.Store(newBufferPool(enabled, maxSize))
+}
+
+func getBufferPool() *bufferPool {
+	return currentBufferPool.Load().(*bufferPool)
+}
+
+func newBufferPool(enabled bool, maxSize int) *bufferPool {
+	p := &bufferPool{enabled: enabled, maxSize: maxSize}
+	classes := 0
+	for size := minPooledBufferSize; size <= maxSize; size <<= 1 {
+		classes++
+	}
+	p.readers = make([]sync.Pool, classes)
+	return p
+}
+
+// sizeClass returns the index of the pool of the smallest buffers holding @size bytes and their size,
+// and the index is -1 if the buffers of the size aren't pooled
+func (p *bufferPool) sizeClass(size int) (int, int) {
+	class, classSize := 0, minPooledBufferSize
+	for classSize < size {
+		class++
+		classSize <<= 1
+	}
+	if !p.enabled || class >= len(p.readers) {
+		return -1, size
+	}
+	return class, classSize
+}
+
+// getReader returns a reader whose buffer holds all of the bytes of @data, and whether it's a pooled one
+func (p *bufferPool) getReader(data *bytes.Buffer) (*bufio.Reader, bool) {
+	class, size := p.sizeClass(data.Len())
+	if class < 0 {
+		return bufio.NewReaderSize(data, data.Len()), false
+	}
+	if r, ok := p.readers[class].Get().(*bufio.Reader); ok {
+		r.Reset(data)
+		return r, true
+	}
+	return bufio.NewReaderSize(data, size), true
+}
+
+// putReader returns the reader to the pool, and the bytes read before are discarded so that none of them
+// is read by the next package. The caller mustn't use the reader or the bytes peeked from it afterwards.
+func (p *bufferPool) putReader(r *bufio.Reader) {
+	class, size := p.sizeClass(r.Size())
+	if class < 0 || size != r.Size() {
+		return
+	}
+	r.Reset(nil)
+	p.readers[class].Put(r)
+}
+
+func (p *bufferPool) getDecoder(body []byte) *hessian.Decoder {
+	if !p.enabled {
+		return hessian.NewDecoder(body)
+	}
+	return decoderPool.Get().(*hessian.Decoder).Reset(body)
+}
+
+// putDecoder returns the decoder to the pool, and the references to the body and the decoded values are dropped
+func (p *bufferPool) putDecoder(decoder *hessian.Decoder) {
+	if !p.enabled {
+		return
+	}
+	decoderPool.Put(decoder.Reset(nil))
+}
@@ -0,0 +1,145 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package impl
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+import (
+	hessian "github.com/apache/dubbo-go-hessian2"
+
+	perrors "github.com/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+func marshalRequestPackage(id int64, args ...interface{}) (*bytes.Buffer, error) {
+	pkg := NewDubboPackage(nil)
+	pkg.Header.Type = PackageRequest
+	pkg.Header.SerialID = constant.S_Hessian2
+	pkg.Header.ID = id
+	pkg.SetSerializer(HessianSerializer{})
+	pkg.Service.Path = "com.ikurento.user.UserProvider"
+	pkg.Service.Method = "GetUser"
+	pkg.SetBody(NewRequestPayload(args, map[string]interface{}{}))
+	return pkg.Marshal()
+}
+
+func unmarshalRequestPackage(data *bytes.Buffer) (*DubboPackage, error) {
+	pkg := NewDubboPackage(data)
+	pkg.SetSerializer(HessianSerializer{})
+	pkg.SetBody(make([]interface{}, 7))
+	err := pkg.Unmarshal()
+	pkg.Release()
+	return pkg, err
+}
+
+func TestBufferPoolConcurrentEncodes(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				// the lengths vary so that the longer bodies encoded before leave the bytes in the pooled buffers
+				arg := strings.Repeat(fmt.Sprintf("%d-%d;", i, j), (i*j)%300+1)
+				data, err := marshalRequestPackage(int64(i*1000+j), arg)
+				if !assert.NoError(t, err) {
+					return
+				}
+				pkg, err := unmarshalRequestPackage(data)
+				if !assert.NoError(t, err) {
+					return
+				}
+				assert.Equal(t, int64(i*1000+j), pkg.Header.ID)
+				assert.Equal(t, []interface{}{arg}, pkg.GetBody().(map[string]interface{})[ArgsKey])
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestBufferPoolReuse(t *testing.T) {
+	pool := newBufferPool(true, 64*1024)
+	reader, pooled := pool.getReader(bytes.NewBuffer(bytes.Repeat([]byte{'x'}, 6000)))
+	assert.True(t, pooled)
+	assert.Equal(t, 8*1024, reader.Size())
+	_, err := reader.Peek(6000)
+	assert.NoError(t, err)
+	pool.putReader(reader)
+
+	// the bytes of the former package are never read by the next one
+	reused, pooled := pool.getReader(bytes.NewBuffer(bytes.Repeat([]byte{'y'}, 5000)))
+	assert.True(t, pooled)
+	assert.Equal(t, 8*1024, reused.Size())
+	data, err := reused.Peek(5000)
+	assert.NoError(t, err)
+	assert.Equal(t, bytes.Repeat([]byte{'y'}, 5000), data)
+	_, err = reused.Peek(5001)
+	assert.Error(t, err)
+
+	// the buffers over the max size aren't pooled
+	reader, pooled = pool.getReader(bytes.NewBuffer(make([]byte, 100*1024)))
+	assert.False(t, pooled)
+	assert.Equal(t, 100*1024, reader.Size())
+	reader, pooled = newBufferPool(false, 64*1024).getReader(bytes.NewBuffer(make([]byte, 5000)))
+	assert.False(t, pooled)
+	assert.Equal(t, 5000, reader.Size())
+}
+
+func TestBufferPoolTruncatedPackage(t *testing.T) {
+	data, err := marshalRequestPackage(1, strings.Repeat("x", 3000))
+	assert.NoError(t, err)
+	_, err = unmarshalRequestPackage(data)
+	assert.NoError(t, err)
+
+	// the truncated package waits for the rest of the body instead of reading the stale bytes of the buffer
+	data, err = marshalRequestPackage(2, strings.Repeat("y", 3000))
+	assert.NoError(t, err)
+	_, err = unmarshalRequestPackage(bytes.NewBuffer(data.Bytes()[:data.Len()-100]))
+	assert.Equal(t, hessian.ErrBodyNotEnough, perrors.Cause(err))
+}
+
+func BenchmarkBufferPool(b *testing.B) {
+	defer SetBufferPool(true, 0)
+	arg := bytes.Repeat([]byte{1}, 16*1024)
+	for _, enabled := range []bool{false, true} {
+		b.Run(fmt.Sprintf("pooled=%v", enabled), func(b *testing.B) {
+			SetBufferPool(enabled, 0)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				data, err := marshalRequestPackage(int64(i), arg)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if _, err = unmarshalRequestPackage(data); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -39,7 +39,31 @@ type ProtocolCodec struct {
 	pkgType    PackageType
 	bodyLen    int
 	serializer Serializer
+	serialID   byte
 	headerRead bool
+	// pooled marks the reader taken from the buffer pool, which is returned by release
+	pooled bool
+	// maxBodyLen is the max body length of the frames, it's DEFAULT_LEN if not positive
+	maxBodyLen int
+	// resolver resolves the options of the services of the requests decoded by the codec
+	resolver OptionsResolver
+	// options are the ones of the bodies encoded and decoded by the codec, which are replaced by the ones
+	// of the service once a request is decoded
+	options *Options
+}
+
+// SetMaxBodyLen sets the max body length of the frames encoded and decoded by the codec.
+// The default one is used if @length is not positive.
+func (c *ProtocolCodec) SetMaxBodyLen(length int) {
+	c.maxBodyLen = length
+}
+
+// GetMaxBodyLen returns the max body length of the frames encoded and decoded by the codec
+func (c *ProtocolCodec) GetMaxBodyLen() int {
+	if c.maxBodyLen <= 0 {
+		return DEFAULT_LEN
+	}
+	return c.maxBodyLen
 }
 
 func (c *ProtocolCodec) ReadHeader(header *DubboHeader) error {
@@ -93,6 +117,10 @@ func (c *ProtocolCodec) ReadHeader(header *DubboHeader) error {
 	if header.BodyLen < 0 {
 		return hessian.ErrIllegalPackage
 	}
+	// reject the oversized frames before waiting for their bodies to avoid buffering them
+	if maxLen := c.GetMaxBodyLen(); header.BodyLen > maxLen {
+		return perrors.WithMessagef(ErrBodyTooLarge, "body length %d, max payload %d", header.BodyLen, maxLen)
+	}
 
 	c.pkgType = header.Type
 	c.bodyLen = header.BodyLen
@@ -132,19 +160,21 @@ func (c *ProtocolCodec) Encode(p DubboPackage) ([]byte, error) {
 	if c.serializer == nil {
 		return nil, perrors.New("serializer should not be nil")
 	}
+	// the bodies are compressed by the options of the service, which are the ones of the request for the response
+	compressor := c.GetOptions().compression
 	header := p.Header
 	switch header.Type {
 	case PackageHeartbeat:
 		if header.ResponseStatus == Zero {
-			return packRequest(p, c.serializer)
+			return packRequest(p, c.serializer, c.GetMaxBodyLen(), compressor)
 		}
-		return packResponse(p, c.serializer)
+		return packResponse(p, c.serializer, c.GetMaxBodyLen(), compressor)
 
 	case PackageRequest, PackageRequest_TwoWay:
-		return packRequest(p, c.serializer)
+		return packRequest(p, c.serializer, c.GetMaxBodyLen(), compressor)
 
 	case PackageResponse:
-		return packResponse(p, c.serializer)
+		return packResponse(p, c.serializer, c.GetMaxBodyLen(), compressor)
 
 	default:
 		return nil, perrors.Errorf("Unrecognized message type: %v", header.Type)
@@ -161,6 +191,11 @@ func (c *ProtocolCodec) Decode(p *DubboPackage) error {
 	if err != nil {
 		return err
 	}
+	if isCompressed(p.Header.SerialID, body) {
+		if body, err = decompress(body, c.GetMaxBodyLen()); err != nil {
+			return err
+		}
+	}
 	if p.IsResponseWithException() {
 		logger.Infof("response with exception: %+v", p.Header)
 		decoder := hessian.NewDecoder(body)
@@ -175,12 +210,31 @@ func (c *ProtocolCodec) Decode(p *DubboPackage) error {
 		// heartbeat no need to unmarshal contents
 		return nil
 	}
+	if p.Header.SerialID != c.serialID {
+		// the body is decoded by the serialization it's encoded with, only the unavailable ones are mismatched
+		serializer, err := GetSerializerById(p.Header.SerialID)
+		if err != nil {
+			return &SerializationMismatchError{LocalID: c.serialID, RemoteID: p.Header.SerialID}
+		}
+		c.serializer, c.serialID = serializer, p.Header.SerialID
+	}
 	if c.serializer == nil {
 		return perrors.New("Codec serializer is nil")
 	}
 	if p.IsResponse() {
+		pending := remoting.GetPendingResponse(remoting.SequenceType(p.Header.ID))
+		if pending == nil {
+			// the invocation has been cancelled or timed out, the late response is dropped without its body
+			logger.Warnf("no pending response of the response %d, its body is skipped", p.Header.ID)
+			p.Body = &ResponsePayload{}
+			return nil
+		}
 		p.Body = &ResponsePayload{
-			RspObj: remoting.GetPendingResponse(remoting.SequenceType(p.Header.ID)).Reply,
+			RspObj:            pending.Reply,
+			LenientCollection: pending.LenientCollection,
+		}
+		if opts, ok := pending.CodecOptions.(*Options); ok {
+			c.options = opts
 		}
 	}
 	return c.serializer.Unmarshal(body, p)
@@ -190,11 +244,81 @@ func (c *ProtocolCodec) SetSerializer(serializer Serializer) {
 	c.serializer = serializer
 }
 
-func packRequest(p DubboPackage, serializer Serializer) ([]byte, error) {
-	var (
-		byteArray []byte
-		pkgLen    int
-	)
+// SetOptionsResolver sets the resolver of the options of the services of the requests decoded by the codec
+func (c *ProtocolCodec) SetOptionsResolver(resolver OptionsResolver) {
+	c.resolver = resolver
+}
+
+// SetOptions sets the options of the bodies encoded and decoded by the codec
+func (c *ProtocolCodec) SetOptions(opts *Options) {
+	c.options = opts
+}
+
+// GetOptions returns the options of the bodies encoded and decoded by the codec, the default ones are returned
+// if they aren't set.
+func (c *ProtocolCodec) GetOptions() *Options {
+	if c == nil || c.options == nil {
+		return defaultOptions
+	}
+	return c.options
+}
+
+// serviceOptions returns the options of the service of @path and @version, the ones of the codec are returned
+// if the service has no options of its own.
+func (c *ProtocolCodec) serviceOptions(path, version interface{}) *Options {
+	if c == nil || c.resolver == nil {
+		return c.GetOptions()
+	}
+	p, _ := path.(string)
+	v, _ := version.(string)
+	if opts := c.resolver(p, v); opts != nil {
+		return opts
+	}
+	return c.GetOptions()
+}
+
+func (c *ProtocolCodec) release() {
+	if c.pooled {
+		getBufferPool().putReader(c.reader)
+		c.reader, c.pooled = nil, false
+	}
+}
+
+// frameSerializer is implemented by the serializers encoding the bodies right after the headers of the frames,
+// so that the bodies aren't copied into the frames
+type frameSerializer interface {
+	marshalFrame(header []byte, p DubboPackage) ([]byte, error)
+}
+
+// packBody appends the body of @p marshalled by @serializer and compressed by @compressor to the frame @header,
+// and sets the length of the body, which can't exceed @maxLen before it's compressed
+func packBody(header []byte, p DubboPackage, serializer Serializer, maxLen int, compressor *compression) ([]byte, error) {
+	var frame []byte
+	if s, ok := serializer.(frameSerializer); ok {
+		var err error
+		if frame, err = s.marshalFrame(header, p); err != nil {
+			return nil, err
+		}
+	} else {
+		body, err := serializer.Marshal(p)
+		if err != nil {
+			return nil, err
+		}
+		frame = make([]byte, len(header)+len(body))
+		copy(frame, header)
+		copy(frame[len(header):], body)
+	}
+	pkgLen := len(frame) - len(header)
+	if pkgLen > maxLen {
+		return nil, perrors.Errorf("Data length %d too large, max payload %d", pkgLen, maxLen)
+	}
+	frame = compressor.compress(frame)
+	binary.BigEndian.PutUint32(frame[12:], uint32(len(frame)-len(header)))
+	return frame, nil
+}
+
+func packRequest(p DubboPackage, serializer Serializer, maxLen int, compressor *compression) ([]byte, error) {
+	var byteArray []byte
 
 	header := p.Header
 
@@ -220,25 +344,15 @@ func packRequest(p DubboPackage, serializer Serializer) ([]byte, error) {
 	//////////////////////////////////////////
 	// body
 	//////////////////////////////////////////
-	if p.IsHeartBeat() {
-		byteArray = append(byteArray, byte('N'))
-		pkgLen = 1
-	} else {
-		body, err := serializer.Marshal(p)
-		if err != nil {
-			return nil, err
-		}
-		pkgLen = len(body)
-		if pkgLen > int(DEFAULT_LEN) { // 8M
-			return nil, perrors.Errorf("Data length %d too large, max payload %d", pkgLen, DEFAULT_LEN)
-		}
-		byteArray = append(byteArray, body...)
+	if !p.IsHeartBeat() {
+		return packBody(byteArray, p, serializer, maxLen, compressor)
 	}
-	binary.BigEndian.PutUint32(byteArray[12:], uint32(pkgLen))
+	byteArray = append(byteArray, byte('N'))
+	binary.BigEndian.PutUint32(byteArray[12:], 1)
 	return byteArray, nil
 }
 
-func packResponse(p DubboPackage, serializer Serializer) ([]byte, error) {
+func packResponse(p DubboPackage, serializer Serializer, maxLen int, compressor *compression) ([]byte, error) {
 	var byteArray []byte
 	header := p.Header
 	hb := p.IsHeartBeat()
@@ -260,19 +374,7 @@ func packResponse(p DubboPackage, serializer Serializer) ([]byte, error) {
 	binary.BigEndian.PutUint64(byteArray[4:], uint64(header.ID))
 
 	// body
-	body, err := serializer.Marshal(p)
-	if err != nil {
-		return nil, err
-	}
-
-	pkgLen := len(body)
-	if pkgLen > int(DEFAULT_LEN) { // 8M
-		return nil, perrors.Errorf("Data length %d too large, max payload %d", pkgLen, DEFAULT_LEN)
-	}
-	// byteArray{body length}
-	binary.BigEndian.PutUint32(byteArray[12:], uint32(pkgLen))
-	byteArray = append(byteArray, body...)
-	return byteArray, nil
+	return packBody(byteArray, p, serializer, maxLen, compressor)
 }
 
 func NewDubboCodec(reader *bufio.Reader) *ProtocolCodec {
@@ -283,5 +385,6 @@ func NewDubboCodec(reader *bufio.Reader) *ProtocolCodec {
 		bodyLen:    0,
 		headerRead: false,
 		serializer: s,
+		serialID:   constant.S_Hessian2,
 	}
 }
@@ -18,11 +18,17 @@
 package impl
 
 import (
+	"bytes"
+	"encoding/binary"
 	"testing"
 	"time"
 )
 
 import (
+	hessian "github.com/apache/dubbo-go-hessian2"
+
+	perrors "github.com/pkg/errors"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -87,3 +93,59 @@ func TestDubboPackage_MarshalAndUnmarshal(t *testing.T) {
 	}
 	assert.Equal(t, tmpData, reassembleBody["attachments"])
 }
+
+func TestDubboPackage_UnmarshalOversizedBody(t *testing.T) {
+	// only the header is sent, which declares a body much larger than the max payload
+	header := DubboRequestHeaderBytesTwoWay
+	header[2] |= constant.S_Hessian2
+	binary.BigEndian.PutUint32(header[12:], 1<<30)
+
+	pkg := NewDubboPackage(bytes.NewBuffer(header[:]))
+	err := pkg.Unmarshal()
+	assert.Error(t, err)
+	assert.Equal(t, ErrBodyTooLarge, perrors.Cause(err))
+	assert.NotEqual(t, hessian.ErrBodyNotEnough, perrors.Cause(err))
+
+	// the frame is waited for once the max payload is raised
+	pkg = NewDubboPackage(bytes.NewBuffer(header[:]))
+	pkg.Codec.SetMaxBodyLen(1 << 30)
+	assert.Equal(t, 1<<30, pkg.Codec.GetMaxBodyLen())
+	err = pkg.Unmarshal()
+	assert.Equal(t, hessian.ErrBodyNotEnough, perrors.Cause(err))
+}
+
+// recordingSerializer records the bodies it decodes
+type recordingSerializer struct {
+	bodies [][]byte
+}
+
+func (s *recordingSerializer) Marshal(DubboPackage) ([]byte, error) {
+	return nil, nil
+}
+
+func (s *recordingSerializer) Unmarshal(body []byte, _ *DubboPackage) error {
+	s.bodies = append(s.bodies, append([]byte(nil), body...))
+	return nil
+}
+
+func TestDubboPackage_UnmarshalBySerialID(t *testing.T) {
+	body := []byte{0x0a, 0x05, 'h', 'e', 'l', 'l', 'o'}
+	header := DubboRequestHeaderBytesTwoWay
+	header[2] |= constant.S_Proto
+	binary.BigEndian.PutUint32(header[12:], uint32(len(body)))
+	data := append(header[:], body...)
+
+	// the package is mismatched without the serialization it's encoded with
+	pkg := NewDubboPackage(bytes.NewBuffer(data))
+	err := pkg.Unmarshal()
+	mismatch, ok := err.(*SerializationMismatchError)
+	assert.True(t, ok)
+	assert.Equal(t, constant.S_Proto, mismatch.RemoteID)
+
+	serializer := &recordingSerializer{}
+	SetSerializer(constant.PROTOBUF_SERIALIZATION, serializer)
+	defer delete(serializers, constant.PROTOBUF_SERIALIZATION)
+	pkg = NewDubboPackage(bytes.NewBuffer(data))
+	assert.NoError(t, pkg.Unmarshal())
+	assert.Equal(t, [][]byte{body}, serializer.bodies)
+}
@@ -0,0 +1,100 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package impl
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"sync"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+// DefaultCompressMinSize is the min body size in bytes of the frames compressed by default
+const DefaultCompressMinSize = 1024
+
+// gzipMagic starts the gzip streams, which no hessian2 body starts with, since a body starts with either
+// the version string of a request, the int type of a response or the null of a heartbeat
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// compression compresses the bodies of the frames over the min size, the nil one compresses nothing
+type compression struct {
+	minSize int
+}
+
+var gzipWriters = sync.Pool{
+	New: func() interface{} {
+		return gzip.NewWriter(nil)
+	},
+}
+
+// compress returns the @frame with its hessian2 body compressed if the body is over the min size,
+// and the frame is left intact if the compressed body isn't smaller
+func (c *compression) compress(frame []byte) []byte {
+	body := frame[HEADER_LENGTH:]
+	if c == nil || len(body) <= c.minSize || frame[2]&SERIAL_MASK != constant.S_Hessian2 {
+		return frame
+	}
+	buf := bytes.NewBuffer(make([]byte, 0, len(frame)))
+	buf.Write(frame[:HEADER_LENGTH])
+	w := gzipWriters.Get().(*gzip.Writer)
+	defer func() {
+		// the pooled writer doesn't hold the buffer
+		w.Reset(ioutil.Discard)
+		gzipWriters.Put(w)
+	}()
+	w.Reset(buf)
+	if _, err := w.Write(body); err != nil {
+		return frame
+	}
+	if err := w.Close(); err != nil || buf.Len() >= len(frame) {
+		return frame
+	}
+	return buf.Bytes()
+}
+
+// isCompressed tells whether the @body of the frame serialized by @serialID is compressed, only the hessian2
+// bodies are compressed by their peers
+func isCompressed(serialID byte, body []byte) bool {
+	return serialID == constant.S_Hessian2 && bytes.HasPrefix(body, gzipMagic)
+}
+
+// decompress returns the decompressed @body, which is rejected if it's over @maxLen bytes
+func decompress(body []byte, maxLen int) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, perrors.WithStack(err)
+	}
+	defer r.Close()
+	decompressed, err := ioutil.ReadAll(io.LimitReader(r, int64(maxLen)+1))
+	if err != nil {
+		return nil, perrors.WithStack(err)
+	}
+	if len(decompressed) > maxLen {
+		return nil, perrors.WithMessagef(ErrBodyTooLarge, "decompressed body length over max payload %d", maxLen)
+	}
+	return decompressed, nil
+}
@@ -0,0 +1,112 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package impl
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+// marshalCompressedRequest marshals the request package of @arg with the options compressing the bodies
+// over @minSize bytes
+func marshalCompressedRequest(minSize int, id int64, arg interface{}) (*bytes.Buffer, error) {
+	pkg := NewDubboPackage(nil)
+	pkg.Header.Type = PackageRequest
+	pkg.Header.SerialID = constant.S_Hessian2
+	pkg.Header.ID = id
+	pkg.SetSerializer(HessianSerializer{})
+	opts := NewOptions()
+	opts.SetCompression(minSize)
+	pkg.Codec.SetOptions(opts)
+	pkg.Service.Path = "com.ikurento.user.UserProvider"
+	pkg.Service.Method = "GetUser"
+	pkg.SetBody(NewRequestPayload([]interface{}{arg}, map[string]interface{}{}))
+	return pkg.Marshal()
+}
+
+func TestCompression(t *testing.T) {
+	// the small payload stays uncompressed
+	small := strings.Repeat("x", 100)
+	data, err := marshalCompressedRequest(2048, 1, small)
+	assert.NoError(t, err)
+	assert.False(t, bytes.HasPrefix(data.Bytes()[HEADER_LENGTH:], gzipMagic))
+	pkg, err := unmarshalRequestPackage(data)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{small}, pkg.GetBody().(map[string]interface{})[ArgsKey])
+
+	// while the large one is compressed without touching the header flags
+	large := strings.Repeat("dubbo-go;", 1000)
+	data, err = marshalCompressedRequest(2048, 2, large)
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(data.Bytes()[HEADER_LENGTH:], gzipMagic))
+	assert.Equal(t, []byte{MAGIC_HIGH, MAGIC_LOW, FLAG_REQUEST | constant.S_Hessian2, Zero}, data.Bytes()[:4])
+	assert.Less(t, data.Len(), len(large))
+	pkg, err = unmarshalRequestPackage(data)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), pkg.Header.ID)
+	assert.Equal(t, []interface{}{large}, pkg.GetBody().(map[string]interface{})[ArgsKey])
+
+	// the status byte of the compressed response is left intact
+	opts := NewOptions()
+	opts.SetCompression(0)
+	rsp := NewDubboPackage(nil)
+	rsp.Header.Type = PackageResponse
+	rsp.Header.SerialID = constant.S_Hessian2
+	rsp.Header.ID = 3
+	rsp.Header.ResponseStatus = Response_OK
+	rsp.SetSerializer(HessianSerializer{})
+	rsp.Codec.SetOptions(opts)
+	rsp.SetBody(&ResponsePayload{RspObj: large})
+	data, err = rsp.Marshal()
+	assert.NoError(t, err)
+	assert.Equal(t, Response_OK, data.Bytes()[3])
+	assert.True(t, bytes.HasPrefix(data.Bytes()[HEADER_LENGTH:], gzipMagic))
+	rsp = NewDubboPackage(data)
+	assert.NoError(t, rsp.ReadHeader())
+	assert.Equal(t, Response_OK, rsp.Header.ResponseStatus)
+	assert.False(t, rsp.IsResponseWithException())
+
+	// the options without the compression compress nothing
+	assert.True(t, opts.CompressionEnabled())
+	assert.False(t, opts.WithoutCompression().CompressionEnabled())
+	assert.True(t, opts.CompressionEnabled())
+	data, err = marshalRequestPackage(4, large)
+	assert.NoError(t, err)
+	assert.False(t, bytes.HasPrefix(data.Bytes()[HEADER_LENGTH:], gzipMagic))
+}
+
+func TestDecompressOverMaxPayload(t *testing.T) {
+	frame := append(make([]byte, HEADER_LENGTH), strings.Repeat("x", 4096)...)
+	frame[2] = constant.S_Hessian2
+	frame = (&compression{}).compress(frame)
+	assert.True(t, isCompressed(constant.S_Hessian2, frame[HEADER_LENGTH:]))
+	_, err := decompress(frame[HEADER_LENGTH:], 1024)
+	assert.ErrorIs(t, err, ErrBodyTooLarge)
+	body, err := decompress(frame[HEADER_LENGTH:], 4096)
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Repeat("x", 4096), string(body))
+}
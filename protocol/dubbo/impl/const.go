@@ -163,6 +163,8 @@ const (
 	Response_SERVICE_ERROR     byte = 70
 	Response_SERVER_ERROR      byte = 80
 	Response_CLIENT_ERROR      byte = 90
+	// Response_SERVER_THREADPOOL_EXHAUSTED_ERROR is replied once the dispatch queue of the server is full
+	Response_SERVER_THREADPOOL_EXHAUSTED_ERROR byte = 100
 
 	// According to "java dubbo" There are two cases of response:
 	// 		1. with attachments
@@ -228,6 +230,10 @@ var (
 	ErrBodyNotEnough   = errors.New("body buffer too short")
 	ErrJavaException   = errors.New("got java exception")
 	ErrIllegalPackage  = errors.New("illegal package!")
+	ErrBodyTooLarge    = errors.New("body length exceeds the max payload")
+	ErrClassNotAllowed = errors.New("class is not allowed to deserialize")
+	ErrSizeExceeded    = errors.New("size exceeds the limit of the deserialization")
+	ErrUnknownField    = errors.New("field is unknown to the type of the deserialization")
 )
 
 // DescRegex ...
@@ -0,0 +1,94 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package impl
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+import (
+	hessian "github.com/apache/dubbo-go-hessian2"
+
+	perrors "github.com/pkg/errors"
+)
+
+// enumTypes maps the names of the registered Java enum classes to their Go types
+var enumTypes sync.Map
+
+// RegisterEnum maps the Go type of @e to the Java enum class e.JavaClassName() for the hessian2 serialization,
+// and the constants are transferred by their names as Java does rather than the ordinals. The Go type is an integer
+// one whose values are the ordinals of the constants, e.g. type Color int32, String returns the name of a constant
+// and EnumValue returns the ordinal of a name, or hessian.InvalidJavaEnum if it's unknown.
+// The constants decoded as the arguments of the requests and the responses resolve to the Go type, and so do the
+// fields of the POJOs, while the ones in the untyped containers are left as hessian.JavaEnum.
+func RegisterEnum(e hessian.POJOEnum) error {
+	typ := reflect.TypeOf(e)
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+	default:
+		return perrors.Errorf("the enum type %s of the java class %s isn't an integer one", typ, e.JavaClassName())
+	}
+	if registered, loaded := enumTypes.LoadOrStore(e.JavaClassName(), typ); loaded && registered != typ {
+		return perrors.Errorf("the java class %s is mapped to the enum type %s already", e.JavaClassName(), registered)
+	}
+	hessian.RegisterJavaEnum(e)
+	return nil
+}
+
+// resolveEnum converts the constant of a Java enum decoded by hessian2 into @typ if it's a registered enum type
+func resolveEnum(v interface{}, typ reflect.Type) (interface{}, bool) {
+	constant, ok := v.(hessian.JavaEnum)
+	if !ok {
+		return v, false
+	}
+	if e, ok := reflect.Zero(typ).Interface().(hessian.POJOEnum); ok {
+		if registered, ok := enumTypes.Load(e.JavaClassName()); ok && registered == typ {
+			return reflect.ValueOf(constant).Convert(typ).Interface(), true
+		}
+	}
+	return v, false
+}
+
+// reflectEnum sets the constant of a Java enum decoded by hessian2 into @out if it points to a registered
+// enum type, which hessian.ReflectResponse can't convert into
+func reflectEnum(v interface{}, out interface{}) bool {
+	value := reflect.ValueOf(out)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return false
+	}
+	constant, ok := resolveEnum(v, value.Type().Elem())
+	if ok {
+		value.Elem().Set(reflect.ValueOf(constant))
+	}
+	return ok
+}
+
+// resolveEnumArg converts the constant decoded as the argument of the type @desc, e.g. Lorg/apache/Color;,
+// into the Go type registered for the Java enum class
+func resolveEnumArg(v interface{}, desc string) interface{} {
+	if _, ok := v.(hessian.JavaEnum); !ok || !strings.HasPrefix(desc, "L") || !strings.HasSuffix(desc, ";") {
+		return v
+	}
+	class := strings.ReplaceAll(desc[1:len(desc)-1], "/", ".")
+	if typ, ok := enumTypes.Load(class); ok {
+		v, _ = resolveEnum(v, typ.(reflect.Type))
+	}
+	return v
+}
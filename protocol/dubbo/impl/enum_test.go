@@ -0,0 +1,101 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package impl
+
+import (
+	"testing"
+)
+
+import (
+	hessian "github.com/apache/dubbo-go-hessian2"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testColor int32
+
+const (
+	testColorRed testColor = iota
+	testColorGreen
+	testColorBlue
+)
+
+var testColorNames = []string{"RED", "GREEN", "BLUE"}
+
+func (testColor) JavaClassName() string {
+	return "org.apache.dubbo.Color"
+}
+
+func (c testColor) String() string {
+	return testColorNames[c]
+}
+
+func (testColor) EnumValue(name string) hessian.JavaEnum {
+	for i, n := range testColorNames {
+		if n == name {
+			return hessian.JavaEnum(i)
+		}
+	}
+	return hessian.InvalidJavaEnum
+}
+
+type testPalette struct {
+	Primary testColor
+}
+
+func (testPalette) JavaClassName() string {
+	return "org.apache.dubbo.Palette"
+}
+
+func init() {
+	if err := RegisterEnum(testColorRed); err != nil {
+		panic(err)
+	}
+	hessian.RegisterPOJO(&testPalette{})
+}
+
+func TestRegisterEnum(t *testing.T) {
+	assert.NoError(t, RegisterEnum(testColorBlue))
+	// the constants of the other kinds can't be the ordinals
+	assert.Error(t, RegisterEnum(struct{ testColor }{}))
+}
+
+func TestEnumJavaResponse(t *testing.T) {
+	// the body of the response of Color.GREEN written by the hessian2 serialization of Java, the constant is
+	// the object of the enum class with the only field name
+	body := []byte("\x91" + "C\x16org.apache.dubbo.Color\x91\x04name" + "\x60\x05GREEN")
+	var reply testColor
+	pkg := NewDubboPackage(nil)
+	pkg.Header.Type = PackageResponse
+	pkg.Header.ResponseStatus = Response_OK
+	pkg.SetBody(NewResponsePayload(&reply, nil, nil))
+	assert.NoError(t, HessianSerializer{}.Unmarshal(body, pkg))
+	assert.Equal(t, testColorGreen, reply)
+
+	var blue testColor
+	roundTripResponse(t, defaultOptions, testColorBlue, &blue)
+	assert.Equal(t, testColorBlue, blue)
+}
+
+func TestEnumRequest(t *testing.T) {
+	body := roundTripRequest(t, defaultOptions, testColorBlue, &testPalette{Primary: testColorGreen})
+	assert.Equal(t, "Lorg/apache/dubbo/Color;Lorg/apache/dubbo/Palette;", body["argsTypes"])
+	args := body["args"].([]interface{})
+	assert.Equal(t, testColorBlue, args[0])
+	assert.Equal(t, testColorGreen, args[1].(*testPalette).Primary)
+}
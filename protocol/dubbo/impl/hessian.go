@@ -27,6 +27,7 @@ import (
 
 import (
 	hessian "github.com/apache/dubbo-go-hessian2"
+	"github.com/apache/dubbo-go-hessian2/java8_time"
 	"github.com/apache/dubbo-go-hessian2/java_exception"
 
 	perrors "github.com/pkg/errors"
@@ -41,7 +42,17 @@ import (
 type HessianSerializer struct{}
 
 func (h HessianSerializer) Marshal(p DubboPackage) ([]byte, error) {
+	return marshal(hessian.NewEncoder(), p)
+}
+
+// marshalFrame marshals the body following @header, and returns the whole frame
+func (h HessianSerializer) marshalFrame(header []byte, p DubboPackage) ([]byte, error) {
 	encoder := hessian.NewEncoder()
+	encoder.Append(header)
+	return marshal(encoder, p)
+}
+
+func marshal(encoder *hessian.Encoder, p DubboPackage) ([]byte, error) {
 	if p.IsRequest() {
 		return marshalRequest(encoder, p)
 	}
@@ -94,7 +105,7 @@ func marshalResponse(encoder *hessian.Encoder, p DubboPackage) ([]byte, error) {
 					_ = encoder.Encode(resNullValue)
 				} else {
 					_ = encoder.Encode(resValue)
-					_ = encoder.Encode(response.RspObj) // result
+					_ = encoder.Encode(p.Codec.GetOptions().mapping.encodeValue(response.RspObj)) // result
 				}
 			}
 
@@ -129,6 +140,7 @@ func marshalRequest(encoder *hessian.Encoder, p DubboPackage) ([]byte, error) {
 		logger.Infof("request args are: %+v", request.Params)
 		return nil, perrors.Errorf("@params is not of type: []interface{}")
 	}
+	args = p.Codec.GetOptions().mapping.encodeValues(args)
 	types, err := getArgsTypeList(args)
 	if err != nil {
 		return nil, perrors.Wrapf(err, " PackRequest(args:%+v)", args)
@@ -198,7 +210,9 @@ func unmarshalRequestBody(body []byte, p *DubboPackage) error {
 	if p.Body == nil {
 		p.SetBody(make([]interface{}, 7))
 	}
-	decoder := hessian.NewDecoder(body)
+	pool := getBufferPool()
+	decoder := pool.getDecoder(body)
+	defer pool.putDecoder(decoder)
 	var (
 		err                                                     error
 		dubboVersion, target, serviceVersion, method, argsTypes interface{}
@@ -226,6 +240,19 @@ func unmarshalRequestBody(body []byte, p *DubboPackage) error {
 	}
 	req[2] = serviceVersion
 
+	// the strings above are decoded before the scan to resolve the options of the service, they are
+	// bounded by the body anyway
+	opts := p.Codec.serviceOptions(target, serviceVersion)
+	if p.Codec != nil {
+		// the response of the request is encoded with the options of the service as well
+		p.Codec.SetOptions(opts)
+	}
+	check := opts.check
+	unknown, err := opts.scan(body, check)
+	if err != nil {
+		return err
+	}
+
 	method, err = decoder.Decode()
 	if err != nil {
 		return perrors.WithStack(err)
@@ -239,6 +266,9 @@ func unmarshalRequestBody(body []byte, p *DubboPackage) error {
 	req[4] = argsTypes
 
 	ats := hessian.DescRegex.FindAllString(argsTypes.(string), -1)
+	if err = check.checkArgsTypes(target, method, ats); err != nil {
+		return err
+	}
 	var arg interface{}
 	for i := 0; i < len(ats); i++ {
 		arg, err = decoder.Decode()
@@ -247,6 +277,11 @@ func unmarshalRequestBody(body []byte, p *DubboPackage) error {
 		}
 		args = append(args, arg)
 	}
+	// the arguments follow the dubbo version, the path, the version, the method and the types of the arguments
+	unknown.attach(5, args)
+	for i := range args {
+		args[i] = opts.mapping.decodeValue(resolveEnumArg(args[i], ats[i]))
+	}
 	req[5] = args
 
 	attachments, err := decoder.Decode()
@@ -268,7 +303,14 @@ func unmarshalRequestBody(body []byte, p *DubboPackage) error {
 }
 
 func unmarshalResponseBody(body []byte, p *DubboPackage) error {
-	decoder := hessian.NewDecoder(body)
+	opts := p.Codec.GetOptions()
+	unknown, err := opts.scan(body, nil)
+	if err != nil {
+		return err
+	}
+	pool := getBufferPool()
+	decoder := pool.getDecoder(body)
+	defer pool.putDecoder(decoder)
 	rspType, err := decoder.Decode()
 	if p.Body == nil {
 		p.SetBody(&ResponsePayload{})
@@ -321,7 +363,19 @@ func unmarshalResponseBody(body []byte, p *DubboPackage) error {
 				return perrors.Errorf("get wrong attachments: %+v", attachments)
 			}
 		}
+		// the response value follows the type of the response
+		unknown.attach(1, []interface{}{rsp})
 
+		if reflectEnum(rsp, response.RspObj) {
+			return nil
+		}
+		rsp = opts.mapping.decodeValue(rsp)
+		if response.LenientCollection {
+			if skipped, ok := reflectCollection(rsp, response.RspObj); ok {
+				response.SkippedElements = skipped
+				return nil
+			}
+		}
 		return perrors.WithStack(hessian.ReflectResponse(rsp, response.RspObj))
 
 	case RESPONSE_NULL_VALUE, RESPONSE_NULL_VALUE_WITH_ATTACHMENTS:
@@ -463,6 +517,9 @@ func getArgType(v interface{}) string {
 		return "java.util.Date"
 	case []time.Time:
 		return "[Ljava.util.Date"
+	case java8_time.ZonedDateTime, *java8_time.ZonedDateTime:
+		// the class of the pojo is the serialization handle of it
+		return zonedDateTimeClass
 	case float32:
 		return "F"
 	case []float32:
@@ -0,0 +1,109 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package impl
+
+import (
+	"reflect"
+)
+
+import (
+	hessian "github.com/apache/dubbo-go-hessian2"
+
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+)
+
+// reflectCollection copies the elements of the decoded list or map @in to the slice or map @out points to, and
+// skips the ones which the element types of @out can't hold rather than failing the whole collection. The elements
+// are converted as hessian.ReflectResponse converts the whole response, so only the ones failing it are skipped.
+// It returns the number of the skipped elements, and false if @in and @out aren't the collections of the same kind,
+// which are left to the strict reflection.
+func reflectCollection(in interface{}, out interface{}) (int, bool) {
+	outValue := reflect.ValueOf(out)
+	if in == nil || outValue.Kind() != reflect.Ptr || outValue.IsNil() {
+		return 0, false
+	}
+	inValue, outValue := reflect.ValueOf(in), outValue.Elem()
+
+	var skipped int
+	switch {
+	case (inValue.Kind() == reflect.Slice || inValue.Kind() == reflect.Array) && outValue.Kind() == reflect.Slice:
+		elemType := outValue.Type().Elem()
+		result := reflect.MakeSlice(outValue.Type(), 0, inValue.Len())
+		for i := 0; i < inValue.Len(); i++ {
+			elem, err := convertElement(inValue.Index(i), elemType)
+			if err != nil {
+				logger.Debugf("[Lenient Collection] skip the element %d: %v", i, err)
+				skipped++
+				continue
+			}
+			result = reflect.Append(result, elem)
+		}
+		outValue.Set(result)
+	case inValue.Kind() == reflect.Map && outValue.Kind() == reflect.Map:
+		keyType, elemType := outValue.Type().Key(), outValue.Type().Elem()
+		result := reflect.MakeMapWithSize(outValue.Type(), inValue.Len())
+		iter := inValue.MapRange()
+		for iter.Next() {
+			key, err := convertElement(iter.Key(), keyType)
+			var elem reflect.Value
+			if err == nil {
+				elem, err = convertElement(iter.Value(), elemType)
+			}
+			if err != nil {
+				logger.Debugf("[Lenient Collection] skip the entry of key %v: %v", iter.Key(), err)
+				skipped++
+				continue
+			}
+			result.SetMapIndex(key, elem)
+		}
+		outValue.Set(result)
+	default:
+		return 0, false
+	}
+	if skipped > 0 {
+		logger.Warnf("[Lenient Collection] skip %d of the %d elements which %s can't hold",
+			skipped, inValue.Len(), outValue.Type())
+	}
+	return skipped, true
+}
+
+// convertElement unwraps the interface @v and converts it to @typ by hessian.ReflectResponse, and the nil ones are
+// the zero values of @typ
+func convertElement(v reflect.Value, typ reflect.Type) (value reflect.Value, err error) {
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return reflect.Zero(typ), nil
+	}
+	out := reflect.New(typ)
+	defer func() {
+		// the values which can't be converted make the reflection panic
+		if e := recover(); e != nil {
+			err = perrors.Errorf("%s can't hold %s: %v", typ, v.Type(), e)
+		}
+	}()
+	if err = hessian.ReflectResponse(v.Interface(), out.Interface()); err != nil {
+		return v, err
+	}
+	return out.Elem(), nil
+}
@@ -0,0 +1,114 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package impl
+
+import (
+	"testing"
+)
+
+import (
+	hessian "github.com/apache/dubbo-go-hessian2"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type lenientUser struct {
+	Name string
+}
+
+func (lenientUser) JavaClassName() string {
+	return "com.ikurento.user.LenientUser"
+}
+
+// decodeCollection encodes the response of @result and decodes it into @reply with the lenient mode or not
+func decodeCollection(t *testing.T, result interface{}, reply interface{}, lenient bool) (*ResponsePayload, error) {
+	pkg := NewDubboPackage(nil)
+	pkg.Header.Type = PackageResponse
+	pkg.Header.ResponseStatus = Response_OK
+	pkg.SetBody(NewResponsePayload(result, nil, map[string]interface{}{}))
+	data, err := HessianSerializer{}.Marshal(*pkg)
+	assert.NoError(t, err)
+
+	payload := NewResponsePayload(reply, nil, nil)
+	payload.LenientCollection = lenient
+	pkg.SetBody(payload)
+	return payload, HessianSerializer{}.Unmarshal(data, pkg)
+}
+
+// decodeMalformedCollection decodes the response of a list whose bytes are truncated into @reply leniently
+func decodeMalformedCollection(t *testing.T, reply interface{}) (*ResponsePayload, error) {
+	pkg := NewDubboPackage(nil)
+	pkg.Header.Type = PackageResponse
+	pkg.Header.ResponseStatus = Response_OK
+	pkg.SetBody(NewResponsePayload([]interface{}{"Alex", "Bob"}, nil, map[string]interface{}{}))
+	data, err := HessianSerializer{}.Marshal(*pkg)
+	assert.NoError(t, err)
+
+	payload := NewResponsePayload(reply, nil, nil)
+	payload.LenientCollection = true
+	pkg.SetBody(payload)
+	return payload, HessianSerializer{}.Unmarshal(data[:len(data)-4], pkg)
+}
+
+func TestLenientCollection(t *testing.T) {
+	hessian.RegisterPOJO(&lenientUser{})
+	defer hessian.UnRegisterPOJOs(&lenientUser{})
+
+	// the list with a malformed element fails as a whole by default
+	users := []interface{}{&lenientUser{Name: "Alex"}, "malformed", &lenientUser{Name: "Bob"}}
+	var reply []*lenientUser
+	_, err := decodeCollection(t, users, &reply, false)
+	assert.Error(t, err)
+
+	payload, err := decodeCollection(t, users, &reply, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []*lenientUser{{Name: "Alex"}, {Name: "Bob"}}, reply)
+	assert.Equal(t, 1, payload.SkippedElements)
+
+	// the null elements are kept
+	payload, err = decodeCollection(t, []interface{}{&lenientUser{Name: "Alex"}, nil}, &reply, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []*lenientUser{{Name: "Alex"}, nil}, reply)
+	assert.Equal(t, 0, payload.SkippedElements)
+
+	var replyMap map[string]*lenientUser
+	payload, err = decodeCollection(t, map[interface{}]interface{}{
+		"alex": &lenientUser{Name: "Alex"}, "bob": 1, 2: &lenientUser{Name: "Carl"},
+	}, &replyMap, true)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]*lenientUser{"alex": {Name: "Alex"}}, replyMap)
+	assert.Equal(t, 2, payload.SkippedElements)
+
+	// the elements are converted as the whole response, e.g. the ints of another size
+	var ints []int32
+	payload, err = decodeCollection(t, []interface{}{int64(1), "malformed", int32(2)}, &ints, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []int32{1, 2}, ints)
+	assert.Equal(t, 1, payload.SkippedElements)
+
+	// while the malformed bytes fail the whole response
+	_, err = decodeMalformedCollection(t, &reply)
+	assert.Error(t, err)
+
+	// the values other than the collections are left to the strict reflection
+	var name string
+	payload, err = decodeCollection(t, "Alex", &name, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "Alex", name)
+	assert.Equal(t, 0, payload.SkippedElements)
+}
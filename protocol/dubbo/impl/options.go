@@ -0,0 +1,123 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package impl
+
+import (
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+// Options are the options of the hessian2 serialization of the bodies of a service, which are configured
+// by the params of its url.
+type Options struct {
+	check         *serializationCheck
+	mapping       *typeMapping
+	limits        *sizeLimits
+	unknownFields string
+	compression   *compression
+}
+
+// NewOptions returns the default options of the hessian2 serialization
+func NewOptions() *Options {
+	return &Options{check: defaultSerializationCheck, mapping: defaultTypeMapping, limits: defaultSizeLimits,
+		unknownFields: UnknownFieldsIgnore}
+}
+
+// defaultOptions are used by the services without their own options
+var defaultOptions = NewOptions()
+
+// SetSerializationCheck sets the class names and package prefixes, which end with a dot, allowed and denied
+// by the hessian2 decoding of the requests. The denied ones are checked in addition to DefaultDeniedClasses,
+// and all of the classes not denied are allowed if @allowed is empty.
+func (o *Options) SetSerializationCheck(allowed, denied []string) {
+	o.check = &serializationCheck{
+		allowed: allowed,
+		denied:  append(append(make([]string, 0, len(DefaultDeniedClasses)+len(denied)), DefaultDeniedClasses...), denied...),
+	}
+}
+
+// SetSizeLimits sets the max number of the chars of a string and the max number of the elements of a list or map
+// decoded by hessian2, which are checked by the scan of the bodies before they are decoded. The ones not positive
+// keep the ones set before, which are the default ones initially.
+func (o *Options) SetSizeLimits(maxStringLength, maxCollectionSize int) {
+	limits := *o.limits
+	if maxStringLength > 0 {
+		limits.maxStringLength = maxStringLength
+	}
+	if maxCollectionSize > 0 {
+		limits.maxCollectionSize = maxCollectionSize
+	}
+	o.limits = &limits
+}
+
+// SetTypeMapping sets the Go types which java.math.BigDecimal and the dates are mapped to by the hessian2
+// serialization of both the requests and the responses, and the java.util.Date decoded is converted into
+// @location. The empty types and the nil location keep the ones set before, which are the default ones initially.
+func (o *Options) SetTypeMapping(decimalType, timeType string, location *time.Location) error {
+	mapping, err := o.mapping.merge(decimalType, timeType, location)
+	if err != nil {
+		return err
+	}
+	o.mapping = mapping
+	return nil
+}
+
+// SetUnknownFieldsPolicy sets how the hessian2 decoding of both the requests and the responses handles the fields
+// of the objects which their Go types don't have, the empty policy is the default one.
+func (o *Options) SetUnknownFieldsPolicy(policy string) error {
+	if policy == "" {
+		policy = UnknownFieldsIgnore
+	}
+	if policy != UnknownFieldsIgnore && policy != UnknownFieldsError && policy != UnknownFieldsCapture {
+		return perrors.Errorf("unknown policy %s of the unknown fields", policy)
+	}
+	o.unknownFields = policy
+	return nil
+}
+
+// SetCompression enables the gzip compression of the hessian2 bodies larger than @minSize bytes encoded with
+// the options, and the default min size is used if it's not positive.
+func (o *Options) SetCompression(minSize int) {
+	if minSize <= 0 {
+		minSize = DefaultCompressMinSize
+	}
+	o.compression = &compression{minSize: minSize}
+}
+
+// CompressionEnabled tells whether the bodies encoded with the options are compressed
+func (o *Options) CompressionEnabled() bool {
+	return o.compression != nil
+}
+
+// WithoutCompression returns the options which don't compress the bodies, e.g. the ones of the responses
+// to the consumers not accepting the compressed ones
+func (o *Options) WithoutCompression() *Options {
+	if o.compression == nil {
+		return o
+	}
+	opts := *o
+	opts.compression = nil
+	return &opts
+}
+
+// OptionsResolver returns the options of the service of @path and @version decoded from a request,
+// or nil if the service has no options of its own.
+type OptionsResolver func(path, version string) *Options
@@ -18,7 +18,6 @@
 package impl
 
 import (
-	"bufio"
 	"bytes"
 	"fmt"
 	"time"
@@ -93,6 +92,14 @@ func (p *DubboPackage) Unmarshal() error {
 	return p.Codec.Decode(p)
 }
 
+// Release returns the pooled buffer reading the package once it's decoded, and the package can't be
+// unmarshalled afterwards
+func (p *DubboPackage) Release() {
+	if p.Codec != nil {
+		p.Codec.release()
+	}
+}
+
 func (p DubboPackage) IsHeartBeat() bool {
 	return p.Header.Type&PackageHeartbeat != 0
 }
@@ -159,7 +166,9 @@ func NewDubboPackage(data *bytes.Buffer) *DubboPackage {
 	if data == nil {
 		codec = NewDubboCodec(nil)
 	} else {
-		codec = NewDubboCodec(bufio.NewReaderSize(data, len(data.Bytes())))
+		reader, pooled := getBufferPool().getReader(data)
+		codec = NewDubboCodec(reader)
+		codec.pooled = pooled
 	}
 	return &DubboPackage{
 		Header:  DubboHeader{},
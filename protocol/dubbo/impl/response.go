@@ -21,6 +21,10 @@ type ResponsePayload struct {
 	RspObj      interface{}
 	Exception   error
 	Attachments map[string]interface{}
+	// LenientCollection skips the elements of the decoded list or map which RspObj can't hold
+	LenientCollection bool
+	// SkippedElements is the number of the elements skipped by the lenient decoding
+	SkippedElements int
 }
 
 // NewResponse create a new ResponsePayload
@@ -21,6 +21,10 @@ import (
 	"fmt"
 )
 
+import (
+	perrors "github.com/pkg/errors"
+)
+
 import (
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 )
@@ -41,14 +45,57 @@ func SetSerializer(name string, serializer Serializer) {
 	serializers[name] = serializer
 }
 
+// SetSerializerWithId sets the serializer of the serialization @name, whose packages are marked by @id
+func SetSerializerWithId(id byte, name string, serializer Serializer) {
+	nameMaps[id] = name
+	SetSerializer(name, serializer)
+}
+
+// HasSerializer returns whether the serialization @name is available
+func HasSerializer(name string) bool {
+	_, ok := serializers[name]
+	return ok
+}
+
+// GetSerialIdByName returns the id of the serialization @name which marks its packages
+func GetSerialIdByName(name string) (byte, error) {
+	for id, n := range nameMaps {
+		if n == name {
+			return id, nil
+		}
+	}
+	return 0, perrors.Errorf("serialization %s not found", name)
+}
+
 func GetSerializerById(id byte) (Serializer, error) {
 	name, ok := nameMaps[id]
 	if !ok {
-		panic(fmt.Sprintf("serialId %d not found", id))
+		return nil, perrors.Errorf("serialId %d not found", id)
 	}
 	serializer, ok := serializers[name]
 	if !ok {
-		panic(fmt.Sprintf("serialization %s not found", name))
+		return nil, perrors.Errorf("serialization %s not found", name)
 	}
 	return serializer, nil
 }
+
+// SerializationMismatchError is returned by the codec once the serialization of the package received
+// differs from the local one and isn't available, so the package couldn't be decoded.
+type SerializationMismatchError struct {
+	// LocalID is the serialization id of the codec
+	LocalID byte
+	// RemoteID is the serialization id of the package received
+	RemoteID byte
+}
+
+func (e *SerializationMismatchError) Error() string {
+	return fmt.Sprintf("serialization mismatch, the local serialization is %s(id %d) but the remote one is %s(id %d)",
+		serializationName(e.LocalID), e.LocalID, serializationName(e.RemoteID), e.RemoteID)
+}
+
+func serializationName(id byte) string {
+	if name, ok := nameMaps[id]; ok {
+		return name
+	}
+	return "unknown"
+}
@@ -0,0 +1,126 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package impl
+
+import (
+	"strings"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+)
+
+// DefaultDeniedClasses are the class names and package prefixes of the known gadget chains, which are
+// always rejected by the hessian2 decoding of the requests.
+var DefaultDeniedClasses = []string{
+	"bsh.",
+	"ch.qos.logback.core.db.",
+	"clojure.",
+	"com.caucho.naming.",
+	"com.mchange.v2.c3p0.",
+	"com.rometools.rome.feed.impl.",
+	"com.sun.jndi.",
+	"com.sun.org.apache.bcel.internal.util.ClassLoader",
+	"com.sun.org.apache.xalan.internal.xsltc.trax.",
+	"com.sun.rowset.JdbcRowSetImpl",
+	"java.lang.ProcessBuilder",
+	"java.lang.Runtime",
+	"java.net.URLClassLoader",
+	"java.rmi.",
+	"javax.management.",
+	"javax.naming.",
+	"javax.script.",
+	"org.apache.commons.beanutils.",
+	"org.apache.commons.collections.functors.",
+	"org.apache.commons.collections4.functors.",
+	"org.apache.xbean.naming.context.",
+	"org.apache.xpath.XPathContext",
+	"org.codehaus.groovy.runtime.",
+	"org.hibernate.engine.",
+	"org.springframework.aop.",
+	"org.springframework.beans.factory.",
+	"sun.rmi.",
+}
+
+// allowedJDKClasses are the package prefixes of the common value types, which are allowed
+// besides the configured allowlist.
+var allowedJDKClasses = []string{"java.lang.", "java.math.", "java.time.", "java.util."}
+
+type serializationCheck struct {
+	allowed []string
+	denied  []string
+}
+
+// defaultSerializationCheck rejects the classes of DefaultDeniedClasses only
+var defaultSerializationCheck = &serializationCheck{denied: DefaultDeniedClasses}
+
+func matchClass(class string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if class == pattern || (strings.HasSuffix(pattern, ".") && strings.HasPrefix(class, pattern)) {
+			return true
+		}
+	}
+	return false
+}
+
+// isAllowed checks @class against the denylist first and then the allowlist
+func (c *serializationCheck) isAllowed(class string) bool {
+	if matchClass(class, c.denied) {
+		return false
+	}
+	return len(c.allowed) == 0 || matchClass(class, allowedJDKClasses) || matchClass(class, c.allowed)
+}
+
+// checkArgsTypes checks the classes of the parameter types declared by the request, so that the disallowed
+// ones are rejected before the arguments are decoded.
+func (c *serializationCheck) checkArgsTypes(service, method interface{}, types []string) error {
+	for _, desc := range types {
+		class := descToClass(desc)
+		if class == "" || c.isAllowed(class) {
+			continue
+		}
+		logger.Warnf("[Serialization Check] reject the request of the method %v of the service %v "+
+			"for the parameter type %s", method, service, class)
+		return perrors.WithMessagef(ErrClassNotAllowed, "class %s", class)
+	}
+	return nil
+}
+
+// checkClassDef checks the class of a class definition found by the scan of the body, so that the disallowed
+// classes nested in the arguments of the allowed types are rejected before they are decoded.
+func (c *serializationCheck) checkClassDef(class string) error {
+	if c.isAllowed(class) {
+		return nil
+	}
+	logger.Warnf("[Serialization Check] reject the request referencing the class %s", class)
+	return perrors.WithMessagef(ErrClassNotAllowed, "class %s", class)
+}
+
+// descToClass converts the type descriptor, e.g. Ljava/lang/String; or [Lcom/foo/Bar;, into the class name,
+// and the primitive types are converted into empty names.
+func descToClass(desc string) string {
+	desc = strings.TrimLeft(desc, "[")
+	if !strings.HasPrefix(desc, "L") {
+		return ""
+	}
+	return strings.ReplaceAll(strings.TrimSuffix(desc[1:], ";"), "/", ".")
+}
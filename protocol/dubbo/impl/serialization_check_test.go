@@ -0,0 +1,128 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package impl
+
+import (
+	"testing"
+)
+
+import (
+	hessian "github.com/apache/dubbo-go-hessian2"
+
+	perrors "github.com/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type invokerTransformer struct {
+	MethodName string
+}
+
+func (invokerTransformer) JavaClassName() string {
+	return "org.apache.commons.collections.functors.InvokerTransformer"
+}
+
+func encodeRequestBody(t *testing.T, argsTypes string, args ...interface{}) []byte {
+	return encodeServiceRequestBody(t, "com.ikurento.user.UserProvider", argsTypes, args...)
+}
+
+func encodeServiceRequestBody(t *testing.T, path, argsTypes string, args ...interface{}) []byte {
+	encoder := hessian.NewEncoder()
+	for _, v := range []interface{}{"2.0.2", path, "", "GetUser", argsTypes} {
+		assert.NoError(t, encoder.Encode(v))
+	}
+	for _, arg := range args {
+		assert.NoError(t, encoder.Encode(arg))
+	}
+	assert.NoError(t, encoder.Encode(map[interface{}]interface{}{}))
+	return encoder.Buffer()
+}
+
+func unmarshalRequest(body []byte) error {
+	return unmarshalServiceRequest(body, nil)
+}
+
+func unmarshalServiceRequest(body []byte, resolver OptionsResolver) error {
+	pkg := NewDubboPackage(nil)
+	pkg.Header.Type = PackageRequest
+	pkg.Codec.SetOptionsResolver(resolver)
+	return unmarshalRequestBody(body, pkg)
+}
+
+func TestSerializationCheckDeniedArgsType(t *testing.T) {
+	// the arguments are never decoded since the declared type is rejected first
+	body := encodeRequestBody(t, "Lorg/apache/commons/collections/functors/InvokerTransformer;")
+	body = append(body, 0xff, 0xff, 0xff)
+	err := unmarshalRequest(body)
+	assert.Equal(t, ErrClassNotAllowed, perrors.Cause(err))
+	assert.Contains(t, err.Error(), "org.apache.commons.collections.functors.InvokerTransformer")
+
+	assert.NoError(t, unmarshalRequest(encodeRequestBody(t, "Ljava/lang/String;", "1")))
+}
+
+func TestSerializationCheckDeniedNestedClass(t *testing.T) {
+	hessian.RegisterPOJO(&invokerTransformer{})
+	body := encodeRequestBody(t, "Ljava/util/List;", []interface{}{&invokerTransformer{MethodName: "exec"}})
+	err := unmarshalRequest(body)
+	assert.Equal(t, ErrClassNotAllowed, perrors.Cause(err))
+}
+
+func TestSerializationCheckAllowlist(t *testing.T) {
+	opts := NewOptions()
+	opts.SetSerializationCheck([]string{"com.ikurento.user."}, []string{"com.ikurento.user.Admin"})
+	resolver := func(string, string) *Options { return opts }
+
+	assert.NoError(t, unmarshalServiceRequest(encodeRequestBody(t, "Ljava/lang/String;I", "1", int32(1)), resolver))
+	err := unmarshalServiceRequest(encodeRequestBody(t, "Lcom/other/User;"), resolver)
+	assert.Equal(t, ErrClassNotAllowed, perrors.Cause(err))
+	err = unmarshalServiceRequest(encodeRequestBody(t, "[Lcom/ikurento/user/Admin;"), resolver)
+	assert.Equal(t, ErrClassNotAllowed, perrors.Cause(err))
+	// the default denylist is kept
+	err = unmarshalServiceRequest(encodeRequestBody(t, "Ljava/lang/Runtime;"), resolver)
+	assert.Equal(t, ErrClassNotAllowed, perrors.Cause(err))
+}
+
+type otherUser struct {
+	Name string
+}
+
+func (otherUser) JavaClassName() string {
+	return "com.other.User"
+}
+
+func TestSerializationCheckPerService(t *testing.T) {
+	hessian.RegisterPOJO(&otherUser{})
+	strict := NewOptions()
+	strict.SetSerializationCheck([]string{"com.ikurento.user."}, nil)
+	resolver := func(path, version string) *Options {
+		if path == "com.ikurento.user.StrictProvider" {
+			return strict
+		}
+		return nil
+	}
+
+	// the nested class is checked against the allowlist of its own service only
+	args := []interface{}{[]interface{}{&otherUser{Name: "1"}}}
+	body := encodeServiceRequestBody(t, "com.ikurento.user.StrictProvider", "Ljava/util/List;", args...)
+	err := unmarshalServiceRequest(body, resolver)
+	assert.Equal(t, ErrClassNotAllowed, perrors.Cause(err))
+	assert.Contains(t, err.Error(), "com.other.User")
+
+	body = encodeServiceRequestBody(t, "com.ikurento.user.UserProvider", "Ljava/util/List;", args...)
+	assert.NoError(t, unmarshalServiceRequest(body, resolver))
+}
@@ -33,8 +33,9 @@ func LoadSerializer(p *DubboPackage) error {
 	}
 	serializer, err := GetSerializerById(serialID)
 	if err != nil {
-		panic(err)
+		return err
 	}
 	p.SetSerializer(serializer)
+	p.Codec.serialID = serialID
 	return nil
 }
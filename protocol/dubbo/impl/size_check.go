@@ -0,0 +1,507 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package impl
+
+import (
+	"encoding/binary"
+)
+
+import (
+	hessian "github.com/apache/dubbo-go-hessian2"
+
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+)
+
+const (
+	// DefaultMaxStringLength is the max number of the chars of a string decoded by hessian2 by default
+	DefaultMaxStringLength = 4 * 1024 * 1024
+	// DefaultMaxCollectionSize is the max number of the elements of a list or map decoded by hessian2 by default
+	DefaultMaxCollectionSize = 1024 * 1024
+	// maxNestingDepth bounds the recursion of the scan on the nested lists, maps and objects
+	maxNestingDepth = 1024
+)
+
+type sizeLimits struct {
+	maxStringLength   int
+	maxCollectionSize int
+}
+
+var defaultSizeLimits = &sizeLimits{maxStringLength: DefaultMaxStringLength, maxCollectionSize: DefaultMaxCollectionSize}
+
+// errMalformed stops the scan on the bytes the scanner doesn't understand, which are left to the decoder
+var errMalformed = perrors.New("malformed hessian2 body")
+
+// check scans the hessian2 @body for the strings and collections over the limits before it's decoded,
+// since the decoder allocates the memory by the lengths declared in the body. The malformed bodies are
+// left to the decoder, which rejects them in turn. The classes of the class definitions are checked by
+// @classes as well unless it's nil.
+func (l *sizeLimits) check(body []byte, classes *serializationCheck) error {
+	return (&sizeScanner{body: body, limits: l, classCheck: classes}).scan()
+}
+
+// sizeScanner walks through the hessian2 values without decoding them
+type sizeScanner struct {
+	body   []byte
+	offset int
+	limits *sizeLimits
+	// fields are the numbers of the fields of the class definitions in order
+	fields []int
+	depth  int
+	// refs counts the references to the former values
+	refs int
+	// classCheck checks the classes of the class definitions unless it's nil
+	classCheck *serializationCheck
+	// unknown applies the unknown fields policy to the objects unless it's nil, and the class definitions
+	// are recorded into classes for it
+	unknown *unknownFields
+	classes []classDef
+}
+
+// classDef is a class definition in the body, and raw is its bytes following the tag C. The fields
+// unknown to the Go type of the class are marked by unknown unless it's nil.
+type classDef struct {
+	name    string
+	fields  []string
+	raw     []byte
+	unknown []bool
+}
+
+// scan walks through all of the top level values of the body
+func (s *sizeScanner) scan() error {
+	for index := 0; s.offset < len(s.body); index++ {
+		s.unknown.push(pathStep{index: index})
+		err := s.value()
+		s.unknown.pop()
+		if err == errMalformed {
+			logger.Debugf("[Size Check] stop scanning the malformed body at offset %d", s.offset)
+			return nil
+		}
+		if err != nil {
+			logger.Warnf("[Size Check] reject the body: %v", err)
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *sizeScanner) next() (byte, error) {
+	if s.offset >= len(s.body) {
+		return 0, errMalformed
+	}
+	s.offset++
+	return s.body[s.offset-1], nil
+}
+
+func (s *sizeScanner) skip(n int) error {
+	if n < 0 || s.offset+n > len(s.body) {
+		return errMalformed
+	}
+	s.offset += n
+	return nil
+}
+
+func (s *sizeScanner) value() error {
+	tag, err := s.next()
+	if err != nil {
+		return err
+	}
+	// the class definitions are the prefixes of the objects following them
+	for tag == 'C' {
+		if err = s.classDef(); err != nil {
+			return err
+		}
+		if tag, err = s.next(); err != nil {
+			return err
+		}
+	}
+	switch {
+	case tag <= 0x1f, tag >= 0x30 && tag <= 0x33, tag == 'R', tag == 'S':
+		return s.string(tag)
+	case tag >= 0x20 && tag <= 0x2f:
+		return s.skip(int(tag - 0x20))
+	case tag >= 0x34 && tag <= 0x37:
+		b, err := s.next()
+		if err != nil {
+			return err
+		}
+		return s.skip(int(tag-0x34)<<8 | int(b))
+	case tag == 'A', tag == 'B':
+		return s.binary(tag)
+	case tag == 'N', tag == 'T', tag == 'F', tag == 0x5b, tag == 0x5c,
+		tag >= 0x80 && tag <= 0xbf, tag >= 0xd8 && tag <= 0xef:
+		return nil
+	case tag == 0x5d, tag >= 0xc0 && tag <= 0xcf, tag >= 0xf0:
+		return s.skip(1)
+	case tag == 0x5e, tag >= 0xd0 && tag <= 0xd7:
+		return s.skip(2)
+	case tag >= 0x38 && tag <= 0x3f:
+		return s.skip(2)
+	case tag == 'I', tag == 'K', tag == 0x59, tag == 0x5f:
+		return s.skip(4)
+	case tag == 'D', tag == 'J', tag == 'L':
+		return s.skip(8)
+	case tag == 'Q':
+		s.refs++
+		_, err = s.int()
+		return err
+	case tag == 'O':
+		ref, err := s.int()
+		if err != nil {
+			return err
+		}
+		return s.object(ref)
+	case tag >= 0x60 && tag <= 0x6f:
+		return s.object(int(tag - 0x60))
+	}
+	return s.collection(tag)
+}
+
+// collection walks through the lists and maps nested in the other values
+func (s *sizeScanner) collection(tag byte) error {
+	if s.depth++; s.depth > maxNestingDepth {
+		return perrors.WithMessagef(ErrSizeExceeded, "nesting depth over %d", maxNestingDepth)
+	}
+	defer func() { s.depth-- }()
+
+	switch {
+	case tag == 'H':
+		return s.entries(2)
+	case tag == 'M':
+		if err := s.typ(); err != nil {
+			return err
+		}
+		return s.entries(2)
+	case tag == 'W':
+		return s.entries(1)
+	case tag == 'U':
+		if err := s.typ(); err != nil {
+			return err
+		}
+		return s.entries(1)
+	case tag == 'X', tag == 'V':
+		if tag == 'V' {
+			if err := s.typ(); err != nil {
+				return err
+			}
+		}
+		length, err := s.int()
+		if err != nil {
+			return err
+		}
+		return s.elements(length)
+	case tag >= 0x70 && tag <= 0x77:
+		if err := s.typ(); err != nil {
+			return err
+		}
+		return s.elements(int(tag - 0x70))
+	case tag >= 0x78 && tag <= 0x7f:
+		return s.elements(int(tag - 0x78))
+	}
+	return errMalformed
+}
+
+// entries walks through the values of a variable length list or map until its end, and every entry
+// of a map consists of @width values
+func (s *sizeScanner) entries(width int) error {
+	for count := 0; ; count++ {
+		if s.offset < len(s.body) && s.body[s.offset] == 'Z' {
+			s.offset++
+			return nil
+		}
+		if count >= s.limits.maxCollectionSize {
+			return perrors.WithMessagef(ErrSizeExceeded, "collection over %d elements", s.limits.maxCollectionSize)
+		}
+		step := pathStep{index: count}
+		if width == 2 {
+			start := s.offset
+			if err := s.value(); err != nil {
+				return err
+			}
+			step = pathStep{key: s.body[start:s.offset]}
+		}
+		s.unknown.push(step)
+		err := s.value()
+		s.unknown.pop()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// elements walks through the values of a fixed length list
+func (s *sizeScanner) elements(length int) error {
+	if length < 0 {
+		return errMalformed
+	}
+	if length > s.limits.maxCollectionSize {
+		return perrors.WithMessagef(ErrSizeExceeded, "collection of %d elements over %d", length, s.limits.maxCollectionSize)
+	}
+	for i := 0; i < length; i++ {
+		s.unknown.push(pathStep{index: i})
+		err := s.value()
+		s.unknown.pop()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *sizeScanner) classDef() error {
+	start := s.offset
+	record := s.unknown != nil
+	name, err := s.name(record || s.classCheck != nil)
+	if err != nil {
+		return err
+	}
+	nameRaw := s.body[start:s.offset]
+	if s.classCheck != nil {
+		if err = s.classCheck.checkClassDef(name); err != nil {
+			return err
+		}
+	}
+	count, err := s.int()
+	if err != nil {
+		return err
+	}
+	if count < 0 {
+		return errMalformed
+	}
+	if count > s.limits.maxCollectionSize {
+		return perrors.WithMessagef(ErrSizeExceeded, "class of %d fields over %d", count, s.limits.maxCollectionSize)
+	}
+	var fields []string
+	for i := 0; i < count; i++ {
+		field, err := s.name(record)
+		if err != nil {
+			return err
+		}
+		if record {
+			fields = append(fields, field)
+		}
+	}
+	s.fields = append(s.fields, count)
+	if record {
+		s.classes = append(s.classes, classDef{name: name, fields: fields, raw: s.body[start:s.offset],
+			unknown: unknownFieldsOf(name, nameRaw, fields)})
+	}
+	return nil
+}
+
+// name walks through a class name or a field name of a class definition, which is decoded only if @decode
+func (s *sizeScanner) name(decode bool) (string, error) {
+	start := s.offset
+	tag, err := s.next()
+	if err != nil {
+		return "", err
+	}
+	if err = s.string(tag); err != nil || !decode {
+		return "", err
+	}
+	name, err := hessian.NewCheapDecoderWithSkip(s.body[start:s.offset]).Decode()
+	if err != nil {
+		return "", errMalformed
+	}
+	str, ok := name.(string)
+	if !ok {
+		return "", errMalformed
+	}
+	return str, nil
+}
+
+func (s *sizeScanner) object(ref int) error {
+	if ref < 0 || ref >= len(s.fields) {
+		return errMalformed
+	}
+	if s.depth++; s.depth > maxNestingDepth {
+		return perrors.WithMessagef(ErrSizeExceeded, "nesting depth over %d", maxNestingDepth)
+	}
+	defer func() { s.depth-- }()
+	if s.unknown != nil {
+		return s.objectFields(&s.classes[ref])
+	}
+	for i := 0; i < s.fields[ref]; i++ {
+		if err := s.value(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// objectFields walks through the fields of an object of the class @def under the unknown fields policy
+func (s *sizeScanner) objectFields(def *classDef) error {
+	var captured map[string]interface{}
+	for i, name := range def.fields {
+		s.unknown.push(pathStep{field: name})
+		var err error
+		if def.unknown != nil && def.unknown[i] {
+			if captured == nil {
+				captured = make(map[string]interface{})
+			}
+			err = s.unknownField(name, def.name, captured)
+		} else {
+			err = s.value()
+		}
+		s.unknown.pop()
+		if err != nil {
+			return err
+		}
+	}
+	if len(captured) > 0 {
+		s.unknown.captured = append(s.unknown.captured,
+			capturedFields{path: append([]pathStep(nil), s.unknown.path...), fields: captured})
+	}
+	return nil
+}
+
+// typ walks through the type of a typed list or map, which is either a string or a reference to a former type
+func (s *sizeScanner) typ() error {
+	tag, err := s.next()
+	if err != nil {
+		return err
+	}
+	if tag <= 0x1f || (tag >= 0x30 && tag <= 0x33) || tag == 'R' || tag == 'S' {
+		return s.string(tag)
+	}
+	s.offset--
+	_, err = s.int()
+	return err
+}
+
+// int reads an int value, e.g. the length of a fixed length list
+func (s *sizeScanner) int() (int, error) {
+	tag, err := s.next()
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case tag >= 0x80 && tag <= 0xbf:
+		return int(tag) - 0x90, nil
+	case tag >= 0xc0 && tag <= 0xcf:
+		b, err := s.next()
+		if err != nil {
+			return 0, err
+		}
+		return (int(tag)-0xc8)<<8 | int(b), nil
+	case tag >= 0xd0 && tag <= 0xd7:
+		if err = s.skip(2); err != nil {
+			return 0, err
+		}
+		return (int(tag)-0xd4)<<16 | int(s.body[s.offset-2])<<8 | int(s.body[s.offset-1]), nil
+	case tag == 'I':
+		if err = s.skip(4); err != nil {
+			return 0, err
+		}
+		return int(int32(binary.BigEndian.Uint32(s.body[s.offset-4:]))), nil
+	}
+	return 0, errMalformed
+}
+
+// string walks through the chunks of a string starting with @tag, and counts its chars
+func (s *sizeScanner) string(tag byte) error {
+	total := 0
+	for {
+		var length int
+		switch {
+		case tag <= 0x1f:
+			length = int(tag)
+		case tag >= 0x30 && tag <= 0x33:
+			b, err := s.next()
+			if err != nil {
+				return err
+			}
+			length = int(tag-0x30)<<8 | int(b)
+		case tag == 'R', tag == 'S':
+			if err := s.skip(2); err != nil {
+				return err
+			}
+			length = int(binary.BigEndian.Uint16(s.body[s.offset-2:]))
+		default:
+			return errMalformed
+		}
+		if total += length; total > s.limits.maxStringLength {
+			return perrors.WithMessagef(ErrSizeExceeded, "string over %d chars", s.limits.maxStringLength)
+		}
+		if err := s.chars(length); err != nil {
+			return err
+		}
+		if tag != 'R' {
+			return nil
+		}
+		var err error
+		if tag, err = s.next(); err != nil {
+			return err
+		}
+	}
+}
+
+// chars skips @count chars in utf-8, and the supplementary ones are counted as two chars like java
+func (s *sizeScanner) chars(count int) error {
+	for count > 0 {
+		if s.offset >= len(s.body) {
+			return errMalformed
+		}
+		ch := s.body[s.offset]
+		switch {
+		case ch < 0x80:
+			s.offset++
+			count--
+		case ch&0xe0 == 0xc0:
+			s.offset += 2
+			count--
+		case ch&0xf0 == 0xe0:
+			s.offset += 3
+			count--
+		case ch&0xf8 == 0xf0:
+			s.offset += 4
+			count -= 2
+		default:
+			return errMalformed
+		}
+	}
+	if s.offset > len(s.body) {
+		return errMalformed
+	}
+	return nil
+}
+
+func (s *sizeScanner) binary(tag byte) error {
+	for {
+		if err := s.skip(2); err != nil {
+			return err
+		}
+		if err := s.skip(int(binary.BigEndian.Uint16(s.body[s.offset-2:]))); err != nil {
+			return err
+		}
+		if tag == 'B' {
+			return nil
+		}
+		var err error
+		if tag, err = s.next(); err != nil {
+			return err
+		}
+		if tag != 'A' && tag != 'B' {
+			s.offset--
+			return s.value()
+		}
+	}
+}
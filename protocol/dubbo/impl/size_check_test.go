@@ -0,0 +1,137 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package impl
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+import (
+	hessian "github.com/apache/dubbo-go-hessian2"
+
+	perrors "github.com/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type sizeCheckUser struct {
+	Name    string
+	Age     int32
+	Tags    []string
+	Friends []*sizeCheckUser
+}
+
+func (sizeCheckUser) JavaClassName() string {
+	return "com.ikurento.user.SizeCheckUser"
+}
+
+func TestSizeCheckScan(t *testing.T) {
+	hessian.RegisterPOJO(&sizeCheckUser{})
+	user := &sizeCheckUser{Name: "Alex", Age: 18, Tags: []string{"a", "b"}, Friends: []*sizeCheckUser{{Name: "Bob"}}}
+	body := encodeRequestBody(t, "Ljava/lang/Object;", []interface{}{
+		"中文😀", strings.Repeat("x", 70000), []byte{1, 2, 3}, make([]byte, 70000),
+		int32(-1), int32(1000), int32(100000), int32(1 << 30), int64(-1), int64(1000), int64(100000), int64(1 << 40),
+		0.0, 1.0, 2.0, 300.0, 1.5, 1e100, true, false, nil, time.Unix(1600000000, 0), time.Unix(1600000000, 1e6),
+		map[interface{}]interface{}{"k": []interface{}{user, user}}, []int32{1, 2, 3}, []string{"1", "2"},
+		user, &sizeCheckUser{Name: "Carl"},
+	})
+
+	// the scanner walks through all of the values without stopping at the bytes it doesn't understand
+	s := &sizeScanner{body: body, limits: defaultSizeLimits}
+	for s.offset < len(body) {
+		if !assert.NoError(t, s.value()) {
+			return
+		}
+	}
+	assert.NoError(t, unmarshalRequest(body))
+}
+
+func TestSizeCheckCollection(t *testing.T) {
+	limited := NewOptions()
+	limited.SetSizeLimits(0, 10)
+	unmarshalRequest := func(body []byte) error {
+		return unmarshalServiceRequest(body, func(string, string) *Options { return limited })
+	}
+
+	assert.NoError(t, unmarshalRequest(encodeRequestBody(t, "Ljava/util/List;", make([]interface{}, 10))))
+	err := unmarshalRequest(encodeRequestBody(t, "Ljava/util/List;", make([]interface{}, 11)))
+	assert.Equal(t, ErrSizeExceeded, perrors.Cause(err))
+	err = unmarshalRequest(encodeRequestBody(t, "Ljava/util/Map;", map[interface{}]interface{}{
+		0: 0, 1: 1, 2: 2, 3: 3, 4: 4, 5: 5, 6: 6, 7: 7, 8: 8, 9: 9, 10: 10,
+	}))
+	assert.Equal(t, ErrSizeExceeded, perrors.Cause(err))
+
+	// the list declaring 1<<30 elements is rejected without allocating them
+	body := encodeRequestBody(t, "Ljava/util/List;")
+	body = append(body[:len(body)-1], 'X', 'I', 0x40, 0, 0, 0, 'N', 'H', 'Z')
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	err = unmarshalRequest(body)
+	runtime.ReadMemStats(&after)
+	assert.Equal(t, ErrSizeExceeded, perrors.Cause(err))
+	assert.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(1<<20))
+
+	// the responses are checked as well
+	encoder := hessian.NewEncoder()
+	assert.NoError(t, encoder.Encode(RESPONSE_VALUE))
+	assert.NoError(t, encoder.Encode(make([]interface{}, 11)))
+	pkg := NewDubboPackage(nil)
+	pkg.Codec.SetOptions(limited)
+	pkg.SetBody(&ResponsePayload{RspObj: &[]interface{}{}})
+	assert.Equal(t, ErrSizeExceeded, perrors.Cause(unmarshalResponseBody(encoder.Buffer(), pkg)))
+}
+
+func TestSizeCheckPerService(t *testing.T) {
+	limited := NewOptions()
+	limited.SetSizeLimits(0, 10)
+	resolver := func(path, version string) *Options {
+		if path == "com.ikurento.user.LimitedProvider" {
+			return limited
+		}
+		return nil
+	}
+
+	// the limits of a service don't apply to the others
+	args := []interface{}{make([]interface{}, 11)}
+	body := encodeServiceRequestBody(t, "com.ikurento.user.LimitedProvider", "Ljava/util/List;", args...)
+	assert.Equal(t, ErrSizeExceeded, perrors.Cause(unmarshalServiceRequest(body, resolver)))
+	body = encodeServiceRequestBody(t, "com.ikurento.user.UserProvider", "Ljava/util/List;", args...)
+	assert.NoError(t, unmarshalServiceRequest(body, resolver))
+
+	// and the ones not set keep the ones set before
+	limited.SetSizeLimits(100, 0)
+	assert.Equal(t, &sizeLimits{maxStringLength: 100, maxCollectionSize: 10}, limited.limits)
+	assert.Equal(t, defaultSizeLimits, NewOptions().limits)
+}
+
+func TestSizeCheckString(t *testing.T) {
+	limited := NewOptions()
+	limited.SetSizeLimits(100000, 0)
+	unmarshalRequest := func(body []byte) error {
+		return unmarshalServiceRequest(body, func(string, string) *Options { return limited })
+	}
+
+	assert.NoError(t, unmarshalRequest(encodeRequestBody(t, "Ljava/lang/String;", strings.Repeat("中", 100000))))
+	err := unmarshalRequest(encodeRequestBody(t, "Ljava/lang/String;", strings.Repeat("中", 100001)))
+	assert.Equal(t, ErrSizeExceeded, perrors.Cause(err))
+	err = unmarshalRequest(encodeRequestBody(t, "Ljava/util/List;", []interface{}{strings.Repeat("x", 100001)}))
+	assert.Equal(t, ErrSizeExceeded, perrors.Cause(err))
+}
@@ -0,0 +1,219 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package impl
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+import (
+	"github.com/apache/dubbo-go-hessian2/java8_time"
+
+	big "github.com/dubbogo/gost/math/big"
+
+	perrors "github.com/pkg/errors"
+)
+
+const (
+	// DecimalTypeDecimal maps java.math.BigDecimal to *big.Decimal of gost, it's the default one
+	DecimalTypeDecimal = "decimal"
+	// DecimalTypeString maps java.math.BigDecimal to its plain string, e.g. for the json marshalling
+	DecimalTypeString = "string"
+	// TimeTypeDate maps time.Time to java.util.Date, which carries the milliseconds of the instant only,
+	// it's the default one
+	TimeTypeDate = "date"
+	// TimeTypeZoned maps time.Time to java.time.ZonedDateTime, which preserves the nanoseconds and the zone
+	TimeTypeZoned = "zoned"
+
+	zonedDateTimeClass = "java.time.ZonedDateTime"
+)
+
+type typeMapping struct {
+	decimalType string
+	timeType    string
+	// location is the one which the decoded java.util.Date is converted into, nil keeps the local one
+	location *time.Location
+}
+
+var defaultTypeMapping = &typeMapping{decimalType: DecimalTypeDecimal, timeType: TimeTypeDate}
+
+// merge returns the mapping with the types and the location set, the empty and the nil ones keep the ones of @m
+func (m *typeMapping) merge(decimalType, timeType string, location *time.Location) (*typeMapping, error) {
+	if decimalType != "" && decimalType != DecimalTypeDecimal && decimalType != DecimalTypeString {
+		return nil, perrors.Errorf("unknown decimal type %s", decimalType)
+	}
+	if timeType != "" && timeType != TimeTypeDate && timeType != TimeTypeZoned {
+		return nil, perrors.Errorf("unknown time type %s", timeType)
+	}
+	merged := *m
+	if decimalType != "" {
+		merged.decimalType = decimalType
+	}
+	if timeType != "" {
+		merged.timeType = timeType
+	}
+	if location != nil {
+		merged.location = location
+	}
+	return &merged, nil
+}
+
+// encodeValues maps the values to the ones encoded by hessian2, and @values is left untouched
+func (m *typeMapping) encodeValues(values []interface{}) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, v := range values {
+		result[i] = m.encodeValue(v)
+	}
+	return result
+}
+
+func (m *typeMapping) encodeValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case *big.Decimal:
+		// the decimal serializer only sets the value of the ones which aren't pointers
+		if v == nil {
+			return nil
+		}
+		return *v
+	case time.Time:
+		if m.timeType == TimeTypeZoned {
+			return ToZonedDateTime(v)
+		}
+	case []interface{}:
+		return m.encodeValues(v)
+	case map[interface{}]interface{}:
+		result := make(map[interface{}]interface{}, len(v))
+		for key, value := range v {
+			result[key] = m.encodeValue(value)
+		}
+		return result
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			result[key] = m.encodeValue(value)
+		}
+		return result
+	}
+	return v
+}
+
+// decodeValue maps the value decoded by hessian2 to the configured Go type, and the containers are updated in place
+func (m *typeMapping) decodeValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case *big.Decimal:
+		if m.decimalType == DecimalTypeString && v != nil {
+			return v.String()
+		}
+	case time.Time:
+		if m.location != nil && !v.IsZero() {
+			return v.In(m.location)
+		}
+	case *java8_time.ZonedDateTime:
+		if m.timeType == TimeTypeZoned && v != nil {
+			return FromZonedDateTime(*v)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = m.decodeValue(value)
+		}
+	case map[interface{}]interface{}:
+		for key, value := range v {
+			v[key] = m.decodeValue(value)
+		}
+	}
+	return v
+}
+
+// ToZonedDateTime converts @t to java.time.ZonedDateTime in the same zone, the zone of the location name known by
+// the tz database is preserved, and the ones unknown, e.g. time.Local, are converted into the offset, e.g. +08:00.
+func ToZonedDateTime(t time.Time) java8_time.ZonedDateTime {
+	_, offset := t.Zone()
+	zoneID := t.Location().String()
+	if _, ok := loadLocation(zoneID); !ok || zoneID == "" || zoneID == "Local" {
+		zoneID = offsetZoneID(offset)
+	}
+	return java8_time.ZonedDateTime{
+		DateTime: java8_time.LocalDateTime{
+			Date: java8_time.LocalDate{Year: int32(t.Year()), Month: int32(t.Month()), Day: int32(t.Day())},
+			Time: java8_time.LocalTime{Hour: int32(t.Hour()), Minute: int32(t.Minute()), Second: int32(t.Second()),
+				Nano: int32(t.Nanosecond())},
+		},
+		Offset: java8_time.ZoneOffSet{Seconds: int32(offset)},
+		ZoneId: zoneID,
+	}
+}
+
+// FromZonedDateTime converts @z to time.Time of the instant in the location of its zone, and the location
+// is the offset of @z if the zone isn't known by the tz database.
+func FromZonedDateTime(z java8_time.ZonedDateTime) time.Time {
+	date, clock := z.DateTime.Date, z.DateTime.Time
+	// the instant is settled by the offset, which avoids the ambiguity of the local time at the transitions
+	t := time.Date(int(date.Year), time.Month(date.Month), int(date.Day), int(clock.Hour), int(clock.Minute),
+		int(clock.Second), int(clock.Nano), time.FixedZone(offsetZoneID(int(z.Offset.Seconds)), int(z.Offset.Seconds)))
+	if location, ok := loadLocation(z.ZoneId); ok && z.ZoneId != "" {
+		return t.In(location)
+	}
+	return t
+}
+
+// maxCachedLocations bounds the locations cached by loadLocation, which are more than the ones of the tz database
+// only if the unknown names are decoded
+const maxCachedLocations = 1024
+
+var (
+	// cachedLocations are the results of time.LoadLocation by the names, nil for the unknown ones
+	cachedLocations     sync.Map
+	cachedLocationCount int32
+)
+
+// loadLocation returns the location of @name like time.LoadLocation, whose results are cached since it reads
+// the tz database on each call.
+func loadLocation(name string) (*time.Location, bool) {
+	if cached, ok := cachedLocations.Load(name); ok {
+		location := cached.(*time.Location)
+		return location, location != nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		location = nil
+	}
+	if atomic.AddInt32(&cachedLocationCount, 1) > maxCachedLocations {
+		atomic.AddInt32(&cachedLocationCount, -1)
+	} else if _, loaded := cachedLocations.LoadOrStore(name, location); loaded {
+		atomic.AddInt32(&cachedLocationCount, -1)
+	}
+	return location, location != nil
+}
+
+// offsetZoneID formats the zone offset @seconds as the id of java.time.ZoneOffset
+func offsetZoneID(seconds int) string {
+	if seconds == 0 {
+		return "Z"
+	}
+	sign := '+'
+	if seconds < 0 {
+		sign, seconds = '-', -seconds
+	}
+	if seconds%60 != 0 {
+		return fmt.Sprintf("%c%02d:%02d:%02d", sign, seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%c%02d:%02d", sign, seconds/3600, seconds/60%60)
+}
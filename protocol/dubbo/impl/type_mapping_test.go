@@ -0,0 +1,176 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package impl
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/apache/dubbo-go-hessian2/java8_time"
+
+	big "github.com/dubbogo/gost/math/big"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+const preciseDecimal = "12345678901234567890.123456789012345678"
+
+func newDecimal(t *testing.T, value string) *big.Decimal {
+	decimal := &big.Decimal{}
+	assert.NoError(t, decimal.FromString(value))
+	return decimal
+}
+
+// roundTripRequest encodes the request with @args and returns the decoded request body, both are mapped by @opts
+func roundTripRequest(t *testing.T, opts *Options, args ...interface{}) map[string]interface{} {
+	pkg := NewDubboPackage(nil)
+	pkg.Header.Type = PackageRequest
+	pkg.Header.SerialID = constant.S_Hessian2
+	pkg.SetSerializer(HessianSerializer{})
+	pkg.Codec.SetOptions(opts)
+	pkg.Service.Path = "com.ikurento.user.UserProvider"
+	pkg.Service.Method = "Pay"
+	pkg.SetBody(NewRequestPayload(args, map[string]interface{}{}))
+	data, err := pkg.Marshal()
+	assert.NoError(t, err)
+
+	decoded := NewDubboPackage(data)
+	decoded.SetSerializer(HessianSerializer{})
+	decoded.Codec.SetOptions(opts)
+	decoded.Body = make([]interface{}, 7)
+	assert.NoError(t, decoded.Unmarshal())
+	return decoded.GetBody().(map[string]interface{})
+}
+
+// roundTripResponse encodes the response of @result and decodes it into @reply, both are mapped by @opts
+func roundTripResponse(t *testing.T, opts *Options, result interface{}, reply interface{}) {
+	pkg := NewDubboPackage(nil)
+	pkg.Header.Type = PackageResponse
+	pkg.Header.ResponseStatus = Response_OK
+	pkg.Codec.SetOptions(opts)
+	pkg.SetBody(NewResponsePayload(result, nil, map[string]interface{}{}))
+	data, err := HessianSerializer{}.Marshal(*pkg)
+	assert.NoError(t, err)
+
+	pkg.SetBody(NewResponsePayload(reply, nil, nil))
+	assert.NoError(t, HessianSerializer{}.Unmarshal(data, pkg))
+}
+
+func TestTypeMappingDecimal(t *testing.T) {
+	// the pointer to the decimal is encoded without losing the precision
+	opts := NewOptions()
+	body := roundTripRequest(t, opts, newDecimal(t, preciseDecimal), *newDecimal(t, "-0.000000000000000000001"))
+	assert.Equal(t, "Ljava/math/BigDecimal;Ljava/math/BigDecimal;", body["argsTypes"])
+	args := body["args"].([]interface{})
+	assert.Equal(t, preciseDecimal, args[0].(*big.Decimal).String())
+	assert.Equal(t, "-0.000000000000000000001", args[1].(*big.Decimal).String())
+	reply := &big.Decimal{}
+	roundTripResponse(t, opts, newDecimal(t, preciseDecimal), reply)
+	assert.Equal(t, preciseDecimal, reply.String())
+
+	stringOpts := NewOptions()
+	assert.NoError(t, stringOpts.SetTypeMapping(DecimalTypeString, "", nil))
+	body = roundTripRequest(t, stringOpts, newDecimal(t, preciseDecimal), []interface{}{newDecimal(t, "1.10")})
+	args = body["args"].([]interface{})
+	assert.Equal(t, preciseDecimal, args[0])
+	assert.Equal(t, []interface{}{"1.10"}, args[1])
+	var str string
+	roundTripResponse(t, stringOpts, newDecimal(t, preciseDecimal), &str)
+	assert.Equal(t, preciseDecimal, str)
+
+	// the options of the others are untouched
+	body = roundTripRequest(t, opts, newDecimal(t, preciseDecimal))
+	assert.Equal(t, preciseDecimal, body["args"].([]interface{})[0].(*big.Decimal).String())
+
+	assert.Error(t, stringOpts.SetTypeMapping("float", "", nil))
+	assert.Equal(t, DecimalTypeString, stringOpts.mapping.decimalType)
+}
+
+func TestTypeMappingMerge(t *testing.T) {
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	assert.NoError(t, err)
+	opts := NewOptions()
+	assert.NoError(t, opts.SetTypeMapping(DecimalTypeString, "", nil))
+	assert.NoError(t, opts.SetTypeMapping("", TimeTypeZoned, nil))
+	assert.NoError(t, opts.SetTypeMapping("", "", shanghai))
+	assert.Equal(t, &typeMapping{decimalType: DecimalTypeString, timeType: TimeTypeZoned, location: shanghai}, opts.mapping)
+	assert.Equal(t, &typeMapping{decimalType: DecimalTypeDecimal, timeType: TimeTypeDate}, NewOptions().mapping)
+}
+
+func TestLoadLocation(t *testing.T) {
+	location, ok := loadLocation("Asia/Shanghai")
+	assert.True(t, ok)
+	assert.Equal(t, "Asia/Shanghai", location.String())
+	cached, ok := cachedLocations.Load("Asia/Shanghai")
+	assert.True(t, ok)
+	assert.Same(t, location, cached)
+	again, _ := loadLocation("Asia/Shanghai")
+	assert.Same(t, location, again)
+
+	location, ok = loadLocation("Unknown/Zone")
+	assert.False(t, ok)
+	assert.Nil(t, location)
+	cached, ok = cachedLocations.Load("Unknown/Zone")
+	assert.True(t, ok)
+	assert.Nil(t, cached)
+}
+
+func TestTypeMappingTime(t *testing.T) {
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	assert.NoError(t, err)
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+	timestamp := time.Date(2021, 11, 7, 1, 30, 15, 123456789, newYork)
+
+	// java.util.Date keeps the milliseconds of the instant, which is converted into the configured location
+	opts := NewOptions()
+	assert.NoError(t, opts.SetTypeMapping("", TimeTypeDate, shanghai))
+	body := roundTripRequest(t, opts, timestamp)
+	assert.Equal(t, "Ljava/util/Date;", body["argsTypes"])
+	date := body["args"].([]interface{})[0].(time.Time)
+	assert.True(t, timestamp.Truncate(time.Millisecond).Equal(date))
+	assert.Equal(t, shanghai, date.Location())
+
+	// java.time.ZonedDateTime preserves the nanoseconds and the zone
+	assert.NoError(t, opts.SetTypeMapping("", TimeTypeZoned, nil))
+	body = roundTripRequest(t, opts, timestamp)
+	assert.Equal(t, "Ljava/time/ZonedDateTime;", body["argsTypes"])
+	zoned := body["args"].([]interface{})[0].(time.Time)
+	assert.True(t, timestamp.Equal(zoned))
+	assert.Equal(t, "America/New_York", zoned.Location().String())
+	assert.Equal(t, timestamp.Format(time.RFC3339Nano), zoned.Format(time.RFC3339Nano))
+
+	var reply time.Time
+	roundTripResponse(t, opts, timestamp, &reply)
+	assert.True(t, timestamp.Equal(reply))
+	assert.Equal(t, timestamp.Format(time.RFC3339Nano), reply.Format(time.RFC3339Nano))
+	assert.Equal(t, "America/New_York", reply.Location().String())
+
+	// the ones without the location name keep the offset
+	fixed := time.Date(2021, 5, 6, 7, 8, 9, 10, time.FixedZone("", -(9*3600+30*60)))
+	assert.Equal(t, "-09:30", ToZonedDateTime(fixed).ZoneId)
+	assert.Equal(t, fixed.Format(time.RFC3339Nano), FromZonedDateTime(ToZonedDateTime(fixed)).Format(time.RFC3339Nano))
+	assert.Equal(t, "Z", ToZonedDateTime(time.Date(2021, 5, 6, 7, 8, 9, 10, time.FixedZone("", 0))).ZoneId)
+	assert.Equal(t, java8_time.ZoneOffSet{Seconds: 8 * 3600}, ToZonedDateTime(timestamp.In(shanghai)).Offset)
+}
@@ -0,0 +1,260 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package impl
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+import (
+	hessian "github.com/apache/dubbo-go-hessian2"
+
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+)
+
+const (
+	// UnknownFieldsIgnore drops the fields of the objects unknown to their Go types, it's the default one
+	// so that the older peers tolerate the fields added by the newer ones
+	UnknownFieldsIgnore = "ignore"
+	// UnknownFieldsError rejects the bodies with the fields unknown to the Go types of their objects
+	UnknownFieldsError = "error"
+	// UnknownFieldsCapture passes the unknown fields of the objects to the ones implementing UnknownFieldsHolder
+	UnknownFieldsCapture = "capture"
+)
+
+// UnknownFieldsHolder is implemented by the POJOs keeping the fields unknown to them under the capture policy,
+// and SetUnknownFields is called with the decoded values of the fields by their names once there is any of them.
+type UnknownFieldsHolder interface {
+	SetUnknownFields(fields map[string]interface{})
+}
+
+// scan scans the hessian2 @body before it's decoded as sizeLimits.check does, and applies the unknown fields
+// policy of @o to the objects in the body by the class definitions recorded by the scan. The fields captured
+// by the scan are returned to be passed to the decoded objects.
+func (o *Options) scan(body []byte, classes *serializationCheck) (*unknownFields, error) {
+	s := &sizeScanner{body: body, limits: o.limits, classCheck: classes}
+	if o.unknownFields != UnknownFieldsIgnore {
+		s.unknown = &unknownFields{policy: o.unknownFields, mapping: o.mapping}
+	}
+	if err := s.scan(); err != nil {
+		return nil, err
+	}
+	return s.unknown, nil
+}
+
+// unknownFields is the state of the unknown fields policy during a scan
+type unknownFields struct {
+	policy string
+	// mapping maps the captured values like the decoded ones
+	mapping *typeMapping
+	// path is the path of the value being scanned from the top level values of the body, it's kept
+	// under the capture policy only
+	path []pathStep
+	// captured are the fields captured from the objects at their paths
+	captured []capturedFields
+}
+
+// pathStep is a step of a path of the values, which is either the field of an object, the key of a map
+// entry or the index of a list element
+type pathStep struct {
+	field string
+	key   []byte
+	index int
+}
+
+type capturedFields struct {
+	path   []pathStep
+	fields map[string]interface{}
+}
+
+func (u *unknownFields) capturing() bool {
+	return u != nil && u.policy == UnknownFieldsCapture
+}
+
+func (u *unknownFields) push(step pathStep) {
+	if u.capturing() {
+		u.path = append(u.path, step)
+	}
+}
+
+func (u *unknownFields) pop() {
+	if u.capturing() {
+		u.path = u.path[:len(u.path)-1]
+	}
+}
+
+// attach passes the captured fields to the objects decoded at their paths, and @values are decoded from
+// the top level values of the body starting at the @first one.
+func (u *unknownFields) attach(first int, values []interface{}) {
+	if u == nil {
+		return
+	}
+	for _, c := range u.captured {
+		i := c.path[0].index - first
+		if i < 0 || i >= len(values) {
+			continue
+		}
+		v, ok := resolvePath(reflect.ValueOf(&values[i]).Elem(), c.path[1:])
+		if !ok || !v.CanAddr() || !v.Addr().CanInterface() {
+			continue
+		}
+		if holder, ok := v.Addr().Interface().(UnknownFieldsHolder); ok {
+			holder.SetUnknownFields(c.fields)
+		}
+	}
+}
+
+// resolvePath returns the struct decoded at @path from @v, and the map entries are resolved only if
+// their keys decode on their own
+func resolvePath(v reflect.Value, path []pathStep) (reflect.Value, bool) {
+	for _, step := range path {
+		if v = indirectValue(v); !v.IsValid() {
+			return v, false
+		}
+		switch {
+		case v.Kind() == reflect.Struct && step.field != "":
+			field, ok := structField(v.Type(), step.field)
+			if !ok || field.PkgPath != "" {
+				return v, false
+			}
+			v = v.FieldByIndex(field.Index)
+		case (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && step.field == "" && step.key == nil:
+			if step.index >= v.Len() {
+				return v, false
+			}
+			v = v.Index(step.index)
+		case v.Kind() == reflect.Map && step.key != nil:
+			key, err := hessian.NewCheapDecoderWithSkip(step.key).Decode()
+			if err != nil || key == nil {
+				return v, false
+			}
+			k := reflect.ValueOf(key)
+			if !k.Type().AssignableTo(v.Type().Key()) {
+				return v, false
+			}
+			v = v.MapIndex(k)
+		default:
+			return v, false
+		}
+	}
+	v = indirectValue(v)
+	return v, v.IsValid() && v.Kind() == reflect.Struct
+}
+
+func indirectValue(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Interface || v.Kind() == reflect.Ptr) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// unknownFieldsOf returns which of the @fields of the class @name are unknown to the Go type registered for it,
+// which is resolved by decoding an object of the class without any field from @nameRaw, the bytes of the name.
+// It's nil if none of them is unknown, the class isn't registered, or it's decoded by its own serializer
+// which doesn't map the fields to the ones of the structs.
+func unknownFieldsOf(name string, nameRaw []byte, fields []string) []bool {
+	if _, serialized := hessian.GetSerializer(name); serialized {
+		return nil
+	}
+	probe := append(append([]byte{'C'}, nameRaw...), 0x90, 0x60)
+	object, err := hessian.NewDecoder(probe).Decode()
+	if err != nil {
+		return nil
+	}
+	typ := reflect.TypeOf(object)
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return nil
+	}
+	var unknown []bool
+	for i, field := range fields {
+		if _, ok := structField(typ, field); !ok {
+			if unknown == nil {
+				unknown = make([]bool, len(fields))
+			}
+			unknown[i] = true
+		}
+	}
+	return unknown
+}
+
+// unknownField scans the value of the unknown field @name of the class @class, which is rejected under the error
+// policy. Under the capture policy the value is decoded on its own with the class definitions before it and put
+// into @captured, and the values referring to the other ones aren't captured since the references are out
+// of their scope.
+func (s *sizeScanner) unknownField(name, class string, captured map[string]interface{}) error {
+	if s.unknown.policy == UnknownFieldsError {
+		return perrors.WithMessagef(ErrUnknownField, "field %s of class %s", name, class)
+	}
+	start, classes, refs := s.offset, len(s.classes), s.refs
+	if err := s.value(); err != nil {
+		return err
+	}
+	if s.refs != refs {
+		logger.Warnf("[Unknown Fields] the field %s of class %s refers to the other values and isn't captured", name, class)
+		return nil
+	}
+	var data []byte
+	for _, def := range s.classes[:classes] {
+		data = append(append(data, 'C'), def.raw...)
+	}
+	value, err := hessian.NewDecoder(append(data, s.body[start:s.offset]...)).Decode()
+	if err != nil {
+		logger.Warnf("[Unknown Fields] the field %s of class %s isn't captured: %v", name, class, err)
+		return nil
+	}
+	if value = s.unknown.mapping.decodeValue(value); value != nil {
+		captured[name] = value
+	}
+	return nil
+}
+
+// structField finds the field of @typ which the hessian2 field @name is decoded into, in the same way as hessian2
+func structField(typ reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if tag, ok := field.Tag.Lookup("hessian"); ok && tag == name ||
+			field.Name == name || lowerCamelCase(field.Name) == name || strings.ToLower(field.Name) == name {
+			return field, true
+		}
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if embedded, ok := structField(field.Type, name); ok {
+				embedded.Index = append([]int{i}, embedded.Index...)
+				return embedded, true
+			}
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+func lowerCamelCase(s string) string {
+	runes := []rune(s)
+	runes[0] = unicode.ToLower(runes[0])
+	return string(runes)
+}
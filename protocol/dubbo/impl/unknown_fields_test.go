@@ -0,0 +1,180 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package impl
+
+import (
+	"testing"
+)
+
+import (
+	hessian "github.com/apache/dubbo-go-hessian2"
+
+	perrors "github.com/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const unknownFieldsUserClass = "com.ikurento.user.UnknownFieldsUser"
+
+// unknownFieldsUser is the older version of unknownFieldsUserV2 without the email and the address
+type unknownFieldsUser struct {
+	Name    string
+	Friends []*unknownFieldsUser
+	unknown map[string]interface{}
+}
+
+func (unknownFieldsUser) JavaClassName() string {
+	return unknownFieldsUserClass
+}
+
+func (u *unknownFieldsUser) SetUnknownFields(fields map[string]interface{}) {
+	u.unknown = fields
+}
+
+type unknownFieldsUserV2 struct {
+	Name    string
+	Email   string
+	Address *unknownFieldsAddress
+	Friends []*unknownFieldsUserV2
+}
+
+func (unknownFieldsUserV2) JavaClassName() string {
+	return unknownFieldsUserClass
+}
+
+type unknownFieldsAddress struct {
+	City string
+}
+
+func (unknownFieldsAddress) JavaClassName() string {
+	return "com.ikurento.user.UnknownFieldsAddress"
+}
+
+// encodeNewerUser encodes the request and the response carrying the newer user, and the older one is
+// registered for the decoding afterwards
+func encodeNewerUser(t *testing.T) ([]byte, []byte) {
+	hessian.RegisterPOJO(&unknownFieldsAddress{})
+	hessian.RegisterPOJO(&unknownFieldsUserV2{})
+	user := &unknownFieldsUserV2{
+		Name: "Alex", Email: "alex@example.com", Address: &unknownFieldsAddress{City: "Hangzhou"},
+		Friends: []*unknownFieldsUserV2{{Name: "Bob", Email: "bob@example.com"}},
+	}
+	request := encodeRequestBody(t, "Lcom/ikurento/user/UnknownFieldsUser;", user)
+
+	pkg := NewDubboPackage(nil)
+	pkg.Header.Type = PackageResponse
+	pkg.Header.ResponseStatus = Response_OK
+	pkg.SetBody(NewResponsePayload(user, nil, map[string]interface{}{}))
+	response, err := HessianSerializer{}.Marshal(*pkg)
+	assert.NoError(t, err)
+
+	hessian.UnRegisterPOJOs(&unknownFieldsUserV2{})
+	hessian.RegisterPOJO(&unknownFieldsUser{})
+	return request, response
+}
+
+func decodeOlderUser(request, response []byte, opts *Options) ([]*unknownFieldsUser, []error) {
+	pkg := NewDubboPackage(nil)
+	pkg.Header.Type = PackageRequest
+	pkg.Codec.SetOptions(opts)
+	reqErr := unmarshalRequestBody(request, pkg)
+	var arg *unknownFieldsUser
+	if reqErr == nil {
+		arg = pkg.GetBody().(map[string]interface{})["args"].([]interface{})[0].(*unknownFieldsUser)
+	}
+
+	reply := &unknownFieldsUser{}
+	pkg = NewDubboPackage(nil)
+	pkg.Header.Type = PackageResponse
+	pkg.Codec.SetOptions(opts)
+	pkg.SetBody(NewResponsePayload(reply, nil, nil))
+	rspErr := HessianSerializer{}.Unmarshal(response, pkg)
+	return []*unknownFieldsUser{arg, reply}, []error{reqErr, rspErr}
+}
+
+func TestUnknownFieldsPolicy(t *testing.T) {
+	request, response := encodeNewerUser(t)
+	defer hessian.UnRegisterPOJOs(&unknownFieldsUser{})
+
+	// the unknown fields are dropped by default
+	opts := NewOptions()
+	users, errs := decodeOlderUser(request, response, opts)
+	assert.Equal(t, []error{nil, nil}, errs)
+	for _, user := range users {
+		assert.Equal(t, "Alex", user.Name)
+		assert.Equal(t, "Bob", user.Friends[0].Name)
+		assert.Nil(t, user.unknown)
+		assert.Nil(t, user.Friends[0].unknown)
+	}
+
+	errorOpts := NewOptions()
+	assert.NoError(t, errorOpts.SetUnknownFieldsPolicy(UnknownFieldsError))
+	_, errs = decodeOlderUser(request, response, errorOpts)
+	for _, err := range errs {
+		assert.Equal(t, ErrUnknownField, perrors.Cause(err))
+		assert.Contains(t, err.Error(), "field email of class "+unknownFieldsUserClass)
+	}
+
+	// the nested objects capture their own unknown fields, and the null ones are left out
+	captureOpts := NewOptions()
+	assert.NoError(t, captureOpts.SetUnknownFieldsPolicy(UnknownFieldsCapture))
+	users, errs = decodeOlderUser(request, response, captureOpts)
+	assert.Equal(t, []error{nil, nil}, errs)
+	for _, user := range users {
+		assert.Equal(t, "Alex", user.Name)
+		assert.Equal(t, map[string]interface{}{
+			"email": "alex@example.com", "address": &unknownFieldsAddress{City: "Hangzhou"},
+		}, user.unknown)
+		assert.Equal(t, "Bob", user.Friends[0].Name)
+		assert.Equal(t, map[string]interface{}{"email": "bob@example.com"}, user.Friends[0].unknown)
+	}
+
+	// the policy is scoped to the options, and the default ones still drop the unknown fields
+	users, errs = decodeOlderUser(request, response, opts)
+	assert.Equal(t, []error{nil, nil}, errs)
+	assert.Nil(t, users[0].unknown)
+
+	assert.Error(t, opts.SetUnknownFieldsPolicy("strict"))
+}
+
+func TestUnknownFieldsCaptureInMap(t *testing.T) {
+	hessian.RegisterPOJO(&unknownFieldsAddress{})
+	hessian.RegisterPOJO(&unknownFieldsUserV2{})
+	users := map[string]interface{}{
+		"alex": &unknownFieldsUserV2{Name: "Alex", Email: "alex@example.com"},
+		"bob":  &unknownFieldsUserV2{Name: "Bob", Email: "bob@example.com"},
+	}
+	request := encodeRequestBody(t, "Ljava/util/Map;", users)
+	hessian.UnRegisterPOJOs(&unknownFieldsUserV2{})
+	hessian.RegisterPOJO(&unknownFieldsUser{})
+	defer hessian.UnRegisterPOJOs(&unknownFieldsUser{})
+
+	opts := NewOptions()
+	assert.NoError(t, opts.SetUnknownFieldsPolicy(UnknownFieldsCapture))
+	pkg := NewDubboPackage(nil)
+	pkg.Header.Type = PackageRequest
+	pkg.Codec.SetOptions(opts)
+	assert.NoError(t, unmarshalRequestBody(request, pkg))
+	arg := pkg.GetBody().(map[string]interface{})["args"].([]interface{})[0].(map[interface{}]interface{})
+
+	// the objects held by the maps capture their own unknown fields by their keys
+	for name, email := range map[string]string{"alex": "alex@example.com", "bob": "bob@example.com"} {
+		user := arg[name].(*unknownFieldsUser)
+		assert.Equal(t, map[string]interface{}{"email": email}, user.unknown)
+	}
+}
@@ -0,0 +1,110 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dubbo
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+type timedResult struct {
+	result   protocol.Result
+	panicked interface{}
+}
+
+// invokeWithServerTimeout invokes @invoker within the server timeout, and returns the ServerTimeoutError once
+// it elapses so that the dispatch slot of the request is released. The invocation goes on in its own goroutine
+// with the context done at the deadline, and its result is discarded.
+func invokeWithServerTimeout(ctx context.Context, invoker protocol.Invoker, inv *invocation.RPCInvocation) protocol.Result {
+	timeout := getServerTimeout(invoker, inv)
+	if timeout <= 0 {
+		return invoker.Invoke(ctx, inv)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan timedResult, 1)
+	go func() {
+		defer func() {
+			if e := recover(); e != nil {
+				done <- timedResult{panicked: e}
+			}
+		}()
+		done <- timedResult{result: invoker.Invoke(ctx, inv)}
+	}()
+
+	// the timer bounds the wait once the context is cancelled before the deadline
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case r := <-done:
+		return r.get()
+	case <-ctx.Done():
+		if ctx.Err() != context.DeadlineExceeded {
+			// the request is cancelled by the consumer, which is handled by the invocation itself within the timeout
+			select {
+			case r := <-done:
+				return r.get()
+			case <-timer.C:
+			}
+		}
+	}
+	logger.Warnf("The invocation of %s#%s is abandoned after the server timeout %s",
+		inv.ServiceKey(), inv.MethodName(), timeout)
+	return &protocol.RPCResult{Err: remoting.NewServerTimeoutError(timeout)}
+}
+
+func (r timedResult) get() protocol.Result {
+	if r.panicked != nil {
+		// the panic is handled by the server as if it's raised by the dispatch goroutine
+		panic(r.panicked)
+	}
+	return r.result
+}
+
+// getServerTimeout returns the shorter one of the server timeout of the invoked method and the timeout of the
+// consumer, since the consumer doesn't wait for the response any longer after its own timeout.
+// The server timeout isn't enforced if it isn't configured.
+func getServerTimeout(invoker protocol.Invoker, inv *invocation.RPCInvocation) time.Duration {
+	url := invoker.GetURL()
+	value := url.GetMethodParam(inv.MethodName(), constant.SERVER_TIMEOUT_KEY, url.GetParam(constant.SERVER_TIMEOUT_KEY, ""))
+	if value == "" {
+		return 0
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		logger.Warnf("The server timeout %s of %s is invalid", value, url.ServiceKey())
+		return 0
+	}
+	// the consumer passes its timeout in milliseconds
+	if millis, err := strconv.ParseInt(inv.AttachmentsByKey(constant.TIMEOUT_KEY, ""), 10, 64); err == nil && millis > 0 {
+		if consumerTimeout := time.Duration(millis) * time.Millisecond; consumerTimeout < timeout {
+			timeout = consumerTimeout
+		}
+	}
+	return timeout
+}
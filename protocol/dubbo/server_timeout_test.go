@@ -0,0 +1,129 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dubbo
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+type sleepInvoker struct {
+	protocol.BaseInvoker
+	ctxDone chan error
+}
+
+func (s *sleepInvoker) Invoke(ctx context.Context, inv protocol.Invocation) protocol.Result {
+	sleep, _ := time.ParseDuration(inv.Arguments()[0].(string))
+	select {
+	case <-time.After(sleep):
+		return &protocol.RPCResult{Rest: "done"}
+	case <-ctx.Done():
+		s.ctxDone <- ctx.Err()
+		return &protocol.RPCResult{Err: ctx.Err()}
+	}
+}
+
+func TestInvokeWithServerTimeout(t *testing.T) {
+	url, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?side=provider" +
+		"&server.timeout=100ms&methods.GetUser.server.timeout=300ms")
+	assert.NoError(t, err)
+	invoker := &sleepInvoker{BaseInvoker: *protocol.NewBaseInvoker(url), ctxDone: make(chan error, 1)}
+	invoke := func(method string, sleep string, consumerTimeout string) (protocol.Result, time.Duration) {
+		attachments := map[string]interface{}{}
+		if consumerTimeout != "" {
+			attachments[constant.TIMEOUT_KEY] = consumerTimeout
+		}
+		start := time.Now()
+		result := invokeWithServerTimeout(context.Background(), invoker,
+			invocation.NewRPCInvocation(method, []interface{}{sleep}, attachments))
+		return result, time.Since(start)
+	}
+
+	// the invocation completed in time
+	result, _ := invoke("UpdateUser", "10ms", "")
+	assert.NoError(t, result.Error())
+	assert.Equal(t, "done", result.Result())
+
+	// the slot is released at the server timeout while the invocation is still in flight
+	result, elapsed := invoke("UpdateUser", "10s", "")
+	assert.Equal(t, remoting.NewServerTimeoutError(100*time.Millisecond), result.Error())
+	assert.Less(t, int64(elapsed), int64(time.Second))
+	assert.Equal(t, context.DeadlineExceeded, <-invoker.ctxDone)
+
+	// the server timeout of the method overrides the one of the service
+	result, _ = invoke("GetUser", "200ms", "")
+	assert.NoError(t, result.Error())
+	result, _ = invoke("GetUser", "10s", "")
+	assert.Equal(t, remoting.NewServerTimeoutError(300*time.Millisecond), result.Error())
+	<-invoker.ctxDone
+
+	// the shorter timeout of the consumer wins, and the longer one doesn't extend the server timeout
+	result, _ = invoke("GetUser", "10s", "50")
+	assert.Equal(t, remoting.NewServerTimeoutError(50*time.Millisecond), result.Error())
+	<-invoker.ctxDone
+	result, _ = invoke("UpdateUser", "10s", "3000")
+	assert.Equal(t, remoting.NewServerTimeoutError(100*time.Millisecond), result.Error())
+	<-invoker.ctxDone
+
+	// nothing is enforced without the server timeout
+	url, err = common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?side=provider")
+	assert.NoError(t, err)
+	invoker.BaseInvoker = *protocol.NewBaseInvoker(url)
+	result, elapsed = invoke("UpdateUser", "200ms", "50")
+	assert.NoError(t, result.Error())
+	assert.GreaterOrEqual(t, int64(elapsed), int64(200*time.Millisecond))
+}
+
+// blockingInvoker ignores the context of the invocation until it's released
+type blockingInvoker struct {
+	protocol.BaseInvoker
+	release chan struct{}
+}
+
+func (b *blockingInvoker) Invoke(context.Context, protocol.Invocation) protocol.Result {
+	<-b.release
+	return &protocol.RPCResult{Rest: "done"}
+}
+
+func TestInvokeWithServerTimeoutCancelled(t *testing.T) {
+	url, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?side=provider&server.timeout=200ms")
+	assert.NoError(t, err)
+	invoker := &blockingInvoker{BaseInvoker: *protocol.NewBaseInvoker(url), release: make(chan struct{})}
+	defer close(invoker.release)
+
+	// the consumer cancels the request, while the invocation doesn't stop on it
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	result := invokeWithServerTimeout(ctx, invoker, invocation.NewRPCInvocation("GetUser", nil, nil))
+	assert.Equal(t, remoting.NewServerTimeoutError(200*time.Millisecond), result.Error())
+	assert.Less(t, int64(time.Since(start)), int64(time.Second))
+}
@@ -0,0 +1,125 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dubbo
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/protocol/dubbo/impl"
+)
+
+var (
+	// serviceOptions are the options of the hessian2 serialization of the exported services by the keys of
+	// their paths and versions. The groups of a service share the options of the one exported last, since
+	// the group of a request isn't decoded until its attachments.
+	serviceOptions     = make(map[string]*impl.Options)
+	serviceOptionsLock sync.RWMutex
+)
+
+func serviceOptionsKey(path, version string) string {
+	return common.ServiceKey(path, "", version)
+}
+
+// exportedServiceOptionsKey returns the key of the service exported by @url, whose interface is the path
+// of the requests
+func exportedServiceOptionsKey(url *common.URL) string {
+	return serviceOptionsKey(url.GetParam(constant.INTERFACE_KEY, strings.TrimPrefix(url.Path, "/")),
+		url.GetParam(constant.VERSION_KEY, ""))
+}
+
+// newServiceOptions returns the options of the hessian2 serialization configured by the params of @url, which is
+// the one of a service or a reference
+func newServiceOptions(url *common.URL) *impl.Options {
+	opts := impl.NewOptions()
+	allowed := url.GetParam(constant.SERIALIZATION_ALLOWLIST_KEY, "")
+	denied := url.GetParam(constant.SERIALIZATION_DENYLIST_KEY, "")
+	if allowed != "" || denied != "" {
+		opts.SetSerializationCheck(splitClasses(allowed), splitClasses(denied))
+	}
+	opts.SetSizeLimits(url.GetParamByIntValue(constant.SERIALIZATION_MAX_STRING_LENGTH_KEY, 0),
+		url.GetParamByIntValue(constant.SERIALIZATION_MAX_COLLECTION_SIZE_KEY, 0))
+	setTypeMapping(url, opts)
+	if err := opts.SetUnknownFieldsPolicy(url.GetParam(constant.SERIALIZATION_UNKNOWN_FIELDS_KEY, "")); err != nil {
+		logger.Warnf("The unknown fields policy of %s is invalid: %v", url.ServiceKey(), err)
+	}
+	// the url of a reference has the advertisement of the provider merged
+	if url.GetParamBool(constant.COMPRESS_KEY, false) &&
+		url.GetParam(constant.COMPRESS_SUPPORTED_KEY, "") == constant.GZIP_COMPRESSION {
+		opts.SetCompression(url.GetParamByIntValue(constant.COMPRESS_MIN_SIZE_KEY, 0))
+	}
+	return opts
+}
+
+// setTypeMapping sets the Go types of the java decimals and dates configured by @url to @opts, the ones
+// not configured keep the default ones.
+func setTypeMapping(url *common.URL, opts *impl.Options) {
+	var location *time.Location
+	if name := url.GetParam(constant.HESSIAN_TIME_LOCATION_KEY, ""); name != "" {
+		var err error
+		if location, err = time.LoadLocation(name); err != nil {
+			logger.Warnf("The time location %s is invalid: %v", name, err)
+		}
+	}
+	decimalType := url.GetParam(constant.HESSIAN_DECIMAL_TYPE_KEY, "")
+	timeType := url.GetParam(constant.HESSIAN_TIME_TYPE_KEY, "")
+	if err := opts.SetTypeMapping(decimalType, timeType, location); err != nil {
+		logger.Warnf("The hessian type mapping of %s is invalid: %v", url.ServiceKey(), err)
+	}
+}
+
+func splitClasses(classes string) []string {
+	var result []string
+	for _, class := range strings.Split(classes, constant.COMMA_SEPARATOR) {
+		if class = strings.TrimSpace(class); class != "" {
+			result = append(result, class)
+		}
+	}
+	return result
+}
+
+// storeServiceOptions stores the options of the service exported by @url
+func storeServiceOptions(url *common.URL, opts *impl.Options) {
+	serviceOptionsLock.Lock()
+	serviceOptions[exportedServiceOptionsKey(url)] = opts
+	serviceOptionsLock.Unlock()
+}
+
+// removeServiceOptions removes the options of the service exported by @url, unless they are replaced
+// by another group of the service.
+func removeServiceOptions(url *common.URL, opts *impl.Options) {
+	key := exportedServiceOptionsKey(url)
+	serviceOptionsLock.Lock()
+	if serviceOptions[key] == opts {
+		delete(serviceOptions, key)
+	}
+	serviceOptionsLock.Unlock()
+}
+
+// lookupServiceOptions resolves the options of the service of the requests decoded by the servers
+func lookupServiceOptions(path, version string) *impl.Options {
+	serviceOptionsLock.RLock()
+	defer serviceOptionsLock.RUnlock()
+	return serviceOptions[serviceOptionsKey(path, version)]
+}
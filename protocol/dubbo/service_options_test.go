@@ -0,0 +1,146 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dubbo
+
+import (
+	"strings"
+	"testing"
+)
+
+import (
+	big "github.com/dubbogo/gost/math/big"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/dubbo/impl"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+func TestServiceOptions(t *testing.T) {
+	strictURL, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.StrictProvider",
+		common.WithParamsValue(constant.SERIALIZATION_ALLOWLIST_KEY, "com.ikurento.user."),
+		common.WithParamsValue(constant.VERSION_KEY, "1.0.0"))
+	assert.NoError(t, err)
+	groupURL, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.StrictProvider",
+		common.WithParamsValue(constant.GROUP_KEY, "other"),
+		common.WithParamsValue(constant.VERSION_KEY, "1.0.0"))
+	assert.NoError(t, err)
+
+	strict := newServiceOptions(strictURL)
+	storeServiceOptions(strictURL, strict)
+	assert.Equal(t, strict, lookupServiceOptions("com.ikurento.user.StrictProvider", "1.0.0"))
+	// the other services keep the default options
+	assert.Nil(t, lookupServiceOptions("com.ikurento.user.StrictProvider", ""))
+	assert.Nil(t, lookupServiceOptions("com.ikurento.user.UserProvider", "1.0.0"))
+
+	// the options replaced by another group aren't removed by the former one
+	group := newServiceOptions(groupURL)
+	storeServiceOptions(groupURL, group)
+	removeServiceOptions(strictURL, strict)
+	assert.Equal(t, group, lookupServiceOptions("com.ikurento.user.StrictProvider", "1.0.0"))
+	removeServiceOptions(groupURL, group)
+	assert.Nil(t, lookupServiceOptions("com.ikurento.user.StrictProvider", "1.0.0"))
+}
+
+// decodeDecimalRequest encodes the request of the decimal to the service of @path with @opts and returns
+// the argument decoded by the server
+func decodeDecimalRequest(t *testing.T, path string, opts interface{}) interface{} {
+	decimal := &big.Decimal{}
+	assert.NoError(t, decimal.FromString("1.10"))
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("Pay"),
+		invocation.WithArguments([]interface{}{decimal}),
+		invocation.WithAttachments(map[string]interface{}{constant.PATH_KEY: path, constant.INTERFACE_KEY: path}))
+	inv.SetAttribute(constant.CODEC_OPTIONS_KEY, opts)
+	var rpcInvocation protocol.Invocation = inv
+	request := remoting.NewRequest("2.0.2")
+	request.Data = &rpcInvocation
+	request.TwoWay = true
+
+	codec := &DubboCodec{}
+	data, err := codec.EncodeRequest(request)
+	assert.NoError(t, err)
+	result, _, err := codec.Decode(data.Bytes())
+	assert.NoError(t, err)
+	return result.Result.(*remoting.Request).Data.(*invocation.RPCInvocation).Arguments()[0]
+}
+
+func TestServiceOptionsTypeMapping(t *testing.T) {
+	stringURL, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.DecimalProvider",
+		common.WithParamsValue(constant.HESSIAN_DECIMAL_TYPE_KEY, "string"),
+		common.WithParamsValue(constant.HESSIAN_TIME_LOCATION_KEY, "Asia/Shanghai"))
+	assert.NoError(t, err)
+	plainURL, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider")
+	assert.NoError(t, err)
+
+	stringOpts := newServiceOptions(stringURL)
+	storeServiceOptions(stringURL, stringOpts)
+	defer removeServiceOptions(stringURL, stringOpts)
+
+	// the services and the references map the decimals by their own params
+	assert.Equal(t, "1.10", decodeDecimalRequest(t, "com.ikurento.user.DecimalProvider", newServiceOptions(plainURL)))
+	decimal, ok := decodeDecimalRequest(t, "com.ikurento.user.UserProvider", stringOpts).(*big.Decimal)
+	assert.True(t, ok)
+	assert.Equal(t, "1.10", decimal.String())
+}
+
+func TestServiceOptionsCompression(t *testing.T) {
+	referenceURL, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider",
+		common.WithParamsValue(constant.COMPRESS_KEY, "true"),
+		common.WithParamsValue(constant.COMPRESS_MIN_SIZE_KEY, "16"))
+	assert.NoError(t, err)
+	// the requests aren't compressed to the providers not advertising it
+	assert.False(t, newServiceOptions(referenceURL).CompressionEnabled())
+	referenceURL.SetParam(constant.COMPRESS_SUPPORTED_KEY, constant.GZIP_COMPRESSION)
+	opts := newServiceOptions(referenceURL)
+	assert.True(t, opts.CompressionEnabled())
+
+	path := "com.ikurento.user.UserProvider"
+	for _, accept := range []bool{true, false} {
+		attachments := map[string]interface{}{constant.PATH_KEY: path, constant.INTERFACE_KEY: path}
+		if accept {
+			attachments[constant.COMPRESS_ACCEPT_KEY] = constant.GZIP_COMPRESSION
+		}
+		inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUser"),
+			invocation.WithArguments([]interface{}{strings.Repeat("dubbo-go;", 100)}),
+			invocation.WithAttachments(attachments))
+		inv.SetAttribute(constant.CODEC_OPTIONS_KEY, opts)
+		var rpcInvocation protocol.Invocation = inv
+		request := remoting.NewRequest("2.0.2")
+		request.Data = &rpcInvocation
+		request.TwoWay = true
+
+		codec := &DubboCodec{}
+		data, err := codec.EncodeRequest(request)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{0x1f, 0x8b}, data.Bytes()[impl.HEADER_LENGTH:impl.HEADER_LENGTH+2])
+		storeServiceOptions(referenceURL, opts)
+		result, _, err := codec.Decode(data.Bytes())
+		removeServiceOptions(referenceURL, opts)
+		assert.NoError(t, err)
+		// the response is compressed only to the consumer accepting it
+		decoded := result.Result.(*remoting.Request)
+		assert.Equal(t, accept, decoded.CodecOptions.(*impl.Options).CompressionEnabled())
+		assert.Equal(t, strings.Repeat("dubbo-go;", 100), decoded.Data.(*invocation.RPCInvocation).Arguments()[0])
+	}
+}
@@ -50,6 +50,13 @@ type Invoker interface {
 	Invoke(context.Context, Invocation) Result
 }
 
+// Connector is implemented by the invokers which are able to connect to their providers ahead of the invocations,
+// the invokers without it connect on demand.
+type Connector interface {
+	// Connect establishes the connection to the provider, and it does nothing if the provider is connected already
+	Connect(ctx context.Context) error
+}
+
 /////////////////////////////
 // base invoker
 /////////////////////////////
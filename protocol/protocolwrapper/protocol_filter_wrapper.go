@@ -72,17 +72,19 @@ func (pfw *ProtocolFilterWrapper) Destroy() {
 }
 
 func BuildInvokerChain(invoker protocol.Invoker, key string) protocol.Invoker {
-	filterName := invoker.GetURL().GetParam(key, "")
-	if filterName == "" {
-		return invoker
+	var filters []filter.Filter
+	// The global interceptors wrap the configured filters
+	filters = append(filters, extension.GetGlobalInterceptors()...)
+	if filterName := invoker.GetURL().GetParam(key, ""); filterName != "" {
+		for _, name := range strings.Split(filterName, ",") {
+			filters = append(filters, extension.GetFilter(strings.TrimSpace(name)))
+		}
 	}
-	filterNames := strings.Split(filterName, ",")
 
 	// The order of filters is from left to right, so loading from right to left
 	next := invoker
-	for i := len(filterNames) - 1; i >= 0; i-- {
-		flt := extension.GetFilter(strings.TrimSpace(filterNames[i]))
-		fi := &FilterInvoker{next: next, invoker: invoker, filter: flt}
+	for i := len(filters) - 1; i >= 0; i-- {
+		fi := &FilterInvoker{next: next, invoker: invoker, filter: filters[i]}
 		next = fi
 	}
 	return next
@@ -120,6 +122,14 @@ func (fi *FilterInvoker) Invoke(ctx context.Context, invocation protocol.Invocat
 	return fi.filter.OnResponse(ctx, result, fi.invoker, invocation)
 }
 
+// Connect connects the invoker to its provider if the invoker is a protocol.Connector
+func (fi *FilterInvoker) Connect(ctx context.Context) error {
+	if connector, ok := fi.invoker.(protocol.Connector); ok {
+		return connector.Connect(ctx)
+	}
+	return nil
+}
+
 // Destroy will destroy invoker
 func (fi *FilterInvoker) Destroy() {
 	fi.invoker.Destroy()
@@ -34,6 +34,7 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/common/logger"
 	"dubbo.apache.org/dubbo-go/v3/filter"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
 )
 
 const mockFilterKey = "mockEcho"
@@ -62,6 +63,43 @@ func TestProtocolFilterWrapperRefer(t *testing.T) {
 	assert.True(t, ok)
 }
 
+func TestBuildInvokerChainGlobalInterceptors(t *testing.T) {
+	var order []string
+	extension.AddGlobalInterceptor(2, func() filter.Filter {
+		return &mockStampFilter{key: "correlation-id", value: "inner", order: &order}
+	})
+	extension.AddGlobalInterceptor(1, func() filter.Filter {
+		return &mockStampFilter{key: "correlation-id", value: "outer", order: &order}
+	})
+
+	filtProto := extension.GetProtocol(FILTER)
+	filtProto.(*ProtocolFilterWrapper).protocol = &protocol.BaseProtocol{}
+
+	// the invocation without any filter configured is intercepted as well
+	invoker := filtProto.Refer(common.NewURLWithOptions(common.WithParams(url.Values{})))
+	inv := invocation.NewRPCInvocation("GetUser", nil, nil)
+	invoker.Invoke(context.Background(), inv)
+	assert.Equal(t, "inner", inv.AttachmentsByKey("correlation-id", ""))
+	assert.Equal(t, []string{"outer", "inner"}, order)
+}
+
+// mockStampFilter stamps the attachment, for test
+type mockStampFilter struct {
+	key   string
+	value string
+	order *[]string
+}
+
+func (f *mockStampFilter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	*f.order = append(*f.order, f.value)
+	invocation.SetAttachments(f.key, f.value)
+	return invoker.Invoke(ctx, invocation)
+}
+
+func (f *mockStampFilter) OnResponse(_ context.Context, result protocol.Result, _ protocol.Invoker, _ protocol.Invocation) protocol.Result {
+	return result
+}
+
 // The initialization of mockEchoFilter, for test
 func init() {
 	extension.SetFilter(mockFilterKey, newFilter)
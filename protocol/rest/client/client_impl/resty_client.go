@@ -82,7 +82,7 @@ func (rc *RestyClient) Do(restRequest *client.RestClientRequest, res interface{}
 		return perrors.WithStack(err)
 	}
 	if resp.IsError() {
-		return perrors.New(resp.String())
+		return perrors.WithStack(&client.StatusError{StatusCode: resp.StatusCode(), Body: resp.String()})
 	}
 	return nil
 }
@@ -39,6 +39,17 @@ type RestClientRequest struct {
 	Body        interface{}
 }
 
+// StatusError is returned by the RestClient when the status code of the response indicates an error
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+// Error returns the body of the response
+func (e *StatusError) Error() string {
+	return e.Body
+}
+
 // RestClient user can implement this client interface to send request
 type RestClient interface {
 	Do(request *RestClientRequest, res interface{}) error
@@ -0,0 +1,312 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package openapi generates the OpenAPI 3.0 documents of the rest services from the same method configs
+// as the ones the rest servers route the requests by.
+package openapi
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/protocol/rest/config"
+)
+
+// Version is the version of the OpenAPI specification the documents follow
+const Version = "3.0.3"
+
+// Document is the OpenAPI document of the rest services deployed on a server
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+
+	// schemaNames are the names of the struct types in the components
+	schemaNames map[reflect.Type]string
+}
+
+// Info is the metadata of the document
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// PathItem holds the operations of a path by the lower case http methods, e.g. get
+type PathItem map[string]*Operation
+
+// Operation is a rest method of the services
+type Operation struct {
+	Tags        []string            `json:"tags,omitempty"`
+	OperationID string              `json:"operationId"`
+	Parameters  []*Parameter        `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+// Parameter is a parameter bound from the path, query or headers
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+// RequestBody is the parameter bound from the body
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response is the response of an operation
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType is the schema of a body in a content type
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds the schemas of the struct types by their names
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas,omitempty"`
+}
+
+// Schema is the schema of a Go type, which refers to the components for the structs
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+var (
+	typeOfTime  = reflect.TypeOf(time.Time{})
+	typeOfBytes = reflect.TypeOf([]byte(nil))
+	// pathParamRegex matches the path params with the regular expressions of go-restful, e.g. {id:[0-9]+}
+	pathParamRegex = regexp.MustCompile(`\{([^}:]+):[^}]*\}`)
+)
+
+// NewDocument creates the empty document
+func NewDocument(title, version string) *Document {
+	return &Document{
+		OpenAPI:     Version,
+		Info:        Info{Title: title, Version: version},
+		Paths:       make(map[string]PathItem),
+		Components:  Components{Schemas: make(map[string]*Schema)},
+		schemaNames: make(map[reflect.Type]string),
+	}
+}
+
+// AddService adds the operations of the methods of @svc deployed by @methodConfigs, the parameters and
+// the responses are described by the types of the methods.
+func (d *Document) AddService(interfaceName string, svc *common.Service, methodConfigs map[string]*config.RestMethodConfig) {
+	names := make([]string, 0, len(methodConfigs))
+	for name := range methodConfigs {
+		names = append(names, name)
+	}
+	// the schema names are stable in the order of the methods
+	sort.Strings(names)
+	for _, name := range names {
+		methodConfig := methodConfigs[name]
+		var method *common.MethodType
+		if svc != nil {
+			method = svc.Method()[methodConfig.MethodName]
+		}
+		path := pathParamRegex.ReplaceAllString(methodConfig.Path, "{$1}")
+		item, ok := d.Paths[path]
+		if !ok {
+			item = make(PathItem)
+			d.Paths[path] = item
+		}
+		item[strings.ToLower(methodConfig.MethodType)] = d.operation(interfaceName, method, methodConfig)
+	}
+}
+
+func (d *Document) operation(interfaceName string, method *common.MethodType, methodConfig *config.RestMethodConfig) *Operation {
+	var argsTypes []reflect.Type
+	var replyType reflect.Type
+	if method != nil {
+		argsTypes = method.ArgsType()
+		replyType = method.ReplyType()
+	}
+	bound := make(map[int]bool)
+	argSchema := func(index int) *Schema {
+		bound[index] = true
+		if index < 0 || index >= len(argsTypes) {
+			return &Schema{Type: "string"}
+		}
+		return d.schema(argsTypes[index])
+	}
+
+	op := &Operation{
+		Tags:        []string{interfaceName},
+		OperationID: methodConfig.MethodName,
+		Responses:   make(map[string]Response),
+	}
+	addParameters := func(params map[int]string, in string) {
+		indexes := make([]int, 0, len(params))
+		for index := range params {
+			indexes = append(indexes, index)
+		}
+		sort.Ints(indexes)
+		for _, index := range indexes {
+			op.Parameters = append(op.Parameters, &Parameter{
+				Name:     params[index],
+				In:       in,
+				Required: in == "path",
+				Schema:   argSchema(index),
+			})
+		}
+	}
+	addParameters(methodConfig.PathParamsMap, "path")
+	addParameters(methodConfig.QueryParamsMap, "query")
+	addParameters(methodConfig.HeadersMap, "header")
+	if methodConfig.Body >= 0 {
+		op.RequestBody = &RequestBody{Required: true, Content: content(methodConfig.Consumes, argSchema(methodConfig.Body))}
+	}
+
+	// the reply of the methods like GetUser(req []interface{}, rsp *User) error is its last argument
+	if replyType == nil && len(argsTypes) > 0 && !bound[len(argsTypes)-1] && argsTypes[len(argsTypes)-1].Kind() == reflect.Ptr {
+		replyType = argsTypes[len(argsTypes)-1]
+	}
+	response := Response{Description: "OK"}
+	if replyType != nil {
+		response.Content = content(methodConfig.Produces, d.schema(replyType))
+	}
+	op.Responses["200"] = response
+	op.Responses["500"] = Response{Description: "the invocation fails"}
+	return op
+}
+
+// content describes the body of @schema in each of the comma separated content types
+func content(contentTypes string, schema *Schema) map[string]MediaType {
+	result := make(map[string]MediaType)
+	for _, contentType := range strings.Split(contentTypes, ",") {
+		if contentType = strings.TrimSpace(contentType); contentType != "" {
+			result[contentType] = MediaType{Schema: schema}
+		}
+	}
+	if len(result) == 0 {
+		result["application/json"] = MediaType{Schema: schema}
+	}
+	return result
+}
+
+// schema describes @t as encoding/json marshals it, and the structs are referred to the components
+func (d *Document) schema(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch {
+	case t == typeOfTime:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == typeOfBytes:
+		return &Schema{Type: "string", Format: "byte"}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: d.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: d.schema(t.Elem())}
+	case reflect.Struct:
+		return &Schema{Ref: "#/components/schemas/" + d.structSchema(t)}
+	}
+	// the interfaces may be any value
+	return &Schema{}
+}
+
+// structSchema adds the schema of the struct @t to the components, and returns its name
+func (d *Document) structSchema(t reflect.Type) string {
+	if name, ok := d.schemaNames[t]; ok {
+		return name
+	}
+	base := t.Name()
+	if base == "" {
+		base = "Object"
+	}
+	// the structs of the same name in the different packages are numbered
+	name := base
+	for i := 2; d.Components.Schemas[name] != nil; i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	// registered ahead of the fields for the recursive types
+	d.schemaNames[t] = name
+	d.Components.Schemas[name] = schema
+	d.addFields(schema, t)
+	return name
+}
+
+func (d *Document) addFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := jsonName(field.Tag.Get("json"))
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				// the fields of the embedded structs are promoted
+				d.addFields(schema, ft)
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		schema.Properties[name] = d.schema(field.Type)
+	}
+}
+
+// jsonName returns the name in the json tag of a field
+func jsonName(tag string) string {
+	if i := strings.Index(tag, ","); i >= 0 {
+		return tag[:i]
+	}
+	return tag
+}
@@ -31,6 +31,8 @@ import (
 // nolint
 type RestExporter struct {
 	protocol.BaseExporter
+	// unexported removes the service from the OpenAPI document of its server once it's unexported
+	unexported func()
 }
 
 // NewRestExporter returns a RestExporter
@@ -44,6 +46,9 @@ func NewRestExporter(key string, invoker protocol.Invoker, exporterMap *sync.Map
 func (re *RestExporter) Unexport() {
 	interfaceName := re.GetInvoker().GetURL().GetParam(constant.INTERFACE_KEY, "")
 	re.BaseExporter.Unexport()
+	if re.unexported != nil {
+		re.unexported()
+	}
 	err := common.ServiceMap.UnRegister(interfaceName, REST, re.GetInvoker().GetURL().ServiceKey())
 	if err != nil {
 		logger.Errorf("[RestExporter.Unexport] error: %v", err)
@@ -19,16 +19,25 @@ package rest
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 )
 
 import (
 	perrors "github.com/pkg/errors"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 )
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 	invocation_impl "dubbo.apache.org/dubbo-go/v3/protocol/invocation"
 	"dubbo.apache.org/dubbo-go/v3/protocol/rest/client"
@@ -40,6 +49,7 @@ type RestInvoker struct {
 	protocol.BaseInvoker
 	client              client.RestClient
 	restMethodConfigMap map[string]*config.RestMethodConfig
+	retryStatus         map[int]struct{}
 }
 
 // NewRestInvoker returns a RestInvoker
@@ -48,6 +58,7 @@ func NewRestInvoker(url *common.URL, client *client.RestClient, restMethodConfig
 		BaseInvoker:         *protocol.NewBaseInvoker(url),
 		client:              *client,
 		restMethodConfigMap: restMethodConfig,
+		retryStatus:         parseRetryStatus(url.GetParam(constant.REST_RETRY_STATUS_KEY, constant.DEFAULT_REST_RETRY_STATUS)),
 	}
 }
 
@@ -82,6 +93,13 @@ func (ri *RestInvoker) Invoke(ctx context.Context, invocation protocol.Invocatio
 	if len(inv.Arguments()) > methodConfig.Body && methodConfig.Body >= 0 {
 		body = inv.Arguments()[methodConfig.Body]
 	}
+	pbJSON := ri.GetURL().GetParam(constant.REST_CODEC_KEY, "") == constant.REST_CODEC_PB_JSON
+	if msg, ok := body.(proto.Message); ok && pbJSON {
+		if body, err = protojson.Marshal(msg); err != nil {
+			result.Err = perrors.WithStack(err)
+			return &result
+		}
+	}
 	req := &client.RestClientRequest{
 		Location:    ri.GetURL().Location,
 		Method:      methodConfig.MethodType,
@@ -91,13 +109,52 @@ func (ri *RestInvoker) Invoke(ctx context.Context, invocation protocol.Invocatio
 		Body:        body,
 		Header:      header,
 	}
-	result.Err = ri.client.Do(req, inv.Reply())
+	if reply, ok := inv.Reply().(proto.Message); ok && pbJSON {
+		raw := json.RawMessage{}
+		if result.Err = ri.client.Do(req, &raw); result.Err == nil {
+			result.Err = protojson.Unmarshal(raw, reply)
+		}
+	} else {
+		result.Err = ri.client.Do(req, inv.Reply())
+	}
 	if result.Err == nil {
 		result.Rest = inv.Reply()
+	} else {
+		result.Err = ri.classifyError(result.Err)
 	}
 	return &result
 }
 
+// classifyError marks the error responses whose status codes aren't retryable as the business errors,
+// so that the cluster only fails over the ones caused by the unavailable providers or the network
+func (ri *RestInvoker) classifyError(err error) error {
+	var statusErr *client.StatusError
+	if !errors.As(err, &statusErr) {
+		return err
+	}
+	if _, ok := ri.retryStatus[statusErr.StatusCode]; ok {
+		return err
+	}
+	return protocol.NewBizError(err)
+}
+
+// parseRetryStatus parses the comma separated status codes
+func parseRetryStatus(config string) map[int]struct{} {
+	retryStatus := make(map[int]struct{})
+	for _, code := range strings.Split(config, ",") {
+		if code = strings.TrimSpace(code); len(code) == 0 {
+			continue
+		}
+		statusCode, err := strconv.Atoi(code)
+		if err != nil {
+			logger.Errorf("The status code %s of %s is invalid", code, constant.REST_RETRY_STATUS_KEY)
+			continue
+		}
+		retryStatus[statusCode] = struct{}{}
+	}
+	return retryStatus
+}
+
 // restStringMapTransform is used to transform rest map
 func restStringMapTransform(paramsMap map[int]string, args []interface{}) (map[string]string, error) {
 	resMap := make(map[string]string, len(paramsMap))
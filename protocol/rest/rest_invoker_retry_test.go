@@ -0,0 +1,112 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	_ "dubbo.apache.org/dubbo-go/v3/cluster/cluster/failover"
+	"dubbo.apache.org/dubbo-go/v3/cluster/directory/static"
+	_ "dubbo.apache.org/dubbo-go/v3/cluster/loadbalance/random"
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/protocol/rest/client"
+	"dubbo.apache.org/dubbo-go/v3/protocol/rest/client/client_impl"
+	rest_config "dubbo.apache.org/dubbo-go/v3/protocol/rest/config"
+)
+
+type retryUser struct {
+	Name string `json:"name"`
+}
+
+// newRetryInvoker joins the rest invoker of @server into a failover cluster
+func newRetryInvoker(t *testing.T, server *httptest.Server) protocol.Invoker {
+	url, err := common.NewURL("rest://" + strings.TrimPrefix(server.URL, "http://") +
+		"/com.ikurento.user.UserProvider?retries=2&loadbalance=random")
+	assert.NoError(t, err)
+	restClient := client_impl.NewRestyClient(&client.RestOptions{RequestTimeout: 3 * time.Second, ConnectTimeout: 3 * time.Second})
+	methodConfigMap := map[string]*rest_config.RestMethodConfig{
+		"GetUser": {
+			MethodName: "GetUser",
+			Path:       "/GetUser",
+			MethodType: "GET",
+			Body:       -1,
+		},
+	}
+	invoker := NewRestInvoker(url, &restClient, methodConfigMap)
+	return extension.GetCluster("failover").Join(static.NewDirectory([]protocol.Invoker{invoker}))
+}
+
+func TestRestInvokerRetryStatus(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name":"dubbo"}`))
+	}))
+	defer server.Close()
+
+	user := &retryUser{}
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUser"), invocation.WithReply(user))
+	result := newRetryInvoker(t, server).Invoke(context.Background(), inv)
+	assert.NoError(t, result.Error())
+	assert.Equal(t, "dubbo", user.Name)
+	// the cluster fails over the 503 response
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+}
+
+func TestRestInvokerBizErrorStatus(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("invalid user"))
+	}))
+	defer server.Close()
+
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUser"), invocation.WithReply(&retryUser{}))
+	result := newRetryInvoker(t, server).Invoke(context.Background(), inv)
+	assert.True(t, protocol.IsBizError(result.Error()))
+	assert.Equal(t, "invalid user", result.Error().Error())
+	// the business error isn't retried
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
+
+func TestParseRetryStatus(t *testing.T) {
+	_, ok := parseRetryStatus(constant.DEFAULT_REST_RETRY_STATUS)[503]
+	assert.True(t, ok)
+	assert.Len(t, parseRetryStatus("429, 503,abc"), 2)
+}
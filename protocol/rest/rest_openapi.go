@@ -0,0 +1,148 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	rest_config "dubbo.apache.org/dubbo-go/v3/protocol/rest/config"
+	"dubbo.apache.org/dubbo-go/v3/protocol/rest/openapi"
+	"dubbo.apache.org/dubbo-go/v3/protocol/rest/server"
+)
+
+// openAPIDocument is the OpenAPI document served on a path of a server, which is rebuilt from its services
+// once they are exported or unexported
+type openAPIDocument struct {
+	route    *rest_config.RestMethodConfig
+	services map[string]*documentedService
+	document *openapi.Document
+}
+
+// documentedService is a service exported with the path of the document
+type documentedService struct {
+	interfaceName string
+	svc           *common.Service
+	methodConfigs map[string]*rest_config.RestMethodConfig
+}
+
+func openAPIDocumentKey(url *common.URL, path string) string {
+	return url.Location + path
+}
+
+// rebuild regenerates the document from its services in the order of their keys, so that the operations and
+// the schemas of the unexported ones are removed as well
+func (d *openAPIDocument) rebuild(title string) {
+	keys := make([]string, 0, len(d.services))
+	for key := range d.services {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	document := openapi.NewDocument(title, constant.Version)
+	for _, key := range keys {
+		service := d.services[key]
+		document.AddService(service.interfaceName, service.svc, service.methodConfigs)
+	}
+	d.document = document
+}
+
+// addOpenAPIDocument adds the service exported by @url to the OpenAPI document of its server, which is served
+// on the path configured by rest.openapi.path, e.g. /openapi.json. The services with the same path share
+// the document, and the ones without the path aren't documented.
+func (rp *RestProtocol) addOpenAPIDocument(url *common.URL, restServer server.RestServer, serviceConfig *rest_config.RestServiceConfig) {
+	path := url.GetParam(constant.REST_OPENAPI_PATH_KEY, "")
+	if path == "" {
+		return
+	}
+	rp.documentLock.Lock()
+	defer rp.documentLock.Unlock()
+	key := openAPIDocumentKey(url, path)
+	document, ok := rp.documentMap[key]
+	if !ok {
+		document = &openAPIDocument{
+			route: &rest_config.RestMethodConfig{
+				MethodName: "OpenAPI",
+				Path:       path,
+				MethodType: http.MethodGet,
+				Produces:   "application/json",
+				Consumes:   "*/*",
+			},
+			services: make(map[string]*documentedService),
+		}
+		rp.documentMap[key] = document
+		restServer.Deploy(document.route, func(_ server.RestServerRequest, resp server.RestServerResponse) {
+			rp.writeOpenAPIDocument(document, resp)
+		})
+	}
+	document.services[url.ServiceKey()] = &documentedService{
+		interfaceName: url.GetParam(constant.INTERFACE_KEY, url.Service()),
+		svc:           common.ServiceMap.GetServiceByServiceKey(url.Protocol, url.ServiceKey()),
+		methodConfigs: serviceConfig.RestMethodConfigsMap,
+	}
+	document.rebuild(url.GetParam(constant.APPLICATION_KEY, "dubbo-go"))
+}
+
+// removeOpenAPIDocument removes the service exported by @url from the OpenAPI document of its server, and
+// the route of the document is removed with its last service.
+func (rp *RestProtocol) removeOpenAPIDocument(url *common.URL) {
+	path := url.GetParam(constant.REST_OPENAPI_PATH_KEY, "")
+	if path == "" {
+		return
+	}
+	rp.documentLock.Lock()
+	defer rp.documentLock.Unlock()
+	key := openAPIDocumentKey(url, path)
+	document, ok := rp.documentMap[key]
+	if !ok {
+		return
+	}
+	delete(document.services, url.ServiceKey())
+	if len(document.services) > 0 {
+		document.rebuild(url.GetParam(constant.APPLICATION_KEY, "dubbo-go"))
+		return
+	}
+	delete(rp.documentMap, key)
+	rp.serverLock.Lock()
+	restServer, ok := rp.serverMap[url.Location]
+	rp.serverLock.Unlock()
+	if ok {
+		restServer.UnDeploy(document.route)
+	}
+}
+
+func (rp *RestProtocol) writeOpenAPIDocument(document *openAPIDocument, resp server.RestServerResponse) {
+	rp.documentLock.Lock()
+	body, err := json.Marshal(document.document)
+	rp.documentLock.Unlock()
+	if err != nil {
+		logger.Errorf("[Rest Protocol] marshal the OpenAPI document error: %v", err)
+		_ = resp.WriteError(http.StatusInternalServerError, err)
+		return
+	}
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(body); err != nil {
+		logger.Errorf("[Rest Protocol] write the OpenAPI document error: %v", err)
+	}
+}
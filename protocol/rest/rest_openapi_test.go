@@ -0,0 +1,218 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	_ "dubbo.apache.org/dubbo-go/v3/common/proxy/proxy_factory"
+	rest_config "dubbo.apache.org/dubbo-go/v3/protocol/rest/config"
+	"dubbo.apache.org/dubbo-go/v3/protocol/rest/openapi"
+)
+
+type OpenAPIUser struct {
+	ID     int      `json:"id"`
+	Name   string   `json:"name,omitempty"`
+	Tags   []string `json:"tags"`
+	Friend *OpenAPIUser
+	secret string
+}
+
+type OpenAPIUserProvider struct{}
+
+func (p *OpenAPIUserProvider) Reference() string {
+	return "com.ikurento.user.OpenAPIUserProvider"
+}
+
+func (p *OpenAPIUserProvider) GetUser(_ context.Context, id int, name string) (*OpenAPIUser, error) {
+	return &OpenAPIUser{ID: id, Name: name}, nil
+}
+
+func (p *OpenAPIUserProvider) UpdateUser(_ context.Context, user *OpenAPIUser) (*OpenAPIUser, error) {
+	return user, nil
+}
+
+func TestRestProtocolExportOpenAPI(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	address := ln.Addr().String()
+	assert.NoError(t, ln.Close())
+
+	url, err := common.NewURL("rest://" + address + "/com.ikurento.user.OpenAPIUserProvider?" +
+		"interface=com.ikurento.user.OpenAPIUserProvider&bean.name=OpenAPIUserProvider" +
+		"&application=user-center&rest.openapi.path=/openapi.json")
+	assert.NoError(t, err)
+	_, err = common.ServiceMap.Register(url.Service(), url.Protocol, "", "", &OpenAPIUserProvider{})
+	assert.NoError(t, err)
+	rest_config.SetRestProviderServiceConfigMap(map[string]*rest_config.RestServiceConfig{
+		"OpenAPIUserProvider": {
+			Server: "go-restful",
+			RestMethodConfigsMap: map[string]*rest_config.RestMethodConfig{
+				"GetUser": {
+					MethodName:     "GetUser",
+					Path:           "/users/{id:[0-9]+}",
+					MethodType:     "GET",
+					Produces:       "application/json",
+					Consumes:       "*/*",
+					PathParamsMap:  map[int]string{0: "id"},
+					QueryParamsMap: map[int]string{1: "name"},
+					Body:           -1,
+				},
+				"UpdateUser": {
+					MethodName: "UpdateUser",
+					Path:       "/users",
+					MethodType: "PUT",
+					Produces:   "application/json",
+					Consumes:   "application/json",
+					Body:       0,
+				},
+			},
+		},
+	})
+	proto := NewRestProtocol()
+	exporter := proto.Export(extension.GetProxyFactory("default").GetInvoker(url))
+	assert.NotNil(t, exporter)
+	defer proto.Destroy()
+
+	resp, err := http.Get("http://" + address + "/openapi.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	document := &openapi.Document{}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(document))
+
+	assert.Equal(t, openapi.Version, document.OpenAPI)
+	assert.Equal(t, "user-center", document.Info.Title)
+	// the paths and the verbs are the ones routing the requests
+	getUser := document.Paths["/users/{id}"]["get"]
+	if assert.NotNil(t, getUser) {
+		assert.Equal(t, "GetUser", getUser.OperationID)
+		assert.Equal(t, []*openapi.Parameter{
+			{Name: "id", In: "path", Required: true, Schema: &openapi.Schema{Type: "integer", Format: "int64"}},
+			{Name: "name", In: "query", Schema: &openapi.Schema{Type: "string"}},
+		}, getUser.Parameters)
+		assert.Equal(t, &openapi.Schema{Ref: "#/components/schemas/OpenAPIUser"},
+			getUser.Responses["200"].Content["application/json"].Schema)
+	}
+	updateUser := document.Paths["/users"]["put"]
+	if assert.NotNil(t, updateUser) && assert.NotNil(t, updateUser.RequestBody) {
+		assert.Equal(t, &openapi.Schema{Ref: "#/components/schemas/OpenAPIUser"},
+			updateUser.RequestBody.Content["application/json"].Schema)
+	}
+	assert.Equal(t, &openapi.Schema{Type: "object", Properties: map[string]*openapi.Schema{
+		"id":     {Type: "integer", Format: "int64"},
+		"name":   {Type: "string"},
+		"tags":   {Type: "array", Items: &openapi.Schema{Type: "string"}},
+		"Friend": {Ref: "#/components/schemas/OpenAPIUser"},
+	}}, document.Components.Schemas["OpenAPIUser"])
+}
+
+type OpenAPIOrderProvider struct{}
+
+func (p *OpenAPIOrderProvider) Reference() string {
+	return "com.ikurento.user.OpenAPIOrderProvider"
+}
+
+func (p *OpenAPIOrderProvider) GetOrder(_ context.Context, id int) (string, error) {
+	return "order", nil
+}
+
+// getOpenAPIDocument returns the status of the document served by @url and the document
+func getOpenAPIDocument(t *testing.T, url string) (int, *openapi.Document) {
+	resp, err := http.Get(url)
+	if !assert.NoError(t, err) {
+		return 0, nil
+	}
+	defer resp.Body.Close()
+	document := &openapi.Document{}
+	if resp.StatusCode == http.StatusOK {
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(document))
+	}
+	return resp.StatusCode, document
+}
+
+func TestRestProtocolOpenAPIPerPath(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	address := ln.Addr().String()
+	assert.NoError(t, ln.Close())
+
+	userURL, err := common.NewURL("rest://" + address + "/com.ikurento.user.OpenAPIUserProvider?" +
+		"interface=com.ikurento.user.OpenAPIUserProvider&bean.name=OpenAPIUserProvider&rest.openapi.path=/openapi.json")
+	assert.NoError(t, err)
+	orderURL, err := common.NewURL("rest://" + address + "/com.ikurento.user.OpenAPIOrderProvider?" +
+		"interface=com.ikurento.user.OpenAPIOrderProvider&bean.name=OpenAPIOrderProvider" +
+		"&rest.openapi.path=/order-openapi.json")
+	assert.NoError(t, err)
+	_, err = common.ServiceMap.Register(userURL.Service(), userURL.Protocol, "", "", &OpenAPIUserProvider{})
+	assert.NoError(t, err)
+	_, err = common.ServiceMap.Register(orderURL.Service(), orderURL.Protocol, "", "", &OpenAPIOrderProvider{})
+	assert.NoError(t, err)
+	rest_config.SetRestProviderServiceConfigMap(map[string]*rest_config.RestServiceConfig{
+		"OpenAPIUserProvider": {
+			Server: "go-restful",
+			RestMethodConfigsMap: map[string]*rest_config.RestMethodConfig{
+				"GetUser": {MethodName: "GetUser", Path: "/users/{id}", MethodType: "GET", Produces: "application/json",
+					Consumes: "*/*", PathParamsMap: map[int]string{0: "id"}, Body: -1},
+			},
+		},
+		"OpenAPIOrderProvider": {
+			Server: "go-restful",
+			RestMethodConfigsMap: map[string]*rest_config.RestMethodConfig{
+				"GetOrder": {MethodName: "GetOrder", Path: "/orders/{id}", MethodType: "GET", Produces: "application/json",
+					Consumes: "*/*", PathParamsMap: map[int]string{0: "id"}, Body: -1},
+			},
+		},
+	})
+	proto := NewRestProtocol()
+	defer proto.Destroy()
+	assert.NotNil(t, proto.Export(extension.GetProxyFactory("default").GetInvoker(userURL)))
+	orderExporter := proto.Export(extension.GetProxyFactory("default").GetInvoker(orderURL))
+	assert.NotNil(t, orderExporter)
+
+	// each of the paths serves the document of its own services
+	status, document := getOpenAPIDocument(t, "http://"+address+"/openapi.json")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, document.Paths, "/users/{id}")
+	assert.NotContains(t, document.Paths, "/orders/{id}")
+	status, document = getOpenAPIDocument(t, "http://"+address+"/order-openapi.json")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, document.Paths, "/orders/{id}")
+	assert.NotContains(t, document.Paths, "/users/{id}")
+
+	// the route of the document is removed with its last service
+	orderExporter.Unexport()
+	status, _ = getOpenAPIDocument(t, "http://"+address+"/order-openapi.json")
+	assert.Equal(t, http.StatusNotFound, status)
+	status, _ = getOpenAPIDocument(t, "http://"+address+"/openapi.json")
+	assert.Equal(t, http.StatusOK, status)
+}
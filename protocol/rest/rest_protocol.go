@@ -52,6 +52,9 @@ type RestProtocol struct {
 	serverMap  map[string]server.RestServer
 	clientLock sync.Mutex
 	clientMap  map[client.RestOptions]client.RestClient
+	// documentMap holds the OpenAPI documents of the servers by their locations and paths
+	documentLock sync.Mutex
+	documentMap  map[string]*openAPIDocument
 }
 
 // NewRestProtocol returns a RestProtocol
@@ -60,6 +63,7 @@ func NewRestProtocol() *RestProtocol {
 		BaseProtocol: protocol.NewBaseProtocol(),
 		serverMap:    make(map[string]server.RestServer, 8),
 		clientMap:    make(map[client.RestOptions]client.RestClient, 8),
+		documentMap:  make(map[string]*openAPIDocument, 8),
 	}
 }
 
@@ -79,6 +83,10 @@ func (rp *RestProtocol) Export(invoker protocol.Invoker) protocol.Exporter {
 	for _, methodConfig := range restServiceConfig.RestMethodConfigsMap {
 		restServer.Deploy(methodConfig, server.GetRouteFunc(invoker, methodConfig))
 	}
+	rp.addOpenAPIDocument(url, restServer, restServiceConfig)
+	exporter.unexported = func() {
+		rp.removeOpenAPIDocument(url)
+	}
 	return exporter
 }
 
@@ -157,6 +165,11 @@ func (rp *RestProtocol) Destroy() {
 	for key := range rp.clientMap {
 		delete(rp.clientMap, key)
 	}
+	rp.documentLock.Lock()
+	for key := range rp.documentMap {
+		delete(rp.documentMap, key)
+	}
+	rp.documentLock.Unlock()
 }
 
 // GetRestProtocol get a rest protocol
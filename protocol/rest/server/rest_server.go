@@ -20,6 +20,7 @@ package server
 import (
 	"context"
 	"errors"
+	"io/ioutil"
 	"net/http"
 	"reflect"
 	"strconv"
@@ -27,10 +28,14 @@ import (
 
 import (
 	perrors "github.com/pkg/errors"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 )
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/common/logger"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
@@ -90,6 +95,7 @@ func GetRouteFunc(invoker protocol.Invoker, methodConfig *rest_config.RestMethod
 			args []interface{}
 		)
 		svc := common.ServiceMap.GetServiceByServiceKey(invoker.GetURL().Protocol, invoker.GetURL().ServiceKey())
+		codec := invoker.GetURL().GetParam(constant.REST_CODEC_KEY, "")
 		// get method
 		method := svc.Method()[methodConfig.MethodName]
 		argsTypes := method.ArgsType()
@@ -101,7 +107,7 @@ func GetRouteFunc(invoker protocol.Invoker, methodConfig *rest_config.RestMethod
 			argsTypes[0].String() == "[]interface {}" {
 			args, err = getArgsInterfaceFromRequest(req, methodConfig)
 		} else {
-			args, err = getArgsFromRequest(req, argsTypes, methodConfig)
+			args, err = getArgsFromRequest(req, argsTypes, methodConfig, codec)
 		}
 		if err != nil {
 			logger.Errorf("[Go Restful] parsing http parameters error:%v", err)
@@ -118,13 +124,39 @@ func GetRouteFunc(invoker protocol.Invoker, methodConfig *rest_config.RestMethod
 			}
 			return
 		}
-		err = resp.WriteEntity(result.Result())
+		if msg, ok := result.Result().(proto.Message); ok && codec == constant.REST_CODEC_PB_JSON {
+			err = writeProtoJSONEntity(resp, msg)
+		} else {
+			err = resp.WriteEntity(result.Result())
+		}
 		if err != nil {
 			logger.Errorf("[Go Restful] WriteEntity error:%v", err)
 		}
 	}
 }
 
+// readProtoJSONEntity reads the body into @msg by the proto3 JSON mapping,
+// unknown fields are rejected as the spec requires
+func readProtoJSONEntity(req RestServerRequest, msg proto.Message) error {
+	body, err := ioutil.ReadAll(req.RawRequest().Body)
+	if err != nil {
+		return err
+	}
+	return protojson.Unmarshal(body, msg)
+}
+
+// writeProtoJSONEntity writes @msg on the response by the proto3 JSON mapping
+func writeProtoJSONEntity(resp RestServerResponse, msg proto.Message) error {
+	body, err := protojson.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+	_, err = resp.Write(body)
+	return err
+}
+
 // getArgsInterfaceFromRequest when service function like GetUser(req []interface{}, rsp *User) error
 // use this method to get arguments
 func getArgsInterfaceFromRequest(req RestServerRequest, methodConfig *rest_config.RestMethodConfig) ([]interface{}, error) {
@@ -174,7 +206,7 @@ func getArgsInterfaceFromRequest(req RestServerRequest, methodConfig *rest_confi
 }
 
 // getArgsFromRequest get arguments from server.RestServerRequest
-func getArgsFromRequest(req RestServerRequest, argsTypes []reflect.Type, methodConfig *rest_config.RestMethodConfig) ([]interface{}, error) {
+func getArgsFromRequest(req RestServerRequest, argsTypes []reflect.Type, methodConfig *rest_config.RestMethodConfig, codec string) ([]interface{}, error) {
 	argsLength := len(argsTypes)
 	args := make([]interface{}, argsLength)
 	for i, t := range argsTypes {
@@ -186,7 +218,7 @@ func getArgsFromRequest(req RestServerRequest, argsTypes []reflect.Type, methodC
 	if err := assembleArgsFromQueryParams(methodConfig, argsLength, argsTypes, req, args); err != nil {
 		return nil, err
 	}
-	if err := assembleArgsFromBody(methodConfig, argsTypes, req, args); err != nil {
+	if err := assembleArgsFromBody(methodConfig, argsTypes, req, args, codec); err != nil {
 		return nil, err
 	}
 	if err := assembleArgsFromHeaders(methodConfig, req, argsLength, argsTypes, args); err != nil {
@@ -216,7 +248,8 @@ func assembleArgsFromHeaders(methodConfig *rest_config.RestMethodConfig, req Res
 }
 
 // assembleArgsFromBody assemble arguments from body
-func assembleArgsFromBody(methodConfig *rest_config.RestMethodConfig, argsTypes []reflect.Type, req RestServerRequest, args []interface{}) error {
+// the body is decoded by protojson when the codec is pb-json and the argument is a proto message
+func assembleArgsFromBody(methodConfig *rest_config.RestMethodConfig, argsTypes []reflect.Type, req RestServerRequest, args []interface{}, codec string) error {
 	if methodConfig.Body >= 0 && methodConfig.Body < len(argsTypes) {
 		t := argsTypes[methodConfig.Body]
 		kind := t.Kind()
@@ -234,7 +267,11 @@ func assembleArgsFromBody(methodConfig *rest_config.RestMethodConfig, argsTypes
 				ni = n.Interface()
 			}
 		}
-		if err := req.ReadEntity(&ni); err != nil {
+		if msg, ok := ni.(proto.Message); ok && codec == constant.REST_CODEC_PB_JSON {
+			if err := readProtoJSONEntity(req, msg); err != nil {
+				return perrors.Errorf("[Go restful] Read proto-json body entity error, error is %v", perrors.WithStack(err))
+			}
+		} else if err := req.ReadEntity(&ni); err != nil {
 			return perrors.Errorf("[Go restful] Read body entity error, error is %v", perrors.WithStack(err))
 		}
 		args[methodConfig.Body] = ni
@@ -0,0 +1,83 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/filter/generic/generalizer"
+	rest_config "dubbo.apache.org/dubbo-go/v3/protocol/rest/config"
+)
+
+type mockRestServerRequest struct {
+	RestServerRequest
+	request *http.Request
+}
+
+func (m *mockRestServerRequest) RawRequest() *http.Request {
+	return m.request
+}
+
+func TestAssembleArgsFromProtoJSONBody(t *testing.T) {
+	methodConfig := &rest_config.RestMethodConfig{MethodName: "GetUser", Body: 0}
+	argsTypes := []reflect.Type{reflect.TypeOf(&generalizer.RequestType{})}
+
+	// proto3 JSON mapping encodes int64 as a string, which encoding/json can't decode
+	req := &mockRestServerRequest{request: httptest.NewRequest(http.MethodPost, "/user", strings.NewReader(`{"id":"1024"}`))}
+	args := make([]interface{}, 1)
+	err := assembleArgsFromBody(methodConfig, argsTypes, req, args, constant.REST_CODEC_PB_JSON)
+	assert.NoError(t, err)
+	assert.IsType(t, &generalizer.RequestType{}, args[0])
+	assert.Equal(t, int64(1024), args[0].(*generalizer.RequestType).GetId())
+
+	req = &mockRestServerRequest{request: httptest.NewRequest(http.MethodPost, "/user", strings.NewReader(`{"id":"1024","unknown":1}`))}
+	err = assembleArgsFromBody(methodConfig, argsTypes, req, args, constant.REST_CODEC_PB_JSON)
+	assert.Error(t, err)
+}
+
+func TestWriteProtoJSONEntity(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	resp := &mockRestServerResponse{ResponseWriter: recorder}
+	err := writeProtoJSONEntity(resp, &generalizer.ResponseType{Id: 1024, Name: "dubbo"})
+	assert.NoError(t, err)
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"id":"1024","name":"dubbo"}`, recorder.Body.String())
+}
+
+type mockRestServerResponse struct {
+	http.ResponseWriter
+}
+
+func (m *mockRestServerResponse) WriteError(int, error) error {
+	return nil
+}
+
+func (m *mockRestServerResponse) WriteEntity(interface{}) error {
+	return nil
+}
@@ -17,6 +17,10 @@
 
 package protocol
 
+import (
+	"errors"
+)
+
 // Result is a RPC result
 type Result interface {
 	// SetError sets error.
@@ -92,3 +96,57 @@ func (r *RPCResult) Attachment(key string, defaultValue interface{}) interface{}
 	}
 	return v
 }
+
+// BizError is the error of the business logic, e.g. the invalid arguments, which fails again on
+// the other providers, so the cluster returns it directly instead of failing over.
+type BizError struct {
+	err error
+}
+
+// NewBizError marks @err as the error of the business logic
+func NewBizError(err error) error {
+	return &BizError{err: err}
+}
+
+// Error returns the message of the wrapped error
+func (e *BizError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *BizError) Unwrap() error {
+	return e.err
+}
+
+// IsBizError checks whether @err is marked as the error of the business logic
+func IsBizError(err error) bool {
+	var bizErr *BizError
+	return errors.As(err, &bizErr)
+}
+
+// TransportError is the error of the transport before the request reaches the provider, e.g. the session
+// is being closed on shutdown, so the cluster retries it on the other providers.
+type TransportError struct {
+	err error
+}
+
+// NewTransportError marks @err as the error of the transport
+func NewTransportError(err error) error {
+	return &TransportError{err: err}
+}
+
+// Error returns the message of the wrapped error
+func (e *TransportError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *TransportError) Unwrap() error {
+	return e.err
+}
+
+// IsTransportError checks whether @err is marked as the error of the transport
+func IsTransportError(err error) bool {
+	var transportErr *TransportError
+	return errors.As(err, &transportErr)
+}
@@ -0,0 +1,31 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package protocol
+
+import (
+	"context"
+)
+
+// ResultInterceptor transforms the result of the provider, including its attachments, after the business
+// method returns and before the result is serialized, e.g. to stamp an attachment or wrap the value in an envelope.
+type ResultInterceptor interface {
+	// Intercept returns the result transformed from @result of @invocation to @invoker
+	Intercept(ctx context.Context, invoker Invoker, invocation Invocation, result Result) Result
+	// GetPriority returns the priority of the interceptor, the ones of the lower priorities are applied first
+	GetPriority() int
+}
@@ -35,6 +35,7 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
 )
 
 const (
@@ -100,6 +101,7 @@ type BaseRegistry struct {
 	done     chan struct{}
 	cltLock  sync.RWMutex           // ctl lock is a lock for services map
 	services map[string]*common.URL // service name + protocol -> service config, for store the service registered
+	health   *metrics.ConnectionHealth
 }
 
 // InitBaseRegistry for init some local variables and set BaseRegistry's subclass to it
@@ -109,9 +111,15 @@ func (r *BaseRegistry) InitBaseRegistry(url *common.URL, facadeRegistry FacadeBa
 	r.done = make(chan struct{})
 	r.services = make(map[string]*common.URL)
 	r.facadeBasedRegistry = facadeRegistry
+	r.health = metrics.NewConnectionHealth(metrics.RegistryComponent, url)
 	return r
 }
 
+// Health returns the health of the connection to the registry reported to the health metrics
+func (r *BaseRegistry) Health() *metrics.ConnectionHealth {
+	return r.health
+}
+
 // GetURL for get registry's url
 func (r *BaseRegistry) GetURL() *common.URL {
 	return r.URL
@@ -151,7 +159,7 @@ func (r *BaseRegistry) Register(conf *common.URL) error {
 	_, ok = r.services[conf.Key()]
 	r.cltLock.Unlock()
 	if ok {
-		return perrors.Errorf("Path{%s} has been registered", conf.Key())
+		return &AlreadyRegisteredError{Key: conf.Key()}
 	}
 
 	err = r.register(conf)
@@ -427,6 +435,7 @@ func (r *BaseRegistry) Subscribe(url *common.URL, notifyListener NotifyListener)
 			} else {
 				logger.Infof("update begin, service event: %v", serviceEvent.String())
 				notifyListener.Notify(serviceEvent)
+				r.health.Active()
 			}
 		}
 		sleepWait(n)
@@ -0,0 +1,77 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package registry
+
+import (
+	"net/url"
+	"strconv"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+type mockFacadeBasedRegistry struct {
+	FacadeBasedRegistry
+	nodes map[string]string
+}
+
+func (r *mockFacadeBasedRegistry) CreatePath(string) error {
+	return nil
+}
+
+func (r *mockFacadeBasedRegistry) DoRegister(root string, node string) error {
+	r.nodes[root+"/"+node] = node
+	return nil
+}
+
+func (r *mockFacadeBasedRegistry) DoUnregister(root string, node string) error {
+	delete(r.nodes, root+"/"+node)
+	return nil
+}
+
+func TestBaseRegistryRegisterDuplicated(t *testing.T) {
+	regURL, _ := common.NewURL("registry://127.0.0.1:2181")
+	facade := &mockFacadeBasedRegistry{nodes: make(map[string]string)}
+	reg := &BaseRegistry{}
+	reg.InitBaseRegistry(regURL, facade)
+
+	urlMap := url.Values{}
+	urlMap.Set(constant.ROLE_KEY, strconv.Itoa(common.PROVIDER))
+	urlMap.Set(constant.INTERFACE_KEY, "com.ikurento.user.UserProvider")
+	testURL, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider",
+		common.WithParams(urlMap), common.WithMethods([]string{"GetUser"}))
+
+	assert.NoError(t, reg.Register(testURL))
+	err := reg.Register(testURL)
+	assert.IsType(t, &AlreadyRegisteredError{}, err)
+	assert.Equal(t, testURL.Key(), err.(*AlreadyRegisteredError).Key)
+	assert.Len(t, facade.nodes, 1)
+
+	// re-registering after unregister is allowed
+	assert.NoError(t, reg.UnRegister(testURL))
+	assert.Len(t, facade.nodes, 0)
+	assert.NoError(t, reg.Register(testURL))
+	assert.Len(t, facade.nodes, 1)
+}
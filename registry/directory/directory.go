@@ -18,10 +18,13 @@
 package directory
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"os"
+	"strings"
 	"sync"
+	"time"
 )
 
 import (
@@ -29,6 +32,7 @@ import (
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/diagnostic"
 	"dubbo.apache.org/dubbo-go/v3/cluster/directory"
 	"dubbo.apache.org/dubbo-go/v3/cluster/directory/base"
 	"dubbo.apache.org/dubbo-go/v3/cluster/directory/static"
@@ -59,6 +63,7 @@ type RegistryDirectory struct {
 	serviceType                    string
 	registry                       registry.Registry
 	cacheInvokersMap               *sync.Map // use sync.map
+	cacheOriginUrlsMap             *sync.Map // the urls of the cached invokers before overridden by the configurators
 	consumerURL                    *common.URL
 	cacheOriginUrl                 *common.URL
 	configurators                  []config_center.Configurator
@@ -67,6 +72,21 @@ type RegistryDirectory struct {
 	// serviceKey                     string
 	// forbidden                      atomic.Bool
 	registerLock sync.Mutex // this lock if for register
+	// notified is closed once the invokers are set by the first notification
+	notified   chan struct{}
+	notifyOnce sync.Once
+	// invokersChanged is closed and replaced once the invokers are set
+	invokersChanged chan struct{}
+	// lastInvokers are the invokers served by the use-cache policy after the providers are gone,
+	// until lastInvokersExpiry
+	lastInvokers       []protocol.Invoker
+	lastInvokersExpiry time.Time
+	// fallbackInvokers are the invokers of the fallback protocol of the reference, which are only listed for the failover
+	fallbackInvokers []protocol.Invoker
+	// duplicateGroup deduplicates the invokers of the addresses reported by the other registries of the reference
+	duplicateGroup *duplicateGroup
+	// healthChecker probes the health check urls of the providers if the reference configures the interval
+	healthChecker *healthChecker
 }
 
 // NewRegistryDirectory will create a new RegistryDirectory
@@ -76,14 +96,21 @@ func NewRegistryDirectory(url *common.URL, registry registry.Registry) (director
 	}
 	logger.Debugf("new RegistryDirectory for service :%s.", url.Key())
 	dir := &RegistryDirectory{
-		Directory:        base.NewDirectory(url),
-		cacheInvokers:    []protocol.Invoker{},
-		cacheInvokersMap: &sync.Map{},
-		serviceType:      url.SubURL.Service(),
-		registry:         registry,
+		Directory:          base.NewDirectory(url),
+		cacheInvokers:      []protocol.Invoker{},
+		cacheInvokersMap:   &sync.Map{},
+		cacheOriginUrlsMap: &sync.Map{},
+		serviceType:        url.SubURL.Service(),
+		registry:           registry,
+		notified:           make(chan struct{}),
+		invokersChanged:    make(chan struct{}),
 	}
 
 	dir.consumerURL = dir.getConsumerUrl(url.SubURL)
+	if url.SubURL.GetParamBool(constant.REGISTRY_DEDUPLICATE_KEY, true) {
+		dir.duplicateGroup = joinDuplicateGroup(url.SubURL, dir)
+	}
+	dir.healthChecker = newHealthChecker(url.SubURL)
 
 	if routerChain, err := chain.NewRouterChain(); err == nil {
 		dir.Directory.SetRouterChain(routerChain)
@@ -92,6 +119,7 @@ func NewRegistryDirectory(url *common.URL, registry registry.Registry) (director
 	}
 
 	dir.consumerConfigurationListener = newConsumerConfigurationListener(dir)
+	diagnostic.Register(diagnostic.ServiceKey(url), dir)
 
 	go dir.subscribe(url.SubURL)
 	return dir, nil
@@ -135,7 +163,7 @@ func (dir *RegistryDirectory) refreshInvokers(event *registry.ServiceEvent) {
 	}
 	dir.setNewInvokers()
 	if oldInvoker != nil {
-		oldInvoker.Destroy()
+		dir.destroyInvokers([]protocol.Invoker{oldInvoker})
 	}
 }
 
@@ -159,8 +187,10 @@ func (dir *RegistryDirectory) refreshAllInvokers(events []*registry.ServiceEvent
 		// MergeURL is executed once and put the result into Event. After this, the key will get from Event.Key().
 		newUrl := dir.convertUrl(event)
 		newUrl = common.MergeURL(newUrl, referenceUrl)
+		originUrl := newUrl.Clone()
 		dir.overrideUrl(newUrl)
 		event.Update(newUrl)
+		dir.cacheOriginUrlsMap.Store(event.Key(), originUrl)
 	}
 	// After notify all addresses, do some callback.
 	defer callback()
@@ -201,9 +231,7 @@ func (dir *RegistryDirectory) refreshAllInvokers(events []*registry.ServiceEvent
 	}()
 	dir.setNewInvokers()
 	// destroy unused invokers
-	for _, invoker := range oldInvokers {
-		go invoker.Destroy()
-	}
+	dir.destroyInvokers(oldInvokers)
 }
 
 // eventMatched checks if a cached invoker appears in the incoming invoker list, if no, then it is safe to remove.
@@ -230,11 +258,49 @@ func (dir *RegistryDirectory) invokerCacheKey(event *registry.ServiceEvent) stri
 
 // setNewInvokers groups the invokers from the cache first, then set the result to both directory and router chain.
 func (dir *RegistryDirectory) setNewInvokers() {
-	newInvokers := dir.toGroupInvokers()
+	newInvokers, fallbackInvokers := dir.toGroupInvokers()
 	dir.invokersLock.Lock()
 	defer dir.invokersLock.Unlock()
+	if policy, timeout := dir.emptyProvidersPolicy(); len(newInvokers) > 0 {
+		dir.lastInvokers = nil
+	} else if policy == constant.EMPTY_PROVIDERS_POLICY_USE_CACHE && len(dir.cacheInvokers) > 0 {
+		dir.lastInvokers = dir.cacheInvokers
+		dir.lastInvokersExpiry = time.Now().Add(timeout)
+	}
 	dir.cacheInvokers = newInvokers
+	dir.fallbackInvokers = fallbackInvokers
 	dir.RouterChain().SetInvokers(newInvokers)
+	dir.healthChecker.update(newInvokers)
+	close(dir.invokersChanged)
+	dir.invokersChanged = make(chan struct{})
+	dir.notifyOnce.Do(func() {
+		close(dir.notified)
+	})
+}
+
+// destroyInvokers destroys the invokers removed from the directory, and they are destroyed once the last known
+// invokers expire if the use-cache policy serves them.
+func (dir *RegistryDirectory) destroyInvokers(invokers []protocol.Invoker) {
+	dir.invokersLock.RLock()
+	var delay time.Duration
+	if dir.lastInvokers != nil {
+		delay = time.Until(dir.lastInvokersExpiry)
+	}
+	dir.invokersLock.RUnlock()
+	for _, invoker := range invokers {
+		if delay > 0 {
+			time.AfterFunc(delay, invoker.Destroy)
+		} else {
+			go invoker.Destroy()
+		}
+	}
+}
+
+// emptyProvidersPolicy returns the empty providers policy of the reference and its timeout
+func (dir *RegistryDirectory) emptyProvidersPolicy() (string, time.Duration) {
+	url := dir.GetURL().SubURL
+	return url.GetParam(constant.EMPTY_PROVIDERS_POLICY_KEY, constant.EMPTY_PROVIDERS_POLICY_FAIL_FAST),
+		url.GetParamDuration(constant.EMPTY_PROVIDERS_TIMEOUT_KEY, constant.DEFAULT_EMPTY_PROVIDERS_TIMEOUT)
 }
 
 // cacheInvokerByEvent caches invokers from the service event
@@ -278,10 +344,12 @@ func (dir *RegistryDirectory) convertUrl(res *registry.ServiceEvent) *common.URL
 	return ret
 }
 
-func (dir *RegistryDirectory) toGroupInvokers() []protocol.Invoker {
+// toGroupInvokers groups the cached invokers, and returns the invokers of the fallback protocol apart
+func (dir *RegistryDirectory) toGroupInvokers() ([]protocol.Invoker, []protocol.Invoker) {
 	var (
-		err             error
-		newInvokersList []protocol.Invoker
+		err              error
+		newInvokersList  []protocol.Invoker
+		fallbackInvokers []protocol.Invoker
 	)
 	groupInvokersMap := make(map[string][]protocol.Invoker)
 
@@ -289,9 +357,32 @@ func (dir *RegistryDirectory) toGroupInvokers() []protocol.Invoker {
 		newInvokersList = append(newInvokersList, value.(protocol.Invoker))
 		return true
 	})
+	if dir.duplicateGroup != nil {
+		newInvokersList = dir.duplicateGroup.deduplicate(dir, newInvokersList)
+	}
 
+	// the reference with a group list only subscribes the groups in it
+	var (
+		groups   []string
+		fallback string
+	)
+	if subURL := dir.GetURL().SubURL; subURL != nil {
+		if group := subURL.GetParam(constant.GROUP_KEY, ""); strings.Contains(group, ",") {
+			groups = strings.Split(group, ",")
+		}
+		if fallback = subURL.GetParam(constant.FALLBACK_PROTOCOL_KEY, ""); fallback == subURL.Protocol {
+			fallback = ""
+		}
+	}
 	for _, invoker := range newInvokersList {
 		group := invoker.GetURL().GetParam(constant.GROUP_KEY, "")
+		if groups != nil && !containsGroup(groups, group) {
+			continue
+		}
+		if fallback != "" && invoker.GetURL().Protocol == fallback {
+			fallbackInvokers = append(fallbackInvokers, invoker)
+			continue
+		}
 
 		groupInvokersMap[group] = append(groupInvokersMap[group], invoker)
 	}
@@ -314,7 +405,16 @@ func (dir *RegistryDirectory) toGroupInvokers() []protocol.Invoker {
 		}
 	}
 
-	return groupInvokersList
+	return groupInvokersList, fallbackInvokers
+}
+
+func containsGroup(groups []string, group string) bool {
+	for _, g := range groups {
+		if strings.TrimSpace(g) == group {
+			return true
+		}
+	}
+	return false
 }
 
 // uncacheInvoker will return abandoned Invoker, if no Invoker to be abandoned, return nil
@@ -325,6 +425,7 @@ func (dir *RegistryDirectory) uncacheInvoker(event *registry.ServiceEvent) proto
 func (dir *RegistryDirectory) uncacheInvokerWithKey(key string) protocol.Invoker {
 	logger.Debugf("service will be deleted in cache invokers: invokers key is  %s!", key)
 	protocol.RemoveUrlKeyUnhealthyStatus(key)
+	dir.cacheOriginUrlsMap.Delete(key)
 	if cacheInvoker, ok := dir.cacheInvokersMap.Load(key); ok {
 		dir.cacheInvokersMap.Delete(key)
 		return cacheInvoker.(protocol.Invoker)
@@ -346,11 +447,15 @@ func (dir *RegistryDirectory) cacheInvoker(url *common.URL, event *registry.Serv
 		logger.Error("URL is nil ,pls check if service url is subscribe successfully!")
 		return nil
 	}
-	// check the url's protocol is equal to the protocol which is configured in reference config or referenceUrl is not care about protocol
-	if url.Protocol == referenceUrl.Protocol || referenceUrl.Protocol == "" {
+	// check the url's protocol is equal to the protocol which is configured in reference config or referenceUrl is not care about protocol,
+	// and the providers of the fallback protocol are cached as well, which are kept apart for the failover by toGroupInvokers
+	if url.Protocol == referenceUrl.Protocol || referenceUrl.Protocol == "" ||
+		url.Protocol == referenceUrl.GetParam(constant.FALLBACK_PROTOCOL_KEY, "") {
 		newUrl := common.MergeURL(url, referenceUrl)
+		originUrl := newUrl.Clone()
 		dir.overrideUrl(newUrl)
 		event.Update(newUrl)
+		dir.cacheOriginUrlsMap.Store(event.Key(), originUrl)
 		if v, ok := dir.doCacheInvoker(newUrl, event); ok {
 			return v
 		}
@@ -365,6 +470,7 @@ func (dir *RegistryDirectory) doCacheInvoker(newUrl *common.URL, event *registry
 		newInvoker := extension.GetProtocol(protocolwrapper.FILTER).Refer(newUrl)
 		if newInvoker != nil {
 			dir.cacheInvokersMap.Store(key, newInvoker)
+			warmUpInBackground(newInvoker)
 		} else {
 			logger.Warnf("service will be added in cache invokers fail, result is null, invokers url is %+v", newUrl.String())
 		}
@@ -379,6 +485,7 @@ func (dir *RegistryDirectory) doCacheInvoker(newUrl *common.URL, event *registry
 		newInvoker := extension.GetProtocol(protocolwrapper.FILTER).Refer(newUrl)
 		if newInvoker != nil {
 			dir.cacheInvokersMap.Store(key, newInvoker)
+			warmUpInBackground(newInvoker)
 			return cacheInvoker.(protocol.Invoker), true
 		} else {
 			logger.Warnf("service will be updated in cache invokers fail, result is null, invokers url is %+v", newUrl.String())
@@ -387,16 +494,129 @@ func (dir *RegistryDirectory) doCacheInvoker(newUrl *common.URL, event *registry
 	return nil, false
 }
 
-// List selected protocol invokers from the directory
+// warmUpInBackground connects to the provider of the invoker cached by a notification if the connection warm up
+// is enabled, regardless of the connection.warmup.top which only limits the warm up before the reference is used.
+func warmUpInBackground(invoker protocol.Invoker) {
+	url := invoker.GetURL()
+	if !url.GetParamBool(constant.CONNECTION_WARMUP_KEY, false) {
+		return
+	}
+	go func() {
+		timeout := url.GetParamDuration(constant.CONNECTION_WARMUP_TIMEOUT_KEY, constant.DEFAULT_CONNECTION_WARMUP_TIMEOUT)
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		if err := directory.WarmUpInvokers(ctx, []protocol.Invoker{invoker}, 0); err != nil {
+			logger.Warnf("Warm up the connection to the provider %s error: %v", url.Location, err)
+		}
+	}()
+}
+
+// List selected protocol invokers from the directory, and the empty providers policy of the reference applies
+// if there isn't any of them
 func (dir *RegistryDirectory) List(invocation protocol.Invocation) []protocol.Invoker {
+	invokers := dir.route(invocation)
+	if len(invokers) > 0 {
+		return invokers
+	}
+	switch policy, timeout := dir.emptyProvidersPolicy(); policy {
+	case constant.EMPTY_PROVIDERS_POLICY_WAIT:
+		return dir.waitInvokers(invocation, timeout)
+	case constant.EMPTY_PROVIDERS_POLICY_USE_CACHE:
+		dir.invokersLock.RLock()
+		defer dir.invokersLock.RUnlock()
+		if len(dir.cacheInvokers) == 0 && time.Now().Before(dir.lastInvokersExpiry) {
+			return dir.lastInvokers
+		}
+	}
+	return invokers
+}
+
+// waitInvokers waits until any invoker is selected or @timeout elapses, and it doesn't outlast the deadline
+// of the invocation
+func (dir *RegistryDirectory) waitInvokers(invocation protocol.Invocation, timeout time.Duration) []protocol.Invoker {
+	if deadline, ok := invocation.AttributeByKey(constant.CALL_DEADLINE_KEY, nil).(time.Time); ok {
+		if remaining := time.Until(deadline); remaining < timeout {
+			timeout = remaining
+		}
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		dir.invokersLock.RLock()
+		changed := dir.invokersChanged
+		dir.invokersLock.RUnlock()
+		invokers := dir.route(invocation)
+		if len(invokers) > 0 {
+			return invokers
+		}
+		select {
+		case <-changed:
+		case <-timer.C:
+			return invokers
+		}
+	}
+}
+
+// route routes the invocation by the router chain, and the providers failing the health check are skipped
+// unless all of the routed ones fail it
+func (dir *RegistryDirectory) route(invocation protocol.Invocation) []protocol.Invoker {
 	routerChain := dir.RouterChain()
 
 	if routerChain == nil {
 		dir.invokersLock.RLock()
 		defer dir.invokersLock.RUnlock()
-		return dir.cacheInvokers
+		return dir.healthChecker.filter(dir.cacheInvokers)
+	}
+	return dir.healthChecker.filter(routerChain.Route(dir.consumerURL, invocation))
+}
+
+// ListFallback lists the invokers of the fallback protocol of the reference notified by the registry
+func (dir *RegistryDirectory) ListFallback(invocation protocol.Invocation) []protocol.Invoker {
+	dir.invokersLock.RLock()
+	defer dir.invokersLock.RUnlock()
+	return dir.fallbackInvokers
+}
+
+// Addresses returns the addresses of the invokers notified by the registry, before they are routed
+func (dir *RegistryDirectory) Addresses() []directory.Address {
+	dir.invokersLock.RLock()
+	invokers := dir.cacheInvokers
+	dir.invokersLock.RUnlock()
+	return directory.ResolveAddresses(invokers)
+}
+
+// WarmUp waits for the first notification of the registry, then connects to the providers notified
+func (dir *RegistryDirectory) WarmUp(ctx context.Context, top int) error {
+	select {
+	case <-dir.notified:
+	case <-ctx.Done():
+		return perrors.Wrap(ctx.Err(), "the providers are not notified by the registry")
+	}
+	dir.invokersLock.RLock()
+	invokers := dir.cacheInvokers
+	dir.invokersLock.RUnlock()
+	return directory.WarmUpInvokers(ctx, invokers, top)
+}
+
+// WaitReady waits until at least @min invokers notified by the registry are available, and it's woken up
+// by the notifications only
+func (dir *RegistryDirectory) WaitReady(ctx context.Context, min int) error {
+	for {
+		dir.invokersLock.RLock()
+		changed := dir.invokersChanged
+		invokers := dir.cacheInvokers
+		dir.invokersLock.RUnlock()
+		available := directory.CountAvailable(directory.ResolveAddresses(invokers))
+		if available >= min {
+			return nil
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return perrors.Wrapf(ctx.Err(), "%d providers notified by the registry are available, fewer than %d",
+				available, min)
+		}
 	}
-	return routerChain.Route(dir.consumerURL, invocation)
 }
 
 // IsAvailable  whether the directory is available
@@ -418,14 +638,54 @@ func (dir *RegistryDirectory) IsAvailable() bool {
 func (dir *RegistryDirectory) Destroy() {
 	// TODO:unregister & unsubscribe
 	dir.Directory.Destroy(func() {
-		invokers := dir.cacheInvokers
+		diagnostic.Unregister(diagnostic.ServiceKey(dir.GetURL()), dir)
+		if dir.duplicateGroup != nil {
+			dir.duplicateGroup.leave(dir.GetURL().SubURL, dir)
+		}
+		dir.healthChecker.stop()
+		invokers, fallbackInvokers := dir.cacheInvokers, dir.fallbackInvokers
 		dir.cacheInvokers = []protocol.Invoker{}
+		dir.fallbackInvokers = nil
 		for _, ivk := range invokers {
 			ivk.Destroy()
 		}
+		for _, ivk := range fallbackInvokers {
+			ivk.Destroy()
+		}
 	})
 }
 
+// refreshOverriddenInvokers overrides the urls of the cached invokers again by the changed configurators,
+// and re-refers the invokers whose urls change, e.g. the timeout is overridden, without the registry notification.
+func (dir *RegistryDirectory) refreshOverriddenInvokers() {
+	var oldInvokers []protocol.Invoker
+	func() {
+		dir.registerLock.Lock()
+		defer dir.registerLock.Unlock()
+		dir.cacheOriginUrlsMap.Range(func(key, value interface{}) bool {
+			cacheInvoker, ok := dir.cacheInvokersMap.Load(key)
+			if !ok {
+				return true
+			}
+			newUrl := value.(*common.URL).Clone()
+			dir.overrideUrl(newUrl)
+			if common.GetCompareURLEqualFunc()(newUrl, cacheInvoker.(protocol.Invoker).GetURL()) {
+				return true
+			}
+			logger.Infof("service will be overridden in cache invokers: new invoker url is %s", newUrl)
+			if newInvoker := extension.GetProtocol(protocolwrapper.FILTER).Refer(newUrl); newInvoker != nil {
+				dir.cacheInvokersMap.Store(key, newInvoker)
+				oldInvokers = append(oldInvokers, cacheInvoker.(protocol.Invoker))
+			}
+			return true
+		})
+	}()
+	dir.setNewInvokers()
+	for _, invoker := range oldInvokers {
+		go invoker.Destroy()
+	}
+}
+
 func (dir *RegistryDirectory) overrideUrl(targetUrl *common.URL) {
 	doOverrideUrl(dir.configurators, targetUrl)
 	doOverrideUrl(dir.consumerConfigurationListener.Configurators(), targetUrl)
@@ -475,8 +735,7 @@ func newReferenceConfigurationListener(dir *RegistryDirectory, url *common.URL)
 // Process handle events and update Invokers
 func (l *referenceConfigurationListener) Process(event *config_center.ConfigChangeEvent) {
 	l.BaseConfigurationListener.Process(event)
-	// FIXME: this doesn't trigger dir.overrideUrl()
-	l.directory.refreshInvokers(nil)
+	l.directory.refreshOverriddenInvokers()
 }
 
 type consumerConfigurationListener struct {
@@ -503,6 +762,5 @@ func (l *consumerConfigurationListener) addNotifyListener(listener registry.Noti
 // Process handles events from Configuration Center and update Invokers
 func (l *consumerConfigurationListener) Process(event *config_center.ConfigChangeEvent) {
 	l.BaseConfigurationListener.Process(event)
-	// FIXME: this doesn't trigger dir.overrideUrl()
-	l.directory.refreshInvokers(nil)
+	l.directory.refreshOverriddenInvokers()
 }
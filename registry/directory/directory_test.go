@@ -18,21 +18,35 @@
 package directory
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sort"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 )
 
 import (
+	perrors "github.com/pkg/errors"
+
 	"github.com/stretchr/testify/assert"
+
+	"go.uber.org/atomic"
 )
 
 import (
+	_ "dubbo.apache.org/dubbo-go/v3/cluster/cluster/failover"
+	clusterdir "dubbo.apache.org/dubbo-go/v3/cluster/directory"
 	_ "dubbo.apache.org/dubbo-go/v3/cluster/router"
 	"dubbo.apache.org/dubbo-go/v3/common"
+	common_cfg "dubbo.apache.org/dubbo-go/v3/common/config"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
 	"dubbo.apache.org/dubbo-go/v3/config"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
 	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
 	"dubbo.apache.org/dubbo-go/v3/protocol/protocolwrapper"
 	"dubbo.apache.org/dubbo-go/v3/registry"
@@ -93,6 +107,64 @@ func Test_MergeProviderUrl(t *testing.T) {
 	}
 }
 
+func Test_Addresses(t *testing.T) {
+	registryDirectory, mockRegistry := normalRegistryDir(true)
+	providerUrl := func(location string, weight string) *common.URL {
+		url, _ := common.NewURL("dubbo://"+location+"/org.apache.dubbo-go.mockService",
+			common.WithParamsValue(constant.GROUP_KEY, "group"),
+			common.WithParamsValue(constant.VERSION_KEY, "1.0.0"),
+			common.WithParamsValue(constant.WEIGHT_KEY, weight))
+		return url
+	}
+	locations := func() map[string]int64 {
+		weights := make(map[string]int64)
+		for _, address := range registryDirectory.Addresses() {
+			assert.True(t, address.Available)
+			weights[address.Location] = address.Weight
+		}
+		return weights
+	}
+	mockRegistry.MockEvent(&registry.ServiceEvent{Action: remoting.EventTypeAdd, Service: providerUrl("0.0.0.1:20000", "200")})
+	mockRegistry.MockEvent(&registry.ServiceEvent{Action: remoting.EventTypeAdd, Service: providerUrl("0.0.0.2:20000", "100")})
+	time.Sleep(1e9)
+	assert.Equal(t, map[string]int64{"0.0.0.1:20000": 200, "0.0.0.2:20000": 100}, locations())
+
+	// the addresses follow the notifications of the registry
+	mockRegistry.MockEvent(&registry.ServiceEvent{Action: remoting.EventTypeDel, Service: providerUrl("0.0.0.1:20000", "200")})
+	mockRegistry.MockEvent(&registry.ServiceEvent{Action: remoting.EventTypeAdd, Service: providerUrl("0.0.0.3:20000", "50")})
+	time.Sleep(1e9)
+	assert.Equal(t, map[string]int64{"0.0.0.2:20000": 100, "0.0.0.3:20000": 50}, locations())
+
+	// and they are exposed by the cluster invoker of the reference
+	lister, ok := extension.GetCluster(constant.ClusterKeyFailover).Join(registryDirectory).(clusterdir.AddressLister)
+	assert.True(t, ok)
+	assert.Len(t, lister.Addresses(), 2)
+}
+
+func Test_FallbackProtocol(t *testing.T) {
+	extension.SetProtocol(protocolwrapper.FILTER, protocolwrapper.NewMockProtocolFilter)
+	url, _ := common.NewURL("mock://127.0.0.1:1111")
+	url.SubURL, _ = common.NewURL("tri://127.0.0.1:20000/org.apache.dubbo-go.mockService",
+		common.WithParamsValue(constant.FALLBACK_PROTOCOL_KEY, "dubbo"))
+	mockRegistry, _ := registry.NewMockRegistry(&common.URL{})
+	dir, _ := NewRegistryDirectory(url, mockRegistry)
+	registryDirectory := dir.(*RegistryDirectory)
+	for _, provider := range []string{"tri://0.0.0.1:20000", "dubbo://0.0.0.2:20000", "rest://0.0.0.3:20000"} {
+		providerUrl, _ := common.NewURL(provider + "/org.apache.dubbo-go.mockService")
+		mockRegistry.(*registry.MockRegistry).MockEvent(&registry.ServiceEvent{Action: remoting.EventTypeAdd, Service: providerUrl})
+	}
+	time.Sleep(1e9)
+
+	// the invokers of the fallback protocol are only listed for the failover
+	invokers := registryDirectory.List(&invocation.RPCInvocation{})
+	assert.Len(t, invokers, 1)
+	assert.Equal(t, "tri", invokers[0].GetURL().Protocol)
+	fallbackInvokers := registryDirectory.ListFallback(&invocation.RPCInvocation{})
+	assert.Len(t, fallbackInvokers, 1)
+	assert.Equal(t, "dubbo", fallbackInvokers[0].GetURL().Protocol)
+	assert.Len(t, registryDirectory.Addresses(), 1)
+}
+
 func Test_MergeOverrideUrl(t *testing.T) {
 	registryDirectory, mockRegistry := normalRegistryDir(true)
 	providerUrl, _ := common.NewURL("dubbo://0.0.0.0:20000/org.apache.dubbo-go.mockService",
@@ -155,6 +227,81 @@ func Test_RefreshUrl(t *testing.T) {
 	assert.Len(t, registryDirectory.cacheInvokers, 0)
 }
 
+func Test_RefreshOverriddenUrl(t *testing.T) {
+	ccUrl, _ := common.NewURL("mock://127.0.0.1:1111")
+	dc, _ := (&config_center.MockDynamicConfigurationFactory{}).GetDynamicConfiguration(ccUrl)
+	common_cfg.GetEnvInstance().SetDynamicConfiguration(dc)
+	defer common_cfg.GetEnvInstance().SetDynamicConfiguration(nil)
+	extension.SetProtocol(protocolwrapper.FILTER, protocolwrapper.NewMockProtocolFilter)
+
+	url, _ := common.NewURL("mock://127.0.0.1:1111")
+	url.SubURL, _ = common.NewURL("dubbo://127.0.0.1:20000/org.apache.dubbo-go.mockService",
+		common.WithParamsValue(constant.GROUP_KEY, "group"),
+		common.WithParamsValue(constant.VERSION_KEY, "1.0.0"),
+		common.WithParamsValue(constant.SIDE_KEY, "consumer"),
+		common.WithParamsValue(constant.APPLICATION_KEY, "test-application"),
+		common.WithParamsValue(constant.TIMEOUT_KEY, "1s"))
+	mockRegistry, _ := registry.NewMockRegistry(&common.URL{})
+	dir, _ := NewRegistryDirectory(url, mockRegistry)
+	registryDirectory := dir.(*RegistryDirectory)
+	providerUrl, _ := common.NewURL("dubbo://192.168.1.1:20000/org.apache.dubbo-go.mockService",
+		common.WithParamsValue(constant.GROUP_KEY, "group"),
+		common.WithParamsValue(constant.VERSION_KEY, "1.0.0"),
+		common.WithParamsValue(constant.SIDE_KEY, "provider"),
+		common.WithParamsValue(constant.APPLICATION_KEY, "provider-application"))
+	mockRegistry.(*registry.MockRegistry).MockEvent(&registry.ServiceEvent{Action: remoting.EventTypeAdd, Service: providerUrl})
+	timeout := func() time.Duration {
+		invokers := registryDirectory.List(&invocation.RPCInvocation{})
+		if len(invokers) != 1 {
+			return 0
+		}
+		return invokers[0].GetURL().GetParamDuration(constant.TIMEOUT_KEY, "")
+	}
+	assert.Eventually(t, func() bool { return timeout() == time.Second }, 3*time.Second, 10*time.Millisecond)
+
+	// the consumer side rule of the service overrides the timeout of the cached invoker
+	rule := `configVersion: v2.7
+scope: service
+key: group/org.apache.dubbo-go.mockService:1.0.0
+enabled: true
+configs:
+- addresses: [0.0.0.0]
+  side: consumer
+  applications: [test-application]
+  parameters:
+    timeout: 5s
+`
+	key := url.SubURL.EncodedServiceKey() + constant.ConfiguratorSuffix
+	registryDirectory.referenceConfigurationListener.Process(&config_center.ConfigChangeEvent{
+		Key: key, Value: rule, ConfigType: remoting.EventTypeUpdate})
+	assert.Equal(t, 5*time.Second, timeout())
+
+	// the rule of the other applications doesn't match
+	registryDirectory.referenceConfigurationListener.Process(&config_center.ConfigChangeEvent{
+		Key: key, Value: strings.Replace(rule, "test-application", "other-application", 1), ConfigType: remoting.EventTypeUpdate})
+	assert.Equal(t, time.Second, timeout())
+
+	// the application rule applies to the consumers of the application
+	registryDirectory.consumerConfigurationListener.Process(&config_center.ConfigChangeEvent{
+		Key: "test-application" + constant.ConfiguratorSuffix,
+		Value: `configVersion: v2.7
+scope: application
+key: test-application
+enabled: true
+configs:
+- side: consumer
+  parameters:
+    timeout: 3s
+`,
+		ConfigType: remoting.EventTypeUpdate})
+	assert.Equal(t, 3*time.Second, timeout())
+
+	// the timeout is restored once the rule is removed
+	registryDirectory.consumerConfigurationListener.Process(&config_center.ConfigChangeEvent{
+		Key: "test-application" + constant.ConfiguratorSuffix, ConfigType: remoting.EventTypeDel})
+	assert.Equal(t, time.Second, timeout())
+}
+
 func normalRegistryDir(noMockEvent ...bool) (*RegistryDirectory, *registry.MockRegistry) {
 	extension.SetProtocol(protocolwrapper.FILTER, protocolwrapper.NewMockProtocolFilter)
 
@@ -185,3 +332,220 @@ func normalRegistryDir(noMockEvent ...bool) (*RegistryDirectory, *registry.MockR
 	}
 	return dir.(*RegistryDirectory), mockRegistry.(*registry.MockRegistry)
 }
+
+func emptyProvidersRegistryDir(policy string) (*RegistryDirectory, *registry.MockRegistry, *common.URL) {
+	extension.SetProtocol(protocolwrapper.FILTER, protocolwrapper.NewMockProtocolFilter)
+
+	url, _ := common.NewURL("mock://127.0.0.1:1111")
+	url.SubURL, _ = common.NewURL("dubbo://127.0.0.1:20000/org.apache.dubbo-go.mockService",
+		common.WithParamsValue(constant.EMPTY_PROVIDERS_POLICY_KEY, policy),
+		common.WithParamsValue(constant.EMPTY_PROVIDERS_TIMEOUT_KEY, "2s"))
+	mockRegistry, _ := registry.NewMockRegistry(&common.URL{})
+	dir, _ := NewRegistryDirectory(url, mockRegistry)
+	providerUrl, _ := common.NewURL("dubbo://0.0.0.1:20000/org.apache.dubbo-go.mockService")
+	return dir.(*RegistryDirectory), mockRegistry.(*registry.MockRegistry), providerUrl
+}
+
+func Test_EmptyProvidersFailFast(t *testing.T) {
+	registryDirectory, _, _ := emptyProvidersRegistryDir(constant.EMPTY_PROVIDERS_POLICY_FAIL_FAST)
+	start := time.Now()
+	assert.Empty(t, registryDirectory.List(&invocation.RPCInvocation{}))
+	assert.Less(t, int64(time.Since(start)), int64(100*time.Millisecond))
+}
+
+func Test_EmptyProvidersWait(t *testing.T) {
+	registryDirectory, mockRegistry, providerUrl := emptyProvidersRegistryDir(constant.EMPTY_PROVIDERS_POLICY_WAIT)
+	time.AfterFunc(300*time.Millisecond, func() {
+		mockRegistry.MockEvent(&registry.ServiceEvent{Action: remoting.EventTypeAdd, Service: providerUrl})
+	})
+	start := time.Now()
+	invokers := registryDirectory.List(&invocation.RPCInvocation{})
+	// the provider appears within the timeout
+	assert.Len(t, invokers, 1)
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(300*time.Millisecond))
+	assert.Less(t, int64(time.Since(start)), int64(2*time.Second))
+}
+
+func Test_EmptyProvidersWaitDeadline(t *testing.T) {
+	registryDirectory, _, _ := emptyProvidersRegistryDir(constant.EMPTY_PROVIDERS_POLICY_WAIT)
+	inv := invocation.NewRPCInvocationWithOptions()
+	inv.SetAttribute(constant.CALL_DEADLINE_KEY, time.Now().Add(200*time.Millisecond))
+	start := time.Now()
+	// the waiting doesn't outlast the deadline of the invocation
+	assert.Empty(t, registryDirectory.List(inv))
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(200*time.Millisecond))
+	assert.Less(t, int64(time.Since(start)), int64(time.Second))
+}
+
+func Test_WaitReady(t *testing.T) {
+	registryDirectory, mockRegistry, providerUrl := emptyProvidersRegistryDir(constant.EMPTY_PROVIDERS_POLICY_FAIL_FAST)
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	assert.True(t, perrors.Is(registryDirectory.WaitReady(ctx, 1), context.DeadlineExceeded))
+
+	anotherUrl, _ := common.NewURL("dubbo://0.0.0.2:20000/org.apache.dubbo-go.mockService")
+	time.AfterFunc(300*time.Millisecond, func() {
+		mockRegistry.MockEvent(&registry.ServiceEvent{Action: remoting.EventTypeAdd, Service: providerUrl})
+	})
+	time.AfterFunc(600*time.Millisecond, func() {
+		mockRegistry.MockEvent(&registry.ServiceEvent{Action: remoting.EventTypeAdd, Service: anotherUrl})
+	})
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	start := time.Now()
+	// it blocks until both of the providers register
+	assert.NoError(t, registryDirectory.WaitReady(ctx, 2))
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(600*time.Millisecond))
+	assert.Less(t, int64(time.Since(start)), int64(time.Second))
+
+	// and returns promptly once they are available
+	start = time.Now()
+	assert.NoError(t, registryDirectory.WaitReady(ctx, 2))
+	assert.Less(t, int64(time.Since(start)), int64(50*time.Millisecond))
+}
+
+func Test_EmptyProvidersUseCache(t *testing.T) {
+	registryDirectory, mockRegistry, providerUrl := emptyProvidersRegistryDir(constant.EMPTY_PROVIDERS_POLICY_USE_CACHE)
+	mockRegistry.MockEvent(&registry.ServiceEvent{Action: remoting.EventTypeAdd, Service: providerUrl})
+	time.Sleep(500 * time.Millisecond)
+	invokers := registryDirectory.List(&invocation.RPCInvocation{})
+	assert.Len(t, invokers, 1)
+	provider := invokers[0]
+
+	// the last known provider is still served after it's gone
+	mockRegistry.MockEvent(&registry.ServiceEvent{Action: remoting.EventTypeDel, Service: providerUrl})
+	time.Sleep(500 * time.Millisecond)
+	assert.Equal(t, []protocol.Invoker{provider}, registryDirectory.List(&invocation.RPCInvocation{}))
+	assert.True(t, provider.IsAvailable())
+
+	// until the cache expires
+	time.Sleep(2 * time.Second)
+	assert.Empty(t, registryDirectory.List(&invocation.RPCInvocation{}))
+	assert.False(t, provider.IsAvailable())
+}
+
+// duplicateRegistryDirs returns the directories of a reference subscribing two registries
+func duplicateRegistryDirs(deduplicate bool) ([]*RegistryDirectory, []*registry.MockRegistry) {
+	extension.SetProtocol(protocolwrapper.FILTER, protocolwrapper.NewMockProtocolFilter)
+
+	subURL, _ := common.NewURL("dubbo://127.0.0.1:20000/org.apache.dubbo-go.mockService",
+		common.WithParamsValue(constant.REGISTRY_DEDUPLICATE_KEY, strconv.FormatBool(deduplicate)))
+	dirs := make([]*RegistryDirectory, 0, 2)
+	registries := make([]*registry.MockRegistry, 0, 2)
+	for _, address := range []string{"mock://127.0.0.1:1111", "mock://127.0.0.1:2222"} {
+		url, _ := common.NewURL(address)
+		url.SubURL = subURL
+		mockRegistry, _ := registry.NewMockRegistry(&common.URL{})
+		dir, _ := NewRegistryDirectory(url, mockRegistry)
+		dirs = append(dirs, dir.(*RegistryDirectory))
+		registries = append(registries, mockRegistry.(*registry.MockRegistry))
+	}
+	return dirs, registries
+}
+
+func Test_DeduplicateProviders(t *testing.T) {
+	dirs, registries := duplicateRegistryDirs(true)
+	providerUrls := make([]*common.URL, 2)
+	providerUrls[0], _ = common.NewURL("dubbo://0.0.0.1:20000/org.apache.dubbo-go.mockService?zone=hangzhou")
+	providerUrls[1], _ = common.NewURL("dubbo://0.0.0.1:20000/org.apache.dubbo-go.mockService?region=east")
+	for i, mockRegistry := range registries {
+		mockRegistry.MockEvent(&registry.ServiceEvent{Action: remoting.EventTypeAdd, Service: providerUrls[i]})
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	list := func() ([]protocol.Invoker, int) {
+		var invokers []protocol.Invoker
+		owner := -1
+		for i, dir := range dirs {
+			if listed := dir.List(&invocation.RPCInvocation{}); len(listed) > 0 {
+				invokers = append(invokers, listed...)
+				owner = i
+			}
+		}
+		return invokers, owner
+	}
+	invokers, owner := list()
+	assert.Len(t, invokers, 1)
+	// the metadata reported by both of the registries is merged
+	assert.Equal(t, "hangzhou", invokers[0].GetURL().GetParam("zone", ""))
+	assert.Equal(t, "east", invokers[0].GetURL().GetParam("region", ""))
+
+	// the other registry takes over the provider once it's gone from the owner
+	registries[owner].MockEvent(&registry.ServiceEvent{Action: remoting.EventTypeDel, Service: providerUrls[owner]})
+	time.Sleep(500 * time.Millisecond)
+	invokers, takeover := list()
+	assert.Len(t, invokers, 1)
+	assert.Equal(t, 1-owner, takeover)
+}
+
+func Test_DeduplicateProvidersDisabled(t *testing.T) {
+	dirs, registries := duplicateRegistryDirs(false)
+	providerUrl, _ := common.NewURL("dubbo://0.0.0.1:20000/org.apache.dubbo-go.mockService")
+	for _, mockRegistry := range registries {
+		mockRegistry.MockEvent(&registry.ServiceEvent{Action: remoting.EventTypeAdd, Service: providerUrl})
+	}
+	time.Sleep(500 * time.Millisecond)
+	for _, dir := range dirs {
+		assert.Len(t, dir.List(&invocation.RPCInvocation{}), 1)
+	}
+}
+
+func Test_HealthCheck(t *testing.T) {
+	var failing atomic.Bool
+	failing.Store(true)
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	defer healthy.Close()
+	flaky := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer flaky.Close()
+
+	extension.SetProtocol(protocolwrapper.FILTER, protocolwrapper.NewMockProtocolFilter)
+	url, _ := common.NewURL("mock://127.0.0.1:1111")
+	url.SubURL, _ = common.NewURL("dubbo://127.0.0.1:20000/org.apache.dubbo-go.mockService",
+		common.WithParamsValue(constant.HEALTH_CHECK_INTERVAL_KEY, "100ms"))
+	mockRegistry, _ := registry.NewMockRegistry(&common.URL{})
+	dir, _ := NewRegistryDirectory(url, mockRegistry)
+	registryDirectory := dir.(*RegistryDirectory)
+	defer registryDirectory.Destroy()
+
+	healthyUrl, _ := common.NewURL("dubbo://0.0.0.1:20000/org.apache.dubbo-go.mockService",
+		common.WithParamsValue(constant.HEALTH_CHECK_URL_KEY, healthy.URL))
+	flakyUrl, _ := common.NewURL("dubbo://0.0.0.2:20000/org.apache.dubbo-go.mockService",
+		common.WithParamsValue(constant.HEALTH_CHECK_URL_KEY, flaky.URL))
+	mockRegistry.(*registry.MockRegistry).MockEvent(&registry.ServiceEvent{Action: remoting.EventTypeAdd, Service: healthyUrl})
+	mockRegistry.(*registry.MockRegistry).MockEvent(&registry.ServiceEvent{Action: remoting.EventTypeAdd, Service: flakyUrl})
+
+	locations := func() []string {
+		var locations []string
+		for _, invoker := range registryDirectory.List(&invocation.RPCInvocation{}) {
+			locations = append(locations, invoker.GetURL().Location)
+		}
+		sort.Strings(locations)
+		return locations
+	}
+	// the provider failing the health check is skipped
+	assert.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual([]string{"0.0.0.1:20000"}, locations())
+	}, 2*time.Second, 20*time.Millisecond)
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal(t, []string{"0.0.0.1:20000"}, locations())
+
+	// and it's used again once it recovers
+	failing.Store(false)
+	assert.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual([]string{"0.0.0.1:20000", "0.0.0.2:20000"}, locations())
+	}, 2*time.Second, 20*time.Millisecond)
+}
+
+func Test_HealthCheckURL(t *testing.T) {
+	url, _ := common.NewURL("dubbo://10.0.0.1:20000/org.apache.dubbo-go.mockService",
+		common.WithParamsValue(constant.HEALTH_CHECK_URL_KEY, ":8080/health"))
+	assert.Equal(t, "http://10.0.0.1:8080/health", healthCheckURL(url))
+	url.SetParam(constant.HEALTH_CHECK_URL_KEY, "https://10.0.0.2/health")
+	assert.Equal(t, "https://10.0.0.2/health", healthCheckURL(url))
+	// the consumer doesn't probe the providers without the interval
+	assert.Nil(t, newHealthChecker(url))
+}
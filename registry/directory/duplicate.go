@@ -0,0 +1,143 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package directory
+
+import (
+	"sync"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+// duplicateGroups holds the *duplicateGroup by the url of the reference, which is shared by the registry
+// directories of the reference subscribing multiple registries
+var duplicateGroups sync.Map
+
+// addressOwner is the directory listing the invoker of a provider address
+type addressOwner struct {
+	dir     *RegistryDirectory
+	invoker protocol.Invoker
+}
+
+// duplicateGroup deduplicates the invokers of the same provider address reported by the multiple registries
+// of a reference. The first directory listing an address owns it, and the others skip their invokers of it
+// after merging their metadata missing in the url of the owner.
+type duplicateGroup struct {
+	lock    sync.Mutex
+	owners  map[string]*addressOwner
+	members map[*RegistryDirectory]struct{}
+}
+
+// joinDuplicateGroup adds @dir to the group of the reference @url
+func joinDuplicateGroup(url *common.URL, dir *RegistryDirectory) *duplicateGroup {
+	value, _ := duplicateGroups.LoadOrStore(url, &duplicateGroup{
+		owners:  make(map[string]*addressOwner),
+		members: make(map[*RegistryDirectory]struct{}),
+	})
+	group := value.(*duplicateGroup)
+	group.lock.Lock()
+	group.members[dir] = struct{}{}
+	group.lock.Unlock()
+	return group
+}
+
+// leave removes @dir from the group of the reference @url, and the other directories take over its addresses
+func (g *duplicateGroup) leave(url *common.URL, dir *RegistryDirectory) {
+	g.lock.Lock()
+	delete(g.members, dir)
+	if len(g.members) == 0 {
+		duplicateGroups.Delete(url)
+	}
+	released := g.release(dir, nil)
+	g.lock.Unlock()
+	if released {
+		g.refreshOthers(dir)
+	}
+}
+
+// deduplicate returns the invokers of @dir whose addresses are owned by it
+func (g *duplicateGroup) deduplicate(dir *RegistryDirectory, invokers []protocol.Invoker) []protocol.Invoker {
+	addresses := make(map[string]struct{}, len(invokers))
+	for _, invoker := range invokers {
+		addresses[invoker.GetURL().Location] = struct{}{}
+	}
+
+	g.lock.Lock()
+	released := g.release(dir, addresses)
+	result := make([]protocol.Invoker, 0, len(invokers))
+	for _, invoker := range invokers {
+		address := invoker.GetURL().Location
+		owner, ok := g.owners[address]
+		if !ok || owner.dir == dir {
+			g.owners[address] = &addressOwner{dir: dir, invoker: invoker}
+			result = append(result, invoker)
+			continue
+		}
+		mergeMetadata(owner.invoker.GetURL(), invoker.GetURL())
+	}
+	g.lock.Unlock()
+
+	if released {
+		g.refreshOthers(dir)
+	}
+	return result
+}
+
+// release gives up the addresses owned by @dir but not in @addresses, and returns whether any is released
+func (g *duplicateGroup) release(dir *RegistryDirectory, addresses map[string]struct{}) bool {
+	released := false
+	for address, owner := range g.owners {
+		if _, ok := addresses[address]; owner.dir == dir && !ok {
+			delete(g.owners, address)
+			released = true
+		}
+	}
+	return released
+}
+
+// refreshOthers lets the directories other than @dir list the released addresses they report as well
+func (g *duplicateGroup) refreshOthers(dir *RegistryDirectory) {
+	g.lock.Lock()
+	others := make([]*RegistryDirectory, 0, len(g.members))
+	for member := range g.members {
+		if member != dir {
+			others = append(others, member)
+		}
+	}
+	g.lock.Unlock()
+	for _, other := range others {
+		// the directory not notified yet lists the addresses on its own notification
+		select {
+		case <-other.notified:
+			go other.setNewInvokers()
+		default:
+		}
+	}
+}
+
+// mergeMetadata sets the params of @duplicate missing in @target
+func mergeMetadata(target, duplicate *common.URL) {
+	duplicate.RangeParams(func(key, value string) bool {
+		if target.GetParam(key, "") == "" {
+			target.SetParam(key, value)
+		}
+		return true
+	})
+}
@@ -0,0 +1,235 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package directory
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+// healthChecker probes the health check urls advertised by the providers of a reference periodically,
+// regardless of the health reported by the registry. The providers failing the latest probe are skipped
+// by the routing until they pass one, and the ones just notified are skipped until their first probe passes.
+// The providers without the health check url are never skipped.
+type healthChecker struct {
+	interval time.Duration
+	client   *http.Client
+
+	lock sync.RWMutex
+	// targets are the health check urls of the probed invokers
+	targets map[protocol.Invoker]string
+	// unhealthy are the probed invokers which haven't passed the latest probe
+	unhealthy map[protocol.Invoker]struct{}
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// newHealthChecker starts the health checker by the interval of the reference @url, or returns nil if the
+// interval isn't configured
+func newHealthChecker(url *common.URL) *healthChecker {
+	if url.GetParam(constant.HEALTH_CHECK_INTERVAL_KEY, "") == "" {
+		return nil
+	}
+	interval := url.GetParamDuration(constant.HEALTH_CHECK_INTERVAL_KEY, "")
+	if interval <= 0 {
+		return nil
+	}
+	c := &healthChecker{
+		interval:  interval,
+		client:    &http.Client{Timeout: url.GetParamDuration(constant.HEALTH_CHECK_TIMEOUT_KEY, constant.DEFAULT_HEALTH_CHECK_TIMEOUT)},
+		targets:   make(map[protocol.Invoker]string),
+		unhealthy: make(map[protocol.Invoker]struct{}),
+		done:      make(chan struct{}),
+	}
+	go c.run()
+	return c
+}
+
+// update replaces the probed invokers by @invokers, and the new ones are probed at once
+func (c *healthChecker) update(invokers []protocol.Invoker) {
+	if c == nil {
+		return
+	}
+	targets := make(map[protocol.Invoker]string, len(invokers))
+	added := make(map[protocol.Invoker]string)
+	c.lock.Lock()
+	for _, invoker := range invokers {
+		target := healthCheckURL(invoker.GetURL())
+		if target == "" {
+			continue
+		}
+		targets[invoker] = target
+		if c.targets[invoker] != target {
+			c.unhealthy[invoker] = struct{}{}
+			added[invoker] = target
+		}
+	}
+	for invoker := range c.unhealthy {
+		if _, ok := targets[invoker]; !ok {
+			delete(c.unhealthy, invoker)
+		}
+	}
+	c.targets = targets
+	c.lock.Unlock()
+	if len(added) > 0 {
+		go c.probe(added)
+	}
+}
+
+// filter removes the unhealthy invokers from @invokers, while all of them are kept if none of them is healthy
+func (c *healthChecker) filter(invokers []protocol.Invoker) []protocol.Invoker {
+	if c == nil {
+		return invokers
+	}
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if len(c.unhealthy) == 0 {
+		return invokers
+	}
+	healthy := make([]protocol.Invoker, 0, len(invokers))
+	for _, invoker := range invokers {
+		if _, ok := c.unhealthy[invoker]; !ok {
+			healthy = append(healthy, invoker)
+		}
+	}
+	if len(healthy) == 0 {
+		return invokers
+	}
+	return healthy
+}
+
+func (c *healthChecker) stop() {
+	if c == nil {
+		return
+	}
+	c.closeOnce.Do(func() {
+		close(c.done)
+	})
+}
+
+func (c *healthChecker) run() {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.lock.RLock()
+			targets := make(map[protocol.Invoker]string, len(c.targets))
+			for invoker, target := range c.targets {
+				targets[invoker] = target
+			}
+			c.lock.RUnlock()
+			c.probe(targets)
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// probe checks @targets concurrently, then records the results of the invokers which are still probed
+func (c *healthChecker) probe(targets map[protocol.Invoker]string) {
+	var (
+		wg      sync.WaitGroup
+		resLock sync.Mutex
+		results = make(map[protocol.Invoker]error, len(targets))
+	)
+	for invoker, target := range targets {
+		wg.Add(1)
+		go func(invoker protocol.Invoker, target string) {
+			defer wg.Done()
+			err := c.check(target)
+			resLock.Lock()
+			results[invoker] = err
+			resLock.Unlock()
+		}(invoker, target)
+	}
+	wg.Wait()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for invoker, err := range results {
+		if c.targets[invoker] != targets[invoker] {
+			continue
+		}
+		_, unhealthy := c.unhealthy[invoker]
+		switch {
+		case err != nil && !unhealthy:
+			c.unhealthy[invoker] = struct{}{}
+			logger.Warnf("The provider %s fails the health check, error: %v", invoker.GetURL().Location, err)
+		case err == nil && unhealthy:
+			delete(c.unhealthy, invoker)
+			logger.Infof("The provider %s passes the health check", invoker.GetURL().Location)
+		}
+	}
+}
+
+// check requests @target, which fails unless it responds with a 2xx status
+func (c *healthChecker) check(target string) error {
+	select {
+	case <-c.done:
+		return perrors.New("the health checker is stopped")
+	default:
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-c.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return perrors.WithStack(err)
+	}
+	rsp, err := c.client.Do(req)
+	if err != nil {
+		return perrors.WithStack(err)
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode < http.StatusOK || rsp.StatusCode >= http.StatusMultipleChoices {
+		return perrors.Errorf("the health check %s responds with the status %s", target, rsp.Status)
+	}
+	return nil
+}
+
+// healthCheckURL returns the health check url advertised by the provider @url, and the one starting with
+// the port is resolved against the host of the provider
+func healthCheckURL(url *common.URL) string {
+	target := url.GetParam(constant.HEALTH_CHECK_URL_KEY, "")
+	if strings.HasPrefix(target, ":") {
+		return "http://" + url.Ip + target
+	}
+	return target
+}
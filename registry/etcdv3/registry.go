@@ -90,6 +90,7 @@ func newETCDV3Registry(url *common.URL) (registry.Registry, error) {
 	); err != nil {
 		return nil, err
 	}
+	r.Health().SetConnected(true)
 
 	r.handleClientRestart()
 	r.InitListeners()
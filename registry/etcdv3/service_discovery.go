@@ -229,6 +229,19 @@ func (e *etcdV3ServiceDiscovery) AddListener(listener registry.ServiceInstancesC
 	return nil
 }
 
+// RemoveListener removes the listener from the services it listens to
+func (e *etcdV3ServiceDiscovery) RemoveListener(listener registry.ServiceInstancesChangedListener) error {
+	initLock.Lock()
+	defer initLock.Unlock()
+
+	for _, t := range listener.GetServiceNames().Values() {
+		if listenerSet, found := e.instanceListenerMap[t.(string)]; found {
+			listenerSet.Remove(listener)
+		}
+	}
+	return nil
+}
+
 // Convert instance to dubbo path
 func toPath(instance registry.ServiceInstance) string {
 	if instance == nil {
@@ -66,7 +66,8 @@ func (p *ProtocolPortsMetadataCustomizer) Customize(instance registry.ServiceIns
 			continue
 		}
 
-		port, err := strconv.Atoi(u.Port)
+		// the consumers connect to the advertised port instead of the bound one if it's configured
+		port, err := strconv.Atoi(u.GetParam(constant.ADVERTISED_PORT_KEY, u.Port))
 		if err != nil {
 			logger.Errorf("Could not customize the metadata of port. ", err)
 		}
@@ -0,0 +1,121 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package event
+
+import (
+	"testing"
+)
+
+import (
+	gxset "github.com/dubbogo/gost/container/set"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/metadata/service/local"
+	"dubbo.apache.org/dubbo-go/v3/registry"
+)
+
+type mockNotifyListener struct {
+	urls []*common.URL
+}
+
+func (m *mockNotifyListener) Notify(event *registry.ServiceEvent) {
+	m.urls = append(m.urls, event.Service)
+}
+
+func (m *mockNotifyListener) NotifyAll([]*registry.ServiceEvent, func()) {
+}
+
+func TestMultipleProtocolsShareOneInstance(t *testing.T) {
+	metadataService, err := local.GetLocalMetadataService()
+	assert.NoError(t, err)
+	dubboURL, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?interface=com.ikurento.user.UserProvider&side=provider&methods=GetUser")
+	triURL, _ := common.NewURL("tri://127.0.0.1:20001/com.ikurento.user.UserProvider?interface=com.ikurento.user.UserProvider&side=provider&methods=GetUser")
+	_, err = metadataService.ExportURL(dubboURL)
+	assert.NoError(t, err)
+	_, err = metadataService.ExportURL(triURL)
+	assert.NoError(t, err)
+	defer func() {
+		_ = metadataService.UnexportURL(dubboURL)
+		_ = metadataService.UnexportURL(triURL)
+	}()
+
+	// the service exported on two protocols is published by one instance listing both endpoints
+	instance := &registry.DefaultServiceInstance{
+		ID:          "127.0.0.1:20005",
+		ServiceName: "user-app",
+		Host:        "127.0.0.1",
+		Port:        20005,
+		Enable:      true,
+		Healthy:     true,
+	}
+	(&ProtocolPortsMetadataCustomizer{}).Customize(instance)
+	endpoints := instance.GetEndPoints()
+	assert.Len(t, endpoints, 2)
+	assert.ElementsMatch(t, []*registry.Endpoint{{Port: 20000, Protocol: "dubbo"}, {Port: 20001, Protocol: "tri"}}, endpoints)
+
+	metadataInfo, err := metadataService.GetMetadataInfo("")
+	assert.NoError(t, err)
+	revision := metadataInfo.CalAndGetRevision()
+	instance.GetMetadata()[constant.EXPORTED_SERVICES_REVISION_PROPERTY_NAME] = revision
+
+	// the consumers get the endpoint of the protocol they are configured with
+	listener := NewServiceInstancesChangedListener(gxset.NewSet("user-app")).(*ServiceInstancesChangedListenerImpl)
+	listener.revisionToMetadata[revision] = metadataInfo
+	err = listener.OnEvent(&registry.ServiceInstancesChangedEvent{
+		ServiceName: "user-app",
+		Instances:   []registry.ServiceInstance{instance},
+	})
+	assert.NoError(t, err)
+
+	dubboListener := &mockNotifyListener{}
+	listener.AddListenerAndNotify(common.MatchKey(dubboURL.ServiceKey(), "dubbo"), dubboListener)
+	triListener := &mockNotifyListener{}
+	listener.AddListenerAndNotify(common.MatchKey(triURL.ServiceKey(), "tri"), triListener)
+	assert.True(t, containsEndpoint(dubboListener.urls, "dubbo", "20000"))
+	assert.True(t, containsEndpoint(triListener.urls, "tri", "20001"))
+}
+
+func containsEndpoint(urls []*common.URL, protocol string, port string) bool {
+	for _, url := range urls {
+		if url.Protocol == protocol && url.Port == port {
+			return true
+		}
+	}
+	return false
+}
+
+func TestProtocolPortsWithAdvertisedPort(t *testing.T) {
+	metadataService, err := local.GetLocalMetadataService()
+	assert.NoError(t, err)
+	dubboURL, _ := common.NewURL("dubbo://0.0.0.0:20000/com.ikurento.user.OrderProvider?interface=com.ikurento.user.OrderProvider&side=provider&methods=GetOrder",
+		common.WithParamsValue(constant.ADVERTISED_PORT_KEY, "30000"))
+	_, err = metadataService.ExportURL(dubboURL)
+	assert.NoError(t, err)
+	defer func() {
+		_ = metadataService.UnexportURL(dubboURL)
+	}()
+
+	instance := &registry.DefaultServiceInstance{ID: "203.0.113.10:30000", Host: "203.0.113.10", Port: 30000}
+	(&ProtocolPortsMetadataCustomizer{}).Customize(instance)
+	assert.Equal(t, []*registry.Endpoint{{Port: 30000, Protocol: "dubbo"}}, instance.GetEndPoints())
+}
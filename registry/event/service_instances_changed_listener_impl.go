@@ -143,6 +143,12 @@ func (lstn *ServiceInstancesChangedListenerImpl) OnEvent(e observer.Event) error
 
 // getMetadataInfo get metadata info when METADATA_STORAGE_TYPE_PROPERTY_NAME is null
 func (lstn *ServiceInstancesChangedListenerImpl) getMetadataInfo(instance registry.ServiceInstance, revision string) (*common.MetadataInfo, error) {
+	return GetMetadataInfo(instance, revision)
+}
+
+// GetMetadataInfo gets the metadata info of the @revision from the metadata report or the metadata service of
+// the @instance, which depends on the METADATA_STORAGE_TYPE_PROPERTY_NAME of the instance
+func GetMetadataInfo(instance registry.ServiceInstance, revision string) (*common.MetadataInfo, error) {
 	var metadataStorageType string
 	var metadataInfo *common.MetadataInfo
 	if instance.GetMetadata() == nil {
@@ -24,12 +24,15 @@ import (
 	"os"
 	"path"
 	"strconv"
+	"sync"
 )
 
 import (
 	gxset "github.com/dubbogo/gost/container/set"
 	gxpage "github.com/dubbogo/gost/hash/page"
 
+	"github.com/fsnotify/fsnotify"
+
 	perrors "github.com/pkg/errors"
 )
 
@@ -54,6 +57,11 @@ type fileSystemServiceDiscovery struct {
 	dynamicConfiguration file.FileSystemDynamicConfiguration
 	rootPath             string
 	fileMap              map[string]string
+
+	listenLock          sync.Mutex
+	instanceListenerMap map[string]*gxset.HashSet
+	// watcher is started by the first listener added
+	watcher *fsnotify.Watcher
 }
 
 func newFileSystemServiceDiscovery() (registry.ServiceDiscovery, error) {
@@ -79,6 +87,7 @@ func newFileSystemServiceDiscovery() (registry.ServiceDiscovery, error) {
 		dynamicConfiguration: *c.(*file.FileSystemDynamicConfiguration),
 		rootPath:             p,
 		fileMap:              make(map[string]string),
+		instanceListenerMap:  make(map[string]*gxset.HashSet),
 	}
 
 	extension.AddCustomShutdownCallback(func() {
@@ -106,6 +115,13 @@ func (fssd *fileSystemServiceDiscovery) String() string {
 // Destroy will destroy the service discovery.
 // If the discovery cannot be destroy, it will return an error.
 func (fssd *fileSystemServiceDiscovery) Destroy() error {
+	fssd.listenLock.Lock()
+	if fssd.watcher != nil {
+		fssd.watcher.Close()
+		fssd.watcher = nil
+	}
+	fssd.listenLock.Unlock()
+
 	fssd.dynamicConfiguration.Close()
 
 	for _, f := range fssd.fileMap {
@@ -262,9 +278,43 @@ func (fssd *fileSystemServiceDiscovery) GetRequestInstances(serviceNames []strin
 }
 
 // ----------------- event ----------------------
-// AddListener adds a new ServiceInstancesChangedListenerImpl
-// client
+// AddListener adds a new ServiceInstancesChangedListenerImpl, which is notified with
+// the ServiceInstancesChangedEvent once the instance files of its services are created, modified or removed
 func (fssd *fileSystemServiceDiscovery) AddListener(listener registry.ServiceInstancesChangedListener) error {
-	// fssd.dynamicConfiguration.AddListener(listener.ServiceName)
+	fssd.listenLock.Lock()
+	defer fssd.listenLock.Unlock()
+
+	if fssd.watcher == nil {
+		if err := fssd.startWatch(); err != nil {
+			return perrors.WithStack(err)
+		}
+	}
+
+	for _, t := range listener.GetServiceNames().Values() {
+		serviceName, ok := t.(string)
+		if !ok {
+			logger.Errorf("service name error %s", t)
+			continue
+		}
+		listenerSet, found := fssd.instanceListenerMap[serviceName]
+		if !found {
+			listenerSet = gxset.NewSet()
+			fssd.instanceListenerMap[serviceName] = listenerSet
+		}
+		listenerSet.Add(listener)
+	}
+	return nil
+}
+
+// RemoveListener removes the listener from the services it listens to
+func (fssd *fileSystemServiceDiscovery) RemoveListener(listener registry.ServiceInstancesChangedListener) error {
+	fssd.listenLock.Lock()
+	defer fssd.listenLock.Unlock()
+
+	for _, t := range listener.GetServiceNames().Values() {
+		if listenerSet, found := fssd.instanceListenerMap[t.(string)]; found {
+			listenerSet.Remove(listener)
+		}
+	}
 	return nil
 }
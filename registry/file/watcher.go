@@ -0,0 +1,139 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+import (
+	"github.com/fsnotify/fsnotify"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/registry"
+)
+
+// dispatchDelay coalesces the events of an instance file written in several steps, e.g. the create and write ones,
+// so that the listeners don't see the instances parsed from a file written partly
+const dispatchDelay = 100 * time.Millisecond
+
+// startWatch watches the root directory for the services added or removed, and the directories of the services
+// for the instance files created, modified or removed
+func (fssd *fileSystemServiceDiscovery) startWatch() error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	rootPath := fssd.dynamicConfiguration.RootPath()
+	if err = watcher.Add(rootPath); err != nil {
+		watcher.Close()
+		return err
+	}
+	for _, v := range fssd.GetServices().Values() {
+		p := filepath.Join(rootPath, v.(string))
+		if err = watcher.Add(p); err != nil {
+			logger.Warnf("[FileServiceDiscovery] watch the directory %s error = err{%v}", p, err)
+		}
+	}
+	fssd.watcher = watcher
+	go fssd.watch(watcher, rootPath)
+	return nil
+}
+
+// watch collects the names of the services changed, and dispatches their instances once the events settle
+func (fssd *fileSystemServiceDiscovery) watch(watcher *fsnotify.Watcher, rootPath string) {
+	changed := make(map[string]struct{})
+	var timer <-chan time.Time
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			logger.Debugf("[FileServiceDiscovery] watcher %s, event %v", rootPath, event)
+			serviceName, isDir := serviceOf(rootPath, event.Name)
+			if serviceName == "" {
+				continue
+			}
+			if isDir && event.Op&fsnotify.Create == fsnotify.Create {
+				// the instances registered before the watch is added are found by the dispatch later
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if err = watcher.Add(event.Name); err != nil {
+						logger.Warnf("[FileServiceDiscovery] watch the directory %s error = err{%v}", event.Name, err)
+					}
+				}
+			}
+			changed[serviceName] = struct{}{}
+			if timer == nil {
+				timer = time.After(dispatchDelay)
+			}
+		case <-timer:
+			for serviceName := range changed {
+				fssd.dispatchEvent(serviceName)
+				delete(changed, serviceName)
+			}
+			timer = nil
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			// err may be nil, ignore
+			if err != nil {
+				logger.Warnf("[FileServiceDiscovery] watch fail:%+v", err)
+			}
+		}
+	}
+}
+
+// serviceOf returns the name of the service the file @name belongs to, and whether the file is the directory
+// of the service itself
+func serviceOf(rootPath, name string) (string, bool) {
+	rel, err := filepath.Rel(rootPath, name)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	parts := strings.Split(rel, string(filepath.Separator))
+	return parts[0], len(parts) == 1
+}
+
+// dispatchEvent notifies the listeners of the service @serviceName with its current instances
+func (fssd *fileSystemServiceDiscovery) dispatchEvent(serviceName string) {
+	fssd.listenLock.Lock()
+	listenerSet, found := fssd.instanceListenerMap[serviceName]
+	var listeners []interface{}
+	if found {
+		listeners = listenerSet.Values()
+	}
+	fssd.listenLock.Unlock()
+	if len(listeners) == 0 {
+		return
+	}
+
+	instances := fssd.GetInstances(serviceName)
+	for _, l := range listeners {
+		err := l.(registry.ServiceInstancesChangedListener).OnEvent(registry.NewServiceInstancesChangedEvent(serviceName, instances))
+		if err != nil {
+			logger.Errorf("[FileServiceDiscovery] DispatchEventByServiceName{%s} error = err{%v}", serviceName, err)
+		}
+	}
+}
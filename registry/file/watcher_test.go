@@ -0,0 +1,149 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package file
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+import (
+	gxset "github.com/dubbogo/gost/container/set"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/observer"
+	"dubbo.apache.org/dubbo-go/v3/config_center/file"
+	"dubbo.apache.org/dubbo-go/v3/registry"
+)
+
+type mockInstancesChangedListener struct {
+	serviceNames *gxset.HashSet
+	events       chan *registry.ServiceInstancesChangedEvent
+}
+
+func (l *mockInstancesChangedListener) OnEvent(e observer.Event) error {
+	l.events <- e.(*registry.ServiceInstancesChangedEvent)
+	return nil
+}
+
+func (l *mockInstancesChangedListener) AddListenerAndNotify(string, registry.NotifyListener) {}
+
+func (l *mockInstancesChangedListener) RemoveListener(string) {}
+
+func (l *mockInstancesChangedListener) GetServiceNames() *gxset.HashSet {
+	return l.serviceNames
+}
+
+func (l *mockInstancesChangedListener) Accept(observer.Event) bool {
+	return true
+}
+
+func (l *mockInstancesChangedListener) GetEventType() reflect.Type {
+	return reflect.TypeOf(&registry.ServiceInstancesChangedEvent{})
+}
+
+func (l *mockInstancesChangedListener) GetPriority() int {
+	return -1
+}
+
+func newTestServiceDiscovery(t *testing.T) *fileSystemServiceDiscovery {
+	url, _ := common.NewURL("")
+	url.AddParamAvoidNil(file.ConfigCenterDirParamName, t.TempDir())
+	c, err := extension.GetConfigCenterFactory(constant.FILE_KEY).GetDynamicConfiguration(url)
+	assert.NoError(t, err)
+	return &fileSystemServiceDiscovery{
+		dynamicConfiguration: *c.(*file.FileSystemDynamicConfiguration),
+		fileMap:              make(map[string]string),
+		instanceListenerMap:  make(map[string]*gxset.HashSet),
+	}
+}
+
+func TestAddListener(t *testing.T) {
+	sd := newTestServiceDiscovery(t)
+	defer func() {
+		assert.NoError(t, sd.Destroy())
+	}()
+
+	existing := &registry.DefaultServiceInstance{ID: "1", ServiceName: "existing", Host: "127.0.0.1", Port: 20000}
+	assert.NoError(t, sd.Register(existing))
+	listener := &mockInstancesChangedListener{
+		serviceNames: gxset.NewSet("existing", "added"),
+		events:       make(chan *registry.ServiceInstancesChangedEvent, 8),
+	}
+	assert.NoError(t, sd.AddListener(listener))
+
+	nextEvent := func() *registry.ServiceInstancesChangedEvent {
+		select {
+		case e := <-listener.events:
+			return e
+		case <-time.After(3 * time.Second):
+			assert.FailNow(t, "no event dispatched")
+			return nil
+		}
+	}
+
+	// the service directory created after the listener is watched as well
+	added := &registry.DefaultServiceInstance{ID: "2", ServiceName: "added", Host: "127.0.0.1", Port: 20001}
+	assert.NoError(t, sd.Register(added))
+	e := nextEvent()
+	assert.Equal(t, "added", e.ServiceName)
+	assert.Len(t, e.Instances, 1)
+	assert.Equal(t, 20001, e.Instances[0].GetPort())
+
+	existing.Port = 20002
+	assert.NoError(t, sd.Update(existing))
+	e = nextEvent()
+	assert.Equal(t, "existing", e.ServiceName)
+	assert.Len(t, e.Instances, 1)
+	assert.Equal(t, 20002, e.Instances[0].GetPort())
+
+	assert.NoError(t, sd.Unregister(existing))
+	e = nextEvent()
+	assert.Equal(t, "existing", e.ServiceName)
+	assert.Empty(t, e.Instances)
+
+	// the services not listened to are ignored
+	assert.NoError(t, sd.Register(&registry.DefaultServiceInstance{ID: "3", ServiceName: "other", Host: "127.0.0.1", Port: 20003}))
+	select {
+	case e = <-listener.events:
+		assert.Failf(t, "unexpected event", "%v", e)
+	case <-time.After(3 * dispatchDelay):
+	}
+}
+
+func TestServiceOf(t *testing.T) {
+	root := filepath.Join("dubbo", "registry")
+	serviceName, isDir := serviceOf(root, filepath.Join(root, "app"))
+	assert.Equal(t, "app", serviceName)
+	assert.True(t, isDir)
+	serviceName, isDir = serviceOf(root, filepath.Join(root, "app", "instance"))
+	assert.Equal(t, "app", serviceName)
+	assert.False(t, isDir)
+	serviceName, _ = serviceOf(root, root)
+	assert.Empty(t, serviceName)
+	serviceName, _ = serviceOf(root, filepath.Join("dubbo", "other"))
+	assert.Empty(t, serviceName)
+}
@@ -104,6 +104,7 @@ func generateUrl(instance model.Instance) *common.URL {
 	for k, v := range instance.Metadata {
 		urlMap.Set(k, v)
 	}
+	registry.ScaleWeights(urlMap, instance.Weight)
 	return common.NewURLWithOptions(
 		common.WithIp(instance.Ip),
 		common.WithPort(strconv.Itoa(int(instance.Port))),
@@ -21,6 +21,7 @@ import (
 	"bytes"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -37,6 +38,7 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
 	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
 	"dubbo.apache.org/dubbo-go/v3/registry"
 	"dubbo.apache.org/dubbo-go/v3/remoting/nacos"
 )
@@ -56,7 +58,10 @@ func init() {
 type nacosRegistry struct {
 	*common.URL
 	namingClient *nacosClient.NacosNamingClient
+	registryLock sync.Mutex
 	registryUrls []*common.URL
+	// health is reported by the results of the registrations and the subscriptions, and the notifications
+	health *metrics.ConnectionHealth
 }
 
 func getCategory(url *common.URL) string {
@@ -118,10 +123,19 @@ func createRegisterParam(url *common.URL, serviceName string, groupName string)
 
 // Register will register the service @url to its nacos registry center
 func (nr *nacosRegistry) Register(url *common.URL) error {
+	nr.registryLock.Lock()
+	defer nr.registryLock.Unlock()
+	for _, registered := range nr.registryUrls {
+		if registered.Key() == url.Key() {
+			return &registry.AlreadyRegisteredError{Key: url.Key()}
+		}
+	}
+
 	serviceName := getServiceName(url)
 	groupName := nr.URL.GetParam(constant.GROUP_KEY, defaultGroup)
 	param := createRegisterParam(url, serviceName, groupName)
 	isRegistry, err := nr.namingClient.Client().RegisterInstance(param)
+	nr.health.SetConnected(err == nil)
 	if err != nil {
 		return err
 	}
@@ -154,6 +168,7 @@ func (nr *nacosRegistry) DeRegister(url *common.URL) error {
 	groupName := nr.URL.GetParam(constant.GROUP_KEY, defaultGroup)
 	param := createDeregisterParam(url, serviceName, groupName)
 	isDeRegistry, err := nr.namingClient.Client().DeregisterInstance(param)
+	nr.health.SetConnected(err == nil)
 	if err != nil {
 		return err
 	}
@@ -163,9 +178,21 @@ func (nr *nacosRegistry) DeRegister(url *common.URL) error {
 	return nil
 }
 
-// UnRegister
+// UnRegister deregisters the service @conf registered before, so that it can be registered again
 func (nr *nacosRegistry) UnRegister(conf *common.URL) error {
-	return perrors.New("UnRegister is not support in nacosRegistry")
+	nr.registryLock.Lock()
+	defer nr.registryLock.Unlock()
+	for i, registered := range nr.registryUrls {
+		if registered.Key() != conf.Key() {
+			continue
+		}
+		if err := nr.DeRegister(registered); err != nil {
+			return err
+		}
+		nr.registryUrls = append(nr.registryUrls[:i], nr.registryUrls[i+1:]...)
+		return nil
+	}
+	return perrors.Errorf("Path{%s} has not registered", conf.Key())
 }
 
 func (nr *nacosRegistry) subscribe(conf *common.URL) (registry.Listener, error) {
@@ -191,6 +218,7 @@ func (nr *nacosRegistry) Subscribe(url *common.URL, notifyListener registry.Noti
 		url.SetParam(constant.REGISTRY_GROUP_KEY, groupName) // update to registry.group
 
 		listener, err := nr.subscribe(url)
+		nr.health.SetConnected(err == nil)
 		if err != nil {
 			if !nr.IsAvailable() {
 				logger.Warnf("event listener game over.")
@@ -209,6 +237,7 @@ func (nr *nacosRegistry) Subscribe(url *common.URL, notifyListener registry.Noti
 				return err
 			}
 			logger.Infof("update begin, service event: %v", serviceEvent.String())
+			nr.health.Active()
 			notifyListener.Notify(serviceEvent)
 		}
 	}
@@ -232,6 +261,8 @@ func (nr *nacosRegistry) IsAvailable() bool {
 
 // nolint
 func (nr *nacosRegistry) Destroy() {
+	nr.registryLock.Lock()
+	defer nr.registryLock.Unlock()
 	for _, url := range nr.registryUrls {
 		err := nr.DeRegister(url)
 		logger.Infof("DeRegister Nacos URL:%+v", url)
@@ -252,6 +283,7 @@ func newNacosRegistry(url *common.URL) (registry.Registry, error) {
 		URL:          url,
 		namingClient: namingClient,
 		registryUrls: []*common.URL{},
+		health:       metrics.NewConnectionHealth(metrics.RegistryComponent, url),
 	}
 	return tmpRegistry, nil
 }
@@ -27,14 +27,24 @@ import (
 )
 
 import (
+	gxset "github.com/dubbogo/gost/container/set"
+	nacosClient "github.com/dubbogo/gost/database/kv/nacos"
+
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/model"
 	"github.com/nacos-group/nacos-sdk-go/vo"
 
 	"github.com/stretchr/testify/assert"
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/loadbalance/roundrobin"
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/observer"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/registry"
 )
 
 func TestNacosRegistry_Register(t *testing.T) {
@@ -229,3 +239,154 @@ func checkNacosServerAlive() bool {
 	}
 	return true
 }
+
+type mockNamingClient struct {
+	naming_client.INamingClient
+	instances  map[string]vo.RegisterInstanceParam
+	subscribed chan *vo.SubscribeParam
+	all        []model.Instance
+}
+
+func (c *mockNamingClient) RegisterInstance(param vo.RegisterInstanceParam) (bool, error) {
+	c.instances[param.ServiceName] = param
+	return true, nil
+}
+
+func (c *mockNamingClient) DeregisterInstance(param vo.DeregisterInstanceParam) (bool, error) {
+	delete(c.instances, param.ServiceName)
+	return true, nil
+}
+
+func (c *mockNamingClient) Subscribe(param *vo.SubscribeParam) error {
+	c.subscribed <- param
+	return nil
+}
+
+func (c *mockNamingClient) Unsubscribe(*vo.SubscribeParam) error {
+	return nil
+}
+
+func (c *mockNamingClient) SelectAllInstances(vo.SelectAllInstancesParam) ([]model.Instance, error) {
+	return c.all, nil
+}
+
+// mockInstancesListener records the instances notified by the service discovery
+type mockInstancesListener struct {
+	registry.ServiceInstancesChangedListener
+	instances chan []registry.ServiceInstance
+}
+
+func (l *mockInstancesListener) OnEvent(e observer.Event) error {
+	l.instances <- e.(*registry.ServiceInstancesChangedEvent).Instances
+	return nil
+}
+
+func (l *mockInstancesListener) GetServiceNames() *gxset.HashSet {
+	return gxset.NewSet("app")
+}
+
+func TestNacosListenerWeight(t *testing.T) {
+	client := &mockNamingClient{subscribed: make(chan *vo.SubscribeParam, 1)}
+	namingClient := &nacosClient.NacosNamingClient{}
+	namingClient.SetClient(client)
+	subscribeUrl, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?interface=com.ikurento.user.UserProvider")
+	listener, err := NewNacosListener(subscribeUrl, namingClient)
+	assert.NoError(t, err)
+	param := <-client.subscribed
+
+	instance := func(ip string, weight float64) model.SubscribeService {
+		return model.SubscribeService{Ip: ip, Port: 20000, Enable: true, Valid: true, Weight: weight,
+			Metadata: map[string]string{"interface": "com.ikurento.user.UserProvider", "protocol": "dubbo"}}
+	}
+	invokers := make(map[string]protocol.Invoker)
+	// push notifies the instances from nacos which are @changed, and returns the distribution of 400 selections
+	push := func(changed int, services ...model.SubscribeService) map[string]int {
+		param.SubscribeCallback(services, nil)
+		for i := 0; i < changed; i++ {
+			event, err := listener.Next()
+			assert.NoError(t, err)
+			invokers[event.Service.Location] = protocol.NewBaseInvoker(event.Service)
+		}
+		list := make([]protocol.Invoker, 0, len(invokers))
+		for _, invoker := range invokers {
+			list = append(list, invoker)
+		}
+		distribution := make(map[string]int)
+		balancer := roundrobin.NewLoadBalance()
+		inv := invocation.NewRPCInvocation("GetUser", nil, nil)
+		for i := 0; i < 400; i++ {
+			distribution[balancer.Select(list, inv).GetURL().Ip]++
+		}
+		return distribution
+	}
+
+	assert.Equal(t, map[string]int{"10.0.0.1": 200, "10.0.0.2": 200}, push(2, instance("10.0.0.1", 1), instance("10.0.0.2", 1)))
+	// the weight adjusted in the console is pushed live
+	assert.Equal(t, map[string]int{"10.0.0.1": 100, "10.0.0.2": 300}, push(1, instance("10.0.0.2", 3), instance("10.0.0.1", 1)))
+	assert.Equal(t, "300", invokers["10.0.0.2:20000"].GetURL().GetParam(constant.WEIGHT_KEY, ""))
+	listener.Close()
+}
+
+func TestNacosServiceDiscoveryWeight(t *testing.T) {
+	client := &mockNamingClient{subscribed: make(chan *vo.SubscribeParam, 1), all: []model.Instance{
+		{Ip: "10.0.0.1", Port: 20000, Weight: 1, Metadata: map[string]string{idKey: "1"}},
+		{Ip: "10.0.0.2", Port: 20000, Weight: 0.5, Metadata: map[string]string{idKey: "2"}},
+	}}
+	namingClient := &nacosClient.NacosNamingClient{}
+	namingClient.SetClient(client)
+	sd := &nacosServiceDiscovery{group: constant.SERVICE_DISCOVERY_DEFAULT_GROUP, namingClient: namingClient,
+		instanceListenerMap: make(map[string]*gxset.HashSet)}
+	info := common.NewMetadataInfWithApp("app")
+	info.Services["com.ikurento.user.UserProvider:dubbo"] = common.NewServiceInfo("com.ikurento.user.UserProvider",
+		"", "", "dubbo", "com.ikurento.user.UserProvider", map[string]string{constant.WEIGHT_KEY: "200"})
+	// weights returns the weights of the providers of @instances by their hosts
+	weights := func(instances []registry.ServiceInstance) map[string]string {
+		result := make(map[string]string)
+		for _, instance := range instances {
+			instance.(*registry.DefaultServiceInstance).SetServiceMetadata(info)
+			for _, u := range instance.ToURLs() {
+				result[u.Ip] = u.GetParam(constant.WEIGHT_KEY, "")
+			}
+		}
+		return result
+	}
+
+	// the weight adjusted in the nacos console scales the weights of the providers of the instance
+	assert.Equal(t, map[string]string{"10.0.0.1": "200", "10.0.0.2": "100"}, weights(sd.GetInstances("app")))
+
+	// and the changes pushed by nacos as well
+	listener := &mockInstancesListener{instances: make(chan []registry.ServiceInstance, 1)}
+	assert.NoError(t, sd.AddListener(listener))
+	param := <-client.subscribed
+	param.SubscribeCallback([]model.SubscribeService{
+		{Ip: "10.0.0.1", Port: 20000, Weight: 2, Metadata: map[string]string{idKey: "1"}},
+		{Ip: "10.0.0.2", Port: 20000, Weight: 1, Metadata: map[string]string{idKey: "2"}},
+	}, nil)
+	assert.Equal(t, map[string]string{"10.0.0.1": "400", "10.0.0.2": "200"}, weights(<-listener.instances))
+}
+
+func TestNacosRegistry_RegisterDuplicated(t *testing.T) {
+	regurl, _ := common.NewURL("registry://127.0.0.1:8848")
+	urlMap := url.Values{}
+	urlMap.Set(constant.ROLE_KEY, strconv.Itoa(common.PROVIDER))
+	urlMap.Set(constant.INTERFACE_KEY, "com.ikurento.user.UserProvider")
+	testUrl, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider", common.WithParams(urlMap))
+
+	client := &mockNamingClient{instances: make(map[string]vo.RegisterInstanceParam)}
+	namingClient := &nacosClient.NacosNamingClient{}
+	namingClient.SetClient(client)
+	reg := &nacosRegistry{URL: regurl, namingClient: namingClient}
+
+	assert.NoError(t, reg.Register(testUrl))
+	err := reg.Register(testUrl)
+	assert.IsType(t, &registry.AlreadyRegisteredError{}, err)
+	assert.Equal(t, testUrl.Key(), err.(*registry.AlreadyRegisteredError).Key)
+	assert.Len(t, client.instances, 1)
+
+	// re-registering after unregister is allowed
+	assert.NoError(t, reg.UnRegister(testUrl))
+	assert.Len(t, client.instances, 0)
+	assert.Error(t, reg.UnRegister(testUrl))
+	assert.NoError(t, reg.Register(testUrl))
+	assert.Len(t, client.instances, 1)
+}
@@ -20,6 +20,9 @@ package nacos
 import (
 	"fmt"
 	"net/url"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 )
 
@@ -40,6 +43,7 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
 	"dubbo.apache.org/dubbo-go/v3/common/logger"
 	"dubbo.apache.org/dubbo-go/v3/config"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
 	"dubbo.apache.org/dubbo-go/v3/registry"
 	"dubbo.apache.org/dubbo-go/v3/remoting/nacos"
 )
@@ -47,8 +51,13 @@ import (
 const (
 	defaultGroup = constant.SERVICE_DISCOVERY_DEFAULT_GROUP
 	idKey        = "id"
+
+	largestMetadataKeysNum = 3
 )
 
+// fallbackMetadataKeys are kept in the instance registered with its metadata in the metadata report
+var fallbackMetadataKeys = []string{idKey, constant.EXPORTED_SERVICES_REVISION_PROPERTY_NAME, constant.SERVICE_INSTANCE_ENDPOINTS}
+
 // init will put the service discovery into extension
 func init() {
 	extension.SetServiceDiscovery(constant.NACOS_KEY, newNacosServiceDiscovery)
@@ -69,6 +78,12 @@ type nacosServiceDiscovery struct {
 
 	instanceListenerMap map[string]*gxset.HashSet
 	listenerLock        sync.Mutex
+
+	// metadataFallback registers the instance with its metadata in the metadata report
+	// once the metadata is rejected by nacos for its size
+	metadataFallback bool
+	// health is reported by the results of the requests to nacos and the notifications
+	health *metrics.ConnectionHealth
 }
 
 // Destroy will close the service discovery.
@@ -89,6 +104,14 @@ func (n *nacosServiceDiscovery) Destroy() error {
 func (n *nacosServiceDiscovery) Register(instance registry.ServiceInstance) error {
 	ins := n.toRegisterInstance(instance)
 	ok, err := n.namingClient.Client().RegisterInstance(ins)
+	if err != nil && isMetadataTooLarge(err) {
+		logger.Warnf("The metadata of the instance %s is rejected by nacos for its size, the largest keys are %v, error: %v",
+			instance.GetServiceName(), largestMetadataKeys(ins.Metadata, largestMetadataKeysNum), err)
+		if n.metadataFallback {
+			ok, err = n.registerWithRemoteMetadata(instance, ins)
+		}
+	}
+	n.health.SetConnected(err == nil)
 	if err != nil || !ok {
 		return perrors.WithMessage(err, "Could not register the instance. "+instance.GetServiceName())
 	}
@@ -96,6 +119,59 @@ func (n *nacosServiceDiscovery) Register(instance registry.ServiceInstance) erro
 	return nil
 }
 
+// registerWithRemoteMetadata publishes the metadata info of @instance to the metadata report,
+// and registers @ins with the metadata which is necessary to look it up in the report only
+func (n *nacosServiceDiscovery) registerWithRemoteMetadata(instance registry.ServiceInstance, ins vo.RegisterInstanceParam) (bool, error) {
+	remoteMetadataService, err := extension.GetRemoteMetadataService()
+	if err != nil {
+		return false, perrors.WithMessage(err, "could not fall back to the metadata report")
+	}
+	remoteMetadataService.PublishMetadata(instance.GetServiceName())
+
+	metadata := make(map[string]string, len(fallbackMetadataKeys)+1)
+	for _, key := range fallbackMetadataKeys {
+		if value, ok := ins.Metadata[key]; ok {
+			metadata[key] = value
+		}
+	}
+	metadata[constant.METADATA_STORAGE_TYPE_PROPERTY_NAME] = constant.REMOTE_METADATA_STORAGE_TYPE
+	ins.Metadata = metadata
+	logger.Infof("Register the instance %s with its metadata in the metadata report", instance.GetServiceName())
+	return n.namingClient.Client().RegisterInstance(ins)
+}
+
+// isMetadataTooLarge checks whether @err is the rejection of the metadata exceeding the size limit of nacos
+func isMetadataTooLarge(err error) bool {
+	msg := strings.ToLower(err.Error())
+	if !strings.Contains(msg, "metadata") {
+		return false
+	}
+	for _, hint := range []string{"too large", "over limit", "exceed", "size"} {
+		if strings.Contains(msg, hint) {
+			return true
+		}
+	}
+	return false
+}
+
+// largestMetadataKeys returns at most @num keys of @metadata with the largest sizes in descending order
+func largestMetadataKeys(metadata map[string]string, num int) []string {
+	keys := make([]string, 0, len(metadata))
+	for k := range metadata {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return len(keys[i])+len(metadata[keys[i]]) > len(keys[j])+len(metadata[keys[j]])
+	})
+	if len(keys) > num {
+		keys = keys[:num]
+	}
+	for i, k := range keys {
+		keys[i] = fmt.Sprintf("%s(%d bytes)", k, len(k)+len(metadata[k]))
+	}
+	return keys
+}
+
 // Update will update the information
 // However, because nacos client doesn't support the update API,
 // so we should unregister the instance and then register it again.
@@ -112,6 +188,7 @@ func (n *nacosServiceDiscovery) Update(instance registry.ServiceInstance) error
 // Unregister will unregister the instance
 func (n *nacosServiceDiscovery) Unregister(instance registry.ServiceInstance) error {
 	ok, err := n.namingClient.Client().DeregisterInstance(n.toDeregisterInstance(instance))
+	n.health.SetConnected(err == nil)
 	if err != nil || !ok {
 		return perrors.WithMessage(err, "Could not unregister the instance. "+instance.GetServiceName())
 	}
@@ -147,6 +224,7 @@ func (n *nacosServiceDiscovery) GetInstances(serviceName string) []registry.Serv
 		ServiceName: serviceName,
 		GroupName:   n.group,
 	})
+	n.health.SetConnected(err == nil)
 	if err != nil {
 		logger.Errorf("Could not query the instances for service: %+v, group: %+v . It happened err %+v",
 			serviceName, n.group, err)
@@ -154,7 +232,7 @@ func (n *nacosServiceDiscovery) GetInstances(serviceName string) []registry.Serv
 	}
 	res := make([]registry.ServiceInstance, 0, len(instances))
 	for _, ins := range instances {
-		metadata := ins.Metadata
+		metadata := instanceMetadata(ins.Metadata, ins.Weight)
 		id := metadata[idKey]
 
 		delete(metadata, idKey)
@@ -175,6 +253,19 @@ func (n *nacosServiceDiscovery) GetInstances(serviceName string) []registry.Serv
 	return res
 }
 
+// instanceMetadata returns the @metadata of the nacos instance with its @weight, which scales the weights of
+// the providers of the instance once it's adjusted in the nacos console
+func instanceMetadata(metadata map[string]string, weight float64) map[string]string {
+	if weight == 1 {
+		return metadata
+	}
+	if metadata == nil {
+		metadata = make(map[string]string, 1)
+	}
+	metadata[constant.INSTANCE_WEIGHT_KEY] = strconv.FormatFloat(weight, 'f', -1, 64)
+	return metadata
+}
+
 // GetInstancesByPage will return the instances
 // Due to nacos namingClient does not support pagination, so we have to query all instances and then return part of them
 func (n *nacosServiceDiscovery) GetInstancesByPage(serviceName string, offset int, pageSize int) gxpage.Pager {
@@ -253,11 +344,14 @@ func (n *nacosServiceDiscovery) AddListener(listener registry.ServiceInstancesCh
 				if err != nil {
 					logger.Errorf("Could not handle the subscribe notification because the err is not nil."+
 						" service name: %s, err: %v", serviceName, err)
+					n.health.SetConnected(false)
+				} else {
+					n.health.Active()
 				}
 				instances := make([]registry.ServiceInstance, 0, len(services))
 				for _, service := range services {
 					// we won't use the nacos instance id here but use our instance id
-					metadata := service.Metadata
+					metadata := instanceMetadata(service.Metadata, service.Weight)
 					id := metadata[idKey]
 
 					delete(metadata, idKey)
@@ -293,6 +387,19 @@ func (n *nacosServiceDiscovery) AddListener(listener registry.ServiceInstancesCh
 	return nil
 }
 
+// RemoveListener removes the listener from the services it listens to
+func (n *nacosServiceDiscovery) RemoveListener(listener registry.ServiceInstancesChangedListener) error {
+	n.listenerLock.Lock()
+	defer n.listenerLock.Unlock()
+
+	for _, t := range listener.GetServiceNames().Values() {
+		if listenerSet, found := n.instanceListenerMap[t.(string)]; found {
+			listenerSet.Remove(listener)
+		}
+	}
+	return nil
+}
+
 // toRegisterInstance convert the ServiceInstance to RegisterInstanceParam
 // the Ephemeral will be true
 func (n *nacosServiceDiscovery) toRegisterInstance(instance registry.ServiceInstance) vo.RegisterInstanceParam {
@@ -355,6 +462,8 @@ func newNacosServiceDiscovery() (registry.ServiceDiscovery, error) {
 		descriptor:          descriptor,
 		registryInstances:   []registry.ServiceInstance{},
 		instanceListenerMap: make(map[string]*gxset.HashSet),
+		metadataFallback:    metadataReportConfig.RegistryFallback,
+		health:              metrics.NewConnectionHealth(metrics.RegistryComponent, url),
 	}
 	return newInstance, nil
 }
@@ -0,0 +1,148 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package nacos
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+import (
+	nacosClient "github.com/dubbogo/gost/database/kv/nacos"
+
+	"github.com/nacos-group/nacos-sdk-go/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/vo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/metadata/service"
+	"dubbo.apache.org/dubbo-go/v3/registry"
+)
+
+const mockMetadataSizeLimit = 1024
+
+// mockSizeLimitedNamingClient rejects the instances whose metadata exceeds mockMetadataSizeLimit
+type mockSizeLimitedNamingClient struct {
+	naming_client.INamingClient
+	registered []vo.RegisterInstanceParam
+}
+
+func (m *mockSizeLimitedNamingClient) RegisterInstance(param vo.RegisterInstanceParam) (bool, error) {
+	size := 0
+	for k, v := range param.Metadata {
+		size += len(k) + len(v)
+	}
+	if size > mockMetadataSizeLimit {
+		return false, errors.New("request return error code 400: Instance metadata size exceeds the limit")
+	}
+	m.registered = append(m.registered, param)
+	return true, nil
+}
+
+type mockRemoteMetadataService struct {
+	local     *common.MetadataInfo
+	published map[string]*common.MetadataInfo
+}
+
+func (m *mockRemoteMetadataService) PublishMetadata(string) {
+	m.published[m.local.Revision] = m.local
+}
+
+func (m *mockRemoteMetadataService) GetMetadata(instance registry.ServiceInstance) (*common.MetadataInfo, error) {
+	revision := instance.GetMetadata()[constant.EXPORTED_SERVICES_REVISION_PROPERTY_NAME]
+	if info, ok := m.published[revision]; ok {
+		return info, nil
+	}
+	return nil, errors.New("metadata not found")
+}
+
+func (m *mockRemoteMetadataService) PublishServiceDefinition(*common.URL) error {
+	return nil
+}
+
+func TestNacosServiceDiscoveryRegisterMetadataTooLarge(t *testing.T) {
+	namingClient := &mockSizeLimitedNamingClient{}
+	client := &nacosClient.NacosNamingClient{}
+	client.SetClient(namingClient)
+	remoteMetadataService := &mockRemoteMetadataService{
+		local:     common.NewMetadataInfo("app", "revision-1", map[string]*common.ServiceInfo{}),
+		published: make(map[string]*common.MetadataInfo),
+	}
+	extension.SetRemoteMetadataService(func() (service.RemoteMetadataService, error) {
+		return remoteMetadataService, nil
+	})
+
+	newInstance := func() *registry.DefaultServiceInstance {
+		return &registry.DefaultServiceInstance{
+			ID:          "127.0.0.1:20000",
+			ServiceName: "app",
+			Host:        "127.0.0.1",
+			Port:        20000,
+			Enable:      true,
+			Healthy:     true,
+			Metadata: map[string]string{
+				constant.EXPORTED_SERVICES_REVISION_PROPERTY_NAME: "revision-1",
+				constant.SERVICE_INSTANCE_ENDPOINTS:               `[{"port":20000,"protocol":"dubbo"}]`,
+				"service.definitions":                             strings.Repeat("x", 2*mockMetadataSizeLimit),
+			},
+		}
+	}
+
+	// the rejection is reported without the fallback
+	sd := &nacosServiceDiscovery{
+		group:        defaultGroup,
+		namingClient: client,
+	}
+	assert.Error(t, sd.Register(newInstance()))
+	assert.Empty(t, namingClient.registered)
+
+	// the instance is registered with the metadata in the metadata report
+	sd.metadataFallback = true
+	instance := newInstance()
+	assert.NoError(t, sd.Register(instance))
+	assert.Len(t, namingClient.registered, 1)
+	assert.Len(t, sd.registryInstances, 1)
+
+	metadata := namingClient.registered[0].Metadata
+	assert.Equal(t, instance.GetID(), metadata[idKey])
+	assert.Equal(t, "revision-1", metadata[constant.EXPORTED_SERVICES_REVISION_PROPERTY_NAME])
+	assert.Equal(t, constant.REMOTE_METADATA_STORAGE_TYPE, metadata[constant.METADATA_STORAGE_TYPE_PROPERTY_NAME])
+	assert.NotEmpty(t, metadata[constant.SERVICE_INSTANCE_ENDPOINTS])
+	assert.NotContains(t, metadata, "service.definitions")
+
+	// the consumers could retrieve the metadata from the report by the registered instance
+	info, err := remoteMetadataService.GetMetadata(&registry.DefaultServiceInstance{Metadata: metadata})
+	assert.NoError(t, err)
+	assert.Equal(t, remoteMetadataService.local, info)
+}
+
+func TestIsMetadataTooLarge(t *testing.T) {
+	assert.True(t, isMetadataTooLarge(errors.New("Instance metadata size over limit")))
+	assert.True(t, isMetadataTooLarge(errors.New("metadata is too large")))
+	assert.False(t, isMetadataTooLarge(errors.New("connection refused")))
+	assert.False(t, isMetadataTooLarge(errors.New("the size of the request exceeds the limit")))
+
+	keys := largestMetadataKeys(map[string]string{"a": "1", "bb": "22222", "c": "333"}, 2)
+	assert.Equal(t, []string{"bb(7 bytes)", "c(4 bytes)"}, keys)
+}
@@ -0,0 +1,183 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package protocol
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/directory"
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/config"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+// joinedCluster is the cluster invoker joining the directory by the cluster strategy @name
+type joinedCluster struct {
+	name    string
+	invoker protocol.Invoker
+}
+
+// switchableClusterInvoker joins the directory of a reference by the cluster strategy pushed by the config center
+// with the key <application>.<interface>.cluster, e.g. failfast to stop the retries of failover during an incident,
+// and the strategy of the reference is restored once it's removed. The invocations in flight finish on the
+// strategy they start with, and the unknown strategies are rejected keeping the current one.
+type switchableClusterInvoker struct {
+	dir        directory.Directory
+	key        string
+	configured string
+	current    atomic.Value
+
+	lock sync.Mutex
+	// joined holds the cluster invokers by the strategies, which share the directory and are destroyed together
+	joined map[string]protocol.Invoker
+}
+
+// newSwitchableClusterInvoker joins @dir by @clusterName, and listens to the strategy of the reference @url
+// in the config center, which must be started
+func newSwitchableClusterInvoker(dir directory.Directory, clusterName string, url *common.URL) *switchableClusterInvoker {
+	invoker := extension.GetCluster(clusterName).Join(dir)
+	sci := &switchableClusterInvoker{
+		dir:        dir,
+		key:        url.GetParam(constant.APPLICATION_KEY, "") + "." + url.GetParam(constant.INTERFACE_KEY, url.Service()) + constant.ClusterRuleSuffix,
+		configured: clusterName,
+		joined:     map[string]protocol.Invoker{clusterName: invoker},
+	}
+	sci.current.Store(&joinedCluster{name: clusterName, invoker: invoker})
+	sci.subscribe()
+	return sci
+}
+
+// configCenterStarted tells whether the config center pushing the cluster strategies is started
+func configCenterStarted() bool {
+	rootConfig := config.GetRootConfig()
+	return rootConfig.ConfigCenter != nil && rootConfig.ConfigCenter.DynamicConfiguration != nil
+}
+
+func (sci *switchableClusterInvoker) subscribe() {
+	rootConfig := config.GetRootConfig()
+	dynamicConfiguration := rootConfig.ConfigCenter.DynamicConfiguration
+	dynamicConfiguration.AddListener(sci.key, sci, config_center.WithGroup(rootConfig.ConfigCenter.Group))
+	value, err := dynamicConfiguration.GetProperties(sci.key, config_center.WithGroup(rootConfig.ConfigCenter.Group))
+	if err != nil {
+		// the strategy may not be published now
+		logger.Debugf("Can not get the cluster strategy for key=%s, error=%v", sci.key, err)
+		return
+	}
+	sci.switchTo(value)
+}
+
+// Process switches the cluster strategy once it changes in the config center
+func (sci *switchableClusterInvoker) Process(event *config_center.ConfigChangeEvent) {
+	value, _ := event.Value.(string)
+	if event.ConfigType == remoting.EventTypeDel {
+		value = ""
+	}
+	sci.switchTo(value)
+}
+
+// switchTo joins the directory by the strategy @name, and the empty one restores the strategy of the reference
+func (sci *switchableClusterInvoker) switchTo(name string) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = sci.configured
+	}
+	if !extension.HasCluster(name) || name == constant.ClusterKeyMergeable {
+		logger.Warnf("The cluster strategy %s of %s is rejected, and %s is kept", name, sci.key, sci.current.Load().(*joinedCluster).name)
+		return
+	}
+
+	sci.lock.Lock()
+	defer sci.lock.Unlock()
+	if name == sci.current.Load().(*joinedCluster).name {
+		return
+	}
+	invoker, ok := sci.joined[name]
+	if !ok {
+		invoker = extension.GetCluster(name).Join(sci.dir)
+		sci.joined[name] = invoker
+	}
+	sci.current.Store(&joinedCluster{name: name, invoker: invoker})
+	logger.Infof("The cluster strategy of %s is switched to %s", sci.key, name)
+}
+
+func (sci *switchableClusterInvoker) invoker() protocol.Invoker {
+	return sci.current.Load().(*joinedCluster).invoker
+}
+
+// Invoke invokes by the current cluster strategy
+func (sci *switchableClusterInvoker) Invoke(ctx context.Context, invocation protocol.Invocation) protocol.Result {
+	return sci.invoker().Invoke(ctx, invocation)
+}
+
+// GetURL returns the url of the directory
+func (sci *switchableClusterInvoker) GetURL() *common.URL {
+	return sci.invoker().GetURL()
+}
+
+// IsAvailable tells whether the providers of the directory are available
+func (sci *switchableClusterInvoker) IsAvailable() bool {
+	return sci.invoker().IsAvailable()
+}
+
+// Addresses returns the provider addresses resolved by the directory
+func (sci *switchableClusterInvoker) Addresses() []directory.Address {
+	if lister, ok := sci.invoker().(directory.AddressLister); ok {
+		return lister.Addresses()
+	}
+	return nil
+}
+
+// WarmUp connects to the providers resolved by the directory in advance
+func (sci *switchableClusterInvoker) WarmUp(ctx context.Context, top int) error {
+	if warmer, ok := sci.invoker().(directory.Warmer); ok {
+		return warmer.WarmUp(ctx, top)
+	}
+	return nil
+}
+
+// WaitReady waits until at least @min providers resolved by the directory are available
+func (sci *switchableClusterInvoker) WaitReady(ctx context.Context, min int) error {
+	if waiter, ok := sci.invoker().(directory.ReadyWaiter); ok {
+		return waiter.WaitReady(ctx, min)
+	}
+	return directory.WaitInvokersReady(ctx, []protocol.Invoker{sci.invoker()}, min)
+}
+
+// Destroy stops listening to the strategy, and destroys the cluster invokers of all of the strategies
+func (sci *switchableClusterInvoker) Destroy() {
+	if configCenterStarted() {
+		rootConfig := config.GetRootConfig()
+		rootConfig.ConfigCenter.DynamicConfiguration.RemoveListener(sci.key, sci,
+			config_center.WithGroup(rootConfig.ConfigCenter.Group))
+	}
+	sci.lock.Lock()
+	defer sci.lock.Unlock()
+	for _, invoker := range sci.joined {
+		invoker.Destroy()
+	}
+}
@@ -0,0 +1,122 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package protocol
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	_ "dubbo.apache.org/dubbo-go/v3/cluster/cluster/failfast"
+	_ "dubbo.apache.org/dubbo-go/v3/cluster/cluster/failover"
+	"dubbo.apache.org/dubbo-go/v3/cluster/directory/static"
+	_ "dubbo.apache.org/dubbo-go/v3/cluster/loadbalance/random"
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/config"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+// listenerDynamicConfiguration records the listeners to push the strategies to them
+type listenerDynamicConfiguration struct {
+	config_center.MockDynamicConfiguration
+	listeners map[string]config_center.ConfigurationListener
+}
+
+func (c *listenerDynamicConfiguration) AddListener(key string, listener config_center.ConfigurationListener, _ ...config_center.Option) {
+	c.listeners[key] = listener
+}
+
+func (c *listenerDynamicConfiguration) RemoveListener(key string, _ config_center.ConfigurationListener, _ ...config_center.Option) {
+	delete(c.listeners, key)
+}
+
+func (c *listenerDynamicConfiguration) GetProperties(_ string, _ ...config_center.Option) (string, error) {
+	return "", nil
+}
+
+func (c *listenerDynamicConfiguration) push(key, value string) {
+	var eventType remoting.EventType = remoting.EventTypeUpdate
+	if value == "" {
+		eventType = remoting.EventTypeDel
+	}
+	c.listeners[key].Process(&config_center.ConfigChangeEvent{Key: key, Value: value, ConfigType: eventType})
+}
+
+// failingInvoker counts the invocations which always fail
+type failingInvoker struct {
+	protocol.BaseInvoker
+	invoked int32
+}
+
+func (fi *failingInvoker) Invoke(_ context.Context, _ protocol.Invocation) protocol.Result {
+	atomic.AddInt32(&fi.invoked, 1)
+	return &protocol.RPCResult{Err: perrors.New("the provider fails")}
+}
+
+func TestSwitchableClusterInvoker(t *testing.T) {
+	originRootConf := config.GetRootConfig()
+	dynamicConfiguration := &listenerDynamicConfiguration{listeners: map[string]config_center.ConfigurationListener{}}
+	config.SetRootConfig(config.RootConfig{
+		Application:  &config.ApplicationConfig{Name: "order-center"},
+		ConfigCenter: &config.CenterConfig{DynamicConfiguration: dynamicConfiguration},
+	})
+	defer config.SetRootConfig(*originRootConf)
+	assert.True(t, configCenterStarted())
+
+	url, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?application=order-center" +
+		"&interface=com.ikurento.user.UserProvider&retries=2")
+	assert.NoError(t, err)
+	provider := &failingInvoker{BaseInvoker: *protocol.NewBaseInvoker(url)}
+	invoker := newSwitchableClusterInvoker(static.NewDirectory([]protocol.Invoker{provider}), "failover", url)
+	const key = "order-center.com.ikurento.user.UserProvider.cluster"
+	assert.Contains(t, dynamicConfiguration.listeners, key)
+
+	invoked := func() int32 {
+		before := atomic.LoadInt32(&provider.invoked)
+		assert.Error(t, invoker.Invoke(context.Background(), invocation.NewRPCInvocation("GetUser", nil, nil)).Error())
+		return atomic.LoadInt32(&provider.invoked) - before
+	}
+	// failover retries the failed invocation
+	assert.Greater(t, invoked(), int32(1))
+
+	// the subsequent invocations don't retry once it's switched to failfast
+	dynamicConfiguration.push(key, "failfast")
+	assert.Equal(t, int32(1), invoked())
+
+	// the unknown strategy is rejected keeping failfast
+	dynamicConfiguration.push(key, "unknown")
+	assert.Equal(t, int32(1), invoked())
+
+	// and the strategy of the reference is restored once the pushed one is removed
+	dynamicConfiguration.push(key, "")
+	assert.Greater(t, invoked(), int32(1))
+
+	invoker.Destroy()
+	assert.NotContains(t, dynamicConfiguration.listeners, key)
+}
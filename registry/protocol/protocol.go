@@ -19,8 +19,10 @@ package protocol
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"sync"
+	"time"
 )
 
 import (
@@ -66,6 +68,44 @@ type registryProtocol struct {
 	serviceConfigurationListeners *sync.Map
 	providerConfigurationListener *providerConfigurationListener
 	once                          sync.Once
+	// registrationsLock guards registrations and unregistered, and serializes UnregisterAll and ReRegisterAll
+	registrationsLock sync.Mutex
+	// the provider urls registered by the exported services, registry key + provider key <--> registration
+	registrations map[string]*registration
+	// the registrations are pulled by UnregisterAll
+	unregistered bool
+	// the registrations deferred by the startup delay which aren't done yet
+	deferredRegistrations map[*deferredRegistration]struct{}
+}
+
+// deferredRegistration is the registration of the provider url deferred by the startup delay
+type deferredRegistration struct {
+	stop chan struct{}
+	// finished is guarded by registrationsLock, it's set once the registration is done or cancelled
+	finished bool
+}
+
+// registryExporter unregisters the provider url once it's unexported, so it isn't registered again by ReRegisterAll
+type registryExporter struct {
+	protocol.Exporter
+	proto   *registryProtocol
+	invoker protocol.Invoker
+	// cancel cancels the registration deferred by the startup delay, it's nil if the registration isn't deferred
+	cancel func()
+}
+
+func (e *registryExporter) Unexport() {
+	if e.cancel != nil {
+		e.cancel()
+	}
+	e.proto.unregisterProviderUrl(e.invoker)
+	e.Exporter.Unexport()
+}
+
+// registration is the provider url registered to the registry
+type registration struct {
+	reg registry.Registry
+	url *common.URL
 }
 
 func init() {
@@ -80,8 +120,11 @@ func getCacheKey(invoker protocol.Invoker) string {
 
 func newRegistryProtocol() *registryProtocol {
 	return &registryProtocol{
-		registries: &sync.Map{},
-		bounds:     &sync.Map{},
+		registries:    &sync.Map{},
+		bounds:        &sync.Map{},
+		registrations: make(map[string]*registration),
+
+		deferredRegistrations: make(map[*deferredRegistration]struct{}),
 	}
 }
 
@@ -95,11 +138,33 @@ func getRegistry(regUrl *common.URL) registry.Registry {
 }
 
 func getUrlToRegistry(providerUrl *common.URL, registryUrl *common.URL) *common.URL {
+	var url *common.URL
 	if registryUrl.GetParamBool("simplified", false) {
-		return providerUrl.CloneWithParams(reserveParams)
+		url = providerUrl.CloneWithParams(reserveParams)
 	} else {
-		return filterHideKey(providerUrl)
+		url = filterHideKey(providerUrl)
 	}
+	return advertise(url, providerUrl)
+}
+
+// advertise replaces the bound address of @url with the advertised one of @providerUrl if it's configured,
+// while the server of the provider still binds the address of @providerUrl
+func advertise(url *common.URL, providerUrl *common.URL) *common.URL {
+	host := providerUrl.GetParam(constant.ADVERTISED_HOST_KEY, "")
+	port := providerUrl.GetParam(constant.ADVERTISED_PORT_KEY, "")
+	if host == "" && port == "" {
+		return url
+	}
+	url.DelParam(constant.ADVERTISED_HOST_KEY)
+	url.DelParam(constant.ADVERTISED_PORT_KEY)
+	if host != "" {
+		url.Ip = host
+	}
+	if port != "" {
+		url.Port = port
+	}
+	url.Location = url.Ip + ":" + url.Port
+	return url
 }
 
 // filterHideKey filter the parameters that do not need to be output in url(Starting with .)
@@ -156,15 +221,27 @@ func (proto *registryProtocol) Refer(url *common.URL) protocol.Invoker {
 		return nil
 	}
 
-	err = reg.Register(serviceUrl)
-	if err != nil {
-		logger.Errorf("consumer service %v register registry %v error, error message is %s",
-			serviceUrl.String(), registryUrl.String(), err.Error())
+	// observer consumer only discovers the providers, it is not registered to the registry
+	if !serviceUrl.GetParamBool(constant.OBSERVER_KEY, false) {
+		err = reg.Register(serviceUrl)
+		if err != nil {
+			logger.Errorf("consumer service %v register registry %v error, error message is %s",
+				serviceUrl.String(), registryUrl.String(), err.Error())
+		}
 	}
 
-	// new cluster invoker
-	cluster := extension.GetCluster(serviceUrl.GetParam(constant.CLUSTER_KEY, constant.DEFAULT_CLUSTER))
-	invoker := cluster.Join(directory)
+	// new cluster invoker, and the groups of the reference with group * or a group list are merged
+	clusterName := serviceUrl.GetParam(constant.CLUSTER_KEY, constant.DEFAULT_CLUSTER)
+	if group := serviceUrl.GetParam(constant.GROUP_KEY, ""); group == constant.ANY_VALUE || strings.Contains(group, ",") {
+		clusterName = constant.ClusterKeyMergeable
+	}
+	var invoker protocol.Invoker
+	if clusterName != constant.ClusterKeyMergeable && configCenterStarted() {
+		// the strategy may be switched by the config center at runtime
+		invoker = newSwitchableClusterInvoker(directory, clusterName, serviceUrl)
+	} else {
+		invoker = extension.GetCluster(clusterName).Join(directory)
+	}
 	proto.invokers = append(proto.invokers, invoker)
 	return invoker
 }
@@ -187,6 +264,7 @@ func (proto *registryProtocol) Export(invoker protocol.Invoker) protocol.Exporte
 	serviceConfigurationListener.OverrideUrl(providerUrl)
 
 	var reg registry.Registry
+	startupDelay := providerUrl.GetParamDuration(constant.STARTUP_DELAY_KEY, "0s")
 	if registryUrl.Protocol != "" {
 		if regI, loaded := proto.registries.Load(registryUrl.Key()); !loaded {
 			reg = getRegistry(registryUrl)
@@ -195,12 +273,10 @@ func (proto *registryProtocol) Export(invoker protocol.Invoker) protocol.Exporte
 		} else {
 			reg = regI.(registry.Registry)
 		}
-		registeredProviderUrl := getUrlToRegistry(providerUrl, registryUrl)
-		err := reg.Register(registeredProviderUrl)
-		if err != nil {
-			logger.Errorf("provider service %v register registry %v error, error message is %s",
-				providerUrl.Key(), registryUrl.Key(), err.Error())
-			return nil
+		if startupDelay <= 0 {
+			if err := proto.registerProviderUrl(reg, providerUrl, registryUrl); err != nil {
+				return nil
+			}
 		}
 	}
 
@@ -216,14 +292,98 @@ func (proto *registryProtocol) Export(invoker protocol.Invoker) protocol.Exporte
 		logger.Infof("The exporter has not been cached, and will return a new exporter!")
 	}
 
+	exporter := cachedExporter.(protocol.Exporter)
 	if registryUrl.Protocol != "" {
+		regExporter := &registryExporter{Exporter: exporter, proto: proto, invoker: invoker}
+		if startupDelay > 0 {
+			// the provider is bound and serving, but it's registered after warming up
+			regExporter.cancel = proto.deferRegisterProviderUrl(reg, providerUrl, registryUrl, startupDelay)
+		}
+		exporter = regExporter
 		go func() {
 			if err := reg.Subscribe(overriderUrl, overrideSubscribeListener); err != nil {
 				logger.Warnf("reg.subscribe(overriderUrl:%v) = error:%v", overriderUrl, err)
 			}
 		}()
 	}
-	return cachedExporter.(protocol.Exporter)
+	return exporter
+}
+
+// registerProviderUrl registers the provider url and records it for ReRegisterAll,
+// it's only recorded if the registrations are pulled by UnregisterAll now
+func (proto *registryProtocol) registerProviderUrl(reg registry.Registry, providerUrl, registryUrl *common.URL) error {
+	proto.registrationsLock.Lock()
+	defer proto.registrationsLock.Unlock()
+	return proto.doRegisterProviderUrl(reg, providerUrl, registryUrl)
+}
+
+// doRegisterProviderUrl is registerProviderUrl with registrationsLock held
+func (proto *registryProtocol) doRegisterProviderUrl(reg registry.Registry, providerUrl, registryUrl *common.URL) error {
+	registeredProviderUrl := getUrlToRegistry(providerUrl, registryUrl)
+	if !proto.unregistered {
+		if err := reg.Register(registeredProviderUrl); err != nil {
+			logger.Errorf("provider service %v register registry %v error, error message is %s",
+				providerUrl.Key(), registryUrl.Key(), err.Error())
+			return err
+		}
+	}
+	proto.registrations[registrationKey(registeredProviderUrl, registryUrl)] = &registration{
+		reg: reg,
+		url: registeredProviderUrl,
+	}
+	return nil
+}
+
+func registrationKey(registeredProviderUrl, registryUrl *common.URL) string {
+	return registryUrl.Key() + "|" + registeredProviderUrl.Key()
+}
+
+// deferRegisterProviderUrl registers the provider url once the @delay elapses or the provider is marked ready,
+// the returned function cancels the registration if it isn't done yet, e.g. once the service is unexported
+func (proto *registryProtocol) deferRegisterProviderUrl(reg registry.Registry, providerUrl, registryUrl *common.URL,
+	delay time.Duration) func() {
+	done := common.DeferStartup()
+	deferred := &deferredRegistration{stop: make(chan struct{})}
+	proto.registrationsLock.Lock()
+	proto.deferredRegistrations[deferred] = struct{}{}
+	proto.registrationsLock.Unlock()
+	logger.Infof("The registration of the provider service %v is deferred for %v", providerUrl.Key(), delay)
+	go func() {
+		defer done()
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-common.ReadyNotify():
+		case <-deferred.stop:
+			logger.Infof("The deferred registration of the provider service %v is cancelled", providerUrl.Key())
+			return
+		}
+		proto.registrationsLock.Lock()
+		defer proto.registrationsLock.Unlock()
+		// the exporter may be gone right before the registration
+		if deferred.finished {
+			return
+		}
+		deferred.finished = true
+		delete(proto.deferredRegistrations, deferred)
+		_ = proto.doRegisterProviderUrl(reg, providerUrl, registryUrl)
+	}()
+	return func() {
+		proto.registrationsLock.Lock()
+		defer proto.registrationsLock.Unlock()
+		proto.cancelDeferredRegistration(deferred)
+	}
+}
+
+// cancelDeferredRegistration cancels @deferred with registrationsLock held
+func (proto *registryProtocol) cancelDeferredRegistration(deferred *deferredRegistration) {
+	if deferred.finished {
+		return
+	}
+	deferred.finished = true
+	close(deferred.stop)
+	delete(proto.deferredRegistrations, deferred)
 }
 
 func (proto *registryProtocol) reExport(invoker protocol.Invoker, newUrl *common.URL) {
@@ -232,6 +392,8 @@ func (proto *registryProtocol) reExport(invoker protocol.Invoker, newUrl *common
 		wrappedNewInvoker := newWrappedInvoker(invoker, newUrl)
 		oldExporter.(protocol.Exporter).Unexport()
 		proto.bounds.Delete(key)
+		// unregister the old provider url, otherwise the registry refuses to register the new one
+		proto.unregisterProviderUrl(invoker)
 		// oldExporter Unexport function unRegister rpcService from the serviceMap, so need register it again as far as possible
 		if err := registerServiceMap(invoker); err != nil {
 			logger.Error(err.Error())
@@ -241,6 +403,83 @@ func (proto *registryProtocol) reExport(invoker protocol.Invoker, newUrl *common
 	}
 }
 
+func (proto *registryProtocol) unregisterProviderUrl(invoker protocol.Invoker) {
+	registryUrl := getRegistryUrl(invoker)
+	if registryUrl.Protocol == "" {
+		return
+	}
+	regI, loaded := proto.registries.Load(registryUrl.Key())
+	if !loaded {
+		return
+	}
+	registeredProviderUrl := getUrlToRegistry(getProviderUrl(invoker), registryUrl)
+	proto.registrationsLock.Lock()
+	defer proto.registrationsLock.Unlock()
+	key := registrationKey(registeredProviderUrl, registryUrl)
+	if _, ok := proto.registrations[key]; !ok {
+		// it's never registered, e.g. the deferred registration is cancelled
+		return
+	}
+	delete(proto.registrations, key)
+	if proto.unregistered {
+		return
+	}
+	if err := regI.(registry.Registry).UnRegister(registeredProviderUrl); err != nil {
+		logger.Warnf("provider service %v unregister registry %v error, error message is %s",
+			registeredProviderUrl.Key(), registryUrl.Key(), err.Error())
+	}
+}
+
+// UnregisterAll unregisters the provider urls of all the exported services from all the registries,
+// the services are still exported and serving, and they're registered again with the same urls by ReRegisterAll.
+// The provider urls of the services exported meanwhile are registered by ReRegisterAll as well.
+func UnregisterAll() error {
+	return GetProtocol().(*registryProtocol).unregisterAll()
+}
+
+// ReRegisterAll registers the provider urls unregistered by UnregisterAll again
+func ReRegisterAll() error {
+	return GetProtocol().(*registryProtocol).reRegisterAll()
+}
+
+func (proto *registryProtocol) unregisterAll() error {
+	proto.registrationsLock.Lock()
+	defer proto.registrationsLock.Unlock()
+	if proto.unregistered {
+		return nil
+	}
+	proto.unregistered = true
+	var errs []string
+	for _, r := range proto.registrations {
+		if err := r.reg.UnRegister(r.url); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", r.url.Key(), err))
+		}
+	}
+	if len(errs) > 0 {
+		return perrors.Errorf("failed to unregister the provider urls: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (proto *registryProtocol) reRegisterAll() error {
+	proto.registrationsLock.Lock()
+	defer proto.registrationsLock.Unlock()
+	if !proto.unregistered {
+		return nil
+	}
+	proto.unregistered = false
+	var errs []string
+	for _, r := range proto.registrations {
+		if err := r.reg.Register(r.url); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", r.url.Key(), err))
+		}
+	}
+	if len(errs) > 0 {
+		return perrors.Errorf("failed to register the provider urls again: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
 func registerServiceMap(invoker protocol.Invoker) error {
 	providerUrl := getProviderUrl(invoker)
 	// the bean.name param of providerUrl is the ServiceConfig id property
@@ -389,6 +628,12 @@ func getSubscribedOverrideUrl(providerUrl *common.URL) *common.URL {
 
 // Destroy registry protocol
 func (proto *registryProtocol) Destroy() {
+	// the deferred registrations are cancelled before the registries are destroyed
+	proto.registrationsLock.Lock()
+	for deferred := range proto.deferredRegistrations {
+		proto.cancelDeferredRegistration(deferred)
+	}
+	proto.registrationsLock.Unlock()
 	// invoker.Destroy() should be performed in config.destroyConsumerProtocols().
 	proto.invokers = []protocol.Invoker{}
 	proto.bounds.Range(func(key, value interface{}) bool {
@@ -405,6 +650,10 @@ func (proto *registryProtocol) Destroy() {
 		proto.registries.Delete(key)
 		return true
 	})
+	proto.registrationsLock.Lock()
+	proto.registrations = make(map[string]*registration)
+	proto.unregistered = false
+	proto.registrationsLock.Unlock()
 }
 
 func getRegistryUrl(invoker protocol.Invoker) *common.URL {
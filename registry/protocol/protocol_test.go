@@ -18,6 +18,8 @@
 package protocol
 
 import (
+	"sort"
+	"sync"
 	"testing"
 	"time"
 )
@@ -133,7 +135,8 @@ func exporterNormal(t *testing.T, regProtocol *registryProtocol) *common.URL {
 	invoker := protocol.NewBaseInvoker(url)
 	exporter := regProtocol.Export(invoker)
 
-	assert.IsType(t, &protocol.BaseExporter{}, exporter)
+	assert.IsType(t, &registryExporter{}, exporter)
+	assert.IsType(t, &protocol.BaseExporter{}, exporter.(*registryExporter).Exporter)
 	assert.Equal(t, exporter.GetInvoker().GetURL().String(), suburl.String())
 	return url
 }
@@ -303,3 +306,242 @@ func TestGetProviderUrlWithHideKey(t *testing.T) {
 	assert.NotContains(t, providerUrl.GetParams(), ".d")
 	assert.Contains(t, providerUrl.GetParams(), "a")
 }
+
+type countingRegistry struct {
+	registry.Registry
+	lock       sync.Mutex
+	registered []*common.URL
+}
+
+func (r *countingRegistry) Register(url *common.URL) error {
+	r.lock.Lock()
+	r.registered = append(r.registered, url)
+	r.lock.Unlock()
+	return r.Registry.Register(url)
+}
+
+func (r *countingRegistry) registeredCount() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return len(r.registered)
+}
+
+func TestObserverRefer(t *testing.T) {
+	reg := &countingRegistry{}
+	extension.SetRegistry("counting", func(url *common.URL) (registry.Registry, error) {
+		mockRegistry, err := registry.NewMockRegistry(url)
+		reg.Registry = mockRegistry
+		return reg, err
+	})
+	extension.SetProtocol(protocolwrapper.FILTER, protocolwrapper.NewMockProtocolFilter)
+	extension.SetCluster("mock", cluster.NewMockCluster)
+	regProtocol := newRegistryProtocol()
+
+	url, _ := common.NewURL("counting://127.0.0.1:1111")
+	url.SubURL, _ = common.NewURL("dubbo://127.0.0.1:20000//",
+		common.WithParamsValue(constant.CLUSTER_KEY, "mock"),
+		common.WithParamsValue(constant.OBSERVER_KEY, "true"))
+	invoker := regProtocol.Refer(url)
+	assert.NotNil(t, invoker)
+	assert.Equal(t, 0, reg.registeredCount())
+
+	url2, _ := common.NewURL("counting://127.0.0.1:1111")
+	url2.SubURL, _ = common.NewURL("dubbo://127.0.0.1:20000//",
+		common.WithParamsValue(constant.CLUSTER_KEY, "mock"))
+	invoker = regProtocol.Refer(url2)
+	assert.NotNil(t, invoker)
+	assert.Equal(t, 1, reg.registeredCount())
+}
+
+func TestExportWithStartupDelay(t *testing.T) {
+	reg := &countingRegistry{}
+	extension.SetRegistry("counting", func(url *common.URL) (registry.Registry, error) {
+		mockRegistry, err := registry.NewMockRegistry(url)
+		reg.Registry = mockRegistry
+		return reg, err
+	})
+	extension.SetProtocol(protocolwrapper.FILTER, protocolwrapper.NewMockProtocolFilter)
+	regProtocol := newRegistryProtocol()
+
+	url, _ := common.NewURL("counting://127.0.0.1:1111")
+	url.SubURL, _ = common.NewURL("dubbo://127.0.0.1:20000/org.apache.dubbo-go.mockService",
+		common.WithParamsValue(constant.STARTUP_DELAY_KEY, "500ms"))
+	start := time.Now()
+	invoker := protocol.NewBaseInvoker(url)
+	assert.NotNil(t, regProtocol.Export(invoker))
+
+	// the provider is exported but neither registered nor ready during the delay
+	_, loaded := regProtocol.bounds.Load(getCacheKey(invoker))
+	assert.True(t, loaded)
+	assert.Equal(t, 0, reg.registeredCount())
+	assert.False(t, common.IsReady())
+
+	assert.Eventually(t, func() bool {
+		return reg.registeredCount() == 1
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.True(t, time.Since(start) >= 500*time.Millisecond)
+	assert.Eventually(t, common.IsReady, time.Second, 10*time.Millisecond)
+}
+
+// discoveryRegistry keeps the registered provider urls as the registry center does
+type discoveryRegistry struct {
+	registry.Registry
+	lock      sync.Mutex
+	providers map[string]*common.URL
+}
+
+func (r *discoveryRegistry) Register(url *common.URL) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.providers[url.Key()] = url
+	return nil
+}
+
+func (r *discoveryRegistry) UnRegister(url *common.URL) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	delete(r.providers, url.Key())
+	return nil
+}
+
+func (r *discoveryRegistry) discover() []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	services := make([]string, 0, len(r.providers))
+	for _, url := range r.providers {
+		services = append(services, url.Service())
+	}
+	sort.Strings(services)
+	return services
+}
+
+func TestUnregisterAllAndReRegisterAll(t *testing.T) {
+	reg := &discoveryRegistry{providers: make(map[string]*common.URL)}
+	extension.SetRegistry("discovery", func(url *common.URL) (registry.Registry, error) {
+		mockRegistry, err := registry.NewMockRegistry(url)
+		reg.Registry = mockRegistry
+		return reg, err
+	})
+	extension.SetProtocol(protocolwrapper.FILTER, protocolwrapper.NewMockProtocolFilter)
+	proto := GetProtocol()
+	defer proto.Destroy()
+
+	for _, service := range []string{"org.apache.dubbo-go.UserProvider", "org.apache.dubbo-go.OrderProvider"} {
+		url, _ := common.NewURL("discovery://127.0.0.1:1111")
+		url.SubURL, _ = common.NewURL("dubbo://127.0.0.1:20000/"+service,
+			common.WithParamsValue(constant.INTERFACE_KEY, service))
+		assert.NotNil(t, proto.Export(protocol.NewBaseInvoker(url)))
+	}
+	expected := []string{"org.apache.dubbo-go.OrderProvider", "org.apache.dubbo-go.UserProvider"}
+	assert.Equal(t, expected, reg.discover())
+
+	assert.NoError(t, UnregisterAll())
+	assert.Empty(t, reg.discover())
+	// it's idempotent while the registrations are pulled
+	assert.NoError(t, UnregisterAll())
+	assert.Empty(t, reg.discover())
+
+	assert.NoError(t, ReRegisterAll())
+	assert.Equal(t, expected, reg.discover())
+}
+
+func TestUnexportBetweenUnregisterAllAndReRegisterAll(t *testing.T) {
+	reg := &discoveryRegistry{providers: make(map[string]*common.URL)}
+	extension.SetRegistry("discovery", func(url *common.URL) (registry.Registry, error) {
+		mockRegistry, err := registry.NewMockRegistry(url)
+		reg.Registry = mockRegistry
+		return reg, err
+	})
+	extension.SetProtocol(protocolwrapper.FILTER, protocolwrapper.NewMockProtocolFilter)
+	proto := GetProtocol()
+	defer proto.Destroy()
+
+	exporters := make(map[string]protocol.Exporter)
+	for _, service := range []string{"org.apache.dubbo-go.UserProvider", "org.apache.dubbo-go.OrderProvider"} {
+		url, _ := common.NewURL("discovery://127.0.0.1:1111")
+		url.SubURL, _ = common.NewURL("dubbo://127.0.0.1:20000/"+service,
+			common.WithParamsValue(constant.INTERFACE_KEY, service))
+		exporters[service] = proto.Export(protocol.NewBaseInvoker(url))
+		assert.NotNil(t, exporters[service])
+	}
+
+	assert.NoError(t, UnregisterAll())
+	assert.Empty(t, reg.discover())
+	exporters["org.apache.dubbo-go.OrderProvider"].Unexport()
+
+	// the unexported service isn't registered again
+	assert.NoError(t, ReRegisterAll())
+	assert.Equal(t, []string{"org.apache.dubbo-go.UserProvider"}, reg.discover())
+
+	// and the service unexported while registered is unregistered
+	exporters["org.apache.dubbo-go.UserProvider"].Unexport()
+	assert.Empty(t, reg.discover())
+}
+
+func TestExportWithAdvertisedAddress(t *testing.T) {
+	reg := &countingRegistry{}
+	extension.SetRegistry("counting", func(url *common.URL) (registry.Registry, error) {
+		mockRegistry, err := registry.NewMockRegistry(url)
+		reg.Registry = mockRegistry
+		return reg, err
+	})
+	extension.SetProtocol(protocolwrapper.FILTER, protocolwrapper.NewMockProtocolFilter)
+	regProtocol := newRegistryProtocol()
+
+	url, _ := common.NewURL("counting://127.0.0.1:1111")
+	url.SubURL, _ = common.NewURL("dubbo://0.0.0.0:20880/org.apache.dubbo-go.advertisedService",
+		common.WithParamsValue(constant.ADVERTISED_HOST_KEY, "203.0.113.10"),
+		common.WithParamsValue(constant.ADVERTISED_PORT_KEY, "30880"))
+	exporter := regProtocol.Export(protocol.NewBaseInvoker(url))
+	assert.NotNil(t, exporter)
+
+	// the server binds the local address
+	assert.Equal(t, "0.0.0.0:20880", exporter.GetInvoker().GetURL().Location)
+	// while the registered one is the advertised address
+	assert.Equal(t, 1, reg.registeredCount())
+	registered := reg.registered[0]
+	assert.Equal(t, "203.0.113.10", registered.Ip)
+	assert.Equal(t, "30880", registered.Port)
+	assert.Equal(t, "203.0.113.10:30880", registered.Location)
+	assert.NotContains(t, registered.GetParams(), constant.ADVERTISED_HOST_KEY)
+	assert.NotContains(t, registered.GetParams(), constant.ADVERTISED_PORT_KEY)
+
+	// the registered address is unchanged by default
+	url2, _ := common.NewURL("counting://127.0.0.1:1111")
+	url2.SubURL, _ = common.NewURL("dubbo://127.0.0.1:20881/org.apache.dubbo-go.defaultService")
+	assert.NotNil(t, regProtocol.Export(protocol.NewBaseInvoker(url2)))
+	assert.Equal(t, 2, reg.registeredCount())
+	assert.Equal(t, "127.0.0.1:20881", reg.registered[1].Location)
+}
+
+func TestExportWithStartupDelayCancelled(t *testing.T) {
+	reg := &countingRegistry{}
+	extension.SetRegistry("counting", func(url *common.URL) (registry.Registry, error) {
+		mockRegistry, err := registry.NewMockRegistry(url)
+		reg.Registry = mockRegistry
+		return reg, err
+	})
+	extension.SetProtocol(protocolwrapper.FILTER, protocolwrapper.NewMockProtocolFilter)
+
+	// the registration is skipped once the service is unexported
+	regProtocol := newRegistryProtocol()
+	url, _ := common.NewURL("counting://127.0.0.1:1111")
+	url.SubURL, _ = common.NewURL("dubbo://127.0.0.1:20000/org.apache.dubbo-go.mockService",
+		common.WithParamsValue(constant.STARTUP_DELAY_KEY, "200ms"))
+	exporter := regProtocol.Export(protocol.NewBaseInvoker(url))
+	assert.False(t, common.IsReady())
+	exporter.Unexport()
+	assert.Eventually(t, common.IsReady, time.Second, 10*time.Millisecond)
+
+	// and once the protocol is destroyed
+	regProtocol = newRegistryProtocol()
+	url, _ = common.NewURL("counting://127.0.0.1:1111")
+	url.SubURL, _ = common.NewURL("dubbo://127.0.0.1:20000/org.apache.dubbo-go.mockService",
+		common.WithParamsValue(constant.STARTUP_DELAY_KEY, "200ms"))
+	assert.NotNil(t, regProtocol.Export(protocol.NewBaseInvoker(url)))
+	regProtocol.Destroy()
+	assert.Eventually(t, common.IsReady, time.Second, 10*time.Millisecond)
+
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal(t, 0, reg.registeredCount())
+}
@@ -17,10 +17,25 @@
 
 package registry
 
+import (
+	"fmt"
+)
+
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
 )
 
+// AlreadyRegisteredError is returned when the same service instance is registered twice by the same process
+// without unregistering it first.
+type AlreadyRegisteredError struct {
+	// Key is the key of the registered url
+	Key string
+}
+
+func (e *AlreadyRegisteredError) Error() string {
+	return fmt.Sprintf("Path{%s} has been registered", e.Key)
+}
+
 /*
  * -----------------------------------NOTICE---------------------------------------------
  * If there is no special case, you'd better inherit BaseRegistry and implement the
@@ -66,6 +81,14 @@ type Registry interface {
 	UnSubscribe(*common.URL, NotifyListener) error
 }
 
+// ServiceLister enumerates the services registered in the registry rather than subscribing to the known ones,
+// e.g. for the service catalogs. It's implemented by the service discovery registries.
+type ServiceLister interface {
+	// ListServices returns the keys of all the services registered currently, i.e. group/interface:version,
+	// which are resolved again by every call to reflect the services added or removed since then.
+	ListServices() []string
+}
+
 // nolint
 type NotifyListener interface {
 	// Notify supports notifications on the service interface and the dimension of the data type. When a list of
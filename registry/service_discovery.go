@@ -75,4 +75,7 @@ type ServiceDiscovery interface {
 	// AddListener adds a new ServiceInstancesChangedListenerImpl
 	// see addServiceInstancesChangedListener in Java
 	AddListener(listener ServiceInstancesChangedListener) error
+
+	// RemoveListener removes the listener added by AddListener, it isn't notified of the changes afterwards
+	RemoveListener(listener ServiceInstancesChangedListener) error
 }
@@ -19,7 +19,11 @@ package registry
 
 import (
 	"encoding/json"
+	"math"
+	"net/url"
 	"strconv"
+	"strings"
+	"sync"
 )
 
 import (
@@ -141,18 +145,80 @@ func (d *DefaultServiceInstance) SetServiceMetadata(m *common.MetadataInfo) {
 	d.ServiceMetadata = m
 }
 
-// ToURLs
+// instanceLabelKeys are the keys of the metadata labels of the instances read by the routers
+var instanceLabelKeys sync.Map
+
+// RegisterInstanceLabelKey copies the metadata label @key of the instances into the params of the urls
+// returned by ToURLs, so that the routers can read it. The urls converted before aren't changed until the
+// instances change.
+func RegisterInstanceLabelKey(key string) {
+	instanceLabelKeys.Store(key, struct{}{})
+}
+
+// ToURLs returns the urls of the services exported by this instance,
+// the port of each url is the endpoint of its protocol if the instance exports more than one protocol
 func (d *DefaultServiceInstance) ToURLs() []*common.URL {
+	protocolPorts := make(map[string]int, 4)
+	for _, endpoint := range d.GetEndPoints() {
+		protocolPorts[endpoint.Protocol] = endpoint.Port
+	}
 	urls := make([]*common.URL, 0, 8)
 	for _, service := range d.ServiceMetadata.Services {
+		port, ok := protocolPorts[service.Protocol]
+		if !ok {
+			port = d.Port
+		}
+		params := service.GetParams()
+		if weight := d.Metadata[constant.INSTANCE_WEIGHT_KEY]; len(weight) > 0 {
+			if w, err := strconv.ParseFloat(weight, 64); err == nil {
+				ScaleWeights(params, w)
+			} else {
+				logger.Warnf("The weight %s of the instance %s is invalid, error: %v", weight, d.ID, err)
+			}
+		}
 		url := common.NewURLWithOptions(common.WithProtocol(service.Protocol),
-			common.WithIp(d.Host), common.WithPort(strconv.Itoa(d.Port)),
-			common.WithMethods(service.GetMethods()), common.WithParams(service.GetParams()))
+			common.WithIp(d.Host), common.WithPort(strconv.Itoa(port)), common.WithPath(service.Path),
+			common.WithMethods(service.GetMethods()), common.WithParams(params),
+			common.WithParamsValue(constant.INTERFACE_KEY, service.Name))
+		if locality := d.Metadata[constant.LOCALITY_KEY]; len(locality) > 0 {
+			url.SetParam(constant.LOCALITY_KEY, locality)
+		}
+		// the labels of the instance read by the routers, e.g. the one of the canary providers
+		instanceLabelKeys.Range(func(key, _ interface{}) bool {
+			if value := d.Metadata[key.(string)]; value != "" && url.GetParam(key.(string), "") == "" {
+				url.SetParam(key.(string), value)
+			}
+			return true
+		})
 		urls = append(urls, url)
 	}
 	return urls
 }
 
+// ScaleWeights scales the weights of the provider in @params by the weight of its instance in the registry, e.g.
+// the one adjusted in the nacos console, which is 1 as registered, so that the balancers follow the adjustment,
+// and 0 drains the provider.
+func ScaleWeights(params url.Values, weight float64) {
+	if weight == 1 {
+		return
+	}
+	if len(params.Get(constant.WEIGHT_KEY)) == 0 {
+		params.Set(constant.WEIGHT_KEY, strconv.Itoa(constant.DEFAULT_WEIGHT))
+	}
+	for k, v := range params {
+		if k != constant.WEIGHT_KEY &&
+			!(strings.HasPrefix(k, constant.METHOD_KEYS+".") && strings.HasSuffix(k, "."+constant.WEIGHT_KEY)) {
+			continue
+		}
+		w, err := strconv.ParseInt(v[0], 10, 64)
+		if err != nil {
+			logger.Warnf("The weight %s=%s of the instance is invalid, error: %v", k, v[0], err)
+			continue
+		}
+		params.Set(k, strconv.FormatInt(int64(math.Round(float64(w)*weight)), 10))
+	}
+}
+
 // GetEndPoints get end points from metadata
 func (d *DefaultServiceInstance) GetEndPoints() []*Endpoint {
 	rawEndpoints := d.Metadata[constant.SERVICE_INSTANCE_ENDPOINTS]
@@ -64,6 +64,7 @@ type serviceDiscoveryRegistry struct {
 	subscribedURLsSynthesizers       []synthesizer.SubscribedURLsSynthesizer
 	serviceRevisionExportedURLsCache map[string]map[string][]*common.URL
 	serviceListeners                 map[string]registry.ServiceInstancesChangedListener
+	wildcardSubscriptions            map[string]*wildcardSubscription
 }
 
 func newServiceDiscoveryRegistry(url *common.URL) (registry.Registry, error) {
@@ -88,6 +89,7 @@ func newServiceDiscoveryRegistry(url *common.URL) (registry.Registry, error) {
 		serviceNameMapping:               serviceNameMapping,
 		metaDataService:                  metaDataService,
 		serviceListeners:                 make(map[string]registry.ServiceInstancesChangedListener),
+		wildcardSubscriptions:            make(map[string]*wildcardSubscription),
 	}, nil
 }
 
@@ -102,6 +104,15 @@ func (s *serviceDiscoveryRegistry) UnSubscribe(url *common.URL, listener registr
 	if !shouldSubscribe(url) {
 		return nil
 	}
+	if isWildcardSubscription(url) {
+		s.lock.Lock()
+		if subscription, ok := s.wildcardSubscriptions[url.Key()]; ok {
+			subscription.stop()
+			delete(s.wildcardSubscriptions, url.Key())
+		}
+		s.lock.Unlock()
+		return nil
+	}
 	err := s.metaDataService.UnsubscribeURL(url)
 	if err != nil {
 		return err
@@ -156,6 +167,12 @@ func (s *serviceDiscoveryRegistry) IsAvailable() bool {
 }
 
 func (s *serviceDiscoveryRegistry) Destroy() {
+	s.lock.Lock()
+	for key, subscription := range s.wildcardSubscriptions {
+		subscription.stop()
+		delete(s.wildcardSubscriptions, key)
+	}
+	s.lock.Unlock()
 	err := s.serviceDiscovery.Destroy()
 	if err != nil {
 		logger.Errorf("destroy serviceDiscovery catch error:%s", err.Error())
@@ -192,9 +209,16 @@ func (s *serviceDiscoveryRegistry) Subscribe(url *common.URL, notify registry.No
 	if !shouldSubscribe(url) {
 		return nil
 	}
-	_, err := s.metaDataService.SubscribeURL(url)
-	if err != nil {
-		return perrors.WithMessage(err, "subscribe url error: "+url.String())
+	if isWildcardSubscription(url) {
+		s.subscribeWildcard(url, notify)
+		return nil
+	}
+	var err error
+	// the subscribed urls of observer consumer are not published by the metadata service
+	if !url.GetParamBool(constant.OBSERVER_KEY, false) {
+		if _, err = s.metaDataService.SubscribeURL(url); err != nil {
+			return perrors.WithMessage(err, "subscribe url error: "+url.String())
+		}
 	}
 	services := s.getServices(url)
 	if services.Empty() {
@@ -229,6 +253,18 @@ func (s *serviceDiscoveryRegistry) Subscribe(url *common.URL, notify registry.No
 	return nil
 }
 
+// subscribeWildcard notifies the providers of all the services matching the patterns of @url to @notify
+func (s *serviceDiscoveryRegistry) subscribeWildcard(url *common.URL, notify registry.NotifyListener) {
+	subscription := newWildcardSubscription(url, notify, s.serviceDiscovery)
+	s.lock.Lock()
+	if old, ok := s.wildcardSubscriptions[url.Key()]; ok {
+		old.stop()
+	}
+	s.wildcardSubscriptions[url.Key()] = subscription
+	s.lock.Unlock()
+	subscription.start()
+}
+
 func getUrlKey(url *common.URL) string {
 	var bf bytes.Buffer
 	if len(url.Protocol) != 0 {
@@ -0,0 +1,73 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package servicediscovery
+
+import (
+	"sort"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/registry/event"
+)
+
+// ListServices returns the keys of the services exported by the instances of all the applications
+// in the service discovery, which are resolved by the metadata of the instances.
+func (s *serviceDiscoveryRegistry) ListServices() []string {
+	apps := s.serviceDiscovery.GetServices()
+	if apps == nil {
+		return []string{}
+	}
+	revisionToMetadata := make(map[string]*common.MetadataInfo)
+	keys := make(map[string]struct{})
+	for _, app := range apps.Values() {
+		for _, instance := range s.serviceDiscovery.GetInstances(app.(string)) {
+			if instance == nil || instance.GetMetadata() == nil {
+				continue
+			}
+			revision := instance.GetMetadata()[constant.EXPORTED_SERVICES_REVISION_PROPERTY_NAME]
+			if len(revision) == 0 || revision == "0" {
+				continue
+			}
+			metadataInfo, ok := revisionToMetadata[revision]
+			if !ok {
+				var err error
+				if metadataInfo, err = event.GetMetadataInfo(instance, revision); err != nil || metadataInfo == nil {
+					// the other instances of the revision are asked instead
+					logger.Warnf("[Service Lister] could not get the metadata of the instance %s: %v",
+						instance.GetAddress(), err)
+					continue
+				}
+				// the instances of the same revision export the same services
+				revisionToMetadata[revision] = metadataInfo
+			}
+			for _, serviceInfo := range metadataInfo.Services {
+				keys[serviceInfo.GetServiceKey()] = struct{}{}
+			}
+		}
+	}
+
+	services := make([]string, 0, len(keys))
+	for key := range keys {
+		services = append(services, key)
+	}
+	sort.Strings(services)
+	return services
+}
@@ -0,0 +1,103 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package servicediscovery
+
+import (
+	"errors"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/metadata/service"
+	"dubbo.apache.org/dubbo-go/v3/registry"
+)
+
+func TestServiceDiscoveryRegistryListServices(t *testing.T) {
+	metadataService := &mockRevisionMetadataService{metadata: make(map[string]*common.MetadataInfo)}
+	for _, s := range [][3]string{
+		{"app-a", "com.demo.Order", "order"},
+		{"app-b", "com.demo.User", "business"},
+		{"app-c", "com.demo.Stock", ""},
+	} {
+		revision := s[0] + "-" + s[1] + "-" + s[2]
+		serviceInfo := common.NewServiceInfo(s[1], s[2], "1.0.0", "dubbo", s[1],
+			map[string]string{constant.GROUP_KEY: s[2], constant.VERSION_KEY: "1.0.0"})
+		metadataService.metadata[revision] = common.NewMetadataInfo(s[0], revision,
+			map[string]*common.ServiceInfo{serviceInfo.GetMatchKey(): serviceInfo})
+	}
+	extension.SetRemoteMetadataService(func() (service.RemoteMetadataService, error) {
+		return metadataService, nil
+	})
+
+	discovery := &mockWildcardServiceDiscovery{instances: make(map[string][]registry.ServiceInstance)}
+	discovery.setInstances("app-a", newWildcardInstance("app-a", "192.168.0.1", "com.demo.Order", "order"),
+		newWildcardInstance("app-a", "192.168.0.2", "com.demo.Order", "order"))
+	discovery.setInstances("app-b", newWildcardInstance("app-b", "192.168.0.3", "com.demo.User", "business"))
+	var reg registry.Registry = &serviceDiscoveryRegistry{serviceDiscovery: discovery}
+	lister, ok := reg.(registry.ServiceLister)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"business/com.demo.User:1.0.0", "order/com.demo.Order:1.0.0"}, lister.ListServices())
+
+	// the services added and removed later are reflected
+	discovery.setInstances("app-c", newWildcardInstance("app-c", "192.168.0.4", "com.demo.Stock", ""))
+	discovery.setInstances("app-a")
+	assert.Equal(t, []string{"business/com.demo.User:1.0.0", "com.demo.Stock:1.0.0"}, lister.ListServices())
+}
+
+// failingHostMetadataService fails to get the metadata of the instances on the host
+type failingHostMetadataService struct {
+	*mockRevisionMetadataService
+	host string
+}
+
+func (m *failingHostMetadataService) GetMetadata(instance registry.ServiceInstance) (*common.MetadataInfo, error) {
+	if instance.GetHost() == m.host {
+		return nil, errors.New("metadata service is unreachable")
+	}
+	return m.mockRevisionMetadataService.GetMetadata(instance)
+}
+
+func TestServiceDiscoveryRegistryListServicesMetadataFailure(t *testing.T) {
+	revision := "app-d-com.demo.Order-order"
+	serviceInfo := common.NewServiceInfo("com.demo.Order", "order", "1.0.0", "dubbo", "com.demo.Order",
+		map[string]string{constant.GROUP_KEY: "order", constant.VERSION_KEY: "1.0.0"})
+	metadataService := &failingHostMetadataService{
+		mockRevisionMetadataService: &mockRevisionMetadataService{metadata: map[string]*common.MetadataInfo{
+			revision: common.NewMetadataInfo("app-d", revision,
+				map[string]*common.ServiceInfo{serviceInfo.GetMatchKey(): serviceInfo}),
+		}},
+		host: "192.168.0.1",
+	}
+	extension.SetRemoteMetadataService(func() (service.RemoteMetadataService, error) {
+		return metadataService, nil
+	})
+
+	// the failure of an instance isn't cached for the other instances of the revision
+	discovery := &mockWildcardServiceDiscovery{instances: make(map[string][]registry.ServiceInstance)}
+	discovery.setInstances("app-d", newWildcardInstance("app-d", "192.168.0.1", "com.demo.Order", "order"),
+		newWildcardInstance("app-d", "192.168.0.2", "com.demo.Order", "order"))
+	reg := &serviceDiscoveryRegistry{serviceDiscovery: discovery}
+	assert.Equal(t, []string{"order/com.demo.Order:1.0.0"}, reg.ListServices())
+}
@@ -0,0 +1,352 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package servicediscovery
+
+import (
+	"path"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+import (
+	gxset "github.com/dubbogo/gost/container/set"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/common/observer"
+	"dubbo.apache.org/dubbo-go/v3/registry"
+	"dubbo.apache.org/dubbo-go/v3/registry/event"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+// isWildcardSubscription checks whether the interface, group or version of the subscribed @url is a pattern
+func isWildcardSubscription(url *common.URL) bool {
+	return strings.Contains(url.Service(), constant.ANY_VALUE) ||
+		strings.Contains(url.GetParam(constant.GROUP_KEY, ""), constant.ANY_VALUE) ||
+		strings.Contains(url.GetParam(constant.VERSION_KEY, ""), constant.ANY_VALUE)
+}
+
+// wildcardSubscription tracks the providers of all the services matching the interface, group and version
+// patterns of the subscribed url, e.g. group=monitoring-*, among all the applications in the service discovery.
+// The applications are listed again every wildcard.refresh.interval, so the services of the new applications
+// are matched as well.
+type wildcardSubscription struct {
+	interfacePattern string
+	groupPattern     string
+	versionPattern   string
+	refreshInterval  time.Duration
+	notify           registry.NotifyListener
+	serviceDiscovery registry.ServiceDiscovery
+
+	lock               sync.Mutex
+	allInstances       map[string][]registry.ServiceInstance
+	revisionToMetadata map[string]*common.MetadataInfo
+	// the listeners added to the service discovery, application <--> listener
+	listeners map[string]*wildcardAppListener
+	// evaluating is set while a goroutine evaluates the instances, and dirty tells it to evaluate them again
+	// since they have changed meanwhile
+	evaluating bool
+	dirty      bool
+	// the matched provider urls notified, url key <--> url, it's only accessed by the evaluating goroutine
+	urls map[string]*common.URL
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newWildcardSubscription(url *common.URL, notify registry.NotifyListener,
+	serviceDiscovery registry.ServiceDiscovery) *wildcardSubscription {
+	return &wildcardSubscription{
+		interfacePattern:   patternOrAny(url.Service()),
+		groupPattern:       patternOrAny(url.GetParam(constant.GROUP_KEY, "")),
+		versionPattern:     patternOrAny(url.GetParam(constant.VERSION_KEY, "")),
+		refreshInterval:    url.GetParamDuration(constant.WILDCARD_REFRESH_INTERVAL_KEY, constant.DEFAULT_WILDCARD_REFRESH),
+		notify:             notify,
+		serviceDiscovery:   serviceDiscovery,
+		allInstances:       make(map[string][]registry.ServiceInstance),
+		revisionToMetadata: make(map[string]*common.MetadataInfo),
+		listeners:          make(map[string]*wildcardAppListener),
+		urls:               make(map[string]*common.URL),
+		done:               make(chan struct{}),
+	}
+}
+
+// patternOrAny treats the absent interface, group or version as the one matching anything
+func patternOrAny(pattern string) string {
+	if len(pattern) == 0 {
+		return constant.ANY_VALUE
+	}
+	return pattern
+}
+
+// start matches the services of the current applications and keeps tracking the new ones
+func (w *wildcardSubscription) start() {
+	w.refresh()
+	go func() {
+		ticker := time.NewTicker(w.refreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				w.refresh()
+			case <-w.done:
+				return
+			}
+		}
+	}()
+}
+
+// stop stops tracking the applications and removes the listeners, the notified urls aren't withdrawn
+func (w *wildcardSubscription) stop() {
+	w.closeOnce.Do(func() {
+		close(w.done)
+		w.lock.Lock()
+		listeners := w.listeners
+		w.listeners = make(map[string]*wildcardAppListener)
+		w.lock.Unlock()
+		for _, listener := range listeners {
+			w.removeListener(listener)
+		}
+	})
+}
+
+func (w *wildcardSubscription) removeListener(listener *wildcardAppListener) {
+	if err := w.serviceDiscovery.RemoveListener(listener); err != nil {
+		logger.Warnf("[Wildcard Subscription] could not remove the listener of the application %s: %v",
+			listener.app, err)
+	}
+}
+
+func (w *wildcardSubscription) stopped() bool {
+	select {
+	case <-w.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// refresh listens to the new applications and forgets the ones which have gone
+func (w *wildcardSubscription) refresh() {
+	apps := w.serviceDiscovery.GetServices()
+	if apps == nil {
+		apps = gxset.NewSet()
+	}
+	var newApps []string
+	w.lock.Lock()
+	for _, v := range apps.Values() {
+		if app := v.(string); !w.listened(app) {
+			newApps = append(newApps, app)
+			w.allInstances[app] = nil
+		}
+	}
+	var gone []*wildcardAppListener
+	for app := range w.allInstances {
+		if !apps.Contains(app) {
+			delete(w.allInstances, app)
+			if listener, ok := w.listeners[app]; ok {
+				delete(w.listeners, app)
+				gone = append(gone, listener)
+			}
+		}
+	}
+	w.lock.Unlock()
+
+	for _, listener := range gone {
+		w.removeListener(listener)
+	}
+	if len(gone) > 0 {
+		w.evaluate()
+	}
+	for _, app := range newApps {
+		w.onInstances(app, w.serviceDiscovery.GetInstances(app))
+		listener := &wildcardAppListener{app: app, subscription: w}
+		if err := w.serviceDiscovery.AddListener(listener); err != nil {
+			logger.Errorf("[Wildcard Subscription] could not listen to the application %s: %v", app, err)
+			continue
+		}
+		w.lock.Lock()
+		if w.listened(app) && !w.stopped() {
+			w.listeners[app] = listener
+			listener = nil
+		}
+		w.lock.Unlock()
+		// the application has gone or the subscription has stopped meanwhile
+		if listener != nil {
+			w.removeListener(listener)
+		}
+	}
+}
+
+// listened must be called with the lock held
+func (w *wildcardSubscription) listened(app string) bool {
+	_, ok := w.allInstances[app]
+	return ok
+}
+
+// onInstances updates the instances of the @app and notifies the changes of the matched urls
+func (w *wildcardSubscription) onInstances(app string, instances []registry.ServiceInstance) {
+	if w.stopped() {
+		return
+	}
+	w.lock.Lock()
+	if !w.listened(app) {
+		w.lock.Unlock()
+		return
+	}
+	w.allInstances[app] = instances
+	w.lock.Unlock()
+	w.evaluate()
+}
+
+// evaluate matches the urls of all the instances and notifies the difference from the last time.
+// The metadata is fetched without the lock held, and the instances changed meanwhile are evaluated again
+// by the goroutine evaluating them instead of the ones changing them.
+func (w *wildcardSubscription) evaluate() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.dirty = true
+	if w.evaluating {
+		return
+	}
+	w.evaluating = true
+	for w.dirty && !w.stopped() {
+		w.dirty = false
+		allInstances := make([][]registry.ServiceInstance, 0, len(w.allInstances))
+		for _, instances := range w.allInstances {
+			allInstances = append(allInstances, instances)
+		}
+		cached := w.revisionToMetadata
+		w.lock.Unlock()
+		revisionToMetadata := w.match(allInstances, cached)
+		w.lock.Lock()
+		w.revisionToMetadata = revisionToMetadata
+	}
+	w.evaluating = false
+}
+
+// match matches the urls of @allInstances, whose metadata is fetched unless it's @cached by the revision,
+// and notifies the difference from the last time. It returns the metadata of the current revisions.
+func (w *wildcardSubscription) match(allInstances [][]registry.ServiceInstance,
+	cached map[string]*common.MetadataInfo) map[string]*common.MetadataInfo {
+	revisionToMetadata := make(map[string]*common.MetadataInfo)
+	urls := make(map[string]*common.URL)
+	for _, instances := range allInstances {
+		for _, instance := range instances {
+			if instance == nil || instance.GetMetadata() == nil {
+				continue
+			}
+			revision := instance.GetMetadata()[constant.EXPORTED_SERVICES_REVISION_PROPERTY_NAME]
+			if len(revision) == 0 || revision == "0" {
+				continue
+			}
+			metadataInfo := revisionToMetadata[revision]
+			if metadataInfo == nil {
+				metadataInfo = cached[revision]
+			}
+			if metadataInfo == nil {
+				var err error
+				if metadataInfo, err = event.GetMetadataInfo(instance, revision); err != nil {
+					logger.Warnf("[Wildcard Subscription] could not get the metadata of the instance %s: %v",
+						instance.GetAddress(), err)
+					continue
+				}
+			}
+			revisionToMetadata[revision] = metadataInfo
+			instance.SetServiceMetadata(metadataInfo)
+			for _, url := range instance.ToURLs() {
+				if w.matchURL(url) {
+					urls[url.Key()] = url
+				}
+			}
+		}
+	}
+
+	for key, url := range w.urls {
+		if _, ok := urls[key]; !ok {
+			w.notify.Notify(&registry.ServiceEvent{Action: remoting.EventTypeDel, Service: url})
+		}
+	}
+	for key, url := range urls {
+		if _, ok := w.urls[key]; !ok {
+			w.notify.Notify(&registry.ServiceEvent{Action: remoting.EventTypeAdd, Service: url})
+		}
+	}
+	w.urls = urls
+	return revisionToMetadata
+}
+
+func (w *wildcardSubscription) matchURL(url *common.URL) bool {
+	return matchPattern(w.interfacePattern, url.Service()) &&
+		matchPattern(w.groupPattern, url.GetParam(constant.GROUP_KEY, "")) &&
+		matchPattern(w.versionPattern, url.GetParam(constant.VERSION_KEY, ""))
+}
+
+func matchPattern(pattern, value string) bool {
+	if pattern == constant.ANY_VALUE {
+		return true
+	}
+	matched, err := path.Match(pattern, value)
+	return err == nil && matched
+}
+
+// wildcardAppListener passes the instance changes of the application to the wildcard subscription
+type wildcardAppListener struct {
+	app          string
+	subscription *wildcardSubscription
+}
+
+// OnEvent updates the instances of the application
+func (l *wildcardAppListener) OnEvent(e observer.Event) error {
+	if ce, ok := e.(*registry.ServiceInstancesChangedEvent); ok && ce.ServiceName == l.app {
+		l.subscription.onInstances(l.app, ce.Instances)
+	}
+	return nil
+}
+
+// AddListenerAndNotify does nothing, the matched urls are notified to the listener of the subscription
+func (l *wildcardAppListener) AddListenerAndNotify(string, registry.NotifyListener) {}
+
+// RemoveListener does nothing
+func (l *wildcardAppListener) RemoveListener(string) {}
+
+// GetServiceNames returns the application
+func (l *wildcardAppListener) GetServiceNames() *gxset.HashSet {
+	return gxset.NewSet(l.app)
+}
+
+// Accept returns true if the event is of the application
+func (l *wildcardAppListener) Accept(e observer.Event) bool {
+	ce, ok := e.(*registry.ServiceInstancesChangedEvent)
+	return ok && ce.ServiceName == l.app
+}
+
+// GetEventType returns ServiceInstancesChangedEvent
+func (l *wildcardAppListener) GetEventType() reflect.Type {
+	return reflect.TypeOf(&registry.ServiceInstancesChangedEvent{})
+}
+
+// GetPriority returns -1, it will be the first invoked listener
+func (l *wildcardAppListener) GetPriority() int {
+	return -1
+}
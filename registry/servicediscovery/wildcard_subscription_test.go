@@ -0,0 +1,280 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package servicediscovery
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	gxset "github.com/dubbogo/gost/container/set"
+
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/metadata/service"
+	"dubbo.apache.org/dubbo-go/v3/registry"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+// mockWildcardServiceDiscovery keeps the instances of the applications in memory
+type mockWildcardServiceDiscovery struct {
+	registry.ServiceDiscovery
+	lock      sync.Mutex
+	instances map[string][]registry.ServiceInstance
+	listeners []registry.ServiceInstancesChangedListener
+}
+
+func (m *mockWildcardServiceDiscovery) GetServices() *gxset.HashSet {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	apps := gxset.NewSet()
+	for app := range m.instances {
+		apps.Add(app)
+	}
+	return apps
+}
+
+func (m *mockWildcardServiceDiscovery) GetInstances(app string) []registry.ServiceInstance {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.instances[app]
+}
+
+func (m *mockWildcardServiceDiscovery) AddListener(listener registry.ServiceInstancesChangedListener) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.listeners = append(m.listeners, listener)
+	return nil
+}
+
+func (m *mockWildcardServiceDiscovery) RemoveListener(listener registry.ServiceInstancesChangedListener) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for i, l := range m.listeners {
+		if l == listener {
+			m.listeners = append(m.listeners[:i], m.listeners[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (m *mockWildcardServiceDiscovery) listenerCount() int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return len(m.listeners)
+}
+
+func (m *mockWildcardServiceDiscovery) setInstances(app string, instances ...registry.ServiceInstance) {
+	m.lock.Lock()
+	m.instances[app] = instances
+	listeners := append([]registry.ServiceInstancesChangedListener(nil), m.listeners...)
+	m.lock.Unlock()
+	e := &registry.ServiceInstancesChangedEvent{ServiceName: app, Instances: instances}
+	for _, listener := range listeners {
+		if listener.Accept(e) {
+			_ = listener.OnEvent(e)
+		}
+	}
+}
+
+// mockRevisionMetadataService returns the metadata info by the revision of the instance
+type mockRevisionMetadataService struct {
+	metadata map[string]*common.MetadataInfo
+}
+
+func (m *mockRevisionMetadataService) PublishMetadata(string) {}
+
+func (m *mockRevisionMetadataService) GetMetadata(instance registry.ServiceInstance) (*common.MetadataInfo, error) {
+	if info, ok := m.metadata[instance.GetMetadata()[constant.EXPORTED_SERVICES_REVISION_PROPERTY_NAME]]; ok {
+		return info, nil
+	}
+	return nil, errors.New("metadata not found")
+}
+
+func (m *mockRevisionMetadataService) PublishServiceDefinition(*common.URL) error {
+	return nil
+}
+
+// mockWildcardNotifyListener keeps the notified providers
+type mockWildcardNotifyListener struct {
+	lock      sync.Mutex
+	providers map[string]*common.URL
+}
+
+func (l *mockWildcardNotifyListener) Notify(e *registry.ServiceEvent) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if e.Action == remoting.EventTypeDel {
+		delete(l.providers, e.Service.Key())
+		return
+	}
+	l.providers[e.Service.Key()] = e.Service
+}
+
+func (l *mockWildcardNotifyListener) NotifyAll([]*registry.ServiceEvent, func()) {}
+
+func (l *mockWildcardNotifyListener) services() []string {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	services := make([]string, 0, len(l.providers))
+	for _, url := range l.providers {
+		services = append(services, url.Service()+":"+url.GetParam(constant.GROUP_KEY, ""))
+	}
+	sort.Strings(services)
+	return services
+}
+
+func newWildcardInstance(app, host, interfaceName, group string) registry.ServiceInstance {
+	return &registry.DefaultServiceInstance{
+		ID:          host + ":20000",
+		ServiceName: app,
+		Host:        host,
+		Port:        20000,
+		Enable:      true,
+		Healthy:     true,
+		Metadata: map[string]string{
+			constant.EXPORTED_SERVICES_REVISION_PROPERTY_NAME: app + "-" + interfaceName + "-" + group,
+			constant.METADATA_STORAGE_TYPE_PROPERTY_NAME:      constant.REMOTE_METADATA_STORAGE_TYPE,
+		},
+	}
+}
+
+func TestWildcardSubscription(t *testing.T) {
+	metadataService := &mockRevisionMetadataService{metadata: make(map[string]*common.MetadataInfo)}
+	for _, s := range [][3]string{
+		{"app-a", "com.demo.Metrics", "monitoring-a"},
+		{"app-b", "com.demo.User", "business"},
+		{"app-c", "com.demo.Trace", "monitoring-c"},
+	} {
+		revision := s[0] + "-" + s[1] + "-" + s[2]
+		serviceInfo := common.NewServiceInfo(s[1], s[2], "1.0.0", "dubbo", s[1],
+			map[string]string{constant.GROUP_KEY: s[2], constant.VERSION_KEY: "1.0.0"})
+		metadataService.metadata[revision] = common.NewMetadataInfo(s[0], revision,
+			map[string]*common.ServiceInfo{serviceInfo.GetMatchKey(): serviceInfo})
+	}
+	extension.SetRemoteMetadataService(func() (service.RemoteMetadataService, error) {
+		return metadataService, nil
+	})
+
+	discovery := &mockWildcardServiceDiscovery{instances: make(map[string][]registry.ServiceInstance)}
+	discovery.setInstances("app-a", newWildcardInstance("app-a", "192.168.0.1", "com.demo.Metrics", "monitoring-a"))
+	discovery.setInstances("app-b", newWildcardInstance("app-b", "192.168.0.2", "com.demo.User", "business"))
+	reg := &serviceDiscoveryRegistry{
+		serviceDiscovery:      discovery,
+		wildcardSubscriptions: make(map[string]*wildcardSubscription),
+	}
+
+	url, _ := common.NewURL("dubbo://127.0.0.1/*",
+		common.WithParamsValue(constant.INTERFACE_KEY, constant.ANY_VALUE),
+		common.WithParamsValue(constant.GROUP_KEY, "monitoring-*"),
+		common.WithParamsValue(constant.SIDE_KEY, constant.CONSUMER),
+		common.WithParamsValue(constant.WILDCARD_REFRESH_INTERVAL_KEY, "50ms"))
+	listener := &mockWildcardNotifyListener{providers: make(map[string]*common.URL)}
+	assert.NoError(t, reg.Subscribe(url, listener))
+	assert.Equal(t, []string{"com.demo.Metrics:monitoring-a"}, listener.services())
+	assert.Equal(t, 2, discovery.listenerCount())
+
+	// the services of the new application are matched once it shows up
+	discovery.setInstances("app-c", newWildcardInstance("app-c", "192.168.0.3", "com.demo.Trace", "monitoring-c"))
+	assert.Eventually(t, func() bool {
+		return len(listener.services()) == 2
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"com.demo.Metrics:monitoring-a", "com.demo.Trace:monitoring-c"}, listener.services())
+
+	// the providers going away are withdrawn
+	discovery.setInstances("app-a")
+	assert.Eventually(t, func() bool {
+		return len(listener.services()) == 1
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"com.demo.Trace:monitoring-c"}, listener.services())
+	discovery.setInstances("app-b", newWildcardInstance("app-b", "192.168.0.4", "com.demo.User", "business"))
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, []string{"com.demo.Trace:monitoring-c"}, listener.services())
+
+	// the listeners of the applications are removed once it's unsubscribed
+	assert.Equal(t, 3, discovery.listenerCount())
+	assert.NoError(t, reg.UnSubscribe(url, listener))
+	assert.Equal(t, 0, discovery.listenerCount())
+}
+
+// blockingMetadataService blocks getting the metadata until it's released
+type blockingMetadataService struct {
+	mockRevisionMetadataService
+	blocked chan struct{}
+	release chan struct{}
+}
+
+func (m *blockingMetadataService) GetMetadata(instance registry.ServiceInstance) (*common.MetadataInfo, error) {
+	m.blocked <- struct{}{}
+	<-m.release
+	return m.mockRevisionMetadataService.GetMetadata(instance)
+}
+
+func TestWildcardSubscriptionEvaluateWithoutLock(t *testing.T) {
+	revision := "app-a-com.demo.Metrics-monitoring-a"
+	serviceInfo := common.NewServiceInfo("com.demo.Metrics", "monitoring-a", "1.0.0", "dubbo", "com.demo.Metrics",
+		map[string]string{constant.GROUP_KEY: "monitoring-a", constant.VERSION_KEY: "1.0.0"})
+	metadataService := &blockingMetadataService{
+		mockRevisionMetadataService: mockRevisionMetadataService{metadata: map[string]*common.MetadataInfo{
+			revision: common.NewMetadataInfo("app-a", revision,
+				map[string]*common.ServiceInfo{serviceInfo.GetMatchKey(): serviceInfo}),
+		}},
+		blocked: make(chan struct{}, 1),
+		release: make(chan struct{}),
+	}
+	extension.SetRemoteMetadataService(func() (service.RemoteMetadataService, error) {
+		return metadataService, nil
+	})
+
+	discovery := &mockWildcardServiceDiscovery{instances: make(map[string][]registry.ServiceInstance)}
+	discovery.setInstances("app-a")
+	url, _ := common.NewURL("dubbo://127.0.0.1/*?group=monitoring-*")
+	listener := &mockWildcardNotifyListener{providers: make(map[string]*common.URL)}
+	subscription := newWildcardSubscription(url, listener, discovery)
+	subscription.start()
+	defer subscription.stop()
+
+	go discovery.setInstances("app-a", newWildcardInstance("app-a", "192.168.0.1", "com.demo.Metrics", "monitoring-a"))
+	<-metadataService.blocked
+	// the changes are accepted while the metadata is being fetched, and they're evaluated right after
+	discovery.setInstances("app-a", newWildcardInstance("app-a", "192.168.0.1", "com.demo.Metrics", "monitoring-a"),
+		newWildcardInstance("app-a", "192.168.0.2", "com.demo.Metrics", "monitoring-a"))
+	close(metadataService.release)
+	assert.Eventually(t, func() bool {
+		listener.lock.Lock()
+		defer listener.lock.Unlock()
+		return len(listener.providers) == 2
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestIsWildcardSubscription(t *testing.T) {
+	url, _ := common.NewURL("dubbo://127.0.0.1/com.demo.User?group=monitoring-*")
+	assert.True(t, isWildcardSubscription(url))
+	url, _ = common.NewURL("dubbo://127.0.0.1/com.demo.User?group=monitoring&version=1.0.0")
+	assert.False(t, isWildcardSubscription(url))
+}
@@ -75,6 +75,7 @@ func newZkRegistry(url *common.URL) (registry.Registry, error) {
 	if err != nil {
 		return nil, err
 	}
+	r.Health().SetConnected(true)
 
 	r.WaitGroup().Add(1)
 	go zookeeper.HandleClientRestart(r)
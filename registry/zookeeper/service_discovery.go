@@ -270,6 +270,19 @@ func (zksd *zookeeperServiceDiscovery) AddListener(listener registry.ServiceInst
 	return nil
 }
 
+// RemoveListener removes the listener from the services it listens to
+func (zksd *zookeeperServiceDiscovery) RemoveListener(listener registry.ServiceInstancesChangedListener) error {
+	zksd.listenLock.Lock()
+	defer zksd.listenLock.Unlock()
+
+	for _, t := range listener.GetServiceNames().Values() {
+		if listenerSet, found := zksd.instanceListenerMap[t.(string)]; found {
+			listenerSet.Remove(listener)
+		}
+	}
+	return nil
+}
+
 // DataChange implement DataListener's DataChange function
 // to resolve event to do DispatchEventByServiceName
 func (zksd *zookeeperServiceDiscovery) DataChange(eventType remoting.Event) bool {
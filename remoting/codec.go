@@ -20,6 +20,10 @@ import (
 	"bytes"
 )
 
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+)
+
 // codec for exchangeClient
 type Codec interface {
 	EncodeRequest(request *Request) (*bytes.Buffer, error)
@@ -27,6 +31,13 @@ type Codec interface {
 	Decode(data []byte) (DecodeResult, int, error)
 }
 
+// URLCodec is implemented by the codecs configured by the url of the server or the client using them
+type URLCodec interface {
+	Codec
+	// ForURL returns the codec configured by @url
+	ForURL(url *common.URL) Codec
+}
+
 type DecodeResult struct {
 	IsRequest bool
 	Result    interface{}
@@ -41,3 +52,12 @@ func RegistryCodec(protocol string, codecTmp Codec) {
 func GetCodec(protocol string) Codec {
 	return codec[protocol]
 }
+
+// GetCodecByURL returns the codec of the protocol of @url, which is configured by @url if it's a URLCodec
+func GetCodecByURL(url *common.URL) Codec {
+	c := GetCodec(url.Protocol)
+	if urlCodec, ok := c.(URLCodec); ok {
+		return urlCodec.ForURL(url)
+	}
+	return c
+}
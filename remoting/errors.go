@@ -0,0 +1,78 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remoting
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const serverBusyRetryAfter = "retry after "
+
+// ServerBusyError is returned once the dispatch queue of the server is full, and the client is supposed to
+// fail over to the other servers, or retry the server after RetryAfter.
+type ServerBusyError struct {
+	RetryAfter time.Duration
+}
+
+// NewServerBusyError creates the ServerBusyError with the retry-after hint @retryAfter
+func NewServerBusyError(retryAfter time.Duration) *ServerBusyError {
+	return &ServerBusyError{RetryAfter: retryAfter}
+}
+
+// ParseServerBusyError restores the ServerBusyError from its message received from the server,
+// and the retry-after hint is zero if the message doesn't carry it.
+func ParseServerBusyError(message string) *ServerBusyError {
+	err := &ServerBusyError{}
+	if i := strings.LastIndex(message, serverBusyRetryAfter); i >= 0 {
+		err.RetryAfter, _ = time.ParseDuration(strings.TrimSpace(message[i+len(serverBusyRetryAfter):]))
+	}
+	return err
+}
+
+func (e *ServerBusyError) Error() string {
+	return fmt.Sprintf("the server is busy, %s%s", serverBusyRetryAfter, e.RetryAfter)
+}
+
+const serverTimeoutAfter = "timed out after "
+
+// ServerTimeoutError is returned once the provider doesn't complete the request within the server timeout,
+// the server abandons the result of the invocation and replies it to release the dispatch slot.
+type ServerTimeoutError struct {
+	Timeout time.Duration
+}
+
+// NewServerTimeoutError creates the ServerTimeoutError of the server timeout @timeout
+func NewServerTimeoutError(timeout time.Duration) *ServerTimeoutError {
+	return &ServerTimeoutError{Timeout: timeout}
+}
+
+// ParseServerTimeoutError restores the ServerTimeoutError from its message received from the server,
+// and the timeout is zero if the message doesn't carry it.
+func ParseServerTimeoutError(message string) *ServerTimeoutError {
+	err := &ServerTimeoutError{}
+	if i := strings.LastIndex(message, serverTimeoutAfter); i >= 0 {
+		err.Timeout, _ = time.ParseDuration(strings.TrimSpace(message[i+len(serverTimeoutAfter):]))
+	}
+	return err
+}
+
+func (e *ServerTimeoutError) Error() string {
+	return fmt.Sprintf("the server %s%s", serverTimeoutAfter, e.Timeout)
+}
@@ -29,6 +29,7 @@ import (
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
 )
 
 type clientFacade interface {
@@ -41,14 +42,25 @@ type clientFacade interface {
 	common.Node
 }
 
+// healthFacade is implemented by the facades reporting the health of their connections
+type healthFacade interface {
+	Health() *metrics.ConnectionHealth
+}
+
 // HandleClientRestart keeps the connection between client and server
 // This method should be used only once. You can use handleClientRestart() in package registry.
+// The connected state is reported to the health of @r if it's a healthFacade.
 func HandleClientRestart(r clientFacade) {
 	defer r.WaitGroup().Done()
+	var health *metrics.ConnectionHealth
+	if hf, ok := r.(healthFacade); ok {
+		health = hf.Health()
+	}
 	for {
 		select {
 		case <-r.Client().GetCtx().Done():
-			r.RestartCallBack()
+			health.SetConnected(false)
+			health.SetConnected(r.RestartCallBack())
 			// re-register all services
 			time.Sleep(10 * time.Microsecond)
 		case <-r.Done():
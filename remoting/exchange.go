@@ -58,10 +58,15 @@ type Request struct {
 	Version string
 	// serial ID (ignore)
 	SerialID byte
+	// CodecOptions are the options of the codec which decoded the request, which encodes the response with them
+	CodecOptions interface{}
 	// Data
 	Data   interface{}
 	TwoWay bool
 	Event  bool
+	// ReadTimeout bounds the wait for the response of the two way request once it's sent if it's positive,
+	// while the timeout of the request still bounds the whole request
+	ReadTimeout time.Duration
 }
 
 // NewRequest aims to create Request.
@@ -82,6 +87,8 @@ type Response struct {
 	Event    bool
 	Error    error
 	Result   interface{}
+	// CodecOptions are the options of the codec encoding the response, which are the ones of the request
+	CodecOptions interface{}
 }
 
 // NewResponse create to a new Response.
@@ -105,6 +112,7 @@ func (response *Response) Handle() {
 	}
 
 	pendingResponse.response = response
+	pendingResponse.stopTimer()
 
 	if pendingResponse.Callback == nil {
 		pendingResponse.Err = pendingResponse.response.Error
@@ -112,6 +120,7 @@ func (response *Response) Handle() {
 	} else {
 		pendingResponse.Callback(pendingResponse.GetCallResponse())
 	}
+	pendingResponse.finished()
 }
 
 type Options struct {
@@ -138,7 +147,15 @@ type PendingResponse struct {
 	Callback  common.AsyncCallback
 	response  *Response
 	Reply     interface{}
-	Done      chan struct{}
+	// LenientCollection tells the codec to skip the elements of the replied collection which Reply can't hold
+	LenientCollection bool
+	// CodecOptions are the options of the codec decoding the response, which are the ones of the request
+	CodecOptions interface{}
+	Done         chan struct{}
+	// timer cancels the async response which isn't received in time, it's stopped once the response is received
+	timer *time.Timer
+	// finish is called once the response is received or cancelled
+	finish atomic.Value
 }
 
 // NewPendingResponse aims to create PendingResponse.
@@ -152,6 +169,24 @@ func NewPendingResponse(id int64) *PendingResponse {
 	}
 }
 
+func (r *PendingResponse) stopTimer() {
+	if r.timer != nil {
+		r.timer.Stop()
+	}
+}
+
+// OnFinish sets @finish called once the response is received or cancelled, e.g. to release the connection
+// the response is awaited on
+func (r *PendingResponse) OnFinish(finish func()) {
+	r.finish.Store(finish)
+}
+
+func (r *PendingResponse) finished() {
+	if finish, ok := r.finish.Load().(func()); ok {
+		finish()
+	}
+}
+
 func (r *PendingResponse) SetResponse(response *Response) {
 	r.response = response
 }
@@ -177,13 +212,29 @@ func removePendingResponse(seq SequenceType) *PendingResponse {
 	if pendingResponses == nil {
 		return nil
 	}
-	if presp, ok := pendingResponses.Load(seq); ok {
-		pendingResponses.Delete(seq)
+	if presp, ok := pendingResponses.LoadAndDelete(seq); ok {
 		return presp.(*PendingResponse)
 	}
 	return nil
 }
 
+// cancelPendingResponse finishes the pending response of @seq with @err, the callback of the async one is notified,
+// it returns false if the response has been received or cancelled
+func cancelPendingResponse(seq SequenceType, err error) bool {
+	pendingResponse := removePendingResponse(seq)
+	if pendingResponse == nil {
+		return false
+	}
+	pendingResponse.Err = err
+	if pendingResponse.Callback == nil {
+		close(pendingResponse.Done)
+	} else {
+		pendingResponse.Callback(pendingResponse.GetCallResponse())
+	}
+	pendingResponse.finished()
+	return true
+}
+
 // get response
 func GetPendingResponse(seq SequenceType) *PendingResponse {
 	if presp, ok := pendingResponses.Load(seq); ok {
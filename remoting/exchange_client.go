@@ -17,7 +17,11 @@
 package remoting
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"strconv"
+	"sync"
 	"time"
 )
 
@@ -27,10 +31,15 @@ import (
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/common/logger"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
+	invocation_impl "dubbo.apache.org/dubbo-go/v3/protocol/invocation"
 )
 
+// ErrAsyncRequestTimeout is the cause of the async response which isn't received in time
+var ErrAsyncRequestTimeout = errors.New("async request timeout")
+
 // It is interface of client for network communication.
 // If you use getty as network communication, you should define GettyClient that implements this interface.
 type Client interface {
@@ -53,8 +62,9 @@ type ExchangeClient struct {
 	address string
 	// the client that will deal with the transport. It is interface, and it will use gettyClient by default.
 	client Client
-	// the tag for init.
-	init bool
+	// the tag for init, which is set under initLock so that the client is connected once by the concurrent callers
+	init     uatomic.Bool
+	initLock sync.Mutex
 	// the number of service using the exchangeClient
 	activeNum uatomic.Uint32
 }
@@ -76,7 +86,12 @@ func NewExchangeClient(url *common.URL, client Client, connectTimeout time.Durat
 }
 
 func (cl *ExchangeClient) doInit(url *common.URL) error {
-	if cl.init {
+	if cl.init.Load() {
+		return nil
+	}
+	cl.initLock.Lock()
+	defer cl.initLock.Unlock()
+	if cl.init.Load() {
 		return nil
 	}
 	if cl.client.Connect(url) != nil {
@@ -87,11 +102,28 @@ func (cl *ExchangeClient) doInit(url *common.URL) error {
 			return errors.New("Failed to connect server " + url.Location)
 		}
 	}
-	// FIXME atomic operation
-	cl.init = true
+	cl.init.Store(true)
 	return nil
 }
 
+// Connect connects to the server if it isn't connected yet, otherwise it's connected by the first request.
+// It returns an error once @ctx is done, and the connecting in progress goes on for the requests.
+func (client *ExchangeClient) Connect(ctx context.Context, url *common.URL) error {
+	if client.init.Load() {
+		return nil
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- client.doInit(url)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("the connection to %s is not established: %w", url.Location, ctx.Err())
+	}
+}
+
 // increase number of service using client
 func (client *ExchangeClient) IncreaseActiveNumber() uint32 {
 	return client.activeNum.Add(1)
@@ -110,6 +142,13 @@ func (client *ExchangeClient) GetActiveNumber() uint32 {
 // two way request
 func (client *ExchangeClient) Request(invocation *protocol.Invocation, url *common.URL, timeout time.Duration,
 	result *protocol.RPCResult) error {
+	return client.RequestWithContext(context.Background(), invocation, url, timeout, result)
+}
+
+// RequestWithContext is a two way request which is aborted once @ctx is done,
+// the server is notified to cancel the invocation as well
+func (client *ExchangeClient) RequestWithContext(ctx context.Context, invocation *protocol.Invocation,
+	url *common.URL, timeout time.Duration, result *protocol.RPCResult) error {
 	if er := client.doInit(url); er != nil {
 		return er
 	}
@@ -117,12 +156,29 @@ func (client *ExchangeClient) Request(invocation *protocol.Invocation, url *comm
 	request.Data = invocation
 	request.Event = false
 	request.TwoWay = true
+	request.ReadTimeout = readTimeout(url, (*invocation).MethodName())
 
 	rsp := NewPendingResponse(request.ID)
 	rsp.response = NewResponse(request.ID, "2.0.2")
 	rsp.Reply = (*invocation).Reply()
+	rsp.LenientCollection = lenientCollection(url, (*invocation).MethodName())
+	rsp.CodecOptions = (*invocation).AttributeByKey(constant.CODEC_OPTIONS_KEY, nil)
 	AddPendingResponse(rsp)
 
+	if ctx.Done() != nil {
+		finished := make(chan struct{})
+		defer close(finished)
+		go func() {
+			select {
+			case <-ctx.Done():
+				if cancelPendingResponse(SequenceType(request.ID), ctx.Err()) {
+					client.sendCancel(*invocation, request.ID, url, timeout)
+				}
+			case <-finished:
+			}
+		}()
+	}
+
 	err := client.client.Request(request, timeout, rsp)
 	// request error
 	if err != nil {
@@ -137,6 +193,25 @@ func (client *ExchangeClient) Request(invocation *protocol.Invocation, url *comm
 	return nil
 }
 
+// readTimeout returns the read timeout of @method configured by @url, which is 0 if it isn't configured
+func readTimeout(url *common.URL, method string) time.Duration {
+	value := url.GetMethodParam(method, constant.READ_TIMEOUT_KEY, url.GetParam(constant.READ_TIMEOUT_KEY, ""))
+	if value == "" {
+		return 0
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		logger.Warnf("the read timeout %s of %s is invalid, error: %v", value, url.Key(), err)
+		return 0
+	}
+	return timeout
+}
+
+// lenientCollection tells whether the collection replied by @method is decoded leniently, which is configured by @url
+func lenientCollection(url *common.URL, method string) bool {
+	return url.GetMethodParamBool(method, constant.SERIALIZATION_LENIENT_COLLECTION_KEY, false)
+}
+
 // async two way request
 func (client *ExchangeClient) AsyncRequest(invocation *protocol.Invocation, url *common.URL, timeout time.Duration,
 	callback common.AsyncCallback, result *protocol.RPCResult) error {
@@ -152,10 +227,18 @@ func (client *ExchangeClient) AsyncRequest(invocation *protocol.Invocation, url
 	rsp.response = NewResponse(request.ID, "2.0.2")
 	rsp.Callback = callback
 	rsp.Reply = (*invocation).Reply()
+	rsp.LenientCollection = lenientCollection(url, (*invocation).MethodName())
+	rsp.CodecOptions = (*invocation).AttributeByKey(constant.CODEC_OPTIONS_KEY, nil)
+	// the callback is notified with an error if the response isn't received in time
+	rsp.timer = time.AfterFunc(timeout, func() {
+		cancelPendingResponse(SequenceType(request.ID), ErrAsyncRequestTimeout)
+	})
 	AddPendingResponse(rsp)
 
 	err := client.client.Request(request, timeout, rsp)
 	if err != nil {
+		removePendingResponse(SequenceType(request.ID))
+		rsp.stopTimer()
 		result.Err = err
 		return err
 	}
@@ -183,11 +266,31 @@ func (client *ExchangeClient) Send(invocation *protocol.Invocation, url *common.
 	return nil
 }
 
+// sendCancel notifies the server by a oneway request that the request of @id has been cancelled
+func (client *ExchangeClient) sendCancel(invocation protocol.Invocation, id int64, url *common.URL, timeout time.Duration) {
+	cancelInvocation := invocation_impl.NewRPCInvocationWithOptions(
+		invocation_impl.WithMethodName(constant.CANCEL_METHOD),
+		invocation_impl.WithArguments([]interface{}{}))
+	for _, k := range []string{constant.PATH_KEY, constant.INTERFACE_KEY, constant.GROUP_KEY, constant.VERSION_KEY,
+		constant.SERIALIZATION_KEY} {
+		if v := invocation.AttachmentsByKey(k, ""); len(v) > 0 {
+			cancelInvocation.SetAttachments(k, v)
+		}
+	}
+	cancelInvocation.SetAttachments(constant.CANCEL_REQUEST_ID_KEY, strconv.FormatInt(id, 10))
+	var inv protocol.Invocation = cancelInvocation
+	if err := client.Send(&inv, url, timeout); err != nil {
+		logger.Warnf("failed to notify the server that the request %d has been cancelled, error: %v", id, err)
+	}
+}
+
 // close client
 func (client *ExchangeClient) Close() {
+	client.initLock.Lock()
+	defer client.initLock.Unlock()
 	client.client.Close()
 	// for reinit client
-	client.init = false
+	client.init.Store(false)
 }
 
 // IsAvailable to check if the underlying network client is available yet.
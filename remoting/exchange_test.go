@@ -0,0 +1,52 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remoting
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+)
+
+func TestResponseHandleStopsTimer(t *testing.T) {
+	var notified int32
+	rsp := NewPendingResponse(SequenceID())
+	rsp.Callback = func(response common.CallbackResponse) {
+		atomic.AddInt32(&notified, 1)
+		assert.Nil(t, response.(AsyncCallbackResponse).Cause)
+	}
+	rsp.timer = time.AfterFunc(50*time.Millisecond, func() {
+		cancelPendingResponse(SequenceType(rsp.seq), ErrAsyncRequestTimeout)
+	})
+	AddPendingResponse(rsp)
+
+	response := NewResponse(rsp.seq, "2.0.2")
+	response.Handle()
+	// the timer doesn't fire once the response is received
+	assert.False(t, rsp.timer.Stop())
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&notified))
+}
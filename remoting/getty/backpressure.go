@@ -0,0 +1,70 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package getty
+
+import (
+	"go.uber.org/atomic"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+)
+
+// dispatchLimiter applies the backpressure on the requests dispatched by the server. The server turns busy
+// once the dispatching requests exceed the high watermark, and the new requests are rejected until
+// the dispatching ones drain below the low watermark.
+type dispatchLimiter struct {
+	high    int32
+	low     int32
+	pending atomic.Int32
+	busy    atomic.Bool
+}
+
+// newDispatchLimiter returns nil for the unlimited dispatching if @high isn't positive
+func newDispatchLimiter(high, low int) *dispatchLimiter {
+	if high <= 0 {
+		return nil
+	}
+	return &dispatchLimiter{high: int32(high), low: int32(low)}
+}
+
+// acquire returns false if the request should be rejected, otherwise release must be called once it's done
+func (l *dispatchLimiter) acquire() bool {
+	if l == nil {
+		return true
+	}
+	n := l.pending.Inc()
+	if n <= l.high && !l.busy.Load() {
+		return true
+	}
+	if n > l.high && l.busy.CAS(false, true) {
+		logger.Warnf("the dispatching requests exceed the high watermark %d, the server turns busy", l.high)
+	}
+	// the dispatching requests may have been drained meanwhile
+	l.release()
+	return false
+}
+
+func (l *dispatchLimiter) release() {
+	if l == nil {
+		return
+	}
+	if l.pending.Dec() <= l.low && l.busy.CAS(true, false) {
+		logger.Infof("the dispatching requests drain below the low watermark %d, the server recovers", l.low)
+	}
+}
@@ -75,6 +75,14 @@ type (
 		QueueLen    int `default:"0" yaml:"queue-len" json:"queue-len,omitempty"`
 		QueueNumber int `default:"0" yaml:"queue-number" json:"queue-number,omitempty"`
 
+		// the requests are rejected with the server busy error once the dispatching ones exceed the high
+		// watermark, until they drain below the low watermark, which is the half of the high one by default
+		DispatchHighWatermark int `default:"0" yaml:"dispatch-high-watermark" json:"dispatch-high-watermark,omitempty"`
+		DispatchLowWatermark  int `default:"0" yaml:"dispatch-low-watermark" json:"dispatch-low-watermark,omitempty"`
+		// the retry-after hint of the server busy error
+		BusyRetryAfter string `default:"1s" yaml:"busy-retry-after" json:"busy-retry-after,omitempty"`
+		busyRetryAfter time.Duration
+
 		// session tcp parameters
 		GettySessionParam GettySessionParam `required:"true" yaml:",inline" json:",inline"`
 	}
@@ -86,6 +94,12 @@ type (
 		// session pool
 		ConnectionNum int `default:"16" yaml:"connection-number" json:"connection-number,omitempty"`
 
+		// the connections of the client idle beyond the timeout are closed, and re-established on the next request,
+		// while the most recently used ConnectionMinIdle connections of all of the clients of the process are kept
+		ConnectionIdleTimeout string `default:"" yaml:"connection-idle-timeout" json:"connection-idle-timeout,omitempty"`
+		connectionIdleTimeout time.Duration
+		ConnectionMinIdle     int `default:"0" yaml:"connection-min-idle" json:"connection-min-idle,omitempty"`
+
 		// heartbeat
 		HeartbeatPeriod string `default:"60s" yaml:"heartbeat-period" json:"heartbeat-period,omitempty"`
 		heartbeatPeriod time.Duration
@@ -221,6 +235,12 @@ func (c *ClientConfig) CheckValidity() error {
 		return perrors.WithMessagef(err, "time.ParseDuration(SessionTimeout{%#v})", c.SessionTimeout)
 	}
 
+	if len(c.ConnectionIdleTimeout) == 0 {
+		c.connectionIdleTimeout = 0
+	} else if c.connectionIdleTimeout, err = time.ParseDuration(c.ConnectionIdleTimeout); err != nil {
+		return perrors.WithMessagef(err, "time.ParseDuration(ConnectionIdleTimeout{%#v})", c.ConnectionIdleTimeout)
+	}
+
 	return perrors.WithStack(c.GettySessionParam.CheckValidity())
 }
 
@@ -254,5 +274,14 @@ func (c *ServerConfig) CheckValidity() error {
 			c.SessionTimeout, time.Duration(config.MaxWheelTimeSpan))
 	}
 
+	if len(c.BusyRetryAfter) == 0 {
+		c.busyRetryAfter = time.Second
+	} else if c.busyRetryAfter, err = time.ParseDuration(c.BusyRetryAfter); err != nil {
+		return perrors.WithMessagef(err, "time.ParseDuration(BusyRetryAfter{%#v})", c.BusyRetryAfter)
+	}
+	if c.DispatchLowWatermark <= 0 || c.DispatchLowWatermark > c.DispatchHighWatermark {
+		c.DispatchLowWatermark = c.DispatchHighWatermark / 2
+	}
+
 	return perrors.WithStack(c.GettySessionParam.CheckValidity())
 }
@@ -129,11 +129,18 @@ type Client struct {
 	conf               ClientConfig
 	mux                sync.RWMutex
 	sslEnabled         bool
+	tlsConfigBuilder   getty.TlsConfigBuilder
 	clientClosed       bool
 	gettyClient        *gettyRPCClient
 	gettyClientMux     sync.RWMutex
 	gettyClientCreated atomic.Bool
 	codec              remoting.Codec
+	// lastUsed is the unix nano time the latest request finishes at
+	lastUsed atomic.Int64
+	// inFlight is the number of the requests acquiring or using the connection
+	inFlight atomic.Int32
+	// evicted is true if the connection is closed for being idle and not re-established yet
+	evicted atomic.Bool
 }
 
 // NewClient create client
@@ -161,15 +168,26 @@ func (c *Client) SetExchangeClient(client *remoting.ExchangeClient) {
 func (c *Client) Connect(url *common.URL) error {
 	initClient(url.Protocol)
 	c.conf = *clientConf
-	c.sslEnabled = url.GetParamBool(constant.SSL_ENABLED_KEY, false)
+	c.sslEnabled = url.GetParamBool(constant.SSL_ENABLED_KEY, false) || common.IsTLSEnabled(url)
+	tlsConfigBuilder, err := newClientTlsConfigBuilder(url)
+	if err != nil {
+		logger.Errorf("build the tls config of the client to %v failed for : %v", url.Location, err)
+		return err
+	}
+	c.tlsConfigBuilder = tlsConfigBuilder
 	// codec
-	c.codec = remoting.GetCodec(url.Protocol)
+	c.codec = remoting.GetCodecByURL(url)
 	c.addr = url.Location
-	_, _, err := c.selectSession(c.addr)
+	c.lastUsed.Store(time.Now().UnixNano())
+	_, _, err = c.selectSession(c.addr, c.opts.ConnectTimeout)
 	if err != nil {
 		logger.Errorf("try to connect server %v failed for : %v", url.Location, err)
+		return err
+	}
+	if c.conf.connectionIdleTimeout > 0 {
+		idleEvictor.add(c)
 	}
-	return err
+	return nil
 }
 
 // Close close network connection
@@ -179,14 +197,43 @@ func (c *Client) Close() {
 	c.gettyClient = nil
 	c.clientClosed = true
 	c.mux.Unlock()
+	idleEvictor.remove(c)
 	if client != nil {
 		client.close()
 	}
 }
 
 // Request send request
+// The connection re-established for the request is bounded by the connect timeout, and the response is awaited
+// within the read timeout of the request, while both of them are bounded by @timeout of the whole request.
 func (c *Client) Request(request *remoting.Request, timeout time.Duration, response *remoting.PendingResponse) error {
-	_, session, err := c.selectSession(c.addr)
+	// the connection isn't evicted until the request finishes, which is once the response is received or
+	// cancelled for the async ones, and once it's sent for the oneway ones
+	c.inFlight.Inc()
+	var once sync.Once
+	finish := func() {
+		once.Do(func() {
+			c.lastUsed.Store(time.Now().UnixNano())
+			c.inFlight.Dec()
+		})
+	}
+	pending := false
+	defer func() {
+		if !pending {
+			finish()
+		}
+	}()
+	async := request.TwoWay && response.Callback != nil
+	if async {
+		// the response may be received before the request returns
+		response.OnFinish(finish)
+	}
+	deadline := time.Now().Add(timeout)
+	connectTimeout := c.opts.ConnectTimeout
+	if connectTimeout > timeout {
+		connectTimeout = timeout
+	}
+	_, session, err := c.selectSession(c.addr, connectTimeout)
 	if err != nil {
 		return perrors.WithStack(err)
 	}
@@ -197,7 +244,7 @@ func (c *Client) Request(request *remoting.Request, timeout time.Duration, respo
 		totalLen int
 		sendLen  int
 	)
-	if totalLen, sendLen, err = c.transfer(session, request, timeout); err != nil {
+	if totalLen, sendLen, err = c.transfer(session, request, time.Until(deadline)); err != nil {
 		if sendLen != 0 && totalLen != sendLen {
 			logger.Warnf("start to close the session at request because %d of %d bytes data is sent success. err:%+v", sendLen, totalLen, err)
 			go c.Close()
@@ -205,12 +252,17 @@ func (c *Client) Request(request *remoting.Request, timeout time.Duration, respo
 		return perrors.WithStack(err)
 	}
 
-	if !request.TwoWay || response.Callback != nil {
+	if !request.TwoWay || async {
+		pending = async
 		return nil
 	}
 
+	readTimeout := time.Until(deadline)
+	if request.ReadTimeout > 0 && request.ReadTimeout < readTimeout {
+		readTimeout = request.ReadTimeout
+	}
 	select {
-	case <-gxtime.After(timeout):
+	case <-gxtime.After(readTimeout):
 		return perrors.WithStack(errClientReadTimeout)
 	case <-response.Done:
 		err = response.Err
@@ -221,13 +273,19 @@ func (c *Client) Request(request *remoting.Request, timeout time.Duration, respo
 
 // IsAvailable returns true if the connection is available, or it can be re-established.
 func (c *Client) IsAvailable() bool {
-	client, _, err := c.selectSession(c.addr)
+	// the evicted connection is re-established on the next request rather than the check
+	if c.evicted.Load() {
+		c.mux.RLock()
+		defer c.mux.RUnlock()
+		return !c.clientClosed
+	}
+	client, _, err := c.selectSession(c.addr, c.opts.ConnectTimeout)
 	return err == nil &&
 		// defensive check
 		client != nil
 }
 
-func (c *Client) selectSession(addr string) (*gettyRPCClient, getty.Session, error) {
+func (c *Client) selectSession(addr string, connectTimeout time.Duration) (*gettyRPCClient, getty.Session, error) {
 	c.mux.RLock()
 	defer c.mux.RUnlock()
 	if c.clientClosed {
@@ -237,13 +295,14 @@ func (c *Client) selectSession(addr string) (*gettyRPCClient, getty.Session, err
 	if !c.gettyClientCreated.Load() {
 		c.gettyClientMux.Lock()
 		if c.gettyClient == nil {
-			rpcClientConn, rpcErr := newGettyRPCClientConn(c, addr)
+			rpcClientConn, rpcErr := newGettyRPCClientConn(c, addr, connectTimeout)
 			if rpcErr != nil {
 				c.gettyClientMux.Unlock()
 				return nil, nil, perrors.WithStack(rpcErr)
 			}
 			c.gettyClientCreated.Store(true)
 			c.gettyClient = rpcClientConn
+			c.evicted.Store(false)
 		}
 		client := c.gettyClient
 		session := c.gettyClient.selectSession()
@@ -251,22 +310,49 @@ func (c *Client) selectSession(addr string) (*gettyRPCClient, getty.Session, err
 		return client, session, nil
 	}
 	c.gettyClientMux.RLock()
+	defer c.gettyClientMux.RUnlock()
 	client := c.gettyClient
-	session := c.gettyClient.selectSession()
-	c.gettyClientMux.RUnlock()
-	return client, session, nil
+	// the connection may be reset since it's checked
+	if client == nil {
+		return nil, nil, perrors.WithStack(errSessionClosed)
+	}
+	return client, client.selectSession(), nil
 
 }
 
 func (c *Client) transfer(session getty.Session, request *remoting.Request, timeout time.Duration) (int, int, error) {
-	totalLen, sendLen, err := session.WritePkg(request, timeout)
-	return totalLen, sendLen, perrors.WithStack(err)
+	return writePkg(session, request, timeout)
 }
 
-func (c *Client) resetRpcConn() {
+// resetRpcConn resets the connection if it's still @conn, which may be replaced after it's evicted
+func (c *Client) resetRpcConn(conn *gettyRPCClient) {
 	c.gettyClientMux.Lock()
+	if c.gettyClient == conn {
+		c.gettyClient = nil
+		c.gettyClientCreated.Store(false)
+	}
+	c.gettyClientMux.Unlock()
+}
+
+// evictIdle closes the connection if it's idle for @idleTimeout, and it's re-established on the next request.
+// The requests acquire the session holding the read lock of mux after they are counted in flight,
+// so none of them is using the connection once it's checked with the write lock.
+func (c *Client) evictIdle(idleTimeout time.Duration) bool {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if c.clientClosed || c.inFlight.Load() > 0 || time.Since(time.Unix(0, c.lastUsed.Load())) < idleTimeout {
+		return false
+	}
+	c.gettyClientMux.Lock()
+	conn := c.gettyClient
 	c.gettyClient = nil
 	c.gettyClientCreated.Store(false)
 	c.gettyClientMux.Unlock()
-
+	if conn == nil {
+		return false
+	}
+	c.evicted.Store(true)
+	logger.Infof("close the connection to %s idle for %s", c.addr, idleTimeout)
+	conn.close()
+	return true
 }
@@ -51,9 +51,70 @@ func TestRunSuite(t *testing.T) {
 	testRequestOneWay(t, client)
 	//testClient_Call(t, client)
 	testClient_AsyncCall(t, client)
+	testClientIdleEviction(t, url)
 	svr.Stop()
 }
 
+func testClientIdleEviction(t *testing.T, url *common.URL) {
+	originRootConf := config.GetRootConfig()
+	config.SetRootConfig(config.RootConfig{
+		Application: &config.ApplicationConfig{Name: "idle-eviction"},
+		Protocols: map[string]*config.ProtocolConfig{
+			"dubbo": {
+				Name:   "dubbo",
+				Params: map[string]interface{}{"connection-number": 2, "connection-idle-timeout": "200ms"},
+			},
+		},
+	})
+	defer config.SetRootConfig(*originRootConf)
+	client := getClient(url)
+	assert.NotNil(t, client)
+	defer client.Close()
+	conn := client.gettyClient
+	testRequestOneWay(t, client)
+
+	// the connection in use isn't evicted
+	client.inFlight.Inc()
+	time.Sleep(500 * time.Millisecond)
+	assert.False(t, client.evicted.Load())
+	client.inFlight.Dec()
+
+	// the async request keeps the connection until its response is handled
+	release := make(chan struct{})
+	handled := make(chan struct{})
+	request := remoting.NewRequest("2.0.2")
+	request.Data = createInvocation("GetUser0", nil, nil, []interface{}{"4", nil, "username"},
+		[]reflect.Value{reflect.ValueOf("4"), reflect.ValueOf(nil), reflect.ValueOf("username")})
+	setAttachment(request.Data.(*invocation.RPCInvocation), map[string]string{INTERFACE_KEY: "com.ikurento.user.UserProvider"})
+	request.TwoWay = true
+	rsp := remoting.NewPendingResponse(request.ID)
+	rsp.SetResponse(remoting.NewResponse(request.ID, "2.0.2"))
+	rsp.Reply = &User{}
+	rsp.Callback = func(common.CallbackResponse) {
+		<-release
+		close(handled)
+	}
+	remoting.AddPendingResponse(rsp)
+	assert.NoError(t, client.Request(request, 3*time.Second, rsp))
+	time.Sleep(500 * time.Millisecond)
+	assert.False(t, client.evicted.Load())
+	assert.Equal(t, int32(1), client.inFlight.Load())
+	close(release)
+	<-handled
+
+	// the idle connection is closed, while the client is still available
+	assert.Eventually(t, client.evicted.Load, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, int64(0), conn.getActive())
+	assert.True(t, client.IsAvailable())
+	assert.True(t, client.evicted.Load())
+
+	// a new connection is created on the next request
+	testRequestOneWay(t, client)
+	assert.False(t, client.evicted.Load())
+	assert.NotNil(t, client.gettyClient)
+	assert.NotEqual(t, conn, client.gettyClient)
+}
+
 func testRequestOneWay(t *testing.T, client *Client) {
 	request := remoting.NewRequest("2.0.2")
 	invocation := createInvocation("GetUser", nil, nil, []interface{}{"1", "username"},
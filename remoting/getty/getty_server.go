@@ -110,6 +110,12 @@ type Server struct {
 	tcpServer      getty.Server
 	rpcHandler     *RpcServerHandler
 	requestHandler func(*invocation.RPCInvocation) protocol.RPCResult
+	// tlsConfigBuilder builds the tls config if SSL is enabled
+	tlsConfigBuilder getty.TlsConfigBuilder
+	// taskPoolSelector returns the dedicated goroutine pool of the invoked service, or nil for the shared one
+	taskPoolSelector func(*invocation.RPCInvocation) gxsync.GenericTaskPool
+	// dispatchLimiter rejects the requests once the server is busy, it's nil if unlimited
+	dispatchLimiter *dispatchLimiter
 }
 
 // NewServer create a new Server
@@ -117,13 +123,19 @@ func NewServer(url *common.URL, handlers func(*invocation.RPCInvocation) protoco
 	// init
 	initServer(url.Protocol)
 
-	srvConf.SSLEnabled = url.GetParamBool(constant.SSL_ENABLED_KEY, false)
+	srvConf.SSLEnabled = url.GetParamBool(constant.SSL_ENABLED_KEY, false) || common.IsTLSEnabled(url)
+	tlsConfigBuilder, err := newServerTlsConfigBuilder(url)
+	if err != nil {
+		panic(fmt.Sprintf("build the tls config of the server %s failed for : %v", url.Location, err))
+	}
 
 	s := &Server{
-		conf:           *srvConf,
-		addr:           url.Location,
-		codec:          remoting.GetCodec(url.Protocol),
-		requestHandler: handlers,
+		conf:             *srvConf,
+		addr:             url.Location,
+		codec:            remoting.GetCodecByURL(url),
+		requestHandler:   handlers,
+		tlsConfigBuilder: tlsConfigBuilder,
+		dispatchLimiter:  newDispatchLimiter(srvConf.DispatchHighWatermark, srvConf.DispatchLowWatermark),
 	}
 
 	s.rpcHandler = NewRpcServerHandler(s.conf.SessionNumber, s.conf.sessionTimeout, s)
@@ -131,6 +143,12 @@ func NewServer(url *common.URL, handlers func(*invocation.RPCInvocation) protoco
 	return s
 }
 
+// SetTaskPoolSelector sets the function used to pick the goroutine pool which a request is dispatched on.
+// Requests whose service has no dedicated pool are handled on the shared pool of the server.
+func (s *Server) SetTaskPoolSelector(selector func(*invocation.RPCInvocation) gxsync.GenericTaskPool) {
+	s.taskPoolSelector = selector
+}
+
 func (s *Server) newSession(session getty.Session) error {
 	var (
 		ok      bool
@@ -205,7 +223,7 @@ func (s *Server) Start() {
 	serverOpts := []getty.ServerOption{getty.WithLocalAddress(addr)}
 	if s.conf.SSLEnabled {
 		serverOpts = append(serverOpts, getty.WithServerSslEnabled(s.conf.SSLEnabled),
-			getty.WithServerTlsConfigBuilder(config.GetServerTlsConfigBuilder()))
+			getty.WithServerTlsConfigBuilder(s.tlsConfigBuilder))
 	}
 
 	serverOpts = append(serverOpts, getty.WithServerTaskPool(gxsync.NewTaskPoolSimple(s.conf.GrPoolSize)))
@@ -0,0 +1,112 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package getty
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// minEvictionInterval bounds how often the idle connections are checked
+const minEvictionInterval = 10 * time.Millisecond
+
+var idleEvictor = &clientEvictor{clients: make(map[*Client]struct{})}
+
+// clientEvictor closes the connections of the clients configured with connection-idle-timeout once they're idle
+// beyond it, while the connection-min-idle most recently used ones of all of the clients are kept. It checks the
+// clients at the half of the shortest timeout, and stops once none of them is left.
+type clientEvictor struct {
+	lock    sync.Mutex
+	clients map[*Client]struct{}
+	running bool
+	// minIdle is the number of the connections kept, which is the connection-min-idle of the client config
+	// shared by all of the clients, the latest one is used if it's changed
+	minIdle int
+}
+
+func (e *clientEvictor) add(c *Client) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.clients[c] = struct{}{}
+	e.minIdle = c.conf.ConnectionMinIdle
+	if !e.running {
+		e.running = true
+		go e.run()
+	}
+}
+
+func (e *clientEvictor) remove(c *Client) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	delete(e.clients, c)
+}
+
+func (e *clientEvictor) run() {
+	for {
+		interval, ok := e.interval()
+		if !ok {
+			return
+		}
+		time.Sleep(interval)
+		e.evict()
+	}
+}
+
+// interval returns the interval before the next check, and false once none of the clients is left
+func (e *clientEvictor) interval() (time.Duration, bool) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if len(e.clients) == 0 {
+		e.running = false
+		return 0, false
+	}
+	var interval time.Duration
+	for c := range e.clients {
+		if timeout := c.conf.connectionIdleTimeout / 2; interval == 0 || timeout < interval {
+			interval = timeout
+		}
+	}
+	if interval < minEvictionInterval {
+		interval = minEvictionInterval
+	}
+	return interval, true
+}
+
+func (e *clientEvictor) evict() {
+	e.lock.Lock()
+	clients := make([]*Client, 0, len(e.clients))
+	for c := range e.clients {
+		if !c.evicted.Load() {
+			clients = append(clients, c)
+		}
+	}
+	minIdle := e.minIdle
+	e.lock.Unlock()
+
+	// the most recently used ones are kept for the minimum
+	sort.Slice(clients, func(i, j int) bool {
+		return clients[i].lastUsed.Load() > clients[j].lastUsed.Load()
+	})
+	for i, c := range clients {
+		if i < minIdle {
+			continue
+		}
+		c.evictIdle(c.conf.connectionIdleTimeout)
+	}
+}
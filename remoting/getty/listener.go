@@ -18,7 +18,9 @@
 package getty
 
 import (
+	"context"
 	"fmt"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -37,6 +39,7 @@ import (
 import (
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
 	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
 	"dubbo.apache.org/dubbo-go/v3/remoting"
 )
@@ -185,6 +188,14 @@ type RpcServerHandler struct {
 	rwlock         sync.RWMutex
 	server         *Server
 	timeoutTimes   int
+	// cancelFuncs stores the cancel functions of the processing requests by requestKey
+	cancelFuncs sync.Map
+}
+
+// requestKey identifies a request of a session, the request id is only unique in its session
+type requestKey struct {
+	session getty.Session
+	id      int64
 }
 
 // nolint
@@ -231,6 +242,13 @@ func (h *RpcServerHandler) OnClose(session getty.Session) {
 	h.rwlock.Lock()
 	delete(h.sessionMap, session)
 	h.rwlock.Unlock()
+	// the consumer has gone away, so its processing requests are cancelled
+	h.cancelFuncs.Range(func(key, value interface{}) bool {
+		if key.(requestKey).session == session {
+			value.(context.CancelFunc)()
+		}
+		return true
+	})
 }
 
 // OnMessage get request from getty client, update the session reqNum and reply response to client
@@ -265,6 +283,7 @@ func (h *RpcServerHandler) OnMessage(session getty.Session, pkg interface{}) {
 	resp.Status = hessian.Response_OK
 	resp.Event = req.Event
 	resp.SerialID = req.SerialID
+	resp.CodecOptions = req.CodecOptions
 	resp.Version = "2.0.2"
 
 	// heartbeat
@@ -274,6 +293,46 @@ func (h *RpcServerHandler) OnMessage(session getty.Session, pkg interface{}) {
 		return
 	}
 
+	// the request couldn't be decoded, e.g. for the serialization mismatch
+	if err, ok := req.Data.(error); ok {
+		if req.TwoWay {
+			resp.Status = hessian.Response_BAD_REQUEST
+			resp.Error = err
+			resp.Result = protocol.RPCResult{Err: err}
+			reply(session, resp)
+		}
+		return
+	}
+
+	invoc, ok := req.Data.(*invocation.RPCInvocation)
+	if ok && invoc.MethodName() == constant.CANCEL_METHOD {
+		h.cancelRequest(session, invoc)
+		return
+	}
+	if !h.server.dispatchLimiter.acquire() {
+		if req.TwoWay {
+			err := remoting.NewServerBusyError(h.server.conf.busyRetryAfter)
+			resp.Status = hessian.Response_SERVER_ERROR
+			resp.Error = err
+			resp.Result = protocol.RPCResult{Err: err}
+			reply(session, resp)
+		}
+		return
+	}
+	if ok && h.server.taskPoolSelector != nil {
+		if pool := h.server.taskPoolSelector(invoc); pool != nil {
+			if !pool.AddTask(func() { h.handleRequest(session, req, resp) }) {
+				h.handleRequest(session, req, resp)
+			}
+			return
+		}
+	}
+	h.handleRequest(session, req, resp)
+}
+
+// handleRequest invokes the service of @req and replies @resp to the client
+func (h *RpcServerHandler) handleRequest(session getty.Session, req *remoting.Request, resp *remoting.Response) {
+	defer h.server.dispatchLimiter.release()
 	defer func() {
 		if e := recover(); e != nil {
 			resp.Status = hessian.Response_SERVER_ERROR
@@ -303,6 +362,17 @@ func (h *RpcServerHandler) OnMessage(session getty.Session, pkg interface{}) {
 	attachments[constant.LOCAL_ADDR] = session.LocalAddr()
 	attachments[constant.REMOTE_ADDR] = session.RemoteAddr()
 
+	if req.TwoWay {
+		ctx, cancel := context.WithCancel(context.Background())
+		key := requestKey{session: session, id: req.ID}
+		h.cancelFuncs.Store(key, cancel)
+		defer func() {
+			h.cancelFuncs.Delete(key)
+			cancel()
+		}()
+		invoc.SetAttribute(constant.REQUEST_CTX_KEY, ctx)
+	}
+
 	result := h.server.requestHandler(invoc)
 	if !req.TwoWay {
 		return
@@ -311,6 +381,19 @@ func (h *RpcServerHandler) OnMessage(session getty.Session, pkg interface{}) {
 	reply(session, resp)
 }
 
+// cancelRequest cancels the context of the processing request which is specified by the cancel invocation
+func (h *RpcServerHandler) cancelRequest(session getty.Session, invoc *invocation.RPCInvocation) {
+	id, err := strconv.ParseInt(invoc.AttachmentsByKey(constant.CANCEL_REQUEST_ID_KEY, ""), 10, 64)
+	if err != nil {
+		logger.Warnf("illegal cancel request id: %v", invoc.Attachment(constant.CANCEL_REQUEST_ID_KEY))
+		return
+	}
+	if cancel, ok := h.cancelFuncs.Load(requestKey{session: session, id: id}); ok {
+		logger.Debugf("the request %d of session{%s} is cancelled by the client", id, session.Stat())
+		cancel.(context.CancelFunc)()
+	}
+}
+
 // OnCron check the session health periodic. if the session's sessionTimeout has reached, just close the session
 func (h *RpcServerHandler) OnCron(session getty.Session) {
 	var (
@@ -358,7 +441,7 @@ func (h *RpcServerHandler) OnCron(session getty.Session) {
 }
 
 func reply(session getty.Session, resp *remoting.Response) {
-	if totalLen, sendLen, err := session.WritePkg(resp, WritePkg_Timeout); err != nil {
+	if totalLen, sendLen, err := writePkg(session, resp, WritePkg_Timeout); err != nil {
 		if sendLen != 0 && totalLen != sendLen {
 			logger.Warnf("start to close the session at replying because %d of %d bytes data is sent success. err:%+v", sendLen, totalLen, err)
 			go session.Close()
@@ -373,7 +456,7 @@ func heartbeat(session getty.Session, timeout time.Duration, callBack func(err e
 	req.Event = true
 	resp := remoting.NewPendingResponse(req.ID)
 	remoting.AddPendingResponse(resp)
-	totalLen, sendLen, err := session.WritePkg(req, -1)
+	totalLen, sendLen, err := writePkg(session, req, -1)
 	if sendLen != 0 && totalLen != sendLen {
 		logger.Warnf("start to close the session at heartbeat because %d of %d bytes data is sent success. err:%+v", sendLen, totalLen, err)
 		go session.Close()
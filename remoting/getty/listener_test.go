@@ -20,9 +20,14 @@ package getty
 import (
 	"context"
 	"testing"
+	"time"
 )
 
 import (
+	getty "github.com/apache/dubbo-getty"
+
+	gxsync "github.com/dubbogo/gost/sync"
+
 	"github.com/opentracing/opentracing-go"
 	"github.com/opentracing/opentracing-go/mocktracer"
 
@@ -31,7 +36,9 @@ import (
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
 	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
 )
 
 // test rebuild the ctx
@@ -60,6 +67,80 @@ func TestRebuildCtx(t *testing.T) {
 	assert.NotNil(t, ctx.Value(constant.TRACING_REMOTE_SPAN_CTX))
 }
 
+// replySession records the responses replied by the server
+type replySession struct {
+	getty.Session
+	replies chan *remoting.Response
+}
+
+func (s *replySession) WritePkg(pkg interface{}, _ time.Duration) (int, int, error) {
+	s.replies <- pkg.(*remoting.Response)
+	return 1, 1, nil
+}
+
+func (s *replySession) LocalAddr() string {
+	return "127.0.0.1:20000"
+}
+
+func (s *replySession) RemoteAddr() string {
+	return "127.0.0.1:30000"
+}
+
+func TestRpcServerHandlerBackpressure(t *testing.T) {
+	conf := GetDefaultServerConfig()
+	conf.DispatchHighWatermark = 4
+	conf.DispatchLowWatermark = 1
+	conf.BusyRetryAfter = "200ms"
+	assert.NoError(t, conf.CheckValidity())
+	proceed := make(chan struct{})
+	server := &Server{
+		conf: *conf,
+		requestHandler: func(*invocation.RPCInvocation) protocol.RPCResult {
+			<-proceed
+			return protocol.RPCResult{Rest: "ok"}
+		},
+		dispatchLimiter: newDispatchLimiter(conf.DispatchHighWatermark, conf.DispatchLowWatermark),
+	}
+	handler := NewRpcServerHandler(conf.SessionNumber, conf.sessionTimeout, server)
+	session := &replySession{replies: make(chan *remoting.Response, 16)}
+	request := func() {
+		req := remoting.NewRequest("2.0.2")
+		req.TwoWay = true
+		req.Data = invocation.NewRPCInvocation("GetUser", nil, map[string]interface{}{})
+		go handler.OnMessage(session, remoting.DecodeResult{IsRequest: true, Result: req})
+	}
+
+	// saturate the dispatch queue
+	for i := 0; i < 4; i++ {
+		request()
+	}
+	assert.Eventually(t, func() bool { return server.dispatchLimiter.pending.Load() == 4 }, time.Second, time.Millisecond)
+	request()
+	busy := <-session.replies
+	assert.Equal(t, remoting.NewServerBusyError(200*time.Millisecond), busy.Error)
+
+	// the server is still busy above the low watermark
+	proceed <- struct{}{}
+	assert.Equal(t, "ok", (<-session.replies).Result.(protocol.RPCResult).Rest)
+	request()
+	assert.IsType(t, &remoting.ServerBusyError{}, (<-session.replies).Error)
+
+	// the server recovers once the queue drains below the low watermark
+	for i := 0; i < 2; i++ {
+		proceed <- struct{}{}
+		<-session.replies
+	}
+	assert.False(t, server.dispatchLimiter.busy.Load())
+	request()
+	proceed <- struct{}{}
+	proceed <- struct{}{}
+	for i := 0; i < 2; i++ {
+		resp := <-session.replies
+		assert.NoError(t, resp.Error)
+		assert.Equal(t, "ok", resp.Result.(protocol.RPCResult).Rest)
+	}
+}
+
 // rebuildCtx rebuild the context by attachment.
 // Once we decided to transfer more context's key-value, we should change this.
 // now we only support rebuild the tracing context
@@ -74,3 +155,56 @@ func rebuildCtx(inv *invocation.RPCInvocation) context.Context {
 	}
 	return ctx
 }
+
+func TestRpcServerHandlerTaskPool(t *testing.T) {
+	conf := GetDefaultServerConfig()
+	assert.NoError(t, conf.CheckValidity())
+	proceed := make(chan struct{})
+	server := &Server{
+		conf: *conf,
+		requestHandler: func(inv *invocation.RPCInvocation) protocol.RPCResult {
+			if inv.MethodName() == "Batch" {
+				<-proceed
+			}
+			return protocol.RPCResult{Rest: inv.MethodName()}
+		},
+		dispatchLimiter: newDispatchLimiter(conf.DispatchHighWatermark, conf.DispatchLowWatermark),
+	}
+	batchPool := gxsync.NewTaskPoolSimple(1)
+	latencyPool := gxsync.NewTaskPoolSimple(1)
+	defer batchPool.Close()
+	defer latencyPool.Close()
+	server.SetTaskPoolSelector(func(inv *invocation.RPCInvocation) gxsync.GenericTaskPool {
+		if inv.MethodName() == "Batch" {
+			return batchPool
+		}
+		return latencyPool
+	})
+	handler := NewRpcServerHandler(conf.SessionNumber, conf.sessionTimeout, server)
+	session := &replySession{replies: make(chan *remoting.Response, 4)}
+	request := func(method string) {
+		req := remoting.NewRequest("2.0.2")
+		req.TwoWay = true
+		req.Data = invocation.NewRPCInvocation(method, nil, map[string]interface{}{})
+		handler.OnMessage(session, remoting.DecodeResult{IsRequest: true, Result: req})
+	}
+
+	// the only goroutine of the batch pool is occupied
+	request("Batch")
+	// the dispatch returns at once since the request is handled on the pool of its service
+	request("Latency")
+	select {
+	case resp := <-session.replies:
+		assert.Equal(t, "Latency", resp.Result.(protocol.RPCResult).Rest)
+	case <-time.After(time.Second):
+		assert.FailNow(t, "the latency service is stalled by the batch service")
+	}
+
+	close(proceed)
+	select {
+	case resp := <-session.replies:
+		assert.Equal(t, "Batch", resp.Result.(protocol.RPCResult).Rest)
+	case <-time.After(time.Second):
+		assert.FailNow(t, "no reply of the batch service")
+	}
+}
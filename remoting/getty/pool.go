@@ -35,7 +35,6 @@ import (
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/common/logger"
-	"dubbo.apache.org/dubbo-go/v3/config"
 )
 
 type gettyRPCClient struct {
@@ -51,7 +50,8 @@ type gettyRPCClient struct {
 	sessions    []*rpcSession
 }
 
-func newGettyRPCClientConn(rpcClient *Client, addr string) (*gettyRPCClient, error) {
+// newGettyRPCClientConn connects to @addr, which fails if the connection isn't established within @connectTimeout
+func newGettyRPCClientConn(rpcClient *Client, addr string, connectTimeout time.Duration) (*gettyRPCClient, error) {
 	var (
 		gettyClient getty.Client
 		sslEnabled  bool
@@ -63,7 +63,7 @@ func newGettyRPCClientConn(rpcClient *Client, addr string) (*gettyRPCClient, err
 		getty.WithReconnectInterval(rpcClient.conf.ReconnectInterval),
 	}
 	if sslEnabled {
-		clientOpts = append(clientOpts, getty.WithClientSslEnabled(sslEnabled), getty.WithClientTlsConfigBuilder(config.GetClientTlsConfigBuilder()))
+		clientOpts = append(clientOpts, getty.WithClientSslEnabled(sslEnabled), getty.WithClientTlsConfigBuilder(rpcClient.tlsConfigBuilder))
 	}
 
 	if clientGrPool != nil {
@@ -80,7 +80,6 @@ func newGettyRPCClientConn(rpcClient *Client, addr string) (*gettyRPCClient, err
 
 	idx := 1
 	start := time.Now()
-	connectTimeout := rpcClient.opts.ConnectTimeout
 	for {
 		idx++
 		if c.isAvailable() {
@@ -227,7 +226,7 @@ func (c *gettyRPCClient) removeSession(session getty.Session) {
 		}
 	}()
 	if removeFlag {
-		c.rpcClient.resetRpcConn()
+		c.rpcClient.resetRpcConn(c)
 		c.close()
 	}
 }
@@ -0,0 +1,58 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package getty
+
+import (
+	"time"
+)
+
+import (
+	getty "github.com/apache/dubbo-getty"
+
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+// errSessionClosed is returned by the writes to the session which is closed or being closed, e.g. on shutdown.
+// It's a transport error since the package isn't sent, so that the consumers retry the requests on other providers.
+var errSessionClosed = protocol.NewTransportError(perrors.New("the session is closed or being closed"))
+
+// writePkg writes @pkg to @session, and the errors of the writes racing with the close of the session are
+// returned as errSessionClosed instead of the panics
+func writePkg(session getty.Session, pkg interface{}, timeout time.Duration) (totalLen int, sendLen int, err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			logger.Warnf("write the package to the session panics: %v", e)
+			totalLen, sendLen, err = 0, 0, perrors.WithStack(errSessionClosed)
+		}
+	}()
+	totalLen, sendLen, err = session.WritePkg(pkg, timeout)
+	// the session recovers the panic of the write and returns nothing, e.g. it's closed meanwhile,
+	// while a package written successfully is never empty
+	if err == nil && totalLen == 0 {
+		err = perrors.New("the package is not written to the session")
+	}
+	if perrors.Is(err, getty.ErrSessionClosed) || err != nil && session.IsClosed() {
+		err = errSessionClosed
+	}
+	return totalLen, sendLen, perrors.WithStack(err)
+}
@@ -0,0 +1,77 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package getty
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	getty "github.com/apache/dubbo-getty"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.uber.org/atomic"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+// closingSession is closed once anything is written to it
+type closingSession struct {
+	getty.Session
+	closed    atomic.Bool
+	recovered bool
+}
+
+func (s *closingSession) IsClosed() bool {
+	return s.closed.Load()
+}
+
+func (s *closingSession) Stat() string {
+	return "closing session"
+}
+
+func (s *closingSession) WritePkg(interface{}, time.Duration) (int, int, error) {
+	if s.closed.Swap(true) {
+		return 0, 0, getty.ErrSessionClosed
+	}
+	if s.recovered {
+		// the session of getty recovers the panic and returns nothing
+		return 0, 0, nil
+	}
+	panic("use of closed network connection")
+}
+
+func TestWritePkgToClosingSession(t *testing.T) {
+	for _, session := range []*closingSession{{}, {recovered: true}} {
+		var err error
+		assert.NotPanics(t, func() {
+			_, _, err = writePkg(session, remoting.NewRequest("2.0.2"), time.Second)
+		})
+		assert.True(t, protocol.IsTransportError(err))
+		assert.Contains(t, err.Error(), "the session is closed or being closed")
+
+		// the session is closed already
+		_, _, err = writePkg(session, remoting.NewRequest("2.0.2"), time.Second)
+		assert.True(t, protocol.IsTransportError(err))
+	}
+}
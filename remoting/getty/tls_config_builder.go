@@ -0,0 +1,68 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package getty
+
+import (
+	"crypto/tls"
+)
+
+import (
+	"github.com/apache/dubbo-getty"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/config"
+)
+
+// staticTlsConfigBuilder returns the tls config built in advance, so that the invalid
+// certificates are reported when the client or the server is created rather than on dialing
+type staticTlsConfigBuilder struct {
+	config *tls.Config
+}
+
+// BuildTlsConfig returns the built tls config
+func (b *staticTlsConfigBuilder) BuildTlsConfig() (*tls.Config, error) {
+	return b.config, nil
+}
+
+// newClientTlsConfigBuilder builds the client tls config by the TLS params of the @url,
+// and uses the global one if the url doesn't have them
+func newClientTlsConfigBuilder(url *common.URL) (getty.TlsConfigBuilder, error) {
+	if !common.IsTLSEnabled(url) {
+		return config.GetClientTlsConfigBuilder(), nil
+	}
+	tlsConfig, err := common.NewClientTLSConfig(url)
+	if err != nil {
+		return nil, err
+	}
+	return &staticTlsConfigBuilder{config: tlsConfig}, nil
+}
+
+// newServerTlsConfigBuilder builds the server tls config by the TLS params of the @url,
+// and uses the global one if the url doesn't have them
+func newServerTlsConfigBuilder(url *common.URL) (getty.TlsConfigBuilder, error) {
+	if !common.IsTLSEnabled(url) {
+		return config.GetServerTlsConfigBuilder(), nil
+	}
+	tlsConfig, err := common.NewServerTLSConfig(url)
+	if err != nil {
+		return nil, err
+	}
+	return &staticTlsConfigBuilder{config: tlsConfig}, nil
+}
@@ -0,0 +1,154 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package getty
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+// writeSelfSignedCert generates a self-signed certificate and its key into @dir
+func writeSelfSignedCert(t *testing.T, dir string, name string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	certFile := filepath.Join(dir, name+".crt")
+	keyFile := filepath.Join(dir, name+".key")
+	assert.NoError(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	assert.NoError(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0o600))
+	return certFile, keyFile
+}
+
+func TestClientTlsConfigBuilder(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer server.Close()
+	addr := strings.TrimPrefix(server.URL, "https://")
+
+	dir := t.TempDir()
+	certFile, keyFile := writeSelfSignedCert(t, dir, "client")
+	serverCAFile := filepath.Join(dir, "server.crt")
+	assert.NoError(t, ioutil.WriteFile(serverCAFile,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600))
+
+	// the server certificate isn't signed by the configured CA
+	url, err := common.NewURL("dubbo://" + addr + "/com.ikurento.user.UserProvider")
+	assert.NoError(t, err)
+	url.SetParam(constant.TLS_CERT_FILE_KEY, certFile)
+	url.SetParam(constant.TLS_KEY_FILE_KEY, keyFile)
+	url.SetParam(constant.TLS_CA_FILE_KEY, certFile)
+	builder, err := newClientTlsConfigBuilder(url)
+	assert.NoError(t, err)
+	tlsConfig, err := builder.BuildTlsConfig()
+	assert.NoError(t, err)
+	_, err = tls.Dial("tcp", addr, tlsConfig)
+	assert.True(t, errors.As(err, &x509.UnknownAuthorityError{}))
+
+	// the server is trusted by its CA
+	url.SetParam(constant.TLS_CA_FILE_KEY, serverCAFile)
+	builder, err = newClientTlsConfigBuilder(url)
+	assert.NoError(t, err)
+	tlsConfig, err = builder.BuildTlsConfig()
+	assert.NoError(t, err)
+	conn, err := tls.Dial("tcp", addr, tlsConfig)
+	assert.NoError(t, err)
+	assert.NoError(t, conn.Close())
+
+	// the key file which doesn't exist is reported when the builder is created
+	url.SetParam(constant.TLS_KEY_FILE_KEY, filepath.Join(dir, "absent.key"))
+	_, err = newClientTlsConfigBuilder(url)
+	assert.Error(t, err)
+}
+
+func TestServerTlsConfigBuilderReload(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeSelfSignedCert(t, dir, "server")
+	url, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider")
+	assert.NoError(t, err)
+	url.SetParam(constant.TLS_CERT_FILE_KEY, certFile)
+	url.SetParam(constant.TLS_KEY_FILE_KEY, keyFile)
+	builder, err := newServerTlsConfigBuilder(url)
+	assert.NoError(t, err)
+	tlsConfig, err := builder.BuildTlsConfig()
+	assert.NoError(t, err)
+
+	// handshake returns the common name of the certificate presented by the server
+	handshake := func() string {
+		serverConn, clientConn := net.Pipe()
+		defer serverConn.Close()
+		defer clientConn.Close()
+		go tls.Server(serverConn, tlsConfig).Handshake()
+		client := tls.Client(clientConn, &tls.Config{InsecureSkipVerify: true})
+		assert.NoError(t, client.Handshake())
+		return client.ConnectionState().PeerCertificates[0].Subject.CommonName
+	}
+	assert.Equal(t, "server", handshake())
+
+	// the rotated certificate is presented by the new handshakes
+	rotatedCertFile, rotatedKeyFile := writeSelfSignedCert(t, dir, "rotated")
+	assert.NoError(t, os.Rename(rotatedCertFile, certFile))
+	assert.NoError(t, os.Rename(rotatedKeyFile, keyFile))
+	modTime := time.Now().Add(time.Minute)
+	assert.NoError(t, os.Chtimes(certFile, modTime, modTime))
+	assert.NoError(t, os.Chtimes(keyFile, modTime, modTime))
+	assert.Equal(t, "rotated", handshake())
+
+	// the previous certificate is kept if the files are broken during the rotation
+	assert.NoError(t, ioutil.WriteFile(keyFile, []byte("broken"), 0o600))
+	modTime = modTime.Add(time.Minute)
+	assert.NoError(t, os.Chtimes(keyFile, modTime, modTime))
+	assert.Equal(t, "rotated", handshake())
+}
@@ -29,8 +29,12 @@ import (
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
 )
 
+// healthCheckInterval is the interval of checking the connected state of the client reported to the health metrics
+var healthCheckInterval = time.Second
+
 type ZkClientFacade interface {
 	ZkClient() *gxzookeeper.ZookeeperClient
 	SetZkClient(*gxzookeeper.ZookeeperClient)
@@ -41,15 +45,31 @@ type ZkClientFacade interface {
 	GetURL() *common.URL
 }
 
+// healthFacade is implemented by the facades reporting the health of their connections
+type healthFacade interface {
+	Health() *metrics.ConnectionHealth
+}
+
 // HandleClientRestart keeps the connection between client and server
 // This method should be used only once. You can use handleClientRestart() in package registry.
+// The connected state is reported to the health of @r if it's a healthFacade.
 func HandleClientRestart(r ZkClientFacade) {
 	defer r.WaitGroup().Done()
+	var health *metrics.ConnectionHealth
+	if hf, ok := r.(healthFacade); ok {
+		health = hf.Health()
+	}
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
 	for {
 		select {
 		case <-r.ZkClient().Reconnect():
-			r.RestartCallBack()
+			health.SetConnected(r.RestartCallBack())
 			time.Sleep(10 * time.Microsecond)
+		case <-ticker.C:
+			if client := r.ZkClient(); client != nil {
+				health.SetConnected(client.ZkConnValid())
+			}
 		case <-r.Done():
 			logger.Warnf("receive registry destroy event, quit client restart handler")
 			return